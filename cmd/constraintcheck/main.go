@@ -0,0 +1,388 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command constraintcheck cross-checks the profile field bounds the domain owns
+// (domain.Constraints) against the OpenAPI spec and the CHECK constraints of the
+// profiles table, and prints a report of every place they disagree.
+//
+//	go run ./cmd/constraintcheck
+//	go run ./cmd/constraintcheck -strict   # also fail when a bound is not restated
+//
+// It runs as part of `go generate` (see main.go). A bound that differs from the
+// domain is a mismatch and fails the run; a bound that is not restated at all
+// (e.g. no CHECK on a column) is only reported, unless -strict is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"app/core/profile/domain"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+type (
+	// sourceKind tells how the spec restates a bound.
+	sourceKind int
+
+	// specSource is one place of the spec bounding a field.
+	specSource struct {
+		kind sourceKind
+		// component name: parameter, request body or schema
+		component string
+		// property of the request body or schema, empty for parameters
+		property string
+	}
+
+	bound struct {
+		min, max *int
+	}
+
+	severity string
+
+	finding struct {
+		severity severity
+		field    string
+		where    string
+		want     string
+		got      string
+	}
+)
+
+const (
+	// a parameter whose schema carries the range
+	parameterRange sourceKind = iota
+	// a request body property carrying the length
+	bodyLength
+	// a request body or schema property carrying an integer as a string: its
+	// lengths must fit exactly the digits of the range
+	bodyDigits
+	schemaDigits
+)
+
+const (
+	mismatch severity = "mismatch"
+	missing  severity = "missing"
+)
+
+// specSources maps each domain field to the places of the spec restating it.
+var specSources = map[string][]specSource{
+	"age": {
+		{kind: parameterRange, component: "MinAge"},
+		{kind: parameterRange, component: "MaxAge"},
+		{kind: bodyDigits, component: "ModifyProfile", property: "age"},
+		{kind: schemaDigits, component: "Profile", property: "age"},
+	},
+	"name": {
+		{kind: bodyLength, component: "CreateProfile", property: "name"},
+		{kind: bodyLength, component: "UpsertProfile", property: "name"},
+		{kind: bodyLength, component: "ModifyProfile", property: "name"},
+	},
+}
+
+// columns maps domain fields to columns of the profiles table.
+var columns = map[string]string{
+	"age":  "age",
+	"name": "username",
+}
+
+func main() {
+	var (
+		specPath   = flag.String("spec", "modules/oapi/openapi-profile.yaml", "OpenAPI document")
+		migrations = flag.String("migrations", "core/profile/migrations/schema", "directory of the V<n>_<name>.sql schema migrations")
+		table      = flag.String("table", "profiles", "table whose CHECK constraints are compared")
+		strict     = flag.Bool("strict", false, "fail on bounds that are not restated, not only on mismatches")
+	)
+	flag.Parse()
+
+	doc, err := openapi3.NewLoader().LoadFromFile(*specPath)
+	if err != nil {
+		fail("load spec: %v", err)
+	}
+	checks, err := loadChecks(*migrations, *table)
+	if err != nil {
+		fail("load migrations: %v", err)
+	}
+
+	var findings []finding
+	for _, c := range domain.Constraints {
+		findings = append(findings, checkSpec(doc, c)...)
+		findings = append(findings, checkDB(checks, *table, c)...)
+	}
+
+	report(os.Stdout, findings)
+	for _, f := range findings {
+		if f.severity == mismatch || *strict {
+			os.Exit(1)
+		}
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "constraintcheck: "+format+"\n", args...)
+	os.Exit(2)
+}
+
+// checkSpec compares c against every spec source of its field.
+func checkSpec(doc *openapi3.T, c domain.Constraint) []finding {
+	var out []finding
+	for _, src := range specSources[c.Field] {
+		want := bound{min: &c.Min, max: &c.Max}
+		where, got, err := src.bound(doc)
+		if err != nil {
+			out = append(out, finding{severity: mismatch, field: c.Field, where: where, want: want.String(), got: err.Error()})
+			continue
+		}
+		if src.kind == bodyDigits || src.kind == schemaDigits {
+			// "150" takes 1 to 3 characters
+			one, digits := 1, len(strconv.Itoa(c.Max))
+			want = bound{min: &one, max: &digits}
+		}
+		out = append(out, compare(c.Field, where, want, got)...)
+	}
+	return out
+}
+
+func (s specSource) bound(doc *openapi3.T) (string, bound, error) {
+	switch s.kind {
+	case parameterRange:
+		where := "spec parameters." + s.component
+		p, ok := doc.Components.Parameters[s.component]
+		if !ok || p.Value == nil || p.Value.Schema == nil || p.Value.Schema.Value == nil {
+			return where, bound{}, fmt.Errorf("parameter not found")
+		}
+		sc := p.Value.Schema.Value
+		return where, bound{min: floatBound(sc.Min), max: floatBound(sc.Max)}, nil
+	case bodyLength, bodyDigits:
+		where := "spec requestBodies." + s.component + "." + s.property
+		rb, ok := doc.Components.RequestBodies[s.component]
+		if !ok || rb.Value == nil {
+			return where, bound{}, fmt.Errorf("request body not found")
+		}
+		mt := rb.Value.Content.Get("application/json")
+		if mt == nil || mt.Schema == nil || mt.Schema.Value == nil {
+			return where, bound{}, fmt.Errorf("no application/json schema")
+		}
+		b, err := lengthBound(mt.Schema.Value.Properties[s.property])
+		return where, b, err
+	default:
+		where := "spec schemas." + s.component + "." + s.property
+		sc, ok := doc.Components.Schemas[s.component]
+		if !ok || sc.Value == nil {
+			return where, bound{}, fmt.Errorf("schema not found")
+		}
+		b, err := lengthBound(sc.Value.Properties[s.property])
+		return where, b, err
+	}
+}
+
+func lengthBound(prop *openapi3.SchemaRef) (bound, error) {
+	if prop == nil || prop.Value == nil {
+		return bound{}, fmt.Errorf("property not found")
+	}
+	var b bound
+	if prop.Value.MinLength > 0 {
+		n := int(prop.Value.MinLength)
+		b.min = &n
+	}
+	if prop.Value.MaxLength != nil {
+		n := int(*prop.Value.MaxLength)
+		b.max = &n
+	}
+	return b, nil
+}
+
+func floatBound(f *float64) *int {
+	if f == nil {
+		return nil
+	}
+	n := int(*f)
+	return &n
+}
+
+// checkDB compares c against the CHECK constraints on its column.
+func checkDB(checks map[checkKey]bound, table string, c domain.Constraint) []finding {
+	col := columns[c.Field]
+	where := fmt.Sprintf("db %s.%s", table, col)
+	if c.Kind == domain.LengthConstraint {
+		where = fmt.Sprintf("db char_length(%s.%s)", table, col)
+	}
+	return compare(c.Field, where, bound{min: &c.Min, max: &c.Max}, checks[checkKey{column: col, kind: c.Kind}])
+}
+
+// compare reports each side of got that is absent or differs from want.
+func compare(field, where string, want, got bound) []finding {
+	if got.min == nil && got.max == nil {
+		return []finding{{severity: missing, field: field, where: where, want: want.String(), got: got.String()}}
+	}
+	if got.min == nil || got.max == nil || *got.min != *want.min || *got.max != *want.max {
+		sev := mismatch
+		// a half restated bound that agrees is only incomplete
+		if (got.min == nil || *got.min == *want.min) && (got.max == nil || *got.max == *want.max) {
+			sev = missing
+		}
+		return []finding{{severity: sev, field: field, where: where, want: want.String(), got: got.String()}}
+	}
+	return nil
+}
+
+func (b bound) String() string {
+	side := func(p *int) string {
+		if p == nil {
+			return "-"
+		}
+		return strconv.Itoa(*p)
+	}
+	return side(b.min) + ".." + side(b.max)
+}
+
+func report(w io.Writer, findings []finding) {
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "constraintcheck: %d domain constraints agree with the spec and the database\n", len(domain.Constraints))
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SEVERITY\tFIELD\tWHERE\tDOMAIN\tFOUND")
+	for _, f := range findings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", f.severity, f.field, f.where, f.want, f.got)
+	}
+	_ = tw.Flush()
+}
+
+type checkKey struct {
+	column string
+	kind   domain.ConstraintKind
+}
+
+var (
+	tableStmt      = regexp.MustCompile(`(?i)^\s*(?:CREATE|ALTER)\s+TABLE\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?(\w+)`)
+	checkStmt      = regexp.MustCompile(`(?i)CONSTRAINT\s+(\w+)\s+CHECK\s*\((.*)\)`)
+	dropStmt       = regexp.MustCompile(`(?i)DROP\s+CONSTRAINT\s+(?:IF\s+EXISTS\s+)?(\w+)`)
+	varcharColumn  = regexp.MustCompile(`(?i)^\s*(\w+)\s+(?:VARCHAR|CHARACTER\s+VARYING)\s*\((\d+)\)`)
+	betweenExpr    = regexp.MustCompile(`(?i)((?:char_length|length)\s*\(\s*\w+\s*\)|\w+)\s+BETWEEN\s+(-?\d+)\s+AND\s+(-?\d+)`)
+	andSeparator   = regexp.MustCompile(`(?i)\s+AND\s+`)
+	comparisonTerm = regexp.MustCompile(`(?i)^(?:(char_length|length)\s*\(\s*(\w+)\s*\)|(\w+))\s*(>=|<=|>|<)\s*(-?\d+)$`)
+)
+
+// loadChecks collects the bounds the migrations put on table, replaying the
+// files in version order so later migrations override earlier ones.
+func loadChecks(dir, table string) (map[checkKey]bound, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "V*.sql"))
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(files, func(a, b string) int { return version(a) - version(b) })
+
+	// per constraint name, so a DROP CONSTRAINT removes its bounds
+	byName := make(map[string]map[checkKey]bound)
+	varchar := make(map[checkKey]bound)
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		current := ""
+		for _, line := range strings.Split(string(b), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "--") {
+				continue
+			}
+			if m := tableStmt.FindStringSubmatch(line); m != nil {
+				current = strings.ToLower(m[1])
+			}
+			if current != table {
+				continue
+			}
+			if m := dropStmt.FindStringSubmatch(line); m != nil {
+				delete(byName, m[1])
+			}
+			if m := checkStmt.FindStringSubmatch(line); m != nil {
+				byName[m[1]] = parseCheck(m[2])
+			}
+			if m := varcharColumn.FindStringSubmatch(line); m != nil {
+				n, _ := strconv.Atoi(m[2])
+				varchar[checkKey{column: strings.ToLower(m[1]), kind: domain.LengthConstraint}] = bound{max: &n}
+			}
+		}
+	}
+
+	out := varchar
+	for _, bounds := range byName {
+		for k, b := range bounds {
+			merged := out[k]
+			if b.min != nil {
+				merged.min = b.min
+			}
+			if b.max != nil {
+				merged.max = b.max
+			}
+			out[k] = merged
+		}
+	}
+	return out, nil
+}
+
+// parseCheck extracts column bounds from a conjunction of comparisons with
+// integer literals; anything else in the expression is ignored.
+func parseCheck(expr string) map[checkKey]bound {
+	expr = strings.TrimRight(strings.TrimSpace(expr), ",;")
+	expr = betweenExpr.ReplaceAllString(expr, "$1 >= $2 AND $1 <= $3")
+
+	out := make(map[checkKey]bound)
+	for _, term := range andSeparator.Split(expr, -1) {
+		term = strings.Trim(strings.TrimSpace(term), "()")
+		m := comparisonTerm.FindStringSubmatch(term)
+		if m == nil {
+			continue
+		}
+		key := checkKey{column: strings.ToLower(m[3]), kind: domain.RangeConstraint}
+		if m[1] != "" {
+			key = checkKey{column: strings.ToLower(m[2]), kind: domain.LengthConstraint}
+		}
+		n, err := strconv.Atoi(m[5])
+		if err != nil {
+			continue
+		}
+		b := out[key]
+		switch m[4] {
+		case ">=":
+			b.min = &n
+		case ">":
+			n++
+			b.min = &n
+		case "<=":
+			b.max = &n
+		case "<":
+			n--
+			b.max = &n
+		}
+		out[key] = b
+	}
+	return out
+}
+
+// version is the <n> of a V<n>_<name>.sql file, 0 when it has none.
+func version(path string) int {
+	base := strings.TrimPrefix(filepath.Base(path), "V")
+	n, _ := strconv.Atoi(base[:max(strings.IndexByte(base, '_'), 0)])
+	return n
+}
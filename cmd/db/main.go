@@ -0,0 +1,217 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command db captures and restores local database snapshots.
+//
+//	go run ./cmd/db snapshot                        # writes snapshots/<database>-<timestamp>.dump
+//	go run ./cmd/db snapshot -file repro-1234.dump
+//	go run ./cmd/db restore -file repro-1234.dump   # replaces the objects contained in the dump
+//
+// Snapshots are pg_dump custom-format archives, streamed through stdout/stdin so the
+// same file works whether the client tools run on the host or inside the compose
+// Postgres container. Without pg_dump/pg_restore on PATH the commands run through
+// `docker compose exec` against -container (see compose.yaml). Connection settings
+// come from the same POSTGRES_PRIMARY_* environment as the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"app/modules/db/postgres"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// config is the subset of the server environment the command needs, so it runs
+// without the secrets the server requires.
+type config struct {
+	Env      string                  `env:"ENV" envDefault:"dev"`
+	Postgres postgres.PostgresConfig `envPrefix:"POSTGRES_"`
+}
+
+const defaultContainer = "postgres-primary"
+
+// restores are refused in these ENV values, the command is meant for local datasets
+var productionEnvs = []string{"prod", "production"}
+
+type options struct {
+	file string
+	// container is the compose service running Postgres; "" runs the tools on the host
+	container string
+}
+
+func main() {
+	fs := flag.NewFlagSet("db", flag.ExitOnError)
+	var (
+		file      = fs.String("file", "", "snapshot file (snapshot default: snapshots/<database>-<timestamp>.dump)")
+		container = fs.String("container", "auto", `compose service to run pg_dump/pg_restore in; "auto" uses it only when the tools are not installed, "" never`)
+	)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: db snapshot|restore [flags]\n")
+		fs.PrintDefaults()
+	}
+
+	if len(os.Args) < 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	cmd := os.Args[1]
+	_ = fs.Parse(os.Args[2:])
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, cmd, options{file: *file, container: *container}); err != nil {
+		slog.ErrorContext(ctx, "db command failed", slog.String("command", cmd), slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cmd string, opts options) error {
+	cfg, err := env.ParseAs[config]()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	pg := cfg.Postgres.WriteConfig
+
+	switch cmd {
+	case "snapshot":
+		return snapshot(ctx, pg, opts)
+	case "restore":
+		if slices.Contains(productionEnvs, strings.ToLower(cfg.Env)) {
+			return fmt.Errorf("refusing to restore a snapshot with ENV=%s", cfg.Env)
+		}
+		return restore(ctx, pg, opts)
+	default:
+		return fmt.Errorf("unknown command %q, expected snapshot or restore", cmd)
+	}
+}
+
+func snapshot(ctx context.Context, pg postgres.PoolConfig, opts options) (err error) {
+	if opts.file == "" {
+		opts.file = filepath.Join("snapshots", fmt.Sprintf("%s-%s.dump", pg.Database, time.Now().UTC().Format("20060102T150405Z")))
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.file), 0o755); err != nil {
+		return fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	out, err := os.Create(opts.file)
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			// a truncated archive is worse than none
+			_ = os.Remove(opts.file)
+		}
+	}()
+
+	c := command(ctx, "pg_dump", pg, opts.container,
+		"--format=custom", "--no-owner", "--no-privileges",
+	)
+	c.Stdout = out
+	start := time.Now()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("pg_dump: %w", err)
+	}
+
+	info, err := out.Stat()
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "snapshot written",
+		slog.String("file", opts.file),
+		slog.String("database", pg.Database),
+		slog.Int64("bytes", info.Size()),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
+func restore(ctx context.Context, pg postgres.PoolConfig, opts options) error {
+	if opts.file == "" {
+		return errors.New("restore needs -file")
+	}
+	in, err := os.Open(opts.file)
+	if err != nil {
+		return fmt.Errorf("open snapshot: %w", err)
+	}
+	defer in.Close()
+
+	// --clean drops what the archive recreates, --single-transaction keeps a failed
+	// restore from leaving the database half replaced
+	c := command(ctx, "pg_restore", pg, opts.container,
+		"--clean", "--if-exists", "--no-owner", "--no-privileges", "--single-transaction",
+	)
+	c.Stdin = in
+	start := time.Now()
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("pg_restore: %w", err)
+	}
+
+	slog.InfoContext(ctx, "snapshot restored",
+		slog.String("file", opts.file),
+		slog.String("database", pg.Database),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
+
+// command builds tool invocation on the host or inside the compose container.
+//
+// Inside the container the server is reached on its own loopback, since the
+// configured host usually only resolves from the host machine.
+func command(ctx context.Context, tool string, pg postgres.PoolConfig, container string, args ...string) *exec.Cmd {
+	if container == "auto" {
+		container = ""
+		if _, err := exec.LookPath(tool); err != nil {
+			container = defaultContainer
+		}
+	}
+
+	host, port := pg.Host, strconv.Itoa(int(pg.Port))
+	if container != "" {
+		host, port = "localhost", "5432"
+	}
+	args = append([]string{"--host", host, "--port", port, "--username", pg.User, "--dbname", pg.Database}, args...)
+
+	var c *exec.Cmd
+	if container == "" {
+		c = exec.CommandContext(ctx, tool, args...)
+		c.Env = append(os.Environ(), "PGPASSWORD="+pg.Password)
+	} else {
+		slog.InfoContext(ctx, "running inside compose container", slog.String("tool", tool), slog.String("container", container))
+		c = exec.CommandContext(ctx, "docker", append([]string{
+			"compose", "exec", "-T", "-e", "PGPASSWORD=" + pg.Password, container, tool,
+		}, args...)...)
+	}
+	c.Stderr = os.Stderr
+	return c
+}
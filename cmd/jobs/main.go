@@ -0,0 +1,112 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command jobs shows the background jobs the server schedules, resolved against the
+// JOBS_* environment of the deployment.
+//
+//	go run ./cmd/jobs list
+//	JOBS_DISABLED=profile.audit.purge go run ./cmd/jobs list
+//
+// A job runs when it is enabled and its requirements ("requires") are met by the
+// server configuration; SOURCE tells whether the schedule is the job's default or
+// comes from JOBS_SCHEDULES. Jobs of the same GROUP share LIMIT concurrent runs
+// across the fleet, see JOBS_GROUPS.
+// Invalid jobs configuration, such as an unknown job name, exits with status 1.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"app/modules/db/redis/locking"
+	"app/modules/jobs"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// config is the subset of the server environment the command needs.
+type config struct {
+	Jobs locking.JobsConfig `envPrefix:"JOBS_"`
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: jobs list\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	switch cmd := flag.Arg(0); cmd {
+	case "list", "":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := list(os.Stdout, time.Now()); err != nil {
+		fmt.Fprintln(os.Stderr, "jobs:", err)
+		os.Exit(1)
+	}
+}
+
+func list(w io.Writer, now time.Time) error {
+	cfg, err := env.ParseAs[config]()
+	if err != nil {
+		return err
+	}
+	reg, err := jobs.NewRegistry()
+	if err != nil {
+		return err
+	}
+	plan, err := reg.Plan(cfg.Jobs)
+	if err != nil {
+		return err
+	}
+	groups, err := cfg.Jobs.ConcurrencyGroups(jobs.Groups()...)
+	if err != nil {
+		return err
+	}
+	limits := make(map[string]int, len(groups))
+	for _, g := range groups {
+		limits[g.Name] = g.Limit
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tENABLED\tSCHEDULE\tSOURCE\tNEXT RUN\tLOCK AT MOST\tLOCK AT LEAST\tREQUIRES\tGROUP\tLIMIT\tDESCRIPTION")
+	for _, p := range plan {
+		source := "default"
+		if p.Overridden {
+			source = "config"
+		}
+		next := "-"
+		if runs := p.NextRuns(now, 1); p.Enabled && len(runs) > 0 {
+			next = runs[0].Format(time.RFC3339)
+		}
+		requires := p.Requires
+		if requires == "" {
+			requires = "-"
+		}
+		group, limit := "-", "-"
+		if p.Group != "" {
+			group, limit = p.Group, fmt.Sprint(limits[p.Group])
+		}
+		fmt.Fprintf(tw, "%s\t%t\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			p.Name, p.Enabled, p.ScheduleSpec, source, next, p.Lock.LockAtMostFor, p.Lock.LockAtLeastFor, requires, group, limit, p.Description)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command loadgen replays randomized, spec-valid requests against a running server.
+//
+//	go run ./cmd/loadgen -target http://localhost:8080 -rate 200 -duration 1m
+//	go run ./cmd/loadgen -ops getProfileById -param id=0199...,0199... -concurrency 32
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"app/modules/loadgen"
+	"app/modules/oapi"
+)
+
+// paramFlags collects repeated -param name=v1,v2 flags.
+type paramFlags map[string][]string
+
+func (p paramFlags) String() string { return fmt.Sprint(map[string][]string(p)) }
+
+func (p paramFlags) Set(s string) error {
+	name, values, ok := strings.Cut(s, "=")
+	if !ok || name == "" || values == "" {
+		return fmt.Errorf("expected name=value[,value...], got %q", s)
+	}
+	p[name] = append(p[name], strings.Split(values, ",")...)
+	return nil
+}
+
+func main() {
+	var (
+		target      = flag.String("target", "http://localhost:8080", "base URL of the server under test")
+		spec        = flag.String("spec", oapi.ProfileSpec, "embedded spec to generate requests from")
+		ops         = flag.String("ops", "", "comma separated operation ids to exercise (default: all)")
+		rps         = flag.Float64("rate", 10, "target requests per second")
+		concurrency = flag.Int("concurrency", 8, "maximum requests in flight")
+		duration    = flag.Duration("duration", 30*time.Second, "how long to run")
+		optional    = flag.Float64("optional", 0.5, "probability of sending optional parameters and fields")
+		seed        = flag.Uint64("seed", 0, "random seed for reproducible runs (0: random)")
+		params      = paramFlags{}
+	)
+	flag.Var(params, "param", "pin a parameter to known values, e.g. -param id=<uuid>,<uuid> (repeatable)")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, *target, *spec, *ops, *rps, *concurrency, *duration, *optional, *seed, params); err != nil {
+		slog.ErrorContext(ctx, "loadgen failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run(
+	ctx context.Context,
+	target, spec, ops string,
+	rps float64, concurrency int, duration time.Duration,
+	optional float64, seed uint64,
+	params paramFlags,
+) error {
+	doc, err := loadgen.LoadSpec(ctx, oapi.Specs, spec)
+	if err != nil {
+		return err
+	}
+
+	genOpts := []loadgen.Option{loadgen.WithOptionalRate(optional)}
+	if ops != "" {
+		genOpts = append(genOpts, loadgen.WithOperations(strings.Split(ops, ",")...))
+	}
+	for name, values := range params {
+		genOpts = append(genOpts, loadgen.WithParamValues(name, values...))
+	}
+	gen, err := loadgen.NewGenerator(doc, genOpts...)
+	if err != nil {
+		return err
+	}
+
+	runOpts := []loadgen.RunnerOption{loadgen.WithRate(rps), loadgen.WithConcurrency(concurrency)}
+	if seed != 0 {
+		runOpts = append(runOpts, loadgen.WithSeed(seed))
+	}
+	runner, err := loadgen.NewRunner(gen, target, runOpts...)
+	if err != nil {
+		return err
+	}
+
+	for _, op := range gen.Operations() {
+		slog.InfoContext(ctx, "operation", slog.String("id", op.ID), slog.String("method", op.Method), slog.String("path", op.Path))
+	}
+	slog.InfoContext(ctx, "starting load", slog.String("target", target), slog.Float64("rate", rps), slog.Duration("duration", duration))
+
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	report, err := runner.Run(runCtx)
+	if err != nil {
+		return err
+	}
+	report.Print(os.Stdout)
+	return nil
+}
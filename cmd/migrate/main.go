@@ -0,0 +1,151 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command migrate lints and applies the profile service schema migrations.
+//
+//	go run ./cmd/migrate lint                 # check pending files, no database needed
+//	go run ./cmd/migrate status               # list pending migrations
+//	go run ./cmd/migrate up                   # lint, then apply
+//	ENV=prod go run ./cmd/migrate -force up   # apply despite lint findings in production
+//
+// Connection settings come from the same POSTGRES_* environment as the server.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"app/core/profile/migrations"
+	"app/modules/db/migrate"
+	"app/modules/db/postgres"
+
+	"github.com/caarlos0/env/v11"
+)
+
+// config is the subset of the server environment the command needs, so it runs
+// without the secrets the server requires.
+type config struct {
+	Env      string                  `env:"ENV" envDefault:"dev"`
+	Postgres postgres.PostgresConfig `envPrefix:"POSTGRES_"`
+}
+
+// productionEnvs are the ENV values where lint findings block a run.
+var productionEnvs = []string{"prod", "production"}
+
+func main() {
+	var (
+		dir   = flag.String("dir", "", "read migrations from this directory instead of the embedded schema")
+		force = flag.Bool("force", false, "apply migrations despite lint findings in production")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: migrate [flags] lint|status|up\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	cmd := flag.Arg(0)
+	if cmd == "" {
+		cmd = "up"
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, cmd, *dir, *force); err != nil {
+		slog.ErrorContext(ctx, "migrate failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, cmd, dir string, force bool) error {
+	var (
+		source fs.FS = migrations.Schema
+		root         = migrations.SchemaDir
+	)
+	if dir != "" {
+		source, root = os.DirFS(dir), "."
+	}
+	all, err := migrate.Load(source, root)
+	if err != nil {
+		return err
+	}
+
+	if cmd == "lint" {
+		// without a database every migration counts as pending
+		findings := migrate.Lint(all)
+		for _, f := range findings {
+			fmt.Println(f)
+		}
+		if len(findings) > 0 {
+			return fmt.Errorf("%w: %d findings", migrate.ErrUnsafeMigration, len(findings))
+		}
+		return nil
+	}
+
+	cfg, err := env.ParseAs[config]()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	production := slices.Contains(productionEnvs, strings.ToLower(cfg.Env))
+
+	pool, err := postgres.New(ctx, &cfg.Postgres, postgres.PostgresOptions{})
+	if err != nil {
+		return fmt.Errorf("connect postgres: %w", err)
+	}
+	defer pool.Shutdown(context.WithoutCancel(ctx))
+
+	m := migrate.New(pool,
+		migrate.WithProduction(production),
+		migrate.WithForce(force),
+	)
+
+	switch cmd {
+	case "status":
+		pending, err := m.Pending(ctx, all)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d applied, %d pending\n", len(all)-len(pending), len(pending))
+		for _, mig := range pending {
+			fmt.Println("  pending", mig.Name())
+		}
+		return nil
+	case "up":
+		if force && production {
+			slog.WarnContext(ctx, "migrate: lint findings are overridden by -force", slog.String("env", cfg.Env))
+		}
+		applied, err := m.Up(ctx, all)
+		if err != nil {
+			var unsafe *migrate.UnsafeError
+			if errors.As(err, &unsafe) {
+				fmt.Fprintln(os.Stderr, unsafe.Error())
+			}
+			return err
+		}
+		fmt.Printf("applied %d migrations\n", len(applied))
+		return nil
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command scaffold generates the vertical slice of a new resource, laid out like
+// the profile service:
+//
+//	go run ./cmd/scaffold -name invoice            # plural defaults to "invoices"
+//	go run ./cmd/scaffold -name category -plural categories -dry-run
+//
+// It writes the OpenAPI spec and its oapi-codegen config, the domain (types, ports,
+// application and its tests), the pg reader/writer with prepared statements, the REST
+// adapter with its service registration and the first migration, then prints the
+// remaining wiring steps. Existing files are never overwritten.
+package main
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+//go:embed all:templates
+var templates embed.FS
+
+// resource is the data every template renders from.
+type resource struct {
+	Name         string // invoice
+	Plural       string // invoices
+	Pascal       string // Invoice
+	PluralPascal string // Invoices
+	Module       string // app
+	Year         int
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+func main() {
+	var (
+		name   = flag.String("name", "", "resource name, singular lower case (e.g. invoice)")
+		plural = flag.String("plural", "", "plural form (default: name + \"s\")")
+		root   = flag.String("root", ".", "repository root")
+		dryRun = flag.Bool("dry-run", false, "print the files that would be written")
+	)
+	flag.Parse()
+
+	if err := run(*root, *name, *plural, *dryRun); err != nil {
+		fmt.Fprintln(os.Stderr, "scaffold:", err)
+		os.Exit(1)
+	}
+}
+
+func run(root, name, plural string, dryRun bool) error {
+	if !namePattern.MatchString(name) {
+		return errors.New("-name must be a lower case identifier, e.g. invoice")
+	}
+	if plural == "" {
+		plural = name + "s"
+	}
+	if !namePattern.MatchString(plural) || plural == name {
+		return errors.New("-plural must be a lower case identifier different from -name")
+	}
+
+	res := resource{
+		Name:         name,
+		Plural:       plural,
+		Pascal:       pascal(name),
+		PluralPascal: pascal(plural),
+		Module:       "app",
+		Year:         time.Now().Year(),
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "core", name)); err == nil {
+		return fmt.Errorf("core/%s already exists", name)
+	}
+
+	files, err := render(res)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		path := filepath.Join(root, f.path)
+		if dryRun {
+			fmt.Println(f.path)
+			continue
+		}
+		if _, err := os.Stat(path); err == nil {
+			return fmt.Errorf("%s already exists", f.path)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.content, 0o644); err != nil {
+			return err
+		}
+		fmt.Println("created", f.path)
+	}
+
+	fmt.Printf(`
+next steps:
+  1. add to main.go and run go generate:
+       //go:generate go tool oapi-codegen -config modules/oapi/stdlib/cfg.server.%[1]s.yaml modules/oapi/openapi-%[1]s.yaml
+  2. register the spec in modules/oapi/embed.go
+  3. wire the service in main.go:
+       reader := %[1]s_pg.NewPostgres%[2]sReader(connectionPool, "%[3]s")
+       writer, err := %[1]s_pg.NewPostgres%[2]sWriter(ctx, connectionPool, "%[3]s")
+       api := %[1]s_http.New%[2]sService(reader, writer)
+     and pass services.New%[2]sAPIService(api) to server.WithServices
+  4. apply core/%[1]s/migrations with cmd/migrate
+`, res.Name, res.Pascal, res.Plural)
+	return nil
+}
+
+type file struct {
+	path    string
+	content []byte
+}
+
+// render executes every template. Template paths mirror the generated layout, with
+// "_name_" and "_plural_" replaced and the ".tmpl" suffix dropped.
+func render(res resource) ([]file, error) {
+	var files []file
+	err := fs.WalkDir(templates, "templates", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		src, err := templates.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		tmpl, err := template.New(path).Delims("[[", "]]").Parse(string(src))
+		if err != nil {
+			return fmt.Errorf("parse %s: %w", path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, res); err != nil {
+			return fmt.Errorf("render %s: %w", path, err)
+		}
+
+		out := strings.TrimSuffix(strings.TrimPrefix(path, "templates/"), ".tmpl")
+		out = strings.NewReplacer("_name_", res.Name, "_plural_", res.Plural).Replace(out)
+		content := buf.Bytes()
+		if strings.HasSuffix(out, ".go") {
+			if content, err = format.Source(content); err != nil {
+				return fmt.Errorf("format %s: %w", out, err)
+			}
+		}
+		files = append(files, file{path: out, content: content})
+		return nil
+	})
+	return files, err
+}
+
+func pascal(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
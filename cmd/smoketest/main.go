@@ -0,0 +1,363 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command smoketest walks the profile API of a deployed instance through one
+// profile's life and exits with status 1 on the first contract violation, so a
+// deployment pipeline can gate on it:
+//
+//	go run ./cmd/smoketest -target https://profiles.staging.example.com \
+//		-header "Authorization: Bearer $TOKEN"
+//
+// The steps are create → get (ETag, 304 on If-None-Match) → update with a stale and
+// then the current If-Match → list by cursor → delete → get answers 404. Every
+// response is checked for its status, the ETag format, rate limit headers (unless
+// -ratelimit=false) and, for errors, RFC 7807 problem details. The profile is deleted
+// even when a step fails.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// strongETag is the shape of item entity tags, see modules/etag.
+var strongETag = regexp.MustCompile(`^"[A-Za-z0-9._-]+"$`)
+
+// staleETag is well-formed but never current.
+const staleETag = `"1.0000000000000000.000000000000000000000000"`
+
+type (
+	// headerFlags collects repeated -header "Name: value" flags.
+	headerFlags http.Header
+
+	smoke struct {
+		client    *http.Client
+		target    string
+		header    http.Header
+		rateLimit bool
+	}
+
+	response struct {
+		status int
+		header http.Header
+		body   []byte
+	}
+
+	// envelope is the success body of single profiles and lists.
+	envelope struct {
+		Data json.RawMessage `json:"data"`
+		Meta struct {
+			Mode       string `json:"mode"`
+			Limit      int    `json:"limit"`
+			NextCursor string `json:"nextCursor"`
+		} `json:"meta"`
+	}
+
+	profile struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+func (h headerFlags) String() string { return fmt.Sprint(http.Header(h)) }
+
+func (h headerFlags) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("expected \"Name: value\", got %q", s)
+	}
+	http.Header(h).Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	return nil
+}
+
+func main() {
+	var (
+		target    = flag.String("target", "http://localhost:8080", "base URL of the deployed instance")
+		timeout   = flag.Duration("timeout", 10*time.Second, "timeout of each request")
+		rateLimit = flag.Bool("ratelimit", true, "require rate limit headers on write responses")
+		header    = headerFlags{}
+	)
+	flag.Var(header, "header", "header sent with every request, e.g. -header \"Authorization: Bearer ...\" (repeatable)")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer cancel()
+
+	s := &smoke{
+		client:    &http.Client{Timeout: *timeout},
+		target:    strings.TrimSuffix(*target, "/"),
+		header:    http.Header(header),
+		rateLimit: *rateLimit,
+	}
+	start := time.Now()
+	if err := s.run(ctx); err != nil {
+		slog.ErrorContext(ctx, "smoke test failed", slog.String("target", s.target), slog.Any("error", err))
+		os.Exit(1)
+	}
+	slog.InfoContext(ctx, "smoke test passed", slog.String("target", s.target), slog.Duration("duration", time.Since(start)))
+}
+
+func (s *smoke) run(ctx context.Context) (err error) {
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	name := "Smoke Test " + hex.EncodeToString(suffix)
+
+	// create
+	res, err := s.step(ctx, "create", http.MethodPost, "/v1/profiles", map[string]string{
+		"name":  name,
+		"email": "smoke+" + hex.EncodeToString(suffix) + "@example.com",
+	}, nil, http.StatusCreated)
+	if err != nil {
+		return err
+	}
+	var created profile
+	if err := decodeData(res, &created); err != nil {
+		return fmt.Errorf("create: %w", err)
+	}
+	if created.ID == "" || created.Name != name {
+		return fmt.Errorf("create: got profile %+v, want name %q and an id", created, name)
+	}
+	if loc := res.header.Get("Location"); !strings.HasSuffix(loc, "/v1/profiles/"+created.ID) {
+		return fmt.Errorf("create: Location %q does not point at profile %s", loc, created.ID)
+	}
+	path := "/v1/profiles/" + created.ID
+
+	deleted := false
+	defer func() {
+		if !deleted {
+			s.cleanup(context.WithoutCancel(ctx), path)
+		}
+	}()
+
+	// get, then revalidate
+	res, err = s.step(ctx, "get", http.MethodGet, path, nil, nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	tag, err := entityTag(res)
+	if err != nil {
+		return fmt.Errorf("get: %w", err)
+	}
+	if _, err := s.step(ctx, "get if-none-match", http.MethodGet, path, nil, http.Header{"If-None-Match": {tag}}, http.StatusNotModified); err != nil {
+		return err
+	}
+
+	// conditional update
+	patch := map[string]string{"name": name + " v2"}
+	if _, err := s.step(ctx, "update stale if-match", http.MethodPatch, path, patch, http.Header{"If-Match": {staleETag}}, http.StatusPreconditionFailed); err != nil {
+		return err
+	}
+	res, err = s.step(ctx, "update", http.MethodPatch, path, patch, http.Header{"If-Match": {tag}}, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	newTag, err := entityTag(res)
+	if err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+	if newTag == tag {
+		return fmt.Errorf("update: ETag %s did not change", tag)
+	}
+
+	// list by cursor
+	res, err = s.step(ctx, "list", http.MethodGet, "/v1/profiles?limit=1", nil, nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	var page envelope
+	if err := json.Unmarshal(res.body, &page); err != nil {
+		return fmt.Errorf("list: decode body: %w", err)
+	}
+	var items []profile
+	if err := json.Unmarshal(page.Data, &items); err != nil {
+		return fmt.Errorf("list: decode data: %w", err)
+	}
+	if page.Meta.Mode != "cursor" || page.Meta.Limit != 1 || len(items) > 1 {
+		return fmt.Errorf("list: got mode %q, limit %d and %d items, want a cursor page of at most 1", page.Meta.Mode, page.Meta.Limit, len(items))
+	}
+	if page.Meta.NextCursor != "" {
+		next := "/v1/profiles?limit=1&after=" + url.QueryEscape(page.Meta.NextCursor)
+		if _, err := s.step(ctx, "list next", http.MethodGet, next, nil, nil, http.StatusOK); err != nil {
+			return err
+		}
+	}
+
+	// delete, then it is gone
+	if _, err := s.step(ctx, "delete", http.MethodDelete, path, nil, http.Header{"If-Match": {newTag}}, http.StatusNoContent); err != nil {
+		return err
+	}
+	deleted = true
+	if _, err := s.step(ctx, "get deleted", http.MethodGet, path, nil, nil, http.StatusNotFound); err != nil {
+		return err
+	}
+	return nil
+}
+
+// step sends one request and checks the contract of its response.
+func (s *smoke) step(ctx context.Context, name, method, path string, body any, header http.Header, want int) (*response, error) {
+	start := time.Now()
+	res, err := s.do(ctx, method, path, body, header)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	if res.status != want {
+		return nil, fmt.Errorf("%s: %s %s answered %d, want %d: %s", name, method, path, res.status, want, truncate(res.body))
+	}
+	if res.status >= http.StatusBadRequest {
+		if err := checkProblem(res); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.rateLimit && method != http.MethodGet && res.status < http.StatusBadRequest {
+		if err := checkRateLimit(res); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	slog.InfoContext(ctx, "step passed",
+		slog.String("step", name),
+		slog.Int("status", res.status),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return res, nil
+}
+
+func (s *smoke) do(ctx context.Context, method, path string, body any, header http.Header) (*response, error) {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.target+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: b}, nil
+}
+
+// cleanup deletes the profile of a failed run.
+func (s *smoke) cleanup(ctx context.Context, path string) {
+	res, err := s.do(ctx, http.MethodGet, path, nil, nil)
+	if err == nil && res.status == http.StatusOK {
+		res, err = s.do(ctx, http.MethodDelete, path, nil, http.Header{"If-Match": {res.header.Get("ETag")}})
+	}
+	if err != nil || res.status != http.StatusNoContent {
+		slog.WarnContext(ctx, "smoke profile not cleaned up", slog.String("path", path), slog.Any("error", err))
+	}
+}
+
+func decodeData(res *response, v any) error {
+	var env envelope
+	if err := json.Unmarshal(res.body, &env); err != nil {
+		return fmt.Errorf("decode body: %w", err)
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		return fmt.Errorf("decode data: %w", err)
+	}
+	return nil
+}
+
+func entityTag(res *response) (string, error) {
+	tag := res.header.Get("ETag")
+	if !strongETag.MatchString(tag) {
+		return "", fmt.Errorf("ETag %q is not a strong quoted entity tag", tag)
+	}
+	return tag, nil
+}
+
+// checkProblem verifies an error response is an RFC 7807 problem matching its status.
+func checkProblem(res *response) error {
+	if ct := res.header.Get("Content-Type"); !strings.HasPrefix(ct, "application/problem+json") {
+		return fmt.Errorf("error response has Content-Type %q, want application/problem+json", ct)
+	}
+	var p struct {
+		Title  string `json:"title"`
+		Status int    `json:"status"`
+	}
+	if err := json.Unmarshal(res.body, &p); err != nil {
+		return fmt.Errorf("decode problem: %w", err)
+	}
+	if p.Title == "" || p.Status != res.status {
+		return fmt.Errorf("problem has title %q and status %d, want a title and %d", p.Title, p.Status, res.status)
+	}
+	return nil
+}
+
+// checkRateLimit verifies the limit headers in either the legacy X-RateLimit-* or
+// the draft RateLimit-* form, depending on RATE_LIMIT_HEADERS of the instance.
+func checkRateLimit(res *response) error {
+	prefix := "X-RateLimit-"
+	if res.header.Get(prefix+"Limit") == "" && res.header.Get("RateLimit-Limit") != "" {
+		prefix = "RateLimit-"
+	}
+	var errs []error
+	values := make(map[string]int)
+	for _, name := range []string{"Limit", "Remaining"} {
+		v := res.header.Get(prefix + name)
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			errs = append(errs, fmt.Errorf("header %s%s is %q, want a non-negative integer", prefix, name, v))
+		}
+		values[name] = n
+	}
+	if len(errs) == 0 && values["Remaining"] > values["Limit"] {
+		errs = append(errs, fmt.Errorf("%sRemaining %d exceeds %sLimit %d", prefix, values["Remaining"], prefix, values["Limit"]))
+	}
+	return errors.Join(errs...)
+}
+
+func truncate(b []byte) string {
+	if len(b) > 512 {
+		return string(b[:512]) + "..."
+	}
+	return string(b)
+}
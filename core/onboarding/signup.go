@@ -0,0 +1,119 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	payment "app/core/payment/domain"
+	profile "app/core/profile/domain"
+	"app/modules/db"
+	"app/modules/saga"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// SagaName is persisted with every signup instance.
+const SagaName = "onboarding.signup"
+
+type (
+	// Signup is the data shared by the steps of a signup.
+	Signup struct {
+		// ProfileID is chosen up front, so a rerun of the creation finds the
+		// profile it created before.
+		ProfileID uuid.UUID `json:"profileId"`
+		Username  string    `json:"username"`
+		Email     string    `json:"email"`
+		// ProfileVersion is the version the profile was created at.
+		ProfileVersion int64     `json:"profileVersion"`
+		AccountID      uuid.UUID `json:"accountId"`
+	}
+
+	// Profiles is what signups need of the profile use cases, see profile.Application.
+	Profiles interface {
+		CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*profile.Profile, error)
+		GetProfileByID(ctx context.Context, id uuid.UUID) (*profile.Profile, error)
+		DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error
+	}
+)
+
+// NewSaga declares the signup saga over its steps, see saga.New.
+func NewSaga(store saga.Store, executor saga.Executor, profiles Profiles, accounts payment.Accounts, opts ...saga.Option) (*saga.Saga[Signup], error) {
+	return saga.New(SagaName, store, executor, Steps(profiles, accounts), opts...)
+}
+
+// Begin starts the signup of username and returns the id of its saga instance.
+func Begin(ctx context.Context, s *saga.Saga[Signup], username, email string) (uuid.UUID, error) {
+	if username == "" || email == "" {
+		return uuid.Nil, profile.ErrInvalidData
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("onboarding: generate profile id: %w", err)
+	}
+	return s.Begin(ctx, Signup{ProfileID: id, Username: username, Email: email})
+}
+
+// Steps returns the steps of a signup, in order.
+func Steps(profiles Profiles, accounts payment.Accounts) []saga.Step[Signup] {
+	return []saga.Step[Signup]{
+		{
+			Name: "profile.create",
+			Action: func(ctx context.Context, s *Signup) error {
+				p, err := profiles.CreateProfileWithID(ctx, s.ProfileID, s.Username, s.Email)
+				if errors.Is(err, profile.ErrPrecondition) {
+					// created by an earlier run that stopped before saving the step
+					p, err = profiles.GetProfileByID(db.StickToPrimary(ctx), s.ProfileID)
+				}
+				if err != nil {
+					return err
+				}
+				s.ProfileVersion = p.Version
+				return nil
+			},
+			Compensate: func(ctx context.Context, s *Signup) error {
+				err := profiles.DeleteProfile(ctx, s.ProfileID, s.ProfileVersion)
+				if !errors.Is(err, profile.ErrPrecondition) {
+					return err
+				}
+				// updated since, or never created when the creation is what failed
+				p, err := profiles.GetProfileByID(db.StickToPrimary(ctx), s.ProfileID)
+				if errors.Is(err, profile.ErrProfileNotFound) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				return profiles.DeleteProfile(ctx, s.ProfileID, p.Version)
+			},
+		},
+		{
+			Name: "payment.open",
+			Action: func(ctx context.Context, s *Signup) error {
+				acc, err := accounts.OpenAccount(ctx, s.ProfileID)
+				if err != nil {
+					return err
+				}
+				s.AccountID = acc.ID
+				return nil
+			},
+			Compensate: func(ctx context.Context, s *Signup) error {
+				return accounts.CloseAccount(ctx, s.ProfileID)
+			},
+		},
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"app/core/payment/domain"
+	"app/modules/clock"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var _ domain.Accounts = (*Accounts)(nil)
+
+// Accounts keeps payment accounts in memory, standing in for the payment provider
+// in local development and tests. Accounts do not survive a restart.
+type Accounts struct {
+	mu       sync.Mutex
+	accounts map[uuid.UUID]domain.Account
+	clock    clock.Clock
+}
+
+// NewAccounts constructs an empty Accounts.
+func NewAccounts(c clock.Clock) *Accounts {
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	return &Accounts{accounts: make(map[uuid.UUID]domain.Account), clock: c}
+}
+
+// OpenAccount implements domain.Accounts.
+func (a *Accounts) OpenAccount(_ context.Context, owner uuid.UUID) (domain.Account, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if acc, ok := a.accounts[owner]; ok && acc.ClosedAt == nil {
+		return acc, nil
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return domain.Account{}, err
+	}
+	acc := domain.Account{ID: id, OwnerID: owner, OpenedAt: a.clock.Now().UTC()}
+	a.accounts[owner] = acc
+	return acc, nil
+}
+
+// CloseAccount implements domain.Accounts.
+func (a *Accounts) CloseAccount(_ context.Context, owner uuid.UUID) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	acc, ok := a.accounts[owner]
+	if !ok || acc.ClosedAt != nil {
+		return nil
+	}
+	closed := a.clock.Now().UTC()
+	acc.ClosedAt = &closed
+	a.accounts[owner] = acc
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	// Account is the payment account of a profile.
+	Account struct {
+		ID      uuid.UUID
+		OwnerID uuid.UUID
+		// ClosedAt is set on accounts that were closed.
+		ClosedAt *time.Time
+		OpenedAt time.Time
+	}
+
+	// Accounts is the port to the payment provider. Both calls are idempotent per
+	// owner, so callers may retry them after a timeout.
+	Accounts interface {
+		// OpenAccount opens the account of owner, or returns the one already open.
+		OpenAccount(ctx context.Context, owner uuid.UUID) (Account, error)
+		// CloseAccount closes the account of owner. Closing an unknown or closed
+		// account succeeds.
+		CloseAccount(ctx context.Context, owner uuid.UUID) error
+	}
+)
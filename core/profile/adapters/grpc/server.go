@@ -0,0 +1,190 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpc
+
+import (
+	"context"
+
+	rest "app/core/profile/adapters/rest"
+	"app/core/profile/domain"
+	pb "app/modules/api/profileapi/grpc"
+	"app/modules/grpcserver"
+	"app/modules/middleware/problem"
+
+	"github.com/gofrs/uuid/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DefaultPageSize is the page size of ListProfiles calls that set none.
+const DefaultPageSize = 20
+
+// MethodScopes lists the scopes each method requires, matching the security of
+// the REST operations, for grpcserver.RequireScopes.
+var MethodScopes = map[string][]string{
+	pb.ProfileService_GetProfile_FullMethodName:     {"profiles:read"},
+	pb.ProfileService_ListProfiles_FullMethodName:   {"profiles:read"},
+	pb.ProfileService_CreateProfile_FullMethodName:  {"profiles:write"},
+	pb.ProfileService_UpdateProfile_FullMethodName:  {"profiles:write"},
+	pb.ProfileService_DeleteProfile_FullMethodName:  {"profiles:write"},
+	pb.ProfileService_RestoreProfile_FullMethodName: {rest.AdminScope},
+}
+
+// ProfileServer implements the generated ProfileServiceServer over the same use
+// cases as the REST adapter. Domain errors are returned as is, for
+// grpcserver.Errors to map with ProblemFromError.
+type ProfileServer struct {
+	pb.UnimplementedProfileServiceServer
+	app *domain.Application
+}
+
+var _ pb.ProfileServiceServer = (*ProfileServer)(nil)
+
+func NewProfileServer(reader domain.ProfileReadStore, writer domain.ProfileWriteStore, signer domain.CursorSigner, opts ...domain.AppOption) *ProfileServer {
+	return &ProfileServer{
+		app: domain.NewApp(reader, writer, signer, opts...),
+	}
+}
+
+func (s *ProfileServer) GetProfile(ctx context.Context, req *pb.GetProfileRequest) (*pb.Profile, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	prof, err := s.app.GetProfileByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toProto(prof), nil
+}
+
+func (s *ProfileServer) ListProfiles(ctx context.Context, req *pb.ListProfilesRequest) (*pb.ListProfilesResponse, error) {
+	sort, err := domain.ParseProfileSort(req.GetSort())
+	if err != nil {
+		return nil, invalidArgument("invalid sort", "sort", "must be one of createdAt, name or age, optionally prefixed with -")
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = DefaultPageSize
+	}
+	profiles, total, err := s.app.GetProfilesByOffset(ctx, domain.ProfileQuery{Sort: sort}, int(req.GetPage()), pageSize)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListProfilesResponse{
+		Profiles:  make([]*pb.Profile, 0, len(profiles)),
+		TotalSize: int32(total),
+	}
+	for i := range profiles {
+		resp.Profiles = append(resp.Profiles, toProto(&profiles[i]))
+	}
+	return resp, nil
+}
+
+func (s *ProfileServer) CreateProfile(ctx context.Context, req *pb.CreateProfileRequest) (*pb.Profile, error) {
+	prof, err := s.app.CreateProfile(ctx, req.GetName(), req.GetEmail())
+	if err != nil {
+		return nil, err
+	}
+	return toProto(prof), nil
+}
+
+func (s *ProfileServer) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.Profile, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	prof, err := s.app.UpdateProfile(ctx, &domain.UpdateProfileParams{
+		ID:      id,
+		Name:    req.GetName(),
+		Email:   req.GetEmail(),
+		Version: req.GetVersion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return toProto(prof), nil
+}
+
+func (s *ProfileServer) DeleteProfile(ctx context.Context, req *pb.DeleteProfileRequest) (*pb.DeleteProfileResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.app.DeleteProfile(ctx, id, req.GetVersion()); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteProfileResponse{}, nil
+}
+
+func (s *ProfileServer) RestoreProfile(ctx context.Context, req *pb.RestoreProfileRequest) (*pb.Profile, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	prof, err := s.app.RestoreProfile(ctx, id, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	return toProto(prof), nil
+}
+
+// ProblemFromError maps handler errors to the problems of the REST API, so both
+// surfaces agree on codes: a duplicate is AlreadyExists, a stale version
+// FailedPrecondition and so on.
+func ProblemFromError(err error) *problem.Problem {
+	p := rest.ProblemFromDomainError(err)
+	out := &problem.Problem{
+		Code:   p.Code,
+		Detail: p.Detail,
+		Status: p.Status,
+		Title:  p.Title,
+		Type:   p.Type,
+	}
+	if p.InvalidParams != nil {
+		params := make([]problem.InvalidParam, 0, len(*p.InvalidParams))
+		for _, ip := range *p.InvalidParams {
+			params = append(params, problem.InvalidParam{Name: ip.Name, Reason: ip.Reason})
+		}
+		out.InvalidParams = &params
+	}
+	return out
+}
+
+func parseID(s string) (uuid.UUID, error) {
+	id, err := uuid.FromString(s)
+	if err != nil || id.IsNil() {
+		return uuid.Nil, invalidArgument("invalid id", "id", "must be a UUID")
+	}
+	return id, nil
+}
+
+func invalidArgument(detail, param, reason string) error {
+	return grpcserver.StatusFromProblem(problem.BadRequest(detail, problem.WithInvalidParam(param, reason))).Err()
+}
+
+func toProto(p *domain.Profile) *pb.Profile {
+	out := &pb.Profile{
+		Id:         p.ID.String(),
+		Name:       p.Name,
+		Email:      p.Email,
+		Age:        int32(p.Age),
+		CreateTime: timestamppb.New(p.CreatedAt),
+		Version:    p.Version,
+	}
+	if p.DeletedAt != nil {
+		out.DeleteTime = timestamppb.New(*p.DeletedAt)
+	}
+	return out
+}
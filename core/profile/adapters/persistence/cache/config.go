@@ -0,0 +1,25 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import "time"
+
+// Config configures the profile cache.
+type Config struct {
+	// Enabled serves profile reads by id from redis.
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// TTL bounds how long a profile, or the tombstone of a write, is cached.
+	TTL time.Duration `env:"TTL" envDefault:"5m"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache puts a read-through cache in front of the profile stores.
+//
+// CachedProfileReader serves GetProfileByID and ExistsProfile from a
+// db.VersionedKV, usually a RedisKV with client-side caching, and loads misses from
+// the wrapped reader once per profile however many requests miss at the same time. CachedProfileWriter
+// invalidates the profiles it writes once their transaction committed.
+//
+// Invalidation is version-aware: a write leaves a tombstone holding the version it
+// produced, and a reader only caches a profile loaded at that version or later.
+// The versions are compared where the entry is stored, in one step with the write
+// (see db.VersionedKV), so a read that raced the write, or hit a replica lagging
+// behind it, cannot put the old profile back in the cache, and an older tombstone
+// never replaces a newer one. Writes that could not reach the cache, as while redis
+// is down, are only picked up once the entries expire, so keep the TTL short.
+package cache
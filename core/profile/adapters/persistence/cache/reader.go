@@ -0,0 +1,129 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"log/slog"
+
+	"app/core/profile/domain"
+	"app/modules/db"
+
+	"github.com/gofrs/uuid/v5"
+	"golang.org/x/sync/singleflight"
+)
+
+var _ domain.ProfileReadStore = (*CachedProfileReader)(nil)
+
+type (
+	// CachedProfileReader is a domain.ProfileReadStore caching profiles by id in
+	// front of another one. Lists, history, counts and stats are not cached.
+	CachedProfileReader struct {
+		domain.ProfileReadStore
+		kv    db.JSONKV[entry]
+		store db.VersionedKV
+
+		// one load per missing profile
+		loads singleflight.Group
+	}
+
+	// entry is a cached profile, or the tombstone a write left when Profile is nil.
+	entry struct {
+		Version int64           `json:"v"`
+		Profile *domain.Profile `json:"p,omitempty"`
+	}
+)
+
+// NewCachedProfileReader caches the profiles read through inner in kv, which
+// also sets their TTL (see redis.WithDefaultTTL).
+func NewCachedProfileReader(inner domain.ProfileReadStore, kv db.VersionedKV) *CachedProfileReader {
+	return &CachedProfileReader{ProfileReadStore: inner, kv: db.NewJSONKV[entry](kv), store: kv}
+}
+
+func key(id uuid.UUID) string { return "profile:" + id.String() }
+
+// put writes e unless the cache holds an entry of a higher version, the check
+// running in kv so that racing loads and writes cannot undo each other.
+func put(ctx context.Context, kv db.VersionedKV, id uuid.UUID, e entry) error {
+	_, err := kv.AtomicSetVersioned(ctx, key(id), "v", e.Version, e)
+	return err
+}
+
+// GetProfileByID implements domain.ProfileReadStore.
+//
+// Contexts stuck to the primary (see db.StickToPrimary) bypass the cache, as they
+// ask for the latest state. Cache failures fall back to inner.
+func (r *CachedProfileReader) GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profile, error) {
+	if db.StuckToPrimary(ctx) {
+		return r.ProfileReadStore.GetProfileByID(ctx, id)
+	}
+
+	cached, err := r.kv.Get(ctx, key(id))
+	if err != nil {
+		slog.WarnContext(ctx, "profile cache: get failed", slog.String("profile.id", id.String()), slog.Any("error", err))
+	}
+	var floor int64
+	if cached != nil {
+		if cached.Profile != nil {
+			p := *cached.Profile
+			return &p, nil
+		}
+		floor = cached.Version
+	}
+
+	// the load is shared, so it must not fail for everyone when this caller gives up
+	res := r.loads.DoChan(key(id), func() (any, error) {
+		return r.load(context.WithoutCancel(ctx), id, floor)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case out := <-res:
+		if out.Err != nil {
+			return nil, out.Err
+		}
+		p := *out.Val.(*domain.Profile)
+		return &p, nil
+	}
+}
+
+// ExistsProfile implements domain.ProfileReadStore, answering from a cached
+// profile when there is one. Misses are not cached, the probe is cheap already.
+func (r *CachedProfileReader) ExistsProfile(ctx context.Context, id uuid.UUID) (int64, error) {
+	if !db.StuckToPrimary(ctx) {
+		if cached, err := r.kv.Get(ctx, key(id)); err == nil && cached != nil && cached.Profile != nil {
+			return cached.Profile.Version, nil
+		}
+	}
+	return r.ProfileReadStore.ExistsProfile(ctx, id)
+}
+
+// load reads the profile from inner and caches it unless a write newer than it
+// left a tombstone, at floor or since. Tombstones at its own version are replaced.
+func (r *CachedProfileReader) load(ctx context.Context, id uuid.UUID, floor int64) (*domain.Profile, error) {
+	p, err := r.ProfileReadStore.GetProfileByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version < floor {
+		// a replica behind the last write
+		return p, nil
+	}
+
+	if err := put(ctx, r.store, id, entry{Version: p.Version, Profile: p}); err != nil {
+		slog.WarnContext(ctx, "profile cache: set failed", slog.String("profile.id", id.String()), slog.Any("error", err))
+	}
+	return p, nil
+}
@@ -0,0 +1,196 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"app/core/profile/domain"
+	"app/modules/db"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var _ domain.ProfileWriteStore = (*CachedProfileWriter)(nil)
+
+type (
+	// CachedProfileWriter is a domain.ProfileWriteStore invalidating the cached
+	// profiles of a CachedProfileReader once their write committed.
+	CachedProfileWriter struct {
+		domain.ProfileWriteStore
+		kv db.VersionedKV
+	}
+
+	// cachedWriterTx records the profiles written in a transaction.
+	cachedWriterTx struct {
+		domain.ProfileWriteTx
+		written *[]write
+	}
+
+	// write is a profile id and the version a write left it at.
+	write struct {
+		id      uuid.UUID
+		version int64
+	}
+)
+
+// NewCachedProfileWriter invalidates the profiles written through inner in kv,
+// the db.VersionedKV of the CachedProfileReader.
+//
+// Creates are not recorded: misses are not cached, so a new profile has nothing
+// to invalidate.
+func NewCachedProfileWriter(inner domain.ProfileWriteStore, kv db.VersionedKV) *CachedProfileWriter {
+	return &CachedProfileWriter{ProfileWriteStore: inner, kv: kv}
+}
+
+// invalidate leaves a tombstone at the version each profile was written at. A
+// tombstone never replaces a newer one, written by a later commit on another node.
+func (w *CachedProfileWriter) invalidate(ctx context.Context, writes ...write) {
+	for _, wr := range writes {
+		if err := put(ctx, w.kv, wr.id, entry{Version: wr.version}); err != nil {
+			// the entry expires with its TTL
+			slog.WarnContext(ctx, "profile cache: invalidate failed", slog.String("profile.id", wr.id.String()), slog.Any("error", err))
+		}
+	}
+}
+
+// WithTx implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) WithTx(ctx context.Context, fn func(ctx context.Context, tx domain.ProfileWriteTx) error) error {
+	var changes []write
+	err := w.ProfileWriteStore.WithTx(ctx, func(ctx context.Context, tx domain.ProfileWriteTx) error {
+		// the pool may re-run a transaction aborted by a conflict
+		changes = changes[:0]
+		return fn(ctx, &cachedWriterTx{ProfileWriteTx: tx, written: &changes})
+	})
+	if err == nil {
+		w.invalidate(ctx, changes...)
+	}
+	return err
+}
+
+// WithTimeoutTx implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) WithTimeoutTx(ctx context.Context, timeout time.Duration, fn func(ctx context.Context, tx domain.ProfileWriteTx) error) error {
+	var changes []write
+	err := w.ProfileWriteStore.WithTimeoutTx(ctx, timeout, func(ctx context.Context, tx domain.ProfileWriteTx) error {
+		changes = changes[:0]
+		return fn(ctx, &cachedWriterTx{ProfileWriteTx: tx, written: &changes})
+	})
+	if err == nil {
+		w.invalidate(ctx, changes...)
+	}
+	return err
+}
+
+// UpsertProfile implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) UpsertProfile(ctx context.Context, email string, params *domain.UpsertProfileParams) (*domain.Profile, bool, error) {
+	p, created, err := w.ProfileWriteStore.UpsertProfile(ctx, email, params)
+	if err == nil && !created {
+		w.invalidate(ctx, write{p.ID, p.Version})
+	}
+	return p, created, err
+}
+
+// UpdateProfile implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) UpdateProfile(ctx context.Context, params *domain.UpdateProfileParams) (*domain.Profile, error) {
+	p, err := w.ProfileWriteStore.UpdateProfile(ctx, params)
+	if err == nil {
+		w.invalidate(ctx, write{p.ID, p.Version})
+	}
+	return p, err
+}
+
+// DeleteProfile implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error {
+	err := w.ProfileWriteStore.DeleteProfile(ctx, id, version)
+	if err == nil {
+		// the soft delete increments the version
+		w.invalidate(ctx, write{id, version + 1})
+	}
+	return err
+}
+
+// RestoreProfile implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*domain.Profile, error) {
+	p, err := w.ProfileWriteStore.RestoreProfile(ctx, id, version)
+	if err == nil {
+		w.invalidate(ctx, write{p.ID, p.Version})
+	}
+	return p, err
+}
+
+// ModifyProfile implements domain.ProfileWriteStore.
+func (w *CachedProfileWriter) ModifyProfile(
+	ctx context.Context,
+	id uuid.UUID,
+	version int64,
+	nameSet, nameNull bool, nameVal string,
+	ageSet, ageNull bool, ageVal int32,
+	emailSet bool, emailVal string,
+) (*domain.Profile, error) {
+	p, err := w.ProfileWriteStore.ModifyProfile(ctx, id, version, nameSet, nameNull, nameVal, ageSet, ageNull, ageVal, emailSet, emailVal)
+	if err == nil {
+		w.invalidate(ctx, write{p.ID, p.Version})
+	}
+	return p, err
+}
+
+func (t *cachedWriterTx) UpsertProfile(ctx context.Context, email string, params *domain.UpsertProfileParams) (*domain.Profile, bool, error) {
+	p, created, err := t.ProfileWriteTx.UpsertProfile(ctx, email, params)
+	if err == nil && !created {
+		*t.written = append(*t.written, write{p.ID, p.Version})
+	}
+	return p, created, err
+}
+
+func (t *cachedWriterTx) UpdateProfile(ctx context.Context, params *domain.UpdateProfileParams) (*domain.Profile, error) {
+	p, err := t.ProfileWriteTx.UpdateProfile(ctx, params)
+	if err == nil {
+		*t.written = append(*t.written, write{p.ID, p.Version})
+	}
+	return p, err
+}
+
+func (t *cachedWriterTx) DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error {
+	err := t.ProfileWriteTx.DeleteProfile(ctx, id, version)
+	if err == nil {
+		*t.written = append(*t.written, write{id, version + 1})
+	}
+	return err
+}
+
+func (t *cachedWriterTx) RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*domain.Profile, error) {
+	p, err := t.ProfileWriteTx.RestoreProfile(ctx, id, version)
+	if err == nil {
+		*t.written = append(*t.written, write{p.ID, p.Version})
+	}
+	return p, err
+}
+
+func (t *cachedWriterTx) ModifyProfile(
+	ctx context.Context,
+	id uuid.UUID,
+	version int64,
+	nameSet, nameNull bool, nameVal string,
+	ageSet, ageNull bool, ageVal int32,
+	emailSet bool, emailVal string,
+) (*domain.Profile, error) {
+	p, err := t.ProfileWriteTx.ModifyProfile(ctx, id, version, nameSet, nameNull, nameVal, ageSet, ageNull, ageVal, emailSet, emailVal)
+	if err == nil {
+		*t.written = append(*t.written, write{p.ID, p.Version})
+	}
+	return p, err
+}
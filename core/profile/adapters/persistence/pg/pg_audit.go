@@ -0,0 +1,140 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"app/core/profile/domain"
+	"app/modules/db"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+const (
+	// DefaultAuditTable is the profile history table written by the trigger of migration V5.
+	DefaultAuditTable = "profile_audit"
+	// DefaultAuditPurgeBatch bounds the rows one purge statement deletes, keeping locks short.
+	DefaultAuditPurgeBatch = 5000
+)
+
+type auditRow struct {
+	ProfileRow
+	Operation string `db:"operation"`
+}
+
+// WithAuditTable reads GetProfileAsOf from table instead of DefaultAuditTable.
+func WithAuditTable(table string) ReaderOption {
+	return func(r *PostgresProfileReader) {
+		if table != "" {
+			r.auditTable = table
+		}
+	}
+}
+
+// GetProfileAsOf picks the latest audit row recorded at or before at, walking the
+// (profile_id, recorded_at DESC) index; the history is append-only, so replicas serve it.
+func (r *PostgresProfileReader) GetProfileAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*domain.Profile, error) {
+	q := psql.RawQuery(fmt.Sprintf(`
+		SELECT profile_id AS id, username, email, age, created_at, updated_at, deleted_at, version_number, operation
+		FROM %s
+		WHERE profile_id = $1 AND recorded_at <= $2
+		ORDER BY recorded_at DESC, audit_id DESC
+		LIMIT 1
+	`, r.auditTable), id, at)
+
+	row, err := bob.One(ctx, r.pool.Reader(ctx), q, scan.StructMapper[auditRow]())
+	if err != nil {
+		return nil, wrapProfileError("pg.GetProfileAsOf", err)
+	}
+	// hard-deleted, or soft-deleted at that instant
+	if row.Operation == "D" || row.DeletedAt.Valid {
+		return nil, domain.ErrProfileNotFound
+	}
+	prof := toProfile(row.ProfileRow)
+	return &prof, nil
+}
+
+// AuditPurger drops profile history older than a retention window.
+//
+// Each profile keeps the newest row recorded before the cutoff, since it still holds
+// the state at the start of the window; only profiles hard-deleted before the cutoff
+// lose their whole history. Schedule Purge through a locking.Scheduler, like
+// StatsViewRefresher.
+type AuditPurger struct {
+	pool      db.ConnectionManager
+	table     string
+	retention time.Duration
+}
+
+func NewAuditPurger(pool db.ConnectionManager, table string, retention time.Duration) *AuditPurger {
+	if table == "" {
+		table = DefaultAuditTable
+	}
+	return &AuditPurger{pool: pool, table: table, retention: retention}
+}
+
+// Purge deletes the rows no instant within the retention window depends on, in
+// batches of DefaultAuditPurgeBatch, and reports how many were removed.
+func (p *AuditPurger) Purge(ctx context.Context) (int64, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+	start := time.Now()
+	// a fixed cutoff, so later batches do not chase rows that age in meanwhile
+	cutoff := start.Add(-p.retention)
+	raw := fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE audit_id IN (
+			SELECT a.audit_id FROM %[1]s a
+			WHERE a.recorded_at < $1
+			  AND (a.operation = 'D' OR EXISTS (
+				SELECT 1 FROM %[1]s b
+				WHERE b.profile_id = a.profile_id
+				  AND b.recorded_at < $1
+				  AND (b.recorded_at, b.audit_id) > (a.recorded_at, a.audit_id)
+			  ))
+			LIMIT $2
+		)
+	`, p.table)
+
+	var total int64
+	for ctx.Err() == nil {
+		res, err := bob.Exec(ctx, p.pool.Writer(), psql.RawQuery(raw, cutoff, DefaultAuditPurgeBatch))
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return total, wrapProfileError("pg.PurgeAudit", err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		if n < DefaultAuditPurgeBatch {
+			break
+		}
+	}
+	slog.DebugContext(ctx, "profile audit purged",
+		slog.String("table", p.table),
+		slog.Int64("deleted", total),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return total, nil
+}
@@ -0,0 +1,185 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"app/core/profile/domain"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/scan"
+)
+
+// MaxBulkInsertRows keeps a single INSERT below Postgres' 65535 bind parameter limit.
+const MaxBulkInsertRows = 10_000
+
+// BulkInsertProfiles inserts fully populated profiles with one multi-row INSERT,
+// keeping their ID, age and creation time. It exists for seeding and backfills,
+// bypassing the prepared single-row path.
+//
+// Rows whose email already exists are skipped; the number actually inserted is
+// returned. A zero Age is stored as NULL. Callers batch at most MaxBulkInsertRows.
+func (w *PostgresProfileWriter) BulkInsertProfiles(ctx context.Context, profiles []domain.Profile) (int, error) {
+	if len(profiles) == 0 {
+		return 0, nil
+	}
+	if len(profiles) > MaxBulkInsertRows {
+		return 0, fmt.Errorf("%w: %d rows exceed MaxBulkInsertRows", domain.ErrInvalidData, len(profiles))
+	}
+
+	mods := make([]bob.Mod[*dialect.InsertQuery], 0, len(profiles)+2)
+	mods = append(mods, im.Into(w.table, "id", "username", "email", "age", "created_at", "updated_at"))
+	for _, p := range profiles {
+		age := sql.NullInt32{Int32: int32(p.Age), Valid: p.Age > 0}
+		mods = append(mods, im.Values(psql.Arg(p.ID, p.Name, p.Email, age, p.CreatedAt, p.CreatedAt)))
+	}
+	mods = append(mods, im.OnConflict("email").DoNothing())
+
+	res, err := psql.Insert(mods...).Exec(ctx, w.db)
+	if err != nil {
+		return 0, wrapProfileError("pg.BulkInsertProfiles", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, wrapProfileError("pg.BulkInsertProfiles", err)
+	}
+	return int(n), nil
+}
+
+// CreateProfiles implements ProfileWriteStore (non-transactional).
+func (w *PostgresProfileWriter) CreateProfiles(ctx context.Context, profiles []domain.NewProfile) ([]domain.CreateProfileResult, error) {
+	results, err := createProfiles(ctx, w.db, w.table, "pg.CreateProfiles", profiles)
+	if err == nil {
+		w.recent.Mark(createdIDs(results)...)
+	}
+	return results, err
+}
+
+func (t *profileWriterTx) CreateProfiles(ctx context.Context, profiles []domain.NewProfile) ([]domain.CreateProfileResult, error) {
+	results, err := createProfiles(ctx, t.tx, t.parent.table, "pg.tx.CreateProfiles", profiles)
+	if err == nil {
+		t.written = append(t.written, createdIDs(results)...)
+	}
+	return results, err
+}
+
+// createProfiles runs one multi-row INSERT ... ON CONFLICT DO NOTHING RETURNING, so
+// conflicting rows are skipped instead of failing the statement.
+//
+// IDs are assigned up front (UUIDv7, like the column default) to match returned rows
+// back to their index. Rows repeating an email or ID of an earlier row never reach the
+// statement, and a second lookup only runs when rows with a caller-chosen ID were
+// skipped, to tell a taken ID from a taken email.
+func createProfiles(ctx context.Context, exec bob.Executor, table, op string, profiles []domain.NewProfile) ([]domain.CreateProfileResult, error) {
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	if len(profiles) > MaxBulkInsertRows {
+		return nil, fmt.Errorf("%w: %d rows exceed MaxBulkInsertRows", domain.ErrInvalidData, len(profiles))
+	}
+
+	results := make([]domain.CreateProfileResult, len(profiles))
+	// index of every row sent, by its id
+	pending := make(map[uuid.UUID]int, len(profiles))
+	// citext compares emails case-insensitively
+	emails := make(map[string]struct{}, len(profiles))
+
+	mods := make([]bob.Mod[*dialect.InsertQuery], 0, len(profiles)+3)
+	mods = append(mods, im.Into(table, "id", "username", "email", "age"))
+	for i, p := range profiles {
+		id := p.ID
+		if id.IsNil() {
+			var err error
+			if id, err = uuid.NewV7(); err != nil {
+				return nil, fmt.Errorf("%s: generate id: %w", op, err)
+			}
+		}
+		email := strings.ToLower(p.Email)
+		if _, taken := pending[id]; taken {
+			results[i].Err = domain.ErrPrecondition
+			continue
+		}
+		if _, taken := emails[email]; taken {
+			results[i].Err = domain.ErrDuplicateProfile
+			continue
+		}
+		pending[id] = i
+		emails[email] = struct{}{}
+
+		age := sql.NullInt32{Int32: int32(p.Age), Valid: p.Age > 0}
+		mods = append(mods, im.Values(psql.Arg(id, p.Name, p.Email, age)))
+	}
+	mods = append(mods,
+		im.OnConflict().DoNothing(),
+		im.Returning("id", "username", "email", "age", "created_at", "version_number"),
+	)
+
+	rows, err := bob.Allx[profileTransformer](ctx, exec, psql.Insert(mods...), scan.StructMapper[ProfileRow]())
+	if err != nil {
+		return nil, wrapProfileError(op, err)
+	}
+	for _, prof := range rows {
+		results[pending[prof.ID]].Profile = &prof
+		delete(pending, prof.ID)
+	}
+	if len(pending) == 0 {
+		return results, nil
+	}
+
+	// the remaining rows conflicted on their email, or on an ID the caller chose
+	var chosen []any
+	for id, i := range pending {
+		results[i].Err = domain.ErrDuplicateProfile
+		if !profiles[i].ID.IsNil() {
+			chosen = append(chosen, id)
+		}
+	}
+	if len(chosen) == 0 {
+		return results, nil
+	}
+	query := psql.Select(
+		sm.Columns("id"),
+		sm.From(table),
+		sm.Where(psql.Quote("id").In(psql.Arg(chosen...))),
+	)
+	taken, err := bob.All(ctx, exec, query, scan.SingleColumnMapper[uuid.UUID])
+	if err != nil {
+		return nil, wrapProfileError(op, err)
+	}
+	for _, id := range taken {
+		results[pending[id]].Err = domain.ErrPrecondition
+	}
+	return results, nil
+}
+
+// createdIDs lists the ids of the created profiles, for recent write marks.
+func createdIDs(results []domain.CreateProfileResult) []string {
+	ids := make([]string, 0, len(results))
+	for _, r := range results {
+		if r.Profile != nil {
+			ids = append(ids, r.Profile.ID.String())
+		}
+	}
+	return ids
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "app/core/profile/adapters/persistence/pg"
+
+// Reasons a query was cut short, recorded as the "reason" attribute.
+const (
+	// the caller (usually the HTTP client) went away and cancelled the request context
+	cancelReasonClientDisconnect = "client_disconnect"
+	// a context deadline or the server side statement_timeout fired
+	cancelReasonTimeout = "timeout"
+)
+
+// cancelledQueries counts queries that did not complete because their context
+// ended, split by reason. It is created lazily so the global MeterProvider set up
+// by telemetry is in place before the first use.
+var cancelledQueries = sync.OnceValue(func() metric.Int64Counter {
+	c, err := otel.Meter(instrumentationName).Int64Counter("app_db_queries_cancelled_total",
+		metric.WithDescription("Profile store queries cancelled before completion, by reason"),
+		metric.WithUnit("{query}"),
+	)
+	if err != nil {
+		slog.Warn("pg: cancelled query counter not created", slog.Any("error", err))
+		return nil
+	}
+	return c
+})
+
+func recordCancelled(op, reason string) {
+	c := cancelledQueries()
+	if c == nil {
+		return
+	}
+	// the request context is already done, it must not gate the measurement
+	c.Add(context.Background(), 1, metric.WithAttributes(
+		attribute.String("op", op),
+		attribute.String("reason", reason),
+	))
+}
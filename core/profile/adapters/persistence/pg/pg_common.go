@@ -18,9 +18,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"app/core/profile/domain"
+	"app/modules/apperr"
 
 	"github.com/gofrs/uuid/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -43,7 +46,7 @@ type (
 
 // toProfile converts a ProfileRow to a domain Profile.
 func toProfile(row ProfileRow) domain.Profile {
-	return domain.Profile{
+	p := domain.Profile{
 		ID:        row.ID,
 		Name:      row.Name,
 		Email:     row.Email,
@@ -51,6 +54,10 @@ func toProfile(row ProfileRow) domain.Profile {
 		CreatedAt: row.CreatedAt,
 		Version:   row.Version.Int64,
 	}
+	if row.DeletedAt.Valid {
+		p.DeletedAt = &row.DeletedAt.Time
+	}
+	return p
 }
 
 // profileTransformer implements bob's transformer interface for automatic row to domain conversion.
@@ -64,28 +71,58 @@ func (profileTransformer) TransformScanned(rows []ProfileRow) ([]domain.Profile,
 	return out, nil
 }
 
-// wrapProfileError centralizes mapping of DB errors to domain errors.
-func wrapProfileError(err error) error {
+// wrapProfileError centralizes mapping of DB errors to domain errors: driver
+// errors become domain sentinels and everything else is classified with apperr
+// codes, so upper layers can decide on retries without knowing about pgx.
+func wrapProfileError(op string, err error) error {
 	if err == nil {
 		return nil
 	}
 
 	// sql.ErrNoRows is expected in many flows (not found / precondition failed)
 	if errors.Is(err, sql.ErrNoRows) {
-		return domain.ErrProfileNotFound
+		return apperr.Wrap(op, domain.ErrProfileNotFound)
 	}
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
 		switch pgErr.Code {
 		case "23505": // unique_violation
-			return domain.ErrDuplicateProfile
-		case "40001": // serialization_failure
-			return domain.ErrPrecondition
+			return apperr.Wrap(op, domain.ErrDuplicateProfile)
+		case "23514", "23502", "22001": // check_violation, not_null_violation, string_data_right_truncation
+			return apperr.Wrap(op, domain.ErrInvalidData)
+		case "40001", "40P01": // serialization_failure, deadlock_detected
+			// keep the driver error in the chain, the pool's tx retry matches on its code
+			return &apperr.Error{Op: op, Retryable: true, Err: fmt.Errorf("%w: %w", domain.ErrPrecondition, err)}
+		case "57014": // query_canceled (statement_timeout)
+			recordCancelled(op, cancelReasonTimeout)
+			return apperr.Transient(op, apperr.CodeTimeout, err)
+		}
+		if strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" { // connection exceptions, admin_shutdown
+			return apperr.Transient(op, apperr.CodeUnavailable, err)
 		}
+		return apperr.WithCode(op, apperr.CodeInternal, err)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// pgx aborts the in-flight query when the request context is cancelled,
+		// which for HTTP requests means the client disconnected
+		recordCancelled(op, cancelReasonClientDisconnect)
+		return apperr.WithCode(op, apperr.CodeCanceled, err)
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err):
+		recordCancelled(op, cancelReasonTimeout)
+		return apperr.Transient(op, apperr.CodeTimeout, err)
+	case pgconn.SafeToRetry(err):
+		// the request never reached the server (e.g. dial failure)
+		return apperr.Transient(op, apperr.CodeUnavailable, err)
+	}
+	var connErr *pgconn.ConnectError
+	if errors.As(err, &connErr) {
+		return apperr.Transient(op, apperr.CodeUnavailable, err)
 	}
 
-	return err
+	return apperr.Wrap(op, err)
 }
 
 // inTxQueryStmt rebinds a QueryStmt to a transaction.
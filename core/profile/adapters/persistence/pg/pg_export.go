@@ -0,0 +1,89 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"app/core/profile/domain"
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// exportCursor names the server-side cursor of StreamProfiles; it only lives as long
+// as its transaction, so a fixed name never clashes between exports.
+const exportCursor = "profile_export"
+
+// StreamProfiles declares a server-side cursor in a read-only transaction and FETCHes
+// it batchSize rows at a time, calling emit between round trips. Postgres only sends
+// the rows asked for, so a slow consumer holds one batch in memory instead of the table.
+//
+// Cancelling ctx interrupts the FETCH in flight and rolls the transaction back, which
+// closes the cursor. Each FETCH is its own statement for statement_timeout, while the
+// whole export keeps a replica snapshot open: long exports on a hot standby may hit
+// max_standby_streaming_delay and be cancelled by recovery conflicts.
+func (r *PostgresProfileReader) StreamProfiles(ctx context.Context, batchSize int, emit func([]domain.Profile) error) error {
+	if batchSize <= 0 {
+		return domain.ErrInvalidData
+	}
+
+	declare := fmt.Sprintf(`
+		DECLARE %s NO SCROLL CURSOR FOR
+		SELECT id, username, email, age, created_at, version_number
+		FROM %s
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+	`, exportCursor, r.table)
+	// FETCH does not take its count as a parameter
+	fetch := psql.RawQuery(fmt.Sprintf("FETCH FORWARD %d FROM %s", batchSize, exportCursor))
+
+	var emitErr error
+	err := r.pool.WithReadOnlyTx(ctx, func(ctx context.Context, q db.Querier) error {
+		if _, err := q.ExecContext(ctx, declare); err != nil {
+			return err
+		}
+		for {
+			batch, err := bob.Allx[profileTransformer](ctx, q, fetch, scan.StructMapper[ProfileRow]())
+			if err != nil {
+				return err
+			}
+			if len(batch) > 0 {
+				if err := emit(batch); err != nil {
+					emitErr = err
+					return err
+				}
+			}
+			if len(batch) < batchSize {
+				return nil
+			}
+		}
+	})
+	if err == nil {
+		return nil
+	}
+	if emitErr != nil && errors.Is(err, emitErr) {
+		return emitErr
+	}
+	if ctx.Err() == nil {
+		slog.ErrorContext(ctx, "StreamProfiles query error", slog.Any("err", err))
+	}
+	return wrapProfileError("pg.StreamProfiles", err)
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"fmt"
+	"strings"
+
+	"app/core/profile/domain"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+)
+
+// Profile lists are built from composable mods: filterMods selects the rows,
+// orderMods sorts them and keysetMod resumes after a cursor pivot. The three agree
+// on the sort expressions below, which migrations V6 and V7 index.
+
+// nameSortExpr compares names case-insensitively in byte order. The "C" collation
+// makes the index usable for both the sort and LIKE prefix matches.
+const nameSortExpr = `lower(COALESCE(username, '')) COLLATE "C"`
+
+type sortKey struct {
+	// expr is the sorted SQL expression.
+	expr string
+	// pivot is the SQL placeholder pivotValue is bound to, normalized like expr.
+	pivot      string
+	pivotValue func(domain.CursorPivot) any
+}
+
+// sortKeys maps every whitelisted domain.SortField to its SQL; ties break on id.
+var sortKeys = map[domain.SortField]sortKey{
+	domain.SortByCreatedAt: {
+		expr:       "created_at",
+		pivot:      "?",
+		pivotValue: func(p domain.CursorPivot) any { return p.CreatedAt },
+	},
+	domain.SortByName: {
+		expr:       nameSortExpr,
+		pivot:      `lower(?) COLLATE "C"`,
+		pivotValue: func(p domain.CursorPivot) any { return p.Name },
+	},
+	domain.SortByAge: {
+		// domain profiles carry a missing age as 0, which is never a valid age
+		expr:       "COALESCE(age, 0)",
+		pivot:      "?",
+		pivotValue: func(p domain.CursorPivot) any { return p.Age },
+	},
+}
+
+func lookupSort(s domain.ProfileSort) (sortKey, error) {
+	key, ok := sortKeys[s.Field]
+	if !ok {
+		return sortKey{}, fmt.Errorf("%w: unknown sort field %q", domain.ErrInvalidData, s.Field)
+	}
+	return key, nil
+}
+
+// filterMods selects the live profiles matching f, and the soft-deleted ones with
+// f.IncludeDeleted. Those are outside the partial indexes and scan the table.
+func filterMods(f domain.ProfileFilter) []bob.Mod[*dialect.SelectQuery] {
+	var mods []bob.Mod[*dialect.SelectQuery]
+	if !f.IncludeDeleted {
+		mods = append(mods, sm.Where(psql.Quote("deleted_at").IsNull()))
+	}
+	if f.NamePrefix != "" {
+		mods = append(mods, sm.Where(psql.Raw(nameSortExpr+" LIKE lower(?)", escapeLike(f.NamePrefix)+"%")))
+	}
+	if f.MinAge != nil {
+		mods = append(mods, sm.Where(psql.Quote("age").GTE(psql.Arg(*f.MinAge))))
+	}
+	if f.MaxAge != nil {
+		mods = append(mods, sm.Where(psql.Quote("age").LTE(psql.Arg(*f.MaxAge))))
+	}
+	if f.CreatedFrom != nil {
+		mods = append(mods, sm.Where(psql.Quote("created_at").GTE(psql.Arg(*f.CreatedFrom))))
+	}
+	if f.CreatedTo != nil {
+		mods = append(mods, sm.Where(psql.Quote("created_at").LT(psql.Arg(*f.CreatedTo))))
+	}
+	if f.EmailDomain != "" {
+		// emails are validated to contain exactly one "@"
+		mods = append(mods, sm.Where(psql.Raw("lower(split_part(email::text, '@', 2)) = lower(?)", f.EmailDomain)))
+	}
+	return mods
+}
+
+// orderMods sorts by key then id, in the list order of s or, reversed, against it.
+func orderMods(key sortKey, s domain.ProfileSort, reversed bool) []bob.Mod[*dialect.SelectQuery] {
+	if s.Desc != reversed {
+		return []bob.Mod[*dialect.SelectQuery]{sm.OrderBy(psql.Raw(key.expr)).Desc(), sm.OrderBy("id").Desc()}
+	}
+	return []bob.Mod[*dialect.SelectQuery]{sm.OrderBy(psql.Raw(key.expr)).Asc(), sm.OrderBy("id").Asc()}
+}
+
+// keysetMod keeps the rows strictly after pivot in the list order of s when forward,
+// strictly before it otherwise. Row comparison keeps (expr, id) on one index range.
+func keysetMod(key sortKey, s domain.ProfileSort, pivot domain.CursorPivot, forward bool) bob.Mod[*dialect.SelectQuery] {
+	cmp := ">"
+	if s.Desc == forward {
+		cmp = "<"
+	}
+	return sm.Where(psql.Raw(
+		fmt.Sprintf("(%s, id) %s (%s, ?)", key.expr, cmp, key.pivot),
+		key.pivotValue(pivot), pivot.ID,
+	))
+}
+
+// escapeLike escapes the LIKE wildcards of s (with the default backslash escape).
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
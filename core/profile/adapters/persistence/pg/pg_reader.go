@@ -16,9 +16,8 @@ package pg
 
 import (
 	"context"
-	"fmt"
 	"log/slog"
-	"time"
+	"slices"
 
 	"app/core/profile/domain"
 	"app/modules/db"
@@ -26,6 +25,7 @@ import (
 	"github.com/gofrs/uuid/v5"
 	"github.com/stephenafamo/bob"
 	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
 	"github.com/stephenafamo/bob/dialect/psql/sm"
 	"github.com/stephenafamo/scan"
 )
@@ -33,13 +33,42 @@ import (
 var _ domain.ProfileReadStore = (*PostgresProfileReader)(nil)
 
 type (
-	// TODO: read-ony transaction
 	PostgresProfileReader struct {
 		table string
-		pool  db.ReaderConnectionManager
+		pool  db.ReaderTxManager
+
+		// materialized view serving ProfileStats, empty to aggregate the live table
+		statsView string
+
+		// profiles written in the last moments, read from the primary
+		recent *db.RecentWrites
+
+		// history serving GetProfileAsOf
+		auditTable string
 	}
+
+	// ReaderOption configures a PostgresProfileReader.
+	ReaderOption func(*PostgresProfileReader)
 )
 
+// WithStatsView serves ProfileStats from the pre-aggregated materialized view
+// (see migration V2 and StatsViewRefresher) instead of scanning the live table.
+// Stats are then as fresh as the last refresh.
+func WithStatsView(view string) ReaderOption {
+	return func(r *PostgresProfileReader) {
+		r.statsView = view
+	}
+}
+
+// WithReadYourWrites reads profiles marked in recent (see WithRecentWrites on the
+// writer) from the primary, so a client reading back its own write does not hit a
+// replica that has not replayed it yet. Lists and stats still use the replicas.
+func WithReadYourWrites(recent *db.RecentWrites) ReaderOption {
+	return func(r *PostgresProfileReader) {
+		r.recent = recent
+	}
+}
+
 // NewPostgresProfileReader creates a new reader that calls Reader() at runtime for load balancing.
 //
 // This approach uses dynamic queries instead of prepared statements for reads.
@@ -51,112 +80,136 @@ type (
 //
 // If read performance is critical and you have a single replica, consider using prepared
 // statements bound to that replica. For most use cases, dynamic queries are sufficient.
-func NewPostgresProfileReader(pool db.ReaderConnectionManager, table string) *PostgresProfileReader {
-	return &PostgresProfileReader{
-		table: table,
-		pool:  pool,
+//
+// Multi-query reads (list + count) run in a read-only transaction so they share one snapshot.
+func NewPostgresProfileReader(pool db.ReaderTxManager, table string, opts ...ReaderOption) *PostgresProfileReader {
+	r := &PostgresProfileReader{
+		table:      table,
+		pool:       pool,
+		auditTable: DefaultAuditTable,
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
 }
 
 // GetProfilesByCursor implements ProfileReadStore (pivot-based cursor).
-// Calls pool.Reader() at runtime for replica load balancing.
+// Calls pool.Reader(ctx) at runtime for replica load balancing.
+//
+// A previous page is read walking the index backwards from the pivot, so it holds the
+// rows nearest to it, and is then reversed into the list order.
 func (r *PostgresProfileReader) GetProfilesByCursor(
 	ctx context.Context,
-	pivotCreatedAt time.Time,
-	pivotID uuid.UUID,
+	q domain.ProfileQuery,
+	pivot domain.CursorPivot,
 	dir domain.CursorDirection,
 	limit int,
 ) ([]domain.Profile, error) {
 	if limit <= 0 {
 		return nil, domain.ErrInvalidData
 	}
-
-	// Comparator relative to ORDER BY created_at DESC, id DESC
-	comparator := "<"
-	if dir == domain.ASC {
-		comparator = ">"
+	key, err := lookupSort(q.Sort)
+	if err != nil {
+		return nil, err
 	}
 
-	raw := fmt.Sprintf(`
-		SELECT id, username, email, age, created_at, version_number
-		FROM %s
-		WHERE deleted_at IS NULL
-		  AND (created_at, id) %s ($1, $2)
-		ORDER BY created_at DESC, id DESC
-		LIMIT $3
-	`, r.table, comparator)
+	forward := dir != domain.ASC
+	mods := append(r.listMods(q.Filter), keysetMod(key, q.Sort, pivot, forward))
+	mods = append(mods, orderMods(key, q.Sort, !forward)...)
+	mods = append(mods, sm.Limit(limit))
 
-	q := psql.RawQuery(raw, pivotCreatedAt, pivotID, limit)
-	rows, err := bob.Allx[profileTransformer](ctx, r.pool.Reader(), q, scan.StructMapper[ProfileRow]())
+	rows, err := bob.Allx[profileTransformer](ctx, r.pool.Reader(ctx), psql.Select(mods...), scan.StructMapper[ProfileRow]())
 	if err != nil {
 		slog.ErrorContext(ctx, "GetProfilesByCursor query error", slog.Any("err", err))
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.GetProfilesByCursor", err)
+	}
+	if !forward {
+		slices.Reverse(rows)
 	}
 	return rows, nil
 }
 
-func (r *PostgresProfileReader) GetProfilesFirstPage(ctx context.Context, limit int) ([]domain.Profile, error) {
+func (r *PostgresProfileReader) GetProfilesFirstPage(ctx context.Context, q domain.ProfileQuery, limit int) ([]domain.Profile, error) {
 	if limit <= 0 {
 		return nil, domain.ErrInvalidData
 	}
+	key, err := lookupSort(q.Sort)
+	if err != nil {
+		return nil, err
+	}
 
-	query := psql.Select(
-		sm.Columns("id", "username", "email", "age", "created_at", "version_number"),
-		sm.From(r.table),
-		sm.Where(psql.Quote("deleted_at").IsNull()),
-		sm.OrderBy("created_at").Desc(),
-		sm.OrderBy("id").Desc(),
-		sm.Limit(limit),
-	)
+	mods := append(r.listMods(q.Filter), orderMods(key, q.Sort, false)...)
+	mods = append(mods, sm.Limit(limit))
 
-	profiles, err := bob.Allx[profileTransformer](ctx, r.pool.Reader(), query, scan.StructMapper[ProfileRow]())
+	profiles, err := bob.Allx[profileTransformer](ctx, r.pool.Reader(ctx), psql.Select(mods...), scan.StructMapper[ProfileRow]())
 	if err != nil {
 		slog.ErrorContext(ctx, "GetProfilesFirstPage error", slog.Any("err", err))
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.GetProfilesFirstPage", err)
 	}
 	return profiles, nil
 }
 
 func (r *PostgresProfileReader) GetProfilesByOffset(
 	ctx context.Context,
+	q domain.ProfileQuery,
 	limit, offset int,
 ) ([]domain.Profile, int, error) {
 	if limit <= 0 || offset < 0 {
 		return nil, 0, domain.ErrInvalidData
 	}
-
-	listQuery := psql.Select(
-		sm.Columns("id", "username", "email", "age", "created_at", "version_number"),
-		sm.From(r.table),
-		sm.Where(psql.Quote("deleted_at").IsNull()),
-		sm.OrderBy("created_at").Desc(),
-		sm.OrderBy("id").Desc(),
-		sm.Limit(limit),
-		sm.Offset(offset),
-	)
-
-	profiles, err := bob.Allx[profileTransformer](ctx, r.pool.Reader(), listQuery, scan.StructMapper[ProfileRow]())
+	key, err := lookupSort(q.Sort)
 	if err != nil {
-		slog.ErrorContext(ctx, "GetProfilesByOffset query error", slog.Any("err", err))
-		return nil, 0, wrapProfileError(err)
+		return nil, 0, err
 	}
 
-	countQuery := psql.Select(
-		sm.Columns("COUNT(*)"),
-		sm.From(r.table),
-		sm.Where(psql.Quote("deleted_at").IsNull()),
+	listMods := append(r.listMods(q.Filter), orderMods(key, q.Sort, false)...)
+	listMods = append(listMods, sm.Limit(limit), sm.Offset(offset))
+	listQuery := psql.Select(listMods...)
+
+	countMods := append([]bob.Mod[*dialect.SelectQuery]{sm.Columns("COUNT(*)"), sm.From(r.table)}, filterMods(q.Filter)...)
+	countQuery := psql.Select(countMods...)
+
+	var (
+		profiles []domain.Profile
+		count    int
 	)
+	// same snapshot for the page and the total, so pages never disagree with totalItems
+	err = r.pool.WithReadOnlyTx(ctx, func(ctx context.Context, q db.Querier) error {
+		var err error
+		profiles, err = bob.Allx[profileTransformer](ctx, q, listQuery, scan.StructMapper[ProfileRow]())
+		if err != nil {
+			slog.ErrorContext(ctx, "GetProfilesByOffset query error", slog.Any("err", err))
+			return err
+		}
 
-	count, err := bob.One(ctx, r.pool.Reader(), countQuery, scan.SingleColumnMapper[int])
+		count, err = bob.One(ctx, q, countQuery, scan.SingleColumnMapper[int])
+		if err != nil {
+			slog.ErrorContext(ctx, "GetProfilesByOffset count error", slog.Any("err", err))
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		slog.ErrorContext(ctx, "GetProfilesByOffset count error", slog.Any("err", err))
-		return nil, 0, wrapProfileError(err)
+		return nil, 0, wrapProfileError("pg.GetProfilesByOffset", err)
 	}
 
 	return profiles, count, nil
 }
 
+// listMods selects the profile columns of the profiles matching f.
+func (r *PostgresProfileReader) listMods(f domain.ProfileFilter) []bob.Mod[*dialect.SelectQuery] {
+	mods := []bob.Mod[*dialect.SelectQuery]{
+		sm.Columns("id", "username", "email", "age", "created_at", "deleted_at", "version_number"),
+		sm.From(r.table),
+	}
+	return append(mods, filterMods(f)...)
+}
+
 func (r *PostgresProfileReader) GetProfileByID(ctx context.Context, id uuid.UUID) (*domain.Profile, error) {
+	ctx = r.recent.Stick(ctx, id.String())
 	query := psql.Select(
 		sm.Columns("id", "username", "email", "age", "created_at", "version_number"),
 		sm.From(r.table),
@@ -164,10 +217,46 @@ func (r *PostgresProfileReader) GetProfileByID(ctx context.Context, id uuid.UUID
 		sm.Where(psql.Quote("deleted_at").IsNull()),
 	)
 
-	row, err := bob.One(ctx, r.pool.Reader(), query, scan.StructMapper[ProfileRow]())
+	row, err := bob.One(ctx, r.pool.Reader(ctx), query, scan.StructMapper[ProfileRow]())
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.GetProfileByID", err)
 	}
 	prof := toProfile(row)
 	return &prof, nil
 }
+
+// ExistsProfile only reads version_number, which the ETag needs; it is otherwise the
+// `SELECT 1 ... WHERE id = $1` probe and never transfers the profile payload.
+func (r *PostgresProfileReader) ExistsProfile(ctx context.Context, id uuid.UUID) (int64, error) {
+	ctx = r.recent.Stick(ctx, id.String())
+	query := psql.Select(
+		sm.Columns("version_number"),
+		sm.From(r.table),
+		sm.Where(psql.Quote("id").EQ(psql.Arg(id))),
+		sm.Where(psql.Quote("deleted_at").IsNull()),
+	)
+
+	version, err := bob.One(ctx, r.pool.Reader(ctx), query, scan.SingleColumnMapper[int64])
+	if err != nil {
+		return 0, wrapProfileError("pg.ExistsProfile", err)
+	}
+	return version, nil
+}
+
+// EmailTaken runs the same index probe whether or not a row matches and ignores
+// deleted_at on purpose: the unique constraint covers soft-deleted rows too.
+func (r *PostgresProfileReader) EmailTaken(ctx context.Context, email string) (bool, error) {
+	query := psql.Select(
+		sm.Columns(psql.Raw("EXISTS (?)", psql.Select(
+			sm.Columns(psql.Raw("1")),
+			sm.From(r.table),
+			sm.Where(psql.Quote("email").EQ(psql.Arg(email))),
+		))),
+	)
+
+	taken, err := bob.One(ctx, r.pool.Reader(ctx), query, scan.SingleColumnMapper[bool])
+	if err != nil {
+		return false, wrapProfileError("pg.EmailTaken", err)
+	}
+	return taken, nil
+}
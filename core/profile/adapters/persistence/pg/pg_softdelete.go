@@ -0,0 +1,199 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"app/modules/db"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+	"golang.org/x/time/rate"
+)
+
+// DefaultSoftDeletePurgeBatch bounds the profiles one purge statement deletes.
+const DefaultSoftDeletePurgeBatch = 1000
+
+type (
+	// SoftDeletePurger hard-deletes profiles soft-deleted longer than a retention window.
+	//
+	// It walks the soft-deleted rows in (created_at, id) order and, with
+	// WithPurgeCheckpoint, saves the last deleted key after every batch, so a run that
+	// crashed or ran out of its lock resumes there instead of rescanning the table.
+	// Deletes are replicated, so WithPurgeRate paces them to keep replica lag bounded.
+	// The audit trigger records every deleted profile; schedule Purge through a
+	// locking.Scheduler, like AuditPurger.
+	SoftDeletePurger struct {
+		pool      db.ConnectionManager
+		table     string
+		retention time.Duration
+
+		checkpoints   db.KV
+		checkpointKey string
+		limiter       *rate.Limiter
+	}
+
+	// SoftDeletePurgerOption configures a SoftDeletePurger.
+	SoftDeletePurgerOption func(*SoftDeletePurger)
+
+	// purgeCheckpoint is the key of the last profile a purge deleted.
+	purgeCheckpoint struct {
+		CreatedAt time.Time `json:"created_at" db:"created_at"`
+		ID        uuid.UUID `json:"id" db:"id"`
+	}
+)
+
+// WithPurgeCheckpoint stores the progress of Purge under key in kv (usually a
+// RedisKV). Losing the checkpoint only makes the next run start over.
+func WithPurgeCheckpoint(kv db.KV, key string) SoftDeletePurgerOption {
+	return func(p *SoftDeletePurger) {
+		if kv != nil && key != "" {
+			p.checkpoints = kv
+			p.checkpointKey = key
+		}
+	}
+}
+
+// WithPurgeRate caps the profiles Purge deletes per second. Zero deletes as fast as
+// the primary allows.
+func WithPurgeRate(perSecond int) SoftDeletePurgerOption {
+	return func(p *SoftDeletePurger) {
+		if perSecond > 0 {
+			p.limiter = rate.NewLimiter(rate.Limit(perSecond), max(perSecond, DefaultSoftDeletePurgeBatch))
+		}
+	}
+}
+
+func NewSoftDeletePurger(pool db.ConnectionManager, table string, retention time.Duration, opts ...SoftDeletePurgerOption) *SoftDeletePurger {
+	if table == "" {
+		table = "profiles"
+	}
+	p := &SoftDeletePurger{pool: pool, table: table, retention: retention}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// Purge deletes the profiles soft-deleted before the retention window, in batches of
+// DefaultSoftDeletePurgeBatch, and reports how many were removed. A pass that reaches
+// the end of the table clears the checkpoint.
+func (p *SoftDeletePurger) Purge(ctx context.Context) (int64, error) {
+	if p.retention <= 0 {
+		return 0, nil
+	}
+	start := time.Now()
+	cutoff := start.Add(-p.retention)
+	raw := fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE id IN (
+			SELECT id FROM %[1]s
+			WHERE deleted_at < $1 AND (created_at, id) > ($2, $3)
+			ORDER BY created_at, id
+			LIMIT $4
+		) AND deleted_at < $1
+		RETURNING created_at, id
+	`, p.table)
+
+	from := p.loadCheckpoint(ctx)
+	var total int64
+	for ctx.Err() == nil {
+		if p.limiter != nil {
+			if err := p.limiter.WaitN(ctx, DefaultSoftDeletePurgeBatch); err != nil {
+				break
+			}
+		}
+		keys, err := bob.All(ctx, p.pool.Writer(), psql.RawQuery(raw, cutoff, from.CreatedAt, from.ID, DefaultSoftDeletePurgeBatch), scan.StructMapper[purgeCheckpoint]())
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return total, wrapProfileError("pg.PurgeSoftDeleted", err)
+		}
+		total += int64(len(keys))
+		if len(keys) < DefaultSoftDeletePurgeBatch {
+			p.saveCheckpoint(ctx, nil)
+			break
+		}
+		// RETURNING does not keep the ORDER BY of the subquery
+		from = slices.MaxFunc(keys, comparePurgeKeys)
+		p.saveCheckpoint(ctx, &from)
+	}
+	slog.DebugContext(ctx, "soft-deleted profiles purged",
+		slog.String("table", p.table),
+		slog.Int64("deleted", total),
+		slog.Time("resume_after", from.CreatedAt),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return total, nil
+}
+
+// comparePurgeKeys orders keys like (created_at, id) does in Postgres, which
+// compares uuids bytewise.
+func comparePurgeKeys(a, b purgeCheckpoint) int {
+	if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+		return c
+	}
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// loadCheckpoint returns the key to resume after; a missing or unreadable
+// checkpoint starts from the beginning.
+func (p *SoftDeletePurger) loadCheckpoint(ctx context.Context) purgeCheckpoint {
+	var c purgeCheckpoint
+	if p.checkpoints == nil {
+		return c
+	}
+	v, err := p.checkpoints.AtomicGet(ctx, p.checkpointKey)
+	if err != nil {
+		slog.WarnContext(ctx, "purge checkpoint unavailable, starting over", slog.String("key", p.checkpointKey), slog.Any("error", err))
+		return c
+	}
+	b, _ := v.([]byte)
+	if len(b) == 0 {
+		return c
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		slog.WarnContext(ctx, "purge checkpoint unreadable, starting over", slog.String("key", p.checkpointKey), slog.Any("error", err))
+		return purgeCheckpoint{}
+	}
+	return c
+}
+
+// saveCheckpoint records c, or clears the checkpoint when c is nil. Failures only
+// cost a rescan, so they are logged.
+func (p *SoftDeletePurger) saveCheckpoint(ctx context.Context, c *purgeCheckpoint) {
+	if p.checkpoints == nil {
+		return
+	}
+	var v any = ""
+	if c != nil {
+		v = c
+	}
+	if _, err := p.checkpoints.AtomicSet(context.WithoutCancel(ctx), p.checkpointKey, v); err != nil {
+		slog.WarnContext(ctx, "purge checkpoint not saved", slog.String("key", p.checkpointKey), slog.Any("error", err))
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app/core/profile/domain"
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/scan"
+)
+
+type dailySignupsRow struct {
+	Day   time.Time `db:"day"`
+	Count int       `db:"count"`
+}
+
+func (r *PostgresProfileReader) CountProfiles(ctx context.Context, filter domain.ProfileFilter) (int, error) {
+	mods := append([]bob.Mod[*dialect.SelectQuery]{sm.Columns("COUNT(*)"), sm.From(r.table)}, filterMods(filter)...)
+	query := psql.Select(mods...)
+
+	count, err := bob.One(ctx, r.pool.Reader(ctx), query, scan.SingleColumnMapper[int])
+	if err != nil {
+		slog.ErrorContext(ctx, "CountProfiles query error", slog.Any("err", err))
+		return 0, wrapProfileError("pg.CountProfiles", err)
+	}
+	return count, nil
+}
+
+func (r *PostgresProfileReader) ProfileStats(ctx context.Context) (*domain.ProfileStats, error) {
+	buckets := append(domain.AgeBuckets[:len(domain.AgeBuckets):len(domain.AgeBuckets)], domain.UnknownAgeBucket)
+	since := time.Now().UTC().Add(-domain.SignupsWindow).Truncate(24 * time.Hour)
+
+	var bucketQuery, signupsQuery bob.Query
+	if r.statsView != "" {
+		bucketQuery, signupsQuery = r.viewStatsQueries(buckets, since)
+	} else {
+		bucketQuery, signupsQuery = r.liveStatsQueries(buckets, since)
+	}
+
+	var (
+		counts  []int
+		signups []dailySignupsRow
+	)
+	// both aggregates from one snapshot, so the total matches the buckets
+	err := r.pool.WithReadOnlyTx(ctx, func(ctx context.Context, q db.Querier) error {
+		var err error
+		counts, err = bob.One(ctx, q, bucketQuery, scan.SliceMapper[int])
+		if err != nil {
+			slog.ErrorContext(ctx, "ProfileStats buckets query error", slog.Any("err", err))
+			return err
+		}
+
+		signups, err = bob.All(ctx, q, signupsQuery, scan.StructMapper[dailySignupsRow]())
+		if err != nil {
+			slog.ErrorContext(ctx, "ProfileStats signups query error", slog.Any("err", err))
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, wrapProfileError("pg.ProfileStats", err)
+	}
+	if len(counts) != len(buckets)+1 {
+		return nil, wrapProfileError("pg.ProfileStats", fmt.Errorf("unexpected column count %d", len(counts)))
+	}
+
+	stats := &domain.ProfileStats{
+		Total:         counts[0],
+		ByAge:         make([]domain.AgeBucketCount, len(buckets)),
+		SignupsPerDay: make([]domain.DailySignups, len(signups)),
+	}
+	for i, b := range buckets {
+		stats.ByAge[i] = domain.AgeBucketCount{AgeBucket: b, Count: counts[i+1]}
+	}
+	for i, s := range signups {
+		stats.SignupsPerDay[i] = domain.DailySignups{
+			Day:   time.Date(s.Day.Year(), s.Day.Month(), s.Day.Day(), 0, 0, 0, 0, time.UTC),
+			Count: s.Count,
+		}
+	}
+	return stats, nil
+}
+
+// liveStatsQueries aggregate the profiles table directly: one pass computing the
+// total followed by one FILTERed count per bucket, and a GROUP BY day for signups.
+func (r *PostgresProfileReader) liveStatsQueries(buckets []domain.AgeBucket, since time.Time) (bob.Query, bob.Query) {
+	columns := make([]any, 0, len(buckets)+1)
+	columns = append(columns, "COUNT(*)")
+	for _, b := range buckets {
+		columns = append(columns, fmt.Sprintf("COUNT(*) FILTER (WHERE %s)", bucketPredicate(b, "age IS NULL")))
+	}
+	bucketQuery := psql.Select(
+		sm.Columns(columns...),
+		sm.From(r.table),
+		sm.Where(psql.Quote("deleted_at").IsNull()),
+	)
+
+	signupsQuery := psql.Select(
+		sm.Columns(
+			psql.Raw("date_trunc('day', created_at AT TIME ZONE 'UTC')").As("day"),
+			psql.Raw("COUNT(*)").As("count"),
+		),
+		sm.From(r.table),
+		sm.Where(psql.Quote("deleted_at").IsNull()),
+		sm.Where(psql.Quote("created_at").GTE(psql.Arg(since))),
+		sm.GroupBy("1"),
+		sm.OrderBy("1"),
+	)
+	return bucketQuery, signupsQuery
+}
+
+// viewStatsQueries sum the (signup_day, age) rows of the materialized view,
+// where an unknown age is stored as 0.
+func (r *PostgresProfileReader) viewStatsQueries(buckets []domain.AgeBucket, since time.Time) (bob.Query, bob.Query) {
+	columns := make([]any, 0, len(buckets)+1)
+	columns = append(columns, "COALESCE(SUM(profiles), 0)::bigint")
+	for _, b := range buckets {
+		columns = append(columns, fmt.Sprintf("COALESCE(SUM(profiles) FILTER (WHERE %s), 0)::bigint", bucketPredicate(b, "age = 0")))
+	}
+	bucketQuery := psql.Select(
+		sm.Columns(columns...),
+		sm.From(r.statsView),
+	)
+
+	signupsQuery := psql.Select(
+		sm.Columns(
+			psql.Raw("signup_day::timestamp").As("day"),
+			psql.Raw("SUM(profiles)::bigint").As("count"),
+		),
+		sm.From(r.statsView),
+		sm.Where(psql.Quote("signup_day").GTE(psql.Arg(since))),
+		sm.GroupBy("1"),
+		sm.OrderBy("1"),
+	)
+	return bucketQuery, signupsQuery
+}
+
+// bucketPredicate renders the SQL predicate of b. Bounds come from domain.AgeBuckets,
+// never from requests, so they are inlined.
+func bucketPredicate(b domain.AgeBucket, unknown string) string {
+	if b.Min == nil && b.Max == nil {
+		return unknown
+	}
+	var conds []string
+	if b.Min != nil {
+		conds = append(conds, fmt.Sprintf("age >= %d", *b.Min))
+	}
+	if b.Max != nil {
+		conds = append(conds, fmt.Sprintf("age <= %d", *b.Max))
+	}
+	return strings.Join(conds, " AND ")
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pg
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob/dialect/psql"
+)
+
+// DefaultStatsView is the materialized view created by migration V2.
+const DefaultStatsView = "profile_stats_daily"
+
+// StatsViewRefresher keeps the stats materialized view up to date.
+//
+// REFRESH ... CONCURRENTLY does not block readers of the view, but it recomputes the
+// whole aggregate, so only one node should run it at a time: schedule Refresh
+// through a locking.Scheduler so ticks where another node holds the lock are skipped.
+type StatsViewRefresher struct {
+	pool db.ConnectionManager
+	view string
+}
+
+func NewStatsViewRefresher(pool db.ConnectionManager, view string) *StatsViewRefresher {
+	if view == "" {
+		view = DefaultStatsView
+	}
+	return &StatsViewRefresher{pool: pool, view: view}
+}
+
+// Refresh recomputes the view on the primary without blocking its readers.
+func (s *StatsViewRefresher) Refresh(ctx context.Context) error {
+	start := time.Now()
+	q := psql.RawQuery("REFRESH MATERIALIZED VIEW CONCURRENTLY ?", psql.Quote(s.view))
+	if _, err := q.Exec(ctx, s.pool.Writer()); err != nil {
+		return wrapProfileError("pg.RefreshStatsView", err)
+	}
+	slog.DebugContext(ctx, "stats view refreshed",
+		slog.String("view", s.view),
+		slog.Duration("duration", time.Since(start)),
+	)
+	return nil
+}
@@ -16,6 +16,8 @@ package pg
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -38,17 +40,38 @@ type (
 		db    *bob.DB // for prepared statements on primary
 		txm   db.TxManager
 
-		createStmt bob.QueryStmt[createProfileArgs, ProfileRow, []ProfileRow]
-		updateStmt bob.QueryStmt[updateProfileArgs, ProfileRow, []ProfileRow]
-		deleteStmt bob.QueryStmt[deleteProfileArgs, uuid.UUID, []uuid.UUID]
+		createStmt       bob.QueryStmt[createProfileArgs, ProfileRow, []ProfileRow]
+		createWithIDStmt bob.QueryStmt[createProfileWithIDArgs, ProfileRow, []ProfileRow]
+		upsertStmt       bob.QueryStmt[createProfileArgs, upsertedRow, []upsertedRow]
+		updateStmt       bob.QueryStmt[updateProfileArgs, ProfileRow, []ProfileRow]
+		deleteStmt       bob.QueryStmt[deleteProfileArgs, uuid.UUID, []uuid.UUID]
+		restoreStmt      bob.QueryStmt[deleteProfileArgs, ProfileRow, []ProfileRow]
+
+		// marks written profiles once their write committed
+		recent *db.RecentWrites
 	}
 
+	// WriterOption configures a PostgresProfileWriter.
+	WriterOption func(*PostgresProfileWriter)
+
 	// Arg types for write operations
 	createProfileArgs struct {
 		Username string `db:"username"`
 		Email    string `db:"email"`
 	}
 
+	createProfileWithIDArgs struct {
+		ID       uuid.UUID `db:"id"`
+		Username string    `db:"username"`
+		Email    string    `db:"email"`
+	}
+
+	// upsertedRow tells an inserted row apart from an updated one.
+	upsertedRow struct {
+		ProfileRow
+		Inserted bool `db:"inserted"`
+	}
+
 	updateProfileArgs struct {
 		ID       uuid.UUID `db:"id"`
 		Username string    `db:"username"`
@@ -64,8 +87,16 @@ type (
 
 var _ bob.Executor = (*bob.DB)(nil)
 
+// WithRecentWrites marks every profile the writer touches in recent, for readers
+// configured with WithReadYourWrites.
+func WithRecentWrites(recent *db.RecentWrites) WriterOption {
+	return func(w *PostgresProfileWriter) {
+		w.recent = recent
+	}
+}
+
 // NewPostgresProfileWriter creates a new writer with prepared statements bound to the primary.
-func NewPostgresProfileWriter(ctx context.Context, pool db.ConnectionPool, table string) (*PostgresProfileWriter, error) {
+func NewPostgresProfileWriter(ctx context.Context, pool db.ConnectionPool, table string, opts ...WriterOption) (*PostgresProfileWriter, error) {
 	primary := pool.Writer().(bob.DB)
 
 	w := &PostgresProfileWriter{
@@ -73,6 +104,11 @@ func NewPostgresProfileWriter(ctx context.Context, pool db.ConnectionPool, table
 		db:    &primary,
 		txm:   pool,
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(w)
+		}
+	}
 
 	// INSERT INTO ... RETURNING ...
 	insertQuery := psql.Insert(
@@ -90,6 +126,48 @@ func NewPostgresProfileWriter(ctx context.Context, pool db.ConnectionPool, table
 	}
 	w.createStmt = createStmt
 
+	// INSERT with a client-chosen id; an existing id returns no row instead of an error,
+	// so it is told apart from an email conflict
+	insertWithIDQuery := psql.Insert(
+		im.Into(table, "id", "username", "email"),
+		im.Values(
+			bob.Named("id"),
+			bob.Named("username"),
+			bob.Named("email"),
+		),
+		im.OnConflict("id").DoNothing(),
+		im.Returning("id", "username", "email", "age", "created_at", "version_number"),
+	)
+
+	createWithIDStmt, err := bob.PrepareQuery[createProfileWithIDArgs](ctx, primary, insertWithIDQuery, scan.StructMapper[ProfileRow]())
+	if err != nil {
+		return nil, fmt.Errorf("prepare create profile with id: %w", err)
+	}
+	w.createWithIDStmt = createWithIDStmt
+
+	// INSERT ... ON CONFLICT (email) DO UPDATE, skipping soft-deleted owners.
+	// xmax is only zero on a freshly inserted tuple.
+	upsertQuery := psql.Insert(
+		im.Into(table, "username", "email"),
+		im.Values(
+			bob.Named("username"),
+			bob.Named("email"),
+		),
+		im.OnConflict("email").DoUpdate(
+			im.SetExcluded("username"),
+			im.SetCol("version_number").To(psql.Quote(table, "version_number").Plus(psql.Raw("1"))),
+			im.SetCol("updated_at").To(psql.Raw("CURRENT_TIMESTAMP")),
+			im.Where(psql.Quote(table, "deleted_at").IsNull()),
+		),
+		im.Returning("id", "username", "email", "age", "created_at", "version_number", "(xmax = 0) AS inserted"),
+	)
+
+	upsertStmt, err := bob.PrepareQuery[createProfileArgs](ctx, primary, upsertQuery, scan.StructMapper[upsertedRow]())
+	if err != nil {
+		return nil, fmt.Errorf("prepare upsert profile: %w", err)
+	}
+	w.upsertStmt = upsertStmt
+
 	// UPDATE ... SET username = :username, email = :email, version_number = version_number + 1
 	updateQuery := psql.Update(
 		um.Table(table),
@@ -125,6 +203,23 @@ func NewPostgresProfileWriter(ctx context.Context, pool db.ConnectionPool, table
 	}
 	w.deleteStmt = deleteStmt
 
+	// Restore of a soft-deleted row, same concurrency check as the delete
+	restoreQuery := psql.Update(
+		um.Table(table),
+		um.SetCol("deleted_at").To(psql.Raw("NULL")),
+		um.SetCol("version_number").To(psql.Raw("version_number + 1")),
+		um.Where(psql.Quote("id").EQ(bob.Named("id"))),
+		um.Where(psql.Quote("deleted_at").IsNotNull()),
+		um.Where(psql.Quote("version_number").EQ(bob.Named("version_number"))),
+		um.Returning("id", "username", "email", "age", "created_at", "version_number"),
+	)
+
+	restoreStmt, err := bob.PrepareQuery[deleteProfileArgs](ctx, primary, restoreQuery, scan.StructMapper[ProfileRow]())
+	if err != nil {
+		return nil, fmt.Errorf("prepare restore profile: %w", err)
+	}
+	w.restoreStmt = restoreStmt
+
 	return w, nil
 }
 
@@ -135,12 +230,31 @@ func (w *PostgresProfileWriter) CreateProfile(ctx context.Context, username, ema
 		Email:    email,
 	})
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.CreateProfile", err)
 	}
 	p := toProfile(row)
+	w.recent.Mark(p.ID.String())
 	return &p, nil
 }
 
+// CreateProfileWithID implements ProfileWriteStore (non-transactional).
+func (w *PostgresProfileWriter) CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*domain.Profile, error) {
+	p, err := createProfileWithID(ctx, w.createWithIDStmt, "pg.CreateProfileWithID", id, username, email)
+	if err == nil {
+		w.recent.Mark(p.ID.String())
+	}
+	return p, err
+}
+
+// UpsertProfile implements ProfileWriteStore (non-transactional).
+func (w *PostgresProfileWriter) UpsertProfile(ctx context.Context, email string, params *domain.UpsertProfileParams) (*domain.Profile, bool, error) {
+	p, inserted, err := upsertProfile(ctx, w.upsertStmt, "pg.UpsertProfile", email, params)
+	if err == nil {
+		w.recent.Mark(p.ID.String())
+	}
+	return p, inserted, err
+}
+
 // UpdateProfile implements ProfileWriteStore (non-transactional).
 func (w *PostgresProfileWriter) UpdateProfile(ctx context.Context, params *domain.UpdateProfileParams) (*domain.Profile, error) {
 	row, err := w.updateStmt.One(ctx, updateProfileArgs{
@@ -150,9 +264,10 @@ func (w *PostgresProfileWriter) UpdateProfile(ctx context.Context, params *domai
 		Version:  params.Version,
 	})
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.UpdateProfile", err)
 	}
 	p := toProfile(row)
+	w.recent.Mark(p.ID.String())
 	return &p, nil
 }
 
@@ -163,11 +278,26 @@ func (w *PostgresProfileWriter) DeleteProfile(ctx context.Context, id uuid.UUID,
 		Version: version,
 	})
 	if err != nil {
-		return wrapProfileError(err)
+		return wrapProfileError("pg.DeleteProfile", err)
 	}
+	w.recent.Mark(id.String())
 	return nil
 }
 
+// RestoreProfile implements ProfileWriteStore (non-transactional).
+func (w *PostgresProfileWriter) RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*domain.Profile, error) {
+	row, err := w.restoreStmt.One(ctx, deleteProfileArgs{
+		ID:      id,
+		Version: version,
+	})
+	if err != nil {
+		return nil, wrapProfileError("pg.RestoreProfile", err)
+	}
+	p := toProfile(row)
+	w.recent.Mark(p.ID.String())
+	return &p, nil
+}
+
 // ModifyProfile implements ProfileWriteStore (non-transactional).
 // This is left unprepared because the SET clause is truly dynamic.
 func (w *PostgresProfileWriter) ModifyProfile(
@@ -218,10 +348,11 @@ func (w *PostgresProfileWriter) ModifyProfile(
 
 	row, err := bob.One(ctx, w.db, query, scan.StructMapper[ProfileRow]())
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.ModifyProfile", err)
 	}
 
 	prof := toProfile(row)
+	w.recent.Mark(prof.ID.String())
 	return &prof, nil
 }
 
@@ -230,18 +361,21 @@ func (w *PostgresProfileWriter) WithTx(
 	ctx context.Context,
 	fn func(ctx context.Context, txTx domain.ProfileWriteTx) error,
 ) error {
-	return w.txm.WithTx(ctx, func(ctx context.Context, q db.Querier) error {
+	txRepo := &profileWriterTx{parent: w}
+	err := w.txm.WithTx(ctx, func(ctx context.Context, q db.Querier) error {
 		tx, ok := q.(bob.Tx)
 		if !ok {
 			return fmt.Errorf("querier is not a transaction")
 		}
 
-		txRepo := &profileWriterTx{
-			parent: w,
-			tx:     tx,
-		}
+		// the pool may re-run a transaction aborted by a conflict
+		txRepo.tx, txRepo.written = tx, txRepo.written[:0]
 		return fn(ctx, txRepo)
 	})
+	if err == nil {
+		w.recent.Mark(txRepo.written...)
+	}
+	return err
 }
 
 // WithTimeoutTx implements ProfileWriteStore transaction support with timeout.
@@ -250,24 +384,30 @@ func (w *PostgresProfileWriter) WithTimeoutTx(
 	timeout time.Duration,
 	fn func(ctx context.Context, txTx domain.ProfileWriteTx) error,
 ) error {
-	return w.txm.WithTimeoutTx(ctx, timeout, func(ctx context.Context, q db.Querier) error {
+	txRepo := &profileWriterTx{parent: w}
+	err := w.txm.WithTimeoutTx(ctx, timeout, func(ctx context.Context, q db.Querier) error {
 		tx, ok := q.(bob.Tx)
 		if !ok {
 			return fmt.Errorf("querier is not a transaction")
 		}
 
-		txRepo := &profileWriterTx{
-			parent: w,
-			tx:     tx,
-		}
+		// the pool may re-run a transaction aborted by a conflict
+		txRepo.tx, txRepo.written = tx, txRepo.written[:0]
 		return fn(ctx, txRepo)
 	})
+	if err == nil {
+		w.recent.Mark(txRepo.written...)
+	}
+	return err
 }
 
 // profileWriterTx is a transaction-scoped writer that reuses prepared statements.
 type profileWriterTx struct {
 	parent *PostgresProfileWriter
 	tx     bob.Tx
+
+	// ids of the profiles written, marked as recent writes after commit
+	written []string
 }
 
 var _ domain.ProfileWriteTx = (*profileWriterTx)(nil)
@@ -280,13 +420,30 @@ func (t *profileWriterTx) CreateProfile(ctx context.Context, username, email str
 		Email:    email,
 	})
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.tx.CreateProfile", err)
 	}
 
 	p := toProfile(row)
+	t.written = append(t.written, p.ID.String())
 	return &p, nil
 }
 
+func (t *profileWriterTx) CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*domain.Profile, error) {
+	p, err := createProfileWithID(ctx, inTxQueryStmt(ctx, t.parent.createWithIDStmt, t.tx), "pg.tx.CreateProfileWithID", id, username, email)
+	if err == nil {
+		t.written = append(t.written, p.ID.String())
+	}
+	return p, err
+}
+
+func (t *profileWriterTx) UpsertProfile(ctx context.Context, email string, params *domain.UpsertProfileParams) (*domain.Profile, bool, error) {
+	p, inserted, err := upsertProfile(ctx, inTxQueryStmt(ctx, t.parent.upsertStmt, t.tx), "pg.tx.UpsertProfile", email, params)
+	if err == nil {
+		t.written = append(t.written, p.ID.String())
+	}
+	return p, inserted, err
+}
+
 func (t *profileWriterTx) UpdateProfile(ctx context.Context, params *domain.UpdateProfileParams) (*domain.Profile, error) {
 	stmt := inTxQueryStmt(ctx, t.parent.updateStmt, t.tx)
 
@@ -297,9 +454,10 @@ func (t *profileWriterTx) UpdateProfile(ctx context.Context, params *domain.Upda
 		Version:  params.Version,
 	})
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.tx.UpdateProfile", err)
 	}
 	p := toProfile(row)
+	t.written = append(t.written, p.ID.String())
 	return &p, nil
 }
 
@@ -311,11 +469,27 @@ func (t *profileWriterTx) DeleteProfile(ctx context.Context, id uuid.UUID, versi
 		Version: version,
 	})
 	if err != nil {
-		return wrapProfileError(err)
+		return wrapProfileError("pg.tx.DeleteProfile", err)
 	}
+	t.written = append(t.written, id.String())
 	return nil
 }
 
+func (t *profileWriterTx) RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*domain.Profile, error) {
+	stmt := inTxQueryStmt(ctx, t.parent.restoreStmt, t.tx)
+
+	row, err := stmt.One(ctx, deleteProfileArgs{
+		ID:      id,
+		Version: version,
+	})
+	if err != nil {
+		return nil, wrapProfileError("pg.tx.RestoreProfile", err)
+	}
+	p := toProfile(row)
+	t.written = append(t.written, p.ID.String())
+	return &p, nil
+}
+
 func (t *profileWriterTx) ModifyProfile(
 	ctx context.Context,
 	id uuid.UUID,
@@ -364,9 +538,54 @@ func (t *profileWriterTx) ModifyProfile(
 
 	row, err := bob.One(ctx, t.tx, query, scan.StructMapper[ProfileRow]())
 	if err != nil {
-		return nil, wrapProfileError(err)
+		return nil, wrapProfileError("pg.tx.ModifyProfile", err)
 	}
 
 	prof := toProfile(row)
+	t.written = append(t.written, prof.ID.String())
 	return &prof, nil
 }
+
+func upsertProfile(
+	ctx context.Context,
+	stmt bob.QueryStmt[createProfileArgs, upsertedRow, []upsertedRow],
+	op, email string,
+	params *domain.UpsertProfileParams,
+) (*domain.Profile, bool, error) {
+	row, err := stmt.One(ctx, createProfileArgs{
+		Username: params.Name,
+		Email:    email,
+	})
+	if err != nil {
+		// the conflict WHERE filtered the row out: a deleted profile still owns the email
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, false, wrapProfileError(op, domain.ErrDuplicateProfile)
+		}
+		return nil, false, wrapProfileError(op, err)
+	}
+	p := toProfile(row.ProfileRow)
+	return &p, row.Inserted, nil
+}
+
+func createProfileWithID(
+	ctx context.Context,
+	stmt bob.QueryStmt[createProfileWithIDArgs, ProfileRow, []ProfileRow],
+	op string,
+	id uuid.UUID,
+	username, email string,
+) (*domain.Profile, error) {
+	row, err := stmt.One(ctx, createProfileWithIDArgs{
+		ID:       id,
+		Username: username,
+		Email:    email,
+	})
+	if err != nil {
+		// ON CONFLICT (id) DO NOTHING: the id is taken, possibly by a deleted profile
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, wrapProfileError(op, domain.ErrPrecondition)
+		}
+		return nil, wrapProfileError(op, err)
+	}
+	p := toProfile(row)
+	return &p, nil
+}
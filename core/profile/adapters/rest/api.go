@@ -27,9 +27,9 @@ type ProfileAPI struct {
 }
 
 // NewProfileService creates a new ProfileAPI instance with all dependencies.
-func NewProfileService(reader domain.ProfileReadStore, writer domain.ProfileWriteStore, signer domain.CursorSigner) *ProfileAPI {
+func NewProfileService(reader domain.ProfileReadStore, writer domain.ProfileWriteStore, signer domain.CursorSigner, opts ...domain.AppOption) *ProfileAPI {
 	return &ProfileAPI{
-		app: domain.NewApp(reader, writer, signer),
+		app: domain.NewApp(reader, writer, signer, opts...),
 	}
 }
 
@@ -0,0 +1,260 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	echo_api "app/modules/api/profileapi/echo"
+	api "app/modules/api/profileapi/stdlib"
+
+	"github.com/labstack/echo/v4"
+)
+
+// echoBridge serves the echo strict interface with the stdlib one, so ProfileAPI
+// is written once and can be mounted by either generated router.
+//
+// Request objects are converted field by field (the generated types are structurally
+// identical) and responses keep the stdlib visitor, which writes to echo's response.
+type echoBridge struct {
+	api api.StrictServerInterface
+}
+
+// echoResponse adapts a stdlib response visitor to every echo response interface.
+type echoResponse func(w http.ResponseWriter) error
+
+var _ echo_api.StrictServerInterface = echoBridge{}
+
+// NewEchoBridge exposes a stdlib strict handler as an echo strict handler.
+func NewEchoBridge(h api.StrictServerInterface) echo_api.StrictServerInterface {
+	return echoBridge{api: h}
+}
+
+func (b echoBridge) Healthz(ctx context.Context, _ echo_api.HealthzRequestObject) (echo_api.HealthzResponseObject, error) {
+	resp, err := b.api.Healthz(ctx, api.HealthzRequestObject{})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitHealthzResponse), nil
+}
+
+func (b echoBridge) ListProfiles(ctx context.Context, req echo_api.ListProfilesRequestObject) (echo_api.ListProfilesResponseObject, error) {
+	q := req.Params
+	resp, err := b.api.ListProfiles(ctx, api.ListProfilesRequestObject{
+		Params: api.ListProfilesParams{
+			Page:           q.Page,
+			PageSize:       q.PageSize,
+			After:          q.After,
+			Before:         q.Before,
+			Limit:          q.Limit,
+			Sort:           (*api.ListProfilesParamsSort)(q.Sort),
+			NamePrefix:     q.NamePrefix,
+			MinAge:         q.MinAge,
+			MaxAge:         q.MaxAge,
+			CreatedFrom:    q.CreatedFrom,
+			CreatedTo:      q.CreatedTo,
+			EmailDomain:    q.EmailDomain,
+			IncludeDeleted: q.IncludeDeleted,
+			IfNoneMatch:    q.IfNoneMatch,
+		},
+	})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitListProfilesResponse), nil
+}
+
+func (b echoBridge) CreateProfile(ctx context.Context, req echo_api.CreateProfileRequestObject) (echo_api.CreateProfileResponseObject, error) {
+	resp, err := b.api.CreateProfile(ctx, api.CreateProfileRequestObject{Body: (*api.CreateProfileJSONRequestBody)(req.Body)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitCreateProfileResponse), nil
+}
+
+func (b echoBridge) UpsertProfile(ctx context.Context, req echo_api.UpsertProfileRequestObject) (echo_api.UpsertProfileResponseObject, error) {
+	resp, err := b.api.UpsertProfile(ctx, api.UpsertProfileRequestObject{
+		Params: api.UpsertProfileParams(req.Params),
+		Body:   (*api.UpsertProfileJSONRequestBody)(req.Body),
+	})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitUpsertProfileResponse), nil
+}
+
+func (b echoBridge) CountProfiles(ctx context.Context, req echo_api.CountProfilesRequestObject) (echo_api.CountProfilesResponseObject, error) {
+	resp, err := b.api.CountProfiles(ctx, api.CountProfilesRequestObject{Params: api.CountProfilesParams(req.Params)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitCountProfilesResponse), nil
+}
+
+func (b echoBridge) ExportProfiles(ctx context.Context, _ echo_api.ExportProfilesRequestObject) (echo_api.ExportProfilesResponseObject, error) {
+	resp, err := b.api.ExportProfiles(ctx, api.ExportProfilesRequestObject{})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitExportProfilesResponse), nil
+}
+
+func (b echoBridge) GetProfileStats(ctx context.Context, _ echo_api.GetProfileStatsRequestObject) (echo_api.GetProfileStatsResponseObject, error) {
+	resp, err := b.api.GetProfileStats(ctx, api.GetProfileStatsRequestObject{})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitGetProfileStatsResponse), nil
+}
+
+func (b echoBridge) DeleteProfile(ctx context.Context, req echo_api.DeleteProfileRequestObject) (echo_api.DeleteProfileResponseObject, error) {
+	resp, err := b.api.DeleteProfile(ctx, api.DeleteProfileRequestObject{Id: req.Id, Params: api.DeleteProfileParams(req.Params)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitDeleteProfileResponse), nil
+}
+
+func (b echoBridge) RestoreProfile(ctx context.Context, req echo_api.RestoreProfileRequestObject) (echo_api.RestoreProfileResponseObject, error) {
+	resp, err := b.api.RestoreProfile(ctx, api.RestoreProfileRequestObject{Id: req.Id, Params: api.RestoreProfileParams(req.Params)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitRestoreProfileResponse), nil
+}
+
+func (b echoBridge) GetProfileById(ctx context.Context, req echo_api.GetProfileByIdRequestObject) (echo_api.GetProfileByIdResponseObject, error) {
+	resp, err := b.api.GetProfileById(ctx, api.GetProfileByIdRequestObject{Id: req.Id, Params: api.GetProfileByIdParams(req.Params)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitGetProfileByIdResponse), nil
+}
+
+func (b echoBridge) HeadProfileById(ctx context.Context, req echo_api.HeadProfileByIdRequestObject) (echo_api.HeadProfileByIdResponseObject, error) {
+	resp, err := b.api.HeadProfileById(ctx, api.HeadProfileByIdRequestObject{Id: req.Id})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitHeadProfileByIdResponse), nil
+}
+
+func (b echoBridge) ModifyProfile(ctx context.Context, req echo_api.ModifyProfileRequestObject) (echo_api.ModifyProfileResponseObject, error) {
+	resp, err := b.api.ModifyProfile(ctx, api.ModifyProfileRequestObject{
+		Id:     req.Id,
+		Params: api.ModifyProfileParams(req.Params),
+		Body:   (*api.ModifyProfileJSONRequestBody)(req.Body),
+	})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitModifyProfileResponse), nil
+}
+
+func (b echoBridge) ModifyProfiles(ctx context.Context, req echo_api.ModifyProfilesRequestObject) (echo_api.ModifyProfilesResponseObject, error) {
+	var body *api.ModifyProfilesJSONRequestBody
+	if req.Body != nil {
+		body = &api.ModifyProfilesJSONRequestBody{Items: make([]api.ProfilePatchItem, len(req.Body.Items))}
+		for i, it := range req.Body.Items {
+			body.Items[i] = api.ProfilePatchItem{Id: it.Id, IfMatch: api.ETagValue(it.IfMatch), Patch: api.ProfilePatch(it.Patch)}
+		}
+	}
+	resp, err := b.api.ModifyProfiles(ctx, api.ModifyProfilesRequestObject{Body: body})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitModifyProfilesResponse), nil
+}
+
+func (b echoBridge) UpdateProfile(ctx context.Context, req echo_api.UpdateProfileRequestObject) (echo_api.UpdateProfileResponseObject, error) {
+	resp, err := b.api.UpdateProfile(ctx, api.UpdateProfileRequestObject{
+		Id: req.Id,
+		Params: api.UpdateProfileParams{
+			IfMatch:     (*api.IfMatch)(req.Params.IfMatch),
+			IfNoneMatch: (*api.UpdateProfileParamsIfNoneMatch)(req.Params.IfNoneMatch),
+		},
+		Body: (*api.UpdateProfileJSONRequestBody)(req.Body),
+	})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitUpdateProfileResponse), nil
+}
+
+func (b echoBridge) CheckProfileEmail(ctx context.Context, req echo_api.CheckProfileEmailRequestObject) (echo_api.CheckProfileEmailResponseObject, error) {
+	resp, err := b.api.CheckProfileEmail(ctx, api.CheckProfileEmailRequestObject{Params: api.CheckProfileEmailParams(req.Params)})
+	if err != nil || resp == nil {
+		return nil, err
+	}
+	return echoResponse(resp.VisitCheckProfileEmailResponse), nil
+}
+
+func (f echoResponse) VisitHealthzResponse(w http.ResponseWriter) error           { return f(w) }
+func (f echoResponse) VisitListProfilesResponse(w http.ResponseWriter) error      { return f(w) }
+func (f echoResponse) VisitCreateProfileResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitCountProfilesResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitGetProfileStatsResponse(w http.ResponseWriter) error   { return f(w) }
+func (f echoResponse) VisitExportProfilesResponse(w http.ResponseWriter) error    { return f(w) }
+func (f echoResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error    { return f(w) }
+func (f echoResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error    { return f(w) }
+func (f echoResponse) VisitHeadProfileByIdResponse(w http.ResponseWriter) error   { return f(w) }
+func (f echoResponse) VisitModifyProfileResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error    { return f(w) }
+func (f echoResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error     { return f(w) }
+func (f echoResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error { return f(w) }
+
+// EchoProblemBridge is the echo counterpart of ProblemBridge.
+func EchoProblemBridge() echo_api.StrictMiddlewareFunc {
+	return func(next echo_api.StrictHandlerFunc, operationID string) echo_api.StrictHandlerFunc {
+		return func(c echo.Context, request any) (resp any, err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					resp, err = nil, panicError(c.Request().Context(), operationID, rec)
+				}
+			}()
+			return next(c, request)
+		}
+	}
+}
+
+// EchoErrorHandler writes errors returned through echo as problems: binding failures
+// (echo.HTTPError) the way ProblemDetailsRequestErrorHandler does for the stdlib
+// router, everything else via ProblemDetailsResponseErrorHandler.
+func EchoErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		slog.DebugContext(c.Request().Context(), "handling error", slog.Any("error", err))
+		problem := NewErrorResponse(
+			WithTitle(http.StatusText(he.Code)),
+			WithStatus(he.Code),
+			WithDetail("invalid request parameter(s)"),
+		)
+		if msg, ok := he.Message.(string); ok && he.Code < http.StatusInternalServerError {
+			WithDetail(msg)(problem)
+		}
+		WriteProblem(c.Response(), problem)
+		return
+	}
+	ProblemDetailsResponseErrorHandler(c.Response(), c.Request(), err)
+}
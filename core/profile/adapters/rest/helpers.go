@@ -15,9 +15,7 @@
 package http
 
 import (
-	"fmt"
 	"strconv"
-	"strings"
 
 	"app/core/profile/domain"
 	api "app/modules/api/profileapi/stdlib"
@@ -38,6 +36,7 @@ func mapProfile(profiles []domain.Profile) []api.Profile {
 			Age:       serde.Ptr(strconv.Itoa(p.Age)),
 			Name:      p.Name,
 			CreatedAt: &p.CreatedAt,
+			DeletedAt: p.DeletedAt,
 			Email:     nullable.NewNullableWithValue(types.Email(p.Email)),
 		})
 	}
@@ -54,24 +53,17 @@ func buildEtagsMap(profiles []domain.Profile) map[string]string {
 	return etags
 }
 
-// computeCollectionETag creates a collection-level ETag by combining individual item ETags.
-// Format: "collection:{pagination-info}:{combined-etags}"
+// computeCollectionETag creates a collection-level ETag from the pagination info
+// and the individual item ETags, see etag.Collection.
 func computeCollectionETag(profiles []domain.Profile, paginationInfo string) string {
-	if len(profiles) == 0 {
-		return fmt.Sprintf("collection:empty:%s", paginationInfo)
-	}
-
-	// Combine all individual ETags
-	var etagBuilder strings.Builder
-	for i, p := range profiles {
-		if i > 0 {
-			etagBuilder.WriteString(",")
-		}
-		etagBuilder.WriteString(etag.ETag(&p))
+	items := make([]etag.ETaggable, len(profiles))
+	for i := range profiles {
+		items[i] = &profiles[i]
 	}
+	return etag.Collection(paginationInfo, items...)
+}
 
-	// Create a simple hash representation
-	// In production, you might want to use a proper hash function
-	combinedEtags := etagBuilder.String()
-	return fmt.Sprintf("collection:%s:%s", paginationInfo, combinedEtags)
+// notModified reports whether an optional If-None-Match header holds tag.
+func notModified(ifNoneMatch *string, tag string) bool {
+	return ifNoneMatch != nil && etag.NoneMatch(*ifNoneMatch, tag)
 }
@@ -21,6 +21,7 @@ import (
 	"net/http"
 
 	"app/modules/middleware"
+	authmw "app/modules/middleware/auth"
 	"app/modules/middleware/problem"
 )
 
@@ -33,12 +34,25 @@ func RecoverHTTPMiddleware() func(http.Handler) http.Handler {
 }
 
 // ProfileHTTPValidationMiddleware returns an OpenAPI validation middleware for the Profile API.
-func ProfileHTTPValidationMiddleware(sysFS fs.FS, specPath string) func(http.Handler) http.Handler {
+// Failed security requirements are answered with 401 or 403 problems, see auth.DenialOf.
+func ProfileHTTPValidationMiddleware(sysFS fs.FS, specPath string, opts ...middleware.ValidationOption) func(http.Handler) http.Handler {
 	return middleware.OpenAPIValidation(
 		sysFS,
 		specPath,
 		// Validation error handler
 		func(ctx context.Context, err error, w http.ResponseWriter, r *http.Request, statusCode int) {
+			if d, ok := authmw.DenialOf(err); ok {
+				w.Header().Set("WWW-Authenticate", d.Challenge)
+				WriteProblem(w, NewErrorResponse(
+					WithTitle(http.StatusText(d.Status)),
+					WithStatus(d.Status),
+					WithDetail(d.Detail),
+					WithCode(d.Code),
+					WithRequest(r),
+				))
+				return
+			}
+
 			problem := NewErrorResponse(
 				WithTitle(http.StatusText(statusCode)),
 				WithStatus(statusCode),
@@ -58,5 +72,6 @@ func ProfileHTTPValidationMiddleware(sysFS fs.FS, specPath string) func(http.Han
 			slog.Debug("validation error", slog.Any("error", err))
 			WriteProblem(w, InternalProblem("server error"))
 		},
+		opts...,
 	)
 }
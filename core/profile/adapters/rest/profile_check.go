@@ -0,0 +1,44 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+)
+
+// CheckProfileEmail tells client forms whether an email is still free.
+// Returns 200 with the availability; the answer is never cached.
+func (p *ProfileAPI) CheckProfileEmail(ctx context.Context, request api.CheckProfileEmailRequestObject) (api.CheckProfileEmailResponseObject, error) {
+	available, err := p.app.CheckEmailAvailable(ctx, string(request.Params.Email))
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidData) {
+			prob := BadRequestProblem("invalid email")
+			WithInvalidParam("email", "must be an email address")(prob)
+			return api.CheckProfileEmail400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+		}
+		return nil, err
+	}
+
+	return api.CheckProfileEmail200JSONResponse{
+		Body: api.SuccessEmailAvailability{Data: api.EmailAvailability{Available: available}},
+		Headers: api.CheckProfileEmail200ResponseHeaders{
+			CacheControl: "no-store",
+		},
+	}, nil
+}
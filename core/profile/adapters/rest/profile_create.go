@@ -35,11 +35,8 @@ func (p *ProfileAPI) CreateProfile(ctx context.Context, request api.CreateProfil
 			WithInvalidParam("name", "invalid value")(prob)
 			return api.CreateProfile422ApplicationProblemPlusJSONResponse(*prob), nil
 		}
-		status := 500
-		if errors.Is(err, domain.ErrDuplicateProfile) {
-			status = 409
-		}
-		return api.CreateProfiledefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: status}, nil
+		// duplicates map to 409, infrastructure failures to 5xx (see ProblemDetailsResponseErrorHandler)
+		return nil, err
 	}
 
 	resp := api.SuccessProfile{
@@ -60,6 +60,15 @@ func (p *ProfileAPI) DeleteProfile(ctx context.Context, request api.DeleteProfil
 		return api.DeleteProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 	}
 
+	if !etag.Matches(ifMatch, &domain.ProfileVersion{ID: uid, Version: version}) {
+		// a tag of another profile
+		prob := PreconditionProblem("etag mismatch")
+		return api.DeleteProfiledefaultApplicationProblemPlusJSONResponse{
+			Body:       *prob,
+			StatusCode: http.StatusPreconditionFailed,
+		}, nil
+	}
+
 	if err := p.app.DeleteProfile(ctx, uid, version); err != nil {
 		prob := ProblemFromDomainError(err)
 		switch {
@@ -75,7 +84,7 @@ func (p *ProfileAPI) DeleteProfile(ctx context.Context, request api.DeleteProfil
 		case errors.Is(err, domain.ErrProfileNotFound):
 			return api.DeleteProfile404ApplicationProblemPlusJSONResponse(*prob), nil
 		default:
-			return api.DeleteProfiledefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: 500}, nil
+			return nil, err
 		}
 	}
 	return api.DeleteProfile204Response{}, nil
@@ -0,0 +1,118 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/apperr"
+	"app/modules/stream"
+)
+
+const (
+	// exportBatchSize is the number of profiles fetched per round trip and flushed per write.
+	exportBatchSize = 500
+	// exportWriteTimeout bounds the write of one batch. A client that stops reading
+	// for longer fails the export, which cancels its query.
+	exportWriteTimeout = 30 * time.Second
+)
+
+// exportResponse streams the export from the response visitor, the only place the
+// strict server hands out the ResponseWriter.
+type exportResponse struct {
+	ctx context.Context
+	app *domain.Application
+}
+
+var _ api.ExportProfilesResponseObject = exportResponse{}
+
+// ExportProfiles streams every live profile as newline-delimited JSON.
+// Nothing is read before the response is visited, see exportResponse.
+func (p *ProfileAPI) ExportProfiles(ctx context.Context, _ api.ExportProfilesRequestObject) (api.ExportProfilesResponseObject, error) {
+	return exportResponse{ctx: ctx, app: p.app}, nil
+}
+
+// VisitExportProfilesResponse pipes the store cursor to the encoder: at most a few
+// batches are in flight, so a slow client slows the cursor down instead of growing memory.
+//
+// The status line only goes out with the first batch, so failing to start the export
+// still gets a regular problem response. Later failures can only end the stream with
+// an error line; a client gone away or no longer reading just ends it.
+func (e exportResponse) VisitExportProfilesResponse(w http.ResponseWriter) error {
+	rc := http.NewResponseController(w)
+	enc := json.NewEncoder(w)
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	var writeErr error
+	err := stream.Pipe(e.ctx,
+		func(ctx context.Context, emit func([]domain.Profile) error) error {
+			return e.app.ExportProfiles(ctx, exportBatchSize, emit)
+		},
+		func(ctx context.Context, batch []domain.Profile) error {
+			// the server WriteTimeout is sized for regular responses, extend it batch by batch
+			_ = rc.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+			if !started {
+				start()
+			}
+			for _, prof := range mapProfile(batch) {
+				if writeErr = enc.Encode(prof); writeErr != nil {
+					return writeErr
+				}
+			}
+			if writeErr = rc.Flush(); errors.Is(writeErr, http.ErrNotSupported) {
+				writeErr = nil
+			}
+			return writeErr
+		},
+		stream.WithStallTimeout(exportWriteTimeout),
+	)
+	switch {
+	case err == nil:
+		if !started {
+			start()
+		}
+		return nil
+	case !started:
+		return err
+	case writeErr != nil || errors.Is(err, stream.ErrStalled) || e.ctx.Err() != nil:
+		slog.DebugContext(e.ctx, "profile export aborted by the client", slog.Any("error", err))
+		return nil
+	}
+
+	prob := ProblemFromDomainError(err)
+	slog.ErrorContext(e.ctx, "profile export failed",
+		slog.Any("error", err),
+		slog.Int("status", prob.Status),
+		slog.String("code", string(apperr.CodeOf(err))),
+	)
+	_ = enc.Encode(struct {
+		Error *ErrorResponse `json:"error"`
+	}{Error: prob})
+	_ = rc.Flush()
+	return nil
+}
@@ -25,8 +25,9 @@ import (
 	"github.com/gofrs/uuid/v5"
 )
 
-// GetProfileById retrieves a single profile by its UUID.
-// Returns 200 with ETag header on success, 404 if not found.
+// GetProfileById retrieves a single profile by its UUID, or its past state with asOf.
+// Returns 200 with ETag header on success, 304 when If-None-Match holds that ETag,
+// 404 if not found.
 func (p *ProfileAPI) GetProfileById(ctx context.Context, request api.GetProfileByIdRequestObject) (api.GetProfileByIdResponseObject, error) {
 	uid, err := uuid.FromBytes(request.Id[:])
 	if err != nil {
@@ -34,24 +35,37 @@ func (p *ProfileAPI) GetProfileById(ctx context.Context, request api.GetProfileB
 		WithInvalidParam("id", "invalid value")(prob)
 		return api.GetProfileById400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 	}
-	prof, err := p.app.GetProfileByID(ctx, uid)
+	var prof *domain.Profile
+	if request.Params.AsOf != nil {
+		prof, err = p.app.GetProfileAsOf(ctx, uid, *request.Params.AsOf)
+	} else {
+		prof, err = p.app.GetProfileByID(ctx, uid)
+	}
 	if err != nil {
 		prob := ProblemFromDomainError(err)
 		switch {
+		case errors.Is(err, domain.ErrOutsideRetention):
+			prob = BadRequestProblem("asOf outside the audit retention window")
+			WithInvalidParam("asOf", "must not be in the future nor older than the audit retention window")(prob)
+			return api.GetProfileById400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 		case errors.Is(err, domain.ErrInvalidData):
 			WithInvalidParam("id", "invalid value")(prob)
 			return api.GetProfileById400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 		case errors.Is(err, domain.ErrProfileNotFound):
 			return api.GetProfileById404ApplicationProblemPlusJSONResponse(*prob), nil
 		default:
-			return api.GetProfileByIddefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: 500}, nil
+			return nil, err
 		}
 	}
+	tag := etag.ETag(prof)
+	if notModified(request.Params.IfNoneMatch, tag) {
+		return api.GetProfileById304Response{Headers: api.GetProfileById304ResponseHeaders{ETag: tag}}, nil
+	}
 	resp := api.SuccessProfile{Data: mapProfile([]domain.Profile{*prof})[0]}
 	return api.GetProfileById200JSONResponse{
 		Body: resp,
 		Headers: api.GetProfileById200ResponseHeaders{
-			ETag: etag.ETag(prof),
+			ETag: tag,
 		},
 	}, nil
 }
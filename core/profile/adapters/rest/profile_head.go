@@ -0,0 +1,52 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/etag"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// HeadProfileById checks whether a profile exists.
+// Returns 200 with ETag header only, 404 if not found.
+func (p *ProfileAPI) HeadProfileById(ctx context.Context, request api.HeadProfileByIdRequestObject) (api.HeadProfileByIdResponseObject, error) {
+	uid, err := uuid.FromBytes(request.Id[:])
+	if err != nil {
+		return api.HeadProfileById400Response{}, nil
+	}
+	ver, err := p.app.ProfileExists(ctx, uid)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidData):
+			return api.HeadProfileById400Response{}, nil
+		case errors.Is(err, domain.ErrProfileNotFound):
+			return api.HeadProfileById404Response{}, nil
+		default:
+			// HEAD responses carry no body; the response error handler still sets the status
+			return nil, err
+		}
+	}
+	return api.HeadProfileById200Response{
+		Headers: api.HeadProfileById200ResponseHeaders{
+			ETag: etag.ETag(ver),
+		},
+	}, nil
+}
@@ -16,183 +16,284 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"app/core/profile/domain"
 	api "app/modules/api/profileapi/stdlib"
 	"app/modules/api/serde"
+	"app/modules/authz"
+	"app/modules/pagination"
+	"app/modules/preferences"
 )
 
-// ListProfiles retrieves a paginated list of profiles.
+// AdminScope lets a caller list soft-deleted profiles with includeDeleted.
+// Restoring them is guarded by the spec, which requires it as well.
+const AdminScope = "profiles:admin"
+
+// ListProfiles retrieves a paginated, optionally filtered and sorted list of profiles.
 // Supports both offset-based (page/pageSize) and cursor-based (after/before/limit) pagination.
-// Returns collection ETag in header and per-item ETags in metadata.
+// Returns collection ETag in header and per-item ETags in metadata, or 304 when
+// If-None-Match holds the collection ETag of the page. Page sizes
+// above the application maximum are clamped to it, as meta.clamped tells.
+// A page size or sort the request omits falls back to the caller's saved preferences.
 func (p *ProfileAPI) ListProfiles(ctx context.Context, request api.ListProfilesRequestObject) (api.ListProfilesResponseObject, error) {
-	// Determine which pagination mode is requested and ensure completeness.
-	offsetProvided := request.Params.Page != nil || request.Params.PageSize != nil
-	cursorProvided := request.Params.After != nil || request.Params.Before != nil || request.Params.Limit != nil
-	offsetComplete := request.Params.Page != nil && request.Params.PageSize != nil
-	// cursor mode requires limit and at most one of after or before
-	hasAfter := request.Params.After != nil
-	hasBefore := request.Params.Before != nil
-	limitProvided := request.Params.Limit != nil
-	cursorComplete := limitProvided && (!hasAfter || !hasBefore)
-
-	slog.DebugContext(ctx,
-		"pagination params",
-		slog.Any("offsetProvided", offsetProvided),
-		slog.Any("cursorProvided", cursorProvided),
-		slog.Any("offsetComplete", offsetComplete),
-		slog.Any("cursorComplete", cursorComplete),
-	)
-
-	// Invalid when: both complete, neither provided, or any incomplete set.
-	if (offsetComplete && cursorComplete) || (!offsetProvided && !cursorProvided) || (offsetProvided && !offsetComplete) || (cursorProvided && !cursorComplete) {
-		prob := BadRequestProblem("Provide either page+pageSize or cursor+limit (complete pair)")
+	applyPreferences(preferences.From(ctx), &request.Params)
+	params, err := pagination.ParseParams(pagination.Query{
+		Page:     request.Params.Page,
+		PageSize: request.Params.PageSize,
+		Limit:    request.Params.Limit,
+		After:    request.Params.After,
+		Before:   request.Params.Before,
+	})
+	if err != nil {
+		prob := BadRequestProblem(pagination.Detail, WithCode("invalid_pagination"))
+		var perr *pagination.Error
+		if errors.As(err, &perr) {
+			for _, ip := range perr.InvalidParams {
+				WithInvalidParam(ip.Name, ip.Reason)(prob)
+			}
+		}
 		return api.ListProfiles400ApplicationProblemPlusJSONResponse{
 			ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob),
 		}, nil
 	}
 
-	// --- offset based ---
-	if offsetComplete {
-		limit := *request.Params.PageSize
-		page := *request.Params.Page
-		slog.DebugContext(ctx, "using offset pagination", slog.Any("page", page), slog.Any("pageSize", limit))
-
-		profiles, count, err := p.app.GetProfilesByOffset(ctx, page, limit)
-		if err != nil {
-			return api.ListProfilesdefaultApplicationProblemPlusJSONResponse{
-				Body:       *InternalProblem("query failed"),
-				StatusCode: 500,
-			}, nil
+	q, prob := profileQuery(request.Params)
+	if prob != nil {
+		return api.ListProfiles400ApplicationProblemPlusJSONResponse{
+			ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob),
+		}, nil
+	}
+	if q.Filter.IncludeDeleted {
+		// checked here rather than by the spec, which cannot require a scope per parameter
+		if err := authz.Require(ctx, AdminScope); err != nil {
+			prob := ProblemFromDomainError(err)
+			if prob.Status == http.StatusUnauthorized {
+				return api.ListProfiles401ApplicationProblemPlusJSONResponse(*prob), nil
+			}
+			WithDetail("includeDeleted requires the " + AdminScope + " scope")(prob)
+			return api.ListProfiles403ApplicationProblemPlusJSONResponse(*prob), nil
 		}
+	}
+
+	switch params := pagination.Clamp(params, p.app.MaxPageSize()).(type) {
+	case pagination.OffsetParams:
+		return p.listProfilesByOffset(ctx, q, params, request.Params.IfNoneMatch)
+	case pagination.CursorParams:
+		return p.listProfilesByCursor(ctx, q, params, request.Params.IfNoneMatch)
+	default:
+		return nil, fmt.Errorf("unhandled pagination params %T", params)
+	}
+}
 
-		pages := 0
-		if limit > 0 {
-			pages = (count + limit - 1) / limit
+// applyPreferences fills the page size and sort the caller saved into the
+// parameters the request omitted.
+func applyPreferences(prefs preferences.Preferences, params *api.ListProfilesParams) {
+	if n := prefs.PageSize; n > 0 && params.PageSize == nil && params.Limit == nil {
+		if params.After != nil || params.Before != nil {
+			params.Limit = &n
+		} else {
+			params.PageSize = &n
 		}
-		etagsMap := buildEtagsMap(profiles)
-		meta := api.PaginationMeta{}
-		_ = meta.FromOffsetMeta(api.OffsetMeta{
-			Page:       page,
-			PageSize:   limit,
-			TotalItems: count,
-			TotalPages: pages,
-			Etags:      &etagsMap,
-			Links: &struct {
-				Next *string `json:"next,omitempty"`
-				Prev *string `json:"prev,omitempty"`
-			}{
-				Next: serde.Ptr(""),
-				Prev: serde.Ptr(""),
-			},
-		})
-		collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("offset:p%d:ps%d", page, limit))
-		return &api.ListProfiles200JSONResponse{
-			Body: api.SuccessProfileList{
-				Data: mapProfile(profiles),
-				Meta: meta,
-			},
-			Headers: api.ListProfiles200ResponseHeaders{
-				Link: "",
-				ETag: collectionEtag,
-			},
-		}, nil
 	}
+	if prefs.Sort != "" && params.Sort == nil {
+		sort := api.ListProfilesParamsSort(prefs.Sort)
+		params.Sort = &sort
+	}
+}
+
+// profileQuery builds the filter and sort of a list request, or the problem
+// rejecting them.
+func profileQuery(params api.ListProfilesParams) (domain.ProfileQuery, *ErrorResponse) {
+	q := domain.ProfileQuery{
+		Filter: domain.ProfileFilter{
+			NamePrefix:     serde.Deref(params.NamePrefix),
+			EmailDomain:    serde.Deref(params.EmailDomain),
+			MinAge:         params.MinAge,
+			MaxAge:         params.MaxAge,
+			CreatedFrom:    params.CreatedFrom,
+			CreatedTo:      params.CreatedTo,
+			IncludeDeleted: serde.Deref(params.IncludeDeleted),
+		},
+	}
+	sort, err := domain.ParseProfileSort(string(serde.Deref(params.Sort)))
+	if err != nil {
+		prob := BadRequestProblem("invalid sort")
+		WithInvalidParam("sort", "must be one of createdAt, name or age, optionally prefixed with -")(prob)
+		return q, prob
+	}
+	q.Sort = sort
+	if err := q.Validate(); err != nil {
+		return q, BadRequestProblem("minAge must not exceed maxAge and createdFrom must precede createdTo")
+	}
+	return q, nil
+}
 
-	// --- cursor based ---
-	// Only reached when cursorComplete is true (limit provided)
-	limit := *request.Params.Limit
+// listProfilesByOffset serves page/pageSize requests.
+func (p *ProfileAPI) listProfilesByOffset(ctx context.Context, q domain.ProfileQuery, params pagination.OffsetParams, ifNoneMatch *string) (api.ListProfilesResponseObject, error) {
+	limit := params.PageSize
+	page := params.Page
+	slog.DebugContext(ctx, "using offset pagination", slog.Any("page", page), slog.Any("pageSize", limit))
+
+	profiles, count, err := p.app.GetProfilesByOffset(ctx, q, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := 0
+	if limit > 0 {
+		pages = (count + limit - 1) / limit
+	}
+	etagsMap := buildEtagsMap(profiles)
+	links := pagination.OffsetLinks(page, limit, pages).Preserve(q.Values())
+	meta := api.PaginationMeta{}
+	_ = meta.FromOffsetMeta(api.OffsetMeta{
+		Page:       page,
+		PageSize:   limit,
+		Clamped:    clampedMeta(params.Clamped),
+		TotalItems: count,
+		TotalPages: pages,
+		Etags:      &etagsMap,
+		Links:      metaLinks(links),
+	})
+	collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("offset:p%d:ps%d:%s", page, limit, q.Key()))
+	if notModified(ifNoneMatch, collectionEtag) {
+		return api.ListProfiles304Response{Headers: api.ListProfiles304ResponseHeaders{ETag: collectionEtag}}, nil
+	}
+	return &api.ListProfiles200JSONResponse{
+		Body: api.SuccessProfileList{
+			Data: mapProfile(profiles),
+			Meta: meta,
+		},
+		Headers: api.ListProfiles200ResponseHeaders{
+			Link: links.Header(),
+			ETag: collectionEtag,
+		},
+	}, nil
+}
+
+// listProfilesByCursor serves limit with optional after/before requests.
+func (p *ProfileAPI) listProfilesByCursor(ctx context.Context, q domain.ProfileQuery, params pagination.CursorParams, ifNoneMatch *string) (api.ListProfilesResponseObject, error) {
+	limit := params.Limit
 	// Initial page: no before/after
-	if !hasAfter && !hasBefore {
-		profiles, err := p.app.GetProfilesFirstPage(ctx, limit)
+	if params.Direction == pagination.First {
+		profiles, err := p.app.GetProfilesFirstPage(ctx, q, limit)
 		if err != nil {
-			return api.ListProfilesdefaultApplicationProblemPlusJSONResponse{
-				Body:       *InternalProblem("query failed"),
-				StatusCode: 500,
-			}, nil
+			return nil, err
 		}
 		var nextStr, prevStr *string
-		if len(profiles) > 0 {
+		// a short page is the last one, so clients following next stop without an empty round trip
+		if len(profiles) == limit {
 			last := profiles[len(profiles)-1]
-			// Newest first, so there is no "prev" set for initial page
-			n := p.app.MakeCursorFromProfile(last, domain.DESC, 24*time.Hour)
+			// nothing precedes the initial page, so there is no "prev" set for it
+			n := p.app.MakeCursorFromProfile(last, q, domain.DESC, 24*time.Hour)
 			nextStr = serde.Ptr(n)
 			// prev remains nil on initial page
 		}
 		etagsMap := buildEtagsMap(profiles)
+		links := pagination.CursorLinks(limit, serde.Deref(nextStr), serde.Deref(prevStr)).Preserve(q.Values())
 		meta := api.PaginationMeta{}
 		_ = meta.FromCursorMeta(api.CursorMeta{
 			Limit:      limit,
+			Clamped:    clampedMeta(params.Clamped),
 			NextCursor: nextStr,
 			PrevCursor: prevStr,
 			Etags:      &etagsMap,
+			Links:      metaLinks(links),
 		})
-		collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("cursor:first:l%d", limit))
+		collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("cursor:first:l%d:%s", limit, q.Key()))
+		if notModified(ifNoneMatch, collectionEtag) {
+			return api.ListProfiles304Response{Headers: api.ListProfiles304ResponseHeaders{ETag: collectionEtag}}, nil
+		}
 		return &api.ListProfiles200JSONResponse{
 			Body: api.SuccessProfileList{
 				Data: mapProfile(profiles),
 				Meta: meta,
 			},
 			Headers: api.ListProfiles200ResponseHeaders{
-				Link: "",
+				Link: links.Header(),
 				ETag: collectionEtag,
 			},
 		}, nil
 	}
 
-	var inCursor string
-	if hasAfter {
-		inCursor = *request.Params.After
-	} else {
-		inCursor = *request.Params.Before
-	}
+	inCursor := params.Cursor
 	slog.DebugContext(ctx, "using cursor pagination", slog.Any("limit", limit))
 
-	profiles, _, err := p.app.GetProfilesByCursor(ctx, inCursor, limit)
+	profiles, _, err := p.app.GetProfilesByCursor(ctx, q, inCursor, limit)
 	if err != nil {
-		// Treat invalid cursor as 400 with invalid param detail
+		// Treat invalid cursor as 400 with invalid param detail; this includes a
+		// cursor minted for another filter or sort
 		prob := BadRequestProblem("invalid cursor")
-		if hasAfter {
-			WithInvalidParam("after", "invalid value")(prob)
-		} else {
-			WithInvalidParam("before", "invalid value")(prob)
-		}
+		WithInvalidParam(string(params.Direction), "invalid value")(prob)
 		return api.ListProfiles400ApplicationProblemPlusJSONResponse{
 			ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob),
 		}, nil
 	}
 
-	// Build cursor meta with next/prev using page edges
+	// Build cursor meta with next/prev using page edges. A short page ends the
+	// direction it was read in, the opposite direction always continues.
 	var nextStr, prevStr *string
 	if len(profiles) > 0 {
-		first := profiles[0]
-		last := profiles[len(profiles)-1]
-		n := p.app.MakeCursorFromProfile(last, domain.DESC, 24*time.Hour)
-		pcur := p.app.MakeCursorFromProfile(first, domain.ASC, 24*time.Hour)
-		nextStr = serde.Ptr(n)
-		prevStr = serde.Ptr(pcur)
+		full := len(profiles) == limit
+		if full || params.Direction == pagination.Before {
+			last := profiles[len(profiles)-1]
+			nextStr = serde.Ptr(p.app.MakeCursorFromProfile(last, q, domain.DESC, 24*time.Hour))
+		}
+		if full || params.Direction == pagination.After {
+			first := profiles[0]
+			prevStr = serde.Ptr(p.app.MakeCursorFromProfile(first, q, domain.ASC, 24*time.Hour))
+		}
 	}
 	etagsMap := buildEtagsMap(profiles)
+	links := pagination.CursorLinks(limit, serde.Deref(nextStr), serde.Deref(prevStr)).Preserve(q.Values())
 	meta := api.PaginationMeta{}
 	_ = meta.FromCursorMeta(api.CursorMeta{
 		Limit:      limit,
+		Clamped:    clampedMeta(params.Clamped),
 		NextCursor: nextStr,
 		PrevCursor: prevStr,
 		Etags:      &etagsMap,
+		Links:      metaLinks(links),
 	})
-	direction := "after"
-	if hasBefore {
-		direction = "before"
+	collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("cursor:%s:l%d:%s", params.Direction, limit, q.Key()))
+	if notModified(ifNoneMatch, collectionEtag) {
+		return api.ListProfiles304Response{Headers: api.ListProfiles304ResponseHeaders{ETag: collectionEtag}}, nil
 	}
-	collectionEtag := computeCollectionETag(profiles, fmt.Sprintf("cursor:%s:l%d", direction, limit))
 	return &api.ListProfiles200JSONResponse{
 		Body: api.SuccessProfileList{Data: mapProfile(profiles), Meta: meta},
 		Headers: api.ListProfiles200ResponseHeaders{
-			Link: "",
+			Link: links.Header(),
 			ETag: collectionEtag,
 		},
 	}, nil
 }
+
+// clampedMeta sets meta.clamped only on clamped pages.
+func clampedMeta(clamped bool) *bool {
+	if !clamped {
+		return nil
+	}
+	return serde.Ptr(true)
+}
+
+// metaLinks mirrors the Link header into the response meta, nil without links.
+func metaLinks(l pagination.Links) *struct {
+	Next *string `json:"next,omitempty"`
+	Prev *string `json:"prev,omitempty"`
+} {
+	if l.Next == "" && l.Prev == "" {
+		return nil
+	}
+	return &struct {
+		Next *string `json:"next,omitempty"`
+		Prev *string `json:"prev,omitempty"`
+	}{
+		Next: serde.PtrOrNil(l.Next),
+		Prev: serde.PtrOrNil(l.Prev),
+	}
+}
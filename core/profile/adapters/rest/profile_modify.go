@@ -60,58 +60,23 @@ func (p *ProfileAPI) ModifyProfile(ctx context.Context, request api.ModifyProfil
 		return api.ModifyProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 	}
 
-	// Compute tri-state updates
-	nameSet, nameNull, nameVal := false, false, ""
-	ageSet, ageNull := false, false
-	var ageValInt32 int32
-	emailSet, emailVal := false, ""
-
-	if request.Body != nil {
-		// name: nullable string
-		if request.Body.Name.IsSpecified() {
-			nameSet = true
-			if request.Body.Name.IsNull() {
-				nameNull = true
-			} else {
-				v := request.Body.Name.MustGet()
-				nameVal = v
-			}
-		}
-		// age: nullable string containing integer (1..150)
-		if request.Body.Age.IsSpecified() {
-			ageSet = true
-			if request.Body.Age.IsNull() {
-				ageNull = true
-			} else {
-				v := request.Body.Age.MustGet()
-				if v == "" {
-					prob := ValidationProblem("validation failed")
-					WithInvalidParam("age", "invalid value")(prob)
-					return api.ModifyProfile422ApplicationProblemPlusJSONResponse(*prob), nil
-				}
-				n, perr := strconv.Atoi(v)
-				if perr != nil || n < 1 || n > 150 {
-					prob := ValidationProblem("validation failed")
-					WithInvalidParam("age", "invalid value")(prob)
-					return api.ModifyProfile422ApplicationProblemPlusJSONResponse(*prob), nil
-				}
-				ageValInt32 = int32(n)
-			}
-		}
-		// email: regular optional update, null not accepted
-		if request.Body.Email != nil {
-			emailSet = true
-			emailVal = string(*request.Body.Email)
-		}
+	if !etag.Matches(ifMatch, &domain.ProfileVersion{ID: uid, Version: version}) {
+		// a tag of another profile
+		prob := PreconditionProblem("etag mismatch")
+		return api.ModifyProfile412ApplicationProblemPlusJSONResponse{
+			PreconditionFailedResponseApplicationProblemPlusJSONResponse: api.PreconditionFailedResponseApplicationProblemPlusJSONResponse{
+				Body:    *prob,
+				Headers: api.PreconditionFailedResponseResponseHeaders{ETag: ifMatch},
+			},
+		}, nil
 	}
 
-	if !nameSet && !ageSet && !emailSet {
-		prob := ValidationProblem("validation failed")
-		WithInvalidParam("body", "no valid fields to update")(prob)
+	patch, prob := profilePatch(request.Body)
+	if prob != nil {
 		return api.ModifyProfile422ApplicationProblemPlusJSONResponse(*prob), nil
 	}
 
-	updated, err := p.app.ModifyProfile(ctx, uid, version, nameSet, nameNull, nameVal, ageSet, ageNull, ageValInt32, emailSet, emailVal)
+	updated, err := p.app.ModifyProfile(ctx, uid, version, patch.NameSet, patch.NameNull, patch.Name, patch.AgeSet, patch.AgeNull, patch.Age, patch.EmailSet, patch.Email)
 	if err != nil {
 		prob := ProblemFromDomainError(err)
 		switch {
@@ -136,7 +101,7 @@ func (p *ProfileAPI) ModifyProfile(ctx context.Context, request api.ModifyProfil
 		case errors.Is(err, domain.ErrProfileNotFound):
 			return api.ModifyProfile404ApplicationProblemPlusJSONResponse(*prob), nil
 		default:
-			return api.ModifyProfiledefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: 500}, nil
+			return nil, err
 		}
 	}
 	resp := api.SuccessProfile{Data: mapProfile([]domain.Profile{*updated})[0]}
@@ -147,3 +112,46 @@ func (p *ProfileAPI) ModifyProfile(ctx context.Context, request api.ModifyProfil
 		},
 	}, nil
 }
+
+// profilePatch computes the tri-state updates of body, or the problem rejecting it.
+func profilePatch(body *api.ProfilePatch) (domain.ProfilePatch, *ErrorResponse) {
+	var patch domain.ProfilePatch
+	if body != nil {
+		// name: nullable string
+		if body.Name.IsSpecified() {
+			patch.NameSet = true
+			if body.Name.IsNull() {
+				patch.NameNull = true
+			} else {
+				patch.Name = body.Name.MustGet()
+			}
+		}
+		// age: nullable string containing integer (domain.MinAge..domain.MaxAge)
+		if body.Age.IsSpecified() {
+			patch.AgeSet = true
+			if body.Age.IsNull() {
+				patch.AgeNull = true
+			} else {
+				n, err := strconv.Atoi(body.Age.MustGet())
+				if err != nil || !domain.ValidAge(n) {
+					prob := ValidationProblem("validation failed")
+					WithInvalidParam("age", "invalid value")(prob)
+					return patch, prob
+				}
+				patch.Age = int32(n)
+			}
+		}
+		// email: regular optional update, null not accepted
+		if body.Email != nil {
+			patch.EmailSet = true
+			patch.Email = string(*body.Email)
+		}
+	}
+
+	if !patch.NameSet && !patch.AgeSet && !patch.EmailSet {
+		prob := ValidationProblem("validation failed")
+		WithInvalidParam("body", "no valid fields to update")(prob)
+		return patch, prob
+	}
+	return patch, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/etag"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// ModifyProfiles patches several profiles in one all-or-nothing transaction.
+// Every item carries the ETag it was read with, like If-Match on ModifyProfile.
+// Returns 207 with one result per item, 400 if the batch itself is malformed.
+func (p *ProfileAPI) ModifyProfiles(ctx context.Context, request api.ModifyProfilesRequestObject) (api.ModifyProfilesResponseObject, error) {
+	if request.Body == nil || len(request.Body.Items) == 0 || len(request.Body.Items) > domain.MaxModifyProfilesBatch {
+		prob := BadRequestProblem(fmt.Sprintf("between 1 and %d items are required", domain.MaxModifyProfilesBatch))
+		WithInvalidParam("items", "invalid length")(prob)
+		return api.ModifyProfiles400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	}
+	items := request.Body.Items
+
+	patches := make([]domain.ProfilePatch, len(items))
+	problems := make([]*ErrorResponse, len(items))
+	rejected := false
+	for i, item := range items {
+		patches[i], problems[i] = batchPatch(item)
+		rejected = rejected || problems[i] != nil
+	}
+	// the batch is all or nothing, a malformed item fails it before it runs
+	if rejected {
+		notApplied := ProblemFromDomainError(domain.ErrNotApplied)
+		results := make([]api.ProfilePatchResult, len(items))
+		for i, item := range items {
+			prob := problems[i]
+			if prob == nil {
+				prob = notApplied
+			}
+			results[i] = problemResult(item.Id, prob)
+		}
+		return api.ModifyProfiles207JSONResponse{Data: results}, nil
+	}
+
+	outcomes, err := p.app.ModifyProfiles(ctx, patches)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidData) {
+			prob := BadRequestProblem("invalid batch")
+			return api.ModifyProfiles400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+		}
+		return nil, err
+	}
+
+	results := make([]api.ProfilePatchResult, len(items))
+	for i, o := range outcomes {
+		if o.Err != nil {
+			results[i] = problemResult(items[i].Id, ProblemFromDomainError(o.Err))
+			continue
+		}
+		data := mapProfile([]domain.Profile{*o.Profile})[0]
+		tag := api.ETagValue(etag.ETag(o.Profile))
+		results[i] = api.ProfilePatchResult{Id: items[i].Id, Status: http.StatusOK, Etag: &tag, Data: &data}
+	}
+	return api.ModifyProfiles207JSONResponse{Data: results}, nil
+}
+
+// batchPatch reads one item of a batch, or the problem rejecting it.
+func batchPatch(item api.ProfilePatchItem) (domain.ProfilePatch, *ErrorResponse) {
+	uid, err := uuid.FromBytes(item.Id[:])
+	if err != nil {
+		prob := BadRequestProblem("invalid id")
+		WithInvalidParam("id", "invalid value")(prob)
+		return domain.ProfilePatch{}, prob
+	}
+
+	versionStr, err := etag.ParseETag(string(item.IfMatch))
+	if err != nil {
+		prob := BadRequestProblem("invalid etag format")
+		WithInvalidParam("ifMatch", "invalid etag format")(prob)
+		return domain.ProfilePatch{}, prob
+	}
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil {
+		prob := BadRequestProblem("invalid etag version")
+		WithInvalidParam("ifMatch", "invalid version in etag")(prob)
+		return domain.ProfilePatch{}, prob
+	}
+	if !etag.Matches(string(item.IfMatch), &domain.ProfileVersion{ID: uid, Version: version}) {
+		// a tag of another profile
+		return domain.ProfilePatch{}, PreconditionProblem("etag mismatch")
+	}
+
+	patch, prob := profilePatch(&item.Patch)
+	if prob != nil {
+		return domain.ProfilePatch{}, prob
+	}
+	patch.ID, patch.Version = uid, version
+	return patch, nil
+}
+
+func problemResult(id api.ProfileId, prob *ErrorResponse) api.ProfilePatchResult {
+	return api.ProfilePatchResult{Id: id, Status: prob.Status, Problem: (*api.Problem)(prob)}
+}
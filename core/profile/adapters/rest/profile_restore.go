@@ -0,0 +1,99 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/etag"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// RestoreProfile undoes the soft delete of a profile.
+// Requires If-Match header with the ETag of the deleted profile.
+// Returns 200 with the new ETag on success, 412 if the profile is live or at another version.
+func (p *ProfileAPI) RestoreProfile(ctx context.Context, request api.RestoreProfileRequestObject) (api.RestoreProfileResponseObject, error) {
+	uid, err := uuid.FromBytes(request.Id[:])
+	if err != nil {
+		prob := BadRequestProblem("invalid id")
+		WithInvalidParam("id", "invalid value")(prob)
+		return api.RestoreProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	}
+
+	ifMatch := string(request.Params.IfMatch)
+	if ifMatch == "" {
+		prob := BadRequestProblem("missing if-match header")
+		WithInvalidParam("If-Match", "header is required")(prob)
+		return api.RestoreProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	}
+
+	versionStr, err := etag.ParseETag(ifMatch)
+	if err != nil {
+		prob := BadRequestProblem("invalid etag format")
+		WithInvalidParam("If-Match", "invalid etag format")(prob)
+		return api.RestoreProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	}
+
+	version, err := strconv.ParseInt(versionStr, 10, 64)
+	if err != nil {
+		prob := BadRequestProblem("invalid etag version")
+		WithInvalidParam("If-Match", "invalid version in etag")(prob)
+		return api.RestoreProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	}
+
+	if !etag.Matches(ifMatch, &domain.ProfileVersion{ID: uid, Version: version}) {
+		// a tag of another profile
+		prob := PreconditionProblem("etag mismatch")
+		return api.RestoreProfile412ApplicationProblemPlusJSONResponse{
+			PreconditionFailedResponseApplicationProblemPlusJSONResponse: api.PreconditionFailedResponseApplicationProblemPlusJSONResponse{
+				Body:    *prob,
+				Headers: api.PreconditionFailedResponseResponseHeaders{ETag: ifMatch},
+			},
+		}, nil
+	}
+
+	restored, err := p.app.RestoreProfile(ctx, uid, version)
+	if err != nil {
+		prob := ProblemFromDomainError(err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidData):
+			WithInvalidParam("id", "invalid value")(prob)
+			return api.RestoreProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+		case errors.Is(err, domain.ErrPrecondition):
+			// deleted profiles cannot be read back, so the current tag is unknown here
+			prob = PreconditionProblem("etag mismatch or profile not deleted", WithCode("profile.precondition_failed"))
+			return api.RestoreProfile412ApplicationProblemPlusJSONResponse{
+				PreconditionFailedResponseApplicationProblemPlusJSONResponse: api.PreconditionFailedResponseApplicationProblemPlusJSONResponse{
+					Body:    *prob,
+					Headers: api.PreconditionFailedResponseResponseHeaders{ETag: ifMatch},
+				},
+			}, nil
+		default:
+			return nil, err
+		}
+	}
+	resp := api.SuccessProfile{Data: mapProfile([]domain.Profile{*restored})[0]}
+	return api.RestoreProfile200JSONResponse{
+		Body: resp,
+		Headers: api.RestoreProfile200ResponseHeaders{
+			ETag: etag.ETag(restored),
+		},
+	}, nil
+}
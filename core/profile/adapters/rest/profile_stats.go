@@ -0,0 +1,89 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/api/serde"
+
+	"github.com/oapi-codegen/nullable"
+	"github.com/oapi-codegen/runtime/types"
+)
+
+// CountProfiles counts live profiles matching the optional query filters.
+func (p *ProfileAPI) CountProfiles(ctx context.Context, request api.CountProfilesRequestObject) (api.CountProfilesResponseObject, error) {
+	params := request.Params
+	filter := domain.ProfileFilter{
+		NamePrefix:  serde.Deref(params.NamePrefix),
+		MinAge:      params.MinAge,
+		MaxAge:      params.MaxAge,
+		CreatedFrom: params.CreatedFrom,
+		CreatedTo:   params.CreatedTo,
+	}
+	if params.EmailDomain != nil {
+		filter.EmailDomain = *params.EmailDomain
+	}
+
+	count, err := p.app.CountProfiles(ctx, filter)
+	if err != nil {
+		prob := ProblemFromDomainError(err)
+		if errors.Is(err, domain.ErrInvalidData) {
+			WithDetail("minAge must not exceed maxAge and createdFrom must precede createdTo")(prob)
+			return api.CountProfiles400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+		}
+		return nil, err
+	}
+
+	return api.CountProfiles200JSONResponse{Data: api.ProfileCount{Count: count}}, nil
+}
+
+// GetProfileStats returns per-age-bucket counts and daily signups for dashboards.
+func (p *ProfileAPI) GetProfileStats(ctx context.Context, request api.GetProfileStatsRequestObject) (api.GetProfileStatsResponseObject, error) {
+	stats, err := p.app.ProfileStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.GetProfileStats200JSONResponse{Data: mapProfileStats(stats)}, nil
+}
+
+func mapProfileStats(stats *domain.ProfileStats) api.ProfileStats {
+	out := api.ProfileStats{
+		Total:         stats.Total,
+		ByAge:         make([]api.AgeBucketCount, 0, len(stats.ByAge)),
+		SignupsPerDay: make([]api.DailySignups, 0, len(stats.SignupsPerDay)),
+	}
+	for _, b := range stats.ByAge {
+		bucket := api.AgeBucketCount{Bucket: b.Label, Count: b.Count}
+		if b.Min != nil {
+			bucket.MinAge = nullable.NewNullableWithValue(*b.Min)
+		}
+		if b.Max != nil {
+			bucket.MaxAge = nullable.NewNullableWithValue(*b.Max)
+		}
+		out.ByAge = append(out.ByAge, bucket)
+	}
+	for _, d := range stats.SignupsPerDay {
+		out.SignupsPerDay = append(out.SignupsPerDay, api.DailySignups{
+			Date:  types.Date{Time: d.Day},
+			Count: d.Count,
+		})
+	}
+	return out
+}
@@ -17,6 +17,8 @@ package http
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"app/core/profile/domain"
@@ -29,6 +31,9 @@ import (
 // UpdateProfile performs a full replacement of a profile (PUT semantics).
 // Requires If-Match header with current ETag for optimistic concurrency control.
 // Returns 200 with new ETag on success, 412 if version mismatch, 404 if not found.
+//
+// With If-None-Match: * instead, the profile is created at the ID, see createProfileAt;
+// without either header the request is answered 428.
 func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfileRequestObject) (api.UpdateProfileResponseObject, error) {
 	uid, err := uuid.FromBytes(request.Id[:])
 	if err != nil {
@@ -37,8 +42,26 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 		return api.UpdateProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 	}
 
+	switch {
+	case request.Params.IfMatch != nil && request.Params.IfNoneMatch != nil:
+		prob := BadRequestProblem("If-Match and If-None-Match are mutually exclusive")
+		WithInvalidParam("If-None-Match", "not allowed together with If-Match")(prob)
+		return api.UpdateProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
+	case request.Params.IfNoneMatch != nil:
+		return p.createProfileAt(ctx, uid, request)
+	case request.Params.IfMatch == nil:
+		prob := NewErrorResponse(
+			WithTitle("Precondition Required"),
+			WithStatus(http.StatusPreconditionRequired),
+			WithDetail("send If-Match to update the profile or If-None-Match: * to create it"),
+			WithCode("profile.precondition_required"),
+		)
+		return api.UpdateProfile428ApplicationProblemPlusJSONResponse(*prob), nil
+	}
+	ifMatch := *request.Params.IfMatch
+
 	// Parse version from ETag without querying database
-	versionStr, err := etag.ParseETag(string(request.Params.IfMatch))
+	versionStr, err := etag.ParseETag(ifMatch)
 	if err != nil {
 		prob := BadRequestProblem("invalid etag format")
 		WithInvalidParam("If-Match", "invalid etag format")(prob)
@@ -52,6 +75,17 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 		return api.UpdateProfile400ApplicationProblemPlusJSONResponse{ProblemResponseApplicationProblemPlusJSONResponse: api.ProblemResponseApplicationProblemPlusJSONResponse(*prob)}, nil
 	}
 
+	if !etag.Matches(ifMatch, &domain.ProfileVersion{ID: uid, Version: version}) {
+		// a tag of another profile
+		prob := PreconditionProblem("etag mismatch")
+		return api.UpdateProfile412ApplicationProblemPlusJSONResponse{
+			PreconditionFailedResponseApplicationProblemPlusJSONResponse: api.PreconditionFailedResponseApplicationProblemPlusJSONResponse{
+				Body:    *prob,
+				Headers: api.PreconditionFailedResponseResponseHeaders{ETag: ifMatch},
+			},
+		}, nil
+	}
+
 	// If email is not provided, fetch current profile to preserve existing email
 	// (PUT semantics with optional field for backwards compatibility)
 	var emailVal string
@@ -68,7 +102,7 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 			case errors.Is(err, domain.ErrProfileNotFound):
 				return api.UpdateProfile404ApplicationProblemPlusJSONResponse(*prob), nil
 			default:
-				return api.UpdateProfiledefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: 500}, nil
+				return nil, err
 			}
 		}
 		emailVal = current.Email
@@ -94,7 +128,7 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 		case errors.Is(err, domain.ErrPrecondition):
 			// On version mismatch, fetch latest to return current ETag in response
 			latest, fetchErr := p.app.GetProfileByID(ctx, uid)
-			etagVal := string(request.Params.IfMatch)
+			etagVal := ifMatch
 			if fetchErr == nil {
 				etagVal = etag.ETag(latest)
 			}
@@ -105,7 +139,7 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 				},
 			}, nil
 		default:
-			return api.UpdateProfiledefaultApplicationProblemPlusJSONResponse{Body: *prob, StatusCode: 500}, nil
+			return nil, err
 		}
 	}
 	resp := api.SuccessProfile{Data: mapProfile([]domain.Profile{*updated})[0]}
@@ -116,3 +150,48 @@ func (p *ProfileAPI) UpdateProfile(ctx context.Context, request api.UpdateProfil
 		},
 	}, nil
 }
+
+// createProfileAt creates the profile at a client-chosen ID (PUT with If-None-Match: *).
+// Returns 201 with Location and ETag, 412 with the current ETag if the ID is taken,
+// 409 if the email is, 422 when the payload cannot create a profile.
+func (p *ProfileAPI) createProfileAt(ctx context.Context, uid uuid.UUID, request api.UpdateProfileRequestObject) (api.UpdateProfileResponseObject, error) {
+	if request.Body == nil || request.Body.Email == nil {
+		prob := ValidationProblem("validation failed", WithCode("profile.invalid"))
+		WithInvalidParam("email", "required to create a profile")(prob)
+		return api.UpdateProfile422ApplicationProblemPlusJSONResponse(*prob), nil
+	}
+
+	created, err := p.app.CreateProfileWithID(ctx, uid, request.Body.Name, string(*request.Body.Email))
+	if err != nil {
+		prob := ProblemFromDomainError(err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidData):
+			WithInvalidParam("name", "invalid value")(prob)
+			return api.UpdateProfile422ApplicationProblemPlusJSONResponse(*prob), nil
+		case errors.Is(err, domain.ErrDuplicateProfile):
+			return api.UpdateProfile409ApplicationProblemPlusJSONResponse(*prob), nil
+		case errors.Is(err, domain.ErrPrecondition):
+			// a deleted profile holding the ID has no current ETag to report
+			var etagVal string
+			if existing, fetchErr := p.app.GetProfileByID(ctx, uid); fetchErr == nil {
+				etagVal = etag.ETag(existing)
+			}
+			return api.UpdateProfile412ApplicationProblemPlusJSONResponse{
+				PreconditionFailedResponseApplicationProblemPlusJSONResponse: api.PreconditionFailedResponseApplicationProblemPlusJSONResponse{
+					Body:    *prob,
+					Headers: api.PreconditionFailedResponseResponseHeaders{ETag: etagVal},
+				},
+			}, nil
+		default:
+			return nil, err
+		}
+	}
+
+	return api.UpdateProfile201JSONResponse{
+		Body: api.SuccessProfile{Data: mapProfile([]domain.Profile{*created})[0]},
+		Headers: api.UpdateProfile201ResponseHeaders{
+			ETag:     etag.ETag(created),
+			Location: fmt.Sprintf("/v1/profiles/%s", created.ID),
+		},
+	}, nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"app/core/profile/domain"
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/etag"
+)
+
+// UpsertProfile creates or replaces the profile owning the email in the query (PUT semantics
+// keyed by email). Returns 201 with Location when created, 200 when updated, both with the new ETag;
+// 409 if a deleted profile holds the email, 422 for validation errors.
+func (p *ProfileAPI) UpsertProfile(ctx context.Context, request api.UpsertProfileRequestObject) (api.UpsertProfileResponseObject, error) {
+	params := &domain.UpsertProfileParams{}
+	if request.Body != nil {
+		params.Name = request.Body.Name
+	}
+	profile, created, err := p.app.UpsertProfile(ctx, string(request.Params.Email), params)
+	if err != nil {
+		prob := ProblemFromDomainError(err)
+		switch {
+		case errors.Is(err, domain.ErrInvalidData):
+			return api.UpsertProfile422ApplicationProblemPlusJSONResponse(*prob), nil
+		case errors.Is(err, domain.ErrDuplicateProfile):
+			return api.UpsertProfile409ApplicationProblemPlusJSONResponse(*prob), nil
+		default:
+			return nil, err
+		}
+	}
+
+	resp := api.SuccessProfile{Data: mapProfile([]domain.Profile{*profile})[0]}
+	if created {
+		return api.UpsertProfile201JSONResponse{
+			Body: resp,
+			Headers: api.UpsertProfile201ResponseHeaders{
+				ETag:     etag.ETag(profile),
+				Location: fmt.Sprintf("/v1/profiles/%s", profile.ID),
+			},
+		}, nil
+	}
+	return api.UpsertProfile200JSONResponse{
+		Body:    resp,
+		Headers: api.UpsertProfile200ResponseHeaders{ETag: etag.ETag(profile)},
+	}, nil
+}
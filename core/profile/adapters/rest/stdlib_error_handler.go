@@ -15,6 +15,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,8 +26,16 @@ import (
 	"app/core/profile/domain"
 	api "app/modules/api/profileapi/stdlib"
 	"app/modules/api/serde"
+	"app/modules/apperr"
+	"app/modules/authz"
+	"app/modules/middleware/problem"
 )
 
+// StatusClientClosedRequest is the non-standard status (borrowed from nginx) used
+// when the client went away before the response was ready. Nobody reads it, but
+// access logs and metrics must not count it as a server failure.
+const StatusClientClosedRequest = 499
+
 type (
 	ErrorResponse       = api.Problem
 	ErrorResponseOption func(*ErrorResponse)
@@ -48,6 +57,21 @@ func WithHTTPCode(code int) func(*ErrorResponse) {
 	}
 }
 
+// WithCode sets the machine readable problem code, which clients and the
+// i18n catalogs key on.
+func WithCode(code string) func(*ErrorResponse) {
+	return func(er *ErrorResponse) {
+		er.Code = &code
+	}
+}
+
+// WithType sets the problem type URI.
+func WithType(uri string) func(*ErrorResponse) {
+	return func(er *ErrorResponse) {
+		er.Type = &uri
+	}
+}
+
 func WithDetail(message string) func(*ErrorResponse) {
 	return func(er *ErrorResponse) {
 		er.Detail = &message
@@ -66,6 +90,17 @@ func WithStatus(status int) func(*ErrorResponse) {
 	}
 }
 
+// WithRequest sets the trace id of the request's span, when one is active, and
+// uses the request path as instance.
+func WithRequest(r *http.Request) func(*ErrorResponse) {
+	return func(er *ErrorResponse) {
+		if id := problem.TraceID(r.Context()); id != "" {
+			er.TraceId = &id
+		}
+		er.Instance = serde.Ptr(r.URL.Path)
+	}
+}
+
 func WithInvalidParam(name, reason string) func(*ErrorResponse) {
 	return func(er *ErrorResponse) {
 		if er.InvalidParams == nil {
@@ -122,30 +157,89 @@ func InternalProblem(detail string) *ErrorResponse {
 	return NewErrorResponse(WithTitle("Internal Server Error"), WithStatus(http.StatusInternalServerError), WithDetail(detail))
 }
 
-// ProblemFromDomainError maps domain/service-layer sentinel errors to RFC7807 problems.
+func CanceledProblem() *ErrorResponse {
+	return NewErrorResponse(WithTitle("Client Closed Request"), WithStatus(StatusClientClosedRequest), WithDetail("request canceled by the client"), WithCode(string(apperr.CodeCanceled)))
+}
+
+// ProblemFromDomainError maps domain/service-layer errors to RFC7807 problems.
+//
+// Domain sentinels get their specific details; anything else is mapped from its
+// apperr code so infrastructure failures surface as 503/504 instead of a blanket 500.
 func ProblemFromDomainError(err error) *ErrorResponse {
-	slog.Debug("mapping error", slog.Any("error", err))
+	slog.Debug("mapping error",
+		slog.Any("error", err),
+		slog.String("code", string(apperr.CodeOf(err))),
+		slog.Any("ops", apperr.OpsOf(err)),
+		slog.Bool("retryable", apperr.IsRetryable(err)),
+	)
 	switch {
 	case errors.Is(err, domain.ErrDuplicateProfile):
-		return ConflictProblem("profile with this name already exists")
+		return ConflictProblem("profile with this name already exists", WithCode("profile.duplicate"))
 	case errors.Is(err, domain.ErrInvalidData):
-		return ValidationProblem("validation failed")
+		return ValidationProblem("validation failed", WithCode("profile.invalid"))
 	case errors.Is(err, domain.ErrProfileNotFound):
-		return NewErrorResponse(WithTitle("Not Found"), WithStatus(http.StatusNotFound), WithDetail("profile not found"))
+		return NewErrorResponse(WithTitle("Not Found"), WithStatus(http.StatusNotFound), WithDetail("profile not found"), WithCode("profile.not_found"))
 	case errors.Is(err, domain.ErrPrecondition):
-		return PreconditionProblem("precondition failed")
+		return PreconditionProblem("precondition failed", WithCode("profile.precondition_failed"))
+	case errors.Is(err, domain.ErrNotApplied):
+		return NewErrorResponse(WithTitle("Failed Dependency"), WithStatus(http.StatusFailedDependency), WithDetail("not applied, another item of the batch failed"), WithCode("profile.not_applied"))
+	case errors.Is(err, authz.ErrUnauthenticated):
+		return NewErrorResponse(WithTitle("Unauthorized"), WithStatus(http.StatusUnauthorized), WithDetail("authentication required"),
+			WithType(authz.ProblemTypeUnauthenticated), WithCode("unauthenticated"))
+	case errors.Is(err, authz.ErrForbidden):
+		return NewErrorResponse(WithTitle("Forbidden"), WithStatus(http.StatusForbidden), WithDetail("missing required scope"),
+			WithType(authz.ProblemTypeInsufficientScope), WithCode("insufficient_scope"))
+	}
+
+	code := apperr.CodeOf(err)
+	withCode := WithCode(string(code))
+	switch code {
+	case apperr.CodeInvalid:
+		return ValidationProblem("validation failed", withCode)
+	case apperr.CodeNotFound:
+		return NewErrorResponse(WithTitle("Not Found"), WithStatus(http.StatusNotFound), WithDetail("resource not found"), withCode)
+	case apperr.CodeConflict:
+		return ConflictProblem("conflict", withCode)
+	case apperr.CodePrecondition:
+		return PreconditionProblem("precondition failed", withCode)
+	case apperr.CodeUnavailable:
+		return NewErrorResponse(WithTitle("Service Unavailable"), WithStatus(http.StatusServiceUnavailable), WithDetail("temporarily unavailable, retry later"), withCode)
+	case apperr.CodeTimeout:
+		return NewErrorResponse(WithTitle("Gateway Timeout"), WithStatus(http.StatusGatewayTimeout), WithDetail("operation timed out"), withCode)
+	case apperr.CodeCanceled:
+		return CanceledProblem()
 	default:
 		return InternalProblem("server error")
 	}
 }
 
-// ProblemDetailsResponseErrorHandler centralizes unexpected handler errors
-// occurring after request parsing, emitting a generic 500 Problem.
+// ProblemDetailsResponseErrorHandler maps errors returned by strict handlers (and
+// panics converted by ProblemBridge) to problems via ProblemFromDomainError.
+//
+// Only the mapped problem reaches the client; the error itself is logged, at error
+// level when it ends up as a 5xx. A request whose client disconnected is answered
+// with 499 and logged at debug level, whatever error the handler surfaced for it.
 func ProblemDetailsResponseErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
-	slog.Debug("handling error", slog.Any("error", err))
-	// Generic 500 Problem for unexpected handler errors.
-	_ = err // avoid leaking internal error details to clients
-	WriteProblem(w, InternalProblem("server error"))
+	prob := ProblemFromDomainError(err)
+	if errors.Is(r.Context().Err(), context.Canceled) && prob.Status >= http.StatusInternalServerError {
+		// the store may have wrapped the cancellation into something else (e.g. a
+		// broken connection), the client is gone either way
+		prob = CanceledProblem()
+	}
+
+	level := slog.LevelDebug
+	if prob.Status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	slog.Log(r.Context(), level, "handler error",
+		slog.Any("error", err),
+		slog.Int("status", prob.Status),
+		slog.String("code", string(apperr.CodeOf(err))),
+		slog.Any("ops", apperr.OpsOf(err)),
+		slog.String("url", r.URL.Path),
+	)
+	WithRequest(r)(prob)
+	WriteProblem(w, prob)
 }
 
 func ProblemDetailsRequestErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
@@ -177,5 +271,6 @@ func ProblemDetailsRequestErrorHandler(w http.ResponseWriter, r *http.Request, e
 		}
 	}
 
+	WithRequest(r)(problem)
 	WriteProblem(w, problem)
 }
@@ -0,0 +1,56 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	api "app/modules/api/profileapi/stdlib"
+	"app/modules/apperr"
+)
+
+// ProblemBridge is a strict middleware that turns a panicking handler into an
+// internal error, so it reaches ProblemDetailsResponseErrorHandler like any other
+// returned error instead of unwinding through the router.
+//
+// Together they let handlers `return nil, err` for every case they do not need to
+// shape themselves: domain sentinels and apperr codes are mapped in one place by
+// ProblemFromDomainError.
+func ProblemBridge() api.StrictMiddlewareFunc {
+	return func(next api.StrictHandlerFunc, operationID string) api.StrictHandlerFunc {
+		return func(ctx context.Context, w http.ResponseWriter, r *http.Request, request any) (resp any, err error) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					resp, err = nil, panicError(ctx, operationID, rec)
+				}
+			}()
+			return next(ctx, w, r, request)
+		}
+	}
+}
+
+// panicError logs a recovered handler panic and turns it into an internal error.
+func panicError(ctx context.Context, operationID string, rec any) error {
+	slog.ErrorContext(ctx, "handler panic",
+		slog.String("operation", operationID),
+		slog.Any("panic", rec),
+		slog.String("stack", string(debug.Stack())),
+	)
+	return apperr.WithCode("http."+operationID, apperr.CodeInternal, fmt.Errorf("panic: %v", rec))
+}
@@ -14,11 +14,53 @@
 
 package domain
 
+import "time"
+
 // TODO: separate /application if we need extra separation on side-effects, use-cases, etc.
-func NewApp(reader ProfileReadStore, writer ProfileWriteStore, signer CursorSigner) *Application {
-	return &Application{
-		reader: reader,
-		writer: writer,
-		signer: signer,
+func NewApp(reader ProfileReadStore, writer ProfileWriteStore, signer CursorSigner, opts ...AppOption) *Application {
+	app := &Application{
+		reader:  reader,
+		writer:  writer,
+		signer:  signer,
+		metrics: noopMetrics{},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(app)
+		}
+	}
+	return app
+}
+
+// WithAuditRetention rejects GetProfileAsOf instants older than d, matching how long
+// the audit history is kept. Zero, the default, accepts any past instant.
+func WithAuditRetention(d time.Duration) AppOption {
+	return func(app *Application) {
+		if d > 0 {
+			app.auditRetention = d
+		}
+	}
+}
+
+// WithMaxPageSize caps the pages the list use cases read at n profiles, whatever
+// the caller asks for. Zero, the default, leaves them unbounded.
+func WithMaxPageSize(n int) AppOption {
+	return func(app *Application) {
+		if n > 0 {
+			app.maxPageSize = n
+		}
+	}
+}
+
+// MaxPageSize returns the cap set by WithMaxPageSize, zero when unbounded.
+func (app *Application) MaxPageSize() int {
+	return app.maxPageSize
+}
+
+// pageSize bounds a requested page size by maxPageSize.
+func (app *Application) pageSize(n int) int {
+	if app.maxPageSize > 0 {
+		return min(n, app.maxPageSize)
 	}
+	return n
 }
@@ -0,0 +1,53 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// Profile field bounds. The OpenAPI spec and the CHECK constraints of the profiles
+// table restate them; cmd/constraintcheck reports where they drift apart.
+const (
+	MinAge = 1
+	MaxAge = 150
+
+	MinNameLength = 5
+	MaxNameLength = 50
+)
+
+// ConstraintKind tells what a Constraint bounds.
+type ConstraintKind string
+
+const (
+	// RangeConstraint bounds an integer value.
+	RangeConstraint ConstraintKind = "range"
+	// LengthConstraint bounds the length of a string in characters.
+	LengthConstraint ConstraintKind = "length"
+)
+
+// Constraint is an inclusive [Min, Max] bound on a profile field.
+type Constraint struct {
+	Field    string
+	Kind     ConstraintKind
+	Min, Max int
+}
+
+// Constraints lists the bounds the domain owns, for tooling that cross-checks them.
+var Constraints = []Constraint{
+	{Field: "age", Kind: RangeConstraint, Min: MinAge, Max: MaxAge},
+	{Field: "name", Kind: LengthConstraint, Min: MinNameLength, Max: MaxNameLength},
+}
+
+// ValidAge reports whether age is within [MinAge, MaxAge].
+func ValidAge(age int) bool {
+	return age >= MinAge && age <= MaxAge
+}
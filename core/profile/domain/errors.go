@@ -14,12 +14,27 @@
 
 package domain
 
-import "errors"
+import (
+	"fmt"
 
+	"app/modules/apperr"
+)
+
+// Domain sentinels are coded apperr errors: callers may keep using errors.Is,
+// while generic layers classify them through apperr.CodeOf.
 var (
-	ErrDuplicateProfile = errors.New("profile with the requested identifiers already exists")
-	ErrInvalidData      = errors.New("invalid data provided for profile operations")
-	ErrUnhandled        = errors.New("unexpected error")
-	ErrProfileNotFound  = errors.New("profile not found")
-	ErrPrecondition     = errors.New("precondition failed")
+	ErrDuplicateProfile = apperr.New(apperr.CodeConflict, "profile with the requested identifiers already exists")
+	ErrInvalidData      = apperr.New(apperr.CodeInvalid, "invalid data provided for profile operations")
+	ErrUnhandled        = apperr.New(apperr.CodeInternal, "unexpected error")
+	ErrProfileNotFound  = apperr.New(apperr.CodeNotFound, "profile not found")
+	ErrPrecondition     = apperr.New(apperr.CodePrecondition, "precondition failed")
+	ErrOutsideRetention = apperr.New(apperr.CodeInvalid, "instant outside the audit retention window")
+	// ErrNotApplied marks the items of an all-or-nothing batch rolled back for another item's failure.
+	ErrNotApplied = apperr.New(apperr.CodeConflict, "not applied, another item of the batch failed")
 )
+
+// unhandled reports an unexpected failure of op. The cause stays in the chain, so its
+// code and retryability win over ErrUnhandled, which only acts as the fallback.
+func unhandled(op string, err error) error {
+	return apperr.Wrap(op, fmt.Errorf("%w (%w)", err, ErrUnhandled))
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "context"
+
+// How a profile came to be created, recorded with ProfileCreated.
+const (
+	CreatedByPost   = "post"
+	CreatedByPut    = "put"
+	CreatedByUpsert = "upsert"
+	CreatedByBulk   = "bulk"
+)
+
+// BusinessMetrics records business events from the use cases, so product dashboards
+// count profiles instead of deriving them from HTTP status codes.
+//
+// Events are recorded once the change is committed. Implementations must be cheap
+// and cannot fail the use case.
+type BusinessMetrics interface {
+	ProfileCreated(ctx context.Context, source string)
+	ProfileDeleted(ctx context.Context)
+}
+
+// WithBusinessMetrics records business events to m. Without it events are dropped.
+func WithBusinessMetrics(m BusinessMetrics) AppOption {
+	return func(app *Application) {
+		if m != nil {
+			app.metrics = m
+		}
+	}
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) ProfileCreated(context.Context, string) {}
+func (noopMetrics) ProfileDeleted(context.Context)         {}
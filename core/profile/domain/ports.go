@@ -38,24 +38,24 @@ import (
 // TODO: ProfileReadTx?
 type ProfileReadStore interface {
 	// GetProfilesByCursor implements cursor-based pagination using a keyset approach.
-	// The cursor contains a pivot point (created_at, id) and direction (ASC/DESC).
+	// The cursor contains a pivot row and a direction (DESC/ASC).
 	// This method is more efficient than offset-based pagination for large datasets
 	// because it uses indexed columns and doesn't require scanning skipped rows.
 	//
 	// Parameters:
-	//   - pivotCreatedAt: The created_at timestamp of the last item from previous page
-	//   - pivotID: The ID of the last item from previous page (for tie-breaking)
-	//   - dir: Direction to paginate (ASC for next page, DESC for previous page)
+	//   - q: Filter and sort of the list; the sort picks the pivot fields compared
+	//   - pivot: The edge item of the previous page (its ID breaks ties)
+	//   - dir: Direction to paginate (DESC for the next page, ASC for the previous page)
 	//   - limit: Maximum number of items to return
 	//
-	// Returns profiles ordered by (created_at DESC, id DESC) regardless of direction.
-	// The direction only affects the comparator used in the WHERE clause.
-	GetProfilesByCursor(ctx context.Context, pivotCreatedAt time.Time, pivotID uuid.UUID, dir CursorDirection, limit int) ([]Profile, error)
+	// Returns profiles in the list order of q.Sort (then id) regardless of direction:
+	// a previous page holds the limit items right before the pivot.
+	GetProfilesByCursor(ctx context.Context, q ProfileQuery, pivot CursorPivot, dir CursorDirection, limit int) ([]Profile, error)
 
 	// GetProfilesFirstPage returns the first page for cursor-based pagination.
 	// This is used when the client doesn't provide a cursor (initial page load).
-	// Results are ordered by (created_at DESC, id DESC) to match cursor pagination order.
-	GetProfilesFirstPage(ctx context.Context, limit int) ([]Profile, error)
+	// Results are in the list order of q.Sort, like cursor pages.
+	GetProfilesFirstPage(ctx context.Context, q ProfileQuery, limit int) ([]Profile, error)
 
 	// GetProfilesByOffset implements traditional offset-based pagination.
 	// Returns both the page of profiles and the total count.
@@ -64,12 +64,43 @@ type ProfileReadStore interface {
 	// the database must scan and discard all skipped rows. Use cursor-based
 	// pagination (GetProfilesByCursor) for better performance on large datasets.
 	//
+	// The total only counts profiles matching q.Filter.
+	//
 	// Returns: (profiles, totalCount, error)
-	GetProfilesByOffset(ctx context.Context, limit, offset int) ([]Profile, int, error)
+	GetProfilesByOffset(ctx context.Context, q ProfileQuery, limit, offset int) ([]Profile, int, error)
+
+	// StreamProfiles reads every live profile from a single snapshot, ordered by
+	// (created_at DESC, id DESC), and passes them to emit in batches of at most batchSize.
+	// Rows are fetched one batch at a time, so memory does not grow with the table.
+	//
+	// It stops at the first error returned by emit, which is returned as is, and
+	// cancels the running query as soon as ctx is done.
+	StreamProfiles(ctx context.Context, batchSize int, emit func([]Profile) error) error
 
 	// GetProfileByID retrieves a single profile by its unique identifier.
 	// Returns ErrProfileNotFound if the profile doesn't exist or is soft-deleted.
 	GetProfileByID(ctx context.Context, id uuid.UUID) (*Profile, error)
+
+	// GetProfileAsOf reconstructs a profile as it was at the given instant from its
+	// audit history. Changes are recorded at the time of their statement, not of their commit.
+	// Returns ErrProfileNotFound if the profile did not exist or was deleted at that instant.
+	GetProfileAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Profile, error)
+
+	// ExistsProfile is a cheap existence probe that avoids loading the row payload.
+	// It returns the current version (needed for ETags) of a live profile.
+	// Returns ErrProfileNotFound if the profile doesn't exist or is soft-deleted.
+	ExistsProfile(ctx context.Context, id uuid.UUID) (version int64, err error)
+
+	// EmailTaken reports whether any profile, including soft-deleted ones, uses email.
+	// Emails compare case-insensitively, like the unique constraint.
+	EmailTaken(ctx context.Context, email string) (bool, error)
+
+	// CountProfiles counts live profiles matching filter.
+	CountProfiles(ctx context.Context, filter ProfileFilter) (int, error)
+
+	// ProfileStats aggregates live profiles by AgeBuckets and by signup day
+	// over the last SignupsWindow. Aggregates are computed from a single snapshot.
+	ProfileStats(ctx context.Context) (*ProfileStats, error)
 }
 
 // ProfileWriteStore defines the port for write operations on profiles.
@@ -87,7 +118,7 @@ type ProfileReadStore interface {
 // a ProfileWriteTx scoped to the transaction lifetime.
 //
 // Optimistic Concurrency:
-// UpdateProfile, ModifyProfile, and DeleteProfile require a version number.
+// UpdateProfile, ModifyProfile, DeleteProfile and RestoreProfile require a version number.
 // If the version doesn't match the current database version, the operation fails
 // with ErrPrecondition, indicating another client has modified the entity.
 //
@@ -107,6 +138,32 @@ type ProfileWriteStore interface {
 	// Returns ErrDuplicateProfile if a profile with the same email already exists.
 	CreateProfile(ctx context.Context, username, email string) (*Profile, error)
 
+	// CreateProfileWithID inserts a new profile at a caller-chosen ID, for clients that
+	// need to retry creates without producing duplicates.
+	//
+	// Returns ErrPrecondition if a profile, live or soft-deleted, already has the ID,
+	// and ErrDuplicateProfile if the email is taken.
+	CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*Profile, error)
+
+	// CreateProfiles inserts many profiles with a single statement, for batch imports.
+	// Results are index-aligned with profiles. A row that conflicts, with a stored
+	// profile or an earlier row of the batch, is skipped with ErrDuplicateProfile (email)
+	// or ErrPrecondition (caller-chosen ID) while the others are inserted.
+	//
+	// Rows must satisfy the table constraints (see ValidNewProfile): a violation
+	// fails the whole statement and is returned as the error, nothing being inserted.
+	CreateProfiles(ctx context.Context, profiles []NewProfile) ([]CreateProfileResult, error)
+
+	// UpsertProfile inserts a profile for email or, when a live profile already
+	// owns it, overwrites its fields and increments its version in one statement.
+	// created is true when a new row was inserted.
+	//
+	// No version check is made; the unique email is the concurrency key.
+	//
+	// Returns ErrDuplicateProfile if the email belongs to a soft-deleted profile,
+	// which is left untouched.
+	UpsertProfile(ctx context.Context, email string, params *UpsertProfileParams) (profile *Profile, created bool, err error)
+
 	// UpdateProfile performs a full update of the profile's username and email.
 	// Uses optimistic concurrency control via the version field.
 	//
@@ -133,6 +190,16 @@ type ProfileWriteStore interface {
 	// Returns ErrPrecondition if version mismatch or ErrProfileNotFound if not found.
 	DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error
 
+	// RestoreProfile undoes a soft delete by clearing deleted_at.
+	// Uses optimistic concurrency control via the version field, like DeleteProfile.
+	//
+	// The email stays reserved while a profile is deleted (see EmailTaken), so a
+	// restore cannot conflict with another profile.
+	//
+	// Returns the restored profile with its new version, or ErrProfileNotFound if no
+	// soft-deleted profile has this id and version.
+	RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*Profile, error)
+
 	// ModifyProfile performs a partial update (PATCH semantics).
 	// Only the fields marked as "set" will be updated; others remain unchanged.
 	//
@@ -215,6 +282,18 @@ type ProfileWriteTx interface {
 	// See ProfileWriteStore.CreateProfile for detailed documentation.
 	CreateProfile(ctx context.Context, username, email string) (*Profile, error)
 
+	// CreateProfileWithID inserts a new profile at the given ID within the transaction.
+	// See ProfileWriteStore.CreateProfileWithID for detailed documentation.
+	CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*Profile, error)
+
+	// CreateProfiles inserts many profiles within the transaction.
+	// See ProfileWriteStore.CreateProfiles for detailed documentation.
+	CreateProfiles(ctx context.Context, profiles []NewProfile) ([]CreateProfileResult, error)
+
+	// UpsertProfile inserts or overwrites a profile by email within the transaction.
+	// See ProfileWriteStore.UpsertProfile for detailed documentation.
+	UpsertProfile(ctx context.Context, email string, params *UpsertProfileParams) (profile *Profile, created bool, err error)
+
 	// UpdateProfile updates a profile within the transaction.
 	// See ProfileWriteStore.UpdateProfile for detailed documentation.
 	UpdateProfile(ctx context.Context, params *UpdateProfileParams) (*Profile, error)
@@ -223,6 +302,10 @@ type ProfileWriteTx interface {
 	// See ProfileWriteStore.DeleteProfile for detailed documentation.
 	DeleteProfile(ctx context.Context, id uuid.UUID, version int64) error
 
+	// RestoreProfile undoes a soft delete within the transaction.
+	// See ProfileWriteStore.RestoreProfile for detailed documentation.
+	RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*Profile, error)
+
 	// ModifyProfile performs a partial update within the transaction.
 	// See ProfileWriteStore.ModifyProfile for detailed documentation.
 	ModifyProfile(
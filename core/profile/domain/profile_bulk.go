@@ -0,0 +1,234 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"regexp"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// MaxCreateProfilesBatch bounds the rows of one CreateProfiles call, keeping its
+// transaction and statement short.
+const MaxCreateProfilesBatch = 1000
+
+type (
+	// NewProfile is one row of a batch create.
+	NewProfile struct {
+		// ID is optional, the store generates one when nil.
+		ID    uuid.UUID
+		Name  string
+		Email string
+		// Age is optional, zero for none.
+		Age int
+	}
+
+	// CreateProfileResult is the outcome of one NewProfile: Profile when it was
+	// created, Err otherwise.
+	CreateProfileResult struct {
+		Profile *Profile
+		Err     error
+	}
+)
+
+// emailPattern restates the chk_valid_email constraint of the profiles table.
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+
+// ValidNewProfile reports whether p satisfies the constraints of the profiles table,
+// so a batch is not rejected as a whole for one bad row.
+func ValidNewProfile(p NewProfile) bool {
+	if len(p.Name) == 0 || !emailPattern.MatchString(p.Email) {
+		return false
+	}
+	return p.Age == 0 || ValidAge(p.Age)
+}
+
+// CreateProfiles creates a batch of profiles in one round trip.
+//
+// Results are index-aligned with profiles. Invalid rows get ErrInvalidData and
+// conflicting ones ErrDuplicateProfile or ErrPrecondition (ID in use), without
+// preventing the other rows from being created. The error is only set when the
+// batch as a whole failed, in which case nothing was created.
+func (app *Application) CreateProfiles(ctx context.Context, profiles []NewProfile) ([]CreateProfileResult, error) {
+	if len(profiles) > MaxCreateProfilesBatch {
+		return nil, ErrInvalidData
+	}
+
+	results := make([]CreateProfileResult, len(profiles))
+	valid := make([]NewProfile, 0, len(profiles))
+	// index in profiles of each valid row
+	at := make([]int, 0, len(profiles))
+	for i, p := range profiles {
+		if !ValidNewProfile(p) {
+			results[i].Err = ErrInvalidData
+			continue
+		}
+		valid = append(valid, p)
+		at = append(at, i)
+	}
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	var stored []CreateProfileResult
+	err := app.writer.WithTx(ctx, func(ctx context.Context, tx ProfileWriteTx) error {
+		var err error
+		stored, err = tx.CreateProfiles(ctx, valid)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidData) {
+			return nil, ErrInvalidData
+		}
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return nil, unhandled("profile.CreateProfiles", err)
+	}
+
+	created := 0
+	for j, r := range stored {
+		results[at[j]] = r
+		if r.Err == nil {
+			app.metrics.ProfileCreated(ctx, CreatedByBulk)
+			created++
+		}
+	}
+	slog.DebugContext(ctx, "created profiles", slog.Int("requested", len(profiles)), slog.Int("created", created))
+	return results, nil
+}
+
+// MaxModifyProfilesBatch bounds the patches of one ModifyProfiles call; each one is
+// a statement of the same transaction.
+const MaxModifyProfilesBatch = 100
+
+type (
+	// ProfilePatch is a partial update of the profile ID at Version, with the
+	// tri-state fields of ModifyProfile.
+	ProfilePatch struct {
+		ID      uuid.UUID
+		Version int64
+
+		NameSet, NameNull bool
+		Name              string
+		AgeSet, AgeNull   bool
+		Age               int32
+		EmailSet          bool
+		Email             string
+	}
+
+	// ModifyProfileResult is the outcome of one ProfilePatch: Profile when it was
+	// applied, Err otherwise.
+	ModifyProfileResult struct {
+		Profile *Profile
+		Err     error
+	}
+)
+
+// empty reports whether p updates no field.
+func (p ProfilePatch) empty() bool {
+	return !p.NameSet && !p.AgeSet && !p.EmailSet
+}
+
+// ModifyProfiles applies a batch of patches in a single transaction.
+//
+// The batch is all or nothing. Results are index-aligned with patches: when a patch
+// fails, its result carries ErrInvalidData, ErrPrecondition (version mismatch or
+// profile gone) or ErrDuplicateProfile, and every other one ErrNotApplied. Invalid
+// patches are reported without opening the transaction. The error is only set when
+// the batch as a whole failed.
+func (app *Application) ModifyProfiles(ctx context.Context, patches []ProfilePatch) ([]ModifyProfileResult, error) {
+	if len(patches) == 0 || len(patches) > MaxModifyProfilesBatch {
+		return nil, ErrInvalidData
+	}
+
+	results := make([]ModifyProfileResult, len(patches))
+	if notApplied(results, func(i int) error {
+		if p := patches[i]; p.ID.IsNil() || p.Version < 0 || p.empty() {
+			return ErrInvalidData
+		}
+		return nil
+	}) {
+		return results, nil
+	}
+
+	// failed is the index of the patch that rolled the batch back
+	failed := -1
+	err := app.writer.WithTx(ctx, func(ctx context.Context, tx ProfileWriteTx) error {
+		// the pool may re-run an aborted transaction
+		failed = -1
+		for i, p := range patches {
+			prof, err := tx.ModifyProfile(ctx, p.ID, p.Version,
+				p.NameSet, p.NameNull, p.Name,
+				p.AgeSet, p.AgeNull, p.Age,
+				p.EmailSet, p.Email,
+			)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i] = ModifyProfileResult{Profile: prof}
+		}
+		return nil
+	})
+	if err == nil {
+		return results, nil
+	}
+	if failed < 0 {
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return nil, unhandled("profile.ModifyProfiles", err)
+	}
+
+	switch {
+	case errors.Is(err, ErrProfileNotFound):
+		err = ErrPrecondition
+	case errors.Is(err, ErrDuplicateProfile):
+		err = ErrDuplicateProfile
+	case errors.Is(err, ErrInvalidData):
+		err = ErrInvalidData
+	default:
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return nil, unhandled("profile.ModifyProfiles", err)
+	}
+	notApplied(results, func(i int) error {
+		if i == failed {
+			return err
+		}
+		return nil
+	})
+	return results, nil
+}
+
+// notApplied sets the results failed by check and, when any did, marks all the
+// others ErrNotApplied. It reports whether any failed.
+func notApplied(results []ModifyProfileResult, check func(i int) error) bool {
+	anyFailed := false
+	for i := range results {
+		if err := check(i); err != nil {
+			results[i] = ModifyProfileResult{Err: err}
+			anyFailed = true
+		}
+	}
+	if !anyFailed {
+		return false
+	}
+	for i := range results {
+		if results[i].Err == nil {
+			results[i] = ModifyProfileResult{Err: ErrNotApplied}
+		}
+	}
+	return true
+}
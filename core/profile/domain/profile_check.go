@@ -0,0 +1,58 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// EmailCheckFloor is the minimum duration of CheckEmailAvailable. Taken and free
+// emails, and lookup failures, all answer after it, so response times do not tell
+// whether an account exists.
+const EmailCheckFloor = 150 * time.Millisecond
+
+// CheckEmailAvailable reports whether a new profile could be created with email.
+func (app *Application) CheckEmailAvailable(ctx context.Context, email string) (bool, error) {
+	email = strings.TrimSpace(email)
+	if email == "" || !strings.Contains(email, "@") {
+		return false, ErrInvalidData
+	}
+
+	start := time.Now()
+	taken, err := app.reader.EmailTaken(ctx, email)
+	waitForFloor(ctx, start, EmailCheckFloor)
+	if err != nil {
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return false, unhandled("profile.CheckEmailAvailable", err)
+	}
+	return !taken, nil
+}
+
+// waitForFloor sleeps until floor has elapsed since start, or ctx is done.
+func waitForFloor(ctx context.Context, start time.Time, floor time.Duration) {
+	remaining := floor - time.Since(start)
+	if remaining <= 0 {
+		return
+	}
+	timer := time.NewTimer(remaining)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
@@ -19,6 +19,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+
+	"github.com/gofrs/uuid/v5"
 )
 
 func (app *Application) CreateProfile(ctx context.Context, username, email string) (*Profile, error) {
@@ -37,6 +39,7 @@ func (app *Application) CreateProfile(ctx context.Context, username, email strin
 	})
 	if err == nil {
 		slog.DebugContext(ctx, "created profile", slog.Any("profile", fmt.Sprintf("%+v", created)))
+		app.metrics.ProfileCreated(ctx, CreatedByPost)
 		return created, nil
 	}
 	if errors.Is(err, ErrDuplicateProfile) {
@@ -45,5 +48,33 @@ func (app *Application) CreateProfile(ctx context.Context, username, email strin
 	}
 
 	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
-	return nil, ErrUnhandled
+	return nil, unhandled("profile.CreateProfile", err)
+}
+
+// CreateProfileWithID creates a profile at a client-chosen ID (PUT with If-None-Match: *).
+// Returns ErrPrecondition when the ID is already in use.
+func (app *Application) CreateProfileWithID(ctx context.Context, id uuid.UUID, username, email string) (*Profile, error) {
+	if id.IsNil() || len(username) == 0 || len(email) == 0 {
+		return nil, ErrInvalidData
+	}
+	var created *Profile
+	err := app.writer.WithTx(ctx, func(ctx context.Context, tx ProfileWriteTx) error {
+		p, err := tx.CreateProfileWithID(ctx, id, username, email)
+		if err != nil {
+			return err
+		}
+		created = p
+		return nil
+	})
+	if err == nil {
+		app.metrics.ProfileCreated(ctx, CreatedByPut)
+		return created, nil
+	}
+	for _, known := range []error{ErrPrecondition, ErrDuplicateProfile, ErrInvalidData} {
+		if errors.Is(err, known) {
+			return nil, known
+		}
+	}
+	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	return nil, unhandled("profile.CreateProfileWithID", err)
 }
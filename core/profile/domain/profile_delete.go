@@ -30,6 +30,7 @@ func (app *Application) DeleteProfile(ctx context.Context, id uuid.UUID, version
 		return tx.DeleteProfile(ctx, id, version)
 	})
 	if err == nil {
+		app.metrics.ProfileDeleted(ctx)
 		return nil
 	}
 	if errors.Is(err, ErrProfileNotFound) {
@@ -39,5 +40,5 @@ func (app *Application) DeleteProfile(ctx context.Context, id uuid.UUID, version
 		return ErrInvalidData
 	}
 	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
-	return ErrUnhandled
+	return unhandled("profile.DeleteProfile", err)
 }
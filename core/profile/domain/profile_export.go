@@ -0,0 +1,55 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// MaxExportBatchSize caps the rows held per batch by ExportProfiles.
+const MaxExportBatchSize = 1000
+
+// ExportProfiles passes every live profile, newest first, to emit in batches of at
+// most batchSize, see ProfileReadStore.StreamProfiles.
+//
+// An error returned by emit stops the export and is returned unchanged, so callers
+// can tell their own failures (a client gone away, ...) from the store's.
+func (app *Application) ExportProfiles(ctx context.Context, batchSize int, emit func([]Profile) error) error {
+	if batchSize <= 0 || batchSize > MaxExportBatchSize {
+		return ErrInvalidData
+	}
+
+	var emitErr error
+	err := app.reader.StreamProfiles(ctx, batchSize, func(batch []Profile) error {
+		if err := emit(batch); err != nil {
+			emitErr = err
+			return err
+		}
+		return nil
+	})
+	if err == nil {
+		return nil
+	}
+	if emitErr != nil && errors.Is(err, emitErr) {
+		return emitErr
+	}
+	if ctx.Err() == nil {
+		// a cancelled export is the caller's doing, not worth an error record
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	}
+	return unhandled("profile.ExportProfiles", err)
+}
@@ -17,7 +17,9 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
 )
@@ -34,5 +36,48 @@ func (app *Application) GetProfileByID(ctx context.Context, id uuid.UUID) (*Prof
 		return nil, ErrProfileNotFound
 	}
 	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
-	return nil, ErrUnhandled
+	return nil, unhandled("profile.GetProfileByID", err)
+}
+
+// GetProfileAsOf returns the profile as it was at the instant at.
+//
+// at must not be in the future; with an audit retention it must also fall within
+// the retention window, otherwise ErrOutsideRetention is returned.
+func (app *Application) GetProfileAsOf(ctx context.Context, id uuid.UUID, at time.Time) (*Profile, error) {
+	if id.IsNil() {
+		return nil, ErrInvalidData
+	}
+	now := time.Now()
+	if at.After(now) {
+		return nil, fmt.Errorf("%w: %s is in the future", ErrOutsideRetention, at.Format(time.RFC3339))
+	}
+	if app.auditRetention > 0 && at.Before(now.Add(-app.auditRetention)) {
+		return nil, fmt.Errorf("%w: history is kept for %s", ErrOutsideRetention, app.auditRetention)
+	}
+
+	prof, err := app.reader.GetProfileAsOf(ctx, id, at)
+	if err == nil {
+		return prof, nil
+	}
+	if errors.Is(err, ErrProfileNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	return nil, unhandled("profile.GetProfileAsOf", err)
+}
+
+// ProfileExists reports the current version of a live profile, without loading it.
+func (app *Application) ProfileExists(ctx context.Context, id uuid.UUID) (*ProfileVersion, error) {
+	if id.IsNil() {
+		return nil, ErrInvalidData
+	}
+	version, err := app.reader.ExistsProfile(ctx, id)
+	if err == nil {
+		return &ProfileVersion{ID: id, Version: version}, nil
+	}
+	if errors.Is(err, ErrProfileNotFound) {
+		return nil, ErrProfileNotFound
+	}
+	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	return nil, unhandled("profile.ProfileExists", err)
 }
@@ -21,12 +21,18 @@ import (
 	"time"
 )
 
-func (app *Application) GetProfilesByOffset(ctx context.Context, page int, pageSize int) ([]Profile, int, error) {
+// GetProfilesByOffset returns a 0-based page of the profiles matching q, and how many match.
+func (app *Application) GetProfilesByOffset(ctx context.Context, q ProfileQuery, page int, pageSize int) ([]Profile, int, error) {
 	if page < 0 || pageSize <= 0 {
 		return nil, 0, ErrInvalidData
 	}
+	q, err := q.normalize()
+	if err != nil {
+		return nil, 0, err
+	}
+	pageSize = app.pageSize(pageSize)
 	offset := page * pageSize
-	profiles, count, err := app.reader.GetProfilesByOffset(ctx, pageSize, offset)
+	profiles, count, err := app.reader.GetProfilesByOffset(ctx, q, pageSize, offset)
 	if err != nil {
 		slog.ErrorContext(ctx, "persistence error", slog.Any("error", err))
 		return nil, 0, err
@@ -34,18 +40,24 @@ func (app *Application) GetProfilesByOffset(ctx context.Context, page int, pageS
 	return profiles, count, nil
 }
 
-func (app *Application) GetProfilesByCursor(ctx context.Context, rawCursor string, limit int) ([]Profile, string, error) {
+// GetProfilesByCursor resumes the list of q from rawCursor, which must have been
+// minted by MakeCursorFromProfile for the same query.
+func (app *Application) GetProfilesByCursor(ctx context.Context, q ProfileQuery, rawCursor string, limit int) ([]Profile, string, error) {
 	if limit <= 0 {
 		return nil, "", ErrInvalidData
 	}
+	q, err := q.normalize()
+	if err != nil {
+		return nil, "", err
+	}
 
-	tok, err := app.decodeCursorToken(rawCursor)
+	tok, err := app.decodeCursorToken(rawCursor, CursorProfiles, q.Key())
 	if err != nil {
 		slog.ErrorContext(ctx, "invalid cursor", slog.Any("error", err))
 		return nil, "", ErrInvalidData
 	}
 
-	profiles, err := app.reader.GetProfilesByCursor(ctx, tok.Pivot.CreatedAt, tok.Pivot.ID, tok.Direction, limit)
+	profiles, err := app.reader.GetProfilesByCursor(ctx, q, tok.Pivot, tok.Direction, app.pageSize(limit))
 	if err != nil {
 		slog.ErrorContext(ctx, "persistence error", slog.Any("error", err))
 		return nil, "", err
@@ -70,7 +82,8 @@ func (app *Application) encodeCursorToken(tok *CursorPaginationToken) (string, e
 	return app.signer.Sign(b)
 }
 
-func (app *Application) decodeCursorToken(s string) (*CursorPaginationToken, error) {
+// decodeCursorToken verifies s and checks it was issued for entity and scope.
+func (app *Application) decodeCursorToken(s string, entity CursorEntity, scope string) (*CursorPaginationToken, error) {
 	if s == "" {
 		return nil, ErrInvalidData
 	}
@@ -88,16 +101,31 @@ func (app *Application) decodeCursorToken(s string) (*CursorPaginationToken, err
 	if tok.Direction != ASC && tok.Direction != DESC {
 		return nil, ErrInvalidData
 	}
+	if tok.Entity != entity || tok.Scope != scope {
+		return nil, ErrInvalidData
+	}
 	return &tok, nil
 }
 
-func (app *Application) MakeCursorFromProfile(p Profile, dir CursorDirection, ttl time.Duration) string {
+// MakeCursorFromProfile mints a cursor resuming the list of q at p, forward (DESC)
+// or backward (ASC) in the list order.
+func (app *Application) MakeCursorFromProfile(p Profile, q ProfileQuery, dir CursorDirection, ttl time.Duration) string {
+	q, err := q.normalize()
+	if err != nil {
+		return ""
+	}
 	tok := &CursorPaginationToken{
 		TTL:       time.Now().Add(ttl),
 		Direction: dir,
+		Entity:    CursorProfiles,
+		Scope:     q.Key(),
+		Pivot: CursorPivot{
+			CreatedAt: p.CreatedAt,
+			ID:        p.ID,
+			Name:      p.Name,
+			Age:       p.Age,
+		},
 	}
-	tok.Pivot.CreatedAt = p.CreatedAt
-	tok.Pivot.ID = p.ID
 	s, err := app.encodeCursorToken(tok)
 	if err != nil {
 		return ""
@@ -106,9 +134,13 @@ func (app *Application) MakeCursorFromProfile(p Profile, dir CursorDirection, tt
 }
 
 // First page for cursor mode (no client-provided cursor)
-func (app *Application) GetProfilesFirstPage(ctx context.Context, limit int) ([]Profile, error) {
+func (app *Application) GetProfilesFirstPage(ctx context.Context, q ProfileQuery, limit int) ([]Profile, error) {
 	if limit <= 0 {
 		return nil, ErrInvalidData
 	}
-	return app.reader.GetProfilesFirstPage(ctx, limit)
+	q, err := q.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return app.reader.GetProfilesFirstPage(ctx, q, app.pageSize(limit))
 }
@@ -0,0 +1,153 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sortable profile fields, as accepted by ParseProfileSort.
+const (
+	SortByCreatedAt SortField = "createdAt"
+	SortByName      SortField = "name"
+	SortByAge       SortField = "age"
+)
+
+type (
+	// SortField names a field profile lists may be ordered by.
+	SortField string
+
+	// ProfileSort orders a profile list by Field, ties broken by ID in the same direction.
+	// Names compare case-insensitively; a missing name or age sorts as empty or zero.
+	ProfileSort struct {
+		Field SortField
+		Desc  bool
+	}
+
+	// ProfileQuery narrows and orders a profile list.
+	ProfileQuery struct {
+		Filter ProfileFilter
+		Sort   ProfileSort
+	}
+)
+
+// DefaultProfileSort lists the newest profiles first.
+var DefaultProfileSort = ProfileSort{Field: SortByCreatedAt, Desc: true}
+
+// sortFields is the whitelist of ParseProfileSort.
+var sortFields = []SortField{SortByCreatedAt, SortByName, SortByAge}
+
+// ParseProfileSort parses a sort parameter: a SortField, prefixed with "-" for a
+// descending order. An empty s is DefaultProfileSort. Fields outside the whitelist
+// are rejected with ErrInvalidData.
+func ParseProfileSort(s string) (ProfileSort, error) {
+	if s == "" {
+		return DefaultProfileSort, nil
+	}
+	sort := ProfileSort{Field: SortField(strings.TrimPrefix(s, "-")), Desc: strings.HasPrefix(s, "-")}
+	for _, f := range sortFields {
+		if f == sort.Field {
+			return sort, nil
+		}
+	}
+	return ProfileSort{}, ErrInvalidData
+}
+
+// String formats s as ParseProfileSort accepts it.
+func (s ProfileSort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// normalize validates f and trims its text fields. It rejects inverted ranges
+// and an email domain holding an "@".
+func (f ProfileFilter) normalize() (ProfileFilter, error) {
+	if f.MinAge != nil && f.MaxAge != nil && *f.MinAge > *f.MaxAge {
+		return f, ErrInvalidData
+	}
+	if f.CreatedFrom != nil && f.CreatedTo != nil && !f.CreatedFrom.Before(*f.CreatedTo) {
+		return f, ErrInvalidData
+	}
+	f.EmailDomain = strings.TrimPrefix(strings.TrimSpace(f.EmailDomain), "@")
+	if strings.Contains(f.EmailDomain, "@") {
+		return f, ErrInvalidData
+	}
+	f.NamePrefix = strings.TrimSpace(f.NamePrefix)
+	return f, nil
+}
+
+// Key canonically encodes q. Cursors are scoped to it, so a cursor minted for one
+// filter or sort is rejected by another instead of resuming the wrong keyset.
+// The unfiltered, default sorted list has an empty key, like the cursors minted
+// before lists could be filtered.
+func (q ProfileQuery) Key() string {
+	return q.Values().Encode()
+}
+
+// Values returns q as the query parameters of the list endpoint, empty for the
+// unfiltered, default sorted list.
+func (q ProfileQuery) Values() url.Values {
+	v := url.Values{}
+	if q.Sort == DefaultProfileSort && q.Filter == (ProfileFilter{}) {
+		return v
+	}
+	v.Set("sort", q.Sort.String())
+	f := q.Filter
+	if f.NamePrefix != "" {
+		v.Set("namePrefix", strings.ToLower(f.NamePrefix))
+	}
+	if f.EmailDomain != "" {
+		v.Set("emailDomain", strings.ToLower(f.EmailDomain))
+	}
+	if f.MinAge != nil {
+		v.Set("minAge", strconv.Itoa(*f.MinAge))
+	}
+	if f.MaxAge != nil {
+		v.Set("maxAge", strconv.Itoa(*f.MaxAge))
+	}
+	if f.CreatedFrom != nil {
+		v.Set("createdFrom", f.CreatedFrom.UTC().Format(time.RFC3339Nano))
+	}
+	if f.CreatedTo != nil {
+		v.Set("createdTo", f.CreatedTo.UTC().Format(time.RFC3339Nano))
+	}
+	if f.IncludeDeleted {
+		v.Set("includeDeleted", "true")
+	}
+	return v
+}
+
+// Validate reports ErrInvalidData for an unknown sort field or an inverted range.
+func (q ProfileQuery) Validate() error {
+	_, err := q.normalize()
+	return err
+}
+
+// normalize validates q and fills in the default sort.
+func (q ProfileQuery) normalize() (ProfileQuery, error) {
+	if q.Sort.Field == "" {
+		q.Sort = DefaultProfileSort
+	} else if _, err := ParseProfileSort(q.Sort.String()); err != nil {
+		return q, err
+	}
+	var err error
+	q.Filter, err = q.Filter.normalize()
+	return q, err
+}
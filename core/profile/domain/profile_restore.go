@@ -0,0 +1,48 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// RestoreProfile brings back a soft-deleted profile at the given version.
+// A profile that is live, unknown or at another version fails with ErrPrecondition.
+func (app *Application) RestoreProfile(ctx context.Context, id uuid.UUID, version int64) (*Profile, error) {
+	if id.IsNil() || version < 0 {
+		return nil, ErrInvalidData
+	}
+	var restored *Profile
+	err := app.writer.WithTx(ctx, func(ctx context.Context, tx ProfileWriteTx) error {
+		var err error
+		restored, err = tx.RestoreProfile(ctx, id, version)
+		return err
+	})
+	if err == nil {
+		return restored, nil
+	}
+	if errors.Is(err, ErrProfileNotFound) {
+		return nil, ErrPrecondition
+	}
+	if errors.Is(err, ErrInvalidData) {
+		return nil, ErrInvalidData
+	}
+	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	return nil, unhandled("profile.RestoreProfile", err)
+}
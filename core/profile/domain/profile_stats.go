@@ -0,0 +1,45 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"log/slog"
+)
+
+// CountProfiles counts live profiles matching filter.
+func (app *Application) CountProfiles(ctx context.Context, filter ProfileFilter) (int, error) {
+	filter, err := filter.normalize()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := app.reader.CountProfiles(ctx, filter)
+	if err != nil {
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return 0, unhandled("profile.CountProfiles", err)
+	}
+	return n, nil
+}
+
+// ProfileStats returns dashboard aggregates over live profiles.
+func (app *Application) ProfileStats(ctx context.Context) (*ProfileStats, error) {
+	stats, err := app.reader.ProfileStats(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+		return nil, unhandled("profile.ProfileStats", err)
+	}
+	return stats, nil
+}
@@ -55,7 +55,7 @@ func (app *Application) UpdateProfile(ctx context.Context, p *UpdateProfileParam
 		return nil, ErrInvalidData
 	}
 	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
-	return nil, ErrUnhandled
+	return nil, unhandled("profile.UpdateProfile", err)
 }
 
 // ModifyProfile applies a partial update: only provided fields are updated.
@@ -88,5 +88,5 @@ func (app *Application) ModifyProfile(ctx context.Context, id uuid.UUID, version
 		return nil, ErrInvalidData
 	}
 	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
-	return nil, ErrUnhandled
+	return nil, unhandled("profile.ModifyProfile", err)
 }
@@ -0,0 +1,57 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// UpsertProfileParams carries the fields an upsert writes; the email keying the
+// upsert is passed separately.
+type UpsertProfileParams struct {
+	Name string
+}
+
+// UpsertProfile creates the profile owning email or replaces its fields when one
+// already exists. created reports which of the two happened.
+//
+// Unlike UpdateProfile no version is required: identity systems provisioning
+// profiles only know the email, and repeating the call converges on the same state.
+func (app *Application) UpsertProfile(ctx context.Context, email string, p *UpsertProfileParams) (profile *Profile, created bool, err error) {
+	if p == nil || len(p.Name) == 0 || len(email) == 0 {
+		return nil, false, ErrInvalidData
+	}
+	err = app.writer.WithTx(ctx, func(ctx context.Context, tx ProfileWriteTx) error {
+		profile, created, err = tx.UpsertProfile(ctx, email, p)
+		return err
+	})
+	if err == nil {
+		slog.DebugContext(ctx, "upserted profile", slog.String("id", profile.ID.String()), slog.Bool("created", created))
+		if created {
+			app.metrics.ProfileCreated(ctx, CreatedByUpsert)
+		}
+		return profile, created, nil
+	}
+	if errors.Is(err, ErrDuplicateProfile) {
+		return nil, false, ErrDuplicateProfile
+	}
+	if errors.Is(err, ErrInvalidData) {
+		return nil, false, ErrInvalidData
+	}
+	slog.ErrorContext(ctx, "unexpected error", slog.Any("error", err))
+	return nil, false, unhandled("profile.UpsertProfile", err)
+}
@@ -26,8 +26,18 @@ type (
 		reader ProfileReadStore
 		writer ProfileWriteStore
 		signer CursorSigner
+
+		// how far back GetProfileAsOf may look, zero for no bound
+		auditRetention time.Duration
+		// largest list page served, zero for no bound
+		maxPageSize int
+
+		metrics BusinessMetrics
 	}
 
+	// AppOption configures an Application.
+	AppOption func(*Application)
+
 	// Profile is the domain model used by the application layer.
 	Profile struct {
 		ID        uuid.UUID
@@ -35,6 +45,8 @@ type (
 		Email     string
 		Age       int
 		CreatedAt time.Time
+		// DeletedAt is set on soft-deleted profiles, only listed with IncludeDeleted.
+		DeletedAt *time.Time
 
 		Version int64
 	}
@@ -44,22 +56,124 @@ func (p *Profile) V() string {
 	return strconv.Itoa(int(p.Version))
 }
 
+// ETagScope keeps the entity tags of two profiles apart at the same version.
+func (p *Profile) ETagScope() string {
+	return p.ID.String()
+}
+
+// ProfileVersion identifies a specific version of a profile without its data.
+type ProfileVersion struct {
+	ID      uuid.UUID
+	Version int64
+}
+
+func (p *ProfileVersion) V() string {
+	return strconv.Itoa(int(p.Version))
+}
+
+// ETagScope matches Profile.ETagScope, so HEAD and GET return the same tag.
+func (p *ProfileVersion) ETagScope() string {
+	return p.ID.String()
+}
+
+type (
+	// ProfileFilter narrows profile lists and counts. Zero fields do not filter.
+	ProfileFilter struct {
+		// case-insensitive prefix of the name
+		NamePrefix string
+		// inclusive age bounds
+		MinAge, MaxAge *int
+		// half-open creation window [CreatedFrom, CreatedTo)
+		CreatedFrom, CreatedTo *time.Time
+		// case-insensitive domain part of the email, without "@"
+		EmailDomain string
+		// also match soft-deleted profiles, for admin tooling
+		IncludeDeleted bool
+	}
+
+	// AgeBucket is a closed age range; a nil Max means unbounded.
+	AgeBucket struct {
+		Label    string
+		Min, Max *int
+	}
+
+	AgeBucketCount struct {
+		AgeBucket
+		Count int
+	}
+
+	DailySignups struct {
+		// Day is midnight UTC
+		Day   time.Time
+		Count int
+	}
+
+	// ProfileStats aggregates live profiles for dashboards.
+	ProfileStats struct {
+		Total int
+		// one entry per AgeBuckets element plus UnknownAgeBucket, in that order
+		ByAge []AgeBucketCount
+		// last SignupsWindow days, ascending, days without signups omitted
+		SignupsPerDay []DailySignups
+	}
+)
+
+// SignupsWindow is how far back ProfileStats reports daily signups.
+const SignupsWindow = 30 * 24 * time.Hour
+
+var (
+	// AgeBuckets partitions valid ages (MinAge..MaxAge) for ProfileStats.
+	AgeBuckets = []AgeBucket{
+		{Label: "1-17", Min: intPtr(1), Max: intPtr(17)},
+		{Label: "18-24", Min: intPtr(18), Max: intPtr(24)},
+		{Label: "25-34", Min: intPtr(25), Max: intPtr(34)},
+		{Label: "35-44", Min: intPtr(35), Max: intPtr(44)},
+		{Label: "45-54", Min: intPtr(45), Max: intPtr(54)},
+		{Label: "55-64", Min: intPtr(55), Max: intPtr(64)},
+		{Label: "65+", Min: intPtr(65)},
+	}
+
+	// UnknownAgeBucket holds profiles without an age.
+	UnknownAgeBucket = AgeBucket{Label: "unknown"}
+)
+
+func intPtr(i int) *int { return &i }
+
 const (
 	ASC  CursorDirection = "asc"
 	DESC CursorDirection = "desc"
 )
 
+// CursorProfiles discriminates cursors of the profile list.
+const CursorProfiles CursorEntity = "profiles"
+
 type (
 	CursorDirection string
 
+	// CursorEntity names the collection a cursor token pages through. Tokens are
+	// only accepted by the endpoint they were issued for, so a signed cursor cannot
+	// be replayed against another keyset (e.g. a future audit history).
+	CursorEntity string
+
+	// CursorPivot is the edge row of a page, holding every sortable field so a cursor
+	// can resume any ProfileSort; ID breaks ties.
+	CursorPivot struct {
+		CreatedAt time.Time `json:"created_at"`
+		ID        uuid.UUID `json:"id"`
+		Name      string    `json:"name,omitempty"`
+		Age       int       `json:"age,omitempty"`
+	}
+
 	CursorPaginationToken struct {
 		TTL       time.Time       `json:"ttl"`
 		Direction CursorDirection `json:"direction"`
 
-		Pivot struct {
-			CreatedAt time.Time `json:"created_at"`
-			ID        uuid.UUID `json:"id"`
-		} `json:"pivot"`
+		Entity CursorEntity `json:"entity"`
+		// Scope narrows Entity to one parent, e.g. the profile id of a history cursor,
+		// or to the filter and sort of the list it pages (see ProfileQuery.Key).
+		Scope string `json:"scope,omitempty"`
+
+		Pivot CursorPivot `json:"pivot"`
 
 		Signature string `json:"-"`
 	}
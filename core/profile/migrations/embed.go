@@ -0,0 +1,27 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrations holds the profile service schema, embedded so the migrate
+// command and the server apply exactly what the binary was built with.
+package migrations
+
+import "embed"
+
+// Schema contains the versioned schema migrations, e.g. "schema/V1_create_table_profiles.sql".
+//
+//go:embed schema/*.sql
+var Schema embed.FS
+
+// SchemaDir is the directory of Schema holding the migration files.
+const SchemaDir = "schema"
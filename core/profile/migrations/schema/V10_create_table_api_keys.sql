@@ -0,0 +1,32 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- API keys (see modules/apikey). Only the SHA-256 hash of a key is stored; the
+-- secret is shown once when the key is issued. Scopes are space separated, as
+-- in OAuth2. Revoked keys are kept for the audit trail.
+CREATE TABLE api_keys (
+    id UUID PRIMARY KEY,
+    key_hash BYTEA NOT NULL,
+    hint TEXT NOT NULL,
+    name TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX ux_api_keys_key_hash ON api_keys (key_hash);
+CREATE INDEX ix_api_keys_subject ON api_keys (subject, created_at);
@@ -0,0 +1,32 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- Saga instances (see modules/saga): how far each run of a workflow got and the
+-- data its steps share. Finished rows are kept for troubleshooting.
+CREATE TABLE saga_instances (
+    id UUID PRIMARY KEY,
+    saga TEXT NOT NULL,
+    status TEXT NOT NULL,
+    step INT NOT NULL DEFAULT 0,
+    data JSONB NOT NULL,
+    error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_saga_instances_status CHECK (status IN ('running', 'completed', 'compensating', 'compensated'))
+);
+
+-- Resume only ever looks for unfinished instances
+CREATE INDEX ix_saga_instances_unfinished ON saga_instances (saga, updated_at)
+    WHERE status IN ('running', 'compensating');
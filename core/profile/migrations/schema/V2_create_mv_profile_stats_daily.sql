@@ -0,0 +1,31 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- Pre-aggregated live profiles per signup day (UTC) and age, backing the stats endpoints.
+-- Age buckets are defined by the application and summed over this view, so changing
+-- them does not require a migration. A NULL age is stored as 0 (valid ages are 1..150)
+-- because REFRESH ... CONCURRENTLY needs a unique index covering every row.
+CREATE MATERIALIZED VIEW profile_stats_daily AS
+SELECT
+    (created_at AT TIME ZONE 'UTC')::date AS signup_day,
+    COALESCE(age, 0) AS age,
+    COUNT(*) AS profiles
+FROM profiles
+WHERE deleted_at IS NULL
+GROUP BY 1, 2;
+
+CREATE UNIQUE INDEX ux_profile_stats_daily_day_age ON profile_stats_daily (signup_day, age);
+
+COMMENT ON MATERIALIZED VIEW profile_stats_daily IS 'Refreshed CONCURRENTLY by the stats refresh job';
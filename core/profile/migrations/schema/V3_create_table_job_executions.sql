@@ -0,0 +1,29 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- One row per background job run that acquired its lock (see locking.ExecutionRecorder),
+-- for dashboards and "last successful run" checks. Rows are never updated.
+CREATE TABLE job_executions (
+    id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    name TEXT NOT NULL,
+    node TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT,
+    CONSTRAINT chk_job_executions_status CHECK (status IN ('succeeded', 'failed', 'timed_out'))
+);
+
+CREATE INDEX ix_job_executions_name_started_at ON job_executions (name, started_at DESC);
@@ -0,0 +1,25 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- Rate limit counters for deployments without Redis (see modules/db/postgres/counter).
+-- UNLOGGED: counters are cheap to lose on a crash and skip the WAL on every request.
+-- Expired rows are reset in place by the next increment and purged by a background job.
+CREATE UNLOGGED TABLE rate_limit_counters (
+    key TEXT PRIMARY KEY,
+    value BIGINT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX ix_rate_limit_counters_expires_at ON rate_limit_counters (expires_at);
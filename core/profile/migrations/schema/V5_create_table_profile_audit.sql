@@ -0,0 +1,62 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Append-only history of profiles, one row per committed change, written by trigger
+-- so every write path (prepared statements, bulk COPY, manual fixes) is recorded.
+-- A row holds the full state after the change ('I', 'U') or before a hard delete ('D'),
+-- so the state at any instant is the latest row recorded at or before it.
+-- Rows older than the retention window are purged by a background job, keeping the
+-- row that still describes each profile at the window start.
+CREATE TABLE profile_audit (
+    audit_id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    profile_id UUID NOT NULL,
+    operation CHAR(1) NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+
+    version_number BIGINT NOT NULL,
+    username TEXT,
+    email CITEXT NOT NULL,
+    age INTEGER,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    deleted_at TIMESTAMPTZ,
+
+    CONSTRAINT chk_profile_audit_operation CHECK (operation IN ('I', 'U', 'D'))
+);
+
+CREATE INDEX ix_profile_audit_profile_recorded ON profile_audit (profile_id, recorded_at DESC, audit_id DESC);
+CREATE INDEX ix_profile_audit_recorded_at ON profile_audit (recorded_at);
+
+CREATE FUNCTION profile_audit_record() RETURNS trigger LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO profile_audit (profile_id, operation, version_number, username, email, age, created_at, updated_at, deleted_at)
+        VALUES (OLD.id, 'D', OLD.version_number, OLD.username, OLD.email, OLD.age, OLD.created_at, OLD.updated_at, OLD.deleted_at);
+        RETURN OLD;
+    END IF;
+
+    INSERT INTO profile_audit (profile_id, operation, version_number, username, email, age, created_at, updated_at, deleted_at)
+    VALUES (NEW.id, left(TG_OP, 1), NEW.version_number, NEW.username, NEW.email, NEW.age, NEW.created_at, NEW.updated_at, NEW.deleted_at);
+    RETURN NEW;
+END;
+$$;
+
+CREATE TRIGGER trg_profiles_audit
+    AFTER INSERT OR UPDATE OR DELETE ON profiles
+    FOR EACH ROW EXECUTE FUNCTION profile_audit_record();
+
+-- existing profiles start their history at their last update
+INSERT INTO profile_audit (profile_id, operation, recorded_at, version_number, username, email, age, created_at, updated_at, deleted_at)
+SELECT id, 'I', greatest(updated_at, coalesce(deleted_at, updated_at)), version_number, username, email, age, created_at, updated_at, deleted_at
+FROM profiles;
@@ -0,0 +1,18 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Serves profile lists sorted by age; a missing age sorts as 0 like in the reader.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS ix_profiles_age
+    ON profiles ((COALESCE(age, 0)), id)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,35 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+
+-- Outbound deliveries (see modules/delivery): webhook calls and published events
+-- with the outcome of their attempts, so failed ones can be requeued. The payload
+-- is kept for the replay, next to its SHA-256 hash.
+CREATE TABLE deliveries (
+    id UUID PRIMARY KEY,
+    destination TEXT NOT NULL,
+    event TEXT NOT NULL,
+    payload BYTEA NOT NULL,
+    payload_hash BYTEA NOT NULL,
+    status TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT chk_deliveries_status CHECK (status IN ('pending', 'succeeded', 'failed'))
+);
+
+-- Dispatchers read pending deliveries, requeues select failed ones, and the purge
+-- finished ones, all by age
+CREATE INDEX ix_deliveries_status ON deliveries (status, updated_at);
@@ -0,0 +1,182 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profilefactory
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"app/core/profile/domain"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	firstNames = []string{
+		"alice", "bao", "carlos", "chen", "dmitri", "elena", "fatima", "giulia", "hana", "ivan",
+		"jamal", "kai", "lan", "linh", "maria", "minh", "noah", "olga", "priya", "quang",
+		"rahul", "sofia", "tuan", "uma", "victor", "wei", "xuan", "yara", "yusuf", "zoe",
+	}
+	lastNames = []string{
+		"anderson", "bui", "costa", "dang", "evans", "fischer", "garcia", "ho", "ivanova", "johnson",
+		"kim", "le", "mueller", "nguyen", "okafor", "pham", "rossi", "silva", "tran", "vo",
+	}
+	defaultDomains = []string{"example.com", "example.org", "example.net", "mail.test", "corp.test"}
+)
+
+type (
+	// Factory produces profiles. It is not safe for concurrent use.
+	Factory struct {
+		rng *rand.Rand
+
+		now    time.Time
+		spread time.Duration
+
+		domains []string
+		// share of profiles without an age, in [0, 1]
+		unknownAge float64
+
+		seq int
+	}
+
+	// Option configures a Factory.
+	Option func(*Factory)
+)
+
+// WithSeed makes the output reproducible. Without it every Factory differs.
+func WithSeed(seed uint64) Option {
+	return func(f *Factory) {
+		f.rng = rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	}
+}
+
+// WithNow sets the newest possible creation time. Defaults to time.Now at construction.
+func WithNow(now time.Time) Option {
+	return func(f *Factory) {
+		if !now.IsZero() {
+			f.now = now
+		}
+	}
+}
+
+// WithCreatedSpread sets how far back creation times go. Defaults to 90 days.
+func WithCreatedSpread(d time.Duration) Option {
+	return func(f *Factory) {
+		if d > 0 {
+			f.spread = d
+		}
+	}
+}
+
+// WithEmailDomains replaces the email domains profiles are spread over.
+func WithEmailDomains(domains ...string) Option {
+	return func(f *Factory) {
+		if len(domains) > 0 {
+			f.domains = domains
+		}
+	}
+}
+
+// WithUnknownAgeRatio sets the share of profiles without an age. Defaults to 0.1.
+func WithUnknownAgeRatio(ratio float64) Option {
+	return func(f *Factory) {
+		if ratio >= 0 && ratio <= 1 {
+			f.unknownAge = ratio
+		}
+	}
+}
+
+func New(opts ...Option) *Factory {
+	f := &Factory{
+		rng:        rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+		now:        time.Now().UTC(),
+		spread:     90 * 24 * time.Hour,
+		domains:    defaultDomains,
+		unknownAge: 0.1,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	return f
+}
+
+// Profile returns the next profile.
+//
+// Emails are unique per Factory. IDs are UUIDv7 derived from CreatedAt, matching
+// what the database assigns, so (created_at, id) ordering stays consistent.
+func (f *Factory) Profile() domain.Profile {
+	f.seq++
+	first := firstNames[f.rng.IntN(len(firstNames))]
+	last := lastNames[f.rng.IntN(len(lastNames))]
+	createdAt := f.createdAt()
+
+	id, err := uuid.NewV7AtTime(createdAt)
+	if err != nil {
+		// only fails when the system random source does
+		panic(fmt.Sprintf("profilefactory: generate id: %v", err))
+	}
+
+	return domain.Profile{
+		ID:        id,
+		Name:      f.username(first, last),
+		Email:     fmt.Sprintf("%s.%s.%d@%s", first, last, f.seq, f.domains[f.rng.IntN(len(f.domains))]),
+		Age:       f.age(),
+		CreatedAt: createdAt,
+	}
+}
+
+// Profiles returns the next n profiles.
+func (f *Factory) Profiles(n int) []domain.Profile {
+	out := make([]domain.Profile, max(n, 0))
+	for i := range out {
+		out[i] = f.Profile()
+	}
+	return out
+}
+
+func (f *Factory) username(first, last string) string {
+	switch f.rng.IntN(4) {
+	case 0:
+		return first + last[:1]
+	case 1:
+		return first + "_" + last
+	case 2:
+		return fmt.Sprintf("%s%d", first, 1950+f.rng.IntN(60))
+	default:
+		return strings.ToUpper(first[:1]) + first[1:] + " " + strings.ToUpper(last[:1]) + last[1:]
+	}
+}
+
+// age is 0 (unknown) for a share of profiles, otherwise roughly normal around 34,
+// clamped to the domain range.
+func (f *Factory) age() int {
+	if f.rng.Float64() < f.unknownAge {
+		return 0
+	}
+	a := int(34 + f.rng.NormFloat64()*12)
+	return min(max(a, 16), 90)
+}
+
+// createdAt skews towards recent times, like a growing product: the age of a
+// profile is the spread scaled by u^2 for a uniform u.
+func (f *Factory) createdAt() time.Time {
+	u := f.rng.Float64()
+	back := time.Duration(u * u * float64(f.spread))
+	return f.now.Add(-back).Truncate(time.Microsecond)
+}
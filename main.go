@@ -16,34 +16,74 @@
 //go:generate go tool oapi-codegen -config modules/oapi/stdlib/cfg.server.payment.yaml modules/oapi/openapi-payment.yaml
 //go:generate go tool oapi-codegen -config modules/oapi/echo/cfg.server.profile.yaml modules/oapi/openapi-profile.yaml
 //go:generate go tool oapi-codegen -config modules/oapi/echo/cfg.server.payment.yaml modules/oapi/openapi-payment.yaml
+//go:generate protoc --proto_path=modules/proto --go_out=. --go_opt=module=app --go-grpc_out=. --go-grpc_opt=module=app modules/proto/profile/v1/profile.proto
+//go:generate go run ./cmd/constraintcheck
 package main
 
 import (
 	"context"
 	"embed"
+	"io/fs"
 	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
+	"app/modules/admin"
+	grpc_profile_api "app/modules/api/profileapi/grpc"
+	"app/modules/apikey"
 	"app/modules/appconfig"
+	"app/modules/authz"
 	"app/modules/clock"
+	"app/modules/db"
+	"app/modules/db/migrate"
 	"app/modules/db/postgres"
+	pgapikey "app/modules/db/postgres/apikey"
+	pgcounter "app/modules/db/postgres/counter"
+	pgdelivery "app/modules/db/postgres/delivery"
+	pgsaga "app/modules/db/postgres/saga"
 	"app/modules/db/redis"
 	"app/modules/db/redis/counter"
+	"app/modules/db/redis/gcra"
+	"app/modules/db/redis/locking"
+	"app/modules/delivery"
+	"app/modules/grpcserver"
+	"app/modules/health"
 	hmac_sign "app/modules/hmac"
+	"app/modules/httpclient"
+	"app/modules/httpsig"
+	"app/modules/i18n"
+	"app/modules/jobs"
+	"app/modules/lifecycle"
 	"app/modules/middleware"
+	authmw "app/modules/middleware/auth"
 	"app/modules/middleware/ratelimit"
+	"app/modules/middleware/routepolicy"
+	"app/modules/preferences"
 	rl "app/modules/ratelimit"
+	"app/modules/readonly"
+	"app/modules/requestid"
+	"app/modules/resilience"
+	"app/modules/retry"
 	"app/modules/server"
 	"app/modules/services"
 	"app/modules/telemetry"
 
+	"app/core/onboarding"
+	paymentmemory "app/core/payment/adapters/memory"
+	profilecache "app/core/profile/adapters/persistence/cache"
 	persistence "app/core/profile/adapters/persistence/pg"
+	"app/core/profile/domain"
+	"app/core/profile/migrations"
 
+	profile_grpc "app/core/profile/adapters/grpc"
 	profile_http "app/core/profile/adapters/rest"
+
+	"github.com/getkin/kin-openapi/openapi3"
 )
 
 // OpenAPI specs for request validation at runtime
@@ -63,8 +103,12 @@ func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGKILL, syscall.SIGTERM, os.Interrupt)
 	defer cancel()
 
+	// startup/shutdown milestones, emitted as log records and OTel span events
+	events := lifecycle.NewEventLog()
+
 	// manual dependency injections, imo there's no need to over-engineer with DI frameworks like Fx or Wire
-	slog.SetLogLoggerLevel(slog.LevelDebug)
+	// records logged with a request context carry its request_id
+	slog.SetDefault(slog.New(requestid.NewLogHandler(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))))
 
 	clock := clock.RealClock{}
 
@@ -72,19 +116,64 @@ func main() {
 	appConfig, err := appconfig.Load()
 	if err != nil {
 		slog.ErrorContext(ctx, "failed to load config", slog.Any("error", err))
-		os.Exit(1)
+		exitCode = 1
+		return
 	}
+	events.Emit(ctx, lifecycle.EventConfigLoaded, slog.String("env", appConfig.Env))
+
+	// --- lifecycle ---
+	// components register as they are constructed; shutdown runs stage by stage
+	// (ingress, workers, stores, telemetry), in reverse dependency order within a stage
+	lc := lifecycle.New(
+		lifecycle.WithStopTimeout(10*time.Second),
+		lifecycle.WithEventLog(events),
+	)
+	defer func() {
+		// ctx is cancelled by the time we get here, keep its values only
+		if err := lc.Stop(context.WithoutCancel(ctx)); err != nil {
+			exitCode = 1
+		}
+	}()
 
 	// --- infrastructure ---
 
+	// telemetry first so every other component is instrumented and flushed last
+	otelShutdown, err := telemetry.Init(ctx, appConfig.Otel)
+	if err != nil {
+		slog.ErrorContext(ctx, "telemetry not properly configured", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	if err := lc.Register(lifecycle.Component{
+		Name:     "telemetry",
+		Priority: lifecycle.PriorityTelemetry,
+		// telemetry stops last, so everything else is down once its hook runs
+		Stop: func(ctx context.Context) error {
+			events.Emit(ctx, lifecycle.EventStopped)
+			return otelShutdown(ctx)
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
 	connectionPool, err := postgres.New(
 		ctx,
 		&appConfig.Postgres,
 		postgres.PostgresOptions{
+			WriterOptions: []postgres.PgxConfigOption{
+				postgres.WithStatementTimeout(appConfig.Postgres.StatementTimeout),
+				postgres.WithSlowQueryLog("primary", appConfig.Postgres.SlowQueryThreshold),
+				postgres.WithOtelTracer(postgres.WithTracedPoolName("primary")),
+			},
 			// assuming writer connection does not pass through pgBouncer,
 			// so we can apply server-side prepared statements
 			ReaderOptions: []postgres.PgxConfigOption{
 				postgres.WithPgBouncerSimpleProtocol(),
+				postgres.WithStatementTimeout(appConfig.Postgres.StatementTimeout),
+				postgres.WithSlowQueryLog("replica", appConfig.Postgres.SlowQueryThreshold),
+				postgres.WithOtelTracer(postgres.WithTracedPoolName("replica")),
 			},
 		},
 	)
@@ -93,18 +182,93 @@ func main() {
 		exitCode = 1
 		return
 	}
-	defer func() {
-		if err := connectionPool.Shutdown(ctx); err != nil {
-			slog.ErrorContext(ctx, "database shutdown error", slog.Any("error", err))
-		}
-	}()
+	if err := lc.Register(lifecycle.Component{
+		Name:      "postgres",
+		Priority:  lifecycle.PriorityStores,
+		DependsOn: []string{"telemetry"},
+		Stop:      connectionPool.Shutdown,
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
 
-	// Should be a separate goroutine
+	// fail fast on a database unreachable at startup; the health monitor watches it from then on
 	if err = connectionPool.HealthCheck(); err != nil {
 		slog.ErrorContext(ctx, "database health check failed", slog.Any("error", err))
 		exitCode = 1
 		return
 	}
+	events.Emit(ctx, lifecycle.EventDBReady)
+
+	// dependency health for /readyz, changing state only after repeated probe outcomes
+	healthRegistry := health.NewRegistry()
+	probe := appConfig.Health
+	healthRegistry.Watch("postgres", probe.Interval, probe.Timeout, func(ctx context.Context) error {
+		_, err := connectionPool.Writer().ExecContext(ctx, "SELECT 1")
+		return err
+	})
+	// a lost replica slows reads down but the writer still serves them
+	for i, replica := range connectionPool.Replicas() {
+		healthRegistry.Watch("postgres-replica-"+strconv.Itoa(i), probe.Interval, probe.Timeout, func(ctx context.Context) error {
+			_, err := replica.ExecContext(ctx, "SELECT 1")
+			return err
+		}, health.WithCritical(false))
+	}
+	healthRegistry.Watch("telemetry", probe.Interval, probe.Timeout, telemetry.CheckExporters, health.WithCritical(false))
+	// rate limits, locks and read-only mode all have a redis failure policy; the watchdog feeds it
+	redisHealth := healthRegistry.Track("redis", health.WithCritical(false))
+	healthMonitor := health.NewMonitor(healthRegistry, health.WithDrainAfter(probe.DrainAfter))
+
+	healthCtx, stopHealth := context.WithCancel(context.WithoutCancel(ctx))
+	var healthDone sync.WaitGroup
+	if err := lc.Register(lifecycle.Component{
+		Name:      "health-probes",
+		Priority:  lifecycle.PriorityWorkers,
+		DependsOn: []string{"postgres"},
+		Start: func(context.Context) error {
+			healthDone.Go(func() { healthMonitor.Run(healthCtx) })
+			return nil
+		},
+		Stop: func(context.Context) error {
+			stopHealth()
+			healthDone.Wait()
+			return nil
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// instances rolling out together race on migrations: an advisory lock lets one
+	// apply them while the others wait, so every instance starts on the new schema
+	if appConfig.Migrate.OnStartup {
+		schema, err := migrate.Load(migrations.Schema, migrations.SchemaDir)
+		if err != nil {
+			slog.ErrorContext(ctx, "migrations not loaded", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		migrateCtx, cancelMigrate := context.WithTimeout(ctx, appConfig.Migrate.Timeout)
+		migrateLocker := locking.NewPostgresLocker(connectionPool, locking.WithPostgresKeyPrefix(appConfig.KeyPrefix("locks")))
+		status, err := migrate.New(connectionPool,
+			migrate.WithProduction(appConfig.IsProduction()),
+			migrate.WithForce(appConfig.Migrate.Force),
+		).UpLocked(migrateCtx, migrateLocker, migrate.DefaultLockName, schema)
+		migrateLocker.Close()
+		cancelMigrate()
+		if err != nil {
+			slog.ErrorContext(ctx, "schema migration failed", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		events.Emit(ctx, lifecycle.EventMigrated,
+			slog.Int("applied", len(status.Applied)),
+			slog.Duration("waited", status.Waited),
+			slog.Duration("duration", status.Duration),
+		)
+	}
 
 	signer, err := hmac_sign.NewHMACSigner([]byte(appConfig.HMAC.Secret))
 	if err != nil {
@@ -114,60 +278,326 @@ func main() {
 	}
 
 	// Initialize reader (uses runtime replica selection) and writer (uses prepared statements on primary)
-	reader := persistence.NewPostgresProfileReader(connectionPool, "profiles")
+	// profiles written through this instance are read back from the primary for a short while
+	recentWrites := db.NewRecentWrites(appConfig.Postgres.StickyWindow)
+	reader := persistence.NewPostgresProfileReader(connectionPool, "profiles",
+		persistence.WithStatsView(persistence.DefaultStatsView),
+		persistence.WithReadYourWrites(recentWrites),
+	)
 
-	writer, err := persistence.NewPostgresProfileWriter(ctx, connectionPool, "profiles")
+	writer, err := persistence.NewPostgresProfileWriter(ctx, connectionPool, "profiles",
+		persistence.WithRecentWrites(recentWrites),
+	)
 	if err != nil {
 		slog.ErrorContext(ctx, "profile writer initialization error", slog.Any("error", err))
 		exitCode = 1
 		return
 	}
 
-	otelShutdown, err := telemetry.Init(ctx, appConfig.Otel)
+	// client-side cached replies, flushable from /admin/caches
+	redisLocalCache := redis.NewLocalCache(0)
+	redisClient, err := redis.NewRueidisClient(ctx, appConfig.Redis, redis.WithLocalCache(redisLocalCache))
 	if err != nil {
-		slog.ErrorContext(ctx, "telemetry not properly configured", slog.Any("error", err))
+		slog.ErrorContext(ctx, "redis not properly setup", slog.Any("error", err))
 		exitCode = 1
 		return
 	}
-	defer func() {
-		if err := otelShutdown(ctx); err != nil {
-			slog.ErrorContext(ctx, "telemetry shutdown error", slog.Any("error", err))
+	events.Emit(ctx, lifecycle.EventRedisReady)
+
+	if err := lc.Register(lifecycle.Component{
+		Name:      "redis",
+		Priority:  lifecycle.PriorityStores,
+		DependsOn: []string{"telemetry"},
+		Stop: func(context.Context) error {
+			redisClient.Close()
+			return nil
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// shared "redis degraded" flag, so consumers apply their failure policy without waiting on timeouts
+	watchdogOpts := []redis.WatchdogOption{
+		redis.WithPingObserver(func(ctx context.Context, err error) { redisHealth.Observe(ctx, err) }),
+	}
+	if d := appConfig.Redis.WatchdogMaxBackoff; d > 0 {
+		watchdogOpts = append(watchdogOpts, redis.WithPingBackoff(retry.Policy{BaseDelay: time.Second, MaxDelay: d, Jitter: 0.2}))
+	}
+	redisWatchdog := redis.NewWatchdog(redisClient, watchdogOpts...)
+	watchdogCtx, stopWatchdog := context.WithCancel(context.WithoutCancel(ctx))
+	var watchdogDone sync.WaitGroup
+	if err := lc.Register(lifecycle.Component{
+		Name:      "redis-watchdog",
+		Priority:  lifecycle.PriorityWorkers,
+		DependsOn: []string{"redis"},
+		Start: func(context.Context) error {
+			watchdogDone.Go(func() { redisWatchdog.Run(watchdogCtx) })
+			return nil
+		},
+		Stop: func(context.Context) error {
+			stopWatchdog()
+			watchdogDone.Wait()
+			return nil
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// fleet-wide read-only mode, toggled through /admin/read-only
+	readOnlyOpts := []readonly.Option{readonly.WithRefreshInterval(appConfig.ReadOnly.RefreshInterval)}
+	if appConfig.ReadOnly.Enabled {
+		readOnlyOpts = append(readOnlyOpts, readonly.WithForced(appConfig.ReadOnly.Reason))
+	}
+	readOnly := readonly.NewSwitch(redis.NewReadOnlyStore(redisClient, appConfig.Key("read_only")), readOnlyOpts...)
+	readOnlyCtx, stopReadOnly := context.WithCancel(context.WithoutCancel(ctx))
+	var readOnlyDone sync.WaitGroup
+	if err := lc.Register(lifecycle.Component{
+		Name:      "read-only",
+		Priority:  lifecycle.PriorityWorkers,
+		DependsOn: []string{"redis"},
+		Start: func(context.Context) error {
+			readOnlyDone.Go(func() { readOnly.Run(readOnlyCtx) })
+			return nil
+		},
+		Stop: func(context.Context) error {
+			stopReadOnly()
+			readOnlyDone.Wait()
+			return nil
+		},
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// --- background jobs ---
+	// one node at a time refreshes the stats view, coordinated through redis locks
+	// or, for deployments without redis, postgres advisory locks
+	var (
+		locker   locking.Locker
+		lockDeps []string
+		lockOpts = []locking.Option{locking.WithLogger(slog.Default())}
+	)
+	switch appConfig.Locking.Backend {
+	case locking.BackendPostgres:
+		locker = locking.NewPostgresLocker(connectionPool, locking.WithPostgresKeyPrefix(appConfig.KeyPrefix("locks")))
+		lockDeps = []string{"postgres"}
+	case locking.BackendRedis, "":
+		redisLocker, err := redis.NewRueidisLocker(appConfig.Redis, appConfig.Key("locks"))
+		if err != nil {
+			slog.ErrorContext(ctx, "redis locker not properly setup", slog.Any("error", err))
+			exitCode = 1
+			return
 		}
-	}()
+		locker = redisLocker
+		if appConfig.Breaker.Enabled {
+			locker = resilience.NewLocker(redisLocker, resilience.New("redis.locks", appConfig.Breaker.Options()...))
+		}
+		if appConfig.Locking.Fair {
+			locker = locking.NewFairLocker(locker, redisClient, locking.WithFairKeyPrefix(appConfig.KeyPrefix("locks", "queue")))
+		}
+		lockDeps = []string{"postgres", "redis"}
+		lockOpts = append(lockOpts, locking.WithDegradedFunc(redisWatchdog.Degraded))
+	default:
+		slog.ErrorContext(ctx, "unknown lock backend", slog.String("backend", string(appConfig.Locking.Backend)))
+		exitCode = 1
+		return
+	}
+	switch appConfig.Locking.History {
+	case locking.BackendPostgres:
+		lockOpts = append(lockOpts, locking.WithExecutionRecorder(
+			locking.NewPostgresExecutionStore(connectionPool, locking.DefaultExecutionTable)))
+	case locking.BackendRedis:
+		lockOpts = append(lockOpts, locking.WithExecutionRecorder(
+			locking.NewRedisExecutionStore(redisClient, appConfig.KeyPrefix("jobs"), locking.DefaultExecutionsKept)))
+	}
+	lockExecutor := locking.NewLockingTaskExecutor(locker, lockOpts...)
+	statsRefresher := persistence.NewStatsViewRefresher(connectionPool, persistence.DefaultStatsView)
 
-	redisClient, err := redis.NewRueidisClient(ctx, appConfig.Redis)
+	// jobs of a concurrency group share a fleet-wide semaphore, JOBS_GROUPS sets the limits
+	jobGroups, err := appConfig.Jobs.ConcurrencyGroups(jobs.Groups()...)
 	if err != nil {
-		slog.ErrorContext(ctx, "redis not properly setup", slog.Any("error", err))
+		slog.ErrorContext(ctx, "job groups config error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	groupPermits := locking.NewSemaphore(redisClient, locking.WithSemaphoreKeyPrefix(appConfig.KeyPrefix("jobs", "groups")))
+	scheduler := locking.NewScheduler(lockExecutor, locking.WithConcurrencyGroups(groupPermits, jobGroups...))
+	// jobs are declared in modules/jobs; JOBS_* enables and reschedules them
+	jobRegistry, err := jobs.NewRegistry()
+	if err != nil {
+		slog.ErrorContext(ctx, "job registry error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	if err := jobRegistry.Bind(jobs.StatsRefresh, statsRefresher.Refresh); err != nil {
+		slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
 		exitCode = 1
 		return
 	}
 
-	defer redisClient.Close()
+	// history older than the retention window is dropped, except the rows asOf reads still need
+	if appConfig.AuditRetention > 0 {
+		auditPurger := persistence.NewAuditPurger(connectionPool, persistence.DefaultAuditTable, appConfig.AuditRetention)
+		if err := jobRegistry.Bind(jobs.AuditPurge, func(ctx context.Context) error {
+			_, err := auditPurger.Purge(ctx)
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+	}
 
-	redisCounter := counter.NewInstrumentedRedisCounterStore(redisClient, "dev")
+	// soft-deleted profiles are hard-deleted in paced batches that resume from a checkpoint
+	if appConfig.SoftDeleteRetention > 0 {
+		checkpoints := redis.NewRedisKV(redisClient, redis.WithKeyPrefix(appConfig.KeyPrefix("jobs", "checkpoints")))
+		profilesPurger := persistence.NewSoftDeletePurger(connectionPool, "profiles", appConfig.SoftDeleteRetention,
+			persistence.WithPurgeCheckpoint(checkpoints, jobs.ProfilesPurge),
+			persistence.WithPurgeRate(appConfig.SoftDeletePurgeRate),
+		)
+		if err := jobRegistry.Bind(jobs.ProfilesPurge, func(ctx context.Context) error {
+			_, err := profilesPurger.Purge(ctx)
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+	}
 
-	keyStrategies := map[ratelimit.KeyStrategyId]ratelimit.KeyFunc{
-		"remote_ip": ratelimit.RemoteIpKeyFunc,
+	if err := lc.Register(lifecycle.Component{
+		Name:      "jobs",
+		Priority:  lifecycle.PriorityWorkers,
+		DependsOn: lockDeps,
+		Start: func(context.Context) error {
+			// jobs outlive the start stage timeout
+			return scheduler.Start(context.WithoutCancel(ctx))
+		},
+		Stop: scheduler.Stop,
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	// the scheduler stops a stage earlier, so no task holds a lock by now
+	if err := lc.OnShutdown("locks", lifecycle.PriorityStores, func(context.Context) error {
+		locker.Close()
+		return nil
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+		exitCode = 1
+		return
 	}
 
+	var (
+		sharedCounter rl.CounterStore
+		fallbackOpts  []rl.FallbackOption
+	)
+	switch appConfig.RateLimit.Store {
+	case ratelimit.CounterBackendPostgres:
+		pgCounter := pgcounter.NewPostgresCounterStore(connectionPool, pgcounter.DefaultTable, appConfig.Key())
+		sharedCounter = pgCounter
+		// expired counters are reset in place; the job only keeps the table small
+		if err := jobRegistry.Bind(jobs.CountersPurge, func(ctx context.Context) error {
+			n, err := pgCounter.Purge(ctx)
+			slog.DebugContext(ctx, "ratelimit counters purged", slog.Int64("deleted", n))
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+	case ratelimit.CounterBackendRedis, "":
+		sharedCounter = counter.NewInstrumentedRedisCounterStore(redisClient, appConfig.Key())
+		// repeated redis failures open the breaker instead of costing every request a timeout
+		if appConfig.Breaker.Enabled {
+			sharedCounter = resilience.NewCounterStore(sharedCounter, resilience.New("redis.counter", appConfig.Breaker.Options()...))
+		}
+		fallbackOpts = append(fallbackOpts, rl.WithPrimaryDegraded(redisWatchdog.Degraded))
+	default:
+		slog.ErrorContext(ctx, "unknown rate limit store", slog.String("store", string(appConfig.RateLimit.Store)))
+		exitCode = 1
+		return
+	}
+	httpDeps := []string{"telemetry", "postgres", "redis", "read-only"}
+
+	// keep limiting per node while the counter store is down, carrying the local counters over restarts
+	limiterCounter := sharedCounter
+	if fb := appConfig.RateLimit.Fallback; fb.Enabled {
+		fallback := rl.NewFallbackCounterStore(sharedCounter, rl.NewMemoryCounterStore(clock), fallbackOpts...)
+		limiterCounter = fallback
+
+		if fb.Persist {
+			host, _ := os.Hostname()
+			snapshots := counter.NewSnapshotStore(redisClient, appConfig.Key("ratelimit", "fallback", host))
+			if err := lc.Register(lifecycle.Component{
+				Name:      "ratelimit-fallback",
+				Priority:  lifecycle.PriorityWorkers,
+				DependsOn: []string{"redis"},
+				Start: func(ctx context.Context) error {
+					// stale counters are not worth failing startup over
+					if err := fallback.Restore(ctx, snapshots); err != nil {
+						slog.WarnContext(ctx, "ratelimit fallback counters not restored", slog.Any("error", err))
+					}
+					return nil
+				},
+				Stop: func(ctx context.Context) error {
+					return fallback.Persist(ctx, snapshots)
+				},
+			}); err != nil {
+				slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+				exitCode = 1
+				return
+			}
+			// persisted only once the server stopped counting
+			httpDeps = append(httpDeps, "ratelimit-fallback")
+		}
+	}
+
+	keyStrategies := ratelimit.KeyStrategies(&appConfig.RateLimit)
+
 	slog.Debug("app rate limit config", slog.Any("rate_limit_config", appConfig.RateLimit))
 
+	// TODO: provide same gin framework version example
+	routeInfo := func(r *http.Request) ratelimit.RouteInfo {
+		id := ratelimit.Pattern(r.Pattern)
+		// pattern is empty if request is not matched again a pattern
+		if r.Pattern == "" {
+			id = ratelimit.Pattern(r.URL.Path)
+		}
+		return ratelimit.RouteInfo{
+			ID:     id,
+			Method: r.Method,
+			Path:   r.URL.Path,
+		}
+	}
+
+	var limiterFactory rl.LimiterFactory
+	switch appConfig.RateLimit.Algorithm {
+	case ratelimit.AlgorithmSlidingWindow:
+		limiterFactory = rl.SlidingWindowFactory(clock, limiterCounter, appConfig.Key())
+	case ratelimit.AlgorithmGCRA:
+		if appConfig.RateLimit.Store == ratelimit.CounterBackendPostgres {
+			slog.ErrorContext(ctx, "gcra rate limiting needs the redis store")
+			exitCode = 1
+			return
+		}
+		limiterFactory = gcra.Factory(redisClient, appConfig.Key(), gcra.WithBurst(appConfig.RateLimit.Burst))
+	default:
+		slog.ErrorContext(ctx, "unknown rate limit algorithm", slog.String("algorithm", string(appConfig.RateLimit.Algorithm)))
+		exitCode = 1
+		return
+	}
+
 	rtp, err := ratelimit.ParsePolicy(
-		rl.SlidingWindowFactory(clock, redisCounter, "dev"),
+		limiterFactory,
 		&appConfig.RateLimit,
-		// TODO: provide same gin framework version example
-		func(r *http.Request) ratelimit.RouteInfo {
-			id := ratelimit.Pattern(r.Pattern)
-			// pattern is empty if request is not matched again a pattern
-			if r.Pattern == "" {
-				id = ratelimit.Pattern(r.URL.Path)
-			}
-			return ratelimit.RouteInfo{
-				ID:     id,
-				Method: r.Method,
-				Path:   r.URL.Path,
-			}
-		},
+		routeInfo,
 		keyStrategies,
 	)
 	if err != nil {
@@ -176,12 +606,180 @@ func main() {
 		return
 	}
 
+	if !appConfig.RateLimit.Fallback.Enabled || appConfig.RateLimit.Algorithm == ratelimit.AlgorithmGCRA {
+		// without local counters there is nothing to count with while redis is down,
+		// and GCRA keeps its state in redis only
+		rtp.Degraded = redisWatchdog.Degraded
+	}
 	rateLimitMiddleware := ratelimit.NewRateLimitMiddleware(rtp)
 
+	routePolicy, err := routepolicy.ParsePolicy(&appConfig.RoutePolicy, routeInfo, routepolicy.WithClock(clock))
+	if err != nil {
+		slog.ErrorContext(ctx, "route policy config not properly parsed", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// client-facing problem texts, negotiated from Accept-Language
+	catalog, err := i18n.Default()
+	if err != nil {
+		slog.ErrorContext(ctx, "i18n catalogs not properly loaded", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// scopes declared in the spec's security sections, checked against the request principal
+	var specFS fs.FS = validationSpecFS
+	if appConfig.SpecDir != "" {
+		specFS = os.DirFS(appConfig.SpecDir)
+	}
+	loadProfileSpec := func() (*openapi3.T, error) {
+		return middleware.LoadSpec(specFS, "modules/oapi/openapi-profile.yaml")
+	}
+	profileSpec, err := loadProfileSpec()
+	if err != nil {
+		slog.ErrorContext(ctx, "profile spec not properly loaded", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	scopeMiddleware, err := authz.SpecScopes(profileSpec,
+		authz.WithConfig(appConfig.Authz),
+		authz.WithSpecReload(loadProfileSpec, middleware.SpecGeneration),
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "authz middleware setup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// canonical method and path, so everything after sees the same route
+	routingMiddleware, err := middleware.RoutingHygiene(appConfig.Routing)
+	if err != nil {
+		slog.ErrorContext(ctx, "routing middleware setup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// cache headers per route class, derived from the canonical path
+	cacheMiddleware, err := middleware.CacheHygiene(appConfig.Cache)
+	if err != nil {
+		slog.ErrorContext(ctx, "cache middleware setup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	compressMiddleware, err := middleware.Compress(appConfig.Compression)
+	if err != nil {
+		slog.ErrorContext(ctx, "compression middleware setup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	// profiles read by id are served from redis, with client-side caching on top
+	var (
+		profileReader domain.ProfileReadStore  = reader
+		profileWriter domain.ProfileWriteStore = writer
+	)
+	if appConfig.ProfileCache.Enabled {
+		profileKV := redis.NewRedisKV(redisClient,
+			redis.WithKeyPrefix(appConfig.KeyPrefix("cache")),
+			redis.WithDefaultTTL(appConfig.ProfileCache.TTL),
+			redis.WithClientSideCache(),
+			redis.WithDegradedMode(redisWatchdog.Degraded, redis.FailOpen),
+		)
+		profileReader = profilecache.NewCachedProfileReader(reader, profileKV)
+		profileWriter = profilecache.NewCachedProfileWriter(writer, profileKV)
+	}
+
 	// --- application layer ---
 
+	appOpts := []domain.AppOption{
+		domain.WithAuditRetention(appConfig.AuditRetention),
+		domain.WithMaxPageSize(appConfig.Pagination.MaxPageSize),
+	}
+	// business events for product dashboards, next to the HTTP metrics
+	if businessMetrics, err := telemetry.NewBusinessMetrics("profile-api"); err != nil {
+		slog.WarnContext(ctx, "failed to initialize business metrics, continuing without them", slog.Any("error", err))
+	} else {
+		appOpts = append(appOpts, domain.WithBusinessMetrics(businessMetrics))
+	}
 	profileApi := profile_http.NewProfileService(
-		reader, writer, signer)
+		profileReader, profileWriter, signer, appOpts...)
+
+	// --- sagas ---
+	// signups span the profile and payment services, see core/onboarding; payment
+	// accounts are kept in memory until an adapter for the payment provider exists
+	if appConfig.Saga.Enabled {
+		// every instance locks on its own name, which would flood the job history
+		sagaLockOpts := []locking.Option{locking.WithNameHashing(locking.HashIfTooLong)}
+		if appConfig.Locking.Backend != locking.BackendPostgres {
+			sagaLockOpts = append(sagaLockOpts, locking.WithDegradedFunc(redisWatchdog.Degraded))
+		}
+		signups, err := onboarding.NewSaga(
+			pgsaga.NewPostgresStore(connectionPool, pgsaga.DefaultTable),
+			locking.NewLockingTaskExecutor(locker, sagaLockOpts...),
+			domain.NewApp(profileReader, profileWriter, signer, appOpts...),
+			paymentmemory.NewAccounts(nil),
+			appConfig.Saga.Options()...,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "saga setup error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		if err := jobRegistry.Bind(jobs.SagaResume, func(ctx context.Context) error {
+			_, err := signups.Resume(ctx)
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		if err := lc.Register(lifecycle.Component{
+			Name:      "sagas",
+			Priority:  lifecycle.PriorityWorkers,
+			DependsOn: lockDeps,
+			Start: func(context.Context) error {
+				// instances outlive the start stage timeout
+				return signups.Start(context.WithoutCancel(ctx))
+			},
+			Stop: signups.Stop,
+		}); err != nil {
+			slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+	}
+
+	// --- delivery journal ---
+	// outbound deliveries are journaled in postgres, so operators can requeue the failed ones
+	var deliveryServices []server.RegistrableService
+	if appConfig.Delivery.Enabled {
+		journal := delivery.New(pgdelivery.NewPostgresStore(connectionPool, pgdelivery.DefaultTable),
+			delivery.WithRetention(appConfig.Delivery.Retention),
+		)
+		if err := jobRegistry.Bind(jobs.DeliveryPurge, func(ctx context.Context) error {
+			n, err := journal.Purge(ctx)
+			slog.DebugContext(ctx, "deliveries purged", slog.Int64("deleted", n))
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		// deliveries whose sender stopped before reporting, or requeued by an operator
+		redeliver := httpclient.DeliverySender(httpclient.New(httpclient.WithRequestID()))
+		if err := jobRegistry.Bind(jobs.DeliveryDispatch, func(ctx context.Context) error {
+			n, err := journal.Dispatch(ctx, redeliver, appConfig.Delivery.DispatchBatch)
+			slog.DebugContext(ctx, "deliveries dispatched", slog.Int("succeeded", n))
+			return err
+		}); err != nil {
+			slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		deliveryServices = append(deliveryServices, services.NewDeliveryService(journal, appConfig.ReadOnly.AdminScope))
+	}
 
 	// Initialize HTTP metrics for middleware-based instrumentation
 	httpMetrics, err := telemetry.NewHTTPMetrics("profile-api")
@@ -190,22 +788,135 @@ func main() {
 		httpMetrics = nil
 	}
 
-	profileSvc := services.NewProfileAPIService(
+	// bearer tokens and API keys resolve to a principal; the validator then enforces
+	// the spec's security
+	var (
+		jwtAuth    *authmw.Authenticator
+		validation []middleware.ValidationOption
+	)
+	if appConfig.JWT.Enabled {
+		jwtAuth, err = authmw.FromConfig(appConfig.JWT)
+		if err != nil {
+			slog.ErrorContext(ctx, "jwt authentication setup error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+	}
+	// service callers authenticate with API keys stored in postgres
+	var apiKeys *apikey.Keys
+	if appConfig.APIKeys.Enabled {
+		apiKeys = apikey.New(pgapikey.NewPostgresStore(connectionPool, pgapikey.DefaultTable))
+	}
+	if jwtAuth != nil || apiKeys != nil {
+		validation = append(validation, middleware.WithAuthenticationFunc(authmw.AuthenticationFunc()))
+	}
+
+	profileSvc, err := services.NewProfileService(
+		appConfig.Server.Router,
 		profileApi,
-		validationSpecFS,
+		specFS,
 		// TODO: fail fast when file not exists
 		"modules/oapi/openapi-profile.yaml",
+		validation...,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "profile service setup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	globalMiddlewares := []func(http.Handler) http.Handler{
+		routingMiddleware,
+		middleware.RequestID(),
+		middleware.AccessLog(appConfig.AccessLog),
+		middleware.Telemetry(httpMetrics),
+		// inside the recorders, so they count the bytes on the wire
+		compressMiddleware,
+		cacheMiddleware,
+		middleware.LocalizeProblems(catalog),
+	}
+	// signed service-to-service calls resolve to a principal before limits and scopes apply
+	if appConfig.Signature.Enabled {
+		verifier, err := httpsig.VerifierFromConfig(appConfig.Signature,
+			redis.NewNonceCache(redisClient, appConfig.KeyPrefix("nonce", "httpsig")))
+		if err != nil {
+			slog.ErrorContext(ctx, "request signature setup error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		globalMiddlewares = append(globalMiddlewares, middleware.VerifySignatures(verifier, appConfig.Signature.Required))
+	}
+	if jwtAuth != nil {
+		globalMiddlewares = append(globalMiddlewares, authmw.Middleware(jwtAuth))
+	}
+	if apiKeys != nil {
+		globalMiddlewares = append(globalMiddlewares, apikey.Middleware(apiKeys, appConfig.APIKeys.Header))
+	}
+	// saved list defaults are keyed by the principal, so they load once it is known
+	prefsStore := preferences.NewStore(
+		redis.NewRedisKV(redisClient, redis.WithKeyPrefix(appConfig.KeyPrefix("preferences"))),
+		preferences.WithValidator(func(p preferences.Preferences) error {
+			if p.Sort == "" {
+				return nil
+			}
+			_, err := domain.ParseProfileSort(p.Sort)
+			return err
+		}),
+	)
+	globalMiddlewares = append(globalMiddlewares,
+		preferences.Middleware(prefsStore),
+		rateLimitMiddleware,
+		middleware.ReadOnly(readOnly, "/admin/"),
+		// roles expand into scopes for the spec check and for authz.Require in handlers
+		authz.Middleware(authz.EnforcerFromConfig(appConfig.Authz)),
+		scopeMiddleware,
+	)
+	// retried POSTs replay the first response; while redis is down they run unprotected
+	if cfg := appConfig.Idempotency; cfg.Enabled {
+		idempotencyKV := redis.NewRedisKV(redisClient,
+			redis.WithKeyPrefix(appConfig.KeyPrefix("idempotency")),
+			redis.WithDefaultTTL(cfg.TTL),
+			redis.WithDegradedMode(redisWatchdog.Degraded, redis.FailOpen),
+		)
+		globalMiddlewares = append(globalMiddlewares, middleware.Idempotency(idempotencyKV, cfg))
+	}
+	globalMiddlewares = append(globalMiddlewares,
+		routepolicy.NewRoutePolicyMiddleware(routePolicy),
+		profile_http.RecoverHTTPMiddleware(),
+		// innermost, to see the pattern the server mux matched
+		middleware.NoteRoute,
 	)
 
+	var apiKeyServices []server.RegistrableService
+	if apiKeys != nil {
+		apiKeyServices = append(apiKeyServices, services.NewAPIKeyService(apiKeys, appConfig.ReadOnly.AdminScope))
+	}
+
 	server, err := server.New(
 		"0.0.0.0", 8080,
 		server.WithWriteTimeout(10*time.Second),
-		server.WithServices(profileSvc),
-		server.WithGlobalMiddlewares(
-			middleware.Telemetry(httpMetrics),
-			rateLimitMiddleware,
-			profile_http.RecoverHTTPMiddleware(),
+		server.WithReadHeaderTimeout(appConfig.Server.ReadHeaderTimeout),
+		server.WithIdleTimeout(appConfig.Server.IdleTimeout),
+		server.WithMaxHeaderBytes(appConfig.Server.MaxHeaderBytes),
+		server.WithTLSFromConfig(appConfig.Server.TLS),
+		server.WithProtocolsFromConfig(appConfig.Server),
+		server.WithServices(
+			// a v2 spec mounts next to it with server.Versioned("v2", ...) over the same profileApi
+			server.Versioned("v1", profileSvc, appConfig.Server.V1),
+			services.NewAdminService(readOnly, appConfig.ReadOnly.AdminScope,
+				admin.Cache{Name: "openapi-specs", Clear: func(context.Context) (int, error) {
+					return middleware.ResetSpecCache(), nil
+				}},
+				admin.Cache{Name: "redis-local", Clear: func(context.Context) (int, error) {
+					return redisLocalCache.Flush(), nil
+				}},
+			),
+			services.NewHealthService(healthMonitor),
+			services.NewPreferencesService(prefsStore),
 		),
+		server.WithServices(apiKeyServices...),
+		server.WithServices(deliveryServices...),
+		server.WithGlobalMiddlewares(globalMiddlewares...),
 	)
 	if err != nil {
 		slog.ErrorContext(ctx, "init server error", slog.Any("error", err))
@@ -213,9 +924,90 @@ func main() {
 		return
 	}
 
-	if err := server.Run(ctx); err != nil {
-		slog.ErrorContext(ctx, "running server error", slog.Any("error", err))
+	healthMonitor.OnDrain(func(context.Context) { server.Drain() })
+	if err := lc.Register(lifecycle.Component{
+		Name:      "http-server",
+		Priority:  lifecycle.PriorityIngress,
+		DependsOn: httpDeps,
+		Start:     server.Start,
+		// readiness fails first, so load balancers stop routing before the listener closes
+		Stop: func(ctx context.Context) error {
+			healthMonitor.Drain(ctx)
+			select {
+			case <-time.After(probe.DrainDelay):
+			case <-ctx.Done():
+			}
+			return server.Shutdown(ctx)
+		},
+		StopTimeout: probe.DrainDelay + 10*time.Second,
+	}); err != nil {
+		slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
 		exitCode = 1
 		return
 	}
+
+	// the profile use cases over gRPC, next to the REST API
+	var grpcErr <-chan error
+	if appConfig.GRPC.Enabled {
+		grpcOpts := []grpcserver.Option{
+			grpcserver.WithService(&grpc_profile_api.ProfileService_ServiceDesc,
+				profile_grpc.NewProfileServer(profileReader, profileWriter, signer, appOpts...)),
+			grpcserver.WithErrorMapper(profile_grpc.ProblemFromError),
+			grpcserver.WithReadiness(healthMonitor.Ready),
+			grpcserver.WithReflection(appConfig.GRPC.Reflection),
+		}
+		if jwtAuth != nil {
+			grpcOpts = append(grpcOpts, grpcserver.WithUnaryInterceptors(grpcserver.Authenticate(jwtAuth)))
+		}
+		grpcOpts = append(grpcOpts, grpcserver.WithUnaryInterceptors(grpcserver.WithEnforcer(authz.EnforcerFromConfig(appConfig.Authz))))
+		if appConfig.GRPC.RateLimit > 0 {
+			grpcOpts = append(grpcOpts, grpcserver.WithUnaryInterceptors(grpcserver.RateLimit(
+				limiterFactory(appConfig.GRPC.RateLimit, appConfig.GRPC.RateWindow), grpcserver.CallerKey)))
+		}
+		grpcOpts = append(grpcOpts, grpcserver.WithUnaryInterceptors(
+			grpcserver.RequireScopes(profile_grpc.MethodScopes, appConfig.Authz.Enabled)))
+
+		grpcServer, err := grpcserver.New(appConfig.GRPC.Addr, grpcOpts...)
+		if err != nil {
+			slog.ErrorContext(ctx, "init grpc server error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		if err := lc.Register(lifecycle.Component{
+			Name:      "grpc-server",
+			Priority:  lifecycle.PriorityIngress,
+			DependsOn: httpDeps,
+			Start:     grpcServer.Start,
+			Stop:      grpcServer.Shutdown,
+		}); err != nil {
+			slog.ErrorContext(ctx, "lifecycle registration error", slog.Any("error", err))
+			exitCode = 1
+			return
+		}
+		grpcErr = grpcServer.Err()
+	}
+
+	// every task is bound by now
+	if err := jobRegistry.AddTo(scheduler, appConfig.Jobs); err != nil {
+		slog.ErrorContext(ctx, "job registration error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+
+	if err := lc.Start(ctx); err != nil {
+		slog.ErrorContext(ctx, "startup error", slog.Any("error", err))
+		exitCode = 1
+		return
+	}
+	events.Emit(ctx, lifecycle.EventServerListening, slog.String("addr", server.Addr()))
+
+	select {
+	case <-ctx.Done():
+	case err := <-server.Err():
+		slog.ErrorContext(ctx, "running server error", slog.Any("error", err))
+		exitCode = 1
+	case err := <-grpcErr:
+		slog.ErrorContext(ctx, "running grpc server error", slog.Any("error", err))
+		exitCode = 1
+	}
 }
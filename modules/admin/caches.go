@@ -0,0 +1,112 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"app/modules/auth"
+	"app/modules/authz"
+	"app/modules/middleware/problem"
+)
+
+type (
+	// Cache is an in-process cache an operator can clear.
+	Cache struct {
+		Name string
+		// Clear drops the cached entries and returns how many were dropped.
+		Clear func(ctx context.Context) (int, error)
+	}
+
+	// ClearResult reports the outcome of clearing one cache.
+	ClearResult struct {
+		Name    string `json:"name"`
+		Cleared int    `json:"cleared"`
+		Error   string `json:"error,omitempty"`
+	}
+)
+
+// CachesHandler serves the caches for operators: GET lists their names, POST clears
+// all of them or only those named by repeated ?name= parameters. Both require scope.
+//
+// POST answers 200 with one ClearResult per cache, or 500 with the same body when
+// any of them failed; an unknown name is rejected with 404 before anything is cleared.
+func CachesHandler(scope string, caches ...Cache) http.Handler {
+	names := make([]string, 0, len(caches))
+	for _, c := range caches {
+		names = append(names, c.Name)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.Require(r.Context(), scope); err != nil {
+			authz.WriteProblem(w, err)
+			return
+		}
+		principal, _ := auth.PrincipalFrom(r.Context())
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			writeJSON(w, http.StatusOK, map[string][]string{"caches": names})
+		case http.MethodPost:
+			selected := r.URL.Query()["name"]
+			for _, name := range selected {
+				if !slices.Contains(names, name) {
+					problem.Write(w, problem.NotFound("unknown cache "+name,
+						problem.WithExtension("caches", names),
+					))
+					return
+				}
+			}
+
+			status := http.StatusOK
+			results := make([]ClearResult, 0, len(caches))
+			for _, c := range caches {
+				if len(selected) > 0 && !slices.Contains(selected, c.Name) {
+					continue
+				}
+				res := ClearResult{Name: c.Name}
+				n, err := c.Clear(r.Context())
+				res.Cleared = n
+				if err != nil {
+					res.Error = err.Error()
+					status = http.StatusInternalServerError
+					slog.ErrorContext(r.Context(), "admin: cache not cleared", slog.String("cache", c.Name), slog.Any("error", err))
+				}
+				results = append(results, res)
+			}
+			slog.InfoContext(r.Context(), "admin: caches cleared by operator",
+				slog.String("subject", principal.Subject),
+				slog.String("caches", strings.Join(selected, ",")),
+				slog.Any("results", results),
+			)
+			writeJSON(w, status, map[string][]ClearResult{"caches": results})
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+		}
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,27 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin holds operator endpoints that act on process-wide state.
+//
+// CachesHandler lets an operator drop in-process caches after a hotfix so that
+// edited specs or data take effect without a restart:
+//
+//	mux.Handle("/admin/caches", admin.CachesHandler("profiles:admin",
+//		admin.Cache{Name: "openapi-specs", Clear: ...},
+//		admin.Cache{Name: "redis-local", Clear: ...},
+//	))
+//
+//	curl -X POST /admin/caches                     # every cache
+//	curl -X POST '/admin/caches?name=openapi-specs' # only the specs
+package admin
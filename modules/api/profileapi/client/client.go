@@ -0,0 +1,233 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	api "app/modules/api/profileapi/stdlib"
+)
+
+// ErrRetryAfterTooLong is returned when the server asks to wait longer than the
+// configured maximum before retrying.
+var ErrRetryAfterTooLong = errors.New("client: retry-after exceeds the maximum wait")
+
+type (
+	// Client calls the profile API under a base URL.
+	Client struct {
+		Profiles *ProfilesService
+
+		baseURL *url.URL
+		http    *http.Client
+		editors []RequestEditorFn
+
+		maxRetries int
+		// waits longer than this are not retried
+		maxWait time.Duration
+		// used when a 429/503 carries no (valid) Retry-After
+		defaultWait time.Duration
+	}
+
+	// RequestEditorFn changes every outgoing request, e.g. to add credentials.
+	RequestEditorFn func(ctx context.Context, req *http.Request) error
+
+	// Option configures a Client.
+	Option func(*Client)
+
+	// Error is a non-2xx response. Problem is the decoded problem details body,
+	// zero when the server sent something else.
+	Error struct {
+		StatusCode int
+		Problem    api.Problem
+	}
+)
+
+func (e *Error) Error() string {
+	msg := http.StatusText(e.StatusCode)
+	if e.Problem.Detail != nil {
+		msg = *e.Problem.Detail
+	}
+	if e.Problem.Code != nil {
+		return fmt.Sprintf("client: %d %s (%s)", e.StatusCode, msg, *e.Problem.Code)
+	}
+	return fmt.Sprintf("client: %d %s", e.StatusCode, msg)
+}
+
+// WithHTTPClient sets the underlying http.Client. Defaults to a client with a 30s timeout.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.http = hc
+		}
+	}
+}
+
+// WithRequestEditor adds fn to the editors applied to every request, in order.
+func WithRequestEditor(fn RequestEditorFn) Option {
+	return func(c *Client) {
+		if fn != nil {
+			c.editors = append(c.editors, fn)
+		}
+	}
+}
+
+// WithMaxRetries sets how many times a throttled request is retried. Defaults to 3;
+// zero disables retries.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+	}
+}
+
+// WithMaxRetryWait caps the Retry-After delay the client is willing to wait.
+// A longer delay fails the call with ErrRetryAfterTooLong. Defaults to 1 minute.
+func WithMaxRetryWait(d time.Duration) Option {
+	return func(c *Client) {
+		if d > 0 {
+			c.maxWait = d
+		}
+	}
+}
+
+// New constructs a Client for the API served at baseURL (scheme, host and an
+// optional path prefix in front of /v1).
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("client: parse base url: %w", err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("client: base url %q must be absolute", baseURL)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+
+	c := &Client{
+		baseURL:     u,
+		http:        &http.Client{Timeout: 30 * time.Second},
+		maxRetries:  3,
+		maxWait:     time.Minute,
+		defaultWait: time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	c.Profiles = &ProfilesService{c: c}
+	return c, nil
+}
+
+// get issues a GET for path?query and decodes a 2xx JSON body into out,
+// retrying throttled attempts. It returns the response headers.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) (http.Header, error) {
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("client: build request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		for _, edit := range c.editors {
+			if err := edit(ctx, req); err != nil {
+				return nil, fmt.Errorf("client: edit request: %w", err)
+			}
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("client: GET %s: %w", path, err)
+		}
+
+		if retryable(resp.StatusCode) && attempt < c.maxRetries {
+			wait := c.retryAfter(resp.Header.Get("Retry-After"))
+			drain(resp)
+			if wait > c.maxWait {
+				return nil, fmt.Errorf("%w: %s", ErrRetryAfterTooLong, wait)
+			}
+			if err := sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		defer drain(resp)
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return nil, decodeError(resp)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return nil, fmt.Errorf("client: decode %s: %w", path, err)
+		}
+		return resp.Header, nil
+	}
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// retryAfter parses a Retry-After value, either delay-seconds or an HTTP date.
+func (c *Client) retryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return c.defaultWait
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return c.defaultWait
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func decodeError(resp *http.Response) error {
+	e := &Error{StatusCode: resp.StatusCode}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/problem+json") {
+		// a malformed body still reports the status
+		_ = json.NewDecoder(resp.Body).Decode(&e.Problem)
+	}
+	return e
+}
+
+// drain lets the transport reuse the connection.
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
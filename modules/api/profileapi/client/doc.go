@@ -0,0 +1,37 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package client is a small Go client for the profile API.
+//
+// It covers what programmatic consumers need beyond the generated server types,
+// mainly walking a list without handling cursors by hand:
+//
+//	c, err := client.New("http://localhost:8080",
+//		client.WithRequestEditor(func(_ context.Context, r *http.Request) error {
+//			r.Header.Set("Authorization", "Bearer "+token)
+//			return nil
+//		}),
+//	)
+//
+//	for page, err := range c.Profiles.List(ctx, client.ListOptions{Limit: 50}) {
+//		if err != nil {
+//			return err
+//		}
+//		for _, p := range page.Items { ... }
+//	}
+//
+// List follows nextCursor until a page comes back without one. Requests rejected
+// with 429 (or 503) are retried after the Retry-After delay the server sent; any
+// other non-2xx response ends the iteration with an *Error carrying the problem.
+package client
@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+
+	api "app/modules/api/profileapi/stdlib"
+)
+
+// DefaultLimit is the page size List uses when ListOptions.Limit is not set.
+const DefaultLimit = 20
+
+type (
+	// ProfilesService groups the /v1/profiles calls.
+	ProfilesService struct {
+		c *Client
+	}
+
+	// ListOptions configures a cursor walk over the profile list.
+	ListOptions struct {
+		// Limit is the page size, DefaultLimit when zero.
+		Limit int
+		// After resumes a previous walk from its last nextCursor.
+		After string
+	}
+
+	// Page is one list response.
+	Page struct {
+		Items []api.Profile
+		Meta  api.CursorMeta
+		// ETag of the collection page
+		ETag string
+	}
+)
+
+// List walks the profile list page by page, newest first, following nextCursor
+// until the last page. The walk stops at the first error, which is yielded with a
+// nil page; breaking out of the loop stops it as well.
+func (s *ProfilesService) List(ctx context.Context, opts ListOptions) iter.Seq2[*Page, error] {
+	return func(yield func(*Page, error) bool) {
+		after := opts.After
+		for {
+			page, err := s.listPage(ctx, opts.limit(), after)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+			if page.Meta.NextCursor == nil || *page.Meta.NextCursor == "" || len(page.Items) == 0 {
+				return
+			}
+			after = *page.Meta.NextCursor
+		}
+	}
+}
+
+// All is List flattened to single profiles.
+func (s *ProfilesService) All(ctx context.Context, opts ListOptions) iter.Seq2[api.Profile, error] {
+	return func(yield func(api.Profile, error) bool) {
+		for page, err := range s.List(ctx, opts) {
+			if err != nil {
+				yield(api.Profile{}, err)
+				return
+			}
+			for _, p := range page.Items {
+				if !yield(p, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *ProfilesService) listPage(ctx context.Context, limit int, after string) (*Page, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	if after != "" {
+		q.Set("after", after)
+	}
+
+	var body api.SuccessProfileList
+	h, err := s.c.get(ctx, "/v1/profiles", q, &body)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := body.Meta.AsCursorMeta()
+	if err != nil {
+		return nil, fmt.Errorf("client: decode cursor meta: %w", err)
+	}
+	return &Page{Items: body.Data, Meta: meta, ETag: h.Get("ETag")}, nil
+}
+
+func (o ListOptions) limit() int {
+	if o.Limit > 0 {
+		return o.Limit
+	}
+	return DefaultLimit
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -18,6 +19,10 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	Oauth2Scopes = "oauth2.Scopes"
+)
+
 // Defines values for CursorMetaMode.
 const (
 	Cursor CursorMetaMode = "cursor"
@@ -28,8 +33,50 @@ const (
 	Offset OffsetMetaMode = "offset"
 )
 
+// Defines values for IfNoneMatchAny.
+const (
+	IfNoneMatchAnyAsterisk IfNoneMatchAny = "*"
+)
+
+// Defines values for Sort.
+const (
+	SortAge            Sort = "age"
+	SortCreatedAt      Sort = "createdAt"
+	SortMinusAge       Sort = "-age"
+	SortMinusCreatedAt Sort = "-createdAt"
+	SortMinusName      Sort = "-name"
+	SortName           Sort = "name"
+)
+
+// Defines values for ListProfilesParamsSort.
+const (
+	ListProfilesParamsSortAge            ListProfilesParamsSort = "age"
+	ListProfilesParamsSortCreatedAt      ListProfilesParamsSort = "createdAt"
+	ListProfilesParamsSortMinusAge       ListProfilesParamsSort = "-age"
+	ListProfilesParamsSortMinusCreatedAt ListProfilesParamsSort = "-createdAt"
+	ListProfilesParamsSortMinusName      ListProfilesParamsSort = "-name"
+	ListProfilesParamsSortName           ListProfilesParamsSort = "name"
+)
+
+// Defines values for UpdateProfileParamsIfNoneMatch.
+const (
+	UpdateProfileParamsIfNoneMatchAsterisk UpdateProfileParamsIfNoneMatch = "*"
+)
+
+// AgeBucketCount defines model for AgeBucketCount.
+type AgeBucketCount struct {
+	// Bucket Bucket label, `unknown` for profiles without an age
+	Bucket string                 `json:"bucket"`
+	Count  int                    `json:"count"`
+	MaxAge nullable.Nullable[int] `json:"maxAge,omitempty"`
+	MinAge nullable.Nullable[int] `json:"minAge,omitempty"`
+}
+
 // CursorMeta defines model for CursorMeta.
 type CursorMeta struct {
+	// Clamped True when limit was lowered to the server maximum
+	Clamped *bool `json:"clamped,omitempty"`
+
 	// Etags Mapping of item UUIDs to their ETags for optimistic concurrency control
 	Etags *map[string]string `json:"etags,omitempty"`
 	Limit int                `json:"limit"`
@@ -47,11 +94,25 @@ type CursorMeta struct {
 // CursorMetaMode defines model for CursorMeta.Mode.
 type CursorMetaMode string
 
-// ETagValue defines model for ETagValue.
+// DailySignups defines model for DailySignups.
+type DailySignups struct {
+	Count int                `json:"count"`
+	Date  openapi_types.Date `json:"date"`
+}
+
+// ETagValue Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
 type ETagValue = string
 
+// EmailAvailability defines model for EmailAvailability.
+type EmailAvailability struct {
+	Available bool `json:"available"`
+}
+
 // OffsetMeta defines model for OffsetMeta.
 type OffsetMeta struct {
+	// Clamped True when pageSize was lowered to the server maximum
+	Clamped *bool `json:"clamped,omitempty"`
+
 	// Etags Mapping of item UUIDs to their ETags for optimistic concurrency control
 	Etags *map[string]string `json:"etags,omitempty"`
 	Links *struct {
@@ -93,13 +154,73 @@ type Problem struct {
 
 // Profile defines model for Profile.
 type Profile struct {
-	Age       *string                                `json:"age,omitempty"`
-	CreatedAt *time.Time                             `json:"createdAt,omitempty"`
+	Age       *string    `json:"age,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+
+	// DeletedAt Set on soft-deleted profiles, only listed with includeDeleted
+	DeletedAt *time.Time                             `json:"deletedAt,omitempty"`
 	Email     nullable.Nullable[openapi_types.Email] `json:"email,omitempty"`
 	Id        openapi_types.UUID                     `json:"id"`
 	Name      string                                 `json:"name"`
 }
 
+// ProfileCount defines model for ProfileCount.
+type ProfileCount struct {
+	Count int `json:"count"`
+}
+
+// ProfilePatch defines model for ProfilePatch.
+type ProfilePatch struct {
+	Age   nullable.Nullable[string] `json:"age,omitempty"`
+	Email *openapi_types.Email      `json:"email,omitempty"`
+	Name  nullable.Nullable[string] `json:"name,omitempty"`
+}
+
+// ProfilePatchItem defines model for ProfilePatchItem.
+type ProfilePatchItem struct {
+	Id openapi_types.UUID `json:"id"`
+
+	// IfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+	IfMatch ETagValue    `json:"ifMatch"`
+	Patch   ProfilePatch `json:"patch"`
+}
+
+// ProfilePatchResult defines model for ProfilePatchResult.
+type ProfilePatchResult struct {
+	Data *Profile `json:"data,omitempty"`
+
+	// Etag Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+	Etag    *ETagValue         `json:"etag,omitempty"`
+	Id      openapi_types.UUID `json:"id"`
+	Problem *Problem           `json:"problem,omitempty"`
+
+	// Status 200 when the patch was applied, 424 when it was rolled back because another item failed, the status of the problem otherwise
+	Status int `json:"status"`
+}
+
+// ProfilePatchResults defines model for ProfilePatchResults.
+type ProfilePatchResults struct {
+	Data []ProfilePatchResult `json:"data"`
+}
+
+// ProfileStats defines model for ProfileStats.
+type ProfileStats struct {
+	ByAge []AgeBucketCount `json:"byAge"`
+
+	// SignupsPerDay Days without signups are omitted
+	SignupsPerDay []DailySignups `json:"signupsPerDay"`
+	Total         int            `json:"total"`
+}
+
+// SuccessEmailAvailability defines model for SuccessEmailAvailability.
+type SuccessEmailAvailability struct {
+	Data EmailAvailability `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
 // SuccessEnvelopeList defines model for SuccessEnvelopeList.
 type SuccessEnvelopeList struct {
 	Data []interface{}  `json:"data"`
@@ -124,21 +245,72 @@ type SuccessProfile struct {
 	} `json:"meta"`
 }
 
+// SuccessProfileCount defines model for SuccessProfileCount.
+type SuccessProfileCount struct {
+	Data ProfileCount `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
 // SuccessProfileList defines model for SuccessProfileList.
 type SuccessProfileList struct {
 	Data []Profile      `json:"data"`
 	Meta PaginationMeta `json:"meta"`
 }
 
+// SuccessProfileStats defines model for SuccessProfileStats.
+type SuccessProfileStats struct {
+	Data ProfileStats `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
+// AsOf defines model for AsOf.
+type AsOf = time.Time
+
+// CreatedFrom defines model for CreatedFrom.
+type CreatedFrom = time.Time
+
+// CreatedTo defines model for CreatedTo.
+type CreatedTo = time.Time
+
 // CursorAfter defines model for CursorAfter.
 type CursorAfter = string
 
 // CursorBefore defines model for CursorBefore.
 type CursorBefore = string
 
+// EmailDomain defines model for EmailDomain.
+type EmailDomain = string
+
+// IfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+type IfMatch = ETagValue
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// IfNoneMatchAny defines model for IfNoneMatchAny.
+type IfNoneMatchAny string
+
+// IncludeDeleted defines model for IncludeDeleted.
+type IncludeDeleted = bool
+
 // Limit defines model for Limit.
 type Limit = int
 
+// MaxAge defines model for MaxAge.
+type MaxAge = int
+
+// MinAge defines model for MinAge.
+type MinAge = int
+
+// NamePrefix defines model for NamePrefix.
+type NamePrefix = string
+
 // Page defines model for Page.
 type Page = int
 
@@ -148,9 +320,12 @@ type PageSize = int
 // ProfileId defines model for ProfileId.
 type ProfileId = openapi_types.UUID
 
-// RequiredIfMatch defines model for RequiredIfMatch.
+// RequiredIfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
 type RequiredIfMatch = ETagValue
 
+// Sort defines model for Sort.
+type Sort string
+
 // PreconditionFailedResponse defines model for PreconditionFailedResponse.
 type PreconditionFailedResponse = Problem
 
@@ -164,10 +339,16 @@ type CreateProfile struct {
 }
 
 // ModifyProfile defines model for ModifyProfile.
-type ModifyProfile struct {
-	Age   nullable.Nullable[string] `json:"age,omitempty"`
-	Email *openapi_types.Email      `json:"email,omitempty"`
-	Name  nullable.Nullable[string] `json:"name,omitempty"`
+type ModifyProfile = ProfilePatch
+
+// ModifyProfiles defines model for ModifyProfiles.
+type ModifyProfiles struct {
+	Items []ProfilePatchItem `json:"items"`
+}
+
+// UpsertProfile defines model for UpsertProfile.
+type UpsertProfile struct {
+	Name string `json:"name"`
 }
 
 // ListProfilesParams defines parameters for ListProfiles.
@@ -175,7 +356,7 @@ type ListProfilesParams struct {
 	// Page 0-based page number (use with `pageSize`)
 	Page *Page `form:"page,omitempty" json:"page,omitempty"`
 
-	// PageSize Page size (use with `page`)
+	// PageSize Page size (use with `page`). Sizes above the server maximum (100 by default) are lowered to it and `meta.clamped` is set.
 	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 
 	// After Opaque cursor returned by the previous response (use with `limit`)
@@ -184,8 +365,43 @@ type ListProfilesParams struct {
 	// Before Opaque cursor returned by the previous response (use with `limit`)
 	Before *CursorBefore `form:"before,omitempty" json:"before,omitempty"`
 
-	// Limit Page size for cursor pagination (use with `cursor`)
+	// Limit Page size for cursor pagination (use with `cursor`). Sizes above the server maximum (100 by default) are lowered to it and `meta.clamped` is set.
 	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Sort Order of the list, `-` for descending; ties are broken by id. Names compare case-insensitively. Defaults to `-createdAt`, newest first.
+	Sort *ListProfilesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// NamePrefix Only profiles whose name starts with this prefix (case-insensitive)
+	NamePrefix *NamePrefix `form:"namePrefix,omitempty" json:"namePrefix,omitempty"`
+
+	// MinAge Minimum age (inclusive)
+	MinAge *MinAge `form:"minAge,omitempty" json:"minAge,omitempty"`
+
+	// MaxAge Maximum age (inclusive)
+	MaxAge *MaxAge `form:"maxAge,omitempty" json:"maxAge,omitempty"`
+
+	// CreatedFrom Only profiles created at or after this instant
+	CreatedFrom *CreatedFrom `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only profiles created before this instant
+	CreatedTo *CreatedTo `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// EmailDomain Only profiles whose email is in this domain (case-insensitive)
+	EmailDomain *EmailDomain `form:"emailDomain,omitempty" json:"emailDomain,omitempty"`
+
+	// IncludeDeleted Also list soft-deleted profiles (requires the profiles:admin scope)
+	IncludeDeleted *IncludeDeleted `form:"includeDeleted,omitempty" json:"includeDeleted,omitempty"`
+
+	// IfNoneMatch Entity tags from earlier responses, or `*`. When one is still current the server answers 304 without a body.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// ListProfilesParamsSort defines parameters for ListProfiles.
+type ListProfilesParamsSort string
+
+// ModifyProfilesJSONBody defines parameters for ModifyProfiles.
+type ModifyProfilesJSONBody struct {
+	Items []ProfilePatchItem `json:"items"`
 }
 
 // CreateProfileJSONBody defines parameters for CreateProfile.
@@ -194,17 +410,50 @@ type CreateProfileJSONBody struct {
 	Name  string               `json:"name"`
 }
 
+// UpsertProfileJSONBody defines parameters for UpsertProfile.
+type UpsertProfileJSONBody struct {
+	Name string `json:"name"`
+}
+
+// UpsertProfileParams defines parameters for UpsertProfile.
+type UpsertProfileParams struct {
+	Email openapi_types.Email `form:"email" json:"email"`
+}
+
+// CountProfilesParams defines parameters for CountProfiles.
+type CountProfilesParams struct {
+	// NamePrefix Only profiles whose name starts with this prefix (case-insensitive)
+	NamePrefix *NamePrefix `form:"namePrefix,omitempty" json:"namePrefix,omitempty"`
+
+	// MinAge Minimum age (inclusive)
+	MinAge *MinAge `form:"minAge,omitempty" json:"minAge,omitempty"`
+
+	// MaxAge Maximum age (inclusive)
+	MaxAge *MaxAge `form:"maxAge,omitempty" json:"maxAge,omitempty"`
+
+	// CreatedFrom Only profiles created at or after this instant
+	CreatedFrom *CreatedFrom `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only profiles created before this instant
+	CreatedTo *CreatedTo `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// EmailDomain Only profiles whose email is in this domain (case-insensitive)
+	EmailDomain *EmailDomain `form:"emailDomain,omitempty" json:"emailDomain,omitempty"`
+}
+
 // DeleteProfileParams defines parameters for DeleteProfile.
 type DeleteProfileParams struct {
 	// IfMatch Match against current entity tag to allow update
 	IfMatch RequiredIfMatch `json:"If-Match"`
 }
 
-// ModifyProfileJSONBody defines parameters for ModifyProfile.
-type ModifyProfileJSONBody struct {
-	Age   nullable.Nullable[string] `json:"age,omitempty"`
-	Email *openapi_types.Email      `json:"email,omitempty"`
-	Name  nullable.Nullable[string] `json:"name,omitempty"`
+// GetProfileByIdParams defines parameters for GetProfileById.
+type GetProfileByIdParams struct {
+	// AsOf Instant to read the profile at, within the audit retention window
+	AsOf *AsOf `form:"asOf,omitempty" json:"asOf,omitempty"`
+
+	// IfNoneMatch Entity tags from earlier responses, or `*`. When one is still current the server answers 304 without a body.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // ModifyProfileParams defines parameters for ModifyProfile.
@@ -221,15 +470,38 @@ type UpdateProfileJSONBody struct {
 
 // UpdateProfileParams defines parameters for UpdateProfile.
 type UpdateProfileParams struct {
+	// IfMatch Current entity tag of the profile to replace
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+
+	// IfNoneMatch `*` to create the profile only if none exists at the ID
+	IfNoneMatch *UpdateProfileParamsIfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// UpdateProfileParamsIfNoneMatch defines parameters for UpdateProfile.
+type UpdateProfileParamsIfNoneMatch string
+
+// RestoreProfileParams defines parameters for RestoreProfile.
+type RestoreProfileParams struct {
 	// IfMatch Match against current entity tag to allow update
 	IfMatch RequiredIfMatch `json:"If-Match"`
 }
 
+// CheckProfileEmailParams defines parameters for CheckProfileEmail.
+type CheckProfileEmailParams struct {
+	Email openapi_types.Email `form:"email" json:"email"`
+}
+
+// ModifyProfilesJSONRequestBody defines body for ModifyProfiles for application/json ContentType.
+type ModifyProfilesJSONRequestBody ModifyProfilesJSONBody
+
 // CreateProfileJSONRequestBody defines body for CreateProfile for application/json ContentType.
 type CreateProfileJSONRequestBody CreateProfileJSONBody
 
+// UpsertProfileJSONRequestBody defines body for UpsertProfile for application/json ContentType.
+type UpsertProfileJSONRequestBody UpsertProfileJSONBody
+
 // ModifyProfileJSONRequestBody defines body for ModifyProfile for application/json ContentType.
-type ModifyProfileJSONRequestBody ModifyProfileJSONBody
+type ModifyProfileJSONRequestBody = ProfilePatch
 
 // UpdateProfileJSONRequestBody defines body for UpdateProfile for application/json ContentType.
 type UpdateProfileJSONRequestBody UpdateProfileJSONBody
@@ -500,21 +772,45 @@ type ServerInterface interface {
 	// List profiles (offset or cursor pagination)
 	// (GET /v1/profiles)
 	ListProfiles(ctx echo.Context, params ListProfilesParams) error
+	// Patch many profiles at once
+	// (PATCH /v1/profiles)
+	ModifyProfiles(ctx echo.Context) error
 	// Create a profile
 	// (POST /v1/profiles)
 	CreateProfile(ctx echo.Context) error
+	// Create or update the profile owning an email
+	// (PUT /v1/profiles)
+	UpsertProfile(ctx echo.Context, params UpsertProfileParams) error
+	// Count profiles matching a filter
+	// (GET /v1/profiles/count)
+	CountProfiles(ctx echo.Context, params CountProfilesParams) error
+	// Aggregate profile statistics
+	// (GET /v1/profiles/stats)
+	GetProfileStats(ctx echo.Context) error
 	// Delete a profile
 	// (DELETE /v1/profiles/{id})
 	DeleteProfile(ctx echo.Context, id ProfileId, params DeleteProfileParams) error
 	// Get profile by ID
 	// (GET /v1/profiles/{id})
-	GetProfileById(ctx echo.Context, id ProfileId) error
+	GetProfileById(ctx echo.Context, id ProfileId, params GetProfileByIdParams) error
+	// Check whether a profile exists
+	// (HEAD /v1/profiles/{id})
+	HeadProfileById(ctx echo.Context, id ProfileId) error
 	// Patch an existing profile
 	// (PATCH /v1/profiles/{id})
 	ModifyProfile(ctx echo.Context, id ProfileId, params ModifyProfileParams) error
-	// Update an existing profile
+	// Update an existing profile, or create one at this ID
 	// (PUT /v1/profiles/{id})
 	UpdateProfile(ctx echo.Context, id ProfileId, params UpdateProfileParams) error
+	// Restore a deleted profile
+	// (POST /v1/profiles/{id}/restore)
+	RestoreProfile(ctx echo.Context, id ProfileId, params RestoreProfileParams) error
+	// Check whether an email can be used for a new profile
+	// (GET /v1/profiles:checkEmail)
+	CheckProfileEmail(ctx echo.Context, params CheckProfileEmailParams) error
+	// Export every live profile as newline-delimited JSON
+	// (GET /v1/profiles:export)
+	ExportProfiles(ctx echo.Context) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -535,6 +831,8 @@ func (w *ServerInterfaceWrapper) Healthz(ctx echo.Context) error {
 func (w *ServerInterfaceWrapper) ListProfiles(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListProfilesParams
 	// ------------- Optional query parameter "page" -------------
@@ -572,20 +870,192 @@ func (w *ServerInterfaceWrapper) ListProfiles(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
 	}
 
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "sort", ctx.QueryParams(), &params.Sort)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter sort: %s", err))
+	}
+
+	// ------------- Optional query parameter "namePrefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namePrefix", ctx.QueryParams(), &params.NamePrefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter namePrefix: %s", err))
+	}
+
+	// ------------- Optional query parameter "minAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minAge", ctx.QueryParams(), &params.MinAge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minAge: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxAge", ctx.QueryParams(), &params.MaxAge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxAge: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", ctx.QueryParams(), &params.CreatedFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdFrom: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", ctx.QueryParams(), &params.CreatedTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdTo: %s", err))
+	}
+
+	// ------------- Optional query parameter "emailDomain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "emailDomain", ctx.QueryParams(), &params.EmailDomain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter emailDomain: %s", err))
+	}
+
+	// ------------- Optional query parameter "includeDeleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeDeleted", ctx.QueryParams(), &params.IncludeDeleted)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter includeDeleted: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.ListProfiles(ctx, params)
 	return err
 }
 
+// ModifyProfiles converts echo context to params.
+func (w *ServerInterfaceWrapper) ModifyProfiles(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ModifyProfiles(ctx)
+	return err
+}
+
 // CreateProfile converts echo context to params.
 func (w *ServerInterfaceWrapper) CreateProfile(ctx echo.Context) error {
 	var err error
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.CreateProfile(ctx)
 	return err
 }
 
+// UpsertProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) UpsertProfile(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpsertProfileParams
+	// ------------- Required query parameter "email" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "email", ctx.QueryParams(), &params.Email)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter email: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.UpsertProfile(ctx, params)
+	return err
+}
+
+// CountProfiles converts echo context to params.
+func (w *ServerInterfaceWrapper) CountProfiles(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CountProfilesParams
+	// ------------- Optional query parameter "namePrefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namePrefix", ctx.QueryParams(), &params.NamePrefix)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter namePrefix: %s", err))
+	}
+
+	// ------------- Optional query parameter "minAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "minAge", ctx.QueryParams(), &params.MinAge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter minAge: %s", err))
+	}
+
+	// ------------- Optional query parameter "maxAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxAge", ctx.QueryParams(), &params.MaxAge)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter maxAge: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", ctx.QueryParams(), &params.CreatedFrom)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdFrom: %s", err))
+	}
+
+	// ------------- Optional query parameter "createdTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", ctx.QueryParams(), &params.CreatedTo)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter createdTo: %s", err))
+	}
+
+	// ------------- Optional query parameter "emailDomain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "emailDomain", ctx.QueryParams(), &params.EmailDomain)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter emailDomain: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CountProfiles(ctx, params)
+	return err
+}
+
+// GetProfileStats converts echo context to params.
+func (w *ServerInterfaceWrapper) GetProfileStats(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProfileStats(ctx)
+	return err
+}
+
 // DeleteProfile converts echo context to params.
 func (w *ServerInterfaceWrapper) DeleteProfile(ctx echo.Context) error {
 	var err error
@@ -597,6 +1067,8 @@ func (w *ServerInterfaceWrapper) DeleteProfile(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params DeleteProfileParams
 
@@ -635,8 +1107,54 @@ func (w *ServerInterfaceWrapper) GetProfileById(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetProfileByIdParams
+	// ------------- Optional query parameter "asOf" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "asOf", ctx.QueryParams(), &params.AsOf)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter asOf: %s", err))
+	}
+
+	headers := ctx.Request().Header
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetProfileById(ctx, id, params)
+	return err
+}
+
+// HeadProfileById converts echo context to params.
+func (w *ServerInterfaceWrapper) HeadProfileById(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetProfileById(ctx, id)
+	err = w.Handler.HeadProfileById(ctx, id)
 	return err
 }
 
@@ -651,6 +1169,8 @@ func (w *ServerInterfaceWrapper) ModifyProfile(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ModifyProfileParams
 
@@ -689,33 +1209,119 @@ func (w *ServerInterfaceWrapper) UpdateProfile(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
 	}
 
+	ctx.Set(Oauth2Scopes, []string{"profiles:write"})
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params UpdateProfileParams
 
 	headers := ctx.Request().Header
-	// ------------- Required header parameter "If-Match" -------------
+	// ------------- Optional header parameter "If-Match" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
-		var IfMatch RequiredIfMatch
+		var IfMatch IfMatch
 		n := len(valueList)
 		if n != 1 {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
 		}
 
-		params.IfMatch = IfMatch
-	} else {
-		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+		params.IfMatch = &IfMatch
 	}
-
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch UpdateProfileParamsIfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-None-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-None-Match: %s", err))
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
 	err = w.Handler.UpdateProfile(ctx, id, params)
 	return err
 }
 
+// RestoreProfile converts echo context to params.
+func (w *ServerInterfaceWrapper) RestoreProfile(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", ctx.Param("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter id: %s", err))
+	}
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:admin"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RestoreProfileParams
+
+	headers := ctx.Request().Header
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch RequiredIfMatch
+		n := len(valueList)
+		if n != 1 {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Expected one value for If-Match, got %d", n))
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter If-Match: %s", err))
+		}
+
+		params.IfMatch = IfMatch
+	} else {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Header parameter If-Match is required, but not found"))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.RestoreProfile(ctx, id, params)
+	return err
+}
+
+// CheckProfileEmail converts echo context to params.
+func (w *ServerInterfaceWrapper) CheckProfileEmail(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CheckProfileEmailParams
+	// ------------- Required query parameter "email" -------------
+
+	err = runtime.BindQueryParameter("form", true, true, "email", ctx.QueryParams(), &params.Email)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter email: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.CheckProfileEmail(ctx, params)
+	return err
+}
+
+// ExportProfiles converts echo context to params.
+func (w *ServerInterfaceWrapper) ExportProfiles(ctx echo.Context) error {
+	var err error
+
+	ctx.Set(Oauth2Scopes, []string{"profiles:read"})
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.ExportProfiles(ctx)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -746,11 +1352,19 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 
 	router.GET(baseURL+"/healthz", wrapper.Healthz)
 	router.GET(baseURL+"/v1/profiles", wrapper.ListProfiles)
+	router.PATCH(baseURL+"/v1/profiles", wrapper.ModifyProfiles)
 	router.POST(baseURL+"/v1/profiles", wrapper.CreateProfile)
+	router.PUT(baseURL+"/v1/profiles", wrapper.UpsertProfile)
+	router.GET(baseURL+"/v1/profiles/count", wrapper.CountProfiles)
+	router.GET(baseURL+"/v1/profiles/stats", wrapper.GetProfileStats)
 	router.DELETE(baseURL+"/v1/profiles/:id", wrapper.DeleteProfile)
 	router.GET(baseURL+"/v1/profiles/:id", wrapper.GetProfileById)
+	router.HEAD(baseURL+"/v1/profiles/:id", wrapper.HeadProfileById)
 	router.PATCH(baseURL+"/v1/profiles/:id", wrapper.ModifyProfile)
 	router.PUT(baseURL+"/v1/profiles/:id", wrapper.UpdateProfile)
+	router.POST(baseURL+"/v1/profiles/:id/restore", wrapper.RestoreProfile)
+	router.GET(baseURL+"/v1/profiles:checkEmail", wrapper.CheckProfileEmail)
+	router.GET(baseURL+"/v1/profiles:export", wrapper.ExportProfiles)
 
 }
 
@@ -815,6 +1429,20 @@ func (response ListProfiles200JSONResponse) VisitListProfilesResponse(w http.Res
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type ListProfiles304ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type ListProfiles304Response struct {
+	Headers ListProfiles304ResponseHeaders
+}
+
+func (response ListProfiles304Response) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
 type ListProfiles400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
@@ -835,6 +1463,15 @@ func (response ListProfiles401ApplicationProblemPlusJSONResponse) VisitListProfi
 	return json.NewEncoder(w).Encode(response)
 }
 
+type ListProfiles403ApplicationProblemPlusJSONResponse Problem
+
+func (response ListProfiles403ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type ListProfiles429ApplicationProblemPlusJSONResponse Problem
 
 func (response ListProfiles429ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
@@ -856,6 +1493,46 @@ func (response ListProfilesdefaultApplicationProblemPlusJSONResponse) VisitListP
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type ModifyProfilesRequestObject struct {
+	Body *ModifyProfilesJSONRequestBody
+}
+
+type ModifyProfilesResponseObject interface {
+	VisitModifyProfilesResponse(w http.ResponseWriter) error
+}
+
+type ModifyProfiles207JSONResponse ProfilePatchResults
+
+func (response ModifyProfiles207JSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(207)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ModifyProfiles400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response ModifyProfiles400ApplicationProblemPlusJSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ModifyProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ModifyProfilesdefaultApplicationProblemPlusJSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type CreateProfileRequestObject struct {
 	Body *CreateProfileJSONRequestBody
 }
@@ -921,6 +1598,160 @@ func (response CreateProfiledefaultApplicationProblemPlusJSONResponse) VisitCrea
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type UpsertProfileRequestObject struct {
+	Params UpsertProfileParams
+	Body   *UpsertProfileJSONRequestBody
+}
+
+type UpsertProfileResponseObject interface {
+	VisitUpsertProfileResponse(w http.ResponseWriter) error
+}
+
+type UpsertProfile200ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type UpsertProfile200JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpsertProfile200ResponseHeaders
+}
+
+func (response UpsertProfile200JSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpsertProfile201ResponseHeaders struct {
+	ETag     ETagValue
+	Location string
+}
+
+type UpsertProfile201JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpsertProfile201ResponseHeaders
+}
+
+func (response UpsertProfile201JSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpsertProfile400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response UpsertProfile400ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpsertProfile409ApplicationProblemPlusJSONResponse Problem
+
+func (response UpsertProfile409ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpsertProfile422ApplicationProblemPlusJSONResponse Problem
+
+func (response UpsertProfile422ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpsertProfiledefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response UpsertProfiledefaultApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CountProfilesRequestObject struct {
+	Params CountProfilesParams
+}
+
+type CountProfilesResponseObject interface {
+	VisitCountProfilesResponse(w http.ResponseWriter) error
+}
+
+type CountProfiles200JSONResponse SuccessProfileCount
+
+func (response CountProfiles200JSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CountProfiles400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response CountProfiles400ApplicationProblemPlusJSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CountProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CountProfilesdefaultApplicationProblemPlusJSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetProfileStatsRequestObject struct {
+}
+
+type GetProfileStatsResponseObject interface {
+	VisitGetProfileStatsResponse(w http.ResponseWriter) error
+}
+
+type GetProfileStats200JSONResponse SuccessProfileStats
+
+func (response GetProfileStats200JSONResponse) VisitGetProfileStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProfileStatsdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetProfileStatsdefaultApplicationProblemPlusJSONResponse) VisitGetProfileStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type DeleteProfileRequestObject struct {
 	Id     ProfileId `json:"id"`
 	Params DeleteProfileParams
@@ -995,7 +1826,8 @@ func (response DeleteProfiledefaultApplicationProblemPlusJSONResponse) VisitDele
 }
 
 type GetProfileByIdRequestObject struct {
-	Id ProfileId `json:"id"`
+	Id     ProfileId `json:"id"`
+	Params GetProfileByIdParams
 }
 
 type GetProfileByIdResponseObject interface {
@@ -1027,6 +1859,20 @@ func (response GetProfileById200JSONResponse) VisitGetProfileByIdResponse(w http
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type GetProfileById304ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type GetProfileById304Response struct {
+	Headers GetProfileById304ResponseHeaders
+}
+
+func (response GetProfileById304Response) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
 type GetProfileById400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
@@ -1059,36 +1905,74 @@ func (response GetProfileByIddefaultApplicationProblemPlusJSONResponse) VisitGet
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ModifyProfileRequestObject struct {
-	Id     ProfileId `json:"id"`
-	Params ModifyProfileParams
-	Body   *ModifyProfileJSONRequestBody
+type HeadProfileByIdRequestObject struct {
+	Id ProfileId `json:"id"`
 }
 
-type ModifyProfileResponseObject interface {
-	VisitModifyProfileResponse(w http.ResponseWriter) error
+type HeadProfileByIdResponseObject interface {
+	VisitHeadProfileByIdResponse(w http.ResponseWriter) error
 }
 
-type ModifyProfile200ResponseHeaders struct {
-	ETag                    ETagValue
-	XRateLimitLimit         int
-	XRateLimitRemaining     int
-	XRateLimitResetSeconds  int
-	XRateLimitWindowSeconds int
+type HeadProfileById200ResponseHeaders struct {
+	ETag ETagValue
 }
 
-type ModifyProfile200JSONResponse struct {
-	Body    SuccessProfile
-	Headers ModifyProfile200ResponseHeaders
+type HeadProfileById200Response struct {
+	Headers HeadProfileById200ResponseHeaders
 }
 
-func (response ModifyProfile200JSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
+func (response HeadProfileById200Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
 	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
-	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
-	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
-	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
-	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
+	w.WriteHeader(200)
+	return nil
+}
+
+type HeadProfileById400Response struct {
+}
+
+func (response HeadProfileById400Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type HeadProfileById404Response struct {
+}
+
+func (response HeadProfileById404Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ModifyProfileRequestObject struct {
+	Id     ProfileId `json:"id"`
+	Params ModifyProfileParams
+	Body   *ModifyProfileJSONRequestBody
+}
+
+type ModifyProfileResponseObject interface {
+	VisitModifyProfileResponse(w http.ResponseWriter) error
+}
+
+type ModifyProfile200ResponseHeaders struct {
+	ETag                    ETagValue
+	XRateLimitLimit         int
+	XRateLimitRemaining     int
+	XRateLimitResetSeconds  int
+	XRateLimitWindowSeconds int
+}
+
+type ModifyProfile200JSONResponse struct {
+	Body    SuccessProfile
+	Headers ModifyProfile200ResponseHeaders
+}
+
+func (response ModifyProfile200JSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
+	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
+	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response.Body)
@@ -1182,6 +2066,25 @@ func (response UpdateProfile200JSONResponse) VisitUpdateProfileResponse(w http.R
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type UpdateProfile201ResponseHeaders struct {
+	ETag     ETagValue
+	Location string
+}
+
+type UpdateProfile201JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpdateProfile201ResponseHeaders
+}
+
+func (response UpdateProfile201JSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 type UpdateProfile400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
@@ -1202,6 +2105,15 @@ func (response UpdateProfile404ApplicationProblemPlusJSONResponse) VisitUpdatePr
 	return json.NewEncoder(w).Encode(response)
 }
 
+type UpdateProfile409ApplicationProblemPlusJSONResponse Problem
+
+func (response UpdateProfile409ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type UpdateProfile412ApplicationProblemPlusJSONResponse struct {
 	PreconditionFailedResponseApplicationProblemPlusJSONResponse
 }
@@ -1223,6 +2135,15 @@ func (response UpdateProfile422ApplicationProblemPlusJSONResponse) VisitUpdatePr
 	return json.NewEncoder(w).Encode(response)
 }
 
+type UpdateProfile428ApplicationProblemPlusJSONResponse Problem
+
+func (response UpdateProfile428ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(428)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type UpdateProfiledefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
@@ -1235,6 +2156,168 @@ func (response UpdateProfiledefaultApplicationProblemPlusJSONResponse) VisitUpda
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
+type RestoreProfileRequestObject struct {
+	Id     ProfileId `json:"id"`
+	Params RestoreProfileParams
+}
+
+type RestoreProfileResponseObject interface {
+	VisitRestoreProfileResponse(w http.ResponseWriter) error
+}
+
+type RestoreProfile200ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type RestoreProfile200JSONResponse struct {
+	Body    SuccessProfile
+	Headers RestoreProfile200ResponseHeaders
+}
+
+func (response RestoreProfile200JSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreProfile400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response RestoreProfile400ApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type RestoreProfile412ApplicationProblemPlusJSONResponse struct {
+	PreconditionFailedResponseApplicationProblemPlusJSONResponse
+}
+
+func (response RestoreProfile412ApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type RestoreProfiledefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response RestoreProfiledefaultApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CheckProfileEmailRequestObject struct {
+	Params CheckProfileEmailParams
+}
+
+type CheckProfileEmailResponseObject interface {
+	VisitCheckProfileEmailResponse(w http.ResponseWriter) error
+}
+
+type CheckProfileEmail200ResponseHeaders struct {
+	CacheControl string
+}
+
+type CheckProfileEmail200JSONResponse struct {
+	Body    SuccessEmailAvailability
+	Headers CheckProfileEmail200ResponseHeaders
+}
+
+func (response CheckProfileEmail200JSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", fmt.Sprint(response.Headers.CacheControl))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type CheckProfileEmail400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response CheckProfileEmail400ApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CheckProfileEmail429ApplicationProblemPlusJSONResponse Problem
+
+func (response CheckProfileEmail429ApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CheckProfileEmaildefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CheckProfileEmaildefaultApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ExportProfilesRequestObject struct {
+}
+
+type ExportProfilesResponseObject interface {
+	VisitExportProfilesResponse(w http.ResponseWriter) error
+}
+
+type ExportProfiles200ResponseHeaders struct {
+	CacheControl string
+}
+
+type ExportProfiles200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	Headers       ExportProfiles200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportProfiles200ApplicationxNdjsonResponse) VisitExportProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Cache-Control", fmt.Sprint(response.Headers.CacheControl))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ExportProfilesdefaultApplicationProblemPlusJSONResponse) VisitExportProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 	// Liveness probe
@@ -1243,21 +2326,45 @@ type StrictServerInterface interface {
 	// List profiles (offset or cursor pagination)
 	// (GET /v1/profiles)
 	ListProfiles(ctx context.Context, request ListProfilesRequestObject) (ListProfilesResponseObject, error)
+	// Patch many profiles at once
+	// (PATCH /v1/profiles)
+	ModifyProfiles(ctx context.Context, request ModifyProfilesRequestObject) (ModifyProfilesResponseObject, error)
 	// Create a profile
 	// (POST /v1/profiles)
 	CreateProfile(ctx context.Context, request CreateProfileRequestObject) (CreateProfileResponseObject, error)
+	// Create or update the profile owning an email
+	// (PUT /v1/profiles)
+	UpsertProfile(ctx context.Context, request UpsertProfileRequestObject) (UpsertProfileResponseObject, error)
+	// Count profiles matching a filter
+	// (GET /v1/profiles/count)
+	CountProfiles(ctx context.Context, request CountProfilesRequestObject) (CountProfilesResponseObject, error)
+	// Aggregate profile statistics
+	// (GET /v1/profiles/stats)
+	GetProfileStats(ctx context.Context, request GetProfileStatsRequestObject) (GetProfileStatsResponseObject, error)
 	// Delete a profile
 	// (DELETE /v1/profiles/{id})
 	DeleteProfile(ctx context.Context, request DeleteProfileRequestObject) (DeleteProfileResponseObject, error)
 	// Get profile by ID
 	// (GET /v1/profiles/{id})
 	GetProfileById(ctx context.Context, request GetProfileByIdRequestObject) (GetProfileByIdResponseObject, error)
+	// Check whether a profile exists
+	// (HEAD /v1/profiles/{id})
+	HeadProfileById(ctx context.Context, request HeadProfileByIdRequestObject) (HeadProfileByIdResponseObject, error)
 	// Patch an existing profile
 	// (PATCH /v1/profiles/{id})
 	ModifyProfile(ctx context.Context, request ModifyProfileRequestObject) (ModifyProfileResponseObject, error)
-	// Update an existing profile
+	// Update an existing profile, or create one at this ID
 	// (PUT /v1/profiles/{id})
 	UpdateProfile(ctx context.Context, request UpdateProfileRequestObject) (UpdateProfileResponseObject, error)
+	// Restore a deleted profile
+	// (POST /v1/profiles/{id}/restore)
+	RestoreProfile(ctx context.Context, request RestoreProfileRequestObject) (RestoreProfileResponseObject, error)
+	// Check whether an email can be used for a new profile
+	// (GET /v1/profiles:checkEmail)
+	CheckProfileEmail(ctx context.Context, request CheckProfileEmailRequestObject) (CheckProfileEmailResponseObject, error)
+	// Export every live profile as newline-delimited JSON
+	// (GET /v1/profiles:export)
+	ExportProfiles(ctx context.Context, request ExportProfilesRequestObject) (ExportProfilesResponseObject, error)
 }
 
 type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
@@ -1320,6 +2427,35 @@ func (sh *strictHandler) ListProfiles(ctx echo.Context, params ListProfilesParam
 	return nil
 }
 
+// ModifyProfiles operation middleware
+func (sh *strictHandler) ModifyProfiles(ctx echo.Context) error {
+	var request ModifyProfilesRequestObject
+
+	var body ModifyProfilesJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ModifyProfiles(ctx.Request().Context(), request.(ModifyProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ModifyProfiles")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ModifyProfilesResponseObject); ok {
+		return validResponse.VisitModifyProfilesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // CreateProfile operation middleware
 func (sh *strictHandler) CreateProfile(ctx echo.Context) error {
 	var request CreateProfileRequestObject
@@ -1349,6 +2485,85 @@ func (sh *strictHandler) CreateProfile(ctx echo.Context) error {
 	return nil
 }
 
+// UpsertProfile operation middleware
+func (sh *strictHandler) UpsertProfile(ctx echo.Context, params UpsertProfileParams) error {
+	var request UpsertProfileRequestObject
+
+	request.Params = params
+
+	var body UpsertProfileJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.UpsertProfile(ctx.Request().Context(), request.(UpsertProfileRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpsertProfile")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(UpsertProfileResponseObject); ok {
+		return validResponse.VisitUpsertProfileResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// CountProfiles operation middleware
+func (sh *strictHandler) CountProfiles(ctx echo.Context, params CountProfilesParams) error {
+	var request CountProfilesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CountProfiles(ctx.Request().Context(), request.(CountProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CountProfiles")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(CountProfilesResponseObject); ok {
+		return validResponse.VisitCountProfilesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetProfileStats operation middleware
+func (sh *strictHandler) GetProfileStats(ctx echo.Context) error {
+	var request GetProfileStatsRequestObject
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProfileStats(ctx.Request().Context(), request.(GetProfileStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProfileStats")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetProfileStatsResponseObject); ok {
+		return validResponse.VisitGetProfileStatsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // DeleteProfile operation middleware
 func (sh *strictHandler) DeleteProfile(ctx echo.Context, id ProfileId, params DeleteProfileParams) error {
 	var request DeleteProfileRequestObject
@@ -1376,10 +2591,11 @@ func (sh *strictHandler) DeleteProfile(ctx echo.Context, id ProfileId, params De
 }
 
 // GetProfileById operation middleware
-func (sh *strictHandler) GetProfileById(ctx echo.Context, id ProfileId) error {
+func (sh *strictHandler) GetProfileById(ctx echo.Context, id ProfileId, params GetProfileByIdParams) error {
 	var request GetProfileByIdRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
 		return sh.ssi.GetProfileById(ctx.Request().Context(), request.(GetProfileByIdRequestObject))
@@ -1400,6 +2616,31 @@ func (sh *strictHandler) GetProfileById(ctx echo.Context, id ProfileId) error {
 	return nil
 }
 
+// HeadProfileById operation middleware
+func (sh *strictHandler) HeadProfileById(ctx echo.Context, id ProfileId) error {
+	var request HeadProfileByIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.HeadProfileById(ctx.Request().Context(), request.(HeadProfileByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "HeadProfileById")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(HeadProfileByIdResponseObject); ok {
+		return validResponse.VisitHeadProfileByIdResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // ModifyProfile operation middleware
 func (sh *strictHandler) ModifyProfile(ctx echo.Context, id ProfileId, params ModifyProfileParams) error {
 	var request ModifyProfileRequestObject
@@ -1463,3 +2704,77 @@ func (sh *strictHandler) UpdateProfile(ctx echo.Context, id ProfileId, params Up
 	}
 	return nil
 }
+
+// RestoreProfile operation middleware
+func (sh *strictHandler) RestoreProfile(ctx echo.Context, id ProfileId, params RestoreProfileParams) error {
+	var request RestoreProfileRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.RestoreProfile(ctx.Request().Context(), request.(RestoreProfileRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RestoreProfile")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(RestoreProfileResponseObject); ok {
+		return validResponse.VisitRestoreProfileResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// CheckProfileEmail operation middleware
+func (sh *strictHandler) CheckProfileEmail(ctx echo.Context, params CheckProfileEmailParams) error {
+	var request CheckProfileEmailRequestObject
+
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.CheckProfileEmail(ctx.Request().Context(), request.(CheckProfileEmailRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CheckProfileEmail")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(CheckProfileEmailResponseObject); ok {
+		return validResponse.VisitCheckProfileEmailResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// ExportProfiles operation middleware
+func (sh *strictHandler) ExportProfiles(ctx echo.Context) error {
+	var request ExportProfilesRequestObject
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportProfiles(ctx.Request().Context(), request.(ExportProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportProfiles")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(ExportProfilesResponseObject); ok {
+		return validResponse.VisitExportProfilesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -19,6 +20,10 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	Oauth2Scopes = "oauth2.Scopes"
+)
+
 // Defines values for CursorMetaMode.
 const (
 	Cursor CursorMetaMode = "cursor"
@@ -29,8 +34,50 @@ const (
 	Offset OffsetMetaMode = "offset"
 )
 
+// Defines values for IfNoneMatchAny.
+const (
+	IfNoneMatchAnyAsterisk IfNoneMatchAny = "*"
+)
+
+// Defines values for Sort.
+const (
+	SortAge            Sort = "age"
+	SortCreatedAt      Sort = "createdAt"
+	SortMinusAge       Sort = "-age"
+	SortMinusCreatedAt Sort = "-createdAt"
+	SortMinusName      Sort = "-name"
+	SortName           Sort = "name"
+)
+
+// Defines values for ListProfilesParamsSort.
+const (
+	ListProfilesParamsSortAge            ListProfilesParamsSort = "age"
+	ListProfilesParamsSortCreatedAt      ListProfilesParamsSort = "createdAt"
+	ListProfilesParamsSortMinusAge       ListProfilesParamsSort = "-age"
+	ListProfilesParamsSortMinusCreatedAt ListProfilesParamsSort = "-createdAt"
+	ListProfilesParamsSortMinusName      ListProfilesParamsSort = "-name"
+	ListProfilesParamsSortName           ListProfilesParamsSort = "name"
+)
+
+// Defines values for UpdateProfileParamsIfNoneMatch.
+const (
+	UpdateProfileParamsIfNoneMatchAsterisk UpdateProfileParamsIfNoneMatch = "*"
+)
+
+// AgeBucketCount defines model for AgeBucketCount.
+type AgeBucketCount struct {
+	// Bucket Bucket label, `unknown` for profiles without an age
+	Bucket string                 `json:"bucket"`
+	Count  int                    `json:"count"`
+	MaxAge nullable.Nullable[int] `json:"maxAge,omitempty"`
+	MinAge nullable.Nullable[int] `json:"minAge,omitempty"`
+}
+
 // CursorMeta defines model for CursorMeta.
 type CursorMeta struct {
+	// Clamped True when limit was lowered to the server maximum
+	Clamped *bool `json:"clamped,omitempty"`
+
 	// Etags Mapping of item UUIDs to their ETags for optimistic concurrency control
 	Etags *map[string]string `json:"etags,omitempty"`
 	Limit int                `json:"limit"`
@@ -48,11 +95,25 @@ type CursorMeta struct {
 // CursorMetaMode defines model for CursorMeta.Mode.
 type CursorMetaMode string
 
-// ETagValue defines model for ETagValue.
+// DailySignups defines model for DailySignups.
+type DailySignups struct {
+	Count int                `json:"count"`
+	Date  openapi_types.Date `json:"date"`
+}
+
+// ETagValue Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
 type ETagValue = string
 
+// EmailAvailability defines model for EmailAvailability.
+type EmailAvailability struct {
+	Available bool `json:"available"`
+}
+
 // OffsetMeta defines model for OffsetMeta.
 type OffsetMeta struct {
+	// Clamped True when pageSize was lowered to the server maximum
+	Clamped *bool `json:"clamped,omitempty"`
+
 	// Etags Mapping of item UUIDs to their ETags for optimistic concurrency control
 	Etags *map[string]string `json:"etags,omitempty"`
 	Links *struct {
@@ -94,13 +155,73 @@ type Problem struct {
 
 // Profile defines model for Profile.
 type Profile struct {
-	Age       *string                                `json:"age,omitempty"`
-	CreatedAt *time.Time                             `json:"createdAt,omitempty"`
+	Age       *string    `json:"age,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+
+	// DeletedAt Set on soft-deleted profiles, only listed with includeDeleted
+	DeletedAt *time.Time                             `json:"deletedAt,omitempty"`
 	Email     nullable.Nullable[openapi_types.Email] `json:"email,omitempty"`
 	Id        openapi_types.UUID                     `json:"id"`
 	Name      string                                 `json:"name"`
 }
 
+// ProfileCount defines model for ProfileCount.
+type ProfileCount struct {
+	Count int `json:"count"`
+}
+
+// ProfilePatch defines model for ProfilePatch.
+type ProfilePatch struct {
+	Age   nullable.Nullable[string] `json:"age,omitempty"`
+	Email *openapi_types.Email      `json:"email,omitempty"`
+	Name  nullable.Nullable[string] `json:"name,omitempty"`
+}
+
+// ProfilePatchItem defines model for ProfilePatchItem.
+type ProfilePatchItem struct {
+	Id openapi_types.UUID `json:"id"`
+
+	// IfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+	IfMatch ETagValue    `json:"ifMatch"`
+	Patch   ProfilePatch `json:"patch"`
+}
+
+// ProfilePatchResult defines model for ProfilePatchResult.
+type ProfilePatchResult struct {
+	Data *Profile `json:"data,omitempty"`
+
+	// Etag Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+	Etag    *ETagValue         `json:"etag,omitempty"`
+	Id      openapi_types.UUID `json:"id"`
+	Problem *Problem           `json:"problem,omitempty"`
+
+	// Status 200 when the patch was applied, 424 when it was rolled back because another item failed, the status of the problem otherwise
+	Status int `json:"status"`
+}
+
+// ProfilePatchResults defines model for ProfilePatchResults.
+type ProfilePatchResults struct {
+	Data []ProfilePatchResult `json:"data"`
+}
+
+// ProfileStats defines model for ProfileStats.
+type ProfileStats struct {
+	ByAge []AgeBucketCount `json:"byAge"`
+
+	// SignupsPerDay Days without signups are omitted
+	SignupsPerDay []DailySignups `json:"signupsPerDay"`
+	Total         int            `json:"total"`
+}
+
+// SuccessEmailAvailability defines model for SuccessEmailAvailability.
+type SuccessEmailAvailability struct {
+	Data EmailAvailability `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
 // SuccessEnvelopeList defines model for SuccessEnvelopeList.
 type SuccessEnvelopeList struct {
 	Data []interface{}  `json:"data"`
@@ -125,21 +246,72 @@ type SuccessProfile struct {
 	} `json:"meta"`
 }
 
+// SuccessProfileCount defines model for SuccessProfileCount.
+type SuccessProfileCount struct {
+	Data ProfileCount `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
 // SuccessProfileList defines model for SuccessProfileList.
 type SuccessProfileList struct {
 	Data []Profile      `json:"data"`
 	Meta PaginationMeta `json:"meta"`
 }
 
+// SuccessProfileStats defines model for SuccessProfileStats.
+type SuccessProfileStats struct {
+	Data ProfileStats `json:"data"`
+	Meta struct {
+		RequestId *string `json:"requestId,omitempty"`
+		TraceId   *string `json:"traceId,omitempty"`
+	} `json:"meta"`
+}
+
+// AsOf defines model for AsOf.
+type AsOf = time.Time
+
+// CreatedFrom defines model for CreatedFrom.
+type CreatedFrom = time.Time
+
+// CreatedTo defines model for CreatedTo.
+type CreatedTo = time.Time
+
 // CursorAfter defines model for CursorAfter.
 type CursorAfter = string
 
 // CursorBefore defines model for CursorBefore.
 type CursorBefore = string
 
+// EmailDomain defines model for EmailDomain.
+type EmailDomain = string
+
+// IfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
+type IfMatch = ETagValue
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// IfNoneMatchAny defines model for IfNoneMatchAny.
+type IfNoneMatchAny string
+
+// IncludeDeleted defines model for IncludeDeleted.
+type IncludeDeleted = bool
+
 // Limit defines model for Limit.
 type Limit = int
 
+// MaxAge defines model for MaxAge.
+type MaxAge = int
+
+// MinAge defines model for MinAge.
+type MinAge = int
+
+// NamePrefix defines model for NamePrefix.
+type NamePrefix = string
+
 // Page defines model for Page.
 type Page = int
 
@@ -149,9 +321,12 @@ type PageSize = int
 // ProfileId defines model for ProfileId.
 type ProfileId = openapi_types.UUID
 
-// RequiredIfMatch defines model for RequiredIfMatch.
+// RequiredIfMatch Quoted entity tag. Item tags are strong and carry the version, the legacy unquoted `v:<version>` form is still accepted.
 type RequiredIfMatch = ETagValue
 
+// Sort defines model for Sort.
+type Sort string
+
 // PreconditionFailedResponse defines model for PreconditionFailedResponse.
 type PreconditionFailedResponse = Problem
 
@@ -165,10 +340,16 @@ type CreateProfile struct {
 }
 
 // ModifyProfile defines model for ModifyProfile.
-type ModifyProfile struct {
-	Age   nullable.Nullable[string] `json:"age,omitempty"`
-	Email *openapi_types.Email      `json:"email,omitempty"`
-	Name  nullable.Nullable[string] `json:"name,omitempty"`
+type ModifyProfile = ProfilePatch
+
+// ModifyProfiles defines model for ModifyProfiles.
+type ModifyProfiles struct {
+	Items []ProfilePatchItem `json:"items"`
+}
+
+// UpsertProfile defines model for UpsertProfile.
+type UpsertProfile struct {
+	Name string `json:"name"`
 }
 
 // ListProfilesParams defines parameters for ListProfiles.
@@ -176,7 +357,7 @@ type ListProfilesParams struct {
 	// Page 0-based page number (use with `pageSize`)
 	Page *Page `form:"page,omitempty" json:"page,omitempty"`
 
-	// PageSize Page size (use with `page`)
+	// PageSize Page size (use with `page`). Sizes above the server maximum (100 by default) are lowered to it and `meta.clamped` is set.
 	PageSize *PageSize `form:"pageSize,omitempty" json:"pageSize,omitempty"`
 
 	// After Opaque cursor returned by the previous response (use with `limit`)
@@ -185,8 +366,43 @@ type ListProfilesParams struct {
 	// Before Opaque cursor returned by the previous response (use with `limit`)
 	Before *CursorBefore `form:"before,omitempty" json:"before,omitempty"`
 
-	// Limit Page size for cursor pagination (use with `cursor`)
+	// Limit Page size for cursor pagination (use with `cursor`). Sizes above the server maximum (100 by default) are lowered to it and `meta.clamped` is set.
 	Limit *Limit `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Sort Order of the list, `-` for descending; ties are broken by id. Names compare case-insensitively. Defaults to `-createdAt`, newest first.
+	Sort *ListProfilesParamsSort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// NamePrefix Only profiles whose name starts with this prefix (case-insensitive)
+	NamePrefix *NamePrefix `form:"namePrefix,omitempty" json:"namePrefix,omitempty"`
+
+	// MinAge Minimum age (inclusive)
+	MinAge *MinAge `form:"minAge,omitempty" json:"minAge,omitempty"`
+
+	// MaxAge Maximum age (inclusive)
+	MaxAge *MaxAge `form:"maxAge,omitempty" json:"maxAge,omitempty"`
+
+	// CreatedFrom Only profiles created at or after this instant
+	CreatedFrom *CreatedFrom `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only profiles created before this instant
+	CreatedTo *CreatedTo `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// EmailDomain Only profiles whose email is in this domain (case-insensitive)
+	EmailDomain *EmailDomain `form:"emailDomain,omitempty" json:"emailDomain,omitempty"`
+
+	// IncludeDeleted Also list soft-deleted profiles (requires the profiles:admin scope)
+	IncludeDeleted *IncludeDeleted `form:"includeDeleted,omitempty" json:"includeDeleted,omitempty"`
+
+	// IfNoneMatch Entity tags from earlier responses, or `*`. When one is still current the server answers 304 without a body.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// ListProfilesParamsSort defines parameters for ListProfiles.
+type ListProfilesParamsSort string
+
+// ModifyProfilesJSONBody defines parameters for ModifyProfiles.
+type ModifyProfilesJSONBody struct {
+	Items []ProfilePatchItem `json:"items"`
 }
 
 // CreateProfileJSONBody defines parameters for CreateProfile.
@@ -195,17 +411,50 @@ type CreateProfileJSONBody struct {
 	Name  string               `json:"name"`
 }
 
+// UpsertProfileJSONBody defines parameters for UpsertProfile.
+type UpsertProfileJSONBody struct {
+	Name string `json:"name"`
+}
+
+// UpsertProfileParams defines parameters for UpsertProfile.
+type UpsertProfileParams struct {
+	Email openapi_types.Email `form:"email" json:"email"`
+}
+
+// CountProfilesParams defines parameters for CountProfiles.
+type CountProfilesParams struct {
+	// NamePrefix Only profiles whose name starts with this prefix (case-insensitive)
+	NamePrefix *NamePrefix `form:"namePrefix,omitempty" json:"namePrefix,omitempty"`
+
+	// MinAge Minimum age (inclusive)
+	MinAge *MinAge `form:"minAge,omitempty" json:"minAge,omitempty"`
+
+	// MaxAge Maximum age (inclusive)
+	MaxAge *MaxAge `form:"maxAge,omitempty" json:"maxAge,omitempty"`
+
+	// CreatedFrom Only profiles created at or after this instant
+	CreatedFrom *CreatedFrom `form:"createdFrom,omitempty" json:"createdFrom,omitempty"`
+
+	// CreatedTo Only profiles created before this instant
+	CreatedTo *CreatedTo `form:"createdTo,omitempty" json:"createdTo,omitempty"`
+
+	// EmailDomain Only profiles whose email is in this domain (case-insensitive)
+	EmailDomain *EmailDomain `form:"emailDomain,omitempty" json:"emailDomain,omitempty"`
+}
+
 // DeleteProfileParams defines parameters for DeleteProfile.
 type DeleteProfileParams struct {
 	// IfMatch Match against current entity tag to allow update
 	IfMatch RequiredIfMatch `json:"If-Match"`
 }
 
-// ModifyProfileJSONBody defines parameters for ModifyProfile.
-type ModifyProfileJSONBody struct {
-	Age   nullable.Nullable[string] `json:"age,omitempty"`
-	Email *openapi_types.Email      `json:"email,omitempty"`
-	Name  nullable.Nullable[string] `json:"name,omitempty"`
+// GetProfileByIdParams defines parameters for GetProfileById.
+type GetProfileByIdParams struct {
+	// AsOf Instant to read the profile at, within the audit retention window
+	AsOf *AsOf `form:"asOf,omitempty" json:"asOf,omitempty"`
+
+	// IfNoneMatch Entity tags from earlier responses, or `*`. When one is still current the server answers 304 without a body.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
 }
 
 // ModifyProfileParams defines parameters for ModifyProfile.
@@ -222,15 +471,38 @@ type UpdateProfileJSONBody struct {
 
 // UpdateProfileParams defines parameters for UpdateProfile.
 type UpdateProfileParams struct {
+	// IfMatch Current entity tag of the profile to replace
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+
+	// IfNoneMatch `*` to create the profile only if none exists at the ID
+	IfNoneMatch *UpdateProfileParamsIfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// UpdateProfileParamsIfNoneMatch defines parameters for UpdateProfile.
+type UpdateProfileParamsIfNoneMatch string
+
+// RestoreProfileParams defines parameters for RestoreProfile.
+type RestoreProfileParams struct {
 	// IfMatch Match against current entity tag to allow update
 	IfMatch RequiredIfMatch `json:"If-Match"`
 }
 
+// CheckProfileEmailParams defines parameters for CheckProfileEmail.
+type CheckProfileEmailParams struct {
+	Email openapi_types.Email `form:"email" json:"email"`
+}
+
+// ModifyProfilesJSONRequestBody defines body for ModifyProfiles for application/json ContentType.
+type ModifyProfilesJSONRequestBody ModifyProfilesJSONBody
+
 // CreateProfileJSONRequestBody defines body for CreateProfile for application/json ContentType.
 type CreateProfileJSONRequestBody CreateProfileJSONBody
 
+// UpsertProfileJSONRequestBody defines body for UpsertProfile for application/json ContentType.
+type UpsertProfileJSONRequestBody UpsertProfileJSONBody
+
 // ModifyProfileJSONRequestBody defines body for ModifyProfile for application/json ContentType.
-type ModifyProfileJSONRequestBody ModifyProfileJSONBody
+type ModifyProfileJSONRequestBody = ProfilePatch
 
 // UpdateProfileJSONRequestBody defines body for UpdateProfile for application/json ContentType.
 type UpdateProfileJSONRequestBody UpdateProfileJSONBody
@@ -501,21 +773,45 @@ type ServerInterface interface {
 	// List profiles (offset or cursor pagination)
 	// (GET /v1/profiles)
 	ListProfiles(w http.ResponseWriter, r *http.Request, params ListProfilesParams)
+	// Patch many profiles at once
+	// (PATCH /v1/profiles)
+	ModifyProfiles(w http.ResponseWriter, r *http.Request)
 	// Create a profile
 	// (POST /v1/profiles)
 	CreateProfile(w http.ResponseWriter, r *http.Request)
+	// Create or update the profile owning an email
+	// (PUT /v1/profiles)
+	UpsertProfile(w http.ResponseWriter, r *http.Request, params UpsertProfileParams)
+	// Count profiles matching a filter
+	// (GET /v1/profiles/count)
+	CountProfiles(w http.ResponseWriter, r *http.Request, params CountProfilesParams)
+	// Aggregate profile statistics
+	// (GET /v1/profiles/stats)
+	GetProfileStats(w http.ResponseWriter, r *http.Request)
 	// Delete a profile
 	// (DELETE /v1/profiles/{id})
 	DeleteProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params DeleteProfileParams)
 	// Get profile by ID
 	// (GET /v1/profiles/{id})
-	GetProfileById(w http.ResponseWriter, r *http.Request, id ProfileId)
+	GetProfileById(w http.ResponseWriter, r *http.Request, id ProfileId, params GetProfileByIdParams)
+	// Check whether a profile exists
+	// (HEAD /v1/profiles/{id})
+	HeadProfileById(w http.ResponseWriter, r *http.Request, id ProfileId)
 	// Patch an existing profile
 	// (PATCH /v1/profiles/{id})
 	ModifyProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params ModifyProfileParams)
-	// Update an existing profile
+	// Update an existing profile, or create one at this ID
 	// (PUT /v1/profiles/{id})
 	UpdateProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params UpdateProfileParams)
+	// Restore a deleted profile
+	// (POST /v1/profiles/{id}/restore)
+	RestoreProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params RestoreProfileParams)
+	// Check whether an email can be used for a new profile
+	// (GET /v1/profiles:checkEmail)
+	CheckProfileEmail(w http.ResponseWriter, r *http.Request, params CheckProfileEmailParams)
+	// Export every live profile as newline-delimited JSON
+	// (GET /v1/profiles:export)
+	ExportProfiles(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -546,6 +842,12 @@ func (siw *ServerInterfaceWrapper) ListProfiles(w http.ResponseWriter, r *http.R
 
 	var err error
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
 	// Parameter object where we will unmarshal all parameters from the context
 	var params ListProfilesParams
 
@@ -589,75 +891,93 @@ func (siw *ServerInterfaceWrapper) ListProfiles(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListProfiles(w, r, params)
-	}))
+	// ------------- Optional query parameter "sort" -------------
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r)
-}
+	// ------------- Optional query parameter "namePrefix" -------------
 
-// CreateProfile operation middleware
-func (siw *ServerInterfaceWrapper) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	err = runtime.BindQueryParameter("form", true, false, "namePrefix", r.URL.Query(), &params.NamePrefix)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namePrefix", Err: err})
+		return
+	}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateProfile(w, r)
-	}))
+	// ------------- Optional query parameter "minAge" -------------
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
+	err = runtime.BindQueryParameter("form", true, false, "minAge", r.URL.Query(), &params.MinAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "minAge", Err: err})
+		return
 	}
 
-	handler.ServeHTTP(w, r)
-}
+	// ------------- Optional query parameter "maxAge" -------------
 
-// DeleteProfile operation middleware
-func (siw *ServerInterfaceWrapper) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	err = runtime.BindQueryParameter("form", true, false, "maxAge", r.URL.Query(), &params.MaxAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "maxAge", Err: err})
+		return
+	}
 
-	var err error
+	// ------------- Optional query parameter "createdFrom" -------------
 
-	// ------------- Path parameter "id" -------------
-	var id ProfileId
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", r.URL.Query(), &params.CreatedFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdFrom", Err: err})
+		return
+	}
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	// ------------- Optional query parameter "createdTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", r.URL.Query(), &params.CreatedTo)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdTo", Err: err})
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params DeleteProfileParams
+	// ------------- Optional query parameter "emailDomain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "emailDomain", r.URL.Query(), &params.EmailDomain)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "emailDomain", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "includeDeleted" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "includeDeleted", r.URL.Query(), &params.IncludeDeleted)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "includeDeleted", Err: err})
+		return
+	}
 
 	headers := r.Header
 
-	// ------------- Required header parameter "If-Match" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
-		var IfMatch RequiredIfMatch
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
 		n := len(valueList)
 		if n != 1 {
-			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
 		if err != nil {
-			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
 			return
 		}
 
-		params.IfMatch = IfMatch
+		params.IfNoneMatch = &IfNoneMatch
 
-	} else {
-		err := fmt.Errorf("Header parameter If-Match is required, but not found")
-		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
-		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteProfile(w, r, id, params)
+		siw.Handler.ListProfiles(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -667,22 +987,17 @@ func (siw *ServerInterfaceWrapper) DeleteProfile(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// GetProfileById operation middleware
-func (siw *ServerInterfaceWrapper) GetProfileById(w http.ResponseWriter, r *http.Request) {
+// ModifyProfiles operation middleware
+func (siw *ServerInterfaceWrapper) ModifyProfiles(w http.ResponseWriter, r *http.Request) {
 
-	var err error
+	ctx := r.Context()
 
-	// ------------- Path parameter "id" -------------
-	var id ProfileId
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
-	}
+	r = r.WithContext(ctx)
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetProfileById(w, r, id)
+		siw.Handler.ModifyProfiles(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -692,50 +1007,57 @@ func (siw *ServerInterfaceWrapper) GetProfileById(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r)
 }
 
-// ModifyProfile operation middleware
-func (siw *ServerInterfaceWrapper) ModifyProfile(w http.ResponseWriter, r *http.Request) {
+// CreateProfile operation middleware
+func (siw *ServerInterfaceWrapper) CreateProfile(w http.ResponseWriter, r *http.Request) {
 
-	var err error
+	ctx := r.Context()
 
-	// ------------- Path parameter "id" -------------
-	var id ProfileId
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
-		return
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateProfile(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ModifyProfileParams
+	handler.ServeHTTP(w, r)
+}
 
-	headers := r.Header
+// UpsertProfile operation middleware
+func (siw *ServerInterfaceWrapper) UpsertProfile(w http.ResponseWriter, r *http.Request) {
 
-	// ------------- Required header parameter "If-Match" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
-		var IfMatch RequiredIfMatch
-		n := len(valueList)
-		if n != 1 {
-			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
-			return
-		}
+	var err error
 
-		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
-		if err != nil {
-			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
-			return
-		}
+	ctx := r.Context()
 
-		params.IfMatch = IfMatch
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpsertProfileParams
+
+	// ------------- Required query parameter "email" -------------
+
+	if paramValue := r.URL.Query().Get("email"); paramValue != "" {
 
 	} else {
-		err := fmt.Errorf("Header parameter If-Match is required, but not found")
-		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "email"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "email", r.URL.Query(), &params.Email)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "email", Err: err})
 		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ModifyProfile(w, r, id, params)
+		siw.Handler.UpsertProfile(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -745,26 +1067,278 @@ func (siw *ServerInterfaceWrapper) ModifyProfile(w http.ResponseWriter, r *http.
 	handler.ServeHTTP(w, r)
 }
 
-// UpdateProfile operation middleware
-func (siw *ServerInterfaceWrapper) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+// CountProfiles operation middleware
+func (siw *ServerInterfaceWrapper) CountProfiles(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// ------------- Path parameter "id" -------------
-	var id ProfileId
+	ctx := r.Context()
 
-	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CountProfilesParams
+
+	// ------------- Optional query parameter "namePrefix" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "namePrefix", r.URL.Query(), &params.NamePrefix)
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "namePrefix", Err: err})
 		return
 	}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params UpdateProfileParams
+	// ------------- Optional query parameter "minAge" -------------
 
-	headers := r.Header
+	err = runtime.BindQueryParameter("form", true, false, "minAge", r.URL.Query(), &params.MinAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "minAge", Err: err})
+		return
+	}
 
-	// ------------- Required header parameter "If-Match" -------------
+	// ------------- Optional query parameter "maxAge" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxAge", r.URL.Query(), &params.MaxAge)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "maxAge", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "createdFrom" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdFrom", r.URL.Query(), &params.CreatedFrom)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdFrom", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "createdTo" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "createdTo", r.URL.Query(), &params.CreatedTo)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "createdTo", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "emailDomain" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "emailDomain", r.URL.Query(), &params.EmailDomain)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "emailDomain", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CountProfiles(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProfileStats operation middleware
+func (siw *ServerInterfaceWrapper) GetProfileStats(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProfileStats(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteProfile operation middleware
+func (siw *ServerInterfaceWrapper) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteProfileParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch RequiredIfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = IfMatch
+
+	} else {
+		err := fmt.Errorf("Header parameter If-Match is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteProfile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetProfileById operation middleware
+func (siw *ServerInterfaceWrapper) GetProfileById(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetProfileByIdParams
+
+	// ------------- Optional query parameter "asOf" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "asOf", r.URL.Query(), &params.AsOf)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "asOf", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetProfileById(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// HeadProfileById operation middleware
+func (siw *ServerInterfaceWrapper) HeadProfileById(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.HeadProfileById(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ModifyProfile operation middleware
+func (siw *ServerInterfaceWrapper) ModifyProfile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ModifyProfileParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "If-Match" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
 		var IfMatch RequiredIfMatch
 		n := len(valueList)
@@ -773,383 +1347,998 @@ func (siw *ServerInterfaceWrapper) UpdateProfile(w http.ResponseWriter, r *http.
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
-		if err != nil {
-			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
-			return
-		}
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = IfMatch
+
+	} else {
+		err := fmt.Errorf("Header parameter If-Match is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ModifyProfile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateProfile operation middleware
+func (siw *ServerInterfaceWrapper) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:write"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateProfileParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch UpdateProfileParamsIfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateProfile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestoreProfile operation middleware
+func (siw *ServerInterfaceWrapper) RestoreProfile(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id ProfileId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:admin"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RestoreProfileParams
+
+	headers := r.Header
+
+	// ------------- Required header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch RequiredIfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = IfMatch
+
+	} else {
+		err := fmt.Errorf("Header parameter If-Match is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreProfile(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CheckProfileEmail operation middleware
+func (siw *ServerInterfaceWrapper) CheckProfileEmail(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CheckProfileEmailParams
+
+	// ------------- Required query parameter "email" -------------
+
+	if paramValue := r.URL.Query().Get("email"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "email"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "email", r.URL.Query(), &params.Email)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "email", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CheckProfileEmail(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportProfiles operation middleware
+func (siw *ServerInterfaceWrapper) ExportProfiles(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, Oauth2Scopes, []string{"profiles:read"})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportProfiles(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+type UnescapedCookieParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnescapedCookieParamError) Error() string {
+	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+}
+
+func (e *UnescapedCookieParamError) Unwrap() error {
+	return e.Err
+}
+
+type UnmarshalingParamError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *UnmarshalingParamError) Error() string {
+	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *UnmarshalingParamError) Unwrap() error {
+	return e.Err
+}
+
+type RequiredParamError struct {
+	ParamName string
+}
+
+func (e *RequiredParamError) Error() string {
+	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+}
+
+type RequiredHeaderError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *RequiredHeaderError) Error() string {
+	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+}
+
+func (e *RequiredHeaderError) Unwrap() error {
+	return e.Err
+}
+
+type InvalidParamFormatError struct {
+	ParamName string
+	Err       error
+}
+
+func (e *InvalidParamFormatError) Error() string {
+	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+}
+
+func (e *InvalidParamFormatError) Unwrap() error {
+	return e.Err
+}
+
+type TooManyValuesForParamError struct {
+	ParamName string
+	Count     int
+}
+
+func (e *TooManyValuesForParamError) Error() string {
+	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+}
+
+// Handler creates http.Handler with routing matching OpenAPI spec.
+func Handler(si ServerInterface) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{})
+}
+
+// ServeMux is an abstraction of http.ServeMux.
+type ServeMux interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
+}
+
+type StdHTTPServerOptions struct {
+	BaseURL          string
+	BaseRouter       ServeMux
+	Middlewares      []MiddlewareFunc
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
+func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseRouter: m,
+	})
+}
+
+func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
+	return HandlerWithOptions(si, StdHTTPServerOptions{
+		BaseURL:    baseURL,
+		BaseRouter: m,
+	})
+}
+
+// HandlerWithOptions creates http.Handler with additional options
+func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
+	m := options.BaseRouter
+
+	if m == nil {
+		m = http.NewServeMux()
+	}
+	if options.ErrorHandlerFunc == nil {
+		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}
+
+	wrapper := ServerInterfaceWrapper{
+		Handler:            si,
+		HandlerMiddlewares: options.Middlewares,
+		ErrorHandlerFunc:   options.ErrorHandlerFunc,
+	}
+
+	m.HandleFunc("GET "+options.BaseURL+"/healthz", wrapper.Healthz)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles", wrapper.ListProfiles)
+	m.HandleFunc("PATCH "+options.BaseURL+"/v1/profiles", wrapper.ModifyProfiles)
+	m.HandleFunc("POST "+options.BaseURL+"/v1/profiles", wrapper.CreateProfile)
+	m.HandleFunc("PUT "+options.BaseURL+"/v1/profiles", wrapper.UpsertProfile)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles/count", wrapper.CountProfiles)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles/stats", wrapper.GetProfileStats)
+	m.HandleFunc("DELETE "+options.BaseURL+"/v1/profiles/{id}", wrapper.DeleteProfile)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles/{id}", wrapper.GetProfileById)
+	m.HandleFunc("HEAD "+options.BaseURL+"/v1/profiles/{id}", wrapper.HeadProfileById)
+	m.HandleFunc("PATCH "+options.BaseURL+"/v1/profiles/{id}", wrapper.ModifyProfile)
+	m.HandleFunc("PUT "+options.BaseURL+"/v1/profiles/{id}", wrapper.UpdateProfile)
+	m.HandleFunc("POST "+options.BaseURL+"/v1/profiles/{id}/restore", wrapper.RestoreProfile)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles:checkEmail", wrapper.CheckProfileEmail)
+	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles:export", wrapper.ExportProfiles)
+
+	return m
+}
+
+type PreconditionFailedResponseResponseHeaders struct {
+	ETag ETagValue
+}
+type PreconditionFailedResponseApplicationProblemPlusJSONResponse struct {
+	Body Problem
+
+	Headers PreconditionFailedResponseResponseHeaders
+}
+
+type ProblemResponseApplicationProblemPlusJSONResponse Problem
+
+type HealthzRequestObject struct {
+}
+
+type HealthzResponseObject interface {
+	VisitHealthzResponse(w http.ResponseWriter) error
+}
+
+type Healthz204Response struct {
+}
+
+func (response Healthz204Response) VisitHealthzResponse(w http.ResponseWriter) error {
+	w.WriteHeader(204)
+	return nil
+}
+
+type ListProfilesRequestObject struct {
+	Params ListProfilesParams
+}
+
+type ListProfilesResponseObject interface {
+	VisitListProfilesResponse(w http.ResponseWriter) error
+}
+
+type ListProfiles200ResponseHeaders struct {
+	ETag                    ETagValue
+	Link                    string
+	XRateLimitLimit         int
+	XRateLimitRemaining     int
+	XRateLimitResetSeconds  int
+	XRateLimitWindowSeconds int
+}
+
+type ListProfiles200JSONResponse struct {
+	Body    SuccessProfileList
+	Headers ListProfiles200ResponseHeaders
+}
+
+func (response ListProfiles200JSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Link", fmt.Sprint(response.Headers.Link))
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
+	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
+	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type ListProfiles304ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type ListProfiles304Response struct {
+	Headers ListProfiles304ResponseHeaders
+}
+
+func (response ListProfiles304Response) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type ListProfiles400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response ListProfiles400ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProfiles401ApplicationProblemPlusJSONResponse Problem
+
+func (response ListProfiles401ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProfiles403ApplicationProblemPlusJSONResponse Problem
+
+func (response ListProfiles403ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProfiles429ApplicationProblemPlusJSONResponse Problem
+
+func (response ListProfiles429ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(429)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ListProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response ListProfilesdefaultApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
 
-		params.IfMatch = IfMatch
+	return json.NewEncoder(w).Encode(response.Body)
+}
 
-	} else {
-		err := fmt.Errorf("Header parameter If-Match is required, but not found")
-		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "If-Match", Err: err})
-		return
-	}
+type ModifyProfilesRequestObject struct {
+	Body *ModifyProfilesJSONRequestBody
+}
 
-	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateProfile(w, r, id, params)
-	}))
+type ModifyProfilesResponseObject interface {
+	VisitModifyProfilesResponse(w http.ResponseWriter) error
+}
 
-	for _, middleware := range siw.HandlerMiddlewares {
-		handler = middleware(handler)
-	}
+type ModifyProfiles207JSONResponse ProfilePatchResults
 
-	handler.ServeHTTP(w, r)
+func (response ModifyProfiles207JSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(207)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type UnescapedCookieParamError struct {
-	ParamName string
-	Err       error
+type ModifyProfiles400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (e *UnescapedCookieParamError) Error() string {
-	return fmt.Sprintf("error unescaping cookie parameter '%s'", e.ParamName)
+func (response ModifyProfiles400ApplicationProblemPlusJSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *UnescapedCookieParamError) Unwrap() error {
-	return e.Err
+type ModifyProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
 }
 
-type UnmarshalingParamError struct {
-	ParamName string
-	Err       error
+func (response ModifyProfilesdefaultApplicationProblemPlusJSONResponse) VisitModifyProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-func (e *UnmarshalingParamError) Error() string {
-	return fmt.Sprintf("Error unmarshaling parameter %s as JSON: %s", e.ParamName, e.Err.Error())
+type CreateProfileRequestObject struct {
+	Body *CreateProfileJSONRequestBody
 }
 
-func (e *UnmarshalingParamError) Unwrap() error {
-	return e.Err
+type CreateProfileResponseObject interface {
+	VisitCreateProfileResponse(w http.ResponseWriter) error
 }
 
-type RequiredParamError struct {
-	ParamName string
+type CreateProfile201ResponseHeaders struct {
+	Location                string
+	XRateLimitLimit         int
+	XRateLimitRemaining     int
+	XRateLimitResetSeconds  int
+	XRateLimitWindowSeconds int
 }
 
-func (e *RequiredParamError) Error() string {
-	return fmt.Sprintf("Query argument %s is required, but not found", e.ParamName)
+type CreateProfile201JSONResponse struct {
+	Body    SuccessProfile
+	Headers CreateProfile201ResponseHeaders
 }
 
-type RequiredHeaderError struct {
-	ParamName string
-	Err       error
+func (response CreateProfile201JSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
+	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
+	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
+	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-func (e *RequiredHeaderError) Error() string {
-	return fmt.Sprintf("Header parameter %s is required, but not found", e.ParamName)
+type CreateProfile400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (e *RequiredHeaderError) Unwrap() error {
-	return e.Err
+func (response CreateProfile400ApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type InvalidParamFormatError struct {
-	ParamName string
-	Err       error
+type CreateProfile422ApplicationProblemPlusJSONResponse Problem
+
+func (response CreateProfile422ApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-func (e *InvalidParamFormatError) Error() string {
-	return fmt.Sprintf("Invalid format for parameter %s: %s", e.ParamName, e.Err.Error())
+type CreateProfiledefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
 }
 
-func (e *InvalidParamFormatError) Unwrap() error {
-	return e.Err
+func (response CreateProfiledefaultApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type TooManyValuesForParamError struct {
-	ParamName string
-	Count     int
+type UpsertProfileRequestObject struct {
+	Params UpsertProfileParams
+	Body   *UpsertProfileJSONRequestBody
 }
 
-func (e *TooManyValuesForParamError) Error() string {
-	return fmt.Sprintf("Expected one value for %s, got %d", e.ParamName, e.Count)
+type UpsertProfileResponseObject interface {
+	VisitUpsertProfileResponse(w http.ResponseWriter) error
 }
 
-// Handler creates http.Handler with routing matching OpenAPI spec.
-func Handler(si ServerInterface) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{})
+type UpsertProfile200ResponseHeaders struct {
+	ETag ETagValue
 }
 
-// ServeMux is an abstraction of http.ServeMux.
-type ServeMux interface {
-	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
-	ServeHTTP(w http.ResponseWriter, r *http.Request)
+type UpsertProfile200JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpsertProfile200ResponseHeaders
 }
 
-type StdHTTPServerOptions struct {
-	BaseURL          string
-	BaseRouter       ServeMux
-	Middlewares      []MiddlewareFunc
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+func (response UpsertProfile200JSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-// HandlerFromMux creates http.Handler with routing matching OpenAPI spec based on the provided mux.
-func HandlerFromMux(si ServerInterface, m ServeMux) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseRouter: m,
-	})
+type UpsertProfile201ResponseHeaders struct {
+	ETag     ETagValue
+	Location string
 }
 
-func HandlerFromMuxWithBaseURL(si ServerInterface, m ServeMux, baseURL string) http.Handler {
-	return HandlerWithOptions(si, StdHTTPServerOptions{
-		BaseURL:    baseURL,
-		BaseRouter: m,
-	})
+type UpsertProfile201JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpsertProfile201ResponseHeaders
 }
 
-// HandlerWithOptions creates http.Handler with additional options
-func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.Handler {
-	m := options.BaseRouter
+func (response UpsertProfile201JSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(201)
 
-	if m == nil {
-		m = http.NewServeMux()
-	}
-	if options.ErrorHandlerFunc == nil {
-		options.ErrorHandlerFunc = func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-		}
-	}
+	return json.NewEncoder(w).Encode(response.Body)
+}
 
-	wrapper := ServerInterfaceWrapper{
-		Handler:            si,
-		HandlerMiddlewares: options.Middlewares,
-		ErrorHandlerFunc:   options.ErrorHandlerFunc,
-	}
+type UpsertProfile400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
 
-	m.HandleFunc("GET "+options.BaseURL+"/healthz", wrapper.Healthz)
-	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles", wrapper.ListProfiles)
-	m.HandleFunc("POST "+options.BaseURL+"/v1/profiles", wrapper.CreateProfile)
-	m.HandleFunc("DELETE "+options.BaseURL+"/v1/profiles/{id}", wrapper.DeleteProfile)
-	m.HandleFunc("GET "+options.BaseURL+"/v1/profiles/{id}", wrapper.GetProfileById)
-	m.HandleFunc("PATCH "+options.BaseURL+"/v1/profiles/{id}", wrapper.ModifyProfile)
-	m.HandleFunc("PUT "+options.BaseURL+"/v1/profiles/{id}", wrapper.UpdateProfile)
+func (response UpsertProfile400ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
 
-	return m
+	return json.NewEncoder(w).Encode(response)
 }
 
-type PreconditionFailedResponseResponseHeaders struct {
-	ETag ETagValue
+type UpsertProfile409ApplicationProblemPlusJSONResponse Problem
+
+func (response UpsertProfile409ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
 }
-type PreconditionFailedResponseApplicationProblemPlusJSONResponse struct {
-	Body Problem
 
-	Headers PreconditionFailedResponseResponseHeaders
+type UpsertProfile422ApplicationProblemPlusJSONResponse Problem
+
+func (response UpsertProfile422ApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
 }
 
-type ProblemResponseApplicationProblemPlusJSONResponse Problem
+type UpsertProfiledefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
 
-type HealthzRequestObject struct {
+func (response UpsertProfiledefaultApplicationProblemPlusJSONResponse) VisitUpsertProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type HealthzResponseObject interface {
-	VisitHealthzResponse(w http.ResponseWriter) error
+type CountProfilesRequestObject struct {
+	Params CountProfilesParams
 }
 
-type Healthz204Response struct {
+type CountProfilesResponseObject interface {
+	VisitCountProfilesResponse(w http.ResponseWriter) error
+}
+
+type CountProfiles200JSONResponse SuccessProfileCount
+
+func (response CountProfiles200JSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CountProfiles400ApplicationProblemPlusJSONResponse struct {
+	ProblemResponseApplicationProblemPlusJSONResponse
+}
+
+func (response CountProfiles400ApplicationProblemPlusJSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type CountProfilesdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response CountProfilesdefaultApplicationProblemPlusJSONResponse) VisitCountProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type GetProfileStatsRequestObject struct {
 }
 
-func (response Healthz204Response) VisitHealthzResponse(w http.ResponseWriter) error {
-	w.WriteHeader(204)
-	return nil
+type GetProfileStatsResponseObject interface {
+	VisitGetProfileStatsResponse(w http.ResponseWriter) error
+}
+
+type GetProfileStats200JSONResponse SuccessProfileStats
+
+func (response GetProfileStats200JSONResponse) VisitGetProfileStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetProfileStatsdefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
+}
+
+func (response GetProfileStatsdefaultApplicationProblemPlusJSONResponse) VisitGetProfileStatsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(response.StatusCode)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ListProfilesRequestObject struct {
-	Params ListProfilesParams
+type DeleteProfileRequestObject struct {
+	Id     ProfileId `json:"id"`
+	Params DeleteProfileParams
 }
 
-type ListProfilesResponseObject interface {
-	VisitListProfilesResponse(w http.ResponseWriter) error
+type DeleteProfileResponseObject interface {
+	VisitDeleteProfileResponse(w http.ResponseWriter) error
 }
 
-type ListProfiles200ResponseHeaders struct {
-	ETag                    ETagValue
-	Link                    string
+type DeleteProfile204ResponseHeaders struct {
 	XRateLimitLimit         int
 	XRateLimitRemaining     int
 	XRateLimitResetSeconds  int
 	XRateLimitWindowSeconds int
 }
 
-type ListProfiles200JSONResponse struct {
-	Body    SuccessProfileList
-	Headers ListProfiles200ResponseHeaders
+type DeleteProfile204Response struct {
+	Headers DeleteProfile204ResponseHeaders
 }
 
-func (response ListProfiles200JSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
-	w.Header().Set("Link", fmt.Sprint(response.Headers.Link))
+func (response DeleteProfile204Response) VisitDeleteProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
 	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
 	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
 	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
-	w.WriteHeader(200)
-
-	return json.NewEncoder(w).Encode(response.Body)
+	w.WriteHeader(204)
+	return nil
 }
 
-type ListProfiles400ApplicationProblemPlusJSONResponse struct {
+type DeleteProfile400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response ListProfiles400ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+func (response DeleteProfile400ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListProfiles401ApplicationProblemPlusJSONResponse Problem
+type DeleteProfile404ApplicationProblemPlusJSONResponse Problem
 
-func (response ListProfiles401ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+func (response DeleteProfile404ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(401)
+	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ListProfiles429ApplicationProblemPlusJSONResponse Problem
+type DeleteProfile412ApplicationProblemPlusJSONResponse struct {
+	PreconditionFailedResponseApplicationProblemPlusJSONResponse
+}
 
-func (response ListProfiles429ApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+func (response DeleteProfile412ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(429)
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(412)
 
-	return json.NewEncoder(w).Encode(response)
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ListProfilesdefaultApplicationProblemPlusJSONResponse struct {
+type DeleteProfiledefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response ListProfilesdefaultApplicationProblemPlusJSONResponse) VisitListProfilesResponse(w http.ResponseWriter) error {
+func (response DeleteProfiledefaultApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type CreateProfileRequestObject struct {
-	Body *CreateProfileJSONRequestBody
+type GetProfileByIdRequestObject struct {
+	Id     ProfileId `json:"id"`
+	Params GetProfileByIdParams
 }
 
-type CreateProfileResponseObject interface {
-	VisitCreateProfileResponse(w http.ResponseWriter) error
+type GetProfileByIdResponseObject interface {
+	VisitGetProfileByIdResponse(w http.ResponseWriter) error
 }
 
-type CreateProfile201ResponseHeaders struct {
-	Location                string
+type GetProfileById200ResponseHeaders struct {
+	ETag                    ETagValue
 	XRateLimitLimit         int
 	XRateLimitRemaining     int
 	XRateLimitResetSeconds  int
 	XRateLimitWindowSeconds int
 }
 
-type CreateProfile201JSONResponse struct {
+type GetProfileById200JSONResponse struct {
 	Body    SuccessProfile
-	Headers CreateProfile201ResponseHeaders
+	Headers GetProfileById200ResponseHeaders
 }
 
-func (response CreateProfile201JSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+func (response GetProfileById200JSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
 	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
 	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
 	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
-	w.WriteHeader(201)
+	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type CreateProfile400ApplicationProblemPlusJSONResponse struct {
+type GetProfileById304ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type GetProfileById304Response struct {
+	Headers GetProfileById304ResponseHeaders
+}
+
+func (response GetProfileById304Response) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(304)
+	return nil
+}
+
+type GetProfileById400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response CreateProfile400ApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+func (response GetProfileById400ApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateProfile422ApplicationProblemPlusJSONResponse Problem
+type GetProfileById404ApplicationProblemPlusJSONResponse Problem
 
-func (response CreateProfile422ApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+func (response GetProfileById404ApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(422)
+	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type CreateProfiledefaultApplicationProblemPlusJSONResponse struct {
+type GetProfileByIddefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response CreateProfiledefaultApplicationProblemPlusJSONResponse) VisitCreateProfileResponse(w http.ResponseWriter) error {
+func (response GetProfileByIddefaultApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type DeleteProfileRequestObject struct {
+type HeadProfileByIdRequestObject struct {
+	Id ProfileId `json:"id"`
+}
+
+type HeadProfileByIdResponseObject interface {
+	VisitHeadProfileByIdResponse(w http.ResponseWriter) error
+}
+
+type HeadProfileById200ResponseHeaders struct {
+	ETag ETagValue
+}
+
+type HeadProfileById200Response struct {
+	Headers HeadProfileById200ResponseHeaders
+}
+
+func (response HeadProfileById200Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(200)
+	return nil
+}
+
+type HeadProfileById400Response struct {
+}
+
+func (response HeadProfileById400Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(400)
+	return nil
+}
+
+type HeadProfileById404Response struct {
+}
+
+func (response HeadProfileById404Response) VisitHeadProfileByIdResponse(w http.ResponseWriter) error {
+	w.WriteHeader(404)
+	return nil
+}
+
+type ModifyProfileRequestObject struct {
 	Id     ProfileId `json:"id"`
-	Params DeleteProfileParams
+	Params ModifyProfileParams
+	Body   *ModifyProfileJSONRequestBody
 }
 
-type DeleteProfileResponseObject interface {
-	VisitDeleteProfileResponse(w http.ResponseWriter) error
+type ModifyProfileResponseObject interface {
+	VisitModifyProfileResponse(w http.ResponseWriter) error
 }
 
-type DeleteProfile204ResponseHeaders struct {
+type ModifyProfile200ResponseHeaders struct {
+	ETag                    ETagValue
 	XRateLimitLimit         int
 	XRateLimitRemaining     int
 	XRateLimitResetSeconds  int
 	XRateLimitWindowSeconds int
 }
 
-type DeleteProfile204Response struct {
-	Headers DeleteProfile204ResponseHeaders
+type ModifyProfile200JSONResponse struct {
+	Body    SuccessProfile
+	Headers ModifyProfile200ResponseHeaders
 }
 
-func (response DeleteProfile204Response) VisitDeleteProfileResponse(w http.ResponseWriter) error {
+func (response ModifyProfile200JSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
 	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
 	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
 	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
-	w.WriteHeader(204)
-	return nil
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type DeleteProfile400ApplicationProblemPlusJSONResponse struct {
+type ModifyProfile400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response DeleteProfile400ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
+func (response ModifyProfile400ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteProfile404ApplicationProblemPlusJSONResponse Problem
+type ModifyProfile404ApplicationProblemPlusJSONResponse Problem
 
-func (response DeleteProfile404ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
+func (response ModifyProfile404ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type DeleteProfile412ApplicationProblemPlusJSONResponse struct {
+type ModifyProfile412ApplicationProblemPlusJSONResponse struct {
 	PreconditionFailedResponseApplicationProblemPlusJSONResponse
 }
 
-func (response DeleteProfile412ApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
+func (response ModifyProfile412ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.WriteHeader(412)
@@ -1157,27 +2346,38 @@ func (response DeleteProfile412ApplicationProblemPlusJSONResponse) VisitDeletePr
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type DeleteProfiledefaultApplicationProblemPlusJSONResponse struct {
+type ModifyProfile422ApplicationProblemPlusJSONResponse Problem
+
+func (response ModifyProfile422ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type ModifyProfiledefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response DeleteProfiledefaultApplicationProblemPlusJSONResponse) VisitDeleteProfileResponse(w http.ResponseWriter) error {
+func (response ModifyProfiledefaultApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type GetProfileByIdRequestObject struct {
-	Id ProfileId `json:"id"`
+type UpdateProfileRequestObject struct {
+	Id     ProfileId `json:"id"`
+	Params UpdateProfileParams
+	Body   *UpdateProfileJSONRequestBody
 }
 
-type GetProfileByIdResponseObject interface {
-	VisitGetProfileByIdResponse(w http.ResponseWriter) error
+type UpdateProfileResponseObject interface {
+	VisitUpdateProfileResponse(w http.ResponseWriter) error
 }
 
-type GetProfileById200ResponseHeaders struct {
+type UpdateProfile200ResponseHeaders struct {
 	ETag                    ETagValue
 	XRateLimitLimit         int
 	XRateLimitRemaining     int
@@ -1185,12 +2385,12 @@ type GetProfileById200ResponseHeaders struct {
 	XRateLimitWindowSeconds int
 }
 
-type GetProfileById200JSONResponse struct {
+type UpdateProfile200JSONResponse struct {
 	Body    SuccessProfile
-	Headers GetProfileById200ResponseHeaders
+	Headers UpdateProfile200ResponseHeaders
 }
 
-func (response GetProfileById200JSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+func (response UpdateProfile200JSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
@@ -1202,98 +2402,138 @@ func (response GetProfileById200JSONResponse) VisitGetProfileByIdResponse(w http
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type GetProfileById400ApplicationProblemPlusJSONResponse struct {
+type UpdateProfile201ResponseHeaders struct {
+	ETag     ETagValue
+	Location string
+}
+
+type UpdateProfile201JSONResponse struct {
+	Body    SuccessProfile
+	Headers UpdateProfile201ResponseHeaders
+}
+
+func (response UpdateProfile201JSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.Header().Set("Location", fmt.Sprint(response.Headers.Location))
+	w.WriteHeader(201)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateProfile400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response GetProfileById400ApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+func (response UpdateProfile400ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetProfileById404ApplicationProblemPlusJSONResponse Problem
+type UpdateProfile404ApplicationProblemPlusJSONResponse Problem
 
-func (response GetProfileById404ApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+func (response UpdateProfile404ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(404)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetProfileByIddefaultApplicationProblemPlusJSONResponse struct {
+type UpdateProfile409ApplicationProblemPlusJSONResponse Problem
+
+func (response UpdateProfile409ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(409)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProfile412ApplicationProblemPlusJSONResponse struct {
+	PreconditionFailedResponseApplicationProblemPlusJSONResponse
+}
+
+func (response UpdateProfile412ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
+	w.WriteHeader(412)
+
+	return json.NewEncoder(w).Encode(response.Body)
+}
+
+type UpdateProfile422ApplicationProblemPlusJSONResponse Problem
+
+func (response UpdateProfile422ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(422)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProfile428ApplicationProblemPlusJSONResponse Problem
+
+func (response UpdateProfile428ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(428)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type UpdateProfiledefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response GetProfileByIddefaultApplicationProblemPlusJSONResponse) VisitGetProfileByIdResponse(w http.ResponseWriter) error {
+func (response UpdateProfiledefaultApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ModifyProfileRequestObject struct {
+type RestoreProfileRequestObject struct {
 	Id     ProfileId `json:"id"`
-	Params ModifyProfileParams
-	Body   *ModifyProfileJSONRequestBody
+	Params RestoreProfileParams
 }
 
-type ModifyProfileResponseObject interface {
-	VisitModifyProfileResponse(w http.ResponseWriter) error
+type RestoreProfileResponseObject interface {
+	VisitRestoreProfileResponse(w http.ResponseWriter) error
 }
 
-type ModifyProfile200ResponseHeaders struct {
-	ETag                    ETagValue
-	XRateLimitLimit         int
-	XRateLimitRemaining     int
-	XRateLimitResetSeconds  int
-	XRateLimitWindowSeconds int
+type RestoreProfile200ResponseHeaders struct {
+	ETag ETagValue
 }
 
-type ModifyProfile200JSONResponse struct {
+type RestoreProfile200JSONResponse struct {
 	Body    SuccessProfile
-	Headers ModifyProfile200ResponseHeaders
+	Headers RestoreProfile200ResponseHeaders
 }
 
-func (response ModifyProfile200JSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+func (response RestoreProfile200JSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
-	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
-	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
-	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
-	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ModifyProfile400ApplicationProblemPlusJSONResponse struct {
+type RestoreProfile400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response ModifyProfile400ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+func (response RestoreProfile400ApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type ModifyProfile404ApplicationProblemPlusJSONResponse Problem
-
-func (response ModifyProfile404ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(404)
-
-	return json.NewEncoder(w).Encode(response)
-}
-
-type ModifyProfile412ApplicationProblemPlusJSONResponse struct {
+type RestoreProfile412ApplicationProblemPlusJSONResponse struct {
 	PreconditionFailedResponseApplicationProblemPlusJSONResponse
 }
 
-func (response ModifyProfile412ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+func (response RestoreProfile412ApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
 	w.WriteHeader(412)
@@ -1301,109 +2541,113 @@ func (response ModifyProfile412ApplicationProblemPlusJSONResponse) VisitModifyPr
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type ModifyProfile422ApplicationProblemPlusJSONResponse Problem
-
-func (response ModifyProfile422ApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(422)
-
-	return json.NewEncoder(w).Encode(response)
-}
-
-type ModifyProfiledefaultApplicationProblemPlusJSONResponse struct {
+type RestoreProfiledefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response ModifyProfiledefaultApplicationProblemPlusJSONResponse) VisitModifyProfileResponse(w http.ResponseWriter) error {
+func (response RestoreProfiledefaultApplicationProblemPlusJSONResponse) VisitRestoreProfileResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UpdateProfileRequestObject struct {
-	Id     ProfileId `json:"id"`
-	Params UpdateProfileParams
-	Body   *UpdateProfileJSONRequestBody
+type CheckProfileEmailRequestObject struct {
+	Params CheckProfileEmailParams
 }
 
-type UpdateProfileResponseObject interface {
-	VisitUpdateProfileResponse(w http.ResponseWriter) error
+type CheckProfileEmailResponseObject interface {
+	VisitCheckProfileEmailResponse(w http.ResponseWriter) error
 }
 
-type UpdateProfile200ResponseHeaders struct {
-	ETag                    ETagValue
-	XRateLimitLimit         int
-	XRateLimitRemaining     int
-	XRateLimitResetSeconds  int
-	XRateLimitWindowSeconds int
+type CheckProfileEmail200ResponseHeaders struct {
+	CacheControl string
 }
 
-type UpdateProfile200JSONResponse struct {
-	Body    SuccessProfile
-	Headers UpdateProfile200ResponseHeaders
+type CheckProfileEmail200JSONResponse struct {
+	Body    SuccessEmailAvailability
+	Headers CheckProfileEmail200ResponseHeaders
 }
 
-func (response UpdateProfile200JSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+func (response CheckProfileEmail200JSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
-	w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit))
-	w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(response.Headers.XRateLimitRemaining))
-	w.Header().Set("X-RateLimit-Reset-Seconds", fmt.Sprint(response.Headers.XRateLimitResetSeconds))
-	w.Header().Set("X-RateLimit-Window-Seconds", fmt.Sprint(response.Headers.XRateLimitWindowSeconds))
+	w.Header().Set("Cache-Control", fmt.Sprint(response.Headers.CacheControl))
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UpdateProfile400ApplicationProblemPlusJSONResponse struct {
+type CheckProfileEmail400ApplicationProblemPlusJSONResponse struct {
 	ProblemResponseApplicationProblemPlusJSONResponse
 }
 
-func (response UpdateProfile400ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+func (response CheckProfileEmail400ApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(400)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UpdateProfile404ApplicationProblemPlusJSONResponse Problem
+type CheckProfileEmail429ApplicationProblemPlusJSONResponse Problem
 
-func (response UpdateProfile404ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+func (response CheckProfileEmail429ApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(404)
+	w.WriteHeader(429)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type UpdateProfile412ApplicationProblemPlusJSONResponse struct {
-	PreconditionFailedResponseApplicationProblemPlusJSONResponse
+type CheckProfileEmaildefaultApplicationProblemPlusJSONResponse struct {
+	Body       Problem
+	StatusCode int
 }
 
-func (response UpdateProfile412ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+func (response CheckProfileEmaildefaultApplicationProblemPlusJSONResponse) VisitCheckProfileEmailResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
-	w.Header().Set("ETag", fmt.Sprint(response.Headers.ETag))
-	w.WriteHeader(412)
+	w.WriteHeader(response.StatusCode)
 
 	return json.NewEncoder(w).Encode(response.Body)
 }
 
-type UpdateProfile422ApplicationProblemPlusJSONResponse Problem
+type ExportProfilesRequestObject struct {
+}
 
-func (response UpdateProfile422ApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(422)
+type ExportProfilesResponseObject interface {
+	VisitExportProfilesResponse(w http.ResponseWriter) error
+}
 
-	return json.NewEncoder(w).Encode(response)
+type ExportProfiles200ResponseHeaders struct {
+	CacheControl string
 }
 
-type UpdateProfiledefaultApplicationProblemPlusJSONResponse struct {
+type ExportProfiles200ApplicationxNdjsonResponse struct {
+	Body          io.Reader
+	Headers       ExportProfiles200ResponseHeaders
+	ContentLength int64
+}
+
+func (response ExportProfiles200ApplicationxNdjsonResponse) VisitExportProfilesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.Header().Set("Cache-Control", fmt.Sprint(response.Headers.CacheControl))
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type ExportProfilesdefaultApplicationProblemPlusJSONResponse struct {
 	Body       Problem
 	StatusCode int
 }
 
-func (response UpdateProfiledefaultApplicationProblemPlusJSONResponse) VisitUpdateProfileResponse(w http.ResponseWriter) error {
+func (response ExportProfilesdefaultApplicationProblemPlusJSONResponse) VisitExportProfilesResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(response.StatusCode)
 
@@ -1418,21 +2662,45 @@ type StrictServerInterface interface {
 	// List profiles (offset or cursor pagination)
 	// (GET /v1/profiles)
 	ListProfiles(ctx context.Context, request ListProfilesRequestObject) (ListProfilesResponseObject, error)
+	// Patch many profiles at once
+	// (PATCH /v1/profiles)
+	ModifyProfiles(ctx context.Context, request ModifyProfilesRequestObject) (ModifyProfilesResponseObject, error)
 	// Create a profile
 	// (POST /v1/profiles)
 	CreateProfile(ctx context.Context, request CreateProfileRequestObject) (CreateProfileResponseObject, error)
+	// Create or update the profile owning an email
+	// (PUT /v1/profiles)
+	UpsertProfile(ctx context.Context, request UpsertProfileRequestObject) (UpsertProfileResponseObject, error)
+	// Count profiles matching a filter
+	// (GET /v1/profiles/count)
+	CountProfiles(ctx context.Context, request CountProfilesRequestObject) (CountProfilesResponseObject, error)
+	// Aggregate profile statistics
+	// (GET /v1/profiles/stats)
+	GetProfileStats(ctx context.Context, request GetProfileStatsRequestObject) (GetProfileStatsResponseObject, error)
 	// Delete a profile
 	// (DELETE /v1/profiles/{id})
 	DeleteProfile(ctx context.Context, request DeleteProfileRequestObject) (DeleteProfileResponseObject, error)
 	// Get profile by ID
 	// (GET /v1/profiles/{id})
 	GetProfileById(ctx context.Context, request GetProfileByIdRequestObject) (GetProfileByIdResponseObject, error)
+	// Check whether a profile exists
+	// (HEAD /v1/profiles/{id})
+	HeadProfileById(ctx context.Context, request HeadProfileByIdRequestObject) (HeadProfileByIdResponseObject, error)
 	// Patch an existing profile
 	// (PATCH /v1/profiles/{id})
 	ModifyProfile(ctx context.Context, request ModifyProfileRequestObject) (ModifyProfileResponseObject, error)
-	// Update an existing profile
+	// Update an existing profile, or create one at this ID
 	// (PUT /v1/profiles/{id})
 	UpdateProfile(ctx context.Context, request UpdateProfileRequestObject) (UpdateProfileResponseObject, error)
+	// Restore a deleted profile
+	// (POST /v1/profiles/{id}/restore)
+	RestoreProfile(ctx context.Context, request RestoreProfileRequestObject) (RestoreProfileResponseObject, error)
+	// Check whether an email can be used for a new profile
+	// (GET /v1/profiles:checkEmail)
+	CheckProfileEmail(ctx context.Context, request CheckProfileEmailRequestObject) (CheckProfileEmailResponseObject, error)
+	// Export every live profile as newline-delimited JSON
+	// (GET /v1/profiles:export)
+	ExportProfiles(ctx context.Context, request ExportProfilesRequestObject) (ExportProfilesResponseObject, error)
 }
 
 type StrictHandlerFunc = strictnethttp.StrictHTTPHandlerFunc
@@ -1514,6 +2782,37 @@ func (sh *strictHandler) ListProfiles(w http.ResponseWriter, r *http.Request, pa
 	}
 }
 
+// ModifyProfiles operation middleware
+func (sh *strictHandler) ModifyProfiles(w http.ResponseWriter, r *http.Request) {
+	var request ModifyProfilesRequestObject
+
+	var body ModifyProfilesJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ModifyProfiles(ctx, request.(ModifyProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ModifyProfiles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ModifyProfilesResponseObject); ok {
+		if err := validResponse.VisitModifyProfilesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // CreateProfile operation middleware
 func (sh *strictHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 	var request CreateProfileRequestObject
@@ -1545,6 +2844,89 @@ func (sh *strictHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// UpsertProfile operation middleware
+func (sh *strictHandler) UpsertProfile(w http.ResponseWriter, r *http.Request, params UpsertProfileParams) {
+	var request UpsertProfileRequestObject
+
+	request.Params = params
+
+	var body UpsertProfileJSONRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		sh.options.RequestErrorHandlerFunc(w, r, fmt.Errorf("can't decode JSON body: %w", err))
+		return
+	}
+	request.Body = &body
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.UpsertProfile(ctx, request.(UpsertProfileRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "UpsertProfile")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(UpsertProfileResponseObject); ok {
+		if err := validResponse.VisitUpsertProfileResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CountProfiles operation middleware
+func (sh *strictHandler) CountProfiles(w http.ResponseWriter, r *http.Request, params CountProfilesParams) {
+	var request CountProfilesRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CountProfiles(ctx, request.(CountProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CountProfiles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CountProfilesResponseObject); ok {
+		if err := validResponse.VisitCountProfilesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// GetProfileStats operation middleware
+func (sh *strictHandler) GetProfileStats(w http.ResponseWriter, r *http.Request) {
+	var request GetProfileStatsRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.GetProfileStats(ctx, request.(GetProfileStatsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetProfileStats")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(GetProfileStatsResponseObject); ok {
+		if err := validResponse.VisitGetProfileStatsResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // DeleteProfile operation middleware
 func (sh *strictHandler) DeleteProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params DeleteProfileParams) {
 	var request DeleteProfileRequestObject
@@ -1573,10 +2955,11 @@ func (sh *strictHandler) DeleteProfile(w http.ResponseWriter, r *http.Request, i
 }
 
 // GetProfileById operation middleware
-func (sh *strictHandler) GetProfileById(w http.ResponseWriter, r *http.Request, id ProfileId) {
+func (sh *strictHandler) GetProfileById(w http.ResponseWriter, r *http.Request, id ProfileId, params GetProfileByIdParams) {
 	var request GetProfileByIdRequestObject
 
 	request.Id = id
+	request.Params = params
 
 	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
 		return sh.ssi.GetProfileById(ctx, request.(GetProfileByIdRequestObject))
@@ -1598,6 +2981,32 @@ func (sh *strictHandler) GetProfileById(w http.ResponseWriter, r *http.Request,
 	}
 }
 
+// HeadProfileById operation middleware
+func (sh *strictHandler) HeadProfileById(w http.ResponseWriter, r *http.Request, id ProfileId) {
+	var request HeadProfileByIdRequestObject
+
+	request.Id = id
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.HeadProfileById(ctx, request.(HeadProfileByIdRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "HeadProfileById")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(HeadProfileByIdResponseObject); ok {
+		if err := validResponse.VisitHeadProfileByIdResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
 // ModifyProfile operation middleware
 func (sh *strictHandler) ModifyProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params ModifyProfileParams) {
 	var request ModifyProfileRequestObject
@@ -1665,3 +3074,80 @@ func (sh *strictHandler) UpdateProfile(w http.ResponseWriter, r *http.Request, i
 		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
 	}
 }
+
+// RestoreProfile operation middleware
+func (sh *strictHandler) RestoreProfile(w http.ResponseWriter, r *http.Request, id ProfileId, params RestoreProfileParams) {
+	var request RestoreProfileRequestObject
+
+	request.Id = id
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.RestoreProfile(ctx, request.(RestoreProfileRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "RestoreProfile")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(RestoreProfileResponseObject); ok {
+		if err := validResponse.VisitRestoreProfileResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// CheckProfileEmail operation middleware
+func (sh *strictHandler) CheckProfileEmail(w http.ResponseWriter, r *http.Request, params CheckProfileEmailParams) {
+	var request CheckProfileEmailRequestObject
+
+	request.Params = params
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.CheckProfileEmail(ctx, request.(CheckProfileEmailRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "CheckProfileEmail")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(CheckProfileEmailResponseObject); ok {
+		if err := validResponse.VisitCheckProfileEmailResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
+
+// ExportProfiles operation middleware
+func (sh *strictHandler) ExportProfiles(w http.ResponseWriter, r *http.Request) {
+	var request ExportProfilesRequestObject
+
+	handler := func(ctx context.Context, w http.ResponseWriter, r *http.Request, request interface{}) (interface{}, error) {
+		return sh.ssi.ExportProfiles(ctx, request.(ExportProfilesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "ExportProfiles")
+	}
+
+	response, err := handler(r.Context(), w, r, request)
+
+	if err != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, err)
+	} else if validResponse, ok := response.(ExportProfilesResponseObject); ok {
+		if err := validResponse.VisitExportProfilesResponse(w); err != nil {
+			sh.options.ResponseErrorHandlerFunc(w, r, err)
+		}
+	} else if response != nil {
+		sh.options.ResponseErrorHandlerFunc(w, r, fmt.Errorf("unexpected response type: %T", response))
+	}
+}
@@ -26,3 +26,21 @@ package serde
 func Ptr[T any](v T) *T {
 	return &v
 }
+
+// PtrOrNil is Ptr for optional fields: the zero value maps to nil so it is omitted.
+func PtrOrNil[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// Deref returns *p, or the zero value when p is nil.
+func Deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+	return *p
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/auth"
+	"app/modules/clock"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	// ErrNotFound is returned by a Store for a key it does not hold.
+	ErrNotFound = errors.New("apikey: key not found")
+	// ErrInvalidKey is returned by Verify for an unknown, revoked or expired key.
+	ErrInvalidKey = errors.New("apikey: invalid key")
+	// ErrInvalid is returned by Issue for a key that cannot be created as given.
+	ErrInvalid = errors.New("apikey: invalid key request")
+)
+
+const (
+	// ClaimKeyID is the principal claim holding the id of the key that
+	// authenticated the request.
+	ClaimKeyID = "api_key_id"
+
+	// secretPrefix makes leaked keys easy to spot for secret scanners.
+	secretPrefix = "ak_"
+	// secretBytes of randomness make the hash safe to look up without a slow KDF.
+	secretBytes = 32
+	hintLength  = len(secretPrefix) + 6
+)
+
+type (
+	// Key describes an issued API key. The secret itself is never kept.
+	Key struct {
+		ID      uuid.UUID `json:"id"`
+		Name    string    `json:"name"`
+		Subject string    `json:"subject"`
+		Scopes  []string  `json:"scopes"`
+		// Hint is the start of the secret, to tell keys apart without revealing them.
+		Hint      string     `json:"hint"`
+		CreatedAt time.Time  `json:"createdAt"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+		RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	}
+
+	// Store persists keys by the hash of their secret.
+	Store interface {
+		Insert(ctx context.Context, key Key, hash []byte) error
+		// FindByHash returns ErrNotFound for an unknown hash, revoked keys included.
+		FindByHash(ctx context.Context, hash []byte) (Key, error)
+		// List returns the keys of subject, or every key when subject is empty.
+		List(ctx context.Context, subject string) ([]Key, error)
+		// Revoke returns ErrNotFound for an unknown id; revoking twice keeps the first time.
+		Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	}
+
+	// Keys issues and verifies API keys on top of a Store.
+	Keys struct {
+		store Store
+		clock clock.Clock
+	}
+
+	// Option configures Keys.
+	Option func(*Keys)
+)
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(k *Keys) {
+		if c != nil {
+			k.clock = c
+		}
+	}
+}
+
+// New constructs Keys over store.
+func New(store Store, opts ...Option) *Keys {
+	k := &Keys{store: store, clock: clock.RealClock{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(k)
+		}
+	}
+	return k
+}
+
+// Issue creates a key for subject granting scopes, valid until expiresAt when set.
+// The returned secret is the only copy: it cannot be recovered later.
+func (k *Keys) Issue(ctx context.Context, name, subject string, scopes []string, expiresAt *time.Time) (string, Key, error) {
+	name, subject = strings.TrimSpace(name), strings.TrimSpace(subject)
+	if name == "" || subject == "" {
+		return "", Key{}, fmt.Errorf("%w: name and subject are required", ErrInvalid)
+	}
+	now := k.clock.Now().UTC()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return "", Key{}, fmt.Errorf("%w: expiresAt must be in the future", ErrInvalid)
+	}
+	for _, s := range scopes {
+		if s == "" || strings.ContainsAny(s, " \t\n") {
+			return "", Key{}, fmt.Errorf("%w: invalid scope %q", ErrInvalid, s)
+		}
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", Key{}, fmt.Errorf("apikey: generate id: %w", err)
+	}
+	raw := make([]byte, secretBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", Key{}, fmt.Errorf("apikey: generate secret: %w", err)
+	}
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	key := Key{
+		ID:        id,
+		Name:      name,
+		Subject:   subject,
+		Scopes:    append([]string{}, scopes...),
+		Hint:      secret[:hintLength],
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	if err := k.store.Insert(ctx, key, hash(secret)); err != nil {
+		return "", Key{}, fmt.Errorf("apikey: insert: %w", err)
+	}
+	return secret, key, nil
+}
+
+// Verify returns the key secret belongs to. Unknown, revoked and expired keys
+// all return ErrInvalidKey; other errors come from the store.
+func (k *Keys) Verify(ctx context.Context, secret string) (Key, error) {
+	if !strings.HasPrefix(secret, secretPrefix) || len(secret) < hintLength {
+		return Key{}, ErrInvalidKey
+	}
+	key, err := k.store.FindByHash(ctx, hash(secret))
+	if errors.Is(err, ErrNotFound) {
+		return Key{}, ErrInvalidKey
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("apikey: lookup: %w", err)
+	}
+	if key.RevokedAt != nil {
+		return Key{}, fmt.Errorf("%w: revoked", ErrInvalidKey)
+	}
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(k.clock.Now()) {
+		return Key{}, fmt.Errorf("%w: expired", ErrInvalidKey)
+	}
+	return key, nil
+}
+
+// List returns the keys of subject, or every key when subject is empty.
+func (k *Keys) List(ctx context.Context, subject string) ([]Key, error) {
+	keys, err := k.store.List(ctx, subject)
+	if err != nil {
+		return nil, fmt.Errorf("apikey: list: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke disables the key with id from now on.
+func (k *Keys) Revoke(ctx context.Context, id uuid.UUID) error {
+	if err := k.store.Revoke(ctx, id, k.clock.Now().UTC()); err != nil {
+		return fmt.Errorf("apikey: revoke %s: %w", id, err)
+	}
+	return nil
+}
+
+// Principal returns the principal a verified key authenticates.
+func (key Key) Principal() *auth.Principal {
+	return &auth.Principal{
+		Subject: key.Subject,
+		Scopes:  key.Scopes,
+		Claims: map[string]any{
+			ClaimKeyID:     key.ID.String(),
+			"api_key_name": key.Name,
+		},
+	}
+}
+
+// KeyIDFrom returns the id of the API key that authenticated p, if any.
+func KeyIDFrom(p *auth.Principal) (string, bool) {
+	if p == nil {
+		return "", false
+	}
+	id, ok := p.Claims[ClaimKeyID].(string)
+	return id, ok && id != ""
+}
+
+func hash(secret string) []byte {
+	sum := sha256.Sum256([]byte(secret))
+	return sum[:]
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apikey authenticates service callers with long-lived API keys.
+//
+// Keys are random secrets handed out once by Keys.Issue; only their SHA-256 hash
+// is stored (see modules/db/postgres/apikey), with the subject and scopes they
+// grant. Middleware resolves the X-API-Key header to an auth.Principal carrying the
+// key id in the ClaimKeyID claim, which the "api_key" rate limit strategy keys on.
+// Handler and RevokeHandler let operators manage the keys:
+//
+//	POST   /admin/api-keys          {"name", "subject", "scopes", "expiresAt"} -> secret, shown once
+//	GET    /admin/api-keys?subject= list, secrets never included
+//	DELETE /admin/api-keys/{id}     revoke
+package apikey
@@ -0,0 +1,150 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"app/modules/auth"
+	"app/modules/authz"
+	"app/modules/middleware/problem"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+type (
+	issueRequest struct {
+		Name      string     `json:"name"`
+		Subject   string     `json:"subject"`
+		Scopes    []string   `json:"scopes"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+
+	issueResponse struct {
+		Key
+		// Secret is only ever returned here.
+		Secret string `json:"secret"`
+	}
+)
+
+// maxIssueBody bounds the POST body, a key request is a few hundred bytes.
+const maxIssueBody = 16 << 10
+
+// Handler serves the key collection for operators holding scope: GET lists keys,
+// optionally of one ?subject=, POST issues a key and answers 201 with its secret.
+func Handler(keys *Keys, scope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.Require(r.Context(), scope); err != nil {
+			authz.WriteProblem(w, err)
+			return
+		}
+		principal, _ := auth.PrincipalFrom(r.Context())
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			list, err := keys.List(r.Context(), r.URL.Query().Get("subject"))
+			if err != nil {
+				slog.ErrorContext(r.Context(), "apikey: list failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("keys could not be listed, retry later"))
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string][]Key{"keys": list})
+
+		case http.MethodPost:
+			var req issueRequest
+			dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxIssueBody))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&req); err != nil {
+				problem.Write(w, problem.BadRequest("invalid JSON body"))
+				return
+			}
+			secret, key, err := keys.Issue(r.Context(), req.Name, req.Subject, req.Scopes, req.ExpiresAt)
+			if errors.Is(err, ErrInvalid) {
+				problem.Write(w, problem.New(
+					problem.WithTitle(http.StatusText(http.StatusUnprocessableEntity)),
+					problem.WithStatus(http.StatusUnprocessableEntity),
+					problem.WithDetail(err.Error()),
+					problem.WithCode("api_key.invalid"),
+				))
+				return
+			}
+			if err != nil {
+				slog.ErrorContext(r.Context(), "apikey: issue failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("key could not be issued, retry later"))
+				return
+			}
+			slog.InfoContext(r.Context(), "apikey: key issued by operator",
+				slog.String("operator", principal.Subject),
+				slog.String("key.id", key.ID.String()),
+				slog.String("key.subject", key.Subject),
+				slog.Any("key.scopes", key.Scopes),
+			)
+			w.Header().Set("Location", r.URL.Path+"/"+key.ID.String())
+			writeJSON(w, http.StatusCreated, issueResponse{Key: key, Secret: secret})
+
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+		}
+	})
+}
+
+// RevokeHandler serves DELETE on a key, whose id is the {id} path wildcard, for
+// operators holding scope. It answers 204, or 404 for an unknown key.
+func RevokeHandler(keys *Keys, scope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.Require(r.Context(), scope); err != nil {
+			authz.WriteProblem(w, err)
+			return
+		}
+		principal, _ := auth.PrincipalFrom(r.Context())
+		if r.Method != http.MethodDelete {
+			w.Header().Set("Allow", "DELETE")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+
+		id, err := uuid.FromString(r.PathValue("id"))
+		if err != nil {
+			problem.Write(w, problem.BadRequest("invalid key id", problem.WithInvalidParam("id", "must be a UUID")))
+			return
+		}
+		switch err := keys.Revoke(r.Context(), id); {
+		case errors.Is(err, ErrNotFound):
+			problem.Write(w, problem.NotFound("API key not found", problem.WithCode("api_key.not_found")))
+			return
+		case err != nil:
+			slog.ErrorContext(r.Context(), "apikey: revoke failed", slog.Any("error", err))
+			problem.Write(w, problem.ServiceUnavailable("key could not be revoked, retry later"))
+			return
+		}
+		slog.InfoContext(r.Context(), "apikey: key revoked by operator",
+			slog.String("operator", principal.Subject),
+			slog.String("key.id", id.String()),
+		)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"app/modules/auth"
+	"app/modules/middleware/problem"
+)
+
+// DefaultHeader carries the key, the same header the "api_key" rate limit strategy reads.
+const DefaultHeader = "X-API-Key"
+
+type (
+	Config struct {
+		// Enabled turns API key authentication and the /admin/api-keys endpoints on.
+		Enabled bool `env:"ENABLED" envDefault:"false"`
+		// Header carries the key.
+		Header string `env:"HEADER" envDefault:"X-API-Key"`
+	}
+)
+
+// Middleware authenticates requests sending an API key in header (DefaultHeader
+// when empty) and stores the key's principal in the request context.
+//
+// Requests without the header, or whose principal an earlier middleware already
+// resolved, pass through. An unknown, revoked or expired key is answered with 401,
+// and with 503 while the store cannot be reached.
+func Middleware(keys *Keys, header string) func(http.Handler) http.Handler {
+	if header == "" {
+		header = DefaultHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret := strings.TrimSpace(r.Header.Get(header))
+			if secret == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := auth.PrincipalFrom(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := keys.Verify(r.Context(), secret)
+			switch {
+			case errors.Is(err, ErrInvalidKey):
+				slog.InfoContext(r.Context(), "apikey: key rejected", slog.Any("error", err))
+				problem.Write(w, problem.Unauthorized("invalid API key", problem.WithCode("invalid_api_key")))
+				return
+			case err != nil:
+				slog.ErrorContext(r.Context(), "apikey: cannot verify key", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("authentication temporarily unavailable",
+					problem.WithCode("authentication_unavailable"),
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), key.Principal())))
+		})
+	}
+}
@@ -1,10 +1,32 @@
 package appconfig
 
 import (
+	"slices"
+	"strings"
+	"time"
+
+	"app/core/profile/adapters/persistence/cache"
+	"app/modules/apikey"
+	"app/modules/authz"
+	"app/modules/db/migrate"
 	"app/modules/db/postgres"
 	"app/modules/db/redis"
+	"app/modules/db/redis/locking"
+	"app/modules/delivery"
+	"app/modules/grpcserver"
+	"app/modules/health"
 	"app/modules/hmac"
+	"app/modules/httpclient"
+	"app/modules/httpsig"
+	"app/modules/middleware"
+	authmw "app/modules/middleware/auth"
 	"app/modules/middleware/ratelimit"
+	"app/modules/middleware/routepolicy"
+	"app/modules/pagination"
+	"app/modules/readonly"
+	"app/modules/resilience"
+	"app/modules/saga"
+	"app/modules/server"
 	"app/modules/telemetry"
 
 	"github.com/caarlos0/env/v11"
@@ -13,26 +35,104 @@ import (
 type Config struct {
 	// TODO: on 12-factor apps on env
 	Env string `env:"ENV" envDefault:"dev"`
+	// Namespace scopes every shared key (redis counters and locks, advisory lock
+	// names, job history, ...) so environments or services can share one backend.
+	// Empty uses Env. See Key and KeyPrefix.
+	Namespace string `env:"NAMESPACE"`
 
 	// --- core infra ----
 	HMAC     hmac.HMACConfig         `envPrefix:"HMAC_"`
 	Redis    redis.RedisConfig       `envPrefix:"REDIS_"`
 	Postgres postgres.PostgresConfig `envPrefix:"POSTGRES_"`
+	Locking  locking.Config          `envPrefix:"LOCK_"`
+	Jobs     locking.JobsConfig      `envPrefix:"JOBS_"`
+	Migrate  migrate.Config          `envPrefix:"MIGRATE_"`
+	Breaker  resilience.Config       `envPrefix:"BREAKER_"`
+	Health   health.Config           `envPrefix:"HEALTH_"`
+	// AuditRetention is how long profile history is kept, and so how far back
+	// `GET /v1/profiles/{id}?asOf=` reads. Zero keeps it forever.
+	AuditRetention time.Duration `env:"AUDIT_RETENTION" envDefault:"2160h"`
+	// SoftDeleteRetention is how long soft-deleted profiles are kept before they are
+	// deleted for good. Zero keeps them forever.
+	SoftDeleteRetention time.Duration `env:"SOFT_DELETE_RETENTION"`
+	// SoftDeletePurgeRate caps the profiles the purge deletes per second, so the
+	// replicas keep up with the deletes. Zero does not limit it.
+	SoftDeletePurgeRate int `env:"SOFT_DELETE_PURGE_RATE" envDefault:"500"`
+	// ProfileCache caches profiles read by id in redis, see persistence/cache.
+	ProfileCache cache.Config `envPrefix:"PROFILE_CACHE_"`
+	// Saga runs the workflows spanning services, see modules/saga.
+	Saga saga.Config `envPrefix:"SAGA_"`
+	// Delivery journals outbound deliveries for replay, see modules/delivery.
+	Delivery delivery.Config `envPrefix:"DELIVERY_"`
+
+	// --- transport ----
+	Server server.Config `envPrefix:"HTTP_"`
+	// GRPC serves the Profile API over gRPC too, see grpcserver.
+	GRPC grpcserver.Config `envPrefix:"GRPC_"`
+	// Pagination caps the page sizes of list endpoints.
+	Pagination pagination.Config `envPrefix:"PAGINATION_"`
+	// SpecDir, when set, serves the OpenAPI specs (modules/oapi/*.yaml) from this
+	// directory instead of the embedded copies, so they can be hotfixed and reloaded
+	// through POST /admin/caches.
+	SpecDir string `env:"SPEC_DIR"`
+	// Egress throttles outgoing calls per downstream host, see httpclient.WithThrottle.
+	Egress httpclient.ThrottleConfig `envPrefix:"EGRESS_"`
 
 	// --- middlewares ----
-	RateLimit ratelimit.RestHTTPConfig `envPrefix:"RATE_LIMIT_"`
+	Routing     middleware.RoutingConfig     `envPrefix:"ROUTING_"`
+	Cache       middleware.CacheConfig       `envPrefix:"CACHE_"`
+	RateLimit   ratelimit.RestHTTPConfig     `envPrefix:"RATE_LIMIT_"`
+	RoutePolicy routepolicy.Config           `envPrefix:"ROUTE_POLICY_"`
+	Authz       authz.Config                 `envPrefix:"AUTHZ_"`
+	JWT         authmw.Config                `envPrefix:"JWT_"`
+	APIKeys     apikey.Config                `envPrefix:"API_KEYS_"`
+	Signature   httpsig.Config               `envPrefix:"HTTP_SIGNATURE_"`
+	ReadOnly    readonly.Config              `envPrefix:"READ_ONLY_"`
+	Idempotency middleware.IdempotencyConfig `envPrefix:"IDEMPOTENCY_"`
+	AccessLog   middleware.AccessLogConfig   `envPrefix:"ACCESS_LOG_"`
+	Compression middleware.CompressionConfig `envPrefix:"COMPRESSION_"`
 
 	// --- otel ----
 	// since it has special naming conventions, we do not use prefix here
 	Otel telemetry.Config
 }
 
+// productionEnvs are the ENV values treated as production.
+var productionEnvs = []string{"prod", "production"}
+
+// IsProduction reports whether Env names a production deployment.
+func (c *Config) IsProduction() bool {
+	return slices.Contains(productionEnvs, strings.ToLower(c.Env))
+}
+
+// Key joins the namespace and parts with ":", e.g. Key("locks") is "dev:locks".
+func (c *Config) Key(parts ...string) string {
+	ns := c.Namespace
+	if ns == "" {
+		ns = c.Env
+	}
+	return strings.Join(append([]string{ns}, parts...), ":")
+}
+
+// KeyPrefix is Key followed by ":", for stores that append their keys directly.
+func (c *Config) KeyPrefix(parts ...string) string {
+	return c.Key(parts...) + ":"
+}
+
 func Load() (*Config, error) {
 	cfg, err := env.ParseAs[Config]()
 	if err != nil {
 		return nil, err
 	}
 
+	// one setting names the deployment in telemetry too
+	if cfg.Otel.Environment == "" {
+		cfg.Otel.Environment = cfg.Env
+	}
+	if cfg.Otel.Namespace == "" {
+		cfg.Otel.Namespace = cfg.Key()
+	}
+
 	if err := validate(&cfg); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,188 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apperr
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Code classifies an error independently of the transport it is reported on.
+type Code string
+
+const (
+	CodeUnknown      Code = "unknown"
+	CodeInvalid      Code = "invalid_argument"
+	CodeNotFound     Code = "not_found"
+	CodeConflict     Code = "conflict"
+	CodePrecondition Code = "failed_precondition"
+	CodeUnavailable  Code = "unavailable"
+	CodeTimeout      Code = "deadline_exceeded"
+	CodeCanceled     Code = "canceled"
+	CodeInternal     Code = "internal"
+)
+
+// Error is the typed application error.
+//
+// All fields are optional: a sentinel usually only sets Code and a message,
+// a wrapping error usually sets Op and Err and inherits the Code of its cause.
+type Error struct {
+	// Op is the operation that failed, e.g. "pg.CreateProfile".
+	Op string
+	// Code classifies the failure. Empty means "inherit from Err".
+	Code Code
+	// Retryable marks transient failures that may succeed when retried as is.
+	Retryable bool
+	// Err is the wrapped cause.
+	Err error
+
+	msg string
+}
+
+// New creates a sentinel error with the given code and message.
+// Sentinels are compared by identity, so errors.Is keeps working through wrapping.
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, msg: msg}
+}
+
+// Wrap records op on err, keeping the cause's code and retryability.
+// It returns nil when err is nil.
+func Wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Err: err}
+}
+
+// WithCode wraps err under op and classifies it with code.
+// It returns nil when err is nil.
+func WithCode(op string, code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Code: code, Err: err}
+}
+
+// Transient wraps err under op, classifies it with code and marks it retryable.
+// It returns nil when err is nil.
+func Transient(op string, code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Op: op, Code: code, Retryable: true, Err: err}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Op != "" {
+		b.WriteString(e.Op)
+	}
+	text := e.msg
+	if text == "" && e.Err != nil {
+		text = e.Err.Error()
+	}
+	if text == "" && e.Code != "" {
+		text = string(e.Code)
+	}
+	if text != "" {
+		if b.Len() > 0 {
+			b.WriteString(": ")
+		}
+		b.WriteString(text)
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// CodeOf returns the first code found in err's chain.
+//
+// Context errors are classified even when they are not wrapped in an *Error,
+// nil maps to the empty code and anything else unclassified is CodeUnknown.
+func CodeOf(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var code Code
+	walk(err, func(e *Error) bool {
+		if e.Code != "" {
+			code = e.Code
+			return true
+		}
+		return false
+	})
+	if code != "" {
+		return code
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return CodeTimeout
+	}
+	return CodeUnknown
+}
+
+// HasCode reports whether err is classified with code.
+func HasCode(err error, code Code) bool {
+	return CodeOf(err) == code
+}
+
+// IsRetryable reports whether any error in err's chain is marked retryable.
+func IsRetryable(err error) bool {
+	retryable := false
+	walk(err, func(e *Error) bool {
+		retryable = e.Retryable
+		return retryable
+	})
+	return retryable
+}
+
+// OpsOf returns the operations recorded in err's chain, outermost first.
+func OpsOf(err error) []string {
+	var ops []string
+	walk(err, func(e *Error) bool {
+		if e.Op != "" {
+			ops = append(ops, e.Op)
+		}
+		return false
+	})
+	return ops
+}
+
+// walk visits every *Error in err's tree depth-first until fn returns true.
+func walk(err error, fn func(*Error) bool) bool {
+	for err != nil {
+		if e, ok := err.(*Error); ok && fn(e) {
+			return true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				if walk(inner, fn) {
+					return true
+				}
+			}
+			return false
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
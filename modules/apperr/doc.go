@@ -0,0 +1,32 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apperr defines the typed application error shared across layers.
+//
+// An *Error carries a Code (what went wrong, transport agnostic), the Op that
+// failed (e.g. "pg.CreateProfile"), a Retryable flag and the wrapped cause.
+// Middlewares such as the problem mapper, retry policies or circuit breakers
+// inspect this metadata through CodeOf / IsRetryable instead of comparing
+// against every layer's sentinel errors.
+//
+//	// domain sentinel
+//	var ErrProfileNotFound = apperr.New(apperr.CodeNotFound, "profile not found")
+//
+//	// persistence adapter
+//	return apperr.Wrap("pg.GetProfile", domain.ErrProfileNotFound)
+//	return apperr.Transient("pg.GetProfile", apperr.CodeUnavailable, err)
+//
+//	// transport
+//	switch apperr.CodeOf(err) { case apperr.CodeNotFound: ... }
+package apperr
@@ -0,0 +1,96 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+type (
+	// Principal is the authenticated caller of a request.
+	Principal struct {
+		// Subject identifies the caller (user id, client id, key id).
+		Subject string
+		// Scopes granted to the caller, e.g. "profiles:read".
+		Scopes []string
+		// Claims holds the raw attributes the credential carried.
+		Claims map[string]any
+	}
+
+	principalKey struct{}
+)
+
+// HasScope reports whether the principal was granted scope.
+func (p *Principal) HasScope(scope string) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+// HasScopes reports whether the principal was granted every scope.
+func (p *Principal) HasScopes(scopes ...string) bool {
+	for _, s := range scopes {
+		if !p.HasScope(s) {
+			return false
+		}
+	}
+	return p != nil
+}
+
+// Granted returns the principal's scopes, falling back to the scope claims
+// when the authenticator did not resolve them.
+func (p *Principal) Granted() []string {
+	if p == nil {
+		return nil
+	}
+	if len(p.Scopes) > 0 {
+		return p.Scopes
+	}
+	return ScopesFromClaims(p.Claims)
+}
+
+// WithPrincipal stores p in ctx.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom returns the principal stored in ctx, if any.
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// ScopesFromClaims extracts scopes from the usual claim shapes:
+//   - "scope": space separated string (RFC 8693 / OAuth2)
+//   - "scp":   array of strings (Azure AD, Okta) or space separated string
+func ScopesFromClaims(claims map[string]any) []string {
+	var scopes []string
+	for _, name := range []string{"scope", "scp"} {
+		switch v := claims[name].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(v)...)
+		case []string:
+			scopes = append(scopes, v...)
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+		}
+	}
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
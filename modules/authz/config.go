@@ -0,0 +1,29 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+type (
+	Config struct {
+		// Enabled turns scope enforcement on. While disabled, the middleware and the
+		// enforcer of EnforcerFromConfig only log the decisions they would have made.
+		Enabled bool `env:"ENABLED" envDefault:"false"`
+
+		// Roles maps each role to the scopes it grants, as
+		// "editor=profiles:read profiles:write;auditor=profiles:read".
+		Roles map[string]string `env:"ROLES" envSeparator:";" envKeyValSeparator:"="`
+		// RoleClaim is the principal claim listing its roles.
+		RoleClaim string `env:"ROLE_CLAIM" envDefault:"roles"`
+	}
+)
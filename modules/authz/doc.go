@@ -0,0 +1,25 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package authz enforces authorization decisions for authenticated principals.
+//
+// SpecScopes derives per-operation scope requirements from an OpenAPI
+// document's security sections and rejects requests whose Principal lacks them
+// with RFC 7807 problems (401 without a principal, 403 with missing scopes).
+//
+// Checks the spec cannot express go through an Enforcer: Middleware stores one
+// (usually a ScopeEnforcer expanding roles into scopes) and handlers call
+// Require(ctx, scopes...), mapping its error with Problem. Problems carry a
+// ProblemType* type URI.
+package authz
@@ -0,0 +1,300 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"app/modules/auth"
+	"app/modules/middleware/problem"
+)
+
+var (
+	// ErrUnauthenticated is returned when an action needs a principal and there is none.
+	ErrUnauthenticated = errors.New("authz: authentication required")
+	// ErrForbidden matches every denial of an authenticated principal, see MissingScopesError.
+	ErrForbidden = errors.New("authz: forbidden")
+)
+
+// Problem types of authorization failures. They name the failure for clients and
+// are not meant to be dereferenced.
+const (
+	ProblemTypeUnauthenticated   = "urn:problem-type:unauthenticated"
+	ProblemTypeInsufficientScope = "urn:problem-type:insufficient-scope"
+)
+
+// DefaultRoleClaim holds the roles of a principal, as a space separated string or an array.
+const DefaultRoleClaim = "roles"
+
+type (
+	// Enforcer decides whether a principal may act with the given scopes.
+	//
+	// Authorize returns nil when p holds every scope, ErrUnauthenticated when p is
+	// nil, and an error matching ErrForbidden otherwise.
+	Enforcer interface {
+		Authorize(ctx context.Context, p *auth.Principal, scopes ...string) error
+	}
+
+	// MissingScopesError is the denial of a principal lacking scopes.
+	MissingScopesError struct {
+		Subject string
+		Missing []string
+	}
+
+	// ScopeEnforcer is an Enforcer granting a principal its own scopes plus those
+	// of its roles.
+	ScopeEnforcer struct {
+		roles     map[string][]string
+		roleClaim string
+		scopes    ScopeMapper
+		enforce   bool
+	}
+
+	// EnforcerOption configures a ScopeEnforcer.
+	EnforcerOption func(*ScopeEnforcer)
+
+	enforcerKey struct{}
+)
+
+var _ Enforcer = (*ScopeEnforcer)(nil)
+
+func (e *MissingScopesError) Error() string {
+	return "authz: " + e.Subject + " lacks scopes " + strings.Join(e.Missing, " ")
+}
+
+// Is makes every MissingScopesError match ErrForbidden.
+func (e *MissingScopesError) Is(target error) bool { return target == ErrForbidden }
+
+// WithRoles expands each role into the scopes it grants, e.g.
+// {"editor": ["profiles:read", "profiles:write"]}.
+func WithRoles(roles map[string][]string) EnforcerOption {
+	return func(e *ScopeEnforcer) {
+		if roles != nil {
+			e.roles = roles
+		}
+	}
+}
+
+// WithRoleClaim reads the roles from claim instead of DefaultRoleClaim.
+func WithRoleClaim(claim string) EnforcerOption {
+	return func(e *ScopeEnforcer) {
+		if claim != "" {
+			e.roleClaim = claim
+		}
+	}
+}
+
+// WithPrincipalScopes overrides how the principal's own scopes are resolved, see WithScopeMapper.
+func WithPrincipalScopes(fn ScopeMapper) EnforcerOption {
+	return func(e *ScopeEnforcer) {
+		if fn != nil {
+			e.scopes = fn
+		}
+	}
+}
+
+// WithEnforcement set to false only logs the scopes a principal lacks and grants
+// them, as SpecScopes does while Config.Enabled is off. A missing principal is
+// still ErrUnauthenticated.
+func WithEnforcement(enforce bool) EnforcerOption {
+	return func(e *ScopeEnforcer) {
+		e.enforce = enforce
+	}
+}
+
+// NewScopeEnforcer constructs a ScopeEnforcer. Without roles it only grants the
+// principal's own scopes.
+func NewScopeEnforcer(opts ...EnforcerOption) *ScopeEnforcer {
+	e := &ScopeEnforcer{
+		roleClaim: DefaultRoleClaim,
+		scopes:    (*auth.Principal).Granted,
+		enforce:   true,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+	return e
+}
+
+// EnforcerFromConfig builds a ScopeEnforcer from the roles and role claim of cfg,
+// enforcing only while cfg.Enabled is set.
+func EnforcerFromConfig(cfg Config, opts ...EnforcerOption) *ScopeEnforcer {
+	roles := make(map[string][]string, len(cfg.Roles))
+	for role, scopes := range cfg.Roles {
+		if role = strings.TrimSpace(role); role != "" {
+			roles[role] = strings.Fields(scopes)
+		}
+	}
+	base := []EnforcerOption{WithRoles(roles), WithRoleClaim(cfg.RoleClaim), WithEnforcement(cfg.Enabled)}
+	return NewScopeEnforcer(append(base, opts...)...)
+}
+
+// Granted returns the scopes p holds directly or through its roles.
+func (e *ScopeEnforcer) Granted(p *auth.Principal) []string {
+	if p == nil {
+		return nil
+	}
+	granted := slices.Clone(e.scopes(p))
+	if len(e.roles) > 0 {
+		for _, role := range claimValues(p.Claims[e.roleClaim]) {
+			granted = append(granted, e.roles[role]...)
+		}
+	}
+	slices.Sort(granted)
+	return slices.Compact(granted)
+}
+
+// Authorize implements Enforcer.
+func (e *ScopeEnforcer) Authorize(ctx context.Context, p *auth.Principal, scopes ...string) error {
+	if p == nil {
+		return ErrUnauthenticated
+	}
+	granted := e.Granted(p)
+	var missing []string
+	for _, s := range scopes {
+		if !slices.Contains(granted, s) {
+			missing = append(missing, s)
+		}
+	}
+	switch {
+	case len(missing) == 0:
+		return nil
+	case !e.enforce:
+		slog.InfoContext(ctx, "authz: missing scopes not enforced",
+			slog.String("subject", p.Subject),
+			slog.Any("missing", missing),
+		)
+		return nil
+	}
+	return &MissingScopesError{Subject: p.Subject, Missing: missing}
+}
+
+func claimValues(v any) []string {
+	switch v := v.(type) {
+	case string:
+		return strings.Fields(v)
+	case []string:
+		return v
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// WithEnforcer stores e in ctx for Require.
+func WithEnforcer(ctx context.Context, e Enforcer) context.Context {
+	return context.WithValue(ctx, enforcerKey{}, e)
+}
+
+// enforcerFrom returns the enforcer stored in ctx, if any.
+func enforcerFrom(ctx context.Context) (Enforcer, bool) {
+	e, ok := ctx.Value(enforcerKey{}).(Enforcer)
+	return e, ok && e != nil
+}
+
+// defaultEnforcer serves Require when no Middleware stored one.
+var defaultEnforcer = NewScopeEnforcer()
+
+// Middleware makes e the enforcer of Require and of SpecScopes further down the chain.
+func Middleware(e Enforcer) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithEnforcer(r.Context(), e)))
+		})
+	}
+}
+
+// Require checks that the principal of ctx holds every scope, with the enforcer
+// Middleware stored (a ScopeEnforcer without roles otherwise). Strict handlers call
+// it for checks the spec cannot express and map the error with Problem:
+//
+//	if err := authz.Require(ctx, "profiles:admin"); err != nil {
+//		return nil, err
+//	}
+func Require(ctx context.Context, scopes ...string) error {
+	e, ok := enforcerFrom(ctx)
+	if !ok {
+		e = defaultEnforcer
+	}
+	p, _ := auth.PrincipalFrom(ctx)
+	return e.Authorize(ctx, p, scopes...)
+}
+
+// RequireScopes is a middleware answering requests that fail Require with its Problem.
+func RequireScopes(scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := Require(r.Context(), scopes...); err != nil {
+				WriteProblem(w, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Problem maps an authorization error to its problem: 401 for ErrUnauthenticated,
+// 403 for ErrForbidden, with a ProblemType* type and the missing scopes when known.
+// It reports false for any other error.
+func Problem(err error) (*problem.Problem, bool) {
+	switch {
+	case errors.Is(err, ErrUnauthenticated):
+		return problem.Unauthorized("authentication required",
+			problem.WithType(ProblemTypeUnauthenticated),
+			problem.WithCode("unauthenticated"),
+		), true
+	case errors.Is(err, ErrForbidden):
+		opts := []problem.Option{
+			problem.WithType(ProblemTypeInsufficientScope),
+			problem.WithCode("insufficient_scope"),
+		}
+		var mse *MissingScopesError
+		if errors.As(err, &mse) {
+			opts = append(opts, problem.WithExtension("missingScopes", mse.Missing))
+		}
+		return problem.Forbidden("missing required scope", opts...), true
+	}
+	return nil, false
+}
+
+// WriteProblem writes the Problem of err with its WWW-Authenticate challenge, or a
+// 500 problem when err is not an authorization error.
+func WriteProblem(w http.ResponseWriter, err error) {
+	p, ok := Problem(err)
+	if !ok {
+		problem.Write(w, problem.Internal("server error"))
+		return
+	}
+	challenge := "Bearer"
+	var mse *MissingScopesError
+	if errors.As(err, &mse) {
+		challenge = `Bearer error="insufficient_scope", scope="` + strings.Join(mse.Missing, " ") + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	problem.Write(w, p)
+}
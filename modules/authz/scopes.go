@@ -0,0 +1,274 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authz
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"app/modules/auth"
+	"app/modules/middleware/problem"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+type (
+	// Requirement is one alternative of an operation's security section:
+	// the caller must hold every scope in it.
+	Requirement []string
+
+	// ScopeMapper resolves the scopes a principal holds.
+	ScopeMapper func(p *auth.Principal) []string
+
+	// Option configures SpecScopes.
+	Option func(*scopeEnforcer)
+
+	scopeEnforcer struct {
+		routes  atomic.Pointer[scopeRoutes]
+		enforce bool
+		scopes  ScopeMapper
+
+		// optional, see WithSpecReload
+		reload     func() (*openapi3.T, error)
+		generation func() uint64
+	}
+
+	// scopeRoutes is the router compiled from one version of the spec.
+	scopeRoutes struct {
+		router     routers.Router
+		doc        *openapi3.T
+		generation uint64
+	}
+)
+
+// WithConfig applies the authz configuration.
+func WithConfig(cfg Config) Option {
+	return func(e *scopeEnforcer) {
+		e.enforce = cfg.Enabled
+	}
+}
+
+// WithScopeMapper overrides how a principal's scopes are resolved.
+// By default Principal.Scopes is used, falling back to the "scope"/"scp" claims.
+func WithScopeMapper(fn ScopeMapper) Option {
+	return func(e *scopeEnforcer) {
+		if fn != nil {
+			e.scopes = fn
+		}
+	}
+}
+
+// WithSpecReload recompiles the routes from load whenever generation changes
+// (usually middleware.LoadSpec and middleware.SpecGeneration), so an operator can
+// pick up a fixed spec without a restart. A spec that fails to load or compile
+// keeps the previous routes in force.
+func WithSpecReload(load func() (*openapi3.T, error), generation func() uint64) Option {
+	return func(e *scopeEnforcer) {
+		if load != nil && generation != nil {
+			e.reload = load
+			e.generation = generation
+		}
+	}
+}
+
+// SpecScopes returns a middleware enforcing the security requirements declared in doc.
+//
+// For every request the matching operation is looked up; its security section
+// (or the document's global one) is a list of alternatives, of which at least
+// one must be satisfied:
+//
+//	security:                 # profiles:read OR (profiles:admin AND audit:read)
+//	  - oauth2: [profiles:read]
+//	  - oauth2: [profiles:admin]
+//	    apiKey: [audit:read]
+//
+// An empty list (`security: []`) or an empty alternative (`- {}`) allows anonymous
+// access. Requests that match no operation pass through for the router to reject.
+func SpecScopes(doc *openapi3.T, opts ...Option) (func(http.Handler) http.Handler, error) {
+	e := &scopeEnforcer{
+		enforce: true,
+		scopes:  (*auth.Principal).Granted,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(e)
+		}
+	}
+
+	var generation uint64
+	if e.generation != nil {
+		generation = e.generation()
+	}
+	routes, err := compileRoutes(doc, generation)
+	if err != nil {
+		return nil, err
+	}
+	e.routes.Store(routes)
+	if !e.enforce {
+		slog.Warn("authz: scope enforcement disabled, requests are only audited")
+	}
+
+	return e.middleware, nil
+}
+
+func compileRoutes(doc *openapi3.T, generation uint64) (*scopeRoutes, error) {
+	// route on paths only, servers in the spec don't matter for authorization
+	spec := *doc
+	spec.Servers = nil
+	router, err := gorillamux.NewRouter(&spec)
+	if err != nil {
+		return nil, err
+	}
+	return &scopeRoutes{router: router, doc: &spec, generation: generation}, nil
+}
+
+// current returns the routes for the current spec generation, recompiling them once after a change.
+func (e *scopeEnforcer) current(r *http.Request) *scopeRoutes {
+	routes := e.routes.Load()
+	if e.generation == nil {
+		return routes
+	}
+	gen := e.generation()
+	if gen == routes.generation {
+		return routes
+	}
+
+	doc, err := e.reload()
+	var fresh *scopeRoutes
+	if err == nil {
+		fresh, err = compileRoutes(doc, gen)
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "authz: spec reload failed, keeping previous routes", slog.Any("error", err))
+		// do not retry on every request until the next reset
+		fresh = &scopeRoutes{router: routes.router, doc: routes.doc, generation: gen}
+	}
+	if !e.routes.CompareAndSwap(routes, fresh) {
+		return e.routes.Load()
+	}
+	return fresh
+}
+
+func (e *scopeEnforcer) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		routes := e.current(r)
+		route, _, err := routes.router.FindRoute(r)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqs := requirements(routes.doc, route.Operation)
+		if reqs == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		principal, ok := auth.PrincipalFrom(r.Context())
+		if !ok {
+			e.deny(w, r, next, route.Operation.OperationID, reqs, "", problem.Unauthorized(
+				"authentication required",
+				problem.WithType(ProblemTypeUnauthenticated),
+				problem.WithCode("unauthenticated"),
+			))
+			return
+		}
+
+		// roles only apply through the Enforcer of Middleware
+		enforcer, hasEnforcer := enforcerFrom(r.Context())
+		held := e.scopes(principal)
+		for _, req := range reqs {
+			if hasEnforcer && enforcer.Authorize(r.Context(), principal, req...) == nil ||
+				!hasEnforcer && holdsAll(held, req) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		e.deny(w, r, next, route.Operation.OperationID, reqs, principal.Subject, problem.Forbidden(
+			"missing required scope",
+			problem.WithType(ProblemTypeInsufficientScope),
+			problem.WithCode("insufficient_scope"),
+			problem.WithExtension("requiredScopes", reqs),
+		))
+	})
+}
+
+// requirements returns the alternatives for op, or nil when anonymous access is allowed.
+func requirements(doc *openapi3.T, op *openapi3.Operation) []Requirement {
+	security := doc.Security
+	if op.Security != nil {
+		security = *op.Security
+	}
+	if len(security) == 0 {
+		return nil
+	}
+
+	reqs := make([]Requirement, 0, len(security))
+	for _, alt := range security {
+		if len(alt) == 0 {
+			return nil
+		}
+		var req Requirement
+		for _, scopes := range alt {
+			req = append(req, scopes...)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// deny logs the decision and answers with p, or only logs it and serves next
+// while enforcement is off.
+func (e *scopeEnforcer) deny(w http.ResponseWriter, r *http.Request, next http.Handler, op string, reqs []Requirement, subject string, p *problem.Problem) {
+	slog.InfoContext(r.Context(), "authz: request denied",
+		slog.String("operation", op),
+		slog.String("subject", subject),
+		slog.Int("status", p.Status),
+		slog.Any("required", reqs),
+		slog.Bool("enforced", e.enforce),
+	)
+	if !e.enforce {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	challenge := "Bearer"
+	if p.Status == http.StatusForbidden {
+		challenge = `Bearer error="insufficient_scope", scope="` + strings.Join(reqs[0], " ") + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+	problem.Write(w, p)
+}
+
+func holdsAll(held []string, req Requirement) bool {
+	for _, want := range req {
+		found := false
+		for _, s := range held {
+			if s == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cache is a typed cache on top of db.KV, so services do not hand-roll
+// serialization around AtomicGet and AtomicSet.
+//
+//	kv := redis.NewRedisKV(client, redis.WithKeyPrefix(appConfig.KeyPrefix("cache", "orders")),
+//		redis.WithDefaultTTL(10*time.Minute))
+//	orders := cache.NewTyped[Order](kv, cache.WithCodec(cache.Msgpack),
+//		cache.WithTTL(5*time.Minute), cache.WithNegativeTTL(30*time.Second))
+//
+//	order, err := orders.GetOrLoad(ctx, id, func(ctx context.Context) (Order, error) {
+//		o, err := repo.Get(ctx, id)
+//		if errors.Is(err, ErrOrderNotFound) {
+//			return Order{}, cache.ErrNotFound
+//		}
+//		return o, err
+//	})
+//
+// Values are encoded with JSON or MessagePack. Expiries are jittered so a batch
+// written at once does not expire at once, loads of the same key are
+// deduplicated, and with WithNegativeTTL keys known to be missing are cached too.
+package cache
@@ -0,0 +1,266 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/db"
+
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// ErrMiss is returned by Get when nothing fresh is cached under the key.
+	ErrMiss = errors.New("cache: miss")
+
+	// ErrNotFound reports a value known not to exist. Loaders return it (or wrap it)
+	// for GetOrLoad to cache the absence, and Get returns it for cached absences.
+	ErrNotFound = errors.New("cache: not found")
+)
+
+const (
+	DefaultTTL    = 5 * time.Minute
+	DefaultJitter = 0.1
+)
+
+type (
+	// Typed caches values of type T in a db.KV, encoded with a Codec.
+	//
+	// Every entry carries its own expiry, jittered so entries written together do
+	// not expire together. The KV's TTL (e.g. redis.WithDefaultTTL) should be at
+	// least the Typed TTL: it only reclaims the memory, a stale entry is a miss
+	// either way.
+	Typed[T any] struct {
+		kv   db.KV
+		opts options
+
+		loads singleflight.Group
+	}
+
+	// Option configures a Typed cache.
+	Option func(*options)
+
+	options struct {
+		codec       Codec
+		ttl         time.Duration
+		jitter      float64
+		negativeTTL time.Duration
+		clock       clock.Clock
+	}
+
+	// envelope is what is stored under a key. Missing marks a cached absence.
+	envelope[T any] struct {
+		Expires int64 `json:"e"` // unix ms
+		Missing bool  `json:"m,omitempty"`
+		Value   *T    `json:"v,omitempty"`
+	}
+)
+
+// WithCodec sets how values are encoded. Defaults to JSON.
+func WithCodec(c Codec) Option {
+	return func(o *options) {
+		if c != nil {
+			o.codec = c
+		}
+	}
+}
+
+// WithTTL sets how long values stay fresh. Defaults to DefaultTTL.
+func WithTTL(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.ttl = d
+		}
+	}
+}
+
+// WithJitter shortens every TTL by a random share of up to fraction (0 to 1) of it.
+// Defaults to DefaultJitter, 0 disables it.
+func WithJitter(fraction float64) Option {
+	return func(o *options) {
+		if fraction >= 0 && fraction < 1 {
+			o.jitter = fraction
+		}
+	}
+}
+
+// WithNegativeTTL makes GetOrLoad cache ErrNotFound from its loader for d, so
+// lookups of missing keys do not all reach the source. Disabled by default.
+func WithNegativeTTL(d time.Duration) Option {
+	return func(o *options) {
+		if d >= 0 {
+			o.negativeTTL = d
+		}
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
+
+// NewTyped constructs a Typed cache on top of an existing db.KV. Scope its keys
+// with the KV, e.g. redis.WithKeyPrefix.
+func NewTyped[T any](kv db.KV, opts ...Option) *Typed[T] {
+	o := options{
+		codec:  JSON,
+		ttl:    DefaultTTL,
+		jitter: DefaultJitter,
+		clock:  clock.RealClock{},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return &Typed[T]{kv: kv, opts: o}
+}
+
+// Get returns the value cached under key, ErrMiss when there is none or it
+// expired, or ErrNotFound for a cached absence.
+func (c *Typed[T]) Get(ctx context.Context, key string) (T, error) {
+	var zero T
+	raw, err := c.kv.AtomicGet(ctx, key)
+	if err != nil {
+		return zero, fmt.Errorf("cache: get %q: %w", key, err)
+	}
+	if raw == nil {
+		return zero, ErrMiss
+	}
+	bs, ok := raw.([]byte)
+	if !ok {
+		return zero, fmt.Errorf("cache: get %q: expected []byte, got %T", key, raw)
+	}
+
+	var e envelope[T]
+	if err := c.opts.codec.Unmarshal(bs, &e); err != nil {
+		return zero, fmt.Errorf("cache: decode %q: %w", key, err)
+	}
+	switch {
+	case c.opts.clock.Now().UnixMilli() >= e.Expires:
+		return zero, ErrMiss
+	case e.Missing:
+		return zero, ErrNotFound
+	case e.Value == nil:
+		return zero, ErrMiss
+	}
+	return *e.Value, nil
+}
+
+// Set caches value under key for the TTL.
+func (c *Typed[T]) Set(ctx context.Context, key string, value T) error {
+	return c.put(ctx, key, envelope[T]{Value: &value}, c.opts.ttl)
+}
+
+// SetNotFound caches the absence of key for the negative TTL, or for the TTL when
+// negative caching is disabled.
+func (c *Typed[T]) SetNotFound(ctx context.Context, key string) error {
+	ttl := c.opts.negativeTTL
+	if ttl <= 0 {
+		ttl = c.opts.ttl
+	}
+	return c.put(ctx, key, envelope[T]{Missing: true}, ttl)
+}
+
+// Invalidate expires key, so that the next read misses. db.KV cannot delete, the
+// key is overwritten with an expired entry instead.
+//
+// A load already running for key may still cache what it read before the write
+// that prompted the invalidation; the TTL bounds how long that value is served.
+func (c *Typed[T]) Invalidate(ctx context.Context, key string) error {
+	c.loads.Forget(key)
+	e := envelope[T]{Expires: c.opts.clock.Now().UnixMilli()}
+	return c.encodeAndSet(ctx, key, e)
+}
+
+// GetOrLoad returns the value cached under key, or calls load and caches what it
+// returns. Concurrent misses on the same key share a single load, which runs
+// detached from the caller's cancellation so that one caller giving up does not
+// fail the others. The callers then share the loaded value, including whatever
+// it points to.
+//
+// The cache is best-effort: when it cannot be read or written the failure is
+// logged and the value comes from load. ErrNotFound from load is cached when
+// WithNegativeTTL is set; every other load error is returned without caching.
+func (c *Typed[T]) GetOrLoad(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	v, err := c.Get(ctx, key)
+	switch {
+	case err == nil, errors.Is(err, ErrNotFound):
+		return v, err
+	case !errors.Is(err, ErrMiss):
+		slog.WarnContext(ctx, "cache: read failed, loading from the source",
+			slog.String("key", key),
+			slog.Any("error", err),
+		)
+	}
+
+	ch := c.loads.DoChan(key, func() (any, error) {
+		ctx := context.WithoutCancel(ctx)
+		v, err := load(ctx)
+		var werr error
+		switch {
+		case err == nil:
+			werr = c.Set(ctx, key, v)
+		case errors.Is(err, ErrNotFound) && c.opts.negativeTTL > 0:
+			werr = c.SetNotFound(ctx, key)
+		}
+		if werr != nil {
+			slog.WarnContext(ctx, "cache: write failed",
+				slog.String("key", key),
+				slog.Any("error", werr),
+			)
+		}
+		return v, err
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	case res := <-ch:
+		v, _ := res.Val.(T)
+		return v, res.Err
+	}
+}
+
+func (c *Typed[T]) put(ctx context.Context, key string, e envelope[T], ttl time.Duration) error {
+	if c.opts.jitter > 0 {
+		ttl -= time.Duration(rand.Int64N(int64(float64(ttl)*c.opts.jitter) + 1))
+	}
+	e.Expires = c.opts.clock.Now().Add(ttl).UnixMilli()
+	return c.encodeAndSet(ctx, key, e)
+}
+
+func (c *Typed[T]) encodeAndSet(ctx context.Context, key string, e envelope[T]) error {
+	bs, err := c.opts.codec.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("cache: encode %q: %w", key, err)
+	}
+	if _, err := c.kv.AtomicSet(ctx, key, bs); err != nil {
+		return fmt.Errorf("cache: set %q: %w", key, err)
+	}
+	return nil
+}
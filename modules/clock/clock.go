@@ -9,6 +9,15 @@ type Clock interface {
 	Now() time.Time
 }
 
+// Timers is a Clock that can also schedule work, so code waiting on durations
+// can run under a Fake clock in tests.
+type Timers interface {
+	Clock
+	// AfterFunc calls f in its own goroutine once d has elapsed. stop cancels the
+	// call and reports whether it did so before f ran.
+	AfterFunc(d time.Duration, f func()) (stop func() bool)
+}
+
 var RealClockProvider = sync.OnceValue(func() Clock {
 	return &RealClock{}
 })
@@ -18,3 +27,7 @@ type RealClock struct{}
 func (RealClock) Now() time.Time {
 	return time.Now()
 }
+
+func (RealClock) AfterFunc(d time.Duration, f func()) func() bool {
+	return time.AfterFunc(d, f).Stop
+}
@@ -0,0 +1,126 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package db
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+)
+
+type (
+	primaryKey struct{}
+
+	// RecentWrites remembers which keys (usually entity ids) were written in the
+	// last window, so reads of those keys can be routed to the primary until the
+	// replicas have caught up.
+	//
+	// It is process-local: a read served by another instance does not see the mark.
+	RecentWrites struct {
+		window time.Duration
+		clock  clock.Clock
+
+		mu      sync.Mutex
+		written map[string]time.Time
+		// next time expired marks are swept, so the map stays bounded by the write rate
+		sweepAt time.Time
+	}
+
+	// RecentWritesOption configures RecentWrites.
+	RecentWritesOption func(*RecentWrites)
+)
+
+// StickToPrimary marks ctx so reads issued with it go to the primary instead of a
+// replica, e.g. to read back a row the same request just wrote.
+func StickToPrimary(ctx context.Context) context.Context {
+	if StuckToPrimary(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// StuckToPrimary reports whether ctx was marked by StickToPrimary.
+func StuckToPrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// WithRecentWritesClock overrides the time source (useful in tests).
+func WithRecentWritesClock(c clock.Clock) RecentWritesOption {
+	return func(w *RecentWrites) {
+		if c != nil {
+			w.clock = c
+		}
+	}
+}
+
+// NewRecentWrites constructs a RecentWrites keeping marks for window.
+// A zero or negative window disables it: Stick never marks a context.
+func NewRecentWrites(window time.Duration, opts ...RecentWritesOption) *RecentWrites {
+	w := &RecentWrites{
+		window:  window,
+		clock:   clock.RealClock{},
+		written: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(w)
+		}
+	}
+	return w
+}
+
+// Mark records that keys were just written. Call it after the write committed.
+func (w *RecentWrites) Mark(keys ...string) {
+	if w == nil || w.window <= 0 || len(keys) == 0 {
+		return
+	}
+	now := w.clock.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	until := now.Add(w.window)
+	for _, k := range keys {
+		w.written[k] = until
+	}
+	if now.After(w.sweepAt) {
+		for k, exp := range w.written {
+			if !now.Before(exp) {
+				delete(w.written, k)
+			}
+		}
+		w.sweepAt = now.Add(w.window)
+	}
+}
+
+// Stick returns ctx stuck to the primary when key was marked within the window,
+// ctx unchanged otherwise.
+func (w *RecentWrites) Stick(ctx context.Context, key string) context.Context {
+	if w == nil || w.window <= 0 {
+		return ctx
+	}
+
+	w.mu.Lock()
+	exp, ok := w.written[key]
+	w.mu.Unlock()
+
+	if ok && w.clock.Now().Before(exp) {
+		return StickToPrimary(ctx)
+	}
+	return ctx
+}
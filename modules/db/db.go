@@ -16,11 +16,16 @@ package db
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/stephenafamo/bob"
 )
 
+// ErrCASMismatch is returned by CASKV.AtomicCompareAndSet when the key holds
+// another value than the expected one, i.e. it was written concurrently.
+var ErrCASMismatch = errors.New("db: compare-and-set mismatch")
+
 type (
 	TxFn func(ctx context.Context, q Querier) error
 
@@ -38,6 +43,7 @@ type (
 		ConnectionManager
 		MigrationManager
 		TxManager
+		ReadOnlyTxManager
 
 		// Shutdown attempts to gracefully close all underlying connections.
 		Shutdown(context.Context) error
@@ -65,8 +71,16 @@ type (
 		// from the underlying database connection pool
 		//
 		// Should fallback to a writer connection if not
-		// available
-		Reader() Querier
+		// available, and return the writer when ctx is stuck
+		// to the primary (see StickToPrimary)
+		Reader(ctx context.Context) Querier
+	}
+
+	// ReaderTxManager is what read stores need: single queries on a replica,
+	// multi-query flows in a read-only transaction
+	ReaderTxManager interface {
+		ReaderConnectionManager
+		ReadOnlyTxManager
 	}
 
 	MigrationManager interface {
@@ -80,10 +94,52 @@ type (
 		WithTimeoutTx(ctx context.Context, timeout time.Duration, fn TxFn) error
 	}
 
+	ReadOnlyTxManager interface {
+		// WithReadOnlyTx runs fn in a READ ONLY transaction on a read replica,
+		// falling back to the writer when no replica is available or ctx is
+		// stuck to the primary.
+		//
+		// All queries issued through fn see the same snapshot (e.g. list + count)
+		// without taking locks that would block writers.
+		WithReadOnlyTx(ctx context.Context, fn TxFn) error
+	}
+
 	// TODO: abstract Transformer logic with generics (KVTransformer)
 	//   func Getx[Tr Transformer[K, V], K, V any]
 	KV interface {
 		AtomicGet(context.Context, string) (any, error)
 		AtomicSet(context.Context, string, any) (any, error)
 	}
+
+	// CASKV is a KV that writes a key only while it holds an expected value.
+	CASKV interface {
+		KV
+		// AtomicCompareAndSet sets key to value if it holds expected, or if it does
+		// not exist when expected is nil, and returns ErrCASMismatch otherwise.
+		AtomicCompareAndSet(ctx context.Context, key string, expected, value any) error
+	}
+
+	// VersionedKV is a KV writing versioned values only over older ones. The check
+	// runs where the value is stored, so that racing writers never replace a newer
+	// value with an older one.
+	VersionedKV interface {
+		KV
+		// AtomicSetVersioned sets key to value, a JSON object holding version in its
+		// field versionField, unless key holds a JSON object with a higher version
+		// there. It reports whether it wrote.
+		AtomicSetVersioned(ctx context.Context, key, versionField string, version int64, value any) (bool, error)
+	}
+
+	// BatchKV is a KV that reads, writes and deletes many keys in one round trip,
+	// for cache warm-ups and invalidation fan-out. Batches are not atomic: keys
+	// may live on different cluster slots, and each one succeeds or fails alone.
+	BatchKV interface {
+		KV
+		// MGet returns the values of keys in order, nil for missing keys.
+		MGet(ctx context.Context, keys []string) ([]any, error)
+		// MSet sets every key of entries to its value.
+		MSet(ctx context.Context, entries map[string]any) error
+		// DeleteMany deletes keys and returns how many existed.
+		DeleteMany(ctx context.Context, keys ...string) (int64, error)
+	}
 )
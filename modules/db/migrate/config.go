@@ -0,0 +1,28 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import "time"
+
+// Config controls the migration run at server startup.
+type Config struct {
+	// OnStartup applies pending migrations before the server prepares its statements.
+	// Disable it when migrations are rolled out separately through cmd/migrate.
+	OnStartup bool `env:"ON_STARTUP" envDefault:"true"`
+	// Force applies migrations despite lint findings in production.
+	Force bool `env:"FORCE" envDefault:"false"`
+	// Timeout bounds the whole run, including waiting for another instance's run.
+	Timeout time.Duration `env:"TIMEOUT" envDefault:"5m"`
+}
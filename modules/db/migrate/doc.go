@@ -0,0 +1,23 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrate applies versioned SQL migrations to Postgres.
+//
+// Files follow the Flyway naming used under core/*/migrations: V<version>_<description>.sql,
+// applied in version order and recorded in a schema_migrations table.
+//
+// Before anything runs, pending migrations go through Lint, which flags statements
+// that lock or rewrite tables that may already be large in production. A Migrator
+// configured for production refuses findings unless forced.
+package migrate
@@ -0,0 +1,256 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Rule identifies an unsafe migration pattern.
+type Rule string
+
+const (
+	// RuleIndexNotConcurrent flags CREATE INDEX without CONCURRENTLY on an existing
+	// table: the build holds a SHARE lock, blocking every write until it finishes.
+	RuleIndexNotConcurrent Rule = "index-not-concurrent"
+	// RuleColumnTypeRewrite flags ALTER COLUMN ... TYPE, which usually rewrites the
+	// whole table and its indexes under an ACCESS EXCLUSIVE lock.
+	RuleColumnTypeRewrite Rule = "column-type-rewrite"
+	// RuleNotNullWithoutDefault flags NOT NULL columns added without a DEFAULT (fails on
+	// any existing row) and SET NOT NULL (scans the table under an ACCESS EXCLUSIVE lock).
+	RuleNotNullWithoutDefault Rule = "not-null-without-default"
+)
+
+// Finding is one unsafe statement.
+type Finding struct {
+	File      string
+	Line      int
+	Rule      Rule
+	Statement string
+	Message   string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", f.File, f.Line, f.Rule, f.Message)
+}
+
+var (
+	concurrentlyRe = regexp.MustCompile(`(?is)\b(?:INDEX|REINDEX)\s+(?:\w+\s+)?CONCURRENTLY\b`)
+
+	createRelRe   = regexp.MustCompile(`^CREATE\s+(?:UNLOGGED\s+)?(?:TABLE|MATERIALIZED\s+VIEW)\s+(?:IF\s+NOT\s+EXISTS\s+)?([\w."]+)`)
+	createIndexRe = regexp.MustCompile(`^CREATE\s+(?:UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?.*?\bON\s+(?:ONLY\s+)?([\w."]+)`)
+	alterTableRe  = regexp.MustCompile(`^ALTER\s+TABLE\s+(?:IF\s+EXISTS\s+)?(?:ONLY\s+)?([\w."]+)\s+(.*)$`)
+
+	alterTypeRe    = regexp.MustCompile(`^ALTER\s+(?:COLUMN\s+)?([\w"]+)\s+(?:SET\s+DATA\s+)?TYPE\b`)
+	setNotNullRe   = regexp.MustCompile(`^ALTER\s+(?:COLUMN\s+)?([\w"]+)\s+SET\s+NOT\s+NULL\b`)
+	addColumnRe    = regexp.MustCompile(`^ADD\s+(?:COLUMN\s+)?(?:IF\s+NOT\s+EXISTS\s+)?([\w"]+)\s`)
+	addConstraint  = regexp.MustCompile(`^ADD\s+(?:CONSTRAINT|PRIMARY|UNIQUE|CHECK|FOREIGN|EXCLUDE)\b`)
+	notNullRe      = regexp.MustCompile(`\bNOT\s+NULL\b`)
+	defaultRe      = regexp.MustCompile(`\bDEFAULT\b`)
+	whitespaceRuns = regexp.MustCompile(`\s+`)
+)
+
+// Lint reports unsafe statements in migrations, which are expected in apply order.
+//
+// Relations created by an earlier statement of the same batch are new and empty,
+// so locking them is harmless and not reported. The checks are lexical: they do not
+// know table sizes, and a finding only means the statement deserves a second look.
+func Lint(migrations []Migration) []Finding {
+	var findings []Finding
+	created := make(map[string]bool)
+
+	for _, m := range migrations {
+		for _, st := range statements(m.SQL) {
+			upper := strings.ToUpper(st.text)
+			report := func(rule Rule, format string, args ...any) {
+				findings = append(findings, Finding{
+					File:      m.File,
+					Line:      st.line,
+					Rule:      rule,
+					Statement: st.text,
+					Message:   fmt.Sprintf(format, args...),
+				})
+			}
+
+			if sm := createRelRe.FindStringSubmatch(upper); sm != nil {
+				created[relation(sm[1])] = true
+				continue
+			}
+
+			if sm := createIndexRe.FindStringSubmatch(upper); sm != nil {
+				table := relation(sm[2])
+				if sm[1] == "" && !created[table] {
+					report(RuleIndexNotConcurrent, "index on %s is built without CONCURRENTLY and blocks writes", table)
+				}
+				continue
+			}
+
+			sm := alterTableRe.FindStringSubmatch(upper)
+			if sm == nil {
+				continue
+			}
+			table := relation(sm[1])
+			if created[table] {
+				continue
+			}
+			for _, clause := range splitTopLevel(sm[2]) {
+				switch {
+				case alterTypeRe.MatchString(clause):
+					col := identifier(alterTypeRe.FindStringSubmatch(clause)[1])
+					report(RuleColumnTypeRewrite, "changing the type of %s.%s may rewrite the table", table, col)
+				case setNotNullRe.MatchString(clause):
+					col := identifier(setNotNullRe.FindStringSubmatch(clause)[1])
+					report(RuleNotNullWithoutDefault, "SET NOT NULL on %s.%s scans the table under an exclusive lock", table, col)
+				case addColumnRe.MatchString(clause) && !addConstraint.MatchString(clause):
+					if notNullRe.MatchString(clause) && !defaultRe.MatchString(clause) {
+						col := identifier(addColumnRe.FindStringSubmatch(clause)[1])
+						report(RuleNotNullWithoutDefault, "column %s.%s is NOT NULL without a DEFAULT", table, col)
+					}
+				}
+			}
+		}
+	}
+	return findings
+}
+
+type statement struct {
+	// text is the statement with comments and literals blanked and whitespace collapsed
+	text string
+	line int
+}
+
+// statements splits sql on top-level semicolons.
+func statements(sql string) []statement {
+	masked := maskSQL(sql)
+
+	var out []statement
+	start := 0
+	for i := 0; i <= len(masked); i++ {
+		if i < len(masked) && masked[i] != ';' {
+			continue
+		}
+		chunk := masked[start:i]
+		if text := strings.TrimSpace(whitespaceRuns.ReplaceAllString(chunk, " ")); text != "" {
+			lead := len(chunk) - len(strings.TrimLeft(chunk, " \t\r\n"))
+			line := 1 + strings.Count(masked[:start+lead], "\n")
+			out = append(out, statement{text: text, line: line})
+		}
+		start = i + 1
+	}
+	return out
+}
+
+// maskSQL blanks comments, quoted literals and dollar-quoted bodies with spaces,
+// keeping newlines so offsets still map to the original lines.
+func maskSQL(sql string) string {
+	b := []byte(sql)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(b); i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+	}
+
+	for i := 0; i < len(b); i++ {
+		switch {
+		case b[i] == '-' && i+1 < len(b) && b[i+1] == '-':
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(b) - i
+			}
+			blank(i, i+end)
+			i += end
+		case b[i] == '/' && i+1 < len(b) && b[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(b) - i
+			} else {
+				end += 4
+			}
+			blank(i, i+end)
+			i += end - 1
+		case b[i] == '\'':
+			// '' escapes a quote, which simply reads as two adjacent literals here
+			end := strings.IndexByte(sql[i+1:], '\'')
+			if end < 0 {
+				end = len(b) - i - 1
+			}
+			// keep the quotes, so "DEFAULT ''" still reads as a default
+			blank(i+1, i+1+end)
+			i += end + 1
+		case b[i] == '$':
+			tag := dollarTag(sql[i:])
+			if tag == "" {
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				end = len(b) - i - len(tag)
+			}
+			blank(i+len(tag), i+len(tag)+end)
+			i += len(tag) + end + len(tag) - 1
+		}
+	}
+	return string(b)
+}
+
+// dollarTag returns the opening "$tag$" at the start of s, if any.
+func dollarTag(s string) string {
+	for j := 1; j < len(s); j++ {
+		c := s[j]
+		switch {
+		case c == '$':
+			return s[:j+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || j > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// splitTopLevel splits ALTER TABLE actions on commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var out []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				out = append(out, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(out, strings.TrimSpace(s[start:]))
+}
+
+// relation normalizes an upper-cased relation name the way Postgres folds it,
+// dropping the default schema.
+func relation(name string) string {
+	name = identifier(name)
+	return strings.TrimPrefix(name, "public.")
+}
+
+func identifier(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, `"`, ""))
+}
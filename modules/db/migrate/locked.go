@@ -0,0 +1,86 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultLockName is the lock held while a startup run applies migrations.
+const DefaultLockName = "schema.migrate"
+
+type (
+	// Locker serializes migration runs across instances.
+	//
+	// locking.Locker satisfies it; a Postgres advisory lock (locking.PostgresLocker)
+	// is the natural fit, as it needs nothing beyond the database being migrated.
+	// The returned context is cancelled when the lock is lost.
+	Locker interface {
+		WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error)
+		TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error)
+	}
+
+	// Status reports the outcome of UpLocked.
+	Status struct {
+		// Applied lists the migrations this instance applied; empty when another
+		// instance got there first or the schema was already up to date.
+		Applied []Migration
+		// Waited is how long the lock was held by another instance.
+		Waited time.Duration
+		// Duration is the total time of the run, including Waited.
+		Duration time.Duration
+	}
+)
+
+// UpLocked runs Up while holding name on locker, so that when several instances
+// roll out at once exactly one applies the pending migrations and the others wait
+// for it, then find nothing left to do.
+//
+// The wait is bounded by ctx only; callers should give it a deadline long enough
+// for the slowest migration. Migrations run on the lock context, so losing the lock
+// aborts the current migration rather than letting a second instance race it.
+func (m *Migrator) UpLocked(ctx context.Context, locker Locker, name string, migrations []Migration) (Status, error) {
+	start := time.Now()
+	if name == "" {
+		name = DefaultLockName
+	}
+
+	lockCtx, release, err := locker.TryWithContext(ctx, name)
+	var waited time.Duration
+	if err != nil {
+		// lockers disagree on the "held elsewhere" error, so any failure falls back to waiting
+		slog.InfoContext(ctx, "migrate: lock held by another instance, waiting",
+			slog.String("lock", name),
+			slog.Any("reason", err),
+		)
+		waitStart := time.Now()
+		lockCtx, release, err = locker.WithContext(ctx, name)
+		if err != nil {
+			return Status{Duration: time.Since(start)}, fmt.Errorf("migrate: acquire lock %q: %w", name, err)
+		}
+		waited = time.Since(waitStart)
+		slog.InfoContext(ctx, "migrate: lock acquired", slog.String("lock", name), slog.Duration("waited", waited))
+	}
+	defer release()
+
+	applied, err := m.Up(lockCtx, migrations)
+	if err != nil && lockCtx.Err() != nil && ctx.Err() == nil {
+		err = fmt.Errorf("migrate: lock %q lost during run: %w", name, err)
+	}
+	return Status{Applied: applied, Waited: waited, Duration: time.Since(start)}, err
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultTable records applied migrations.
+const DefaultTable = "schema_migrations"
+
+var (
+	// ErrUnsafeMigration is returned when lint findings block a production run.
+	ErrUnsafeMigration = errors.New("migrate: unsafe migration")
+	// ErrChecksumMismatch is returned when an applied migration file was edited afterwards.
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+)
+
+// UnsafeError lists the findings that stopped a run.
+type UnsafeError struct {
+	Findings []Finding
+}
+
+func (e *UnsafeError) Error() string {
+	lines := make([]string, 0, len(e.Findings))
+	for _, f := range e.Findings {
+		lines = append(lines, f.String())
+	}
+	return fmt.Sprintf("%s (%d findings, rerun with force to apply anyway):\n  %s",
+		ErrUnsafeMigration, len(e.Findings), strings.Join(lines, "\n  "))
+}
+
+func (e *UnsafeError) Is(target error) bool { return target == ErrUnsafeMigration }
+
+type (
+	// Pool is the part of db.ConnectionPool the migrator needs.
+	Pool interface {
+		db.ConnectionManager
+		db.TxManager
+	}
+
+	// Migrator applies pending migrations in version order.
+	Migrator struct {
+		pool  Pool
+		table string
+
+		// production runs refuse lint findings unless forced
+		production bool
+		force      bool
+	}
+
+	// Option configures a Migrator.
+	Option func(*Migrator)
+
+	appliedRow struct {
+		Version  int    `db:"version"`
+		Checksum string `db:"checksum"`
+	}
+)
+
+// WithTable overrides the bookkeeping table name.
+func WithTable(name string) Option {
+	return func(m *Migrator) {
+		if name != "" {
+			m.table = name
+		}
+	}
+}
+
+// WithProduction makes lint findings fatal unless WithForce is also set.
+// Outside production findings are only logged.
+func WithProduction(production bool) Option {
+	return func(m *Migrator) {
+		m.production = production
+	}
+}
+
+// WithForce applies migrations despite lint findings.
+func WithForce(force bool) Option {
+	return func(m *Migrator) {
+		m.force = force
+	}
+}
+
+func New(pool Pool, opts ...Option) *Migrator {
+	m := &Migrator{pool: pool, table: DefaultTable}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Pending returns the migrations not applied yet.
+//
+// Applied migrations whose content changed since are reported as ErrChecksumMismatch:
+// editing history silently diverges environments.
+func (m *Migrator) Pending(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	q := psql.RawQuery("SELECT version, checksum FROM ? ORDER BY version", psql.Quote(m.table))
+	rows, err := bob.All(ctx, m.pool.Writer(), q, scan.StructMapper[appliedRow]())
+	if err != nil {
+		return nil, fmt.Errorf("migrate: list applied: %w", err)
+	}
+	applied := make(map[int]string, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r.Checksum
+	}
+
+	var pending []Migration
+	for _, mig := range migrations {
+		sum, ok := applied[mig.Version]
+		if !ok {
+			pending = append(pending, mig)
+			continue
+		}
+		if sum != mig.Checksum() {
+			return nil, fmt.Errorf("%w: %s was modified after being applied", ErrChecksumMismatch, mig.File)
+		}
+	}
+	return pending, nil
+}
+
+// Up lints and applies every pending migration, returning the ones applied.
+//
+// Each migration runs in its own transaction together with its bookkeeping row,
+// except those using CONCURRENTLY, which Postgres refuses inside a transaction;
+// a failure there may leave an INVALID index behind that must be dropped by hand.
+func (m *Migrator) Up(ctx context.Context, migrations []Migration) ([]Migration, error) {
+	pending, err := m.Pending(ctx, migrations)
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) == 0 {
+		slog.InfoContext(ctx, "migrate: schema is up to date")
+		return nil, nil
+	}
+
+	if findings := Lint(pending); len(findings) > 0 {
+		for _, f := range findings {
+			slog.WarnContext(ctx, "migrate: unsafe statement",
+				slog.String("file", f.File),
+				slog.Int("line", f.Line),
+				slog.String("rule", string(f.Rule)),
+				slog.String("message", f.Message),
+			)
+		}
+		if m.production && !m.force {
+			return nil, &UnsafeError{Findings: findings}
+		}
+	}
+
+	var done []Migration
+	for _, mig := range pending {
+		start := time.Now()
+		if err := m.apply(ctx, mig); err != nil {
+			return done, fmt.Errorf("migrate: apply %s: %w", mig.File, err)
+		}
+		slog.InfoContext(ctx, "migrate: applied",
+			slog.String("migration", mig.Name()),
+			slog.Duration("duration", time.Since(start)),
+		)
+		done = append(done, mig)
+	}
+	return done, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	record := psql.RawQuery("INSERT INTO ? (version, description, checksum) VALUES (?, ?, ?)",
+		psql.Quote(m.table), mig.Version, mig.Description, mig.Checksum())
+
+	if mig.concurrent() {
+		// no arguments: pgx sends the file with the simple protocol, so it may hold several statements
+		if _, err := m.pool.Writer().ExecContext(ctx, mig.SQL); err != nil {
+			return err
+		}
+		_, err := record.Exec(ctx, m.pool.Writer())
+		return err
+	}
+
+	return m.pool.WithTx(ctx, func(ctx context.Context, q db.Querier) error {
+		if _, err := q.ExecContext(ctx, mig.SQL); err != nil {
+			return err
+		}
+		_, err := record.Exec(ctx, q)
+		return err
+	})
+}
+
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	q := psql.RawQuery(`CREATE TABLE IF NOT EXISTS ? (
+    version INTEGER PRIMARY KEY,
+    description TEXT NOT NULL,
+    checksum TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT current_timestamp
+)`, psql.Quote(m.table))
+	if _, err := q.Exec(ctx, m.pool.Writer()); err != nil {
+		return fmt.Errorf("migrate: create %s: %w", m.table, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var fileNameRe = regexp.MustCompile(`^V(\d+)_(\w+)\.sql$`)
+
+// Migration is a single versioned migration file.
+type Migration struct {
+	Version     int
+	Description string
+	// File is the path inside the source FS.
+	File string
+	SQL  string
+}
+
+// Checksum fingerprints the migration content, so edits to applied files are detected.
+func (m Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.SQL))
+	return hex.EncodeToString(sum[:])
+}
+
+// Name renders the migration as "V<version>_<description>".
+func (m Migration) Name() string {
+	return "V" + strconv.Itoa(m.Version) + "_" + m.Description
+}
+
+// concurrent reports whether the migration builds or drops an index CONCURRENTLY,
+// which Postgres refuses inside a transaction block.
+func (m Migration) concurrent() bool {
+	return concurrentlyRe.MatchString(maskSQL(m.SQL))
+}
+
+// Load reads every migration file in dir, sorted by version.
+//
+// Files not matching V<version>_<description>.sql are ignored; duplicate versions are an error.
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read %q: %w", dir, err)
+	}
+
+	var out []Migration
+	seen := make(map[int]string)
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		m := fileNameRe.FindStringSubmatch(e.Name())
+		if m == nil {
+			continue
+		}
+		version, err := strconv.Atoi(m[1])
+		if err != nil {
+			return nil, fmt.Errorf("migrate: %q: invalid version: %w", e.Name(), err)
+		}
+		if prev, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrate: version %d declared by both %q and %q", version, prev, e.Name())
+		}
+		seen[version] = e.Name()
+
+		file := path.Join(dir, e.Name())
+		b, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("migrate: read %q: %w", file, err)
+		}
+		out = append(out, Migration{
+			Version:     version,
+			Description: m[2],
+			File:        file,
+			SQL:         strings.TrimSpace(string(b)),
+		})
+	}
+
+	slices.SortFunc(out, func(a, b Migration) int { return a.Version - b.Version })
+	return out, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apikey
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/apikey"
+	"app/modules/db"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultTable is the table created by the api_keys migration.
+const DefaultTable = "api_keys"
+
+var _ apikey.Store = (*PostgresStore)(nil)
+
+type (
+	// PostgresStore is an apikey.Store on a Postgres table.
+	//
+	// Every query runs on the primary: a key must stop working as soon as it is
+	// revoked, and work as soon as it is issued, whatever the replica lag.
+	PostgresStore struct {
+		pool db.ConnectionManager
+
+		insertSQL string
+		findSQL   string
+		listSQL   string
+		revokeSQL string
+	}
+
+	keyRow struct {
+		ID        uuid.UUID    `db:"id"`
+		Hint      string       `db:"hint"`
+		Name      string       `db:"name"`
+		Subject   string       `db:"subject"`
+		Scopes    string       `db:"scopes"`
+		CreatedAt time.Time    `db:"created_at"`
+		ExpiresAt sql.NullTime `db:"expires_at"`
+		RevokedAt sql.NullTime `db:"revoked_at"`
+	}
+)
+
+// NewPostgresStore constructs a store over table, DefaultTable when empty.
+func NewPostgresStore(pool db.ConnectionManager, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultTable
+	}
+	t := `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	const columns = `id, hint, name, subject, scopes, created_at, expires_at, revoked_at`
+
+	return &PostgresStore{
+		pool: pool,
+		insertSQL: `INSERT INTO ` + t + ` (id, key_hash, hint, name, subject, scopes, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		findSQL: `SELECT ` + columns + ` FROM ` + t + ` WHERE key_hash = ?`,
+		listSQL: `SELECT ` + columns + ` FROM ` + t + ` WHERE ? = '' OR subject = ? ORDER BY subject, created_at`,
+		// COALESCE keeps the first revocation time
+		revokeSQL: `UPDATE ` + t + ` SET revoked_at = COALESCE(revoked_at, ?) WHERE id = ?`,
+	}
+}
+
+// Insert implements apikey.Store.
+func (p *PostgresStore) Insert(ctx context.Context, key apikey.Key, hash []byte) error {
+	var expiresAt sql.NullTime
+	if key.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *key.ExpiresAt, Valid: true}
+	}
+	q := psql.RawQuery(p.insertSQL,
+		key.ID, hash, key.Hint, key.Name, key.Subject, strings.Join(key.Scopes, " "), key.CreatedAt, expiresAt,
+	)
+	if _, err := bob.Exec(ctx, p.pool.Writer(), q); err != nil {
+		return fmt.Errorf("apikey: insert %s: %w", key.ID, err)
+	}
+	return nil
+}
+
+// FindByHash implements apikey.Store. Revoked keys are returned with RevokedAt
+// set, so Verify can tell them apart in its logs.
+func (p *PostgresStore) FindByHash(ctx context.Context, hash []byte) (apikey.Key, error) {
+	row, err := bob.One(ctx, p.pool.Writer(), psql.RawQuery(p.findSQL, hash), scan.StructMapper[keyRow]())
+	if errors.Is(err, sql.ErrNoRows) {
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+	if err != nil {
+		return apikey.Key{}, fmt.Errorf("apikey: find: %w", err)
+	}
+	return toKey(row), nil
+}
+
+// List implements apikey.Store.
+func (p *PostgresStore) List(ctx context.Context, subject string) ([]apikey.Key, error) {
+	rows, err := bob.All(ctx, p.pool.Writer(), psql.RawQuery(p.listSQL, subject, subject), scan.StructMapper[keyRow]())
+	if err != nil {
+		return nil, fmt.Errorf("apikey: list: %w", err)
+	}
+	keys := make([]apikey.Key, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, toKey(row))
+	}
+	return keys, nil
+}
+
+// Revoke implements apikey.Store.
+func (p *PostgresStore) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	res, err := bob.Exec(ctx, p.pool.Writer(), psql.RawQuery(p.revokeSQL, at, id))
+	if err != nil {
+		return fmt.Errorf("apikey: revoke %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return apikey.ErrNotFound
+	}
+	return nil
+}
+
+func toKey(row keyRow) apikey.Key {
+	key := apikey.Key{
+		ID:        row.ID,
+		Name:      row.Name,
+		Subject:   row.Subject,
+		Scopes:    strings.Fields(row.Scopes),
+		Hint:      row.Hint,
+		CreatedAt: row.CreatedAt,
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	if row.ExpiresAt.Valid {
+		key.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.RevokedAt.Valid {
+		key.RevokedAt = &row.RevokedAt.Time
+	}
+	return key
+}
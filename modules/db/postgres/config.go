@@ -1,10 +1,26 @@
 package postgres
 
+import "time"
+
 type (
 	// Note: For env parsing to work, we must export all struct fields
 	PostgresConfig struct {
 		WriteConfig PoolConfig   `envPrefix:"PRIMARY_"`
 		ReadConfigs []PoolConfig `envPrefix:"REPLICA_"`
+
+		// StickyWindow is how long reads of a just written row go to the primary
+		// (read-your-writes, see db.RecentWrites). Zero always reads from replicas.
+		StickyWindow time.Duration `env:"STICKY_WINDOW" envDefault:"2s"`
+
+		// StatementTimeout bounds every statement server side, zero keeps the
+		// server default (see WithStatementTimeout).
+		StatementTimeout time.Duration `env:"STATEMENT_TIMEOUT" envDefault:"0"`
+		// SlowQueryThreshold is the duration from which queries are logged as slow,
+		// zero disables the log (see WithSlowQueryLog).
+		SlowQueryThreshold time.Duration `env:"SLOW_QUERY_THRESHOLD" envDefault:"500ms"`
+
+		// TxRetry re-runs WithTx on serialization failures and deadlocks.
+		TxRetry TxRetryConfig `envPrefix:"TX_RETRY_"`
 	}
 
 	PoolConfig struct {
@@ -0,0 +1,132 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/db"
+	"app/modules/ratelimit"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultTable is the table created by the rate_limit_counters migration.
+const DefaultTable = "rate_limit_counters"
+
+// DefaultPurgeBatch is how many expired counters Purge deletes per statement.
+const DefaultPurgeBatch = 1000
+
+var _ ratelimit.CounterStore = (*PostgresCounter)(nil)
+
+// PostgresCounter is a CounterStore on a Postgres table, for deployments without Redis.
+//
+// Every Incr is a single UPSERT ... RETURNING on the primary: a counter whose
+// expires_at has passed restarts at 1 with a fresh expiry, so expired rows
+// never need to be deleted for correctness. Purge removes them to keep the
+// table small and should run periodically (see the ratelimit.counters.purge job).
+//
+// Expiry uses the database clock, so nodes with skewed clocks agree on windows.
+type PostgresCounter struct {
+	pool   db.ConnectionManager
+	prefix string
+
+	incrSQL  string
+	getSQL   string
+	purgeSQL string
+}
+
+// NewPostgresCounterStore constructs a store over table, DefaultTable when empty.
+//
+// prefix is optional; if non-empty, keys become prefix + ":" + key.
+func NewPostgresCounterStore(pool db.ConnectionManager, table, prefix string) *PostgresCounter {
+	if table == "" {
+		table = DefaultTable
+	}
+	if prefix != "" && prefix[len(prefix)-1] != ':' {
+		prefix += ":"
+	}
+	t := `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+
+	return &PostgresCounter{
+		pool:   pool,
+		prefix: prefix,
+		// ttl = 0 keeps the counter forever, a negative ttl expires it right away (like PEXPIRE)
+		incrSQL: `INSERT INTO ` + t + ` AS c (key, value, expires_at)
+VALUES (?, 1, CASE WHEN ?::bigint = 0 THEN 'infinity'::timestamptz ELSE now() + ?::bigint * interval '1 millisecond' END)
+ON CONFLICT (key) DO UPDATE SET
+	value = CASE WHEN c.expires_at <= now() THEN 1 ELSE c.value + 1 END,
+	expires_at = CASE WHEN c.expires_at <= now() THEN EXCLUDED.expires_at ELSE c.expires_at END
+RETURNING value`,
+		getSQL: `SELECT value FROM ` + t + ` WHERE key = ? AND expires_at > now()`,
+		purgeSQL: `DELETE FROM ` + t + ` WHERE key IN (
+	SELECT key FROM ` + t + ` WHERE expires_at <= now() LIMIT ? FOR UPDATE SKIP LOCKED
+)`,
+	}
+}
+
+// Incr implements ratelimit.CounterStore.
+func (p *PostgresCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	ms := ttl.Milliseconds()
+	if ttl > 0 && ms == 0 {
+		ms = 1
+	}
+	v, err := bob.One(ctx, p.pool.Writer(), psql.RawQuery(p.incrSQL, p.prefix+key, ms, ms), scan.SingleColumnMapper[int64])
+	if err != nil {
+		return 0, fmt.Errorf("counter: incr %q: %w", key, err)
+	}
+	return v, nil
+}
+
+// Get implements ratelimit.CounterStore. It reads the primary: a replica may lag
+// behind the increments the limiter just made.
+func (p *PostgresCounter) Get(ctx context.Context, key string) (int64, error) {
+	v, err := bob.One(ctx, p.pool.Writer(), psql.RawQuery(p.getSQL, p.prefix+key), scan.SingleColumnMapper[int64])
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("counter: get %q: %w", key, err)
+	}
+	return v, nil
+}
+
+// Purge deletes expired counters in batches of DefaultPurgeBatch and returns how
+// many were deleted. It stops early, without error, when ctx is done.
+func (p *PostgresCounter) Purge(ctx context.Context) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		res, err := bob.Exec(ctx, p.pool.Writer(), psql.RawQuery(p.purgeSQL, DefaultPurgeBatch))
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return total, fmt.Errorf("counter: purge expired: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		if n < DefaultPurgeBatch {
+			break
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/db"
+	"app/modules/delivery"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultTable is the table created by the deliveries migration.
+const DefaultTable = "deliveries"
+
+// DefaultPurgeBatch is how many deliveries Purge deletes per statement.
+const DefaultPurgeBatch = 1000
+
+var _ delivery.Store = (*PostgresStore)(nil)
+
+type (
+	// PostgresStore is a delivery.Store on a Postgres table.
+	//
+	// Every query runs on the primary: a dispatcher reading pending deliveries
+	// from a lagging replica would attempt again the ones that just succeeded.
+	PostgresStore struct {
+		pool db.ConnectionManager
+
+		insertSQL  string
+		recordSQL  string
+		pendingSQL string
+		requeueSQL string
+		purgeSQL   string
+	}
+
+	deliveryRow struct {
+		ID          uuid.UUID      `db:"id"`
+		Destination string         `db:"destination"`
+		Event       string         `db:"event"`
+		Payload     []byte         `db:"payload"`
+		PayloadHash []byte         `db:"payload_hash"`
+		Status      string         `db:"status"`
+		Attempts    int            `db:"attempts"`
+		LastError   sql.NullString `db:"last_error"`
+		CreatedAt   time.Time      `db:"created_at"`
+		UpdatedAt   time.Time      `db:"updated_at"`
+	}
+)
+
+// NewPostgresStore constructs a store over table, DefaultTable when empty.
+func NewPostgresStore(pool db.ConnectionManager, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultTable
+	}
+	t := `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	const columns = `id, destination, event, payload, payload_hash, status, attempts, last_error, created_at, updated_at`
+
+	return &PostgresStore{
+		pool: pool,
+		insertSQL: `INSERT INTO ` + t + ` (` + columns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		recordSQL: `UPDATE ` + t + ` SET status = ?, attempts = attempts + 1, last_error = NULLIF(?, ''), updated_at = ? WHERE id = ?`,
+		// matches the status index
+		pendingSQL: `SELECT ` + columns + ` FROM ` + t + ` WHERE status = 'pending' ORDER BY updated_at LIMIT ?`,
+		requeueSQL: `UPDATE ` + t + ` SET status = 'pending', updated_at = ?
+WHERE status = ?
+  AND (? = '' OR destination = ?)
+  AND (? = '' OR event = ?)
+  AND (?::timestamptz IS NULL OR created_at >= ?)
+  AND (?::timestamptz IS NULL OR created_at < ?)`,
+		purgeSQL: `DELETE FROM ` + t + ` WHERE id IN (
+	SELECT id FROM ` + t + ` WHERE status <> 'pending' AND updated_at < ? LIMIT ? FOR UPDATE SKIP LOCKED
+)`,
+	}
+}
+
+// Insert implements delivery.Store.
+func (p *PostgresStore) Insert(ctx context.Context, d delivery.Delivery) error {
+	q := psql.RawQuery(p.insertSQL,
+		d.ID, d.Destination, d.Event, d.Payload, d.PayloadHash, string(d.Status), d.Attempts,
+		sql.NullString{String: d.LastError, Valid: d.LastError != ""}, d.CreatedAt, d.UpdatedAt,
+	)
+	if _, err := bob.Exec(ctx, p.pool.Writer(), q); err != nil {
+		return fmt.Errorf("delivery: insert %s: %w", d.ID, err)
+	}
+	return nil
+}
+
+// Record implements delivery.Store.
+func (p *PostgresStore) Record(ctx context.Context, id uuid.UUID, status delivery.Status, lastError string, at time.Time) error {
+	res, err := bob.Exec(ctx, p.pool.Writer(), psql.RawQuery(p.recordSQL, string(status), lastError, at, id))
+	if err != nil {
+		return fmt.Errorf("delivery: record %s: %w", id, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return delivery.ErrNotFound
+	}
+	return nil
+}
+
+// Pending implements delivery.Store.
+func (p *PostgresStore) Pending(ctx context.Context, limit int) ([]delivery.Delivery, error) {
+	rows, err := bob.All(ctx, p.pool.Writer(), psql.RawQuery(p.pendingSQL, limit), scan.StructMapper[deliveryRow]())
+	if err != nil {
+		return nil, fmt.Errorf("delivery: pending: %w", err)
+	}
+	ds := make([]delivery.Delivery, 0, len(rows))
+	for _, row := range rows {
+		ds = append(ds, toDelivery(row))
+	}
+	return ds, nil
+}
+
+// Requeue implements delivery.Store.
+func (p *PostgresStore) Requeue(ctx context.Context, f delivery.Filter, at time.Time) (int64, error) {
+	since, until := nullTime(f.Since), nullTime(f.Until)
+	q := psql.RawQuery(p.requeueSQL,
+		at, string(f.Status),
+		f.Destination, f.Destination,
+		f.Event, f.Event,
+		since, since,
+		until, until,
+	)
+	res, err := bob.Exec(ctx, p.pool.Writer(), q)
+	if err != nil {
+		return 0, fmt.Errorf("delivery: requeue: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n, nil
+}
+
+// Purge implements delivery.Store, deleting in batches of DefaultPurgeBatch. It
+// stops early, without error, when ctx is done.
+func (p *PostgresStore) Purge(ctx context.Context, cutoff time.Time) (int64, error) {
+	var total int64
+	for ctx.Err() == nil {
+		res, err := bob.Exec(ctx, p.pool.Writer(), psql.RawQuery(p.purgeSQL, cutoff, DefaultPurgeBatch))
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return total, fmt.Errorf("delivery: purge: %w", err)
+		}
+		n, _ := res.RowsAffected()
+		total += n
+		if n < DefaultPurgeBatch {
+			break
+		}
+	}
+	return total, nil
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
+
+func toDelivery(row deliveryRow) delivery.Delivery {
+	return delivery.Delivery{
+		ID:          row.ID,
+		Destination: row.Destination,
+		Event:       row.Event,
+		Payload:     row.Payload,
+		PayloadHash: row.PayloadHash,
+		Status:      delivery.Status(row.Status),
+		Attempts:    row.Attempts,
+		LastError:   row.LastError.String,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+	}
+}
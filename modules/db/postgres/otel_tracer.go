@@ -0,0 +1,176 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// rowsAffectedKey is not part of the semantic conventions yet.
+const rowsAffectedKey = attribute.Key("db.rows_affected")
+
+type (
+	// OtelTracerOption configures the tracer installed by WithOtelTracer.
+	OtelTracerOption func(*otelTracer)
+
+	otelTracer struct {
+		tracer trace.Tracer
+		pool   string
+	}
+)
+
+var (
+	_ pgx.QueryTracer    = (*otelTracer)(nil)
+	_ pgx.BatchTracer    = (*otelTracer)(nil)
+	_ pgx.CopyFromTracer = (*otelTracer)(nil)
+	_ pgx.PrepareTracer  = (*otelTracer)(nil)
+)
+
+// WithTracedPoolName tags every span with the pool it ran on ("primary", "replica").
+func WithTracedPoolName(pool string) OtelTracerOption {
+	return func(t *otelTracer) {
+		t.pool = pool
+	}
+}
+
+// WithOtelTracer emits a client span for every query, batch, COPY and prepare on
+// the pool's connections, as a child of the span in the query context, so Postgres
+// calls show up under the HTTP request trace.
+//
+// Spans carry the statement (db.query.text, formerly db.statement), the rows
+// affected and the server address; the duration is the span's own. Arguments are
+// never recorded. It can be combined with WithSlowQueryLog.
+func WithOtelTracer(opts ...OtelTracerOption) PgxConfigOption {
+	return func(cfg *pgxpool.Config) {
+		t := &otelTracer{tracer: otel.Tracer(instrumentationName)}
+		for _, opt := range opts {
+			if opt != nil {
+				opt(t)
+			}
+		}
+		addTracer(cfg, t)
+	}
+}
+
+// addTracer installs t next to any tracer already configured.
+func addTracer(cfg *pgxpool.Config, t pgx.QueryTracer) {
+	if cfg.ConnConfig.Tracer != nil {
+		cfg.ConnConfig.Tracer = multitracer.New(cfg.ConnConfig.Tracer, t)
+		return
+	}
+	cfg.ConnConfig.Tracer = t
+}
+
+func (t *otelTracer) start(ctx context.Context, conn *pgx.Conn, name string, attrs ...attribute.KeyValue) context.Context {
+	attrs = append(attrs, semconv.DBSystemPostgreSQL)
+	if t.pool != "" {
+		attrs = append(attrs, attribute.String("db.pool", t.pool))
+	}
+	if conn != nil {
+		cfg := conn.Config()
+		attrs = append(attrs,
+			semconv.DBNamespace(cfg.Database),
+			semconv.ServerAddress(cfg.Host),
+			semconv.ServerPort(int(cfg.Port)),
+		)
+	}
+	ctx, _ = t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	return ctx
+}
+
+func endSpan(ctx context.Context, tag pgconn.CommandTag, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetAttributes(rowsAffectedKey.Int64(tag.RowsAffected()))
+	}
+	span.End()
+}
+
+func (t *otelTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	op := operationName(data.SQL)
+	_, text := sqlDigest(data.SQL)
+	return t.start(ctx, conn, op, semconv.DBOperationName(op), semconv.DBQueryText(text))
+}
+
+func (t *otelTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	endSpan(ctx, data.CommandTag, data.Err)
+}
+
+func (t *otelTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	size := 0
+	if data.Batch != nil {
+		size = data.Batch.Len()
+	}
+	return t.start(ctx, conn, "BATCH", semconv.DBOperationName("BATCH"), attribute.Int("db.batch.size", size))
+}
+
+// TraceBatchQuery records each statement of the batch as an event of the batch span.
+func (t *otelTracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	_, text := sqlDigest(data.SQL)
+	attrs := []attribute.KeyValue{semconv.DBQueryText(text), rowsAffectedKey.Int64(data.CommandTag.RowsAffected())}
+	if data.Err != nil {
+		attrs = append(attrs, attribute.String("error.message", data.Err.Error()))
+	}
+	trace.SpanFromContext(ctx).AddEvent("query", trace.WithAttributes(attrs...))
+}
+
+func (t *otelTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	endSpan(ctx, pgconn.CommandTag{}, data.Err)
+}
+
+func (t *otelTracer) TraceCopyFromStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceCopyFromStartData) context.Context {
+	table := data.TableName.Sanitize()
+	return t.start(ctx, conn, "COPY "+table, semconv.DBOperationName("COPY"), semconv.DBCollectionName(table))
+}
+
+func (t *otelTracer) TraceCopyFromEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceCopyFromEndData) {
+	endSpan(ctx, data.CommandTag, data.Err)
+}
+
+func (t *otelTracer) TracePrepareStart(ctx context.Context, conn *pgx.Conn, data pgx.TracePrepareStartData) context.Context {
+	_, text := sqlDigest(data.SQL)
+	return t.start(ctx, conn, "PREPARE", semconv.DBOperationName("PREPARE"), semconv.DBQueryText(text))
+}
+
+func (t *otelTracer) TracePrepareEnd(ctx context.Context, _ *pgx.Conn, data pgx.TracePrepareEndData) {
+	endSpan(ctx, pgconn.CommandTag{}, data.Err)
+}
+
+// operationName is the leading keyword of the statement (SELECT, INSERT, WITH, ...),
+// short and low-cardinality enough to name the span.
+func operationName(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "app/modules/db/postgres"
+
+// digestMaxLen bounds the SQL text logged with a slow query.
+const digestMaxLen = 512
+
+// WithStatementTimeout makes the server cancel any statement running longer than d
+// on the pool's connections (statement_timeout, reported as SQLSTATE 57014).
+// Zero or negative keeps the server default.
+//
+// The setting is sent as a startup parameter; behind PgBouncer it must be listed
+// in ignore_startup_parameters or set on the PgBouncer side instead.
+func WithStatementTimeout(d time.Duration) PgxConfigOption {
+	return func(cfg *pgxpool.Config) {
+		if d <= 0 {
+			return
+		}
+		if cfg.ConnConfig.RuntimeParams == nil {
+			cfg.ConnConfig.RuntimeParams = make(map[string]string)
+		}
+		cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(d.Milliseconds(), 10)
+	}
+}
+
+// WithSlowQueryLog logs every query taking at least threshold, with its SQL digest
+// and duration, and records it in the app_db_slow_query_duration_seconds histogram.
+// pool names the pool in both ("primary", "replica"). Zero or negative disables it.
+func WithSlowQueryLog(pool string, threshold time.Duration) PgxConfigOption {
+	return func(cfg *pgxpool.Config) {
+		if threshold <= 0 {
+			return
+		}
+		addTracer(cfg, newSlowQueryTracer(pool, threshold))
+	}
+}
+
+type (
+	slowQueryTracer struct {
+		pool      string
+		threshold time.Duration
+		durations metric.Float64Histogram
+	}
+
+	queryStartKey struct{}
+
+	queryStart struct {
+		sql string
+		at  time.Time
+	}
+)
+
+var _ pgx.QueryTracer = (*slowQueryTracer)(nil)
+
+func newSlowQueryTracer(pool string, threshold time.Duration) *slowQueryTracer {
+	t := &slowQueryTracer{pool: pool, threshold: threshold}
+	var err error
+	if t.durations, err = otel.Meter(instrumentationName).Float64Histogram("app_db_slow_query_duration_seconds",
+		metric.WithDescription("Duration of queries exceeding the slow query threshold"),
+		metric.WithUnit("s"),
+	); err != nil {
+		slog.Warn("postgres: slow query histogram not created", slog.Any("error", err))
+	}
+	return t
+}
+
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{sql: data.SQL, at: time.Now()})
+}
+
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	d := time.Since(start.at)
+	if d < t.threshold {
+		return
+	}
+
+	digest, text := sqlDigest(start.sql)
+	attrs := []slog.Attr{
+		slog.String("pool", t.pool),
+		slog.String("db.query.digest", digest),
+		slog.String("db.query.text", text),
+		slog.Duration("duration", d),
+		slog.Duration("threshold", t.threshold),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, slog.Any("error", data.Err))
+	}
+	slog.LogAttrs(ctx, slog.LevelWarn, "postgres: slow query", attrs...)
+
+	if t.durations != nil {
+		// the query context may be cancelled already
+		t.durations.Record(context.WithoutCancel(ctx), d.Seconds(), metric.WithAttributes(
+			attribute.String("pool", t.pool),
+			attribute.String("db.query.digest", digest),
+			attribute.Bool("error", data.Err != nil),
+		))
+	}
+}
+
+// sqlDigest normalizes whitespace and returns a short stable hash of the statement
+// with the (truncated) text. Queries are parameterized, so the digest identifies the
+// statement shape without carrying user data.
+func sqlDigest(sql string) (digest, text string) {
+	text = strings.Join(strings.Fields(sql), " ")
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(text))
+	digest = fmt.Sprintf("%016x", h.Sum64())
+	if len(text) > digestMaxLen {
+		text = text[:digestMaxLen] + "..."
+	}
+	return digest, text
+}
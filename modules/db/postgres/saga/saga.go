@@ -0,0 +1,156 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saga
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/db"
+	"app/modules/saga"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultTable is the table created by the saga_instances migration.
+const DefaultTable = "saga_instances"
+
+var _ saga.Store = (*PostgresStore)(nil)
+
+type (
+	// PostgresStore is a saga.Store on a Postgres table.
+	//
+	// Every query runs on the primary: a saga reads its state right after saving
+	// it, and a stale replica would replay steps that are already done.
+	PostgresStore struct {
+		pool db.ConnectionManager
+
+		insertSQL     string
+		getSQL        string
+		updateSQL     string
+		unfinishedSQL string
+	}
+
+	instanceRow struct {
+		ID        uuid.UUID      `db:"id"`
+		Saga      string         `db:"saga"`
+		Status    string         `db:"status"`
+		Step      int            `db:"step"`
+		Data      []byte         `db:"data"`
+		Error     sql.NullString `db:"error"`
+		CreatedAt time.Time      `db:"created_at"`
+		UpdatedAt time.Time      `db:"updated_at"`
+	}
+)
+
+// NewPostgresStore constructs a store over table, DefaultTable when empty.
+func NewPostgresStore(pool db.ConnectionManager, table string) *PostgresStore {
+	if table == "" {
+		table = DefaultTable
+	}
+	t := `"` + strings.ReplaceAll(table, `"`, `""`) + `"`
+	const columns = `id, saga, status, step, data, error, created_at, updated_at`
+
+	return &PostgresStore{
+		pool: pool,
+		insertSQL: `INSERT INTO ` + t + ` (` + columns + `)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		getSQL:    `SELECT ` + columns + ` FROM ` + t + ` WHERE id = ?`,
+		updateSQL: `UPDATE ` + t + ` SET status = ?, step = ?, data = ?, error = ?, updated_at = ? WHERE id = ?`,
+		// matches the partial index on unfinished instances
+		unfinishedSQL: `SELECT ` + columns + ` FROM ` + t + `
+WHERE saga = ? AND status IN ('running', 'compensating') AND updated_at < ?
+ORDER BY updated_at LIMIT ?`,
+	}
+}
+
+// Create implements saga.Store.
+func (p *PostgresStore) Create(ctx context.Context, inst saga.Instance) error {
+	q := psql.RawQuery(p.insertSQL,
+		inst.ID, inst.Saga, string(inst.Status), inst.Step, []byte(inst.Data), nullString(inst.Error), inst.CreatedAt, inst.UpdatedAt,
+	)
+	if _, err := bob.Exec(ctx, p.pool.Writer(), q); err != nil {
+		return fmt.Errorf("saga: insert %s: %w", inst.ID, err)
+	}
+	return nil
+}
+
+// Get implements saga.Store.
+func (p *PostgresStore) Get(ctx context.Context, id uuid.UUID) (saga.Instance, error) {
+	row, err := bob.One(ctx, p.pool.Writer(), psql.RawQuery(p.getSQL, id), scan.StructMapper[instanceRow]())
+	if errors.Is(err, sql.ErrNoRows) {
+		return saga.Instance{}, saga.ErrNotFound
+	}
+	if err != nil {
+		return saga.Instance{}, fmt.Errorf("saga: get %s: %w", id, err)
+	}
+	return toInstance(row), nil
+}
+
+// Update implements saga.Store.
+func (p *PostgresStore) Update(ctx context.Context, inst saga.Instance) error {
+	q := psql.RawQuery(p.updateSQL,
+		string(inst.Status), inst.Step, []byte(inst.Data), nullString(inst.Error), inst.UpdatedAt, inst.ID,
+	)
+	res, err := bob.Exec(ctx, p.pool.Writer(), q)
+	if err != nil {
+		return fmt.Errorf("saga: update %s: %w", inst.ID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return saga.ErrNotFound
+	}
+	return nil
+}
+
+// Unfinished implements saga.Store.
+func (p *PostgresStore) Unfinished(ctx context.Context, name string, before time.Time, limit int) ([]saga.Instance, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	rows, err := bob.All(ctx, p.pool.Writer(), psql.RawQuery(p.unfinishedSQL, name, before, limit), scan.StructMapper[instanceRow]())
+	if err != nil {
+		return nil, fmt.Errorf("saga: list unfinished %q: %w", name, err)
+	}
+	insts := make([]saga.Instance, 0, len(rows))
+	for _, row := range rows {
+		insts = append(insts, toInstance(row))
+	}
+	return insts, nil
+}
+
+func toInstance(row instanceRow) saga.Instance {
+	return saga.Instance{
+		ID:        row.ID,
+		Saga:      row.Saga,
+		Status:    saga.Status(row.Status),
+		Step:      row.Step,
+		Data:      json.RawMessage(row.Data),
+		Error:     row.Error.String,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"app/modules/retry"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// TxRetryConfig is the opt-in policy re-running a WithTx function when Postgres
+// aborts the transaction with a retryable error class.
+//
+// Only the whole transaction can be retried: fn runs again from the start on a new
+// transaction, so it must not have side effects outside the database.
+type TxRetryConfig struct {
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// MaxAttempts counts the first run.
+	MaxAttempts int `env:"MAX_ATTEMPTS" envDefault:"3"`
+	// Backoff before retry n is a random delay in [0, min(MaxDelay, BaseDelay*2^(n-1))).
+	BaseDelay time.Duration `env:"BASE_DELAY" envDefault:"20ms"`
+	MaxDelay  time.Duration `env:"MAX_DELAY"  envDefault:"500ms"`
+}
+
+// IsRetryableTxError reports whether err aborted a transaction that succeeds when
+// run again: serialization_failure (40001) and deadlock_detected (40P01).
+func IsRetryableTxError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == "40001" || pgErr.Code == "40P01"
+}
+
+// runWithRetry calls run until it succeeds, fails with a non retryable error, the
+// attempts are used up or ctx ends. It returns the last error.
+func (c TxRetryConfig) runWithRetry(ctx context.Context, run func(ctx context.Context) error) error {
+	attempts := 1
+	if c.Enabled && c.MaxAttempts > 1 {
+		attempts = c.MaxAttempts
+	}
+	// full jitter, so concurrent losers of the same conflict spread out
+	policy := retry.Policy{MaxAttempts: attempts, BaseDelay: c.BaseDelay, MaxDelay: c.MaxDelay}
+	return retry.Do(ctx, policy, run,
+		retry.If(IsRetryableTxError),
+		retry.WithName("postgres.tx"),
+		retry.WithOnRetry(func(ctx context.Context, attempt int, delay time.Duration, err error) {
+			slog.DebugContext(ctx, "postgres: retrying aborted transaction",
+				slog.Int("attempt", attempt),
+				slog.Duration("backoff", delay),
+				slog.Any("error", err),
+			)
+		}),
+	)
+}
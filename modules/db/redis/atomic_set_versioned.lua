@@ -0,0 +1,38 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+local key = KEYS[1]
+local value = ARGV[1]
+local ttl = tonumber(ARGV[2])
+local field = ARGV[3]
+local version = tonumber(ARGV[4])
+
+local current = redis.call("GET", key)
+if current then
+  local ok, doc = pcall(cjson.decode, current)
+  if ok and type(doc) == "table" then
+    local stored = tonumber(doc[field])
+    if stored and stored > version then
+      return 0
+    end
+  end
+end
+
+if ttl and ttl > 0 then
+  redis.call("SET", key, value, "EX", ttl)
+else
+  redis.call("SET", key, value)
+end
+
+return 1
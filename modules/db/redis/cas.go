@@ -0,0 +1,145 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"app/modules/db"
+
+	"github.com/redis/rueidis"
+)
+
+// ErrCASMismatch is returned when a compare-and-set finds another value than the
+// one it expected, i.e. the key was written concurrently. Read it again and retry.
+// It is db.ErrCASMismatch, so callers holding a db.CASKV can test for it.
+var ErrCASMismatch = db.ErrCASMismatch
+
+var _ db.CASKV = (*RedisKV)(nil)
+
+// updateAttempts bounds how many times Update re-reads a key that keeps changing.
+const updateAttempts = 3
+
+var (
+	//go:embed compare_and_set.lua
+	compareAndSetLua string
+
+	// Lua script for AtomicCompareAndSet:
+	//
+	//   - KEYS[1]  = full key
+	//   - ARGV[1]  = "1" when the key is expected to be missing
+	//   - ARGV[2]  = expected serialized value
+	//   - ARGV[3]  = new serialized value
+	//   - ARGV[4]  = TTL in seconds (string; 0 or empty = no TTL)
+	//
+	// Returns 1 when the value was set, 0 on a mismatch.
+	luaCompareAndSet = rueidis.NewLuaScript(compareAndSetLua)
+)
+
+// AtomicCompareAndSet sets key to value only if it currently holds expected, or
+// only if it does not exist when expected is nil. Values are serialized like
+// AtomicSet and the default TTL applies. It returns ErrCASMismatch when the key
+// holds anything else.
+//
+// While degraded with FailOpen it behaves as if redis had nothing: a set on a
+// missing key is a no-op, any other expectation a mismatch. FailClosed returns
+// ErrDegraded.
+func (k *RedisKV) AtomicCompareAndSet(ctx context.Context, key string, expected, value any) error {
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			if expected == nil {
+				return nil
+			}
+			return fmt.Errorf("redis kv: AtomicCompareAndSet %q: %w", key, ErrCASMismatch)
+		}
+		return fmt.Errorf("redis kv: AtomicCompareAndSet %q: %w", key, ErrDegraded)
+	}
+
+	absent, expectedArg := "1", ""
+	if expected != nil {
+		serialized, err := encodeValue(expected)
+		if err != nil {
+			return fmt.Errorf("redis kv: encode expected value for key %q: %w", key, err)
+		}
+		absent, expectedArg = "0", serialized
+	}
+	serialized, err := encodeValue(value)
+	if err != nil {
+		return fmt.Errorf("redis kv: encode value for key %q: %w", key, err)
+	}
+	ttlArg := ""
+	if ttl := k.ttlSeconds(); ttl > 0 {
+		ttlArg = strconv.FormatInt(ttl, 10)
+	}
+
+	res := luaCompareAndSet.Exec(ctx, k.client, []string{k.key(key)}, []string{absent, expectedArg, serialized, ttlArg})
+	swapped, err := res.AsInt64()
+	if err != nil {
+		return fmt.Errorf("redis kv: AtomicCompareAndSet %q failed: %w", key, err)
+	}
+	if swapped == 0 {
+		return fmt.Errorf("redis kv: AtomicCompareAndSet %q: %w", key, ErrCASMismatch)
+	}
+	return nil
+}
+
+// Update reads key, passes its value (nil when missing) to fn and sets what fn
+// returns with AtomicCompareAndSet. When the key changes in between, fn runs
+// again on the new value, up to a few times before Update gives up with
+// ErrCASMismatch. fn may therefore run more than once and must not have side
+// effects.
+//
+// fn returning a nil slice leaves the key as it is. An error from fn aborts the
+// update and is returned as is. Update returns the value the key holds afterwards.
+// While degraded it returns ErrDegraded whatever the policy: a no-op would report
+// an update that never happened.
+func (k *RedisKV) Update(ctx context.Context, key string, fn func(old []byte) ([]byte, error)) ([]byte, error) {
+	if k.isDegraded() {
+		return nil, fmt.Errorf("redis kv: Update %q: %w", key, ErrDegraded)
+	}
+
+	for range updateAttempts {
+		// reads skip the client-side cache, a stale value would only cause a mismatch
+		old, err := k.client.Do(ctx, k.client.B().Get().Key(k.key(key)).Build()).AsBytes()
+		if err != nil && !rueidis.IsRedisNil(err) {
+			return nil, fmt.Errorf("redis kv: Update %q: get failed: %w", key, err)
+		}
+
+		value, err := fn(old)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return old, nil
+		}
+
+		var expected any
+		if old != nil {
+			expected = old
+		}
+		err = k.AtomicCompareAndSet(ctx, key, expected, value)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, ErrCASMismatch) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("redis kv: Update %q: gave up after %d attempts: %w", key, updateAttempts, ErrCASMismatch)
+}
@@ -2,7 +2,6 @@ package redis
 
 import "time"
 
-// TODO: CAS/WATCH based flows
 // TODO: sentinel
 
 // RedisConfig contains configuration for constructing a rueidis.Client.
@@ -53,4 +52,8 @@ type RedisConfig struct {
 	// NOTE: this just configures CLIENT TRACKING ON with PREFIX/BCAST/OPTIN.
 	// You still opt-in per-command using DoCache() on the client.
 	ClientTrackingPrefixes []string `env:"CLIENT_TRACKING_PREFIXES" envSeparator:","`
+
+	// WatchdogMaxBackoff, when set, spaces out the watchdog pings of an outage up
+	// to this interval, see WithPingBackoff. Zero keeps pinging every second.
+	WatchdogMaxBackoff time.Duration `env:"WATCHDOG_MAX_BACKOFF"`
 }
@@ -42,29 +42,44 @@ var (
 	luaAtomicIncrWithTTL = rueidis.NewLuaScript(atomicIncrLua)
 )
 
-type RedisCounter struct {
-	client rueidis.Client
-	prefix string
-}
+type (
+	RedisCounter struct {
+		client rueidis.Client
+		prefix string
+
+		// hot key sharding, disabled when shards < 2
+		shards int
+		isHot  HotKeyFunc
+	}
+
+	// Option configures a RedisCounter.
+	Option func(*RedisCounter)
+)
 
 // NewRedisCounterStore wraps a rueidis.Client as a CounterStore.
 //
 // prefix is optional; if non-empty, keys become prefix + ":" + key.
-func NewRedisCounterStore(client rueidis.Client, prefix string) *RedisCounter {
+func NewRedisCounterStore(client rueidis.Client, prefix string, opts ...Option) *RedisCounter {
 	if prefix != "" && prefix[len(prefix)-1] != ':' {
 		prefix += ":"
 	}
-	return &RedisCounter{
+	r := &RedisCounter{
 		client: client,
 		prefix: prefix,
 	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
 }
 
 // Optionally add hooks (logging, OTEL) via rueidishook here.
-func NewInstrumentedRedisCounterStore(client rueidis.Client, prefix string) ratelimit.CounterStore {
+func NewInstrumentedRedisCounterStore(client rueidis.Client, prefix string, opts ...Option) ratelimit.CounterStore {
 	// hooked := rueidishook.WithHook(client, )
 	// return NewRedisCounterStore(hooked, prefix)
-	return NewRedisCounterStore(client, prefix)
+	return NewRedisCounterStore(client, prefix, opts...)
 }
 
 func (r *RedisCounter) buildKey(key string) string {
@@ -73,6 +88,10 @@ func (r *RedisCounter) buildKey(key string) string {
 
 // Get implements ratelimit.CounterStore.
 func (r *RedisCounter) Get(ctx context.Context, key string) (int64, error) {
+	if r.sharded(key) {
+		return r.sumShards(ctx, key, -1)
+	}
+
 	k := r.buildKey(key)
 	rr := r.client.Do(ctx, r.client.B().Get().Key(k).Build())
 	bs, err := rr.AsBytes()
@@ -95,6 +114,10 @@ func (r *RedisCounter) Get(ctx context.Context, key string) (int64, error) {
 
 // Incr implements ratelimit.CounterStore.
 func (r *RedisCounter) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if r.sharded(key) {
+		return r.incrShard(ctx, key, ttl)
+	}
+
 	ms := ttl.Milliseconds()
 	k := r.buildKey(key)
 
@@ -0,0 +1,107 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// HotKeyFunc reports whether a counter key should be sharded.
+type HotKeyFunc func(key string) bool
+
+// WithSharding spreads counters matched by isHot over n subkeys.
+//
+// A single very hot key (one client or route dominating traffic) pins all its
+// load on one Redis Cluster slot. With sharding, Incr increments a random shard
+// and returns the sum of all shards; Get sums the shards. Subkeys are
+// "{prefix}{key}:shard:{i}" and are deliberately not hash-tagged, so they land on
+// different slots.
+//
+// The trade-off is n round trips' worth of reads per Incr (sent as one pipelined
+// batch) and a total that is no longer atomic with the increment, so concurrent
+// requests may briefly observe slightly stale sums. A nil isHot shards every key.
+//
+// Changing n on a live deployment loses counts in the shards that are no longer read
+// until their TTL expires. Shards of one deployment are picked at random; spreading
+// counters over several Redis deployments instead needs a stable key-to-node mapping,
+// see modules/hashring.
+func WithSharding(n int, isHot HotKeyFunc) Option {
+	return func(r *RedisCounter) {
+		if n < 2 {
+			return
+		}
+		r.shards = n
+		r.isHot = isHot
+	}
+}
+
+func (r *RedisCounter) sharded(key string) bool {
+	return r.shards > 1 && (r.isHot == nil || r.isHot(key))
+}
+
+func (r *RedisCounter) shardKey(key string, i int) string {
+	return r.buildKey(key) + ":shard:" + strconv.Itoa(i)
+}
+
+func (r *RedisCounter) incrShard(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	shard := rand.IntN(r.shards)
+	k := r.shardKey(key, shard)
+
+	rr := luaAtomicIncrWithTTL.Exec(ctx, r.client, []string{k}, []string{strconv.FormatInt(ttl.Milliseconds(), 10)})
+	val, err := rr.AsInt64()
+	if err != nil {
+		return 0, fmt.Errorf("redis counter Incr shard: %w", err)
+	}
+
+	rest, err := r.sumShards(ctx, key, shard)
+	if err != nil {
+		return 0, err
+	}
+	return val + rest, nil
+}
+
+// sumShards adds up every shard of key except skip (-1 reads all).
+func (r *RedisCounter) sumShards(ctx context.Context, key string, skip int) (int64, error) {
+	cmds := make(rueidis.Commands, 0, r.shards)
+	for i := 0; i < r.shards; i++ {
+		if i == skip {
+			continue
+		}
+		cmds = append(cmds, r.client.B().Get().Key(r.shardKey(key, i)).Build())
+	}
+
+	var total int64
+	for _, res := range r.client.DoMulti(ctx, cmds...) {
+		bs, err := res.AsBytes()
+		if err != nil {
+			if rueidis.IsRedisNil(err) {
+				continue
+			}
+			return 0, fmt.Errorf("redis counter Get shard: %w", err)
+		}
+		n, err := strconv.ParseInt(string(bs), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("redis counter Get shard parse: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package counter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"app/modules/ratelimit"
+
+	"github.com/redis/rueidis"
+)
+
+var _ ratelimit.SnapshotStore = (*SnapshotStore)(nil)
+
+// SnapshotStore keeps a ratelimit.Snapshot in a single Redis string.
+//
+// The key should identify the node (e.g. include the hostname), so a node picks up
+// its own counters after a restart. The value expires with the last counter in it.
+type SnapshotStore struct {
+	client rueidis.Client
+	key    string
+}
+
+func NewSnapshotStore(client rueidis.Client, key string) *SnapshotStore {
+	return &SnapshotStore{client: client, key: key}
+}
+
+// SaveSnapshot implements ratelimit.SnapshotStore.
+func (s *SnapshotStore) SaveSnapshot(ctx context.Context, snap ratelimit.Snapshot) error {
+	var last time.Time
+	for _, c := range snap.Counters {
+		if c.ExpiresAt.After(last) {
+			last = c.ExpiresAt
+		}
+	}
+	ttl := last.Sub(snap.TakenAt)
+	if ttl <= 0 {
+		return s.client.Do(ctx, s.client.B().Del().Key(s.key).Build()).Error()
+	}
+
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("counter: encode snapshot: %w", err)
+	}
+	cmd := s.client.B().Set().Key(s.key).Value(rueidis.BinaryString(b)).Px(ttl).Build()
+	if err := s.client.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("counter: save snapshot %q: %w", s.key, err)
+	}
+	return nil
+}
+
+// LoadSnapshot implements ratelimit.SnapshotStore. GETDEL makes a snapshot load once.
+func (s *SnapshotStore) LoadSnapshot(ctx context.Context) (ratelimit.Snapshot, bool, error) {
+	b, err := s.client.Do(ctx, s.client.B().Getdel().Key(s.key).Build()).AsBytes()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return ratelimit.Snapshot{}, false, nil
+		}
+		return ratelimit.Snapshot{}, false, fmt.Errorf("counter: load snapshot %q: %w", s.key, err)
+	}
+
+	var snap ratelimit.Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return ratelimit.Snapshot{}, false, fmt.Errorf("counter: decode snapshot %q: %w", s.key, err)
+	}
+	return snap, true, nil
+}
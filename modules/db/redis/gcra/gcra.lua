@@ -0,0 +1,32 @@
+-- GCRA (generic cell rate algorithm) decision for one request.
+-- KEYS[1] = full key, holding the theoretical arrival time (TAT) in microseconds
+-- ARGV[1] = emission interval in microseconds (window / limit)
+-- ARGV[2] = delay variation tolerance in microseconds (emission interval * burst)
+--
+-- Returns {allowed, remaining, retry_after_us, reset_after_us}.
+-- The server clock is used so every node agrees on "now".
+
+local key = KEYS[1]
+local emission = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+    tat = now
+end
+
+local new_tat = tat + emission
+local allow_at = new_tat - tolerance
+
+if now < allow_at then
+    return {0, 0, allow_at - now, tat - now}
+end
+
+-- the key expires once the bucket is full again, an absent key means a full bucket
+local ttl_ms = math.ceil((new_tat - now) / 1000)
+redis.call("SET", key, string.format("%.0f", new_tat), "PX", ttl_ms)
+
+return {1, math.floor((now - allow_at) / emission), 0, new_tat - now}
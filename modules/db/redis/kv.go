@@ -30,7 +30,8 @@ import (
 )
 
 var (
-	_ db.KV = (*RedisKV)(nil)
+	_ db.BatchKV     = (*RedisKV)(nil)
+	_ db.VersionedKV = (*RedisKV)(nil)
 
 	//go:embed atomic_set.lua
 	atomicSetLua string
@@ -53,12 +54,29 @@ var (
 	//   - Single round-trip
 	//   - Atomic read-modify-write including TTL update
 	luaAtomicSet = rueidis.NewLuaScript(atomicSetLua)
+
+	//go:embed atomic_set_versioned.lua
+	atomicSetVersionedLua string
+
+	// Lua script for AtomicSetVersioned:
+	//
+	//   - KEYS[1]  = full key
+	//   - ARGV[1]  = serialized value
+	//   - ARGV[2]  = TTL in seconds (string; 0 or empty = no TTL)
+	//   - ARGV[3]  = version field of the stored JSON object
+	//   - ARGV[4]  = version of the value
+	//
+	// Returns 1 when the value was set, 0 when the stored one has a higher version.
+	luaAtomicSetVersioned = rueidis.NewLuaScript(atomicSetVersionedLua)
 )
 
 // RedisKV is a Rueidis-backed implementation of db.KV with:
 //   - Key prefixing (multi-tenant / env scoping)
 //   - AtomicSet via Lua (GET + SET + TTL in one script)
-//   - Optional server-assisted client-side caching for reads (AtomicGet)
+//   - Optional server-assisted client-side caching for reads (AtomicGet, MGet)
+//   - Pipelined batches (MGet, MSet, DeleteMany) implementing db.BatchKV
+//   - Compare-and-set via Lua (AtomicCompareAndSet, Update)
+//   - Versioned writes via Lua (AtomicSetVersioned)
 type RedisKV struct {
 	client rueidis.Client
 
@@ -70,6 +88,10 @@ type RedisKV struct {
 
 	// If true, AtomicGet will use DoCache with cache TTL = defaultTTL.
 	enableClientCache bool
+
+	// while degraded() is true calls skip redis and follow policy, see WithDegradedMode
+	degraded func() bool
+	policy   FailurePolicy
 }
 
 // RedisKVOption configures RedisKV.
@@ -103,6 +125,18 @@ func WithClientSideCache() RedisKVOption {
 	}
 }
 
+// WithDegradedMode short-circuits calls while degraded reports true (usually Watchdog.Degraded).
+//
+//   - FailOpen: reads are misses and writes no-ops, for pure caches; Update still
+//     fails, see AtomicCompareAndSet and Update
+//   - FailClosed: every call returns ErrDegraded without waiting on redis timeouts
+func WithDegradedMode(degraded func() bool, policy FailurePolicy) RedisKVOption {
+	return func(k *RedisKV) {
+		k.degraded = degraded
+		k.policy = policy
+	}
+}
+
 // NewRedisKV constructs a RedisKV on top of an existing rueidis.Client.
 //
 // The same client can be shared across multiple RedisKV instances (different prefixes).
@@ -132,6 +166,12 @@ func (k *RedisKV) key(raw string) string {
 //   - Returns (nil, nil) if the key does not exist
 //   - Optionally uses server-assisted client-side caching if enabled
 func (k *RedisKV) AtomicGet(ctx context.Context, key string) (any, error) {
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis kv: AtomicGet %q: %w", key, ErrDegraded)
+	}
 	fullKey := k.key(key)
 
 	var res rueidis.RedisResult
@@ -144,7 +184,7 @@ func (k *RedisKV) AtomicGet(ctx context.Context, key string) (any, error) {
 
 	bs, err := res.AsBytes()
 	if err != nil {
-		if re, ok := rueidis.IsRedisErr(err); ok && re.IsNil() {
+		if rueidis.IsRedisNil(err) {
 			// Key missing – treat as nil value.
 			return nil, nil
 		}
@@ -162,6 +202,12 @@ func (k *RedisKV) AtomicGet(ctx context.Context, key string) (any, error) {
 //   - SET new value (with EX TTL if configured)
 //   - Returns the previous value as []byte or nil if none
 func (r *RedisKV) AtomicSet(ctx context.Context, key string, value any) (any, error) {
+	if r.isDegraded() {
+		if r.policy == FailOpen {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("redis kv: AtomicSet %q: %w", key, ErrDegraded)
+	}
 	fullKey := r.key(key)
 
 	serialized, err := encodeValue(value)
@@ -170,11 +216,7 @@ func (r *RedisKV) AtomicSet(ctx context.Context, key string, value any) (any, er
 	}
 
 	ttlArg := ""
-	if r.defaultTTL > 0 {
-		ttl := int64(r.defaultTTL / time.Second)
-		if ttl <= 0 {
-			ttl = 1
-		}
+	if ttl := r.ttlSeconds(); ttl > 0 {
 		ttlArg = strconv.FormatInt(ttl, 10)
 	}
 
@@ -182,7 +224,7 @@ func (r *RedisKV) AtomicSet(ctx context.Context, key string, value any) (any, er
 	res := luaAtomicSet.Exec(ctx, r.client, []string{fullKey}, []string{serialized, ttlArg})
 	bs, err := res.AsBytes()
 	if err != nil {
-		if re, ok := rueidis.IsRedisErr(err); ok && re.IsNil() {
+		if rueidis.IsRedisNil(err) {
 			// No previous value.
 			return nil, nil
 		}
@@ -193,6 +235,172 @@ func (r *RedisKV) AtomicSet(ctx context.Context, key string, value any) (any, er
 	return bs, nil
 }
 
+// MGet implements db.BatchKV.MGet.
+//
+// Every key is a GET of its own, pipelined with DoMulti, so keys may map to
+// different cluster slots. Reads use client-side caching like AtomicGet.
+func (k *RedisKV) MGet(ctx context.Context, keys []string) ([]any, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	out := make([]any, len(keys))
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			return out, nil
+		}
+		return nil, fmt.Errorf("redis kv: MGet: %w", ErrDegraded)
+	}
+
+	var results []rueidis.RedisResult
+	if k.enableClientCache && k.defaultTTL > 0 {
+		cmds := make([]rueidis.CacheableTTL, len(keys))
+		for i, key := range keys {
+			cmds[i] = rueidis.CT(k.client.B().Get().Key(k.key(key)).Cache(), k.defaultTTL)
+		}
+		results = k.client.DoMultiCache(ctx, cmds...)
+	} else {
+		cmds := make(rueidis.Commands, len(keys))
+		for i, key := range keys {
+			cmds[i] = k.client.B().Get().Key(k.key(key)).Build()
+		}
+		results = k.client.DoMulti(ctx, cmds...)
+	}
+
+	for i, res := range results {
+		bs, err := res.AsBytes()
+		if err != nil {
+			if rueidis.IsRedisNil(err) {
+				continue
+			}
+			return nil, fmt.Errorf("redis kv: MGet %q failed: %w", keys[i], err)
+		}
+		out[i] = bs
+	}
+	return out, nil
+}
+
+// MSet implements db.BatchKV.MSet.
+//
+// Values are serialized like AtomicSet and written with the default TTL, one SET
+// per key pipelined with DoMulti. Unlike AtomicSet it does not return the
+// previous values. The keys that failed are joined in the error.
+func (k *RedisKV) MSet(ctx context.Context, entries map[string]any) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			return nil
+		}
+		return fmt.Errorf("redis kv: MSet: %w", ErrDegraded)
+	}
+
+	ttl := k.ttlSeconds()
+	keys := make([]string, 0, len(entries))
+	cmds := make(rueidis.Commands, 0, len(entries))
+	for key, value := range entries {
+		serialized, err := encodeValue(value)
+		if err != nil {
+			return fmt.Errorf("redis kv: encode value for key %q: %w", key, err)
+		}
+		set := k.client.B().Set().Key(k.key(key)).Value(serialized)
+		if ttl > 0 {
+			cmds = append(cmds, set.ExSeconds(ttl).Build())
+		} else {
+			cmds = append(cmds, set.Build())
+		}
+		keys = append(keys, key)
+	}
+
+	var errs []error
+	for i, res := range k.client.DoMulti(ctx, cmds...) {
+		if err := res.Error(); err != nil {
+			errs = append(errs, fmt.Errorf("redis kv: MSet %q failed: %w", keys[i], err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DeleteMany implements db.BatchKV.DeleteMany.
+//
+// Every key is a DEL of its own, pipelined with DoMulti, so keys may map to
+// different cluster slots. It returns how many keys existed, along with the
+// joined errors of the keys that failed.
+func (k *RedisKV) DeleteMany(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("redis kv: DeleteMany: %w", ErrDegraded)
+	}
+
+	cmds := make(rueidis.Commands, len(keys))
+	for i, key := range keys {
+		cmds[i] = k.client.B().Del().Key(k.key(key)).Build()
+	}
+
+	var (
+		deleted int64
+		errs    []error
+	)
+	for i, res := range k.client.DoMulti(ctx, cmds...) {
+		n, err := res.AsInt64()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("redis kv: DeleteMany %q failed: %w", keys[i], err))
+			continue
+		}
+		deleted += n
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// ttlSeconds is the default TTL in whole seconds, at least 1, or 0 for no TTL.
+func (k *RedisKV) ttlSeconds() int64 {
+	if k.defaultTTL <= 0 {
+		return 0
+	}
+	return max(int64(k.defaultTTL/time.Second), 1)
+}
+
+// AtomicSetVersioned implements db.VersionedKV.AtomicSetVersioned.
+//
+// The stored value is decoded with cjson in a Lua script; values that are not JSON
+// objects, or lack the field, are overwritten. Values are serialized like
+// AtomicSet and the default TTL applies. While degraded with FailOpen it is a
+// no-op reporting false.
+func (k *RedisKV) AtomicSetVersioned(ctx context.Context, key, versionField string, version int64, value any) (bool, error) {
+	if k.isDegraded() {
+		if k.policy == FailOpen {
+			return false, nil
+		}
+		return false, fmt.Errorf("redis kv: AtomicSetVersioned %q: %w", key, ErrDegraded)
+	}
+
+	serialized, err := encodeValue(value)
+	if err != nil {
+		return false, fmt.Errorf("redis kv: encode value for key %q: %w", key, err)
+	}
+	ttlArg := ""
+	if ttl := k.ttlSeconds(); ttl > 0 {
+		ttlArg = strconv.FormatInt(ttl, 10)
+	}
+
+	res := luaAtomicSetVersioned.Exec(ctx, k.client, []string{k.key(key)},
+		[]string{serialized, ttlArg, versionField, strconv.FormatInt(version, 10)})
+	written, err := res.AsInt64()
+	if err != nil {
+		return false, fmt.Errorf("redis kv: AtomicSetVersioned %q failed: %w", key, err)
+	}
+	return written == 1, nil
+}
+
+func (k *RedisKV) isDegraded() bool {
+	return k.degraded != nil && k.degraded()
+}
+
 // HealthCheck is a small helper to be used by readiness/liveness probes.
 func (k *RedisKV) HealthCheck(ctx context.Context) error {
 	return k.client.Do(ctx, k.client.B().Ping().Build()).Error()
@@ -0,0 +1,148 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/redis/rueidis"
+)
+
+// DefaultLocalCacheEntries bounds each connection's client-side cache when not configured.
+const DefaultLocalCacheEntries = 10_000
+
+// LocalCache is a client-side cache store for rueidis that can be flushed on demand.
+//
+// The rueidis default store is only flushed through server invalidations; installing
+// a LocalCache (see WithLocalCache) lets an operator drop every locally cached reply
+// after fixing data out of band. Each connection gets its own LRU bounded by entry
+// count, so CacheSizeEachConn does not apply.
+type LocalCache struct {
+	maxEntries int
+
+	mu     sync.Mutex
+	stores map[*lruEntries]struct{}
+}
+
+// NewLocalCache constructs a LocalCache keeping up to maxEntries replies per
+// connection, DefaultLocalCacheEntries when maxEntries <= 0.
+func NewLocalCache(maxEntries int) *LocalCache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultLocalCacheEntries
+	}
+	return &LocalCache{maxEntries: maxEntries, stores: make(map[*lruEntries]struct{})}
+}
+
+// NewStore is a rueidis.NewCacheStoreFn; rueidis calls it once per connection.
+func (c *LocalCache) NewStore(rueidis.CacheStoreOption) rueidis.CacheStore {
+	entries := newLRUEntries(c.maxEntries)
+	c.mu.Lock()
+	c.stores[entries] = struct{}{}
+	c.mu.Unlock()
+	return &localStore{CacheStore: rueidis.NewSimpleCacheAdapter(entries), cache: c, entries: entries}
+}
+
+// Flush drops the cached replies of every connection and returns how many were dropped.
+// Requests in flight still complete and cache their (fresh) reply.
+func (c *LocalCache) Flush() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for entries := range c.stores {
+		n += entries.flush()
+	}
+	return n
+}
+
+// localStore forgets its entries when rueidis closes the connection.
+type localStore struct {
+	rueidis.CacheStore
+	cache   *LocalCache
+	entries *lruEntries
+}
+
+func (s *localStore) Close(err error) {
+	s.CacheStore.Close(err)
+	s.cache.mu.Lock()
+	delete(s.cache.stores, s.entries)
+	s.cache.mu.Unlock()
+}
+
+// lruEntries is a rueidis.SimpleCache evicting the least recently used reply.
+type lruEntries struct {
+	mu    sync.Mutex
+	max   int
+	order *list.List // front is most recently used
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key string
+	val rueidis.RedisMessage
+}
+
+var _ rueidis.SimpleCache = (*lruEntries)(nil)
+
+func newLRUEntries(max int) *lruEntries {
+	return &lruEntries{max: max, order: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (l *lruEntries) Get(key string) rueidis.RedisMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	el, ok := l.items[key]
+	if !ok {
+		return rueidis.RedisMessage{}
+	}
+	l.order.MoveToFront(el)
+	return el.Value.(*lruItem).val
+}
+
+func (l *lruEntries) Set(key string, val rueidis.RedisMessage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		el.Value.(*lruItem).val = val
+		l.order.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruItem{key: key, val: val})
+	for l.order.Len() > l.max {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (l *lruEntries) Del(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		l.order.Remove(el)
+		delete(l.items, key)
+	}
+}
+
+func (l *lruEntries) Flush() { l.flush() }
+
+func (l *lruEntries) flush() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := len(l.items)
+	l.order.Init()
+	clear(l.items)
+	return n
+}
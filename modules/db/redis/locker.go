@@ -0,0 +1,47 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"fmt"
+
+	"github.com/redis/rueidis"
+	"github.com/redis/rueidis/rueidislock"
+)
+
+// NewRueidisLocker creates a rueidislock.Locker on its own connection to the Redis
+// described by opt. Locks rely on client-side caching invalidations, so the locker
+// does not share the application client.
+//
+// keyPrefix namespaces the lock keys; empty keeps the rueidislock default.
+func NewRueidisLocker(opt RedisConfig, keyPrefix string) (rueidislock.Locker, error) {
+	clientOpt, err := rueidis.ParseURL(opt.URL)
+	if err != nil {
+		return nil, fmt.Errorf("rueidislock: parse url: %w", err)
+	}
+	clientOpt.ClientName = opt.ClientName
+
+	locker, err := rueidislock.NewLocker(rueidislock.LockerOption{
+		ClientOption: clientOpt,
+		KeyPrefix:    keyPrefix,
+		// one key per lock, as for a single Redis instance;
+		// the default of 2 (out of 3 keys) targets independent nodes
+		KeyMajority: 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rueidislock: new locker: %w", err)
+	}
+	return locker, nil
+}
@@ -0,0 +1,32 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+// Backend selects the Locker implementation.
+type Backend string
+
+const (
+	BackendRedis    Backend = "redis"
+	BackendPostgres Backend = "postgres"
+)
+
+// Config selects and configures the lock backend of background jobs.
+type Config struct {
+	Backend Backend `env:"BACKEND" envDefault:"redis"`
+	// History selects where job runs are recorded: "postgres", "redis" or empty for nowhere.
+	History Backend `env:"HISTORY"`
+	// Fair queues executors waiting for a busy lock in arrival order (redis backend only).
+	Fair bool `env:"FAIR"`
+}
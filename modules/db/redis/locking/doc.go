@@ -86,3 +86,73 @@ package locking
 // 			}
 // 		}
 // 	}
+
+// Scheduled jobs:
+//
+// Instead of the ticker loop above, register the job with a Scheduler; it owns the
+// goroutines, skips ticks where another node holds the lock and drains on Stop:
+//
+// 	sched := locking.NewScheduler(exec)
+// 	if err := sched.Add(locking.Job{
+// 		Lock:     jobCfg,
+// 		Schedule: locking.MustParseCron("*/5 * * * *"), // or locking.Every(5*time.Minute)
+// 		Task:     job,
+// 	}); err != nil {
+// 		log.Fatal(err)
+// 	}
+// 	_ = sched.Start(ctx)
+// 	defer sched.Stop(context.Background())
+//
+// 	sched.Pause("profile-cleanup")  // e.g. from an admin endpoint
+// 	sched.Resume("profile-cleanup")
+
+// Concurrent executors:
+//
+// A job that may run on several nodes at once, up to a cap, takes a permit from a
+// Semaphore instead of the exclusive lock:
+//
+// 	sem := locking.NewSemaphore(redisClient, locking.WithSemaphoreKeyPrefix("dev:permits"))
+// 	exec := locking.NewLockingTaskExecutor(locker,
+// 		locking.WithSemaphore(sem),
+// 		locking.WithMaxConcurrency(3), // three nodes can run reindex at once
+// 	)
+//
+// Permits are leases renewed while the task runs; a node that dies hands its permit
+// back once the lease expires (see WithLease).
+
+// Fair waiting:
+//
+// With WithWaitForLock(true) every blocked executor races for the lock the moment
+// it is released, so a slow node can lose every round. Wrapping the locker in a
+// FairLocker queues the waiters in Redis and hands the lock over in arrival order:
+//
+// 	exec := locking.NewLockingTaskExecutor(
+// 		locking.NewFairLocker(locker, redisClient, locking.WithFairKeyPrefix("dev:locks:queue")),
+// 		locking.WithWaitForLock(true),
+// 	)
+//
+// Executors in "try once" mode do not queue; they are turned away while waiters
+// are queued. Set LOCK_FAIR=true to enable it in the service.
+
+// Concurrency groups:
+//
+// Different jobs that compete for the same resource can be capped together. Jobs
+// tagged with a Group take one of the group's permits after winning their own lock:
+//
+// 	sched := locking.NewScheduler(exec, locking.WithConcurrencyGroups(sem,
+// 		locking.ConcurrencyGroup{Name: "maintenance", Limit: 2},
+// 	))
+// 	_ = sched.Add(locking.Job{Lock: purgeCfg, Schedule: hourly, Task: purge, Group: "maintenance"})
+//
+// A job waiting for a permit keeps its lock, so each period still runs at most once.
+
+// Testing:
+//
+// Package lockingtest runs several executors as simulated nodes against one
+// miniredis and drives their LockAtLeastFor / LockAtMostFor timers from a
+// clock.Fake (see WithTimers), so the guarantees above can be asserted without
+// sleeping:
+//
+// 	c := lockingtest.NewCluster(t, 5)
+// 	probe := &lockingtest.Probe{}
+// 	errs := c.Run(ctx, jobCfg, probe.Track(task))
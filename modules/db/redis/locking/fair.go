@@ -0,0 +1,253 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/redis/rueidis"
+)
+
+var (
+	_ Locker = (*FairLocker)(nil)
+
+	//go:embed fair_join.lua
+	fairJoinLua string
+	//go:embed fair_leave.lua
+	fairLeaveLua string
+	//go:embed fair_queued.lua
+	fairQueuedLua string
+
+	// Lua scripts for the waiter queue
+	// - KEYS[1] = queue list, KEYS[2] = leases zset
+	// - join:   ARGV[1] = ticket, ARGV[2] = lease ms, ARGV[3] = wake key prefix
+	// - leave:  ARGV[1] = ticket, ARGV[2] = lease ms, ARGV[3] = wake key prefix
+	// - queued: no arguments
+	luaFairJoin   = rueidis.NewLuaScript(fairJoinLua)
+	luaFairLeave  = rueidis.NewLuaScript(fairLeaveLua)
+	luaFairQueued = rueidis.NewLuaScript(fairQueuedLua)
+)
+
+type (
+	// FairLocker makes the blocking acquisitions of a Locker succeed roughly in
+	// arrival order, so a node that keeps losing the race for a busy lock is not
+	// starved by faster ones.
+	//
+	// Blocked callers join a Redis queue per lock name and only the head of the
+	// queue contends for the underlying lock. Once it holds the lock, it leaves the
+	// queue and hands the turn to the next waiter through its wake list. Try
+	// acquisitions fail with ErrLockNotAcquired while anyone is queued instead of
+	// barging ahead.
+	//
+	// Keys used (hash-tagged on the lock name so the scripts work on Redis Cluster):
+	//   - {prefix}{name}:queue   LIST of waiter tickets in arrival order
+	//   - {prefix}{name}:leases  ZSET of tickets scored by lease expiry
+	//   - {prefix}{name}:wake:*  LIST a ticket blocks on until it reaches the head
+	//
+	// Waiters renew their lease every third of it. The ticket of a node that dies
+	// is dropped once its lease expires, and the next waiter notices at its next
+	// renewal at the latest.
+	FairLocker struct {
+		locker Locker
+		client rueidis.Client
+		prefix string
+		node   string
+		lease  time.Duration
+	}
+
+	// FairLockerOption configures a FairLocker.
+	FairLockerOption func(*FairLocker)
+)
+
+// WithFairKeyPrefix scopes the waiter queue keys under a prefix (env, service, etc).
+func WithFairKeyPrefix(prefix string) FairLockerOption {
+	return func(f *FairLocker) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, ":") {
+			prefix += ":"
+		}
+		f.prefix = prefix
+	}
+}
+
+// WithWaiterLease sets how long a queued waiter keeps its place without renewal.
+// Defaults to 15 seconds.
+func WithWaiterLease(d time.Duration) FairLockerOption {
+	return func(f *FairLocker) {
+		if d > 0 {
+			f.lease = d
+		}
+	}
+}
+
+// NewFairLocker queues the blocking acquisitions of locker on client.
+func NewFairLocker(locker Locker, client rueidis.Client, opts ...FairLockerOption) *FairLocker {
+	f := &FairLocker{
+		locker: locker,
+		client: client,
+		node:   defaultNodeID(),
+		lease:  15 * time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	return f
+}
+
+func (f *FairLocker) keys(name string) []string {
+	base := f.prefix + "{" + name + "}:"
+	return []string{base + "queue", base + "leases"}
+}
+
+func (f *FairLocker) wakePrefix(name string) string {
+	return f.prefix + "{" + name + "}:wake:"
+}
+
+// Queued returns how many live waiters are queued for name.
+func (f *FairLocker) Queued(ctx context.Context, name string) (int, error) {
+	n, err := luaFairQueued.Exec(ctx, f.client, f.keys(name), nil).AsInt64()
+	if err != nil {
+		return 0, fmt.Errorf("locking: queued waiters of %q: %w", name, err)
+	}
+	return int(n), nil
+}
+
+// TryWithContext acquires the lock once, unless other callers are queued for it.
+func (f *FairLocker) TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	n, err := f.Queued(ctx, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if n > 0 {
+		return nil, nil, ErrLockNotAcquired
+	}
+	return f.locker.TryWithContext(ctx, name)
+}
+
+// WithContext queues for the lock and blocks until it is acquired or ctx is done.
+func (f *FairLocker) WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, nil, fmt.Errorf("locking: generate waiter ticket: %w", err)
+	}
+	w := &waiter{f: f, name: name, ticket: f.node + ":" + id.String()}
+
+	head, err := w.join(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	stop := w.keepalive(ctx)
+	defer func() {
+		stop()
+		// the ticket leaves on success and failure alike, handing the turn over
+		leaveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+		// best effort: a ticket left behind expires with its lease
+		_ = w.leave(leaveCtx)
+	}()
+
+	for !head {
+		if err := w.wait(ctx); err != nil {
+			return nil, nil, err
+		}
+		if head, err = w.join(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	return f.locker.WithContext(ctx, name)
+}
+
+// Close closes the underlying locker.
+func (f *FairLocker) Close() {
+	f.locker.Close()
+}
+
+// waiter is one queued WithContext call.
+type waiter struct {
+	f      *FairLocker
+	name   string
+	ticket string
+}
+
+func (w *waiter) args() []string {
+	return []string{w.ticket, strconv.FormatInt(w.f.lease.Milliseconds(), 10), w.f.wakePrefix(w.name)}
+}
+
+// join enqueues the ticket or renews its lease, reporting whether it is the head.
+func (w *waiter) join(ctx context.Context) (bool, error) {
+	head, err := luaFairJoin.Exec(ctx, w.f.client, w.f.keys(w.name), w.args()).AsInt64()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, fmt.Errorf("locking: queue for %q: %w", w.name, err)
+	}
+	return head == 1, nil
+}
+
+func (w *waiter) leave(ctx context.Context) error {
+	return luaFairLeave.Exec(ctx, w.f.client, w.f.keys(w.name), w.args()).Error()
+}
+
+// wait blocks until the ticket is woken up or a renewal is due.
+func (w *waiter) wait(ctx context.Context) error {
+	wake := w.f.wakePrefix(w.name) + w.ticket
+	cmd := w.f.client.B().Blpop().Key(wake).Timeout((w.f.lease / 3).Seconds()).Build()
+	err := w.f.client.Do(ctx, cmd).Error()
+	switch {
+	case err == nil, rueidis.IsRedisNil(err):
+		return nil
+	case ctx.Err() != nil:
+		return ctx.Err()
+	default:
+		return fmt.Errorf("locking: wait in queue for %q: %w", w.name, err)
+	}
+}
+
+// keepalive renews the lease of the ticket until the returned func is called,
+// which covers the time the head blocks on the underlying lock.
+func (w *waiter) keepalive(ctx context.Context) func() {
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(w.f.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			// a failed renewal is retried on the next tick, the place is only lost
+			// once the lease ran out
+			_, _ = w.join(ctx)
+		}
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
@@ -0,0 +1,55 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Join the waiter queue of a lock, or renew the lease of a waiter already in it.
+-- KEYS[1] = queue list, tickets in arrival order
+-- KEYS[2] = leases zset, tickets scored by lease expiry (unix ms, redis time)
+-- ARGV[1] = ticket
+-- ARGV[2] = lease in milliseconds
+-- ARGV[3] = wake key prefix, a ticket's wake list is ARGV[3] .. ticket
+--
+-- Returns 1 when the ticket is at the head of the queue, 0 otherwise.
+
+local queue, leases = KEYS[1], KEYS[2]
+local ticket = ARGV[1]
+local lease_ms = tonumber(ARGV[2])
+local wake_prefix = ARGV[3]
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+-- waiters that stopped renewing lose their place
+local head = redis.call("LINDEX", queue, 0)
+for _, dead in ipairs(redis.call("ZRANGEBYSCORE", leases, "-inf", now)) do
+  redis.call("LREM", queue, 0, dead)
+end
+redis.call("ZREMRANGEBYSCORE", leases, "-inf", now)
+
+if not redis.call("ZSCORE", leases, ticket) then
+  redis.call("RPUSH", queue, ticket)
+end
+redis.call("ZADD", leases, now + lease_ms, ticket)
+redis.call("PEXPIRE", queue, 2 * lease_ms)
+redis.call("PEXPIRE", leases, 2 * lease_ms)
+
+-- a dead head hands its turn over like a leaving one
+local successor = redis.call("LINDEX", queue, 0)
+if successor ~= head and successor ~= ticket then
+  redis.call("RPUSH", wake_prefix .. successor, "1")
+  redis.call("PEXPIRE", wake_prefix .. successor, lease_ms)
+end
+if successor == ticket then
+  return 1
+end
+return 0
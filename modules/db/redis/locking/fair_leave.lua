@@ -0,0 +1,42 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Leave the waiter queue of a lock, handing the turn to the next waiter.
+-- KEYS[1] = queue list, KEYS[2] = leases zset, see fair_join.lua
+-- ARGV[1] = ticket
+-- ARGV[2] = lease in milliseconds
+-- ARGV[3] = wake key prefix
+--
+-- Returns 1 when a waiter was woken up.
+
+local queue, leases = KEYS[1], KEYS[2]
+local ticket = ARGV[1]
+local lease_ms = tonumber(ARGV[2])
+local wake_prefix = ARGV[3]
+
+local was_head = redis.call("LINDEX", queue, 0) == ticket
+redis.call("LREM", queue, 0, ticket)
+redis.call("ZREM", leases, ticket)
+redis.call("DEL", wake_prefix .. ticket)
+if not was_head then
+  return 0
+end
+
+local successor = redis.call("LINDEX", queue, 0)
+if not successor then
+  return 0
+end
+redis.call("RPUSH", wake_prefix .. successor, "1")
+redis.call("PEXPIRE", wake_prefix .. successor, lease_ms)
+return 1
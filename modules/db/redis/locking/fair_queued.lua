@@ -0,0 +1,29 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Count the live waiters of a lock.
+-- KEYS[1] = queue list, KEYS[2] = leases zset, see fair_join.lua
+--
+-- Waiters whose lease expired are dropped first.
+
+local queue, leases = KEYS[1], KEYS[2]
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+for _, dead in ipairs(redis.call("ZRANGEBYSCORE", leases, "-inf", now)) do
+  redis.call("LREM", queue, 0, dead)
+end
+redis.call("ZREMRANGEBYSCORE", leases, "-inf", now)
+return redis.call("LLEN", queue)
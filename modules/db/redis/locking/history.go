@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// ExecutionStatus is the outcome of a job run.
+type ExecutionStatus string
+
+const (
+	ExecutionSucceeded ExecutionStatus = "succeeded"
+	ExecutionFailed    ExecutionStatus = "failed"
+	// ExecutionTimedOut marks runs cut off by LockAtMostFor.
+	ExecutionTimedOut ExecutionStatus = "timed_out"
+)
+
+// recordTimeout bounds how long a finished run waits for its record to be written.
+const recordTimeout = 5 * time.Second
+
+type (
+	// ExecutionRecord describes one run of a task under its lock.
+	ExecutionRecord struct {
+		Name       string          `json:"name"`
+		Node       string          `json:"node"`
+		StartedAt  time.Time       `json:"started_at"`
+		FinishedAt time.Time       `json:"finished_at"`
+		Status     ExecutionStatus `json:"status"`
+		Error      string          `json:"error,omitempty"`
+	}
+
+	// ExecutionRecorder persists the runs of an executor, see WithExecutionRecorder.
+	// Only runs that acquired the lock are recorded.
+	ExecutionRecorder interface {
+		RecordExecution(ctx context.Context, rec ExecutionRecord) error
+	}
+
+	// ExecutionHistory queries recorded runs, for dashboards and staleness alerts.
+	ExecutionHistory interface {
+		// LastSuccess returns the latest succeeded run of name, if any.
+		LastSuccess(ctx context.Context, name string) (ExecutionRecord, bool, error)
+		// Recent returns up to limit runs of name, newest first.
+		Recent(ctx context.Context, name string, limit int) ([]ExecutionRecord, error)
+	}
+)
+
+// WithExecutionRecorder records every run through r. Failing to record a run is
+// logged and never fails the run itself.
+func WithExecutionRecorder(r ExecutionRecorder) Option {
+	return func(e *LockingTaskExecutor) {
+		e.recorder = r
+	}
+}
+
+// WithNodeID sets the node recorded with each run. Defaults to the hostname.
+func WithNodeID(node string) Option {
+	return func(e *LockingTaskExecutor) {
+		if node != "" {
+			e.node = node
+		}
+	}
+}
+
+func defaultNodeID() string {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		return "unknown"
+	}
+	return host
+}
+
+func (e *LockingTaskExecutor) record(ctx context.Context, name string, start, end time.Time, err error) {
+	if e.recorder == nil {
+		return
+	}
+	rec := ExecutionRecord{
+		Name:       name,
+		Node:       e.node,
+		StartedAt:  start.UTC(),
+		FinishedAt: end.UTC(),
+		Status:     ExecutionSucceeded,
+	}
+	if err != nil {
+		rec.Status = ExecutionFailed
+		if errors.Is(err, context.DeadlineExceeded) {
+			rec.Status = ExecutionTimedOut
+		}
+		rec.Error = err.Error()
+	}
+
+	// a cancelled caller should still leave a trace of the run
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordTimeout)
+	defer cancel()
+	if rerr := e.recorder.RecordExecution(ctx, rec); rerr != nil && e.logger != nil {
+		e.logger.Warn("locking: failed to record execution",
+			slog.String("lock.name", name),
+			slog.Any("error", rerr),
+		)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/bob/dialect/psql/dialect"
+	"github.com/stephenafamo/bob/dialect/psql/im"
+	"github.com/stephenafamo/bob/dialect/psql/sm"
+	"github.com/stephenafamo/scan"
+)
+
+// DefaultExecutionTable is the table created by the job_executions migration.
+const DefaultExecutionTable = "job_executions"
+
+var (
+	_ ExecutionRecorder = (*PostgresExecutionStore)(nil)
+	_ ExecutionHistory  = (*PostgresExecutionStore)(nil)
+)
+
+// PostgresExecutionStore keeps one row per run. Writes go to the primary,
+// history queries to a replica.
+type PostgresExecutionStore struct {
+	pool  db.ConnectionManager
+	table string
+}
+
+type executionRow struct {
+	Name       string         `db:"name"`
+	Node       string         `db:"node"`
+	StartedAt  time.Time      `db:"started_at"`
+	FinishedAt time.Time      `db:"finished_at"`
+	Status     string         `db:"status"`
+	Error      sql.NullString `db:"error"`
+}
+
+// NewPostgresExecutionStore constructs a store over table, DefaultExecutionTable when empty.
+func NewPostgresExecutionStore(pool db.ConnectionManager, table string) *PostgresExecutionStore {
+	if table == "" {
+		table = DefaultExecutionTable
+	}
+	return &PostgresExecutionStore{pool: pool, table: table}
+}
+
+// RecordExecution implements ExecutionRecorder.
+func (s *PostgresExecutionStore) RecordExecution(ctx context.Context, rec ExecutionRecord) error {
+	errText := sql.NullString{String: rec.Error, Valid: rec.Error != ""}
+	q := psql.Insert(
+		im.Into(s.table, "name", "node", "started_at", "finished_at", "status", "error"),
+		im.Values(psql.Arg(rec.Name, rec.Node, rec.StartedAt, rec.FinishedAt, string(rec.Status), errText)),
+	)
+	if _, err := q.Exec(ctx, s.pool.Writer()); err != nil {
+		return fmt.Errorf("locking: record execution of %q: %w", rec.Name, err)
+	}
+	return nil
+}
+
+// LastSuccess implements ExecutionHistory.
+func (s *PostgresExecutionStore) LastSuccess(ctx context.Context, name string) (ExecutionRecord, bool, error) {
+	rows, err := s.query(ctx, name, 1, sm.Where(psql.Quote("status").EQ(psql.Arg(string(ExecutionSucceeded)))))
+	if err != nil || len(rows) == 0 {
+		return ExecutionRecord{}, false, err
+	}
+	return rows[0], true, nil
+}
+
+// Recent implements ExecutionHistory.
+func (s *PostgresExecutionStore) Recent(ctx context.Context, name string, limit int) ([]ExecutionRecord, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	return s.query(ctx, name, limit)
+}
+
+func (s *PostgresExecutionStore) query(ctx context.Context, name string, limit int, mods ...bob.Mod[*dialect.SelectQuery]) ([]ExecutionRecord, error) {
+	q := psql.Select(
+		sm.Columns("name", "node", "started_at", "finished_at", "status", "error"),
+		sm.From(s.table),
+		sm.Where(psql.Quote("name").EQ(psql.Arg(name))),
+		sm.OrderBy(psql.Quote("started_at")).Desc(),
+		sm.Limit(limit),
+	)
+	q.Apply(mods...)
+
+	rows, err := bob.All(ctx, s.pool.Reader(ctx), q, scan.StructMapper[executionRow]())
+	if err != nil {
+		return nil, fmt.Errorf("locking: query executions of %q: %w", name, err)
+	}
+	out := make([]ExecutionRecord, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, ExecutionRecord{
+			Name:       r.Name,
+			Node:       r.Node,
+			StartedAt:  r.StartedAt,
+			FinishedAt: r.FinishedAt,
+			Status:     ExecutionStatus(r.Status),
+			Error:      r.Error.String,
+		})
+	}
+	return out, nil
+}
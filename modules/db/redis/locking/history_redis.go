@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/rueidis"
+)
+
+// DefaultExecutionsKept is how many runs per job RedisExecutionStore keeps.
+const DefaultExecutionsKept = 100
+
+var (
+	_ ExecutionRecorder = (*RedisExecutionStore)(nil)
+	_ ExecutionHistory  = (*RedisExecutionStore)(nil)
+)
+
+// RedisExecutionStore keeps the latest runs of each job in a capped list.
+//
+// Keys used (per job, hash-tagged on the name):
+//   - {prefix}{name}:runs          LIST of encoded records, newest first
+//   - {prefix}{name}:last_success  STRING encoded record of the latest succeeded run
+//
+// The last success is kept apart so it survives a streak of failures longer than the list.
+type RedisExecutionStore struct {
+	client rueidis.Client
+	prefix string
+	keep   int64
+}
+
+// NewRedisExecutionStore constructs a store keeping up to keep runs per job,
+// DefaultExecutionsKept when keep <= 0.
+func NewRedisExecutionStore(client rueidis.Client, prefix string, keep int) *RedisExecutionStore {
+	if keep <= 0 {
+		keep = DefaultExecutionsKept
+	}
+	return &RedisExecutionStore{client: client, prefix: prefix, keep: int64(keep)}
+}
+
+func (s *RedisExecutionStore) key(name, suffix string) string {
+	return s.prefix + "{" + name + "}:" + suffix
+}
+
+// RecordExecution implements ExecutionRecorder.
+func (s *RedisExecutionStore) RecordExecution(ctx context.Context, rec ExecutionRecord) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("locking: encode execution of %q: %w", rec.Name, err)
+	}
+	value := rueidis.BinaryString(b)
+	runs := s.key(rec.Name, "runs")
+
+	cmds := rueidis.Commands{
+		s.client.B().Lpush().Key(runs).Element(value).Build(),
+		s.client.B().Ltrim().Key(runs).Start(0).Stop(s.keep - 1).Build(),
+	}
+	if rec.Status == ExecutionSucceeded {
+		cmds = append(cmds, s.client.B().Set().Key(s.key(rec.Name, "last_success")).Value(value).Build())
+	}
+	for _, resp := range s.client.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return fmt.Errorf("locking: record execution of %q: %w", rec.Name, err)
+		}
+	}
+	return nil
+}
+
+// LastSuccess implements ExecutionHistory.
+func (s *RedisExecutionStore) LastSuccess(ctx context.Context, name string) (ExecutionRecord, bool, error) {
+	b, err := s.client.Do(ctx, s.client.B().Get().Key(s.key(name, "last_success")).Build()).AsBytes()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return ExecutionRecord{}, false, nil
+		}
+		return ExecutionRecord{}, false, fmt.Errorf("locking: last success of %q: %w", name, err)
+	}
+	var rec ExecutionRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return ExecutionRecord{}, false, fmt.Errorf("locking: decode execution of %q: %w", name, err)
+	}
+	return rec, true, nil
+}
+
+// Recent implements ExecutionHistory.
+func (s *RedisExecutionStore) Recent(ctx context.Context, name string, limit int) ([]ExecutionRecord, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	vals, err := s.client.Do(ctx, s.client.B().Lrange().Key(s.key(name, "runs")).Start(0).Stop(int64(limit)-1).Build()).AsStrSlice()
+	if err != nil {
+		return nil, fmt.Errorf("locking: recent executions of %q: %w", name, err)
+	}
+	out := make([]ExecutionRecord, 0, len(vals))
+	for _, v := range vals {
+		var rec ExecutionRecord
+		if err := json.Unmarshal([]byte(v), &rec); err != nil {
+			return nil, fmt.Errorf("locking: decode execution of %q: %w", name, err)
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
@@ -20,9 +20,28 @@ import (
 	"log/slog"
 	"time"
 
+	appclock "app/modules/clock"
+
 	"github.com/redis/rueidis/rueidislock"
 )
 
+// Locker is the lock backend of a LockingTaskExecutor.
+//
+// rueidislock.Locker satisfies it directly; PostgresLocker provides the same
+// semantics on Postgres advisory locks. The returned context is cancelled
+// when the lock is lost, the returned cancel func releases it.
+type Locker interface {
+	// WithContext blocks until the lock is acquired or ctx is done.
+	WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error)
+	// TryWithContext acquires the lock once, failing with ErrLockNotAcquired
+	// (or rueidislock.ErrNotLocked) when another holder has it.
+	TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error)
+	// Close releases held locks and makes the locker unusable.
+	Close()
+}
+
+var _ Locker = (rueidislock.Locker)(nil)
+
 // TaskFunc is the task signature executed under the distributed lock.
 type TaskFunc func(ctx context.Context) error
 
@@ -43,6 +62,13 @@ type LockConfiguration struct {
 // and the lock is already held by another node.
 var ErrLockNotAcquired = errors.New("locking: lock not acquired")
 
+// ErrBackendDegraded is returned without touching redis while the degraded check
+// (see WithDegradedFunc) reports the lock backend as unreachable.
+var ErrBackendDegraded = errors.New("locking: lock backend degraded")
+
+// ErrLockerClosed is returned by lockers that were closed.
+var ErrLockerClosed = errors.New("locking: locker closed")
+
 // ErrInvalidConfiguration is returned when LockConfiguration is invalid.
 var ErrInvalidConfiguration = errors.New("locking: invalid lock configuration")
 
@@ -51,13 +77,13 @@ type clock func() time.Time
 
 func defaultClock() time.Time { return time.Now() }
 
-// LockingTaskExecutor coordinates distributed locks around tasks using
-// github.com/redis/rueidis/rueidislock.
+// LockingTaskExecutor coordinates distributed locks around tasks using a
+// Locker, usually github.com/redis/rueidis/rueidislock.
 //
 // It is intended for scheduled jobs / background tasks where you want
 // "at most one node executes this job at a time".
 type LockingTaskExecutor struct {
-	locker rueidislock.Locker
+	locker Locker
 	logger *slog.Logger
 
 	// if true, Execute() will block waiting for the lock (locker.WithContext).
@@ -70,10 +96,28 @@ type LockingTaskExecutor struct {
 	acquireTimeout time.Duration
 
 	// Optional prefix applied to all LockConfiguration.Name values.
-	// Final Redis lock key name will be: prefix + cfg.Name.
+	// Final Redis lock key name will be: prefix + cfg.Name (or its hash, see WithNameHashing).
 	namePrefix string
 
+	// how names are mapped to keys and the max key length, see names.go
+	hashing       NameHashing
+	maxNameLength int
+	names         nameRegistry
+
+	// optional; lock attempts fail fast while it reports true
+	degraded func() bool
+
+	// more than one concurrent run takes permits instead of the lock, see WithMaxConcurrency
+	maxConcurrency int
+	permits        Permits
+
+	// optional; stores a record of each run, see history.go
+	recorder ExecutionRecorder
+	node     string
+
 	now clock
+	// optional; drives the LockAtLeastFor hold and LockAtMostFor deadline, see WithTimers
+	timers appclock.Timers
 }
 
 // Option configures a LockingTaskExecutor.
@@ -110,6 +154,33 @@ func WithNamePrefix(prefix string) Option {
 	}
 }
 
+// WithDegradedFunc makes Execute fail closed with ErrBackendDegraded while fn reports
+// true (usually redis.Watchdog.Degraded): a task that must run at most once at a time
+// cannot run without its lock, and failing fast beats waiting on redis timeouts.
+func WithDegradedFunc(fn func() bool) Option {
+	return func(e *LockingTaskExecutor) {
+		e.degraded = fn
+	}
+}
+
+// WithMaxConcurrency lets up to n nodes run the same task at once. n > 1 takes
+// one of n permits from the Permits set with WithSemaphore instead of the lock;
+// the lock name is used as the semaphore name. Defaults to 1.
+func WithMaxConcurrency(n int) Option {
+	return func(e *LockingTaskExecutor) {
+		if n > 0 {
+			e.maxConcurrency = n
+		}
+	}
+}
+
+// WithSemaphore sets the permit backend used by WithMaxConcurrency, usually a *Semaphore.
+func WithSemaphore(p Permits) Option {
+	return func(e *LockingTaskExecutor) {
+		e.permits = p
+	}
+}
+
 // WithClock overrides the time source (useful in tests).
 func WithClock(fn clock) Option {
 	return func(e *LockingTaskExecutor) {
@@ -119,14 +190,30 @@ func WithClock(fn clock) Option {
 	}
 }
 
-// NewLockingTaskExecutor constructs a new LockingTaskExecutor from a rueidislock.Locker.
+// WithTimers drives the time source, the LockAtLeastFor hold and the LockAtMostFor
+// deadline from t, so tests control them with a clock.Fake. Under t the task
+// context is still cancelled with context.DeadlineExceeded. Lock acquisition and
+// the locker's own key validity keep running on real time.
+func WithTimers(t appclock.Timers) Option {
+	return func(e *LockingTaskExecutor) {
+		if t != nil {
+			e.timers = t
+			e.now = t.Now
+		}
+	}
+}
+
+// NewLockingTaskExecutor constructs a new LockingTaskExecutor from a Locker.
 //
 // The same Locker can be shared by multiple executors with different prefixes / semantics.
-func NewLockingTaskExecutor(locker rueidislock.Locker, opts ...Option) *LockingTaskExecutor {
+func NewLockingTaskExecutor(locker Locker, opts ...Option) *LockingTaskExecutor {
 	e := &LockingTaskExecutor{
 		locker:         locker,
 		waitForLock:    false, // default: "try once" behavior
 		acquireTimeout: 0,
+		maxConcurrency: 1,
+		maxNameLength:  DefaultMaxNameLength,
+		node:           defaultNodeID(),
 		now:            defaultClock,
 	}
 	for _, opt := range opts {
@@ -169,7 +256,25 @@ func (e *LockingTaskExecutor) Execute(
 		return err
 	}
 
-	lockName := e.lockName(cfg.Name)
+	lockName, err := e.lockName(cfg.Name)
+	if err != nil {
+		return err
+	}
+
+	locker := e.locker
+	if e.maxConcurrency > 1 {
+		if e.permits == nil {
+			return fmt.Errorf("%w: max concurrency %d needs a semaphore", ErrInvalidConfiguration, e.maxConcurrency)
+		}
+		locker = permitLocker{permits: e.permits, n: e.maxConcurrency}
+	}
+
+	if e.degraded != nil && e.degraded() {
+		if e.logger != nil {
+			e.logger.Warn("locking: lock backend degraded, skipping task", slog.String("lock.name", lockName))
+		}
+		return ErrBackendDegraded
+	}
 
 	if e.logger != nil {
 		e.logger.Info("locking: attempting to acquire lock",
@@ -177,6 +282,7 @@ func (e *LockingTaskExecutor) Execute(
 			slog.Duration("lock.at_most_for", cfg.LockAtMostFor),
 			slog.Duration("lock.at_least_for", cfg.LockAtLeastFor),
 			slog.Bool("lock.wait_for_lock", e.waitForLock),
+			slog.Int("lock.max_concurrency", e.maxConcurrency),
 		)
 	}
 
@@ -186,7 +292,6 @@ func (e *LockingTaskExecutor) Execute(
 	var (
 		lockCtx    context.Context
 		lockCancel context.CancelFunc
-		err        error
 	)
 
 	if e.waitForLock {
@@ -198,10 +303,10 @@ func (e *LockingTaskExecutor) Execute(
 			defer cancel()
 		}
 
-		lockCtx, lockCancel, err = e.locker.WithContext(acquireCtx, lockName)
+		lockCtx, lockCancel, err = locker.WithContext(acquireCtx, lockName)
 		if err != nil {
 			// ErrLockerClosed means the locker client is unusable now.
-			if errors.Is(err, rueidislock.ErrLockerClosed) {
+			if isLockerClosed(err) {
 				return fmt.Errorf("locking: locker closed while acquiring lock %q: %w", lockName, err)
 			}
 			// Context errors should be surfaced as-is.
@@ -212,9 +317,9 @@ func (e *LockingTaskExecutor) Execute(
 		}
 	} else {
 		// Try-once mode: TryWithContext
-		lockCtx, lockCancel, err = e.locker.TryWithContext(ctx, lockName)
+		lockCtx, lockCancel, err = locker.TryWithContext(ctx, lockName)
 		if err != nil {
-			if errors.Is(err, rueidislock.ErrNotLocked) {
+			if errors.Is(err, rueidislock.ErrNotLocked) || errors.Is(err, ErrLockNotAcquired) {
 				// Someone else already holds the lock.
 				if e.logger != nil {
 					e.logger.Info("locking: lock not acquired (already held by another node)",
@@ -222,7 +327,7 @@ func (e *LockingTaskExecutor) Execute(
 				}
 				return ErrLockNotAcquired
 			}
-			if errors.Is(err, rueidislock.ErrLockerClosed) {
+			if isLockerClosed(err) {
 				return fmt.Errorf("locking: locker closed while trying to acquire lock %q: %w", lockName, err)
 			}
 			return fmt.Errorf("locking: failed to try-acquire lock %q: %w", lockName, err)
@@ -246,7 +351,7 @@ func (e *LockingTaskExecutor) Execute(
 	var taskCancel context.CancelFunc
 
 	if cfg.LockAtMostFor > 0 {
-		taskCtx, taskCancel = context.WithTimeout(lockCtx, cfg.LockAtMostFor)
+		taskCtx, taskCancel = e.withTimeout(lockCtx, cfg.LockAtMostFor)
 	} else {
 		taskCtx, taskCancel = context.WithCancel(lockCtx)
 	}
@@ -257,6 +362,7 @@ func (e *LockingTaskExecutor) Execute(
 	err = task(taskCtx)
 	taskEnd := e.now()
 	taskDuration := taskEnd.Sub(taskStart)
+	e.record(ctx, cfg.Name, taskStart, taskEnd, err)
 
 	if e.logger != nil {
 		e.logger.Info("locking: task finished",
@@ -283,11 +389,11 @@ func (e *LockingTaskExecutor) Execute(
 				)
 			}
 
-			timer := time.NewTimer(wait)
-			defer timer.Stop()
+			elapsed, stop := e.after(wait)
+			defer stop()
 
 			select {
-			case <-timer.C:
+			case <-elapsed:
 				// normal completion, we kept the lock long enough
 			case <-ctx.Done():
 				// caller canceled; respect it
@@ -301,11 +407,46 @@ func (e *LockingTaskExecutor) Execute(
 	return err
 }
 
-func (e *LockingTaskExecutor) lockName(base string) string {
-	if e.namePrefix == "" {
-		return base
+// after returns a channel closed once d has elapsed, and a func stopping the timer.
+func (e *LockingTaskExecutor) after(d time.Duration) (<-chan struct{}, func() bool) {
+	ch := make(chan struct{})
+	fire := func() { close(ch) }
+	if e.timers == nil {
+		return ch, time.AfterFunc(d, fire).Stop
 	}
-	return e.namePrefix + base
+	return ch, e.timers.AfterFunc(d, fire)
+}
+
+// withTimeout is context.WithTimeout, timed by the executor's timers when set.
+func (e *LockingTaskExecutor) withTimeout(parent context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if e.timers == nil {
+		return context.WithTimeout(parent, d)
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	stop := e.timers.AfterFunc(d, func() { cancel(context.DeadlineExceeded) })
+	return timedContext{Context: ctx, deadline: e.now().Add(d)}, func() {
+		stop()
+		cancel(context.Canceled)
+	}
+}
+
+// timedContext reports the deadline of a context cancelled by a clock timer and
+// context.DeadlineExceeded once it passed, like a context.WithTimeout one.
+type timedContext struct {
+	context.Context
+	deadline time.Time
+}
+
+func (c timedContext) Deadline() (time.Time, bool) {
+	return c.deadline, true
+}
+
+func (c timedContext) Err() error {
+	err := c.Context.Err()
+	if err != nil && context.Cause(c.Context) == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
+	return err
 }
 
 func validateConfig(cfg LockConfiguration) error {
@@ -324,3 +465,7 @@ func validateConfig(cfg LockConfiguration) error {
 	}
 	return nil
 }
+
+func isLockerClosed(err error) bool {
+	return errors.Is(err, rueidislock.ErrLockerClosed) || errors.Is(err, ErrLockerClosed)
+}
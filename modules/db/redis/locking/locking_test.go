@@ -0,0 +1,257 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"app/modules/db/redis/locking"
+	"app/modules/db/redis/locking/lockingtest"
+)
+
+func TestExecute_ExactlyOnce(t *testing.T) {
+	c := lockingtest.NewCluster(t, 8)
+	cfg := locking.LockConfiguration{Name: "cleanup", LockAtLeastFor: time.Minute}
+	probe := &lockingtest.Probe{}
+
+	run := c.Go(t.Context(), cfg, probe.Track(nil))
+	// the losers are turned away while the winner holds the lock
+	run.WaitN(len(c.Nodes) - 1)
+	c.Clock.BlockUntil(1)
+	c.Clock.Advance(cfg.LockAtLeastFor)
+
+	lockingtest.AssertExactlyOnce(t, probe, run.Wait())
+}
+
+func TestExecute_LockAtLeastFor(t *testing.T) {
+	c := lockingtest.NewCluster(t, 2)
+	cfg := locking.LockConfiguration{Name: "refresh", LockAtLeastFor: 30 * time.Second}
+	probe := &lockingtest.Probe{}
+	first, second := c.Nodes[0], c.Nodes[1]
+
+	done := make(chan error, 1)
+	go func() { done <- first.Executor.Execute(t.Context(), cfg, track(probe, first, nil)) }()
+	// the task returned right away, the lock is held until the hold elapsed
+	c.Clock.BlockUntil(1)
+
+	if err := second.Executor.Execute(t.Context(), cfg, track(probe, second, nil)); !errors.Is(err, locking.ErrLockNotAcquired) {
+		t.Fatalf("Execute during the hold = %v, want ErrLockNotAcquired", err)
+	}
+	c.Clock.Advance(cfg.LockAtLeastFor - time.Second)
+	if err := second.Executor.Execute(t.Context(), cfg, track(probe, second, nil)); !errors.Is(err, locking.ErrLockNotAcquired) {
+		t.Fatalf("Execute before the hold elapsed = %v, want ErrLockNotAcquired", err)
+	}
+
+	c.Clock.Advance(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("holder Execute = %v", err)
+	}
+	go func() { done <- second.Executor.Execute(t.Context(), cfg, track(probe, second, nil)) }()
+	c.Clock.BlockUntil(1)
+	c.Clock.Advance(cfg.LockAtLeastFor)
+	if err := <-done; err != nil {
+		t.Fatalf("Execute after the hold = %v, want nil", err)
+	}
+
+	lockingtest.AssertMutualExclusion(t, probe, 2)
+}
+
+func TestExecute_LockAtMostFor(t *testing.T) {
+	c := lockingtest.NewCluster(t, 2)
+	cfg := locking.LockConfiguration{Name: "reindex", LockAtMostFor: time.Minute}
+	first, second := c.Nodes[0], c.Nodes[1]
+
+	done := make(chan error, 1)
+	go func() {
+		done <- first.Executor.Execute(t.Context(), cfg, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	c.Clock.BlockUntil(1)
+
+	if err := second.Executor.Execute(t.Context(), cfg, func(context.Context) error { return nil }); !errors.Is(err, locking.ErrLockNotAcquired) {
+		t.Fatalf("Execute while the task runs = %v, want ErrLockNotAcquired", err)
+	}
+	c.Clock.Advance(cfg.LockAtMostFor - time.Second)
+	select {
+	case err := <-done:
+		t.Fatalf("task stopped before LockAtMostFor: %v", err)
+	default:
+	}
+
+	c.Clock.Advance(time.Second)
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Execute past LockAtMostFor = %v, want context.DeadlineExceeded", err)
+	}
+	// the lock went with the cancelled task
+	if err := second.Executor.Execute(t.Context(), cfg, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Execute after the cancellation = %v, want nil", err)
+	}
+}
+
+func TestExecute_WaitForLock(t *testing.T) {
+	c := lockingtest.NewCluster(t, 4, lockingtest.WithExecutorOptions(
+		locking.WithWaitForLock(true),
+		locking.WithAcquireTimeout(10*time.Second),
+	))
+	cfg := locking.LockConfiguration{Name: "compact"}
+	probe := &lockingtest.Probe{}
+
+	errs := c.Run(t.Context(), cfg, probe.Track(func(context.Context, *lockingtest.Node) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}))
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("node-%d: Execute = %v", i, err)
+		}
+	}
+	// waiting nodes take turns instead of skipping the run
+	lockingtest.AssertMutualExclusion(t, probe, len(c.Nodes))
+}
+
+func TestExecute_FairLocking(t *testing.T) {
+	c := lockingtest.NewCluster(t, 4,
+		lockingtest.WithFairLocking(),
+		lockingtest.WithExecutorOptions(
+			locking.WithWaitForLock(true),
+			locking.WithAcquireTimeout(10*time.Second),
+		))
+	cfg := locking.LockConfiguration{Name: "rollup"}
+	probe := &lockingtest.Probe{}
+
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, len(c.Nodes))
+	holder := c.Nodes[0]
+	go func() {
+		done <- holder.Executor.Execute(t.Context(), cfg, track(probe, holder, func() {
+			close(started)
+			<-release
+		}))
+	}()
+	<-started
+
+	// the waiters arrive one after the other while the lock is busy
+	for i, n := range c.Nodes[1:] {
+		go func() { done <- n.Executor.Execute(t.Context(), cfg, track(probe, n, nil)) }()
+		waitQueued(t, holder.Fair, c.LockKey(cfg.Name), i+1)
+	}
+	close(release)
+	for range c.Nodes {
+		if err := <-done; err != nil {
+			t.Fatalf("Execute = %v", err)
+		}
+	}
+
+	runs := probe.Runs()
+	for i, n := range c.Nodes {
+		if i >= len(runs) || runs[i] != n.Name {
+			t.Fatalf("runs = %v, want the nodes in arrival order", runs)
+		}
+	}
+	lockingtest.AssertMutualExclusion(t, probe, len(c.Nodes))
+}
+
+func TestScheduler_ConcurrencyGroup(t *testing.T) {
+	c := lockingtest.NewCluster(t, 2)
+	group := locking.ConcurrencyGroup{Name: "maintenance", Limit: 1}
+	names := []string{"purge.audit", "purge.counters"}
+	probe := &lockingtest.Probe{}
+
+	var once sync.Map
+	ran := make(chan string, len(names))
+	for _, n := range c.Nodes {
+		// the scheduler runs on real time; under the cluster clock the deadline of the
+		// task context would have passed before the permit is taken
+		exec := locking.NewLockingTaskExecutor(n.Locker, locking.WithNamePrefix(c.Prefix), locking.WithNodeID(n.Name))
+		sem := locking.NewSemaphore(c.NewClient(t),
+			locking.WithSemaphoreKeyPrefix(c.Prefix+"groups"), locking.WithRetryInterval(5*time.Millisecond))
+		s := locking.NewScheduler(exec, locking.WithConcurrencyGroups(sem, group))
+		if err := s.Add(locking.Job{
+			Lock:     locking.LockConfiguration{Name: "undeclared", LockAtMostFor: time.Minute},
+			Schedule: locking.Every(time.Hour),
+			Task:     func(context.Context) error { return nil },
+			Group:    "heavy",
+		}); !errors.Is(err, locking.ErrInvalidConfiguration) {
+			t.Fatalf("Add with an undeclared group = %v, want ErrInvalidConfiguration", err)
+		}
+		// the jobs of both nodes fire together, every second
+		for _, name := range names {
+			if err := s.Add(locking.Job{
+				Lock:     locking.LockConfiguration{Name: name, LockAtMostFor: time.Minute},
+				Schedule: locking.Every(time.Second),
+				Task: track(probe, n, func() {
+					time.Sleep(20 * time.Millisecond)
+					if _, loaded := once.LoadOrStore(name, true); !loaded {
+						ran <- name
+					}
+				}),
+				Group: group.Name,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := s.Start(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Stop(context.Background())
+	}
+
+	for range names {
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("not every job of the group ran, runs: %v", probe.Runs())
+		}
+	}
+	// the two jobs take different locks, only the group keeps them apart
+	if got := probe.MaxConcurrent(); got > group.Limit {
+		t.Fatalf("%d jobs of the group overlapped, want at most %d", got, group.Limit)
+	}
+}
+
+func waitQueued(t *testing.T, f *locking.FairLocker, name string, want int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		n, err := f.Queued(t.Context(), name)
+		if err != nil {
+			t.Fatalf("Queued = %v", err)
+		}
+		if n == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Queued = %d, want %d", n, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// track runs body, if any, as the task of n.
+func track(p *lockingtest.Probe, n *lockingtest.Node, body func()) locking.TaskFunc {
+	task := p.Track(func(context.Context, *lockingtest.Node) error {
+		if body != nil {
+			body()
+		}
+		return nil
+	})
+	return func(ctx context.Context) error { return task(ctx, n) }
+}
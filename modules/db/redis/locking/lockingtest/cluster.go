@@ -0,0 +1,223 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lockingtest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/db/redis/locking"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/rueidis"
+	"github.com/redis/rueidis/rueidislock"
+)
+
+type (
+	// Cluster is a set of nodes contending for the same locks.
+	Cluster struct {
+		// Redis is the in-process server, nil when WithRedisAddr is used.
+		Redis *miniredis.Miniredis
+		// Clock drives every executor, see locking.WithTimers.
+		Clock *clock.Fake
+		// Prefix is prepended to lock names by every executor, see LockKey.
+		Prefix string
+		Nodes  []*Node
+
+		addr string
+	}
+
+	// Node is one simulated instance of the application.
+	Node struct {
+		Name   string
+		Locker rueidislock.Locker
+		// Fair wraps Locker when the cluster runs WithFairLocking, nil otherwise.
+		Fair     *locking.FairLocker
+		Executor *locking.LockingTaskExecutor
+	}
+
+	// Task is a locked task, told which node runs it.
+	Task func(ctx context.Context, n *Node) error
+
+	// Option configures a Cluster.
+	Option func(*options)
+
+	options struct {
+		addr     string
+		executor []locking.Option
+		fair     bool
+		fairOpts []locking.FairLockerOption
+	}
+)
+
+// RedisAddrEnv names the environment variable that, when set, points every Cluster
+// without WithRedisAddr at an external Redis, e.g. a CI service container.
+const RedisAddrEnv = "LOCKINGTEST_REDIS_ADDR"
+
+// Epoch is the time the Cluster clock starts at.
+var Epoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WithRedisAddr runs the nodes against an external Redis (e.g. a test container)
+// instead of miniredis. Lock names are prefixed with the test name, so tests can
+// share the server.
+func WithRedisAddr(addr string) Option {
+	return func(o *options) {
+		o.addr = addr
+	}
+}
+
+// WithExecutorOptions configures every executor, e.g. locking.WithWaitForLock(true).
+func WithExecutorOptions(opts ...locking.Option) Option {
+	return func(o *options) {
+		o.executor = append(o.executor, opts...)
+	}
+}
+
+// WithFairLocking queues the blocking acquisitions of every node in a
+// locking.FairLocker, on a client of its own.
+func WithFairLocking(opts ...locking.FairLockerOption) Option {
+	return func(o *options) {
+		o.fair = true
+		o.fairOpts = append(o.fairOpts, opts...)
+	}
+}
+
+// NewCluster starts n nodes. Lockers are closed when tb finishes.
+func NewCluster(tb testing.TB, n int, opts ...Option) *Cluster {
+	tb.Helper()
+	o := options{addr: os.Getenv(RedisAddrEnv)}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	c := &Cluster{Clock: clock.NewFake(Epoch), addr: o.addr}
+	if c.addr == "" {
+		c.Redis = miniredis.RunT(tb)
+		c.addr = c.Redis.Addr()
+	}
+	c.Prefix = "lockingtest:" + strings.ReplaceAll(tb.Name(), "/", ":") + ":"
+
+	for i := range n {
+		locker, err := rueidislock.NewLocker(rueidislock.LockerOption{
+			ClientOption: rueidis.ClientOption{
+				InitAddress: []string{c.addr},
+				// miniredis has no client side caching
+				DisableCache: true,
+			},
+			KeyMajority:    1,
+			NoLoopTracking: true,
+		})
+		if err != nil {
+			tb.Fatalf("lockingtest: node %d: new locker: %v", i, err)
+		}
+		tb.Cleanup(locker.Close)
+
+		node := &Node{Name: fmt.Sprintf("node-%d", i), Locker: locker}
+		var executorLocker locking.Locker = locker
+		if o.fair {
+			node.Fair = locking.NewFairLocker(locker, c.NewClient(tb), o.fairOpts...)
+			executorLocker = node.Fair
+		}
+
+		execOpts := append([]locking.Option{
+			locking.WithNamePrefix(c.Prefix),
+			locking.WithTimers(c.Clock),
+			locking.WithNodeID(node.Name),
+		}, o.executor...)
+		node.Executor = locking.NewLockingTaskExecutor(executorLocker, execOpts...)
+		c.Nodes = append(c.Nodes, node)
+	}
+	return c
+}
+
+// NewClient connects a client to the cluster's Redis, e.g. for a locking.Semaphore.
+// It is closed when tb finishes.
+func (c *Cluster) NewClient(tb testing.TB) rueidis.Client {
+	tb.Helper()
+	client, err := rueidis.NewClient(rueidis.ClientOption{InitAddress: []string{c.addr}, DisableCache: true})
+	if err != nil {
+		tb.Fatalf("lockingtest: new client: %v", err)
+	}
+	tb.Cleanup(client.Close)
+	return client
+}
+
+// LockKey returns the name the executors pass to their locker for a task lock name.
+func (c *Cluster) LockKey(name string) string {
+	return c.Prefix + name
+}
+
+// Run executes task on every node at once and waits for all of them.
+func (c *Cluster) Run(ctx context.Context, cfg locking.LockConfiguration, task Task) []error {
+	return c.Go(ctx, cfg, task).Wait()
+}
+
+// Go executes task on every node at once in the background. The nodes are released
+// together once all of them are started, to maximize contention.
+func (c *Cluster) Go(ctx context.Context, cfg locking.LockConfiguration, task Task) *Run {
+	r := &Run{
+		errs:     make([]error, len(c.Nodes)),
+		returned: make(chan int, len(c.Nodes)),
+	}
+	var ready sync.WaitGroup
+	start := make(chan struct{})
+	r.wg.Add(len(c.Nodes))
+	ready.Add(len(c.Nodes))
+	for i, n := range c.Nodes {
+		go func() {
+			defer r.wg.Done()
+			ready.Done()
+			<-start
+			r.errs[i] = n.Executor.Execute(ctx, cfg, func(ctx context.Context) error {
+				return task(ctx, n)
+			})
+			r.returned <- i
+		}()
+	}
+	ready.Wait()
+	close(start)
+	return r
+}
+
+// Run is an execution started by Cluster.Go.
+type Run struct {
+	wg       sync.WaitGroup
+	errs     []error
+	returned chan int
+}
+
+// WaitN blocks until n more nodes returned from Execute and returns their indexes.
+func (r *Run) WaitN(n int) []int {
+	out := make([]int, 0, n)
+	for range n {
+		out = append(out, <-r.returned)
+	}
+	return out
+}
+
+// Wait blocks until every node returned and reports their Execute errors,
+// indexed like Cluster.Nodes.
+func (r *Run) Wait() []error {
+	r.wg.Wait()
+	return r.errs
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lockingtest runs lock executors as a cluster of simulated nodes, to
+// verify the guarantees of locking.LockingTaskExecutor under contention.
+//
+// Every node has its own rueidislock.Locker and executor against one shared Redis:
+// an in-process miniredis by default, or a containerized one with WithRedisAddr
+// or the LOCKINGTEST_REDIS_ADDR environment variable.
+// The executors share a clock.Fake, so LockAtLeastFor holds and LockAtMostFor
+// deadlines only pass when the test advances it:
+//
+//	c := lockingtest.NewCluster(t, 5)
+//	probe := &lockingtest.Probe{}
+//	run := c.Go(ctx, cfg, probe.Track(task))
+//	run.WaitN(4)                  // the losers return right away
+//	c.Clock.BlockUntil(1)         // the winner holds the lock for LockAtLeastFor
+//	c.Clock.Advance(cfg.LockAtLeastFor)
+//	lockingtest.AssertExactlyOnce(t, probe, run.Wait())
+package lockingtest
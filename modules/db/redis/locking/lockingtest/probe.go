@@ -0,0 +1,101 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lockingtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"app/modules/db/redis/locking"
+)
+
+// Probe records the executions of a task across the nodes of a Cluster.
+// The zero value is ready to use.
+type Probe struct {
+	mu        sync.Mutex
+	runs      []string
+	active    int
+	maxActive int
+}
+
+// Track wraps task to record each of its executions.
+func (p *Probe) Track(task Task) Task {
+	return func(ctx context.Context, n *Node) error {
+		p.mu.Lock()
+		p.runs = append(p.runs, n.Name)
+		p.active++
+		p.maxActive = max(p.maxActive, p.active)
+		p.mu.Unlock()
+
+		defer func() {
+			p.mu.Lock()
+			p.active--
+			p.mu.Unlock()
+		}()
+		if task == nil {
+			return nil
+		}
+		return task(ctx, n)
+	}
+}
+
+// Runs returns the names of the nodes that executed the task, in start order.
+func (p *Probe) Runs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.runs...)
+}
+
+// MaxConcurrent returns the largest number of executions that overlapped.
+func (p *Probe) MaxConcurrent() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maxActive
+}
+
+// AssertExactlyOnce fails tb unless a single node executed the task and every
+// other node was turned away with locking.ErrLockNotAcquired.
+func AssertExactlyOnce(tb testing.TB, p *Probe, errs []error) {
+	tb.Helper()
+	if runs := p.Runs(); len(runs) != 1 {
+		tb.Fatalf("lockingtest: task ran %d times (%v), want exactly once", len(runs), runs)
+	}
+	succeeded := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, locking.ErrLockNotAcquired):
+			tb.Fatalf("lockingtest: node-%d: Execute = %v, want nil or ErrLockNotAcquired", i, err)
+		}
+	}
+	if succeeded != 1 {
+		tb.Fatalf("lockingtest: %d nodes executed, want 1", succeeded)
+	}
+}
+
+// AssertMutualExclusion fails tb unless the task ran want times without two
+// executions ever overlapping.
+func AssertMutualExclusion(tb testing.TB, p *Probe, want int) {
+	tb.Helper()
+	if got := p.MaxConcurrent(); got > 1 {
+		tb.Fatalf("lockingtest: %d executions overlapped, want at most 1", got)
+	}
+	if runs := p.Runs(); len(runs) != want {
+		tb.Fatalf("lockingtest: task ran %d times (%v), want %d", len(runs), runs, want)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// NameHashing controls how lock names are turned into Redis keys.
+type NameHashing int
+
+const (
+	// HashNever uses prefix + name as is (default).
+	HashNever NameHashing = iota
+	// HashAlways uses prefix + hex(sha256(name)), for user-derived names.
+	HashAlways
+	// HashIfTooLong hashes only names that would exceed the max length.
+	HashIfTooLong
+)
+
+// DefaultMaxNameLength bounds the final lock key length unless overridden by WithMaxNameLength.
+const DefaultMaxNameLength = 512
+
+// maxTrackedNames caps the collision tracking table so user-derived names cannot grow it unbounded.
+const maxTrackedNames = 10_000
+
+// WithNameHashing sets how lock names are mapped to keys. See NameHashing.
+func WithNameHashing(mode NameHashing) Option {
+	return func(e *LockingTaskExecutor) {
+		e.hashing = mode
+	}
+}
+
+// WithMaxNameLength sets the maximum length of the final lock key (prefix included).
+// Longer keys are rejected with ErrInvalidConfiguration, or hashed under HashIfTooLong.
+// Zero or a negative n disables the guard.
+func WithMaxNameLength(n int) Option {
+	return func(e *LockingTaskExecutor) {
+		e.maxNameLength = n
+	}
+}
+
+// nameRegistry remembers which logical name produced each key, so a key reached
+// from two different names (hash collision, or prefix + name ambiguity such as
+// "a:" + "b:c" vs "a:b:" + "c") is reported instead of silently sharing a lock.
+type nameRegistry struct {
+	mu    sync.Mutex
+	names map[string]string
+}
+
+// observe records key -> name and returns the previously seen name if it differs.
+func (r *nameRegistry) observe(key, name string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if prev, ok := r.names[key]; ok {
+		return prev, prev != name
+	}
+	if r.names == nil {
+		r.names = make(map[string]string)
+	}
+	if len(r.names) < maxTrackedNames {
+		r.names[key] = name
+	}
+	return "", false
+}
+
+func hashName(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return hex.EncodeToString(sum[:])
+}
+
+// lockName maps a logical lock name to its Redis key, enforcing the max length.
+func (e *LockingTaskExecutor) lockName(base string) (string, error) {
+	key := e.namePrefix + base
+	switch e.hashing {
+	case HashAlways:
+		key = e.namePrefix + hashName(base)
+	case HashIfTooLong:
+		if e.maxNameLength > 0 && len(key) > e.maxNameLength {
+			key = e.namePrefix + hashName(base)
+		}
+	}
+
+	if e.maxNameLength > 0 && len(key) > e.maxNameLength {
+		return "", fmt.Errorf("%w: lock key is %d bytes, max %d", ErrInvalidConfiguration, len(key), e.maxNameLength)
+	}
+
+	if prev, collided := e.names.observe(key, base); collided {
+		logger := e.logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("locking: different lock names map to the same key",
+			slog.String("lock.key", key),
+			slog.String("lock.name", base),
+			slog.String("lock.previous_name", prev),
+		)
+	}
+	return key, nil
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"app/modules/db"
+
+	"github.com/stephenafamo/bob"
+	"github.com/stephenafamo/bob/dialect/psql"
+	"github.com/stephenafamo/scan"
+)
+
+type (
+	// PostgresLocker implements Locker with transaction-scoped Postgres advisory locks,
+	// for deployments without Redis.
+	//
+	// Every held lock pins one pooled connection in an open transaction; the lock is
+	// released when the transaction ends, including when the connection dies, so a
+	// crashed node never leaves a lock behind. Lock names are hashed to the 64-bit
+	// advisory lock key space.
+	PostgresLocker struct {
+		pool      db.TxManager
+		prefix    string
+		keepAlive time.Duration
+
+		mu     sync.Mutex
+		closed bool
+		// closes on Close, releasing every held lock
+		closing chan struct{}
+	}
+
+	// PostgresLockerOption configures a PostgresLocker.
+	PostgresLockerOption func(*PostgresLocker)
+)
+
+var _ Locker = (*PostgresLocker)(nil)
+
+// WithPostgresKeyPrefix namespaces lock names before they are hashed, so services
+// sharing a database do not contend on equal names.
+func WithPostgresKeyPrefix(prefix string) PostgresLockerOption {
+	return func(l *PostgresLocker) {
+		l.prefix = prefix
+	}
+}
+
+// WithKeepAlive sets how often a held lock checks its connection is still alive;
+// the lock context is cancelled as soon as it is not. Defaults to 10 seconds.
+func WithKeepAlive(d time.Duration) PostgresLockerOption {
+	return func(l *PostgresLocker) {
+		if d > 0 {
+			l.keepAlive = d
+		}
+	}
+}
+
+// NewPostgresLocker constructs a PostgresLocker taking its connections from pool.
+func NewPostgresLocker(pool db.TxManager, opts ...PostgresLockerOption) *PostgresLocker {
+	l := &PostgresLocker{
+		pool:      pool,
+		keepAlive: 10 * time.Second,
+		closing:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(l)
+		}
+	}
+	return l
+}
+
+// WithContext blocks until the lock is acquired or ctx is done.
+func (l *PostgresLocker) WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.acquire(ctx, name, true)
+}
+
+// TryWithContext acquires the lock or returns ErrLockNotAcquired without waiting.
+func (l *PostgresLocker) TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.acquire(ctx, name, false)
+}
+
+// Close releases every held lock; later acquisitions fail with ErrLockerClosed.
+func (l *PostgresLocker) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.closed {
+		l.closed = true
+		close(l.closing)
+	}
+}
+
+func (l *PostgresLocker) acquire(ctx context.Context, name string, wait bool) (context.Context, context.CancelFunc, error) {
+	l.mu.Lock()
+	closed := l.closed
+	l.mu.Unlock()
+	if closed {
+		return nil, nil, ErrLockerClosed
+	}
+
+	key := l.key(name)
+	lockCtx, cancelLock := context.WithCancel(ctx)
+	acquired := make(chan error, 1)
+	release := make(chan struct{})
+
+	go func() {
+		defer cancelLock()
+		var reported bool
+		// the transaction outlives ctx: it is only ended by release, Close or a dead connection
+		err := l.pool.WithTx(context.WithoutCancel(ctx), func(txCtx context.Context, q db.Querier) error {
+			ok, err := lock(ctx, q, key, wait)
+			reported = true
+			if err != nil {
+				acquired <- err
+				return err
+			}
+			if !ok {
+				acquired <- ErrLockNotAcquired
+				return nil
+			}
+			acquired <- nil
+			return l.hold(txCtx, lockCtx, q, release)
+		})
+		if !reported {
+			acquired <- err
+		}
+	}()
+
+	if err := <-acquired; err != nil {
+		cancelLock()
+		if errors.Is(err, ErrLockNotAcquired) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("locking: postgres advisory lock %q: %w", name, err)
+	}
+
+	return lockCtx, sync.OnceFunc(func() {
+		close(release)
+		cancelLock()
+	}), nil
+}
+
+// hold keeps the transaction, and with it the lock, open until released.
+func (l *PostgresLocker) hold(txCtx, lockCtx context.Context, q db.Querier, release <-chan struct{}) error {
+	ticker := time.NewTicker(l.keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-release:
+			return nil
+		case <-lockCtx.Done():
+			return nil
+		case <-l.closing:
+			return nil
+		case <-ticker.C:
+			if _, err := psql.RawQuery("SELECT 1").Exec(txCtx, q); err != nil {
+				return fmt.Errorf("locking: advisory lock connection lost: %w", err)
+			}
+		}
+	}
+}
+
+func lock(ctx context.Context, q db.Querier, key int64, wait bool) (bool, error) {
+	if wait {
+		if _, err := psql.RawQuery("SELECT pg_advisory_xact_lock(?)", key).Exec(ctx, q); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return bob.One(ctx, q, psql.RawQuery("SELECT pg_try_advisory_xact_lock(?)", key), scan.SingleColumnMapper[bool])
+}
+
+func (l *PostgresLocker) key(name string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(l.prefix))
+	_, _ = h.Write([]byte(name))
+	return int64(h.Sum64())
+}
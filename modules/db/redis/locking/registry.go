@@ -0,0 +1,246 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// JobDefinition declares a background job with its defaults. Definitions carry
+	// no task, so the set of jobs can be listed without building their dependencies;
+	// tasks are attached with Registry.Bind.
+	JobDefinition struct {
+		Name        string
+		Description string
+		// Schedule is the default schedule, a cron expression, macro or "@every <duration>".
+		Schedule string
+		// Lock holds the default lock durations; its Name is always the job name.
+		Lock LockConfiguration
+		// Off jobs only run when listed in JobsConfig.Enabled.
+		Off bool
+		// Requires describes the configuration the job depends on, e.g.
+		// "RATE_LIMIT_STORE=postgres". Its task is only bound when that holds.
+		Requires string
+		// Group optionally names the ConcurrencyGroup the job belongs to.
+		Group string
+	}
+
+	// JobsConfig enables, disables and reschedules registered jobs by name.
+	JobsConfig struct {
+		// Enabled turns on jobs defined as Off.
+		Enabled []string `env:"ENABLED" envSeparator:","`
+		// Disabled turns jobs off; it wins over Enabled.
+		Disabled []string `env:"DISABLED" envSeparator:","`
+		// Schedules overrides default schedules, as "<name>=<schedule>" entries
+		// separated by ";" since cron lists use commas:
+		//
+		//	JOBS_SCHEDULES="profile.stats.refresh=@every 10m;profile.audit.purge=0 3 * * *"
+		Schedules []string `env:"SCHEDULES" envSeparator:";"`
+		// Groups overrides the limits of concurrency groups, as "<group>=<limit>":
+		//
+		//	JOBS_GROUPS="maintenance=2"
+		Groups []string `env:"GROUPS" envSeparator:","`
+	}
+
+	// PlannedJob is a job definition resolved against a JobsConfig.
+	PlannedJob struct {
+		JobDefinition
+		Enabled bool
+		// Bound reports whether a task was attached (always false outside the server).
+		Bound bool
+		// ScheduleSpec is the effective schedule; Overridden when it comes from config.
+		ScheduleSpec string
+		Overridden   bool
+		Schedule     Schedule
+	}
+
+	// Registry is the declarative list of a service's background jobs:
+	//
+	//	reg, _ := locking.NewRegistry(defs...)
+	//	reg.Bind("profile.stats.refresh", refresher.Refresh)
+	//	reg.AddTo(scheduler, cfg.Jobs)
+	Registry struct {
+		defs  map[string]JobDefinition
+		names []string
+		tasks map[string]TaskFunc
+	}
+)
+
+// NewRegistry constructs a Registry holding defs.
+func NewRegistry(defs ...JobDefinition) (*Registry, error) {
+	r := &Registry{
+		defs:  make(map[string]JobDefinition, len(defs)),
+		tasks: make(map[string]TaskFunc, len(defs)),
+	}
+	for _, d := range defs {
+		if err := r.Define(d); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Define adds a job definition. Its default schedule and lock are validated right away.
+func (r *Registry) Define(d JobDefinition) error {
+	d.Lock.Name = d.Name
+	if err := validateConfig(d.Lock); err != nil {
+		return err
+	}
+	if _, err := ParseCron(d.Schedule); err != nil {
+		return fmt.Errorf("locking: job %q: %w", d.Name, err)
+	}
+	if _, ok := r.defs[d.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateJob, d.Name)
+	}
+	r.defs[d.Name] = d
+	r.names = append(r.names, d.Name)
+	return nil
+}
+
+// Bind attaches the task of a defined job.
+func (r *Registry) Bind(name string, task TaskFunc) error {
+	if _, ok := r.defs[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownJob, name)
+	}
+	if task == nil {
+		return fmt.Errorf("locking: job %q: task must not be nil", name)
+	}
+	if _, ok := r.tasks[name]; ok {
+		return fmt.Errorf("%w: %q bound twice", ErrDuplicateJob, name)
+	}
+	r.tasks[name] = task
+	return nil
+}
+
+// Plan resolves every definition against cfg, in definition order.
+//
+// Names in cfg that match no definition are an error, so a typo does not silently
+// leave a job running.
+func (r *Registry) Plan(cfg JobsConfig) ([]PlannedJob, error) {
+	schedules := make(map[string]string, len(cfg.Schedules))
+	for _, entry := range cfg.Schedules {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q: want <name>=<schedule>", ErrInvalidSchedule, entry)
+		}
+		schedules[strings.TrimSpace(name)] = strings.TrimSpace(spec)
+	}
+
+	var errs []error
+	for _, names := range [][]string{cfg.Enabled, cfg.Disabled, slices.Sorted(maps.Keys(schedules))} {
+		for _, name := range names {
+			if _, ok := r.defs[strings.TrimSpace(name)]; !ok && strings.TrimSpace(name) != "" {
+				errs = append(errs, fmt.Errorf("%w: %q in jobs config", ErrUnknownJob, name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	out := make([]PlannedJob, 0, len(r.names))
+	for _, name := range r.names {
+		d := r.defs[name]
+		p := PlannedJob{
+			JobDefinition: d,
+			Enabled:       (!d.Off || containsName(cfg.Enabled, name)) && !containsName(cfg.Disabled, name),
+			ScheduleSpec:  d.Schedule,
+		}
+		_, p.Bound = r.tasks[name]
+		if spec, ok := schedules[name]; ok {
+			p.ScheduleSpec, p.Overridden = spec, true
+		}
+		s, err := ParseCron(p.ScheduleSpec)
+		if err != nil {
+			return nil, fmt.Errorf("locking: job %q: %w", name, err)
+		}
+		p.Schedule = s
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// AddTo adds every enabled, bound job to s with its effective schedule.
+// Jobs left out are logged with the reason.
+func (r *Registry) AddTo(s *Scheduler, cfg JobsConfig) error {
+	plan, err := r.Plan(cfg)
+	if err != nil {
+		return err
+	}
+	for _, p := range plan {
+		switch {
+		case !p.Enabled:
+			s.logger.Info("locking: job disabled", slog.String("job", p.Name))
+			continue
+		case !p.Bound:
+			s.logger.Debug("locking: job not bound", slog.String("job", p.Name), slog.String("requires", p.Requires))
+			continue
+		}
+		if err := s.Add(Job{Lock: p.Lock, Schedule: p.Schedule, Task: r.tasks[p.Name], Group: p.Group}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ConcurrencyGroups returns defaults with the limits overridden by c.Groups.
+// Overrides of undeclared groups are an error, like unknown job names.
+func (c JobsConfig) ConcurrencyGroups(defaults ...ConcurrencyGroup) ([]ConcurrencyGroup, error) {
+	out := slices.Clone(defaults)
+	for _, entry := range c.Groups {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		name, limit, ok := strings.Cut(entry, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(limit))
+		if !ok || err != nil || n <= 0 {
+			return nil, fmt.Errorf("%w: %q: want <group>=<positive limit>", ErrInvalidConfiguration, entry)
+		}
+		i := slices.IndexFunc(out, func(g ConcurrencyGroup) bool { return g.Name == strings.TrimSpace(name) })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown concurrency group %q in jobs config", ErrInvalidConfiguration, name)
+		}
+		out[i].Limit = n
+	}
+	return out, nil
+}
+
+// NextRuns returns the next n activations of p after from, for listings.
+func (p PlannedJob) NextRuns(from time.Time, n int) []time.Time {
+	out := make([]time.Time, 0, n)
+	for t := from; len(out) < n; {
+		t = p.Schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+func containsName(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(n string) bool { return strings.TrimSpace(n) == name })
+}
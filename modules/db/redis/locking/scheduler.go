@@ -0,0 +1,382 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDuplicateJob is returned when a job name is registered twice.
+	ErrDuplicateJob = errors.New("locking: duplicate job")
+	// ErrUnknownJob is returned by Pause and Resume for names that were never added.
+	ErrUnknownJob = errors.New("locking: unknown job")
+	// ErrSchedulerStarted is returned when jobs are added or Start is called after Start.
+	ErrSchedulerStarted = errors.New("locking: scheduler already started")
+)
+
+type (
+	// Job is a task run on a schedule under the distributed lock described by Lock.
+	// Lock.Name doubles as the job name.
+	Job struct {
+		Lock     LockConfiguration
+		Schedule Schedule
+		Task     TaskFunc
+		// Group optionally names the ConcurrencyGroup the job belongs to.
+		Group string
+	}
+
+	// ConcurrencyGroup caps how many jobs tagged with Name run at once, fleet-wide:
+	// a job holding its lock waits for one of Limit permits of the group before its
+	// task starts. See WithConcurrencyGroups.
+	ConcurrencyGroup struct {
+		Name  string
+		Limit int
+	}
+
+	// JobStatus is a point-in-time view of a registered job.
+	JobStatus struct {
+		Name    string
+		Group   string
+		Paused  bool
+		Running bool
+		LastRun time.Time
+		LastErr error
+		NextRun time.Time
+	}
+
+	// Scheduler runs jobs through a LockingTaskExecutor, replacing hand-rolled
+	// ticker loops:
+	//
+	//	s := locking.NewScheduler(exec)
+	//	s.Add(locking.Job{Lock: cfg, Schedule: locking.MustParseCron("*/5 * * * *"), Task: refresh})
+	//	s.Start(ctx)
+	//	defer s.Stop(shutdownCtx)
+	//
+	// Each job gets its own goroutine and never overlaps with itself on this node;
+	// the lock keeps other nodes out. A tick that finds the lock held elsewhere or the
+	// backend degraded is skipped quietly, other failures are logged and the job
+	// keeps its schedule.
+	Scheduler struct {
+		exec   *LockingTaskExecutor
+		logger *slog.Logger
+		now    clock
+
+		// optional, see WithConcurrencyGroups
+		permits Permits
+		groups  map[string]int
+
+		mu      sync.Mutex
+		jobs    map[string]*scheduledJob
+		names   []string
+		started bool
+
+		// cancels the scheduling loops; running tasks keep taskCtx
+		stopLoops context.CancelFunc
+		// cancels running tasks once the drain deadline passes
+		cancelTasks context.CancelFunc
+		wg          sync.WaitGroup
+	}
+
+	// SchedulerOption configures a Scheduler.
+	SchedulerOption func(*Scheduler)
+
+	scheduledJob struct {
+		Job
+
+		paused  bool
+		running bool
+		lastRun time.Time
+		lastErr error
+		nextRun time.Time
+
+		// wakes the loop on Resume
+		resume chan struct{}
+	}
+)
+
+// WithSchedulerLogger configures structured logging. Defaults to slog.Default().
+func WithSchedulerLogger(l *slog.Logger) SchedulerOption {
+	return func(s *Scheduler) {
+		if l != nil {
+			s.logger = l
+		}
+	}
+}
+
+// WithSchedulerClock overrides the time source used to compute the next activation.
+func WithSchedulerClock(fn clock) SchedulerOption {
+	return func(s *Scheduler) {
+		if fn != nil {
+			s.now = fn
+		}
+	}
+}
+
+// WithConcurrencyGroups declares groups whose jobs share permits from p, usually a
+// *Semaphore; each group is a semaphore named "group:<name>".
+//
+// A job waits for a permit while holding its own lock, so the other nodes skip
+// the run as usual instead of running it once a permit frees up. The wait counts
+// against LockAtMostFor and ends early on Stop.
+func WithConcurrencyGroups(p Permits, groups ...ConcurrencyGroup) SchedulerOption {
+	return func(s *Scheduler) {
+		if p == nil {
+			return
+		}
+		s.permits = p
+		for _, g := range groups {
+			s.groups[g.Name] = g.Limit
+		}
+	}
+}
+
+// NewScheduler constructs a Scheduler executing jobs through exec.
+func NewScheduler(exec *LockingTaskExecutor, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		exec:   exec,
+		logger: slog.Default(),
+		now:    defaultClock,
+		jobs:   make(map[string]*scheduledJob),
+		groups: make(map[string]int),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(job Job) error {
+	if job.Task == nil {
+		return errors.New("locking: job task must not be nil")
+	}
+	if job.Schedule == nil {
+		return fmt.Errorf("%w: job %q has no schedule", ErrInvalidSchedule, job.Lock.Name)
+	}
+	if err := validateConfig(job.Lock); err != nil {
+		return err
+	}
+	if limit, ok := s.groups[job.Group]; job.Group != "" && (!ok || limit <= 0) {
+		return fmt.Errorf("%w: job %q: concurrency group %q is not declared with a positive limit", ErrInvalidConfiguration, job.Lock.Name, job.Group)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return fmt.Errorf("%w: cannot add %q", ErrSchedulerStarted, job.Lock.Name)
+	}
+	if _, ok := s.jobs[job.Lock.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateJob, job.Lock.Name)
+	}
+	s.jobs[job.Lock.Name] = &scheduledJob{Job: job, resume: make(chan struct{}, 1)}
+	s.names = append(s.names, job.Lock.Name)
+	return nil
+}
+
+// Start launches one goroutine per job and returns immediately.
+//
+// Loops end when ctx is cancelled or Stop is called; tasks already running are
+// not interrupted by either, see Stop.
+func (s *Scheduler) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return ErrSchedulerStarted
+	}
+	s.started = true
+
+	loopCtx, stopLoops := context.WithCancel(ctx)
+	taskCtx, cancelTasks := context.WithCancel(context.WithoutCancel(ctx))
+	s.stopLoops, s.cancelTasks = stopLoops, cancelTasks
+
+	for _, name := range s.names {
+		j := s.jobs[name]
+		s.wg.Go(func() { s.loop(loopCtx, taskCtx, j) })
+	}
+	return nil
+}
+
+// Stop stops scheduling new runs and waits for running tasks to finish.
+//
+// When ctx expires first, running tasks get their context cancelled, Stop waits for
+// them to return and reports ctx.Err(). Stop is safe to call without Start.
+func (s *Scheduler) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	stopLoops, cancelTasks := s.stopLoops, s.cancelTasks
+	s.mu.Unlock()
+	if stopLoops == nil {
+		return nil
+	}
+	stopLoops()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		cancelTasks()
+		return nil
+	case <-ctx.Done():
+		cancelTasks()
+		<-done
+		return fmt.Errorf("locking: scheduler drain: %w", ctx.Err())
+	}
+}
+
+// Pause skips upcoming runs of a job until Resume. A run already in progress completes.
+func (s *Scheduler) Pause(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownJob, name)
+	}
+	j.paused = true
+	return nil
+}
+
+// Resume re-enables a paused job; it next runs at its following activation.
+func (s *Scheduler) Resume(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownJob, name)
+	}
+	if j.paused {
+		j.paused = false
+		select {
+		case j.resume <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Jobs returns the status of every job in registration order.
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]JobStatus, 0, len(s.names))
+	for _, name := range s.names {
+		j := s.jobs[name]
+		out = append(out, JobStatus{
+			Name:    name,
+			Group:   j.Group,
+			Paused:  j.paused,
+			Running: j.running,
+			LastRun: j.lastRun,
+			LastErr: j.lastErr,
+			NextRun: j.nextRun,
+		})
+	}
+	return out
+}
+
+func (s *Scheduler) loop(loopCtx, taskCtx context.Context, j *scheduledJob) {
+	name := j.Lock.Name
+	task := j.Task
+	if j.Group != "" {
+		task = s.grouped(loopCtx, j.Group, task)
+	}
+	for {
+		next := j.Schedule.Next(s.now())
+		if next.IsZero() {
+			s.logger.WarnContext(loopCtx, "locking: job schedule exhausted", slog.String("job", name))
+			return
+		}
+		s.mu.Lock()
+		j.nextRun = next
+		s.mu.Unlock()
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-loopCtx.Done():
+			timer.Stop()
+			return
+		case <-j.resume:
+			// recompute from now so a long pause does not fire a backlog of runs
+			timer.Stop()
+			continue
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		if j.paused {
+			s.mu.Unlock()
+			continue
+		}
+		j.running = true
+		s.mu.Unlock()
+
+		start := s.now()
+		err := s.exec.Execute(taskCtx, j.Lock, task)
+
+		s.mu.Lock()
+		j.running = false
+		j.lastRun = start
+		j.lastErr = err
+		s.mu.Unlock()
+
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrLockNotAcquired), errors.Is(err, ErrBackendDegraded):
+			s.logger.DebugContext(loopCtx, "locking: job run skipped", slog.String("job", name), slog.Any("reason", err))
+		case taskCtx.Err() != nil:
+			// cancelled by an expired drain deadline
+			return
+		case loopCtx.Err() != nil && errors.Is(err, context.Canceled):
+			// stopped while waiting for a group permit
+			return
+		default:
+			s.logger.ErrorContext(loopCtx, "locking: job failed", slog.String("job", name), slog.Any("error", err))
+		}
+	}
+}
+
+// grouped runs task under a permit of group. Waiting for the permit ends with
+// loopCtx, the task itself only with ctx or the loss of the permit.
+func (s *Scheduler) grouped(loopCtx context.Context, group string, task TaskFunc) TaskFunc {
+	limit := s.groups[group]
+	return func(ctx context.Context) error {
+		waitCtx, cancelWait := context.WithCancel(ctx)
+		defer cancelWait()
+		stopWait := context.AfterFunc(loopCtx, cancelWait)
+		permitCtx, release, err := s.permits.Acquire(waitCtx, "group:"+group, limit)
+		// the permit lives on waitCtx, which from now on only ends with ctx
+		stopWait()
+		if err != nil {
+			return fmt.Errorf("locking: concurrency group %q: %w", group, err)
+		}
+		defer release()
+
+		runCtx, cancelRun := context.WithCancel(ctx)
+		defer cancelRun()
+		stopLost := context.AfterFunc(permitCtx, cancelRun)
+		defer stopLost()
+		return task(runCtx)
+	}
+}
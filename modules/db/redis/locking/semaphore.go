@@ -0,0 +1,264 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locking
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/redis/rueidis"
+)
+
+var (
+	_ Permits = (*Semaphore)(nil)
+
+	//go:embed semaphore_acquire.lua
+	semaphoreAcquireLua string
+	//go:embed semaphore_renew.lua
+	semaphoreRenewLua string
+
+	// Lua scripts for permits
+	// - KEYS[1] = holders zset scored by lease expiry
+	// - acquire: ARGV[1] = holder, ARGV[2] = permits, ARGV[3] = lease ms
+	// - renew:   ARGV[1] = holder, ARGV[2] = lease ms
+	//
+	// Both purge expired holders first and read the time from redis, so node
+	// clocks do not have to agree.
+	luaSemaphoreAcquire = rueidis.NewLuaScript(semaphoreAcquireLua)
+	luaSemaphoreRenew   = rueidis.NewLuaScript(semaphoreRenewLua)
+)
+
+type (
+	// Permits is a counting lock: up to permits holders of name at a time.
+	// The returned context is cancelled when the permit is lost, the returned
+	// cancel func releases it. *Semaphore implements it, see WithMaxConcurrency.
+	Permits interface {
+		// Acquire blocks until a permit is free or ctx is done.
+		Acquire(ctx context.Context, name string, permits int) (context.Context, context.CancelFunc, error)
+		// TryAcquire takes a permit once, failing with ErrLockNotAcquired when none is free.
+		TryAcquire(ctx context.Context, name string, permits int) (context.Context, context.CancelFunc, error)
+	}
+
+	// Semaphore is a Redis-backed distributed counting semaphore.
+	//
+	// Keys used:
+	//   - {prefix}{name} ZSET of holder ids scored by lease expiry
+	//
+	// A holder renews its lease every third of the lease; a node that dies
+	// gives its permit back once the lease expires.
+	Semaphore struct {
+		client rueidis.Client
+		prefix string
+		node   string
+
+		lease         time.Duration
+		retryInterval time.Duration
+	}
+
+	// SemaphoreOption configures a Semaphore.
+	SemaphoreOption func(*Semaphore)
+)
+
+// WithSemaphoreKeyPrefix scopes semaphore keys under a prefix (env, service, etc).
+func WithSemaphoreKeyPrefix(prefix string) SemaphoreOption {
+	return func(s *Semaphore) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, ":") {
+			prefix += ":"
+		}
+		s.prefix = prefix
+	}
+}
+
+// WithLease sets how long a permit survives without renewal. Defaults to 30 seconds.
+func WithLease(d time.Duration) SemaphoreOption {
+	return func(s *Semaphore) {
+		if d > 0 {
+			s.lease = d
+		}
+	}
+}
+
+// WithRetryInterval sets how often a blocked Acquire retries. Defaults to 500ms.
+func WithRetryInterval(d time.Duration) SemaphoreOption {
+	return func(s *Semaphore) {
+		if d > 0 {
+			s.retryInterval = d
+		}
+	}
+}
+
+// NewSemaphore constructs a Semaphore on top of an existing rueidis.Client.
+func NewSemaphore(client rueidis.Client, opts ...SemaphoreOption) *Semaphore {
+	s := &Semaphore{
+		client:        client,
+		node:          defaultNodeID(),
+		lease:         30 * time.Second,
+		retryInterval: 500 * time.Millisecond,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+func (s *Semaphore) key(name string) string { return s.prefix + name }
+
+// TryAcquire implements Permits.
+func (s *Semaphore) TryAcquire(ctx context.Context, name string, permits int) (context.Context, context.CancelFunc, error) {
+	holder, err := s.holderID()
+	if err != nil {
+		return nil, nil, err
+	}
+	ok, err := s.take(ctx, name, holder, permits)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		return nil, nil, ErrLockNotAcquired
+	}
+	return s.hold(ctx, name, holder)
+}
+
+// Acquire implements Permits.
+func (s *Semaphore) Acquire(ctx context.Context, name string, permits int) (context.Context, context.CancelFunc, error) {
+	holder, err := s.holderID()
+	if err != nil {
+		return nil, nil, err
+	}
+	ticker := time.NewTicker(s.retryInterval)
+	defer ticker.Stop()
+	for {
+		ok, err := s.take(ctx, name, holder, permits)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ok {
+			return s.hold(ctx, name, holder)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Semaphore) holderID() (string, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return "", fmt.Errorf("locking: generate holder id: %w", err)
+	}
+	return s.node + ":" + id.String(), nil
+}
+
+func (s *Semaphore) take(ctx context.Context, name, holder string, permits int) (bool, error) {
+	if permits <= 0 {
+		return false, fmt.Errorf("%w: permits must be positive", ErrInvalidConfiguration)
+	}
+	got, err := luaSemaphoreAcquire.Exec(ctx, s.client,
+		[]string{s.key(name)},
+		[]string{holder, strconv.Itoa(permits), strconv.FormatInt(s.lease.Milliseconds(), 10)},
+	).AsInt64()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		return false, fmt.Errorf("locking: acquire permit %q: %w", name, err)
+	}
+	return got == 1, nil
+}
+
+// hold renews the lease until released or ctx is done, cancelling the returned
+// context when ctx is, or when the permit is lost or cannot be renewed before the
+// lease runs out.
+func (s *Semaphore) hold(ctx context.Context, name, holder string) (context.Context, context.CancelFunc, error) {
+	// shutdown and the caller's cancellation reach the task holding the permit
+	permitCtx, lost := context.WithCancel(ctx)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		defer lost()
+
+		interval := s.lease / 3
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		renewedAt := time.Now()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-permitCtx.Done():
+				// the permit expires with its lease unless released first
+				return
+			case <-ticker.C:
+			}
+			renewCtx, cancel := context.WithTimeout(permitCtx, interval)
+			got, err := luaSemaphoreRenew.Exec(renewCtx, s.client,
+				[]string{s.key(name)},
+				[]string{holder, strconv.FormatInt(s.lease.Milliseconds(), 10)},
+			).AsInt64()
+			cancel()
+			switch {
+			case err == nil && got == 1:
+				renewedAt = time.Now()
+			case err == nil:
+				// expired and taken over by someone else
+				return
+			case time.Since(renewedAt) >= s.lease:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			close(stop)
+			<-done
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+			defer cancel()
+			// best effort: an unreleased permit expires with its lease
+			_ = s.client.Do(releaseCtx, s.client.B().Zrem().Key(s.key(name)).Member(holder).Build()).Error()
+		})
+	}
+	return permitCtx, release, nil
+}
+
+// permitLocker adapts Permits to the Locker the executor runs on.
+type permitLocker struct {
+	permits Permits
+	n       int
+}
+
+func (l permitLocker) WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.permits.Acquire(ctx, name, l.n)
+}
+
+func (l permitLocker) TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.permits.TryAcquire(ctx, name, l.n)
+}
+
+func (permitLocker) Close() {}
@@ -0,0 +1,39 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Renew the lease of a permit holder.
+-- KEYS[1] = holders zset, scored by lease expiry (unix ms, redis time)
+-- ARGV[1] = holder id
+-- ARGV[2] = lease in milliseconds
+--
+-- Returns 1 when the lease was renewed, 0 when the holder lost its permit.
+
+local key = KEYS[1]
+local holder = ARGV[1]
+local lease_ms = tonumber(ARGV[2])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call("ZREMRANGEBYSCORE", key, "-inf", now)
+
+if not redis.call("ZSCORE", key, holder) then
+  return 0
+end
+
+redis.call("ZADD", key, "XX", now + lease_ms, holder)
+if redis.call("PTTL", key) < lease_ms then
+  redis.call("PEXPIRE", key, lease_ms)
+end
+return 1
@@ -0,0 +1,63 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/hmac"
+	"app/modules/httpsig"
+
+	"github.com/redis/rueidis"
+)
+
+var (
+	_ hmac.NonceStore    = (*NonceCache)(nil)
+	_ httpsig.NonceStore = (*NonceCache)(nil)
+)
+
+// NonceCache is a hmac.NonceStore and httpsig.NonceStore keeping each nonce as a
+// key with SET NX EX, so concurrent deliveries of the same request agree on a
+// single winner.
+type NonceCache struct {
+	client rueidis.Client
+	prefix string
+}
+
+// NewNonceCache constructs a NonceCache storing nonces under prefix.
+func NewNonceCache(client rueidis.Client, prefix string) *NonceCache {
+	prefix = strings.TrimSpace(prefix)
+	if prefix != "" && !strings.HasSuffix(prefix, ":") {
+		prefix += ":"
+	}
+	return &NonceCache{client: client, prefix: prefix}
+}
+
+// Remember implements hmac.NonceStore and httpsig.NonceStore.
+func (c *NonceCache) Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error) {
+	cmd := c.client.B().Set().Key(c.prefix + nonce).Value("1").Nx().Ex(ttl).Build()
+	err := c.client.Do(ctx, cmd).Error()
+	if rueidis.IsRedisNil(err) {
+		// NX did not set: the nonce was already there
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("redis: remember nonce: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,284 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"app/modules/retry"
+
+	"github.com/redis/rueidis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const pubSubInstrumentationName = "app/modules/db/redis/pubsub"
+
+// ErrPubSubStarted is returned by Subscribe once the PubSub is running.
+var ErrPubSubStarted = errors.New("redis pubsub: already started")
+
+type (
+	// MessageHandler processes a message received on channel (without the channel
+	// prefix). Its error is logged and recorded on the message span.
+	MessageHandler func(ctx context.Context, channel string, payload []byte) error
+
+	// PubSub publishes messages and dispatches the messages of its subscribed
+	// channels to their handlers.
+	//
+	// Subscriptions hold a connection of their own, separate from the pipelined
+	// one commands use. When it breaks, PubSub subscribes again with backoff.
+	// Redis does not keep messages for disconnected subscribers: whatever is
+	// published in the meantime is lost, so pair invalidations with TTLs.
+	//
+	// Handlers run one at a time, in the order messages arrive. Keep them quick,
+	// a slow handler holds up every message after it.
+	PubSub struct {
+		client rueidis.Client
+		tracer trace.Tracer
+
+		prefix  string
+		backoff retry.Policy
+
+		mu       sync.Mutex
+		handlers map[string]MessageHandler
+		cancel   context.CancelFunc
+		done     chan struct{}
+	}
+
+	// PubSubOption configures PubSub.
+	PubSubOption func(*PubSub)
+)
+
+// WithChannelPrefix scopes all channels under a prefix (env, service, etc).
+func WithChannelPrefix(prefix string) PubSubOption {
+	return func(p *PubSub) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, ":") {
+			prefix += ":"
+		}
+		p.prefix = prefix
+	}
+}
+
+// WithResubscribeBackoff spaces out the attempts to subscribe again after the
+// subscription broke: attempt n waits b.Delay(n). Defaults to 100ms doubling up
+// to 10s.
+func WithResubscribeBackoff(b retry.Policy) PubSubOption {
+	return func(p *PubSub) {
+		p.backoff = b
+	}
+}
+
+// NewPubSub constructs a PubSub on top of an existing rueidis.Client.
+func NewPubSub(client rueidis.Client, opts ...PubSubOption) *PubSub {
+	p := &PubSub{
+		client:   client,
+		tracer:   otel.Tracer(pubSubInstrumentationName),
+		backoff:  retry.Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: 10 * time.Second},
+		handlers: make(map[string]MessageHandler),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
+	}
+	return p
+}
+
+// Handle adapts a handler of JSON messages of type T into a MessageHandler.
+// Payloads that do not decode into T are reported as errors, fn is not called.
+func Handle[T any](fn func(ctx context.Context, channel string, msg T) error) MessageHandler {
+	return func(ctx context.Context, channel string, payload []byte) error {
+		var msg T
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return fmt.Errorf("redis pubsub: decode message on %q: %w", channel, err)
+		}
+		return fn(ctx, channel, msg)
+	}
+}
+
+// Subscribe registers h for the messages of channel. It must be called before
+// Start; subscribing to a channel twice replaces its handler.
+func (p *PubSub) Subscribe(channel string, h MessageHandler) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return fmt.Errorf("%w: cannot subscribe to %q", ErrPubSubStarted, channel)
+	}
+	if h == nil {
+		return fmt.Errorf("redis pubsub: nil handler for %q", channel)
+	}
+	p.handlers[p.prefix+channel] = h
+	return nil
+}
+
+// Publish sends value on channel and returns how many subscribers received it.
+// Values are serialized like RedisKV.AtomicSet: strings and bytes as-is,
+// anything else as JSON.
+func (p *PubSub) Publish(ctx context.Context, channel string, value any) (int64, error) {
+	payload, err := encodeValue(value)
+	if err != nil {
+		return 0, fmt.Errorf("redis pubsub: encode message for %q: %w", channel, err)
+	}
+
+	ctx, span := p.tracer.Start(ctx, "publish "+channel,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(p.spanAttrs(channel, len(payload), semconv.MessagingOperationTypePublish)...),
+	)
+	defer span.End()
+
+	receivers, err := p.client.Do(ctx, p.client.B().Publish().Channel(p.prefix+channel).Message(payload).Build()).AsInt64()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return 0, fmt.Errorf("redis pubsub: publish on %q: %w", channel, err)
+	}
+	return receivers, nil
+}
+
+// Start subscribes to the registered channels in the background. It returns
+// right away; Stop ends the subscription. Start without any subscription only
+// marks the PubSub as started.
+func (p *PubSub) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		return ErrPubSubStarted
+	}
+
+	// the subscription outlives the start context, Stop ends it
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	p.cancel = cancel
+	p.done = make(chan struct{})
+
+	channels := make([]string, 0, len(p.handlers))
+	for ch := range p.handlers {
+		channels = append(channels, ch)
+	}
+	slices.Sort(channels)
+	if len(channels) == 0 {
+		close(p.done)
+		return nil
+	}
+
+	go func() {
+		defer close(p.done)
+		p.run(ctx, channels)
+	}()
+	return nil
+}
+
+// Stop unsubscribes and waits for the handler in progress, if any, to return.
+func (p *PubSub) Stop(ctx context.Context) error {
+	p.mu.Lock()
+	cancel, done := p.cancel, p.done
+	p.mu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run keeps the subscription up until ctx is cancelled.
+func (p *PubSub) run(ctx context.Context, channels []string) {
+	failures := 0
+	for {
+		begin := time.Now()
+		cmd := p.client.B().Subscribe().Channel(channels...).Build()
+		err := p.client.Receive(ctx, cmd, func(msg rueidis.PubSubMessage) {
+			p.dispatch(ctx, msg)
+		})
+		if ctx.Err() != nil || errors.Is(err, rueidis.ErrClosing) {
+			return
+		}
+
+		// a subscription that held for a while starts over with a short wait
+		if time.Since(begin) > p.backoff.MaxDelay {
+			failures = 0
+		}
+		failures++
+		delay := p.backoff.Delay(failures)
+		slog.WarnContext(ctx, "redis pubsub: subscription lost, resubscribing",
+			slog.Int("channels", len(channels)),
+			slog.Duration("backoff", delay),
+			slog.Any("error", err),
+		)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (p *PubSub) dispatch(ctx context.Context, msg rueidis.PubSubMessage) {
+	h := p.handlers[msg.Channel]
+	if h == nil {
+		return
+	}
+	channel := strings.TrimPrefix(msg.Channel, p.prefix)
+
+	// publishers do not propagate a trace, every message starts its own
+	ctx, span := p.tracer.Start(ctx, "process "+channel,
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(p.spanAttrs(channel, len(msg.Message), semconv.MessagingOperationTypeDeliver)...),
+	)
+	defer span.End()
+
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		return h(ctx, channel, []byte(msg.Message))
+	}()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		slog.ErrorContext(ctx, "redis pubsub: message handler failed",
+			slog.String("channel", channel),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func (p *PubSub) spanAttrs(channel string, size int, op attribute.KeyValue) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("redis"),
+		semconv.MessagingDestinationName(p.prefix + channel),
+		semconv.MessagingMessageBodySize(size),
+		op,
+	}
+}
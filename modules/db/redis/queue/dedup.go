@@ -0,0 +1,91 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+var (
+	// ErrDuplicateJob is returned when a job with the same DedupKey was already
+	// enqueued within the dedup window. The returned Job carries the ID of the
+	// job that owns the key, so callers can treat this as success.
+	ErrDuplicateJob = errors.New("queue: duplicate job")
+
+	//go:embed enqueue_dedup.lua
+	enqueueDedupLua string
+
+	// Lua script for deduplicated enqueue
+	// - KEYS[1] = dedup key
+	// - KEYS[2] = ready list / delayed zset
+	// - ARGV[1] = job id, ARGV[2] = window ms, ARGV[3] = encoded job, ARGV[4] = score or ""
+	// Atomically:
+	// - SET dedup id NX PX window; on conflict return the owning id
+	// - otherwise LPUSH / ZADD the job and return its id
+	//
+	// Claiming the key and pushing the job in one script means a failed push
+	// never leaves a dedup key behind that would swallow retries.
+	luaEnqueueDedup = rueidis.NewLuaScript(enqueueDedupLua)
+)
+
+// DefaultDedupWindow is how long a dedup key collapses repeated enqueues when not configured.
+const DefaultDedupWindow = 5 * time.Minute
+
+// WithDedupWindow sets how long a DedupKey suppresses further enqueues of the same logical job.
+func WithDedupWindow(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.dedupWindow = d
+		}
+	}
+}
+
+func (q *Queue) dedupKey(key string) string { return q.key("dedup:" + key) }
+
+// enqueueDedup pushes an already prepared job onto target unless its DedupKey is taken.
+// score is the ready-at unix ms for delayed jobs, or empty for immediate ones.
+func (q *Queue) enqueueDedup(ctx context.Context, job Job, encoded, target, score string) (Job, error) {
+	windowMs := q.dedupWindow.Milliseconds()
+	if windowMs <= 0 {
+		windowMs = DefaultDedupWindow.Milliseconds()
+	}
+
+	res := luaEnqueueDedup.Exec(ctx, q.client,
+		[]string{q.dedupKey(job.DedupKey), target},
+		[]string{job.ID, strconv.FormatInt(windowMs, 10), encoded, score},
+	)
+	owner, err := res.ToString()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			// cannot happen: the script runs atomically, so the key SET NX refused is
+			// still there for GET. Treat it as the other job having won.
+			return job, ErrDuplicateJob
+		}
+		return Job{}, fmt.Errorf("queue %q: enqueue dedup: %w", q.name, err)
+	}
+
+	if owner != job.ID {
+		job.ID = owner
+		return job, ErrDuplicateJob
+	}
+	return job, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"app/modules/db/redis/locking"
+
+	"github.com/redis/rueidis"
+)
+
+var (
+	//go:embed promote_delayed.lua
+	promoteDelayedLua string
+
+	// Lua script for promoting due delayed jobs
+	// - KEYS[1] = delayed zset
+	// - KEYS[2] = ready list
+	// - ARGV[1] = now in unix milliseconds
+	// - ARGV[2] = batch size
+	// Atomically:
+	// - due = ZRANGEBYSCORE delayed -inf now LIMIT 0 batch
+	// - ZREM each due member and LPUSH it onto ready
+	luaPromoteDelayed = rueidis.NewLuaScript(promoteDelayedLua)
+)
+
+// DefaultPromoteBatch bounds how many jobs one pump tick moves, keeping each script call short.
+const DefaultPromoteBatch = 100
+
+func (q *Queue) delayedKey() string { return q.key("delayed") }
+
+// EnqueueAt schedules a job to become ready at the given time.
+//
+// A time that is not in the future enqueues the job immediately.
+// Delayed jobs only become visible to consumers once a pump (see Promote / RunPump) moves them.
+func (q *Queue) EnqueueAt(ctx context.Context, job Job, at time.Time) (Job, error) {
+	if !at.After(q.clock.Now()) {
+		return q.Enqueue(ctx, job)
+	}
+
+	job, encoded, err := q.prepare(job)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if job.DedupKey != "" {
+		return q.enqueueDedup(ctx, job, encoded, q.delayedKey(), strconv.FormatInt(at.UnixMilli(), 10))
+	}
+
+	cmd := q.client.B().Zadd().Key(q.delayedKey()).ScoreMember().
+		ScoreMember(float64(at.UnixMilli()), encoded).Build()
+	if err := q.client.Do(ctx, cmd).Error(); err != nil {
+		return Job{}, fmt.Errorf("queue %q: enqueue delayed: %w", q.name, err)
+	}
+	return job, nil
+}
+
+// EnqueueIn schedules a job to become ready after delay d.
+//
+//	q.EnqueueIn(ctx, queue.Job{Type: "profile.verification_reminder", Payload: p}, 24*time.Hour)
+func (q *Queue) EnqueueIn(ctx context.Context, job Job, d time.Duration) (Job, error) {
+	return q.EnqueueAt(ctx, job, q.clock.Now().Add(d))
+}
+
+// Promote moves up to batch due delayed jobs onto the ready list and returns how many were moved.
+func (q *Queue) Promote(ctx context.Context, batch int) (int64, error) {
+	if batch <= 0 {
+		batch = DefaultPromoteBatch
+	}
+	now := q.clock.Now().UnixMilli()
+	res := luaPromoteDelayed.Exec(ctx, q.client,
+		[]string{q.delayedKey(), q.readyKey()},
+		[]string{strconv.FormatInt(now, 10), strconv.Itoa(batch)},
+	)
+	n, err := res.AsInt64()
+	if err != nil {
+		return 0, fmt.Errorf("queue %q: promote delayed: %w", q.name, err)
+	}
+	return n, nil
+}
+
+// PumpTask returns a task that drains every currently due delayed job, batch by batch.
+// It is meant to run under a LockingTaskExecutor so only one node pumps at a time.
+func (q *Queue) PumpTask(batch int) locking.TaskFunc {
+	if batch <= 0 {
+		batch = DefaultPromoteBatch
+	}
+	return func(ctx context.Context) error {
+		for {
+			n, err := q.Promote(ctx, batch)
+			if err != nil {
+				return err
+			}
+			if n > 0 {
+				slog.DebugContext(ctx, "promoted delayed jobs", slog.String("queue", q.name), slog.Int64("count", n))
+			}
+			// a short batch means nothing else is due right now
+			if n < int64(batch) {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+// RunPump periodically promotes due delayed jobs under the distributed lock described by cfg.
+//
+// It blocks until ctx is cancelled. Ticks where another node holds the lock are skipped silently.
+func (q *Queue) RunPump(
+	ctx context.Context,
+	exec *locking.LockingTaskExecutor,
+	cfg locking.LockConfiguration,
+	interval time.Duration,
+) {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	task := q.PumpTask(DefaultPromoteBatch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := exec.Execute(ctx, cfg, task)
+			if err != nil && !errors.Is(err, locking.ErrLockNotAcquired) && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "delayed job pump failed", slog.String("queue", q.name), slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package queue provides a lightweight Redis-backed job queue with delayed execution.
+//
+// Immediate jobs go onto a LIST consumed with BRPOP. Delayed jobs are parked in a ZSET
+// scored by their ready-at timestamp and moved onto the LIST by a pump that runs under
+// the distributed locking executor, so no external scheduler is required.
+//
+// A payload that does not decode as a Job is moved to a dead-letter list,
+// {prefix}{name}:dead, and a job popped while the consumer shuts down goes back to
+// the head of the ready list:
+//
+//	q := queue.New(redisClient, "profile", queue.WithKeyPrefix("dev"))
+//	_, _ = q.EnqueueIn(ctx, queue.Job{Type: "profile.verification_reminder", Payload: payload}, 24*time.Hour)
+//
+//	go q.RunPump(ctx, lockExecutor, locking.LockConfiguration{
+//		Name:          "queue.profile.pump",
+//		LockAtMostFor: 30 * time.Second,
+//	}, time.Second)
+//
+//	// repeated enqueues of the same logical job within the dedup window collapse into one
+//	_, err := q.Enqueue(ctx, queue.Job{Type: "profile.reindex", DedupKey: "reindex:profile:" + id})
+//	if errors.Is(err, queue.ErrDuplicateJob) { /* already queued */ }
+//
+//	q.Consume(ctx, 4, func(ctx context.Context, job queue.Job) error { ... })
+package queue
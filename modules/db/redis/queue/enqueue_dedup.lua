@@ -0,0 +1,43 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Enqueue a job unless another job with the same dedup key was enqueued within the window.
+-- KEYS[1] = dedup key
+-- KEYS[2] = ready list (immediate) or delayed zset (scheduled)
+-- ARGV[1] = job id
+-- ARGV[2] = dedup window in milliseconds
+-- ARGV[3] = encoded job
+-- ARGV[4] = ready-at score for delayed jobs, empty for immediate jobs
+--
+-- Returns the id of the job that owns the dedup key (ARGV[1] when this call enqueued).
+
+local dedup = KEYS[1]
+local target = KEYS[2]
+local id = ARGV[1]
+local window_ms = ARGV[2]
+local job = ARGV[3]
+local score = ARGV[4]
+
+local claimed = redis.call("SET", dedup, id, "NX", "PX", window_ms)
+if not claimed then
+  return redis.call("GET", dedup)
+end
+
+if score == "" then
+  redis.call("LPUSH", target, job)
+else
+  redis.call("ZADD", target, score, job)
+end
+
+return id
@@ -0,0 +1,33 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Move due jobs from the delayed ZSET onto the ready LIST.
+-- KEYS[1] = delayed zset
+-- KEYS[2] = ready list
+-- ARGV[1] = now (unix ms)
+-- ARGV[2] = max jobs to promote in one call
+
+local delayed = KEYS[1]
+local ready = KEYS[2]
+local now = ARGV[1]
+local batch = tonumber(ARGV[2])
+
+local due = redis.call("ZRANGEBYSCORE", delayed, "-inf", now, "LIMIT", 0, batch)
+
+for _, member in ipairs(due) do
+  redis.call("ZREM", delayed, member)
+  redis.call("LPUSH", ready, member)
+end
+
+return #due
@@ -0,0 +1,296 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/worker"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/redis/rueidis"
+)
+
+var (
+	// ErrInvalidJob is returned when a job cannot be enqueued as given.
+	ErrInvalidJob = errors.New("queue: invalid job")
+
+	// ErrMalformedJob is returned by Dequeue for a payload that does not decode as
+	// a Job. The payload has been moved to the dead-letter list.
+	ErrMalformedJob = errors.New("queue: malformed job")
+)
+
+type (
+	// Job is the unit of work carried through the queue.
+	//
+	// Payload is opaque to the queue; handlers decide how to decode it
+	// (usually JSON keyed by Type).
+	Job struct {
+		ID         string          `json:"id"`
+		Type       string          `json:"type"`
+		Payload    json.RawMessage `json:"payload,omitempty"`
+		EnqueuedAt time.Time       `json:"enqueued_at"`
+
+		// DedupKey optionally identifies the logical job (e.g. "reindex:profile:<id>").
+		// Enqueuing another job with the same key within the dedup window is collapsed
+		// into the first one and reported as ErrDuplicateJob.
+		DedupKey string `json:"dedup_key,omitempty"`
+	}
+
+	// Handler processes a single job. Returning an error only logs the failure;
+	// the queue does not retry on its own, a handler wanting a retry enqueues the
+	// job again with EnqueueIn.
+	Handler func(ctx context.Context, job Job) error
+
+	// Queue is a Redis-backed FIFO job queue.
+	//
+	// Keys used (all share the same hash tag so Lua scripts work on Redis Cluster):
+	//   - {prefix}{name}:ready   LIST of encoded jobs ready for consumption
+	//   - {prefix}{name}:delayed ZSET of encoded jobs scored by ready-at (unix ms)
+	//   - {prefix}{name}:dedup:* STRING job id owning a dedup key, expiring with the window
+	//   - {prefix}{name}:dead    LIST of payloads that did not decode as a Job
+	Queue struct {
+		client rueidis.Client
+		clock  clock.Clock
+
+		name   string
+		prefix string
+
+		// how long a consumer blocks on BRPOP before re-checking ctx
+		pollTimeout time.Duration
+
+		// how long a DedupKey suppresses repeated enqueues
+		dedupWindow time.Duration
+	}
+
+	// Option configures a Queue.
+	Option func(*Queue)
+)
+
+// WithKeyPrefix scopes all queue keys under a prefix (env, service, etc).
+func WithKeyPrefix(prefix string) Option {
+	return func(q *Queue) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, ":") {
+			prefix += ":"
+		}
+		q.prefix = prefix
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(q *Queue) {
+		if c != nil {
+			q.clock = c
+		}
+	}
+}
+
+// WithPollTimeout sets how long a consumer blocks waiting for a job
+// before checking for cancellation again. Defaults to 5 seconds.
+func WithPollTimeout(d time.Duration) Option {
+	return func(q *Queue) {
+		if d > 0 {
+			q.pollTimeout = d
+		}
+	}
+}
+
+// New constructs a Queue named name on top of an existing rueidis.Client.
+func New(client rueidis.Client, name string, opts ...Option) *Queue {
+	q := &Queue{
+		client:      client,
+		clock:       clock.RealClock{},
+		name:        name,
+		pollTimeout: 5 * time.Second,
+		dedupWindow: DefaultDedupWindow,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(q)
+		}
+	}
+	return q
+}
+
+// keys are hash-tagged on the queue name so every key of one queue lands on the same slot.
+func (q *Queue) key(suffix string) string {
+	return q.prefix + "{" + q.name + "}:" + suffix
+}
+
+func (q *Queue) readyKey() string { return q.key("ready") }
+
+func (q *Queue) deadKey() string { return q.key("dead") }
+
+// Enqueue pushes a job for immediate consumption.
+//
+// ID and EnqueuedAt are filled in when empty. The stored job is returned.
+// Jobs carrying a DedupKey may return ErrDuplicateJob, see WithDedupWindow.
+func (q *Queue) Enqueue(ctx context.Context, job Job) (Job, error) {
+	job, encoded, err := q.prepare(job)
+	if err != nil {
+		return Job{}, err
+	}
+
+	if job.DedupKey != "" {
+		return q.enqueueDedup(ctx, job, encoded, q.readyKey(), "")
+	}
+
+	cmd := q.client.B().Lpush().Key(q.readyKey()).Element(encoded).Build()
+	if err := q.client.Do(ctx, cmd).Error(); err != nil {
+		return Job{}, fmt.Errorf("queue %q: enqueue: %w", q.name, err)
+	}
+	return job, nil
+}
+
+// Dequeue blocks up to the poll timeout for the next ready job.
+// It returns (nil, nil) when no job became available in time.
+//
+// A payload that does not decode is pushed onto the dead-letter list, and
+// ErrMalformedJob is returned.
+func (q *Queue) Dequeue(ctx context.Context) (*Job, error) {
+	cmd := q.client.B().Brpop().Key(q.readyKey()).Timeout(q.pollTimeout.Seconds()).Build()
+	// BRPOP replies with [key, element]
+	vals, err := q.client.Do(ctx, cmd).AsStrSlice()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("queue %q: dequeue: %w", q.name, err)
+	}
+	if len(vals) != 2 {
+		return nil, fmt.Errorf("queue %q: dequeue: unexpected reply length %d", q.name, len(vals))
+	}
+
+	var job Job
+	if err := json.Unmarshal([]byte(vals[1]), &job); err != nil {
+		// the payload is off the ready list already, park it where it can be inspected
+		dead := q.client.B().Lpush().Key(q.deadKey()).Element(vals[1]).Build()
+		if derr := q.client.Do(context.WithoutCancel(ctx), dead).Error(); derr != nil {
+			return nil, fmt.Errorf("queue %q: dead-letter malformed job: %w", q.name, errors.Join(err, derr))
+		}
+		return nil, fmt.Errorf("%w: queue %q: %w", ErrMalformedJob, q.name, err)
+	}
+	return &job, nil
+}
+
+// requeue puts a dequeued job back at the head of the ready list, to be the
+// next one consumed.
+func (q *Queue) requeue(ctx context.Context, job Job) error {
+	b, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("queue %q: encode job: %w", q.name, err)
+	}
+	// BRPOP pops from the right
+	cmd := q.client.B().Rpush().Key(q.readyKey()).Element(rueidis.BinaryString(b)).Build()
+	if err := q.client.Do(ctx, cmd).Error(); err != nil {
+		return fmt.Errorf("queue %q: requeue: %w", q.name, err)
+	}
+	return nil
+}
+
+// Consume pulls jobs and dispatches them to handler using a worker.BlockingPool of the given size.
+//
+// It blocks until ctx is cancelled. A job dequeued but not yet handed to the pool
+// by then is pushed back onto the ready list.
+func (q *Queue) Consume(ctx context.Context, size int, handler Handler) {
+	jobs := make(chan Job)
+
+	go func() {
+		defer close(jobs)
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+			job, err := q.Dequeue(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				slog.ErrorContext(ctx, "queue dequeue error", slog.String("queue", q.name), slog.Any("error", err))
+				if errors.Is(err, ErrMalformedJob) {
+					// redis answered, there is no outage to wait out
+					continue
+				}
+				// avoid a hot loop while redis is unavailable
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				continue
+			}
+			if job == nil {
+				continue
+			}
+			select {
+			case jobs <- *job:
+			case <-ctx.Done():
+				rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+				if err := q.requeue(rctx, *job); err != nil {
+					slog.ErrorContext(ctx, "queue job lost on shutdown",
+						slog.String("queue", q.name),
+						slog.String("job.id", job.ID),
+						slog.Any("error", err),
+					)
+				}
+				cancel()
+				return
+			}
+		}
+	}()
+
+	worker.BlockingPool(ctx, size, jobs, func(ctx context.Context, job Job) {
+		if err := handler(ctx, job); err != nil {
+			slog.ErrorContext(ctx, "queue job failed",
+				slog.String("queue", q.name),
+				slog.String("job.id", job.ID),
+				slog.String("job.type", job.Type),
+				slog.Any("error", err),
+			)
+		}
+	})
+}
+
+// prepare fills in defaults and encodes the job.
+func (q *Queue) prepare(job Job) (Job, string, error) {
+	if job.Type == "" {
+		return Job{}, "", fmt.Errorf("%w: type must not be empty", ErrInvalidJob)
+	}
+	if job.ID == "" {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return Job{}, "", fmt.Errorf("queue %q: generate id: %w", q.name, err)
+		}
+		job.ID = id.String()
+	}
+	if job.EnqueuedAt.IsZero() {
+		job.EnqueuedAt = q.clock.Now().UTC()
+	}
+
+	b, err := json.Marshal(job)
+	if err != nil {
+		return Job{}, "", fmt.Errorf("queue %q: encode job: %w", q.name, err)
+	}
+	return job, rueidis.BinaryString(b), nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"app/modules/readonly"
+
+	"github.com/redis/rueidis"
+)
+
+var _ readonly.Store = (*ReadOnlyStore)(nil)
+
+// ReadOnlyStore keeps the fleet-wide read-only State as JSON under a single key.
+type ReadOnlyStore struct {
+	client rueidis.Client
+	key    string
+}
+
+func NewReadOnlyStore(client rueidis.Client, key string) *ReadOnlyStore {
+	return &ReadOnlyStore{client: client, key: key}
+}
+
+// Load implements readonly.Store.
+func (s *ReadOnlyStore) Load(ctx context.Context) (readonly.State, error) {
+	bs, err := s.client.Do(ctx, s.client.B().Get().Key(s.key).Build()).AsBytes()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return readonly.State{}, nil
+		}
+		return readonly.State{}, fmt.Errorf("redis readonly: load: %w", err)
+	}
+	var st readonly.State
+	if err := json.Unmarshal(bs, &st); err != nil {
+		return readonly.State{}, fmt.Errorf("redis readonly: decode: %w", err)
+	}
+	return st, nil
+}
+
+// Save implements readonly.Store.
+func (s *ReadOnlyStore) Save(ctx context.Context, st readonly.State) error {
+	bs, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("redis readonly: encode: %w", err)
+	}
+	if err := s.client.Do(ctx, s.client.B().Set().Key(s.key).Value(rueidis.BinaryString(bs)).Build()).Error(); err != nil {
+		return fmt.Errorf("redis readonly: save: %w", err)
+	}
+	return nil
+}
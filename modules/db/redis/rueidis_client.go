@@ -27,6 +27,18 @@ import (
 	"github.com/redis/rueidis/rueidisotel"
 )
 
+// ClientOption adjusts the rueidis options NewRueidisClient derived from RedisConfig.
+type ClientOption func(*rueidis.ClientOption)
+
+// WithLocalCache stores client-side cached replies in c, so they can be flushed on demand.
+func WithLocalCache(c *LocalCache) ClientOption {
+	return func(o *rueidis.ClientOption) {
+		if c != nil && !o.DisableCache {
+			o.NewCacheStoreFn = c.NewStore
+		}
+	}
+}
+
 // NewRueidisClient creates a production-ready rueidis.Client from RueidisOptions.
 //
 // It:
@@ -37,7 +49,7 @@ import (
 //   - Configures server-assisted client-side caching tracking options
 //   - Wraps the client with OpenTelemetry (optional)
 //   - Performs a PING with a small timeout to fail fast
-func NewRueidisClient(ctx context.Context, opt RedisConfig) (rueidis.Client, error) {
+func NewRueidisClient(ctx context.Context, opt RedisConfig, opts ...ClientOption) (rueidis.Client, error) {
 	if opt.URL == "" {
 		return nil, errors.New("rueidis: URL must not be empty")
 	}
@@ -125,6 +137,12 @@ func NewRueidisClient(ctx context.Context, opt RedisConfig) (rueidis.Client, err
 		clientOpt.ClientTrackingOptions = tracking
 	}
 
+	for _, o := range opts {
+		if o != nil {
+			o(&clientOpt)
+		}
+	}
+
 	var cli rueidis.Client
 
 	if opt.EnableOtel {
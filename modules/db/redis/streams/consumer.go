@@ -0,0 +1,359 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"app/modules/worker"
+
+	"github.com/redis/rueidis"
+)
+
+const (
+	// DefaultMinIdle is how long a delivered message may stay unacknowledged before
+	// another consumer claims it, when not configured.
+	DefaultMinIdle = time.Minute
+	// DefaultMaxDeliveries is how many times a message is delivered before it is
+	// dead-lettered, when not configured.
+	DefaultMaxDeliveries = 5
+)
+
+type (
+	// Handler processes a single message. Returning nil acknowledges it. Any other
+	// error leaves it pending, to be claimed and delivered again once it has been
+	// idle for the min idle time, until it runs out of deliveries. ErrDeadLetter
+	// dead-letters it right away.
+	Handler func(ctx context.Context, msg Message) error
+
+	// Consumer reads a Stream as one member of a consumer group. Every message
+	// reaches one consumer of each group.
+	//
+	// Delivery is at least once. A message stays pending from the moment it is read
+	// until its handler succeeds, so messages a crashed or stopped consumer left
+	// behind are claimed by the others (or by itself once restarted) after the min
+	// idle time.
+	Consumer struct {
+		stream *Stream
+
+		group string
+		name  string
+
+		// where a group created by the consumer starts reading
+		startID string
+
+		// how many messages a read or claim returns at most
+		batch int64
+
+		// how long a read blocks before re-checking ctx
+		pollTimeout time.Duration
+
+		// how long a message stays pending before it is claimed, and how often to look
+		minIdle       time.Duration
+		claimInterval time.Duration
+
+		maxDeliveries int64
+	}
+
+	// ConsumerOption configures a Consumer.
+	ConsumerOption func(*Consumer)
+)
+
+// WithStartID sets where the group starts when Consume creates it: "$" (the
+// default) for messages appended from then on, "0" for the whole stream.
+func WithStartID(id string) ConsumerOption {
+	return func(c *Consumer) {
+		if id != "" {
+			c.startID = id
+		}
+	}
+}
+
+// WithBatchSize sets how many messages a single read or claim returns at most.
+// Defaults to 16.
+func WithBatchSize(n int64) ConsumerOption {
+	return func(c *Consumer) {
+		if n > 0 {
+			c.batch = n
+		}
+	}
+}
+
+// WithPollTimeout sets how long a read blocks waiting for messages before
+// checking for cancellation again. Defaults to 5 seconds.
+func WithPollTimeout(d time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.pollTimeout = d
+		}
+	}
+}
+
+// WithMinIdle sets how long a message stays unacknowledged before it is claimed
+// and delivered again. Keep it well above the slowest handler, or messages being
+// handled get delivered twice. Defaults to DefaultMinIdle.
+func WithMinIdle(d time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.minIdle = d
+		}
+	}
+}
+
+// WithClaimInterval sets how often the consumer looks for idle messages to claim.
+// Defaults to half the min idle time.
+func WithClaimInterval(d time.Duration) ConsumerOption {
+	return func(c *Consumer) {
+		if d > 0 {
+			c.claimInterval = d
+		}
+	}
+}
+
+// WithMaxDeliveries sets how many times a message is delivered before it is moved
+// to the dead letters. Defaults to DefaultMaxDeliveries.
+func WithMaxDeliveries(n int64) ConsumerOption {
+	return func(c *Consumer) {
+		if n > 0 {
+			c.maxDeliveries = n
+		}
+	}
+}
+
+// NewConsumer constructs the consumer name of group on s. Names must be unique
+// within the group and stable across restarts, e.g. the pod name, so a restarted
+// consumer resumes its own pending messages.
+func NewConsumer(s *Stream, group, name string, opts ...ConsumerOption) *Consumer {
+	c := &Consumer{
+		stream:        s,
+		group:         group,
+		name:          name,
+		startID:       "$",
+		batch:         16,
+		pollTimeout:   5 * time.Second,
+		minIdle:       DefaultMinIdle,
+		maxDeliveries: DefaultMaxDeliveries,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	if c.claimInterval <= 0 {
+		c.claimInterval = c.minIdle / 2
+	}
+	return c
+}
+
+// CreateGroup creates the consumer group, and the stream if needed. A group that
+// already exists is left as it is.
+func (c *Consumer) CreateGroup(ctx context.Context) error {
+	cmd := c.stream.client.B().XgroupCreate().Key(c.stream.key()).Group(c.group).Id(c.startID).Mkstream().Build()
+	if err := c.stream.client.Do(ctx, cmd).Error(); err != nil && !rueidis.IsRedisBusyGroup(err) {
+		return fmt.Errorf("streams %q: create group %q: %w", c.stream.name, c.group, err)
+	}
+	return nil
+}
+
+// Consume creates the group if needed, then reads messages and dispatches them to
+// handler using a worker.BlockingPool of the given size. Every claim interval it
+// first dead-letters idle messages that ran out of deliveries and claims the
+// other idle ones.
+//
+// It blocks until ctx is cancelled. Messages read but not handled by then stay
+// pending and are delivered again.
+func (c *Consumer) Consume(ctx context.Context, size int, handler Handler) error {
+	if err := c.CreateGroup(ctx); err != nil {
+		return err
+	}
+
+	msgs := make(chan Message)
+
+	go func() {
+		defer close(msgs)
+		// claim right away: whatever this consumer left pending before a restart
+		var nextClaim time.Time
+		cursor := "0-0"
+		for ctx.Err() == nil {
+			var batch []Message
+			if now := time.Now(); !now.Before(nextClaim) {
+				claimed, next, err := c.claim(ctx, cursor)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					slog.ErrorContext(ctx, "streams claim error", c.attrs(slog.Any("error", err))...)
+					next = "0-0"
+				}
+				// keep claiming until the pending entries are all scanned
+				if cursor = next; cursor == "0-0" {
+					nextClaim = now.Add(c.claimInterval)
+				}
+				batch = claimed
+			}
+			if len(batch) == 0 {
+				var err error
+				batch, err = c.read(ctx)
+				if err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					slog.ErrorContext(ctx, "streams read error", c.attrs(slog.Any("error", err))...)
+					// avoid a hot loop while redis is unavailable
+					select {
+					case <-ctx.Done():
+						return
+					case <-time.After(time.Second):
+					}
+					continue
+				}
+			}
+			for _, msg := range batch {
+				select {
+				case msgs <- msg:
+				case <-ctx.Done():
+					// the rest stays pending and is claimed again
+					return
+				}
+			}
+		}
+	}()
+
+	worker.BlockingPool(ctx, size, msgs, func(ctx context.Context, msg Message) {
+		c.handle(ctx, handler, msg)
+	})
+	return nil
+}
+
+// read returns the next new messages of the group, or none when the poll timeout
+// passed without any.
+func (c *Consumer) read(ctx context.Context) ([]Message, error) {
+	cmd := c.stream.client.B().Xreadgroup().Group(c.group, c.name).Count(c.batch).
+		Block(c.pollTimeout.Milliseconds()).Streams().Key(c.stream.key()).Id(">").Build()
+	streams, err := c.stream.client.Do(ctx, cmd).AsXRead()
+	if err != nil {
+		if rueidis.IsRedisNil(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("streams %q: read: %w", c.stream.name, err)
+	}
+
+	entries := streams[c.stream.key()]
+	out := make([]Message, 0, len(entries))
+	for _, e := range entries {
+		out = append(out, decode(e))
+	}
+	return out, nil
+}
+
+// claim takes over the messages idle for the min idle time from cursor on and
+// dead-letters those among them that are exhausted. It returns the others along
+// with the cursor to continue from, "0-0" once every pending entry was scanned.
+func (c *Consumer) claim(ctx context.Context, cursor string) ([]Message, string, error) {
+	cmd := c.stream.client.B().Xautoclaim().Key(c.stream.key()).Group(c.group).Consumer(c.name).
+		MinIdleTime(strconv.FormatInt(c.minIdle.Milliseconds(), 10)).Start(cursor).Count(c.batch).Build()
+	// XAUTOCLAIM replies with [next cursor, entries, deleted ids (Redis 7)]
+	reply, err := c.stream.client.Do(ctx, cmd).ToArray()
+	if err != nil {
+		return nil, cursor, fmt.Errorf("streams %q: claim: %w", c.stream.name, err)
+	}
+	if len(reply) < 2 {
+		return nil, cursor, fmt.Errorf("streams %q: claim: unexpected reply length %d", c.stream.name, len(reply))
+	}
+	next, err := reply[0].ToString()
+	if err != nil {
+		return nil, cursor, fmt.Errorf("streams %q: claim: %w", c.stream.name, err)
+	}
+	entries, err := reply[1].ToArray()
+	if err != nil {
+		return nil, cursor, fmt.Errorf("streams %q: claim: %w", c.stream.name, err)
+	}
+
+	out := make([]Message, 0, len(entries))
+	for _, raw := range entries {
+		e, err := raw.AsXRangeEntry()
+		if err != nil {
+			// Redis 6.2 replies nil for entries trimmed while pending
+			continue
+		}
+		if e.FieldValues == nil {
+			c.ack(ctx, e.ID)
+			continue
+		}
+		out = append(out, decode(e))
+	}
+	// the claimed messages stay pending under this consumer, so those left after
+	// an error are claimed again once idle
+	out, err = c.deadLetterExhausted(ctx, out)
+	if err != nil {
+		return nil, cursor, err
+	}
+	return out, next, nil
+}
+
+func (c *Consumer) handle(ctx context.Context, handler Handler, msg Message) {
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		return handler(ctx, msg)
+	}()
+
+	// a handled message is acknowledged even when the consumer is stopping
+	ctx = context.WithoutCancel(ctx)
+	switch {
+	case err == nil:
+		c.ack(ctx, msg.ID)
+	case errors.Is(err, ErrDeadLetter):
+		if _, derr := c.deadLetter(ctx, msg.ID, 0); derr != nil {
+			slog.ErrorContext(ctx, "streams dead-letter error", c.attrs(slog.String("message.id", msg.ID), slog.Any("error", derr))...)
+			return
+		}
+		slog.WarnContext(ctx, "streams message dead-lettered", c.attrs(
+			slog.String("message.id", msg.ID),
+			slog.String("message.type", msg.Type),
+			slog.Any("error", err),
+		)...)
+	default:
+		slog.ErrorContext(ctx, "streams message failed", c.attrs(
+			slog.String("message.id", msg.ID),
+			slog.String("message.type", msg.Type),
+			slog.Any("error", err),
+		)...)
+	}
+}
+
+func (c *Consumer) ack(ctx context.Context, id string) {
+	cmd := c.stream.client.B().Xack().Key(c.stream.key()).Group(c.group).Id(id).Build()
+	if err := c.stream.client.Do(ctx, cmd).Error(); err != nil {
+		// it stays pending and is delivered again after the min idle time
+		slog.ErrorContext(ctx, "streams ack error", c.attrs(slog.String("message.id", id), slog.Any("error", err))...)
+	}
+}
+
+func (c *Consumer) attrs(extra ...any) []any {
+	return append([]any{
+		slog.String("stream", c.stream.name),
+		slog.String("group", c.group),
+		slog.String("consumer", c.name),
+	}, extra...)
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+// Headers set on dead letters.
+const (
+	// DeadLetterIDHeader is the ID the message had in its source stream.
+	DeadLetterIDHeader = "dead-letter.id"
+	// DeadLetterGroupHeader is the consumer group that gave up on the message.
+	DeadLetterGroupHeader = "dead-letter.group"
+	// DeadLetterConsumerHeader is the consumer that had the message last.
+	DeadLetterConsumerHeader = "dead-letter.consumer"
+	// DeadLetterDeliveriesHeader is how many times the message was delivered.
+	DeadLetterDeliveriesHeader = "dead-letter.deliveries"
+)
+
+var (
+	// ErrDeadLetter makes a consumer dead-letter a message right away instead of
+	// having it redelivered, e.g. one that cannot be decoded. Handlers return it,
+	// or wrap it.
+	ErrDeadLetter = errors.New("streams: dead letter")
+
+	//go:embed dead_letter.lua
+	deadLetterLua string
+
+	// Lua script moving a pending entry to the dead letters
+	// - KEYS[1] = stream, KEYS[2] = dead letter stream
+	// - ARGV[1] = group, ARGV[2] = entry id, ARGV[3] = min idle ms, ARGV[4] = max len or 0
+	// Atomically, when the entry is still pending and idle for at least min idle:
+	// - XADD its fields and the dead-letter.* headers to the dead letters
+	// - XACK it, so that it leaves the pending entries of the group
+	//
+	// Checking the idle time within the script means an entry another consumer
+	// claimed in the meantime is left to that consumer.
+	luaDeadLetter = rueidis.NewLuaScript(deadLetterLua)
+)
+
+// deadLetter moves entry id to the dead letters if it has been idle for minIdle.
+// It reports whether it did.
+func (c *Consumer) deadLetter(ctx context.Context, id string, minIdle time.Duration) (bool, error) {
+	res := luaDeadLetter.Exec(ctx, c.stream.client,
+		[]string{c.stream.key(), c.stream.deadKey()},
+		[]string{c.group, id, strconv.FormatInt(minIdle.Milliseconds(), 10), strconv.FormatInt(c.stream.maxLen, 10)},
+	)
+	moved, err := res.AsInt64()
+	if err != nil {
+		return false, fmt.Errorf("streams %q: dead-letter %s: %w", c.stream.name, id, err)
+	}
+	return moved == 1, nil
+}
+
+// deadLetterExhausted dead-letters the claimed messages that were delivered
+// MaxDeliveries times already, and returns the others. XAUTOCLAIM counted the
+// claim itself as a delivery, hence the strict comparison.
+func (c *Consumer) deadLetterExhausted(ctx context.Context, claimed []Message) ([]Message, error) {
+	if len(claimed) == 0 {
+		return claimed, nil
+	}
+	cmds := make(rueidis.Commands, 0, len(claimed))
+	for _, msg := range claimed {
+		cmds = append(cmds, c.stream.client.B().Xpending().Key(c.stream.key()).Group(c.group).
+			Start(msg.ID).End(msg.ID).Count(1).Consumer(c.name).Build())
+	}
+
+	out := claimed[:0]
+	for i, res := range c.stream.client.DoMulti(ctx, cmds...) {
+		msg := claimed[i]
+		pending, err := res.ToArray()
+		if err != nil {
+			return nil, fmt.Errorf("streams %q: pending: %w", c.stream.name, err)
+		}
+		if len(pending) == 0 {
+			// acknowledged meanwhile, nothing left to deliver
+			continue
+		}
+		// each entry is [id, consumer, idle ms, deliveries]
+		fields, err := pending[0].ToArray()
+		if err != nil || len(fields) != 4 {
+			return nil, fmt.Errorf("streams %q: pending: unexpected entry", c.stream.name)
+		}
+		deliveries, err := fields[3].AsInt64()
+		if err != nil || deliveries <= c.maxDeliveries {
+			out = append(out, msg)
+			continue
+		}
+		// this consumer owns the entry now, so no idle time is required
+		moved, err := c.deadLetter(ctx, msg.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		if moved {
+			slog.WarnContext(ctx, "streams message dead-lettered", c.attrs(
+				slog.String("message.id", msg.ID),
+				slog.Int64("deliveries", deliveries),
+			)...)
+		}
+	}
+	return out, nil
+}
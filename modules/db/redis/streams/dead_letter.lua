@@ -0,0 +1,49 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+local stream = KEYS[1]
+local dead = KEYS[2]
+local group = ARGV[1]
+local id = ARGV[2]
+local min_idle = ARGV[3]
+local max_len = tonumber(ARGV[4])
+
+-- only while still pending and idle: nobody acknowledged or claimed it meanwhile
+local pending = redis.call("XPENDING", stream, group, "IDLE", min_idle, id, id, 1)
+if #pending == 0 then
+  return 0
+end
+
+-- a trimmed entry has nothing left to keep, it is only acknowledged
+local entries = redis.call("XRANGE", stream, id, id)
+if #entries > 0 then
+  local fields = entries[1][2]
+  table.insert(fields, "h:dead-letter.id")
+  table.insert(fields, id)
+  table.insert(fields, "h:dead-letter.group")
+  table.insert(fields, group)
+  table.insert(fields, "h:dead-letter.consumer")
+  table.insert(fields, pending[1][2])
+  table.insert(fields, "h:dead-letter.deliveries")
+  table.insert(fields, tostring(pending[1][4]))
+
+  if max_len and max_len > 0 then
+    redis.call("XADD", dead, "MAXLEN", "~", max_len, "*", unpack(fields))
+  else
+    redis.call("XADD", dead, "*", unpack(fields))
+  end
+end
+
+redis.call("XACK", stream, group, id)
+return 1
@@ -0,0 +1,35 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package streams is a lightweight Redis Streams queue with consumer groups, for
+// fan-out and work distribution without running Kafka.
+//
+// Producers append to a Stream. Every consumer group gets each message once, and
+// within a group the consumers share the messages, each dispatching them on a
+// worker.BlockingPool:
+//
+//	events := streams.New(redisClient, "profile.events", streams.WithKeyPrefix("dev"))
+//	_, err := events.Append(ctx, streams.Message{Type: "profile.updated", Payload: payload})
+//
+//	indexer := streams.NewConsumer(events, "search-indexer", podName,
+//		streams.WithMinIdle(time.Minute), streams.WithMaxDeliveries(5))
+//	err = indexer.Consume(ctx, 4, func(ctx context.Context, msg streams.Message) error { ... })
+//
+// A message is acknowledged once its handler succeeds. Messages that stayed
+// unacknowledged for the min idle time, because their handler failed or their
+// consumer died, are claimed with XAUTOCLAIM and delivered again. After
+// MaxDeliveries deliveries, or as soon as a handler returns ErrDeadLetter, they are
+// moved to the DeadLetters stream with dead-letter.* headers telling where they
+// came from.
+package streams
@@ -0,0 +1,181 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/rueidis"
+)
+
+var (
+	// ErrInvalidMessage is returned when a message cannot be appended as given.
+	ErrInvalidMessage = errors.New("streams: invalid message")
+)
+
+// DefaultMaxLen is roughly how many entries a stream keeps when not configured.
+const DefaultMaxLen = 100_000
+
+// entry fields; headers are stored as "h:<name>"
+const (
+	fieldType    = "type"
+	fieldPayload = "payload"
+	headerPrefix = "h:"
+
+	deadSuffix = ":dead"
+)
+
+type (
+	// Message is an entry of a stream.
+	//
+	// Payload is opaque to the stream; handlers decide how to decode it
+	// (usually JSON keyed by Type).
+	Message struct {
+		// ID is assigned by Redis on Append, e.g. "1700000000000-0".
+		ID      string
+		Type    string
+		Payload []byte
+
+		// Headers carry metadata such as trace context or, on dead letters, where
+		// the message came from (see the DeadLetter* constants).
+		Headers map[string]string
+	}
+
+	// Stream is an append-only Redis stream, consumed by consumer groups.
+	//
+	// Keys used (sharing a hash tag so Lua scripts work on Redis Cluster):
+	//   - {prefix}{name}       STREAM of messages
+	//   - {prefix}{name}:dead  STREAM of messages that exhausted their deliveries
+	Stream struct {
+		client rueidis.Client
+
+		name   string
+		prefix string
+		// suffix follows the hash tag, ":dead" for dead letters
+		suffix string
+
+		// approximate MAXLEN applied on every append, 0 keeps everything
+		maxLen int64
+	}
+
+	// Option configures a Stream.
+	Option func(*Stream)
+)
+
+// WithKeyPrefix scopes the stream keys under a prefix (env, service, etc).
+func WithKeyPrefix(prefix string) Option {
+	return func(s *Stream) {
+		prefix = strings.TrimSpace(prefix)
+		if prefix != "" && !strings.HasSuffix(prefix, ":") {
+			prefix += ":"
+		}
+		s.prefix = prefix
+	}
+}
+
+// WithMaxLen caps the stream, and its dead letters, to roughly n entries by
+// trimming the oldest ones on append. Defaults to DefaultMaxLen, 0 disables
+// trimming. Trimming removes entries whether or not every group consumed them.
+func WithMaxLen(n int64) Option {
+	return func(s *Stream) {
+		if n >= 0 {
+			s.maxLen = n
+		}
+	}
+}
+
+// New constructs a Stream named name on top of an existing rueidis.Client.
+func New(client rueidis.Client, name string, opts ...Option) *Stream {
+	s := &Stream{
+		client: client,
+		name:   name,
+		maxLen: DefaultMaxLen,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// DeadLetters returns the stream that messages exhausting their deliveries are
+// moved to. It can be inspected, or consumed like any other stream to replay them.
+func (s *Stream) DeadLetters() *Stream {
+	return &Stream{
+		client: s.client,
+		name:   s.name,
+		prefix: s.prefix,
+		suffix: s.suffix + deadSuffix,
+		maxLen: s.maxLen,
+	}
+}
+
+// keys are hash-tagged on the stream name so a stream and its dead letters land on the same slot.
+func (s *Stream) key() string { return s.prefix + "{" + s.name + "}" + s.suffix }
+
+func (s *Stream) deadKey() string { return s.key() + deadSuffix }
+
+// Append adds msg to the end of the stream and returns its ID.
+func (s *Stream) Append(ctx context.Context, msg Message) (string, error) {
+	if msg.Type == "" {
+		return "", fmt.Errorf("%w: type must not be empty", ErrInvalidMessage)
+	}
+	id, err := s.client.Do(ctx, s.appendCmd(s.key(), msg)).ToString()
+	if err != nil {
+		return "", fmt.Errorf("streams %q: append: %w", s.name, err)
+	}
+	return id, nil
+}
+
+func (s *Stream) appendCmd(key string, msg Message) rueidis.Completed {
+	cmd := s.client.B().Arbitrary("XADD").Keys(key)
+	if s.maxLen > 0 {
+		cmd = cmd.Args("MAXLEN", "~", strconv.FormatInt(s.maxLen, 10))
+	}
+	return cmd.Args("*").Args(encode(msg)...).Build()
+}
+
+// encode flattens msg into XADD field/value pairs.
+func encode(msg Message) []string {
+	out := make([]string, 0, 4+2*len(msg.Headers))
+	out = append(out, fieldType, msg.Type, fieldPayload, rueidis.BinaryString(msg.Payload))
+	for k, v := range msg.Headers {
+		out = append(out, headerPrefix+k, v)
+	}
+	return out
+}
+
+func decode(e rueidis.XRangeEntry) Message {
+	msg := Message{ID: e.ID}
+	for k, v := range e.FieldValues {
+		switch {
+		case k == fieldType:
+			msg.Type = v
+		case k == fieldPayload:
+			msg.Payload = []byte(v)
+		case strings.HasPrefix(k, headerPrefix):
+			if msg.Headers == nil {
+				msg.Headers = make(map[string]string)
+			}
+			msg.Headers[strings.TrimPrefix(k, headerPrefix)] = v
+		}
+	}
+	return msg
+}
@@ -0,0 +1,22 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenbucket implements a token bucket shared through Redis, so every
+// instance draws from the same budget.
+//
+// It fits outbound throttling: a third party allowing 100 calls per second gets one
+// bucket for the whole fleet instead of 100 per instance (see httpclient.HostThrottle).
+// The bucket holds up to a capacity of tokens, refills continuously at the configured
+// rate and a call may take several tokens at once for weighted quotas.
+package tokenbucket
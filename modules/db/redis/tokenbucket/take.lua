@@ -0,0 +1,62 @@
+-- Copyright 2025 Nhat-Nguyen Nguyen
+--
+-- Licensed under the Apache License, Version 2.0 (the "License");
+-- you may not use this file except in compliance with the License.
+-- You may obtain a copy of the License at
+--
+--     http://www.apache.org/licenses/LICENSE-2.0
+--
+-- Unless required by applicable law or agreed to in writing, software
+-- distributed under the License is distributed on an "AS IS" BASIS,
+-- WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+-- See the License for the specific language governing permissions and
+-- limitations under the License.
+
+-- Token bucket withdrawal.
+-- KEYS[1] = bucket hash {tokens, ts}
+-- ARGV[1] = capacity (max tokens)
+-- ARGV[2] = refill interval in microseconds per token
+-- ARGV[3] = tokens requested
+--
+-- Returns {taken, remaining, wait_us}: wait_us is how long until the request could
+-- be served, -1 when it exceeds the capacity and never can.
+-- The server clock is used so every node agrees on "now".
+
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local t = redis.call("TIME")
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+
+local state = redis.call("HMGET", key, "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if not tokens or not ts then
+    -- an absent bucket is a full one
+    tokens = capacity
+    ts = now
+end
+if now > ts then
+    tokens = math.min(capacity, tokens + (now - ts) / interval)
+end
+
+if cost > capacity then
+    return {0, math.floor(tokens), -1}
+end
+
+local taken = 0
+local wait = 0
+if tokens >= cost then
+    tokens = tokens - cost
+    taken = 1
+else
+    wait = math.ceil((cost - tokens) * interval)
+end
+
+redis.call("HSET", key, "tokens", string.format("%.6f", tokens), "ts", string.format("%.0f", now))
+-- the key expires once the bucket is full again
+redis.call("PEXPIRE", key, math.ceil((capacity - tokens) * interval / 1000) + 1000)
+
+return {taken, math.floor(tokens), wait}
@@ -0,0 +1,156 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tokenbucket
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"app/modules/ratelimit"
+
+	"github.com/redis/rueidis"
+)
+
+var (
+	_ ratelimit.RateLimiter = (*Bucket)(nil)
+
+	//go:embed take.lua
+	takeLua string
+
+	// Refilling and withdrawing in one script keeps concurrent callers on different
+	// nodes from spending the same tokens.
+	luaTake = rueidis.NewLuaScript(takeLua)
+
+	// ErrExceedsCapacity is returned by Take for more tokens than the bucket holds.
+	ErrExceedsCapacity = errors.New("tokenbucket: request exceeds bucket capacity")
+)
+
+type (
+	// Bucket is a token bucket refilled at rate tokens per period, holding at most
+	// capacity tokens. Each key is a separate bucket.
+	Bucket struct {
+		client rueidis.Client
+		prefix string
+
+		rate     int64
+		period   time.Duration
+		capacity int64
+	}
+
+	// Option configures a Bucket.
+	Option func(*Bucket)
+
+	// Result is the outcome of Take.
+	Result struct {
+		Taken bool
+		// whole tokens left after the call
+		Remaining int64
+		// until enough tokens are back when not Taken
+		RetryAfter time.Duration
+	}
+)
+
+// WithCapacity sets how many tokens the bucket holds, i.e. the largest burst.
+// It defaults to the rate.
+func WithCapacity(n int64) Option {
+	return func(b *Bucket) {
+		if n > 0 {
+			b.capacity = n
+		}
+	}
+}
+
+// New constructs a Bucket refilled with rate tokens per period.
+//
+// prefix is optional; if non-empty, keys become prefix + ":tokenbucket:" + ....
+func New(client rueidis.Client, prefix string, rate int64, period time.Duration, opts ...Option) *Bucket {
+	if prefix != "" && prefix[len(prefix)-1] != ':' {
+		prefix += ":"
+	}
+	b := &Bucket{
+		client: client,
+		prefix: prefix,
+		rate:   max(rate, 1),
+		period: max(period, time.Millisecond),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	if b.capacity == 0 {
+		b.capacity = b.rate
+	}
+	return b
+}
+
+// Factory returns a ratelimit.LimiterFactory creating buckets on client, refilled
+// with limit tokens per window.
+func Factory(client rueidis.Client, prefix string, opts ...Option) ratelimit.LimiterFactory {
+	return func(limit int64, window time.Duration) ratelimit.RateLimiter {
+		return New(client, prefix, limit, window, opts...)
+	}
+}
+
+// Take withdraws n tokens from the bucket of key, or none if fewer are available.
+func (b *Bucket) Take(ctx context.Context, key string, n int64) (Result, error) {
+	interval := float64(b.period.Microseconds()) / float64(b.rate)
+	rr := luaTake.Exec(ctx, b.client, []string{b.buildKey(key)}, []string{
+		strconv.FormatInt(b.capacity, 10),
+		strconv.FormatFloat(interval, 'f', -1, 64),
+		strconv.FormatInt(max(n, 1), 10),
+	})
+	vals, err := rr.AsIntSlice()
+	if err != nil {
+		return Result{}, fmt.Errorf("tokenbucket Take: %w", err)
+	}
+	if len(vals) != 3 {
+		return Result{}, fmt.Errorf("tokenbucket Take: unexpected reply length %d", len(vals))
+	}
+	if vals[2] < 0 {
+		return Result{Remaining: vals[1]}, fmt.Errorf("%w: %d > %d", ErrExceedsCapacity, n, b.capacity)
+	}
+	return Result{
+		Taken:      vals[0] == 1,
+		Remaining:  vals[1],
+		RetryAfter: time.Duration(vals[2]) * time.Microsecond,
+	}, nil
+}
+
+// Allow implements ratelimit.RateLimiter, taking one token.
+func (b *Bucket) Allow(ctx context.Context, key ratelimit.Key) (ratelimit.Result, error) {
+	res, err := b.Take(ctx, string(key), 1)
+	if err != nil {
+		return ratelimit.Result{}, err
+	}
+	interval := b.period / time.Duration(b.rate)
+	return ratelimit.Result{
+		Allowed:    res.Taken,
+		Remaining:  res.Remaining,
+		RetryAfter: res.RetryAfter,
+		Limit:      b.rate,
+		Window:     b.period,
+		// time until the bucket is full again
+		WindowResetIn: time.Duration(b.capacity-res.Remaining) * interval,
+	}, nil
+}
+
+func (b *Bucket) buildKey(key string) string {
+	return fmt.Sprintf("%stokenbucket:%d/%s:%s", b.prefix, b.rate, b.period, key)
+}
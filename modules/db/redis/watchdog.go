@@ -0,0 +1,253 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redis
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/retry"
+
+	"github.com/redis/rueidis"
+)
+
+// ErrDegraded is returned by fail-closed consumers while the Watchdog reports redis as degraded.
+var ErrDegraded = errors.New("redis: degraded")
+
+// FailurePolicy decides what a consumer does while redis is degraded.
+type FailurePolicy int
+
+const (
+	// FailClosed rejects the operation with ErrDegraded.
+	FailClosed FailurePolicy = iota
+	// FailOpen behaves as if redis had nothing (cache miss, no-op write).
+	FailOpen
+)
+
+type (
+	// WatchdogHook is called on every degraded/recovered transition.
+	// downFor is how long connectivity had been failing at that point.
+	WatchdogHook func(ctx context.Context, degraded bool, downFor time.Duration)
+
+	// PingObserver is called with the outcome of every ping, e.g. to feed a health.Tracker.
+	PingObserver func(ctx context.Context, err error)
+
+	// Watchdog pings redis in the background and raises a shared degraded flag once
+	// connectivity has been lost for longer than a grace period.
+	//
+	// rueidis reconnects on its own; the flag lets consumers stop waiting on dial
+	// and command timeouts for every call and apply their failure policy right away:
+	// the rate limiter, lock executor and RedisKV all accept Degraded as a func() bool.
+	Watchdog struct {
+		client rueidis.Client
+		clock  clock.Clock
+
+		interval      time.Duration
+		pingTimeout   time.Duration
+		degradedAfter time.Duration
+		// optional, see WithPingBackoff
+		backoff   *retry.Policy
+		hooks     []WatchdogHook
+		observers []PingObserver
+
+		degraded atomic.Bool
+
+		mu           sync.Mutex
+		failingSince time.Time
+	}
+
+	// WatchdogOption configures a Watchdog.
+	WatchdogOption func(*Watchdog)
+)
+
+// WithWatchdogInterval sets how often redis is pinged. Defaults to 1 second.
+func WithWatchdogInterval(d time.Duration) WatchdogOption {
+	return func(w *Watchdog) {
+		if d > 0 {
+			w.interval = d
+		}
+	}
+}
+
+// WithPingTimeout bounds every ping. Defaults to 500ms.
+func WithPingTimeout(d time.Duration) WatchdogOption {
+	return func(w *Watchdog) {
+		if d > 0 {
+			w.pingTimeout = d
+		}
+	}
+}
+
+// WithDegradedAfter sets how long pings must keep failing before the flag is raised,
+// so a single blip or failover does not flip every consumer. Defaults to 5 seconds.
+func WithDegradedAfter(d time.Duration) WatchdogOption {
+	return func(w *Watchdog) {
+		if d >= 0 {
+			w.degradedAfter = d
+		}
+	}
+}
+
+// WithPingBackoff spaces out the pings while they keep failing: the ping after n
+// failures waits p.Delay(n), but never less than the interval, instead of the
+// interval. Every instance then stops hammering a redis that is down, at the cost of
+// noticing its recovery up to p.MaxDelay later.
+func WithPingBackoff(p retry.Policy) WatchdogOption {
+	return func(w *Watchdog) {
+		w.backoff = &p
+	}
+}
+
+// WithWatchdogHook registers a transition callback (metrics, alerts, cache flushes, ...).
+func WithWatchdogHook(fn WatchdogHook) WatchdogOption {
+	return func(w *Watchdog) {
+		if fn != nil {
+			w.hooks = append(w.hooks, fn)
+		}
+	}
+}
+
+// WithPingObserver registers a callback receiving every ping outcome.
+func WithPingObserver(fn PingObserver) WatchdogOption {
+	return func(w *Watchdog) {
+		if fn != nil {
+			w.observers = append(w.observers, fn)
+		}
+	}
+}
+
+// WithWatchdogClock overrides the time source (useful in tests).
+func WithWatchdogClock(c clock.Clock) WatchdogOption {
+	return func(w *Watchdog) {
+		if c != nil {
+			w.clock = c
+		}
+	}
+}
+
+func NewWatchdog(client rueidis.Client, opts ...WatchdogOption) *Watchdog {
+	w := &Watchdog{
+		client:        client,
+		clock:         clock.RealClock{},
+		interval:      time.Second,
+		pingTimeout:   500 * time.Millisecond,
+		degradedAfter: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(w)
+		}
+	}
+	return w
+}
+
+// Degraded reports whether redis has been unreachable for longer than the grace period.
+// It is safe to call from any goroutine and cheap enough for every request.
+func (w *Watchdog) Degraded() bool {
+	if w == nil {
+		return false
+	}
+	return w.degraded.Load()
+}
+
+// Run pings redis every interval, or as WithPingBackoff paces it, until ctx is cancelled.
+func (w *Watchdog) Run(ctx context.Context) {
+	timer := time.NewTimer(w.interval)
+	defer timer.Stop()
+
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+		if w.check(ctx) == nil {
+			failures = 0
+		} else {
+			failures++
+		}
+		timer.Reset(w.next(failures))
+	}
+}
+
+// next returns how long to wait for the ping following failures failed ones.
+func (w *Watchdog) next(failures int) time.Duration {
+	if failures == 0 || w.backoff == nil {
+		return w.interval
+	}
+	return max(w.backoff.Delay(failures), w.interval)
+}
+
+// Check runs a single ping and updates the flag.
+func (w *Watchdog) Check(ctx context.Context) {
+	_ = w.check(ctx)
+}
+
+// check is Check, returning the ping error.
+func (w *Watchdog) check(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, w.pingTimeout)
+	err := w.client.Do(pingCtx, w.client.B().Ping().Build()).Error()
+	cancel()
+	if ctx.Err() != nil {
+		// shutting down, not a connectivity signal
+		return nil
+	}
+	for _, o := range w.observers {
+		o(ctx, err)
+	}
+
+	now := w.clock.Now()
+	w.mu.Lock()
+	var (
+		changed bool
+		downFor time.Duration
+	)
+	if err == nil {
+		if !w.failingSince.IsZero() {
+			downFor = now.Sub(w.failingSince)
+		}
+		w.failingSince = time.Time{}
+		changed = w.degraded.CompareAndSwap(true, false)
+	} else {
+		if w.failingSince.IsZero() {
+			w.failingSince = now
+		}
+		downFor = now.Sub(w.failingSince)
+		if downFor >= w.degradedAfter {
+			changed = w.degraded.CompareAndSwap(false, true)
+		}
+	}
+	w.mu.Unlock()
+
+	if !changed {
+		return err
+	}
+	degraded := err != nil
+	if degraded {
+		slog.WarnContext(ctx, "redis: degraded", slog.Duration("down_for", downFor), slog.Any("error", err))
+	} else {
+		slog.InfoContext(ctx, "redis: recovered", slog.Duration("down_for", downFor))
+	}
+	for _, h := range w.hooks {
+		h(ctx, degraded, downFor)
+	}
+	return err
+}
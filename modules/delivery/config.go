@@ -0,0 +1,27 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import "time"
+
+// Config configures the delivery journal.
+type Config struct {
+	// Enabled keeps the journal and serves its admin endpoint.
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// Retention is how long finished deliveries are kept. Zero keeps them forever.
+	Retention time.Duration `env:"RETENTION" envDefault:"720h"`
+	// DispatchBatch is how many pending deliveries each run of the dispatch job attempts.
+	DispatchBatch int `env:"DISPATCH_BATCH" envDefault:"100"`
+}
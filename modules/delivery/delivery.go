@@ -0,0 +1,284 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"app/modules/clock"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	// ErrNotFound is returned by a Store for a delivery it does not hold.
+	ErrNotFound = errors.New("delivery: not found")
+	// ErrInvalid is returned for a delivery or filter that cannot be used as given.
+	ErrInvalid = errors.New("delivery: invalid request")
+)
+
+// maxErrorLength bounds the error kept for a failed attempt.
+const maxErrorLength = 1024
+
+// DefaultDispatchDelay is how long Dispatch leaves a pending delivery alone after
+// its last update, while its sender may still be attempting it.
+const DefaultDispatchDelay = time.Minute
+
+// Status is where a delivery stands.
+type Status string
+
+const (
+	// StatusPending deliveries wait for their next attempt.
+	StatusPending Status = "pending"
+	// StatusSucceeded deliveries were accepted by their destination.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed deliveries failed their last attempt and wait for a requeue.
+	StatusFailed Status = "failed"
+)
+
+type (
+	// Delivery is one outbound message and the outcome of its attempts.
+	Delivery struct {
+		ID          uuid.UUID `json:"id"`
+		Destination string    `json:"destination"`
+		Event       string    `json:"event"`
+		Payload     []byte    `json:"-"`
+		// PayloadHash is the SHA-256 of Payload, to spot duplicates without reading it.
+		PayloadHash []byte    `json:"payloadHash"`
+		Status      Status    `json:"status"`
+		Attempts    int       `json:"attempts"`
+		LastError   string    `json:"lastError,omitempty"`
+		CreatedAt   time.Time `json:"createdAt"`
+		UpdatedAt   time.Time `json:"updatedAt"`
+	}
+
+	// Filter selects the deliveries to requeue. Zero fields do not filter, except
+	// Status, which defaults to StatusFailed. Since and Until bound the time the
+	// deliveries were recorded, Until excluded.
+	Filter struct {
+		Status      Status     `json:"status,omitempty"`
+		Destination string     `json:"destination,omitempty"`
+		Event       string     `json:"event,omitempty"`
+		Since       *time.Time `json:"since,omitempty"`
+		Until       *time.Time `json:"until,omitempty"`
+	}
+
+	// Store persists the journal.
+	Store interface {
+		Insert(ctx context.Context, d Delivery) error
+		// Record counts one attempt of id and sets its status; ErrNotFound for an unknown id.
+		Record(ctx context.Context, id uuid.UUID, status Status, lastError string, at time.Time) error
+		// Pending returns up to limit pending deliveries, oldest first.
+		Pending(ctx context.Context, limit int) ([]Delivery, error)
+		// Requeue sets the deliveries matching f back to pending and returns how many.
+		Requeue(ctx context.Context, f Filter, at time.Time) (int64, error)
+		// Purge deletes the finished deliveries last updated before cutoff.
+		Purge(ctx context.Context, cutoff time.Time) (int64, error)
+	}
+
+	// Sender makes one attempt of d; a nil error means the destination accepted it.
+	Sender func(ctx context.Context, d Delivery) error
+
+	// Journal records deliveries and their attempts on top of a Store.
+	Journal struct {
+		store         Store
+		clock         clock.Clock
+		retention     time.Duration
+		dispatchDelay time.Duration
+	}
+
+	// Option configures a Journal.
+	Option func(*Journal)
+
+	eventKey struct{}
+)
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(j *Journal) {
+		if c != nil {
+			j.clock = c
+		}
+	}
+}
+
+// WithRetention sets how long Purge keeps finished deliveries. Zero, the
+// default, keeps them forever.
+func WithRetention(d time.Duration) Option {
+	return func(j *Journal) {
+		if d > 0 {
+			j.retention = d
+		}
+	}
+}
+
+// WithDispatchDelay overrides DefaultDispatchDelay.
+func WithDispatchDelay(d time.Duration) Option {
+	return func(j *Journal) {
+		if d > 0 {
+			j.dispatchDelay = d
+		}
+	}
+}
+
+// New constructs a Journal over store.
+func New(store Store, opts ...Option) *Journal {
+	j := &Journal{store: store, clock: clock.RealClock{}, dispatchDelay: DefaultDispatchDelay}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(j)
+		}
+	}
+	return j
+}
+
+// Record journals a pending delivery of payload to destination, before its first attempt.
+func (j *Journal) Record(ctx context.Context, destination, event string, payload []byte) (Delivery, error) {
+	destination, event = strings.TrimSpace(destination), strings.TrimSpace(event)
+	if destination == "" || event == "" {
+		return Delivery{}, fmt.Errorf("%w: destination and event are required", ErrInvalid)
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		return Delivery{}, fmt.Errorf("delivery: generate id: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	now := j.clock.Now().UTC()
+	d := Delivery{
+		ID:          id,
+		Destination: destination,
+		Event:       event,
+		Payload:     payload,
+		PayloadHash: sum[:],
+		Status:      StatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := j.store.Insert(ctx, d); err != nil {
+		return Delivery{}, fmt.Errorf("delivery: insert: %w", err)
+	}
+	return d, nil
+}
+
+// Attempt records the outcome of one attempt of id: succeeded when cause is nil,
+// failed otherwise.
+func (j *Journal) Attempt(ctx context.Context, id uuid.UUID, cause error) error {
+	status, lastError := StatusSucceeded, ""
+	if cause != nil {
+		status, lastError = StatusFailed, cause.Error()
+		if len(lastError) > maxErrorLength {
+			lastError = lastError[:maxErrorLength]
+		}
+	}
+	if err := j.store.Record(ctx, id, status, lastError, j.clock.Now().UTC()); err != nil {
+		return fmt.Errorf("delivery: record attempt of %s: %w", id, err)
+	}
+	return nil
+}
+
+// Pending returns up to limit deliveries waiting for an attempt, oldest first.
+func (j *Journal) Pending(ctx context.Context, limit int) ([]Delivery, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("%w: limit must be positive", ErrInvalid)
+	}
+	ds, err := j.store.Pending(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("delivery: pending: %w", err)
+	}
+	return ds, nil
+}
+
+// Dispatch attempts up to limit pending deliveries with send, oldest first, and
+// records the outcome of each. It returns how many succeeded. Deliveries updated
+// within the dispatch delay are left for a later run, so a first attempt still in
+// flight is not sent twice. Failed attempts wait for a Requeue.
+//
+// Run it from a single node at a time, such as the delivery.journal.dispatch job.
+func (j *Journal) Dispatch(ctx context.Context, send Sender, limit int) (int, error) {
+	due, err := j.Pending(ctx, limit)
+	if err != nil {
+		return 0, err
+	}
+	settled := j.clock.Now().Add(-j.dispatchDelay)
+	succeeded := 0
+	for _, d := range due {
+		if d.UpdatedAt.After(settled) {
+			// oldest first, the rest is fresher still
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return succeeded, err
+		}
+		cause := send(ctx, d)
+		if err := j.Attempt(ctx, d.ID, cause); err != nil {
+			return succeeded, err
+		}
+		if cause == nil {
+			succeeded++
+		}
+	}
+	return succeeded, nil
+}
+
+// Requeue sets the deliveries matching f back to pending, so they are attempted
+// again, and returns how many. Only failed and succeeded deliveries can be requeued.
+func (j *Journal) Requeue(ctx context.Context, f Filter) (int64, error) {
+	switch f.Status {
+	case "":
+		f.Status = StatusFailed
+	case StatusFailed, StatusSucceeded:
+	default:
+		return 0, fmt.Errorf("%w: status must be %q or %q", ErrInvalid, StatusFailed, StatusSucceeded)
+	}
+	if f.Since != nil && f.Until != nil && !f.Until.After(*f.Since) {
+		return 0, fmt.Errorf("%w: until must be after since", ErrInvalid)
+	}
+	n, err := j.store.Requeue(ctx, f, j.clock.Now().UTC())
+	if err != nil {
+		return 0, fmt.Errorf("delivery: requeue: %w", err)
+	}
+	return n, nil
+}
+
+// Purge deletes the finished deliveries older than the retention window and
+// returns how many. Pending deliveries are never purged. Without a retention it
+// deletes nothing.
+func (j *Journal) Purge(ctx context.Context) (int64, error) {
+	if j.retention <= 0 {
+		return 0, nil
+	}
+	n, err := j.store.Purge(ctx, j.clock.Now().Add(-j.retention))
+	if err != nil {
+		return n, fmt.Errorf("delivery: purge: %w", err)
+	}
+	return n, nil
+}
+
+// WithEvent marks the requests sent with ctx as deliveries of event, for a
+// sender journaling them such as httpclient.RecordingTransport.
+func WithEvent(ctx context.Context, event string) context.Context {
+	return context.WithValue(ctx, eventKey{}, event)
+}
+
+// EventFrom returns the event WithEvent stored in ctx, if any.
+func EventFrom(ctx context.Context) (string, bool) {
+	event, ok := ctx.Value(eventKey{}).(string)
+	return event, ok && event != ""
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/delivery"
+	"app/modules/httpclient"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+// memStore is a delivery.Store in memory, with the semantics of the Postgres one.
+type memStore struct {
+	mu   sync.Mutex
+	rows []delivery.Delivery
+}
+
+func (s *memStore) Insert(_ context.Context, d delivery.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rows = append(s.rows, d)
+	return nil
+}
+
+func (s *memStore) Record(_ context.Context, id uuid.UUID, status delivery.Status, lastError string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.rows {
+		if s.rows[i].ID == id {
+			s.rows[i].Status, s.rows[i].LastError, s.rows[i].UpdatedAt = status, lastError, at
+			s.rows[i].Attempts++
+			return nil
+		}
+	}
+	return delivery.ErrNotFound
+}
+
+func (s *memStore) Pending(_ context.Context, limit int) ([]delivery.Delivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []delivery.Delivery
+	for _, d := range s.rows {
+		if d.Status == delivery.StatusPending {
+			out = append(out, d)
+		}
+	}
+	slices.SortFunc(out, func(a, b delivery.Delivery) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	return out[:min(limit, len(out))], nil
+}
+
+func (s *memStore) Requeue(_ context.Context, f delivery.Filter, at time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var n int64
+	for i, d := range s.rows {
+		if d.Status != f.Status ||
+			f.Destination != "" && d.Destination != f.Destination ||
+			f.Event != "" && d.Event != f.Event ||
+			f.Since != nil && d.CreatedAt.Before(*f.Since) ||
+			f.Until != nil && !d.CreatedAt.Before(*f.Until) {
+			continue
+		}
+		s.rows[i].Status, s.rows[i].UpdatedAt = delivery.StatusPending, at
+		n++
+	}
+	return n, nil
+}
+
+func (s *memStore) Purge(_ context.Context, cutoff time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	before := len(s.rows)
+	s.rows = slices.DeleteFunc(s.rows, func(d delivery.Delivery) bool {
+		return d.Status != delivery.StatusPending && d.UpdatedAt.Before(cutoff)
+	})
+	return int64(before - len(s.rows)), nil
+}
+
+func (s *memStore) get(t *testing.T, id uuid.UUID) delivery.Delivery {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, d := range s.rows {
+		if d.ID == id {
+			return d
+		}
+	}
+	t.Fatalf("delivery %s not in the store", id)
+	return delivery.Delivery{}
+}
+
+func (s *memStore) only(t *testing.T) delivery.Delivery {
+	t.Helper()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.rows) != 1 {
+		t.Fatalf("store holds %d deliveries, want 1", len(s.rows))
+	}
+	return s.rows[0]
+}
+
+func TestJournalRequeueRedispatchPurge(t *testing.T) {
+	ctx := context.Background()
+	clk := clock.NewFake(time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC))
+	store := &memStore{}
+	journal := delivery.New(store,
+		delivery.WithClock(clk),
+		delivery.WithRetention(24*time.Hour),
+		delivery.WithDispatchDelay(time.Minute),
+	)
+
+	var up atomic.Bool
+	var bodies []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		mu.Unlock()
+		if !up.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	hook := srv.URL + "/hooks"
+
+	// the first attempt goes through the recording transport and fails
+	client := httpclient.New(httpclient.WithJournal(journal))
+	req, _ := http.NewRequestWithContext(delivery.WithEvent(ctx, "profile.created"), http.MethodPost, hook, strings.NewReader(`{"id":1}`))
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	resp.Body.Close()
+	d := store.only(t)
+	if d.Status != delivery.StatusFailed || d.Attempts != 1 || d.Event != "profile.created" || d.Destination != hook {
+		t.Fatalf("after the first attempt: %+v", d)
+	}
+	if !strings.Contains(d.LastError, "502") || string(d.Payload) != `{"id":1}` {
+		t.Fatalf("after the first attempt: error %q, payload %q", d.LastError, d.Payload)
+	}
+
+	// requests without an event are not journaled
+	plain, _ := http.NewRequestWithContext(ctx, http.MethodPost, hook, strings.NewReader("x"))
+	if resp, err := client.Do(plain); err == nil {
+		resp.Body.Close()
+	}
+	store.only(t)
+
+	redeliver := httpclient.DeliverySender(httpclient.New(httpclient.WithJournal(journal)))
+	if n, err := journal.Dispatch(ctx, redeliver, 10); err != nil || n != 0 {
+		t.Fatalf("Dispatch before requeue = %d, %v; want 0, nil", n, err)
+	}
+
+	if _, err := journal.Requeue(ctx, delivery.Filter{Status: delivery.StatusPending}); err == nil {
+		t.Fatal("Requeue of pending deliveries succeeded, want ErrInvalid")
+	}
+	n, err := journal.Requeue(ctx, delivery.Filter{Destination: hook})
+	if err != nil || n != 1 {
+		t.Fatalf("Requeue = %d, %v; want 1, nil", n, err)
+	}
+	if got := store.get(t, d.ID); got.Status != delivery.StatusPending || got.Attempts != 1 {
+		t.Fatalf("after requeue: %+v", got)
+	}
+
+	// a delivery just requeued is left alone for the dispatch delay
+	up.Store(true)
+	if n, err := journal.Dispatch(ctx, redeliver, 10); err != nil || n != 0 {
+		t.Fatalf("Dispatch within the delay = %d, %v; want 0, nil", n, err)
+	}
+	clk.Advance(2 * time.Minute)
+	if n, err := journal.Dispatch(ctx, redeliver, 10); err != nil || n != 1 {
+		t.Fatalf("Dispatch = %d, %v; want 1, nil", n, err)
+	}
+	got := store.get(t, d.ID)
+	if got.Status != delivery.StatusSucceeded || got.Attempts != 2 || got.LastError != "" {
+		t.Fatalf("after the second attempt: %+v", got)
+	}
+	store.only(t)
+	mu.Lock()
+	if want := []string{`{"id":1}`, "x", `{"id":1}`}; !slices.Equal(bodies, want) {
+		t.Fatalf("destination received %q, want %q", bodies, want)
+	}
+	mu.Unlock()
+
+	// finished deliveries go once past the retention, pending ones stay
+	stuck, err := journal.Record(ctx, hook, "profile.deleted", []byte(`{"id":2}`))
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	clk.Advance(23 * time.Hour)
+	if n, err := journal.Purge(ctx); err != nil || n != 0 {
+		t.Fatalf("Purge within retention = %d, %v; want 0, nil", n, err)
+	}
+	clk.Advance(2 * time.Hour)
+	if n, err := journal.Purge(ctx); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v; want 1, nil", n, err)
+	}
+	if d := store.only(t); d.ID != stuck.ID || d.Status != delivery.StatusPending {
+		t.Fatalf("after purge: %+v", d)
+	}
+}
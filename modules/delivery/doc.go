@@ -0,0 +1,34 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package delivery keeps a durable journal of outbound deliveries (webhook calls,
+// published events), so the ones that failed can be found and replayed.
+//
+// A sender records each delivery before its first attempt and reports the outcome
+// of every attempt. The journal keeps the payload with its SHA-256 hash, the status
+// and how many attempts were made. httpclient.WithJournal does both for the
+// requests whose context names an event:
+//
+//	client := httpclient.New(httpclient.WithJournal(journal))
+//	req, _ := http.NewRequestWithContext(delivery.WithEvent(ctx, "profile.created"), http.MethodPost, hookURL, body)
+//	resp, err := client.Do(req)
+//
+// The delivery.journal.dispatch job runs Dispatch, which sends the pending
+// deliveries again: those whose sender stopped before reporting, and those an
+// operator requeued. RequeueHandler lets operators requeue failed deliveries by
+// filter, and the delivery.journal.purge job deletes finished deliveries past the
+// retention window. The Postgres store lives in modules/db/postgres/delivery:
+//
+//	POST /admin/deliveries/requeue {"status", "destination", "event", "since", "until"} -> {"requeued": n}
+package delivery
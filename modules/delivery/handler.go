@@ -0,0 +1,82 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package delivery
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"app/modules/auth"
+	"app/modules/authz"
+	"app/modules/middleware/problem"
+)
+
+// maxFilterBody bounds the POST body, a filter is a few hundred bytes.
+const maxFilterBody = 16 << 10
+
+// RequeueHandler serves POST with a Filter for operators holding scope: the
+// matching deliveries go back to pending, and it answers 200 with how many.
+func RequeueHandler(journal *Journal, scope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.Require(r.Context(), scope); err != nil {
+			authz.WriteProblem(w, err)
+			return
+		}
+		principal, _ := auth.PrincipalFrom(r.Context())
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+
+		var f Filter
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFilterBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil {
+			problem.Write(w, problem.BadRequest("invalid JSON body"))
+			return
+		}
+		n, err := journal.Requeue(r.Context(), f)
+		if errors.Is(err, ErrInvalid) {
+			problem.Write(w, problem.New(
+				problem.WithTitle(http.StatusText(http.StatusUnprocessableEntity)),
+				problem.WithStatus(http.StatusUnprocessableEntity),
+				problem.WithDetail(err.Error()),
+				problem.WithCode("delivery.invalid_filter"),
+			))
+			return
+		}
+		if err != nil {
+			slog.ErrorContext(r.Context(), "delivery: requeue failed", slog.Any("error", err))
+			problem.Write(w, problem.ServiceUnavailable("deliveries could not be requeued, retry later"))
+			return
+		}
+		slog.InfoContext(r.Context(), "delivery: deliveries requeued by operator",
+			slog.String("operator", principal.Subject),
+			slog.Any("filter", f),
+			slog.Int64("requeued", n),
+		)
+		writeJSON(w, http.StatusOK, map[string]int64{"requeued": n})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
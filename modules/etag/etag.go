@@ -12,28 +12,151 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package etag derives the entity tags of the API.
+//
+// Items get a strong tag that carries their version, so If-Match can be turned
+// back into the version a conditional write compares against, next to a SHA-256
+// digest of the item identity and version:
+//
+//	"1.<version, 16 hex digits>.<digest, 24 hex digits>"
+//
+// Collections get a tag of a fixed length whatever their size:
+//
+//	"c1.<digest, 32 hex digits>"
+//
+// The leading number is the format version. ParseETag still accepts the legacy
+// v:<version> tags issued before, so clients holding one can keep writing. Check
+// the digest with Matches before writing: ParseETag alone accepts the tag of any
+// entity at the version.
 package etag
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 )
 
+// ErrInvalidETag is returned by ParseETag for a tag that is not an item tag.
+var ErrInvalidETag = errors.New("invalid etag format")
+
+const (
+	legacyPrefix     = "v:"
+	itemPrefix       = "1."
+	collectionPrefix = "c1."
+
+	// hex digits of the version and digest in an item tag
+	versionDigits = 16
+	digestDigits  = 24
+)
+
 type ETaggable interface {
+	// V is the decimal version of the entity.
 	V() string
 }
 
-// For HTTP headers, remember that the actual header value is usually quoted:
-//
-// fmt.Sprintf("%q", ETag(obj))
+// Scoped is an ETaggable whose tags also depend on its identity, so two entities
+// at the same version never share a tag.
+type Scoped interface {
+	ETaggable
+	ETagScope() string
+}
+
+// ETag returns the quoted strong entity tag of obj, ready for the ETag header.
 func ETag(obj ETaggable) string {
-	return "v:" + obj.V()
+	var scope string
+	if s, ok := obj.(Scoped); ok {
+		scope = s.ETagScope()
+	}
+	v := obj.V()
+	version := v
+	if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+		version = fmt.Sprintf("%0*x", versionDigits, n)
+	}
+	sum := sha256.Sum256([]byte(scope + "\x00" + v))
+	return `"` + itemPrefix + version + "." + hex.EncodeToString(sum[:])[:digestDigits] + `"`
+}
+
+// Collection returns the quoted entity tag of a list, from the query it answers
+// (filters, page) and the tags of its items, in order.
+func Collection(query string, items ...ETaggable) string {
+	h := sha256.New()
+	h.Write([]byte(query))
+	for _, item := range items {
+		h.Write([]byte{0})
+		h.Write([]byte(ETag(item)))
+	}
+	return `"` + collectionPrefix + hex.EncodeToString(h.Sum(nil))[:32] + `"`
 }
 
+// ParseETag returns the decimal version carried by an item tag, in the current
+// format, quoted, or in the legacy one, unquoted. Weak tags are rejected: If-Match
+// compares strongly. The digest is not checked, see Matches.
 func ParseETag(etag string) (string, error) {
-	const prefix = "v:"
-	if !strings.HasPrefix(etag, prefix) {
-		return "", fmt.Errorf("invalid etag format")
+	etag = strings.TrimSpace(etag)
+	if v, ok := strings.CutPrefix(etag, legacyPrefix); ok {
+		if _, err := strconv.ParseUint(v, 10, 64); err != nil {
+			return "", ErrInvalidETag
+		}
+		return v, nil
+	}
+	if len(etag) < 2 || etag[0] != '"' || etag[len(etag)-1] != '"' {
+		return "", ErrInvalidETag
+	}
+	rest, ok := strings.CutPrefix(etag[1:len(etag)-1], itemPrefix)
+	if !ok {
+		return "", ErrInvalidETag
+	}
+	version, digest, ok := strings.Cut(rest, ".")
+	if !ok || len(version) != versionDigits || len(digest) != digestDigits {
+		return "", ErrInvalidETag
+	}
+	n, err := strconv.ParseUint(version, 16, 64)
+	if err != nil {
+		return "", ErrInvalidETag
+	}
+	return strconv.FormatUint(n, 10), nil
+}
+
+// Matches reports whether tag, as accepted by ParseETag, is the tag of obj, so an
+// If-Match copied from another entity at the same version is refused. Legacy tags
+// carry no digest and match any entity at their version.
+func Matches(tag string, obj ETaggable) bool {
+	tag = strings.TrimSpace(tag)
+	if v, ok := strings.CutPrefix(tag, legacyPrefix); ok {
+		n, err := strconv.ParseUint(v, 10, 64)
+		return err == nil && strconv.FormatUint(n, 10) == obj.V()
+	}
+	return tag == ETag(obj)
+}
+
+// NoneMatch reports whether an If-None-Match header value lists tag, so a
+// conditional GET can answer 304 Not Modified. The comparison is weak: a W/ prefix
+// and quotes around each entity tag are ignored, and "*" matches any tag.
+func NoneMatch(header, tag string) bool {
+	header = strings.TrimSpace(header)
+	if header == "" || tag == "" {
+		return false
+	}
+	if header == "*" {
+		return true
+	}
+	tag = opaque(tag)
+	for _, candidate := range strings.Split(header, ",") {
+		if opaque(candidate) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// opaque strips the weakness indicator and quotes of an entity tag.
+func opaque(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "W/")
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
 	}
-	return strings.TrimPrefix(etag, prefix), nil
+	return s
 }
@@ -0,0 +1,31 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import "time"
+
+// Config configures the gRPC listener.
+type Config struct {
+	// Enabled serves gRPC on Addr next to the HTTP server.
+	Enabled bool   `env:"ENABLED" envDefault:"false"`
+	Addr    string `env:"ADDR" envDefault:":9090"`
+	// Reflection lists the services to clients such as grpcurl.
+	Reflection bool `env:"REFLECTION" envDefault:"true"`
+
+	// RateLimit allows that many calls per RateWindow and method to each caller,
+	// zero disables rate limiting.
+	RateLimit  int64         `env:"RATE_LIMIT" envDefault:"100"`
+	RateWindow time.Duration `env:"RATE_WINDOW" envDefault:"1m"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcserver serves gRPC services next to the HTTP server, with the same
+// cross-cutting concerns as the middleware chain of the REST API.
+//
+// New installs, outermost first:
+//
+//   - OpenTelemetry tracing and metrics (otelgrpc stats handler)
+//   - Recover, answering panics with codes.Internal
+//   - the interceptors given with WithUnaryInterceptors: Authenticate,
+//     WithEnforcer, RateLimit, RequireScopes, ...
+//   - Errors, mapping errors that are not a gRPC status through a problem,
+//     see StatusFromProblem
+//
+// Every service registered with WithService is reported by the standard gRPC
+// health service, following the readiness function of WithReadiness, and listed by
+// server reflection when enabled.
+package grpcserver
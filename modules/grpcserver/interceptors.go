@@ -0,0 +1,214 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/modules/auth"
+	"app/modules/authz"
+	authmw "app/modules/middleware/auth"
+	rl "app/modules/ratelimit"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Recover answers a panicking call with codes.Internal and logs the stack, as the
+// Recover middleware does for HTTP.
+func Recover() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				slog.ErrorContext(ctx, "grpc: panic recovered",
+					slog.String("method", info.FullMethod),
+					slog.Any("panic", rec),
+					slog.String("stack", string(debug.Stack())),
+				)
+				resp, err = nil, status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// Errors turns the errors of handlers that are not a gRPC status yet into one,
+// through the problem mapper returns for them. Errors of 5xx problems are logged.
+func Errors(mapper ErrorMapper) grpc.UnaryServerInterceptor {
+	if mapper == nil {
+		mapper = DefaultErrorMapper
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		if err == nil {
+			return resp, nil
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		prob := mapper(err)
+		level := slog.LevelDebug
+		if prob.Status >= 500 {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "grpc: call failed",
+			slog.String("method", info.FullMethod),
+			slog.Int("status", prob.Status),
+			slog.Any("error", err),
+		)
+		return nil, StatusFromProblem(prob).Err()
+	}
+}
+
+// Authenticate verifies the bearer token of the "authorization" metadata and
+// stores its principal in the context, as the JWT middleware does for HTTP. Calls
+// without a token go on anonymously, for RequireScopes to reject.
+func Authenticate(a *authmw.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token, ok := bearerToken(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if _, ok := auth.PrincipalFrom(ctx); ok {
+			return handler(ctx, req)
+		}
+
+		principal, err := a.Authenticate(ctx, token)
+		switch {
+		case errors.Is(err, authmw.ErrKeysUnavailable):
+			slog.ErrorContext(ctx, "auth: cannot verify token", slog.Any("error", err))
+			return nil, status.Error(codes.Unavailable, "authentication temporarily unavailable")
+		case err != nil:
+			slog.InfoContext(ctx, "auth: token rejected", slog.Any("error", err))
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+		return handler(auth.WithPrincipal(ctx, principal), req)
+	}
+}
+
+// WithEnforcer makes e the enforcer of authz.Require for the handlers, as
+// authz.Middleware does for HTTP.
+func WithEnforcer(e authz.Enforcer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(authz.WithEnforcer(ctx, e), req)
+	}
+}
+
+// RequireScopes checks the scopes listed for each full method name with
+// authz.Require. Methods that are not listed need no scope. Unless enforce is set,
+// denied calls are only logged, as the spec scopes of the REST API are.
+func RequireScopes(scopes map[string][]string, enforce bool) grpc.UnaryServerInterceptor {
+	if !enforce {
+		slog.Warn("authz: grpc scope enforcement disabled, calls are only audited")
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		required, ok := scopes[info.FullMethod]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if err := authz.Require(ctx, required...); err != nil {
+			p, _ := auth.PrincipalFrom(ctx)
+			subject := ""
+			if p != nil {
+				subject = p.Subject
+			}
+			slog.InfoContext(ctx, "authz: call denied",
+				slog.String("method", info.FullMethod),
+				slog.String("subject", subject),
+				slog.Any("required", required),
+				slog.Bool("enforced", enforce),
+			)
+			if enforce {
+				return nil, StatusFromProblem(DefaultErrorMapper(err)).Err()
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitKey identifies the caller of a call; an empty key skips rate limiting.
+type RateLimitKey func(ctx context.Context, fullMethod string) rl.Key
+
+// RateLimit allows calls while limiter allows their key, and answers the others
+// with codes.ResourceExhausted and a "retry-after" trailer in seconds. A failing
+// limiter lets calls through, as the HTTP rate limiter does when degraded. Health
+// checks are never limited.
+func RateLimit(limiter rl.RateLimiter, key RateLimitKey) grpc.UnaryServerInterceptor {
+	if key == nil {
+		key = CallerKey
+	}
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/") {
+			return handler(ctx, req)
+		}
+		k := key(ctx, info.FullMethod)
+		if k == "" {
+			return handler(ctx, req)
+		}
+		res, err := limiter.Allow(ctx, k)
+		if err != nil {
+			slog.WarnContext(ctx, "grpc: rate limiter unavailable, allowing call",
+				slog.String("method", info.FullMethod),
+				slog.Any("error", err),
+			)
+			return handler(ctx, req)
+		}
+		if !res.Allowed {
+			retryAfter := int64(res.RetryAfter.Round(time.Second) / time.Second)
+			_ = grpc.SetTrailer(ctx, metadata.Pairs("retry-after", strconv.FormatInt(max(retryAfter, 1), 10)))
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// CallerKey keys calls by method and principal subject, or by peer address for
+// anonymous callers.
+func CallerKey(ctx context.Context, fullMethod string) rl.Key {
+	if p, ok := auth.PrincipalFrom(ctx); ok && p.Subject != "" {
+		return rl.Key("grpc:" + fullMethod + ":sub:" + p.Subject)
+	}
+	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+		host := pr.Addr.String()
+		if i := strings.LastIndexByte(host, ':'); i > 0 {
+			host = host[:i]
+		}
+		return rl.Key("grpc:" + fullMethod + ":ip:" + host)
+	}
+	return ""
+}
+
+func bearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	for _, v := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "bearer") && token != "" {
+			return strings.TrimSpace(token), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type (
+	// Server serves gRPC services on one listener.
+	Server struct {
+		addr string
+		srv  *grpc.Server
+
+		health   *health.Server
+		services []string
+		ready    func() bool
+		// how often ready is polled for the health service
+		readyInterval time.Duration
+
+		reflection   bool
+		interceptors []grpc.UnaryServerInterceptor
+		errorMapper  ErrorMapper
+		serverOpts   []grpc.ServerOption
+		registers    []func(grpc.ServiceRegistrar)
+
+		stop  context.CancelFunc
+		wg    sync.WaitGroup
+		errCh chan error
+	}
+
+	// Option configures a Server.
+	Option func(*Server)
+)
+
+// WithService registers impl as the service of desc, e.g.
+// WithService(&pb.ProfileService_ServiceDesc, profileServer).
+func WithService(desc *grpc.ServiceDesc, impl any) Option {
+	return func(s *Server) {
+		s.services = append(s.services, desc.ServiceName)
+		s.registers = append(s.registers, func(r grpc.ServiceRegistrar) {
+			r.RegisterService(desc, impl)
+		})
+	}
+}
+
+// WithUnaryInterceptors adds interceptors, in the order provided, between Recover
+// and Errors.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.interceptors = append(s.interceptors, interceptors...)
+	}
+}
+
+// WithErrorMapper sets how Errors maps the errors of handlers to problems.
+func WithErrorMapper(fn ErrorMapper) Option {
+	return func(s *Server) {
+		if fn != nil {
+			s.errorMapper = fn
+		}
+	}
+}
+
+// WithReadiness makes the health service report NOT_SERVING while ready returns
+// false, e.g. health.Monitor.Ready.
+func WithReadiness(ready func() bool) Option {
+	return func(s *Server) {
+		s.ready = ready
+	}
+}
+
+// WithReflection registers the server reflection service.
+func WithReflection(enabled bool) Option {
+	return func(s *Server) {
+		s.reflection = enabled
+	}
+}
+
+// WithServerOptions passes options, e.g. credentials or keepalive, to grpc.NewServer.
+func WithServerOptions(opts ...grpc.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOpts = append(s.serverOpts, opts...)
+	}
+}
+
+// New constructs a Server listening on addr once started.
+func New(addr string, opts ...Option) (*Server, error) {
+	if addr == "" {
+		return nil, errors.New("grpcserver: empty address")
+	}
+	s := &Server{
+		addr:          addr,
+		health:        health.NewServer(),
+		readyInterval: time.Second,
+		errorMapper:   DefaultErrorMapper,
+		errCh:         make(chan error, 1),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+
+	interceptors := append([]grpc.UnaryServerInterceptor{Recover()}, s.interceptors...)
+	interceptors = append(interceptors, Errors(s.errorMapper))
+	serverOpts := append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+	}, s.serverOpts...)
+	s.srv = grpc.NewServer(serverOpts...)
+
+	for _, register := range s.registers {
+		register(s.srv)
+	}
+	healthpb.RegisterHealthServer(s.srv, s.health)
+	if s.reflection {
+		reflection.Register(s.srv)
+	}
+	for _, name := range s.services {
+		slog.Info("registered grpc service", slog.String("service", name))
+	}
+	return s, nil
+}
+
+// Start binds the listener and serves in the background.
+//
+// Bind errors are returned synchronously; errors while serving are reported on Err.
+func (s *Server) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("grpcserver: listen %s: %w", s.addr, err)
+	}
+	s.setServing(s.ready == nil || s.ready())
+
+	watchCtx, stop := context.WithCancel(context.WithoutCancel(ctx))
+	s.stop = stop
+	if s.ready != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.watchReadiness(watchCtx)
+		}()
+	}
+
+	go func() {
+		slog.InfoContext(ctx, "grpc server listening", slog.String("addr", ln.Addr().String()))
+		if err := s.srv.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			s.reportErr(fmt.Errorf("grpcserver: serve: %w", err))
+		}
+	}()
+	return nil
+}
+
+// Shutdown reports NOT_SERVING, then stops accepting calls and waits for the
+// running ones to finish. Calls still running when ctx is done are cancelled.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s.stop != nil {
+		s.stop()
+	}
+	s.wg.Wait()
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.srv.Stop()
+		<-done
+		return fmt.Errorf("grpcserver: shutdown: %w", ctx.Err())
+	}
+}
+
+// Err reports errors encountered while serving.
+func (s *Server) Err() <-chan error {
+	return s.errCh
+}
+
+func (s *Server) watchReadiness(ctx context.Context) {
+	ticker := time.NewTicker(s.readyInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.setServing(s.ready())
+		}
+	}
+}
+
+// setServing reports the overall status ("") and the one of every service.
+func (s *Server) setServing(serving bool) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !serving {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	s.health.SetServingStatus("", status)
+	for _, name := range s.services {
+		s.health.SetServingStatus(name, status)
+	}
+}
+
+func (s *Server) reportErr(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
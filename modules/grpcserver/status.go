@@ -0,0 +1,126 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"app/modules/authz"
+	"app/modules/middleware/problem"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain is the ErrorInfo domain of the statuses built by StatusFromProblem.
+const ErrorDomain = "app"
+
+// ErrorMapper maps a handler error to the problem the REST API would answer with.
+type ErrorMapper func(err error) *problem.Problem
+
+// DefaultErrorMapper maps authorization errors with authz.Problem, cancellations
+// and deadlines to 499 and 504, and anything else to 500.
+func DefaultErrorMapper(err error) *problem.Problem {
+	if p, ok := authz.Problem(err); ok {
+		return p
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return problem.New(problem.WithStatus(499), problem.WithTitle("Client Closed Request"), problem.WithDetail("request canceled"))
+	case errors.Is(err, context.DeadlineExceeded):
+		return problem.New(problem.WithStatus(http.StatusGatewayTimeout), problem.WithDetail("operation timed out"))
+	}
+	return problem.Internal("server error")
+}
+
+// StatusFromProblem translates a problem to a gRPC status: its HTTP status to the
+// matching code, its detail to the message, its code and type to an ErrorInfo and
+// its invalid params to a BadRequest.
+func StatusFromProblem(p *problem.Problem) *status.Status {
+	msg := p.Title
+	if p.Detail != nil && *p.Detail != "" {
+		msg = *p.Detail
+	}
+	st := status.New(CodeFromHTTPStatus(p.Status), msg)
+
+	var details []protoadapt.MessageV1
+	if p.Code != nil || (p.Type != nil && *p.Type != "about:blank") {
+		info := &errdetails.ErrorInfo{Domain: ErrorDomain, Metadata: map[string]string{}}
+		if p.Code != nil {
+			info.Reason = *p.Code
+		}
+		if p.Type != nil && *p.Type != "about:blank" {
+			info.Metadata["type"] = *p.Type
+		}
+		details = append(details, info)
+	}
+	if p.InvalidParams != nil && len(*p.InvalidParams) > 0 {
+		br := &errdetails.BadRequest{}
+		for _, ip := range *p.InvalidParams {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{Field: ip.Name, Description: ip.Reason})
+		}
+		details = append(details, br)
+	}
+	if len(details) == 0 {
+		return st
+	}
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st
+	}
+	return withDetails
+}
+
+// CodeFromHTTPStatus maps an HTTP status to the gRPC code clients handle the same
+// way, the inverse of the mapping gRPC-HTTP gateways use.
+func CodeFromHTTPStatus(s int) codes.Code {
+	switch s {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return codes.OK
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired, http.StatusFailedDependency:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case 499:
+		return codes.Canceled
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	switch {
+	case s >= 500:
+		return codes.Internal
+	case s >= 400:
+		return codes.FailedPrecondition
+	}
+	return codes.Unknown
+}
@@ -0,0 +1,31 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hashring maps keys to nodes with consistent hashing.
+//
+// Every node is placed on a 64-bit ring at many pseudo-random points (virtual
+// nodes) and a key belongs to the first point at or after its own hash. Adding
+// a node only moves the keys it now owns, about 1/n of them, and removing one
+// only moves the keys it owned; every other key keeps its node. That makes it
+// suitable for spreading counters or locks over several Redis deployments:
+//
+//	ring := hashring.New()
+//	ring.Add("redis-a", "redis-b", "redis-c")
+//	node, _ := ring.Get("ratelimit:client-42")
+//	client := clients[node]
+//
+// The mapping only depends on the set of nodes and their weights, not on the
+// order they were added in, so every instance configured with the same nodes
+// agrees on it.
+package hashring
@@ -0,0 +1,241 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashring
+
+import (
+	"cmp"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"sync"
+)
+
+// DefaultReplicas is the number of virtual nodes per unit of weight. With 160,
+// the load of each node typically stays within 10% of the mean.
+const DefaultReplicas = 160
+
+type (
+	// HashFunc hashes keys and virtual node labels onto the ring.
+	HashFunc func(data []byte) uint64
+
+	// Ring is a consistent hash ring. It is safe for concurrent use; lookups only
+	// take a read lock.
+	Ring struct {
+		mu sync.RWMutex
+
+		replicas int
+		hash     HashFunc
+
+		// node name -> weight
+		nodes map[string]int
+		// sorted by hash, then owner, so collisions resolve the same everywhere
+		points []point
+	}
+
+	// Option configures a Ring.
+	Option func(*Ring)
+
+	point struct {
+		hash  uint64
+		owner string
+	}
+)
+
+// WithReplicas sets the virtual nodes per unit of weight. More replicas spread
+// keys more evenly at the cost of memory and slower membership changes.
+func WithReplicas(n int) Option {
+	return func(r *Ring) {
+		if n > 0 {
+			r.replicas = n
+		}
+	}
+}
+
+// WithHash replaces the default hash, FNV-1a with a 64-bit finalizer.
+func WithHash(h HashFunc) Option {
+	return func(r *Ring) {
+		if h != nil {
+			r.hash = h
+		}
+	}
+}
+
+// New constructs an empty Ring.
+func New(opts ...Option) *Ring {
+	r := &Ring{
+		replicas: DefaultReplicas,
+		hash:     defaultHash,
+		nodes:    make(map[string]int),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r
+}
+
+// Add places nodes on the ring with weight 1. Nodes already present keep their weight.
+func (r *Ring) Add(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, n := range nodes {
+		if _, ok := r.nodes[n]; ok || n == "" {
+			continue
+		}
+		r.nodes[n] = 1
+		changed = true
+	}
+	if changed {
+		r.rebuild()
+	}
+}
+
+// AddWeighted places node on the ring, or changes its weight. A node of weight 2
+// receives about twice the keys of a node of weight 1. Weights below 1 remove it.
+func (r *Ring) AddWeighted(node string, weight int) {
+	if node == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if weight < 1 {
+		if _, ok := r.nodes[node]; !ok {
+			return
+		}
+		delete(r.nodes, node)
+	} else {
+		if r.nodes[node] == weight {
+			return
+		}
+		r.nodes[node] = weight
+	}
+	r.rebuild()
+}
+
+// Remove takes nodes off the ring; their keys move to the following nodes.
+func (r *Ring) Remove(nodes ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := false
+	for _, n := range nodes {
+		if _, ok := r.nodes[n]; ok {
+			delete(r.nodes, n)
+			changed = true
+		}
+	}
+	if changed {
+		r.rebuild()
+	}
+}
+
+// Get returns the node owning key. ok is false when the ring is empty.
+func (r *Ring) Get(key string) (node string, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return "", false
+	}
+	return r.points[r.search(r.hash([]byte(key)))].owner, true
+}
+
+// GetN returns up to n distinct nodes for key, the owner first, then the nodes
+// that would take over if the previous ones were removed. It suits replicating
+// a key or falling back to the next node when the owner is unavailable.
+func (r *Ring) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	n = min(n, len(r.nodes))
+	if n <= 0 {
+		return nil
+	}
+
+	out := make([]string, 0, n)
+	start := r.search(r.hash([]byte(key)))
+	for i := 0; i < len(r.points) && len(out) < n; i++ {
+		owner := r.points[(start+i)%len(r.points)].owner
+		if !slices.Contains(out, owner) {
+			out = append(out, owner)
+		}
+	}
+	return out
+}
+
+// Nodes returns the nodes on the ring, sorted.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.nodes))
+	for n := range r.nodes {
+		out = append(out, n)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// Len reports the number of nodes on the ring.
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.nodes)
+}
+
+// search returns the index of the first point at or after h, wrapping around.
+// Callers must hold r.mu.
+func (r *Ring) search(h uint64) int {
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+	if i == len(r.points) {
+		return 0
+	}
+	return i
+}
+
+// rebuild recomputes every point from the node set, so the ring does not depend on
+// the order of membership changes. Callers must hold r.mu.
+func (r *Ring) rebuild() {
+	total := 0
+	for _, w := range r.nodes {
+		total += w * r.replicas
+	}
+	points := make([]point, 0, total)
+	buf := make([]byte, 0, 64)
+	for node, w := range r.nodes {
+		for i := 0; i < w*r.replicas; i++ {
+			buf = append(append(append(buf[:0], node...), '#'), strconv.Itoa(i)...)
+			points = append(points, point{hash: r.hash(buf), owner: node})
+		}
+	}
+	slices.SortFunc(points, func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), cmp.Compare(a.owner, b.owner))
+	})
+	r.points = points
+}
+
+// defaultHash is FNV-1a followed by the splitmix64 finalizer: FNV alone keeps
+// labels differing in their last bytes ("node#1", "node#2") too close on the ring.
+func defaultHash(data []byte) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
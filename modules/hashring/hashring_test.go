@@ -0,0 +1,206 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hashring
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+const testKeys = 100_000
+
+func nodeNames(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("redis-%d", i)
+	}
+	return out
+}
+
+func assign(r *Ring) map[string]string {
+	out := make(map[string]string, testKeys)
+	for i := 0; i < testKeys; i++ {
+		key := fmt.Sprintf("ratelimit:client-%d", i)
+		node, ok := r.Get(key)
+		if !ok {
+			panic("empty ring")
+		}
+		out[key] = node
+	}
+	return out
+}
+
+func load(owners map[string]string) map[string]int {
+	out := make(map[string]int)
+	for _, node := range owners {
+		out[node]++
+	}
+	return out
+}
+
+func TestGet_EmptyRing(t *testing.T) {
+	r := New()
+	if node, ok := r.Get("k"); ok || node != "" {
+		t.Fatalf("Get on empty ring = %q, %v", node, ok)
+	}
+	if got := r.GetN("k", 3); got != nil {
+		t.Fatalf("GetN on empty ring = %v", got)
+	}
+}
+
+// Each node's share must stay close to the mean, and the spread must be far
+// below what a few replicas per node would give.
+func TestDistribution(t *testing.T) {
+	for _, nodes := range []int{3, 10, 32} {
+		t.Run(fmt.Sprint(nodes), func(t *testing.T) {
+			r := New()
+			r.Add(nodeNames(nodes)...)
+			counts := load(assign(r))
+			if len(counts) != nodes {
+				t.Fatalf("%d nodes own keys, want %d", len(counts), nodes)
+			}
+
+			mean := float64(testKeys) / float64(nodes)
+			var sq float64
+			for node, c := range counts {
+				dev := (float64(c) - mean) / mean
+				if math.Abs(dev) > 0.25 {
+					t.Errorf("%s owns %d keys, %.1f%% off the mean %.0f", node, c, dev*100, mean)
+				}
+				sq += dev * dev
+			}
+			if stddev := math.Sqrt(sq / float64(nodes)); stddev > 0.10 {
+				t.Errorf("relative standard deviation %.3f, want <= 0.10", stddev)
+			}
+		})
+	}
+}
+
+func TestAddWeighted_Distribution(t *testing.T) {
+	r := New()
+	r.Add("a", "b")
+	r.AddWeighted("c", 2)
+	counts := load(assign(r))
+	ratio := float64(counts["c"]) / float64(counts["a"]+counts["b"]) * 2
+	if ratio < 1.6 || ratio > 2.4 {
+		t.Fatalf("weight-2 node owns %.2fx the keys of a weight-1 node (%v)", ratio, counts)
+	}
+}
+
+// Adding a node may only move keys onto it, and roughly its fair share of them.
+func TestAdd_OnlyMovesKeysToNewNode(t *testing.T) {
+	r := New()
+	r.Add(nodeNames(9)...)
+	before := assign(r)
+
+	r.Add("redis-new")
+	after := assign(r)
+
+	moved := 0
+	for key, node := range after {
+		if node == before[key] {
+			continue
+		}
+		if node != "redis-new" {
+			t.Fatalf("key %s moved from %s to %s, not to the new node", key, before[key], node)
+		}
+		moved++
+	}
+	if share := float64(moved) / testKeys; share < 0.07 || share > 0.13 {
+		t.Fatalf("%.1f%% of keys moved, want about 10%%", share*100)
+	}
+}
+
+// Removing a node may only move the keys it owned.
+func TestRemove_OnlyMovesKeysOfRemovedNode(t *testing.T) {
+	r := New()
+	r.Add(nodeNames(10)...)
+	before := assign(r)
+
+	r.Remove("redis-3")
+	after := assign(r)
+
+	for key, node := range after {
+		if before[key] != "redis-3" && node != before[key] {
+			t.Fatalf("key %s moved from %s to %s", key, before[key], node)
+		}
+		if node == "redis-3" {
+			t.Fatalf("key %s still maps to a removed node", key)
+		}
+	}
+
+	// and adding it back restores the original mapping
+	r.Add("redis-3")
+	for key, node := range assign(r) {
+		if node != before[key] {
+			t.Fatalf("key %s maps to %s after re-adding, want %s", key, node, before[key])
+		}
+	}
+}
+
+// Instances configured with the same nodes must agree, whatever the order and history.
+func TestMapping_IndependentOfOrder(t *testing.T) {
+	nodes := nodeNames(8)
+	a := New()
+	a.Add(nodes...)
+
+	reversed := slices.Clone(nodes)
+	slices.Reverse(reversed)
+	b := New()
+	b.Add("extra")
+	b.Add(reversed...)
+	b.Remove("extra")
+
+	for i := 0; i < 10_000; i++ {
+		key := fmt.Sprint(i)
+		na, _ := a.Get(key)
+		nb, _ := b.Get(key)
+		if na != nb {
+			t.Fatalf("key %s: %s vs %s", key, na, nb)
+		}
+	}
+}
+
+func TestGetN(t *testing.T) {
+	r := New()
+	r.Add(nodeNames(5)...)
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint(i)
+		got := r.GetN(key, 3)
+		if len(got) != 3 {
+			t.Fatalf("GetN(%q, 3) = %v", key, got)
+		}
+		if owner, _ := r.Get(key); got[0] != owner {
+			t.Fatalf("GetN(%q)[0] = %s, owner is %s", key, got[0], owner)
+		}
+		if len(slices.Compact(slices.Sorted(slices.Values(got)))) != 3 {
+			t.Fatalf("GetN(%q) returned duplicates: %v", key, got)
+		}
+
+		// the second node is where the key goes once the owner is gone
+		r.Remove(got[0])
+		if next, _ := r.Get(key); next != got[1] {
+			t.Fatalf("after removing %s, %q maps to %s, want %s", got[0], key, next, got[1])
+		}
+		r.Add(got[0])
+	}
+
+	if got := r.GetN("k", 10); len(got) != 5 {
+		t.Fatalf("GetN beyond ring size = %v, want all 5 nodes", got)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import "time"
+
+type (
+	// Config sets how often watched dependencies are probed and when the process drains.
+	Config struct {
+		Interval time.Duration `env:"INTERVAL" envDefault:"5s"`
+		// Timeout bounds each probe; a probe running out of time counts as a failure.
+		Timeout time.Duration `env:"TIMEOUT" envDefault:"1s"`
+
+		// DrainAfter drains the process once it stayed unready that long, see
+		// WithDrainAfter. Zero never drains before shutdown.
+		DrainAfter time.Duration `env:"DRAIN_AFTER"`
+		// DrainDelay is how long shutdown keeps serving after draining, so load
+		// balancers see /readyz fail before the listener closes.
+		DrainDelay time.Duration `env:"DRAIN_DELAY" envDefault:"0s"`
+	}
+)
@@ -0,0 +1,39 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health tracks the health of the dependencies a process relies on.
+//
+// Every dependency gets a Tracker, a small state machine fed with probe outcomes:
+//
+//	healthy --failure--> degraded --N consecutive failures--> unhealthy
+//	unhealthy --success--> degraded --M consecutive successes--> healthy
+//
+// The thresholds give the machine hysteresis: a single failed probe only degrades
+// a dependency and a single success does not declare it recovered, so flapping
+// connections do not flap readiness. Transitions, and only transitions, are logged,
+// counted as app_health_transitions_total and passed to TransitionHooks.
+//
+// A Registry aggregates the trackers for /readyz:
+//
+//	reg := health.NewRegistry()
+//	reg.Watch("postgres", 5*time.Second, time.Second, checkPostgres)
+//	cache := reg.Track("redis", health.WithCritical(false)) // fed with cache.Observe
+//	go reg.Run(ctx)
+//	mux.Handle("GET /healthz", reg.LiveHandler())
+//	mux.Handle("GET /readyz", reg.ReadyHandler())
+//
+// The process is not ready while a critical dependency is unhealthy; non-critical
+// ones only degrade the reported status. A Monitor over the Registry reports
+// readiness changes and drains the process, see Monitor.
+package health
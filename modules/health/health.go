@@ -0,0 +1,286 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+)
+
+// Status is the health of a dependency or of the whole process.
+type Status string
+
+const (
+	StatusHealthy   Status = "healthy"
+	StatusDegraded  Status = "degraded"
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// rank orders statuses from worst to best, and is the value of the status gauge.
+func (s Status) rank() int64 {
+	switch s {
+	case StatusHealthy:
+		return 2
+	case StatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type (
+	// Check probes a dependency, returning nil when it is usable.
+	Check func(ctx context.Context) error
+
+	// Transition describes a status change of one dependency.
+	Transition struct {
+		Dependency string
+		From, To   Status
+		At         time.Time
+		// Err is the probe error that caused the change, nil on recoveries.
+		Err error
+	}
+
+	// TransitionHook is called after every status change (alerts, draining, ...).
+	TransitionHook func(ctx context.Context, t Transition)
+
+	// DependencyState is a snapshot of a Tracker.
+	DependencyState struct {
+		Name     string    `json:"name"`
+		Status   Status    `json:"status"`
+		Since    time.Time `json:"since"`
+		Critical bool      `json:"critical"`
+		// consecutive failed probes, zero while the last probe succeeded
+		Failures  int    `json:"consecutive_failures,omitempty"`
+		LastError string `json:"last_error,omitempty"`
+		// when the last probe outcome was observed, and how long a polled probe took
+		LastChecked time.Time `json:"last_checked,omitzero"`
+		Latency     string    `json:"latency,omitempty"`
+	}
+
+	// Tracker is the health state machine of one dependency, see the package doc.
+	// It is safe for concurrent use.
+	Tracker struct {
+		name     string
+		clock    clock.Clock
+		critical bool
+
+		unhealthyAfter int
+		recoverAfter   int
+		hooks          []TransitionHook
+
+		mu        sync.Mutex
+		status    Status
+		since     time.Time
+		failures  int
+		successes int
+		lastErr   error
+		checkedAt time.Time
+		latency   time.Duration
+	}
+
+	// Option configures a Tracker.
+	Option func(*Tracker)
+)
+
+// WithUnhealthyAfter sets how many consecutive failures turn a dependency unhealthy.
+// Defaults to 3.
+func WithUnhealthyAfter(n int) Option {
+	return func(t *Tracker) {
+		if n > 0 {
+			t.unhealthyAfter = n
+		}
+	}
+}
+
+// WithRecoverAfter sets how many consecutive successes turn a dependency healthy again.
+// Defaults to 2.
+func WithRecoverAfter(n int) Option {
+	return func(t *Tracker) {
+		if n > 0 {
+			t.recoverAfter = n
+		}
+	}
+}
+
+// WithCritical sets whether an unhealthy dependency makes the process unready.
+// Defaults to true; dependencies with a fallback (cache, local counters) should not be.
+func WithCritical(critical bool) Option {
+	return func(t *Tracker) {
+		t.critical = critical
+	}
+}
+
+// WithTransitionHook registers a callback for status changes.
+func WithTransitionHook(fn TransitionHook) Option {
+	return func(t *Tracker) {
+		if fn != nil {
+			t.hooks = append(t.hooks, fn)
+		}
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(t *Tracker) {
+		if c != nil {
+			t.clock = c
+		}
+	}
+}
+
+// NewTracker constructs a Tracker for the named dependency. It starts healthy:
+// dependencies are expected to be checked once before the process starts serving.
+func NewTracker(name string, opts ...Option) *Tracker {
+	t := &Tracker{
+		name:           name,
+		clock:          clock.RealClock{},
+		critical:       true,
+		unhealthyAfter: 3,
+		recoverAfter:   2,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(t)
+		}
+	}
+	t.status = StatusHealthy
+	t.since = t.clock.Now()
+	return t
+}
+
+// Name returns the dependency name.
+func (t *Tracker) Name() string { return t.name }
+
+// Status returns the current status.
+func (t *Tracker) Status() Status {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+// State returns a snapshot of the tracker.
+func (t *Tracker) State() DependencyState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := DependencyState{
+		Name:     t.name,
+		Status:   t.status,
+		Since:    t.since,
+		Critical: t.critical,
+		Failures: t.failures,
+	}
+	if t.lastErr != nil {
+		s.LastError = t.lastErr.Error()
+	}
+	s.LastChecked = t.checkedAt
+	if t.latency > 0 {
+		s.Latency = t.latency.String()
+	}
+	return s
+}
+
+// Observe feeds the outcome of one probe and returns the resulting status.
+func (t *Tracker) Observe(ctx context.Context, err error) Status {
+	return t.observe(ctx, err, 0)
+}
+
+func (t *Tracker) observe(ctx context.Context, err error, latency time.Duration) Status {
+	t.mu.Lock()
+	t.checkedAt, t.latency = t.clock.Now(), latency
+	next := t.status
+	if err == nil {
+		t.failures, t.lastErr = 0, nil
+		t.successes++
+		switch {
+		case t.status == StatusHealthy:
+		case t.successes >= t.recoverAfter:
+			next = StatusHealthy
+		case t.status == StatusUnhealthy:
+			next = StatusDegraded
+		}
+	} else {
+		t.successes, t.lastErr = 0, err
+		t.failures++
+		switch {
+		case t.failures >= t.unhealthyAfter:
+			next = StatusUnhealthy
+		case t.status == StatusHealthy:
+			next = StatusDegraded
+		}
+	}
+
+	if next == t.status {
+		t.mu.Unlock()
+		return next
+	}
+	tr := Transition{Dependency: t.name, From: t.status, To: next, At: t.clock.Now(), Err: err}
+	t.status, t.since = next, tr.At
+	hooks := t.hooks
+	t.mu.Unlock()
+
+	t.notify(ctx, tr)
+	for _, h := range hooks {
+		h(ctx, tr)
+	}
+	return next
+}
+
+// Poll runs check right away and then every interval, each bounded by timeout,
+// feeding the outcomes to Observe until ctx is cancelled.
+func (t *Tracker) Poll(ctx context.Context, interval, timeout time.Duration, check Check) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := t.clock.Now()
+		err := check(checkCtx)
+		latency := t.clock.Now().Sub(start)
+		cancel()
+		if ctx.Err() != nil {
+			// shutting down, not a health signal
+			return
+		}
+		t.observe(ctx, err, latency)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (t *Tracker) notify(ctx context.Context, tr Transition) {
+	attrs := []any{
+		slog.String("dependency", tr.Dependency),
+		slog.String("from", string(tr.From)),
+		slog.String("to", string(tr.To)),
+		slog.Bool("critical", t.critical),
+	}
+	if tr.Err != nil {
+		attrs = append(attrs, slog.Any("error", tr.Err))
+	}
+	if tr.To.rank() < tr.From.rank() {
+		slog.WarnContext(ctx, "health: dependency state changed", attrs...)
+		return
+	}
+	slog.InfoContext(ctx, "health: dependency state changed", attrs...)
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type (
+	// Monitor runs the probes of a Registry and owns the readiness of the process.
+	//
+	// The process is ready while the aggregated Report is not unhealthy and it is
+	// not draining. Readiness changes are logged as "health: readiness changed" and
+	// recorded as the app_health_ready gauge (1 ready, 0 not).
+	//
+	// Drain takes the process out of rotation for good: /readyz answers 503 while
+	// requests are still served, and the OnDrain callbacks run (usually
+	// server.Drain, which stops keeping connections alive). Call it on shutdown
+	// before the listener closes, or have the Monitor call it with WithDrainAfter.
+	Monitor struct {
+		registry   *Registry
+		clock      clock.Timers
+		drainAfter time.Duration
+
+		mu         sync.Mutex
+		ready      bool
+		since      time.Time
+		draining   bool
+		stopDrain  func() bool
+		drainHooks []func(ctx context.Context)
+
+		readyGauge metric.Int64Gauge
+	}
+
+	// MonitorOption configures a Monitor.
+	MonitorOption func(*Monitor)
+)
+
+// WithDrainAfter makes the Monitor drain once the process stayed unready for d,
+// so clients holding keep-alive connections move to healthy instances. Zero,
+// the default, never drains on its own.
+func WithDrainAfter(d time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		if d > 0 {
+			m.drainAfter = d
+		}
+	}
+}
+
+// WithMonitorClock overrides the time source (useful in tests).
+func WithMonitorClock(c clock.Timers) MonitorOption {
+	return func(m *Monitor) {
+		if c != nil {
+			m.clock = c
+		}
+	}
+}
+
+// NewMonitor constructs a Monitor over reg. It starts ready, like the trackers.
+func NewMonitor(reg *Registry, opts ...MonitorOption) *Monitor {
+	m := &Monitor{registry: reg, clock: clock.RealClock{}, ready: true}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	m.since = m.clock.Now()
+
+	var err error
+	if m.readyGauge, err = otel.Meter(instrumentationName).Int64Gauge("app_health_ready",
+		metric.WithDescription("Process readiness: 1 ready, 0 unready or draining"),
+	); err != nil {
+		slog.Warn("health: ready gauge not created", slog.Any("error", err))
+	}
+	reg.OnTransition(func(ctx context.Context, _ Transition) { m.evaluate(ctx) })
+	return m
+}
+
+// Run polls the watched dependencies until ctx is cancelled, see Registry.Run.
+func (m *Monitor) Run(ctx context.Context) {
+	m.record(ctx, m.Ready())
+	m.registry.Run(ctx)
+
+	m.mu.Lock()
+	if m.stopDrain != nil {
+		m.stopDrain()
+		m.stopDrain = nil
+	}
+	m.mu.Unlock()
+}
+
+// Ready reports whether the process should receive traffic.
+func (m *Monitor) Ready() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ready && !m.draining
+}
+
+// Draining reports whether Drain was called.
+func (m *Monitor) Draining() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.draining
+}
+
+// Report returns the Report of the Registry, flagged while draining.
+func (m *Monitor) Report() Report {
+	rep := m.registry.Report()
+	rep.Draining = m.Draining()
+	return rep
+}
+
+// OnDrain registers a callback run once when the process starts draining.
+func (m *Monitor) OnDrain(fn func(ctx context.Context)) {
+	if fn == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.drainHooks = append(m.drainHooks, fn)
+}
+
+// Drain takes the process out of rotation and runs the OnDrain callbacks.
+// It only acts once; the process does not come back from draining.
+func (m *Monitor) Drain(ctx context.Context) {
+	m.mu.Lock()
+	if m.draining {
+		m.mu.Unlock()
+		return
+	}
+	m.draining = true
+	hooks := m.drainHooks
+	m.mu.Unlock()
+
+	slog.WarnContext(ctx, "health: draining")
+	m.record(ctx, false)
+	for _, h := range hooks {
+		h(ctx)
+	}
+}
+
+// ReadyHandler serves the Report as JSON, with 503 while the process is not
+// ready and 200 otherwise.
+func (m *Monitor) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeReport(w, req, m.Report(), m.Ready())
+	})
+}
+
+// LiveHandler is the Registry's LiveHandler.
+func (m *Monitor) LiveHandler() http.Handler {
+	return m.registry.LiveHandler()
+}
+
+// evaluate recomputes readiness after a dependency transition.
+func (m *Monitor) evaluate(ctx context.Context) {
+	ready := m.registry.Report().Status != StatusUnhealthy
+
+	m.mu.Lock()
+	if ready == m.ready {
+		m.mu.Unlock()
+		return
+	}
+	now := m.clock.Now()
+	unreadyFor := now.Sub(m.since)
+	m.ready, m.since = ready, now
+	if m.stopDrain != nil {
+		m.stopDrain()
+		m.stopDrain = nil
+	}
+	if !ready && m.drainAfter > 0 {
+		// the callback starts from a detached ctx: the transition's ctx ends with its probe
+		drainCtx := context.WithoutCancel(ctx)
+		m.stopDrain = m.clock.AfterFunc(m.drainAfter, func() {
+			if !m.stillUnready() {
+				return
+			}
+			slog.WarnContext(drainCtx, "health: unready for too long", slog.Duration("after", m.drainAfter))
+			m.Drain(drainCtx)
+		})
+	}
+	draining := m.draining
+	m.mu.Unlock()
+
+	if ready {
+		slog.InfoContext(ctx, "health: readiness changed", slog.Bool("ready", true), slog.Duration("unready_for", unreadyFor))
+	} else {
+		slog.WarnContext(ctx, "health: readiness changed", slog.Bool("ready", false))
+	}
+	m.record(ctx, ready && !draining)
+}
+
+func (m *Monitor) stillUnready() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return !m.ready
+}
+
+func (m *Monitor) record(ctx context.Context, ready bool) {
+	if m.readyGauge == nil {
+		return
+	}
+	var v int64
+	if ready {
+		v = 1
+	}
+	m.readyGauge.Record(ctx, v)
+}
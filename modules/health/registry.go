@@ -0,0 +1,209 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "app/modules/health"
+
+type (
+	// Report is the aggregated health served by /readyz.
+	Report struct {
+		Status Status `json:"status"`
+		// Draining is set while a Monitor takes the process out of rotation.
+		Draining     bool              `json:"draining,omitempty"`
+		Dependencies []DependencyState `json:"dependencies"`
+	}
+
+	// Registry aggregates the trackers of a process.
+	Registry struct {
+		mu       sync.Mutex
+		trackers []*Tracker
+		probes   []probe
+		defaults []Option
+		hooks    []TransitionHook
+
+		transitions metric.Int64Counter
+		status      metric.Int64Gauge
+	}
+
+	// probe is a check polled by Run.
+	probe struct {
+		tracker           *Tracker
+		interval, timeout time.Duration
+		check             Check
+	}
+)
+
+// NewRegistry constructs an empty Registry. opts apply to every tracker it creates,
+// before the options given to Track.
+func NewRegistry(opts ...Option) *Registry {
+	r := &Registry{defaults: opts}
+
+	meter := otel.Meter(instrumentationName)
+	var err error
+	if r.transitions, err = meter.Int64Counter("app_health_transitions_total",
+		metric.WithDescription("Dependency health status changes"),
+		metric.WithUnit("{transition}"),
+	); err != nil {
+		slog.Warn("health: transition counter not created", slog.Any("error", err))
+	}
+	if r.status, err = meter.Int64Gauge("app_health_status",
+		metric.WithDescription("Dependency health status: 2 healthy, 1 degraded, 0 unhealthy"),
+	); err != nil {
+		slog.Warn("health: status gauge not created", slog.Any("error", err))
+	}
+	return r
+}
+
+// Track creates and registers the tracker of the named dependency.
+func (r *Registry) Track(name string, opts ...Option) *Tracker {
+	all := make([]Option, 0, len(r.defaults)+len(opts)+1)
+	all = append(all, r.defaults...)
+	all = append(all, opts...)
+	all = append(all, WithTransitionHook(r.record))
+	t := NewTracker(name, all...)
+
+	r.mu.Lock()
+	r.trackers = append(r.trackers, t)
+	r.mu.Unlock()
+
+	if r.status != nil {
+		r.status.Record(context.Background(), t.Status().rank(),
+			metric.WithAttributes(attribute.String("dependency", name)))
+	}
+	return t
+}
+
+// OnTransition registers a callback for the status changes of every dependency,
+// including those tracked later.
+func (r *Registry) OnTransition(fn TransitionHook) {
+	if fn == nil {
+		return
+	}
+	r.mu.Lock()
+	r.hooks = append(r.hooks, fn)
+	r.mu.Unlock()
+}
+
+// Watch tracks the named dependency like Track, and has Run poll check every
+// interval, each probe bounded by timeout. Watch dependencies before calling Run.
+func (r *Registry) Watch(name string, interval, timeout time.Duration, check Check, opts ...Option) *Tracker {
+	t := r.Track(name, opts...)
+	r.mu.Lock()
+	r.probes = append(r.probes, probe{tracker: t, interval: interval, timeout: timeout, check: check})
+	r.mu.Unlock()
+	return t
+}
+
+// Run polls every watched dependency until ctx is cancelled, then waits for the
+// probes in flight. /readyz serves the last outcomes, so a probe never runs on
+// the request path and a slow dependency cannot stall the load balancer.
+func (r *Registry) Run(ctx context.Context) {
+	r.mu.Lock()
+	probes := append([]probe(nil), r.probes...)
+	r.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, p := range probes {
+		wg.Go(func() { p.tracker.Poll(ctx, p.interval, p.timeout, p.check) })
+	}
+	wg.Wait()
+}
+
+// Report aggregates every tracker: unhealthy when a critical dependency is,
+// degraded when any dependency is not healthy, healthy otherwise.
+func (r *Registry) Report() Report {
+	r.mu.Lock()
+	trackers := append([]*Tracker(nil), r.trackers...)
+	r.mu.Unlock()
+
+	rep := Report{Status: StatusHealthy, Dependencies: make([]DependencyState, 0, len(trackers))}
+	for _, t := range trackers {
+		s := t.State()
+		rep.Dependencies = append(rep.Dependencies, s)
+		switch {
+		case s.Status == StatusUnhealthy && s.Critical:
+			rep.Status = StatusUnhealthy
+		case s.Status != StatusHealthy && rep.Status == StatusHealthy:
+			rep.Status = StatusDegraded
+		}
+	}
+	return rep
+}
+
+// ReadyHandler serves the Report as JSON, with 503 while it is unhealthy so load
+// balancers stop routing to the process, and 200 otherwise.
+func (r *Registry) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rep := r.Report()
+		writeReport(w, req, rep, rep.Status != StatusUnhealthy)
+	})
+}
+
+func writeReport(w http.ResponseWriter, req *http.Request, rep Report, ready bool) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if ready {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if req.Method == http.MethodHead {
+		return
+	}
+	_ = json.NewEncoder(w).Encode(rep)
+}
+
+// LiveHandler answers 204 as long as the process serves requests. Liveness does
+// not look at dependencies: restarting a process because its database is down
+// only adds load to the database.
+func (r *Registry) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func (r *Registry) record(ctx context.Context, t Transition) {
+	dep := attribute.String("dependency", t.Dependency)
+	if r.transitions != nil {
+		r.transitions.Add(ctx, 1, metric.WithAttributes(dep,
+			attribute.String("from", string(t.From)),
+			attribute.String("to", string(t.To)),
+		))
+	}
+	if r.status != nil {
+		r.status.Record(ctx, t.To.rank(), metric.WithAttributes(dep))
+	}
+
+	r.mu.Lock()
+	hooks := r.hooks
+	r.mu.Unlock()
+	for _, h := range hooks {
+		h(ctx, t)
+	}
+}
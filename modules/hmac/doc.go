@@ -0,0 +1,32 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hmac signs values and requests with a shared HMAC-SHA256 secret.
+//
+// HMACSigner signs opaque values such as the pagination cursors the service hands
+// to its clients, and checks them when they come back.
+//
+// RequestSigner and RequestVerifier sign webhook-style requests in X-Signature,
+// with a timestamp and a nonce the receiver remembers in a NonceStore:
+//
+//	X-Signature: v1=<hex>
+//	X-Signature-Timestamp: 1760400000
+//	X-Signature-Nonce: <base64url>
+//
+// modules/httpsig signs requests with HMAC keys too. New services calling each
+// other should use httpsig: its signature also covers the host, names the key so
+// secrets rotate per caller, accepts Ed25519 keys, and resolves the caller to an
+// auth.Principal. RequestSigner is for receivers and senders that only share one
+// secret, such as third-party webhooks, guarded by middleware.VerifyRequestSignatures.
+package hmac
@@ -0,0 +1,283 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hmac
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/internal/httpreq"
+)
+
+// Request signing for webhook-style callers: the sender signs
+//
+//	<timestamp>.<nonce>.<method> <request target>\n<body>
+//
+// with a shared secret and sends the result as X-Signature: v1=<hex>, next to the
+// timestamp and nonce it covers. The request target is the path and query, so a
+// signature is only valid for the endpoint it was made for. The receiver rejects
+// stale timestamps and nonces it has already seen, so a captured request cannot be
+// replayed.
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Signature-Timestamp"
+	NonceHeader     = "X-Signature-Nonce"
+
+	// signatureVersion prefixes the signature so the scheme can evolve.
+	signatureVersion = "v1="
+
+	// DefaultTolerance is how far the timestamp may drift from the receiver clock.
+	DefaultTolerance = 5 * time.Minute
+	// DefaultMaxBodyBytes bounds how much of a body the verifier reads.
+	DefaultMaxBodyBytes int64 = 1 << 20
+)
+
+var (
+	ErrMissingSignature = errors.New("hmac: missing request signature")
+	ErrInvalidSignature = errors.New("hmac: invalid request signature")
+	ErrStaleTimestamp   = errors.New("hmac: request timestamp outside tolerance")
+	ErrReplayed         = errors.New("hmac: request nonce already used")
+	ErrMissingNonces    = errors.New("hmac: missing nonce store")
+)
+
+type (
+	// NonceStore remembers the nonces of verified requests.
+	//
+	// Remember reports whether nonce was new, and keeps it for at least ttl.
+	NonceStore interface {
+		Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+	}
+
+	// RequestSigner signs outgoing requests, see SignatureHeader.
+	RequestSigner struct {
+		key   []byte
+		clock clock.Clock
+	}
+
+	// RequestVerifier checks the signature of incoming requests.
+	RequestVerifier struct {
+		keys         [][]byte
+		clock        clock.Clock
+		nonces       NonceStore
+		tolerance    time.Duration
+		maxBodyBytes int64
+	}
+
+	// RequestOption configures a RequestSigner or RequestVerifier.
+	RequestOption func(*requestOptions)
+
+	requestOptions struct {
+		clock        clock.Clock
+		tolerance    time.Duration
+		maxBodyBytes int64
+		previous     [][]byte
+	}
+)
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) RequestOption {
+	return func(o *requestOptions) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
+
+// WithTolerance sets how far the timestamp may be from the verifier clock, in
+// either direction. Verifier only; defaults to DefaultTolerance.
+func WithTolerance(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		if d > 0 {
+			o.tolerance = d
+		}
+	}
+}
+
+// WithMaxBodyBytes bounds the body the verifier reads. Verifier only; defaults
+// to DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) RequestOption {
+	return func(o *requestOptions) {
+		if n > 0 {
+			o.maxBodyBytes = n
+		}
+	}
+}
+
+// WithPreviousSecrets keeps accepting signatures made with retired secrets while
+// senders rotate. Verifier only.
+func WithPreviousSecrets(secrets ...[]byte) RequestOption {
+	return func(o *requestOptions) {
+		for _, s := range secrets {
+			if len(s) > 0 {
+				o.previous = append(o.previous, s)
+			}
+		}
+	}
+}
+
+func buildRequestOptions(opts []RequestOption) requestOptions {
+	o := requestOptions{
+		clock:        clock.RealClock{},
+		tolerance:    DefaultTolerance,
+		maxBodyBytes: DefaultMaxBodyBytes,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// NewRequestSigner constructs a RequestSigner using the shared secret.
+func NewRequestSigner(secret []byte, opts ...RequestOption) (*RequestSigner, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingKey
+	}
+	o := buildRequestOptions(opts)
+	return &RequestSigner{key: secret, clock: o.clock}, nil
+}
+
+// Sign sets the timestamp, nonce and signature headers on r.
+//
+// The body is read to sign it and replaced with an equivalent reader.
+// Sign modifies r; RoundTrippers must sign a clone.
+func (s *RequestSigner) Sign(r *http.Request) error {
+	body, err := httpreq.ReadBody(r, -1)
+	if err != nil {
+		return fmt.Errorf("hmac: read body: %w", err)
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(s.clock.Now().Unix(), 10)
+
+	r.Header.Set(TimestampHeader, ts)
+	r.Header.Set(NonceHeader, nonce)
+	r.Header.Set(SignatureHeader, signatureVersion+hex.EncodeToString(requestMAC(s.key, ts, nonce, r.Method, httpreq.Target(r), body)))
+	return nil
+}
+
+// NewRequestVerifier constructs a RequestVerifier accepting signatures made with
+// secret (and WithPreviousSecrets). nonces remembers the requests already
+// verified, such as a redis.NonceCache; without it a request could be replayed
+// within the tolerance.
+func NewRequestVerifier(secret []byte, nonces NonceStore, opts ...RequestOption) (*RequestVerifier, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingKey
+	}
+	if nonces == nil {
+		return nil, ErrMissingNonces
+	}
+	o := buildRequestOptions(opts)
+	return &RequestVerifier{
+		keys:         append([][]byte{secret}, o.previous...),
+		clock:        o.clock,
+		nonces:       nonces,
+		tolerance:    o.tolerance,
+		maxBodyBytes: o.maxBodyBytes,
+	}, nil
+}
+
+// Verify checks the signature of r, then claims its nonce.
+//
+// The body is read to check the signature and replaced with an equivalent
+// reader, so handlers still see it. Errors of the NonceStore are returned
+// wrapped as they are; the others match one of the Err* sentinels.
+func (v *RequestVerifier) Verify(r *http.Request) error {
+	header := r.Header.Get(SignatureHeader)
+	if header == "" {
+		return ErrMissingSignature
+	}
+	ts, nonce := r.Header.Get(TimestampHeader), r.Header.Get(NonceHeader)
+	if ts == "" || nonce == "" {
+		return fmt.Errorf("%w: missing %s or %s", ErrInvalidSignature, TimestampHeader, NonceHeader)
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp", ErrInvalidSignature)
+	}
+	if skew := v.clock.Now().Sub(time.Unix(unix, 0)); skew > v.tolerance || skew < -v.tolerance {
+		return ErrStaleTimestamp
+	}
+
+	body, err := httpreq.ReadBody(r, v.maxBodyBytes)
+	if err != nil {
+		return fmt.Errorf("%w: read body: %w", ErrInvalidSignature, err)
+	}
+	if !v.matches(header, ts, nonce, r.Method, httpreq.Target(r), body) {
+		return ErrInvalidSignature
+	}
+
+	// only authentic nonces are stored, so forged requests cannot burn them
+	// the timestamp check rejects the request once the nonce may be forgotten
+	fresh, err := v.nonces.Remember(r.Context(), nonce, 2*v.tolerance)
+	if err != nil {
+		return fmt.Errorf("hmac: remember nonce: %w", err)
+	}
+	if !fresh {
+		return ErrReplayed
+	}
+	return nil
+}
+
+// matches accepts any of the comma separated v1 signatures of header, so a sender
+// rotating its secret may send both.
+func (v *RequestVerifier) matches(header, ts, nonce, method, target string, body []byte) bool {
+	for sig := range strings.SplitSeq(header, ",") {
+		got, ok := strings.CutPrefix(strings.TrimSpace(sig), signatureVersion)
+		if !ok {
+			continue
+		}
+		mac, err := hex.DecodeString(got)
+		if err != nil {
+			continue
+		}
+		for _, key := range v.keys {
+			if hmac.Equal(requestMAC(key, ts, nonce, method, target, body), mac) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func requestMAC(key []byte, ts, nonce, method, target string, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	// a request target holds no raw newline, the body starts after the first one
+	_, _ = mac.Write([]byte(ts + "." + nonce + "." + method + " " + target + "\n"))
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("hmac: generate nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultTimeout bounds a whole request, including reading the response body.
+const DefaultTimeout = 10 * time.Second
+
+type (
+	// Option configures the client built by New.
+	Option func(*config)
+
+	config struct {
+		timeout time.Duration
+		base    http.RoundTripper
+		// applied innermost first
+		wrappers []func(http.RoundTripper) http.RoundTripper
+	}
+)
+
+// WithTimeout overrides DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithBaseTransport replaces the clone of http.DefaultTransport the decorators wrap.
+func WithBaseTransport(rt http.RoundTripper) Option {
+	return func(c *config) {
+		if rt != nil {
+			c.base = rt
+		}
+	}
+}
+
+// New builds a client. Without options it is http.DefaultTransport with DefaultTimeout.
+func New(opts ...Option) *http.Client {
+	c := &config{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	if c.base == nil {
+		c.base = http.DefaultTransport.(*http.Transport).Clone()
+	}
+
+	rt := c.base
+	for _, wrap := range c.wrappers {
+		rt = wrap(rt)
+	}
+	return &http.Client{Transport: rt, Timeout: c.timeout}
+}
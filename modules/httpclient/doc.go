@@ -0,0 +1,39 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpclient builds the *http.Client used for calls to other services.
+//
+// Behaviour is layered as http.RoundTripper decorators around a shared base
+// transport, so each concern (signing, throttling, ...) can be used on its own:
+//
+//	signer, _ := httpsig.SignerFromConfig(cfg.Signature)
+//	client := httpclient.New(
+//		httpclient.WithTimeout(5*time.Second),
+//		httpclient.WithSigner(signer),
+//	)
+//
+// WithThrottle keeps the whole fleet under a third party's rate limit by drawing
+// tokens from a bucket per downstream host shared through Redis:
+//
+//	throttle, _ := httpclient.ThrottleFromConfig(cfg.Egress, func(l httpclient.HostLimit) ratelimit.RateLimiter {
+//		return tokenbucket.New(redisClient, cfg.Key("egress"), l.Rate, l.Period, tokenbucket.WithCapacity(l.Burst))
+//	})
+//	client := httpclient.New(httpclient.WithSigner(signer), httpclient.WithThrottle(throttle))
+//
+// WithRequestID forwards the X-Request-Id of the request being served, so the
+// downstream logs correlate with ours.
+//
+// WithJournal records the requests sent with a delivery.WithEvent context in a
+// delivery.Journal, and DeliverySender replays them from it.
+package httpclient
@@ -0,0 +1,115 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"app/modules/delivery"
+	"app/modules/internal/httpreq"
+)
+
+// RecordingTransport journals the requests whose context names a delivery event
+// (see delivery.WithEvent): each is recorded as pending before it is sent, and the
+// outcome of the attempt is reported once it answers. Other requests are sent as
+// they are. A request that cannot be recorded is not sent.
+type RecordingTransport struct {
+	Base    http.RoundTripper
+	Journal *delivery.Journal
+}
+
+var _ http.RoundTripper = (*RecordingTransport)(nil)
+
+// WithJournal records outgoing deliveries in j. Add it last, so the outcome covers
+// signing and throttling too. A nil journal records nothing.
+func WithJournal(j *delivery.Journal) Option {
+	return func(c *config) {
+		if j == nil {
+			return
+		}
+		c.wrappers = append(c.wrappers, func(next http.RoundTripper) http.RoundTripper {
+			return &RecordingTransport{Base: next, Journal: j}
+		})
+	}
+}
+
+// RoundTrip implements http.RoundTripper. A 2xx answer is a successful attempt.
+func (t *RecordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	event, ok := delivery.EventFrom(r.Context())
+	if !ok {
+		return base.RoundTrip(r)
+	}
+
+	sent := r.Clone(r.Context())
+	payload, err := httpreq.ReadBody(sent, -1)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: read delivery payload: %w", err)
+	}
+	d, err := t.Journal.Record(r.Context(), r.URL.String(), event, payload)
+	if err != nil {
+		return nil, fmt.Errorf("httpclient: record delivery: %w", err)
+	}
+
+	resp, err := base.RoundTrip(sent)
+	// a delivery left pending is sent again by the dispatcher
+	if aerr := t.Journal.Attempt(context.WithoutCancel(r.Context()), d.ID, attemptOutcome(resp, err)); aerr != nil {
+		slog.WarnContext(r.Context(), "httpclient: delivery attempt not recorded",
+			slog.String("delivery.id", d.ID.String()),
+			slog.Any("error", aerr),
+		)
+	}
+	return resp, err
+}
+
+// DeliverySender returns a delivery.Sender replaying deliveries with client, for
+// delivery.Journal.Dispatch: the payload is POSTed to the destination as
+// application/json, and a 2xx answer is a success. Replays carry no event, so
+// a client built WithJournal does not record them again.
+func DeliverySender(client *http.Client) delivery.Sender {
+	return func(ctx context.Context, d delivery.Delivery) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Destination, bytes.NewReader(d.Payload))
+		if err != nil {
+			return fmt.Errorf("httpclient: build delivery request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		// drain a bounded prefix so the connection can be reused
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return attemptOutcome(resp, nil)
+	}
+}
+
+func attemptOutcome(resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("httpclient: destination answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"net/http"
+
+	"app/modules/requestid"
+)
+
+// RequestIDTransport forwards the request id of the outgoing request's context
+// (see requestid.From) in X-Request-Id, unless the caller set one.
+type RequestIDTransport struct {
+	Base http.RoundTripper
+}
+
+var _ http.RoundTripper = (*RequestIDTransport)(nil)
+
+// WithRequestID decorates the client with a RequestIDTransport. Add it after
+// WithSigner so the header is covered by the signature.
+func WithRequestID() Option {
+	return func(c *config) {
+		c.wrappers = append(c.wrappers, func(next http.RoundTripper) http.RoundTripper {
+			return &RequestIDTransport{Base: next}
+		})
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RequestIDTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	id := requestid.From(r.Context())
+	if id == "" || r.Header.Get(requestid.Header) != "" {
+		return base.RoundTrip(r)
+	}
+	r = r.Clone(r.Context())
+	r.Header.Set(requestid.Header, id)
+	return base.RoundTrip(r)
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+
+	"app/modules/hmac"
+	"app/modules/httpsig"
+)
+
+type (
+	// RequestSigner adds a signature to a request, see httpsig.Signer and hmac.RequestSigner.
+	RequestSigner interface {
+		Sign(r *http.Request) error
+	}
+
+	// SigningTransport signs every request with a RequestSigner before sending it.
+	SigningTransport struct {
+		Base   http.RoundTripper
+		Signer RequestSigner
+	}
+)
+
+var (
+	_ http.RoundTripper = (*SigningTransport)(nil)
+
+	_ RequestSigner = (*httpsig.Signer)(nil)
+	_ RequestSigner = (*hmac.RequestSigner)(nil)
+)
+
+// WithSigner signs outgoing requests. A nil signer leaves requests unsigned.
+func WithSigner(s *httpsig.Signer) Option {
+	return func(c *config) {
+		if s == nil {
+			return
+		}
+		c.wrappers = append(c.wrappers, signing(s))
+	}
+}
+
+// WithHMACSigner signs outgoing requests for webhook-style receivers guarded by
+// middleware.VerifyRequestSignatures. A nil signer leaves requests unsigned.
+func WithHMACSigner(s *hmac.RequestSigner) Option {
+	return func(c *config) {
+		if s == nil {
+			return
+		}
+		c.wrappers = append(c.wrappers, signing(s))
+	}
+}
+
+func signing(s RequestSigner) func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return &SigningTransport{Base: next, Signer: s}
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+//
+// The request is cloned before signing, as RoundTrippers must not modify their input.
+// Signing reads the body into memory, so redirects and retries replay the same bytes.
+func (t *SigningTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	signed := r.Clone(r.Context())
+	if err := t.Signer.Sign(signed); err != nil {
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+		return nil, fmt.Errorf("httpclient: sign request: %w", err)
+	}
+	return t.base().RoundTrip(signed)
+}
+
+func (t *SigningTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
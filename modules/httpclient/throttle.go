@@ -0,0 +1,220 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/modules/ratelimit"
+	"app/modules/retry"
+)
+
+// ErrThrottled is returned when a request would wait longer than the throttle allows.
+var ErrThrottled = errors.New("httpclient: outbound request throttled")
+
+type (
+	// Throttle delays outgoing requests to respect a downstream rate limit.
+	// Wait blocks until a request to host may be sent, or returns an error.
+	Throttle interface {
+		Wait(ctx context.Context, host string) error
+	}
+
+	// ThrottlingTransport waits on a Throttle before sending each request.
+	ThrottlingTransport struct {
+		Base     http.RoundTripper
+		Throttle Throttle
+	}
+
+	// ThrottleConfig configures egress throttling per downstream host.
+	//
+	// Hosts are written as "<host>=<rate>/<period>[:<burst>]", e.g.
+	//
+	//	EGRESS_HOSTS=api.stripe.com=90/1s:100,hooks.slack.com=1/1s
+	//
+	// where host matches the request's URL host (including a non-default port).
+	// Unlisted hosts are not throttled.
+	ThrottleConfig struct {
+		Enabled bool     `env:"ENABLED" envDefault:"false"`
+		Hosts   []string `env:"HOSTS" envSeparator:","`
+		// MaxWait bounds how long a request queues for a token before failing with ErrThrottled.
+		MaxWait time.Duration `env:"MAX_WAIT" envDefault:"5s"`
+	}
+
+	// HostLimit is the budget of one downstream host.
+	HostLimit struct {
+		Host   string
+		Rate   int64
+		Period time.Duration
+		// Burst is the bucket capacity; zero uses Rate.
+		Burst int64
+	}
+
+	// HostThrottle is a Throttle with a separate limiter per host.
+	//
+	// Backed by a shared store (tokenbucket.Factory), every instance draws from the
+	// same budget, so the fleet as a whole stays under the third party's limit.
+	HostThrottle struct {
+		limiters map[string]ratelimit.RateLimiter
+		maxWait  time.Duration
+	}
+
+	// HostLimiterFactory builds the limiter of one host, e.g. a tokenbucket.Bucket
+	// sized by the limit's Rate, Period and Burst.
+	HostLimiterFactory func(limit HostLimit) ratelimit.RateLimiter
+)
+
+var (
+	_ http.RoundTripper = (*ThrottlingTransport)(nil)
+	_ Throttle          = (*HostThrottle)(nil)
+)
+
+// WithThrottle makes requests wait on t before they are sent. A nil throttle is a no-op.
+//
+// Pass it after WithSigner so a request that queued is signed when it leaves
+// and its Date stays within the verifier's skew.
+func WithThrottle(t Throttle) Option {
+	return func(c *config) {
+		if t == nil {
+			return
+		}
+		c.wrappers = append(c.wrappers, func(next http.RoundTripper) http.RoundTripper {
+			return &ThrottlingTransport{Base: next, Throttle: t}
+		})
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *ThrottlingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if err := t.Throttle.Wait(r.Context(), r.URL.Host); err != nil {
+		if r.Body != nil {
+			_ = r.Body.Close()
+		}
+		return nil, err
+	}
+	if t.Base != nil {
+		return t.Base.RoundTrip(r)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// ParseHostLimit parses a limit written as "<host>=<rate>/<period>[:<burst>]".
+func ParseHostLimit(spec string) (HostLimit, error) {
+	host, budget, ok := strings.Cut(strings.TrimSpace(spec), "=")
+	if !ok || host == "" {
+		return HostLimit{}, fmt.Errorf("httpclient: host limit %q: want <host>=<rate>/<period>[:<burst>]", spec)
+	}
+	budget, burst, hasBurst := strings.Cut(budget, ":")
+	rate, period, ok := strings.Cut(budget, "/")
+	if !ok {
+		return HostLimit{}, fmt.Errorf("httpclient: host limit %q: missing period", spec)
+	}
+
+	l := HostLimit{Host: strings.ToLower(host)}
+	var err error
+	if l.Rate, err = strconv.ParseInt(rate, 10, 64); err != nil || l.Rate <= 0 {
+		return HostLimit{}, fmt.Errorf("httpclient: host limit %q: invalid rate", spec)
+	}
+	if l.Period, err = time.ParseDuration(period); err != nil || l.Period <= 0 {
+		return HostLimit{}, fmt.Errorf("httpclient: host limit %q: invalid period", spec)
+	}
+	if hasBurst {
+		if l.Burst, err = strconv.ParseInt(burst, 10, 64); err != nil || l.Burst < 0 {
+			return HostLimit{}, fmt.Errorf("httpclient: host limit %q: invalid burst", spec)
+		}
+	}
+	return l, nil
+}
+
+// NewHostThrottle builds a HostThrottle for limits, creating each limiter with newLimiter.
+//
+// maxWait <= 0 never waits: a request without a token fails immediately.
+func NewHostThrottle(newLimiter HostLimiterFactory, maxWait time.Duration, limits ...HostLimit) *HostThrottle {
+	t := &HostThrottle{
+		limiters: make(map[string]ratelimit.RateLimiter, len(limits)),
+		maxWait:  max(maxWait, 0),
+	}
+	for _, l := range limits {
+		t.limiters[strings.ToLower(l.Host)] = newLimiter(l)
+	}
+	return t
+}
+
+// ThrottleFromConfig builds a HostThrottle from cfg. It returns (nil, nil) when
+// throttling is disabled, which WithThrottle accepts.
+func ThrottleFromConfig(cfg ThrottleConfig, newLimiter HostLimiterFactory) (Throttle, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	limits := make([]HostLimit, 0, len(cfg.Hosts))
+	seen := make(map[string]struct{}, len(cfg.Hosts))
+	for _, spec := range cfg.Hosts {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		l, err := ParseHostLimit(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := seen[l.Host]; dup {
+			return nil, fmt.Errorf("httpclient: duplicate host limit %q", l.Host)
+		}
+		seen[l.Host] = struct{}{}
+		limits = append(limits, l)
+	}
+	return NewHostThrottle(newLimiter, cfg.MaxWait, limits...), nil
+}
+
+// Wait takes a token for host, sleeping until one is available or maxWait is exceeded.
+//
+// The throttle fails open: if the limiter's store is unreachable the request is sent
+// unthrottled rather than failing an otherwise healthy call.
+func (t *HostThrottle) Wait(ctx context.Context, host string) error {
+	host = strings.ToLower(host)
+	l, ok := t.limiters[host]
+	if !ok {
+		return nil
+	}
+
+	// a little jitter keeps the instances woken by the same refill from colliding
+	policy := retry.Policy{MaxElapsed: t.maxWait, Jitter: 0.1}
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		res, err := l.Allow(ctx, ratelimit.Key(host))
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			slog.WarnContext(ctx, "httpclient: egress throttle unavailable, sending unthrottled",
+				slog.String("host", host),
+				slog.Any("error", err),
+			)
+			return nil
+		}
+		if res.Allowed {
+			return nil
+		}
+		return retry.After(fmt.Errorf("%w: %s: retry in %s", ErrThrottled, host, res.RetryAfter), max(res.RetryAfter, time.Millisecond))
+	}, retry.If(func(err error) bool { return errors.Is(err, ErrThrottled) }), retry.WithName("httpclient.throttle"))
+	if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpsig
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Config configures request signing for both directions.
+//
+// Keys are written as "<id>:<alg>:<base64 material>[:<scope> <scope>...]", e.g.
+//
+//	HTTP_SIGNATURE_KEYS=billing:ed25519:MCowBQ...:profiles:read profiles:write
+//
+// where material is the shared secret for hmac-sha256, the 32-byte public key
+// (verification) or the 64-byte private key (signing) for ed25519.
+type Config struct {
+	// Enabled mounts the verification middleware.
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// Required rejects unsigned requests; otherwise they pass through unauthenticated
+	// and only requests carrying a signature are verified.
+	Required bool `env:"REQUIRED" envDefault:"false"`
+	// Keys accepted on incoming requests.
+	Keys []string `env:"KEYS" envSeparator:","`
+	// SigningKey signs outgoing requests of this service.
+	SigningKey string        `env:"SIGNING_KEY"`
+	MaxSkew    time.Duration `env:"MAX_SKEW" envDefault:"5m"`
+}
+
+// VerifierFromConfig builds a Verifier over the configured Keys, remembering
+// nonces in nonces.
+func VerifierFromConfig(cfg Config, nonces NonceStore, opts ...Option) (*Verifier, error) {
+	keys := make(StaticKeys, len(cfg.Keys))
+	for _, spec := range cfg.Keys {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		k, err := ParseKey(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := keys[k.ID]; dup {
+			return nil, fmt.Errorf("%w: duplicate key id %q", ErrInvalidKey, k.ID)
+		}
+		keys[k.ID] = k
+	}
+	return NewVerifier(keys, nonces, append([]Option{WithMaxSkew(cfg.MaxSkew)}, opts...)...)
+}
+
+// SignerFromConfig builds a Signer from SigningKey. It returns (nil, nil) when no key is configured.
+func SignerFromConfig(cfg Config, opts ...Option) (*Signer, error) {
+	if strings.TrimSpace(cfg.SigningKey) == "" {
+		return nil, nil
+	}
+	k, err := ParseKey(cfg.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(k, opts...)
+}
+
+// ParseKey parses a key written as "<id>:<alg>:<base64 material>[:<scopes>]".
+func ParseKey(spec string) (Key, error) {
+	parts := strings.SplitN(strings.TrimSpace(spec), ":", 4)
+	if len(parts) < 3 {
+		return Key{}, fmt.Errorf("%w: expected <id>:<alg>:<material>", ErrInvalidKey)
+	}
+	id, alg := parts[0], Algorithm(strings.ToLower(parts[1]))
+	material, err := decodeMaterial(parts[2])
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %q: material is not base64", ErrInvalidKey, id)
+	}
+
+	var k Key
+	switch alg {
+	case HMACSHA256:
+		k, err = HMACKey(id, material)
+	case Ed25519:
+		switch len(material) {
+		case ed25519.PublicKeySize:
+			k, err = Ed25519PublicKey(id, ed25519.PublicKey(material))
+		case ed25519.PrivateKeySize:
+			k, err = Ed25519PrivateKey(id, ed25519.PrivateKey(material))
+		default:
+			err = fmt.Errorf("%w: %q: ed25519 key must be %d or %d bytes", ErrInvalidKey, id, ed25519.PublicKeySize, ed25519.PrivateKeySize)
+		}
+	default:
+		err = fmt.Errorf("%w: %q: unsupported algorithm %q", ErrInvalidKey, id, alg)
+	}
+	if err != nil {
+		return Key{}, err
+	}
+	if len(parts) == 4 {
+		k.Scopes = strings.Fields(parts[3])
+	}
+	return k, nil
+}
+
+// decodeMaterial accepts standard and URL-safe base64, padded or not.
+func decodeMaterial(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "+/") {
+		return base64.RawStdEncoding.DecodeString(s)
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
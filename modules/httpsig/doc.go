@@ -0,0 +1,30 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpsig signs and verifies HTTP requests between services.
+//
+// A signature covers the method, request target, host, Date header, a nonce and
+// a SHA-256 Content-Digest of the body, so a captured request cannot be replayed
+// against another route, another host, with another body or outside the allowed
+// clock skew. The verifier remembers each nonce in a NonceStore, so it cannot be
+// replayed as it is either. Keys are either shared HMAC-SHA256 secrets or Ed25519 key pairs
+// (the receiving service then only holds the public key).
+//
+// The signature travels in a single header:
+//
+//	Signature: keyid="billing",alg="ed25519",created=1760400000,nonce="<base64url>",sig="<base64url>"
+//
+// Clients sign through httpclient.SigningTransport; servers verify with
+// middleware.VerifySignatures, which turns the key id into an auth.Principal.
+package httpsig
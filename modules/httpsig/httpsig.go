@@ -0,0 +1,420 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpsig
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/modules/internal/httpreq"
+)
+
+const (
+	// SignatureHeader carries the signature parameters.
+	SignatureHeader = "Signature"
+	// DigestHeader carries the body digest, formatted as in RFC 9530.
+	DigestHeader = "Content-Digest"
+
+	// DefaultMaxSkew is how far the signature creation time may drift from the verifier clock.
+	DefaultMaxSkew = 5 * time.Minute
+	// DefaultMaxBodyBytes bounds how much of a body the verifier reads to check its digest.
+	DefaultMaxBodyBytes int64 = 10 << 20
+)
+
+var (
+	ErrMissingSignature = errors.New("httpsig: missing signature")
+	ErrMalformed        = errors.New("httpsig: malformed signature")
+	ErrUnknownKey       = errors.New("httpsig: unknown key")
+	ErrInvalidSignature = errors.New("httpsig: invalid signature")
+	ErrExpired          = errors.New("httpsig: signature outside allowed clock skew")
+	ErrDigestMismatch   = errors.New("httpsig: body does not match digest")
+	ErrInvalidKey       = errors.New("httpsig: invalid key")
+	ErrReplayed         = errors.New("httpsig: signature nonce already used")
+	ErrMissingNonces    = errors.New("httpsig: missing nonce store")
+)
+
+// Algorithm names a signature algorithm.
+type Algorithm string
+
+const (
+	HMACSHA256 Algorithm = "hmac-sha256"
+	Ed25519    Algorithm = "ed25519"
+)
+
+type (
+	// Key is a named signing or verification key.
+	//
+	// An HMAC key signs and verifies; an Ed25519 key built from a private key signs
+	// and verifies, one built from a public key only verifies.
+	Key struct {
+		ID        string
+		Algorithm Algorithm
+		// Scopes granted to requests signed with this key, see middleware.VerifySignatures.
+		Scopes []string
+
+		secret  []byte
+		private ed25519.PrivateKey
+		public  ed25519.PublicKey
+	}
+
+	// KeyStore resolves the key a request claims to be signed with.
+	KeyStore interface {
+		Key(id string) (Key, bool)
+	}
+
+	// NonceStore remembers the nonces of verified signatures, as hmac.NonceStore.
+	//
+	// Remember reports whether nonce was new, and keeps it for at least ttl.
+	NonceStore interface {
+		Remember(ctx context.Context, nonce string, ttl time.Duration) (bool, error)
+	}
+
+	// StaticKeys is an in-memory KeyStore.
+	StaticKeys map[string]Key
+
+	// Signer adds a signature to outgoing requests.
+	Signer struct {
+		key Key
+		now func() time.Time
+	}
+
+	// Verifier checks signatures on incoming requests.
+	Verifier struct {
+		keys         KeyStore
+		nonces       NonceStore
+		now          func() time.Time
+		maxSkew      time.Duration
+		maxBodyBytes int64
+	}
+
+	// Option configures a Signer or Verifier.
+	Option func(*options)
+
+	options struct {
+		now          func() time.Time
+		maxSkew      time.Duration
+		maxBodyBytes int64
+	}
+)
+
+var _ KeyStore = StaticKeys(nil)
+
+// Key implements KeyStore.
+func (s StaticKeys) Key(id string) (Key, bool) {
+	k, ok := s[id]
+	return k, ok
+}
+
+// HMACKey builds a shared-secret key. Secrets shorter than 32 bytes are rejected.
+func HMACKey(id string, secret []byte) (Key, error) {
+	if id == "" {
+		return Key{}, fmt.Errorf("%w: empty key id", ErrInvalidKey)
+	}
+	if len(secret) < 32 {
+		return Key{}, fmt.Errorf("%w: %q: hmac secret must be at least 32 bytes", ErrInvalidKey, id)
+	}
+	return Key{ID: id, Algorithm: HMACSHA256, secret: secret}, nil
+}
+
+// Ed25519PrivateKey builds a key able to sign.
+func Ed25519PrivateKey(id string, priv ed25519.PrivateKey) (Key, error) {
+	if id == "" {
+		return Key{}, fmt.Errorf("%w: empty key id", ErrInvalidKey)
+	}
+	if len(priv) != ed25519.PrivateKeySize {
+		return Key{}, fmt.Errorf("%w: %q: ed25519 private key must be %d bytes", ErrInvalidKey, id, ed25519.PrivateKeySize)
+	}
+	return Key{ID: id, Algorithm: Ed25519, private: priv, public: priv.Public().(ed25519.PublicKey)}, nil
+}
+
+// Ed25519PublicKey builds a verification-only key.
+func Ed25519PublicKey(id string, pub ed25519.PublicKey) (Key, error) {
+	if id == "" {
+		return Key{}, fmt.Errorf("%w: empty key id", ErrInvalidKey)
+	}
+	if len(pub) != ed25519.PublicKeySize {
+		return Key{}, fmt.Errorf("%w: %q: ed25519 public key must be %d bytes", ErrInvalidKey, id, ed25519.PublicKeySize)
+	}
+	return Key{ID: id, Algorithm: Ed25519, public: pub}, nil
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(now func() time.Time) Option {
+	return func(o *options) {
+		if now != nil {
+			o.now = now
+		}
+	}
+}
+
+// WithMaxSkew sets how far the signature creation time may be from the verifier
+// clock, in either direction. Verifier only; defaults to DefaultMaxSkew.
+func WithMaxSkew(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.maxSkew = d
+		}
+	}
+}
+
+// WithMaxBodyBytes bounds the body the verifier reads. Verifier only;
+// defaults to DefaultMaxBodyBytes.
+func WithMaxBodyBytes(n int64) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxBodyBytes = n
+		}
+	}
+}
+
+func buildOptions(opts []Option) options {
+	o := options{now: time.Now, maxSkew: DefaultMaxSkew, maxBodyBytes: DefaultMaxBodyBytes}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	return o
+}
+
+// NewSigner constructs a Signer. The key must be able to sign.
+func NewSigner(key Key, opts ...Option) (*Signer, error) {
+	if key.Algorithm == Ed25519 && key.private == nil {
+		return nil, fmt.Errorf("%w: %q: ed25519 public key cannot sign", ErrInvalidKey, key.ID)
+	}
+	if key.Algorithm != HMACSHA256 && key.Algorithm != Ed25519 {
+		return nil, fmt.Errorf("%w: %q: unsupported algorithm %q", ErrInvalidKey, key.ID, key.Algorithm)
+	}
+	o := buildOptions(opts)
+	return &Signer{key: key, now: o.now}, nil
+}
+
+// Sign sets the Date, Content-Digest and Signature headers on r, with a fresh
+// nonce in the signature.
+//
+// The body is read to compute its digest and replaced with an equivalent reader.
+// Sign modifies r; RoundTrippers must sign a clone.
+func (s *Signer) Sign(r *http.Request) error {
+	body, err := httpreq.ReadBody(r, -1)
+	if err != nil {
+		return fmt.Errorf("httpsig: read body: %w", err)
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return err
+	}
+
+	created := s.now().UTC().Truncate(time.Second)
+	r.Header.Set("Date", created.Format(http.TimeFormat))
+	r.Header.Set(DigestHeader, digest(body))
+
+	sig, err := s.key.sign(signingString(r, created.Unix(), nonce))
+	if err != nil {
+		return err
+	}
+	r.Header.Set(SignatureHeader, fmt.Sprintf(`keyid=%q,alg=%q,created=%d,nonce=%q,sig=%q`,
+		s.key.ID, s.key.Algorithm, created.Unix(), nonce, base64.RawURLEncoding.EncodeToString(sig)))
+	return nil
+}
+
+// NewVerifier constructs a Verifier resolving keys from keys. nonces remembers
+// the signatures already verified, such as a redis.NonceCache; without it a
+// request could be replayed within the allowed clock skew.
+func NewVerifier(keys KeyStore, nonces NonceStore, opts ...Option) (*Verifier, error) {
+	if nonces == nil {
+		return nil, ErrMissingNonces
+	}
+	o := buildOptions(opts)
+	return &Verifier{keys: keys, nonces: nonces, now: o.now, maxSkew: o.maxSkew, maxBodyBytes: o.maxBodyBytes}, nil
+}
+
+// Verify checks the signature of r, then claims its nonce, and returns the key
+// it was signed with.
+//
+// The body is read to check its digest and replaced with an equivalent reader,
+// so handlers still see it. Errors of the NonceStore are returned wrapped as they
+// are; the others match one of the Err* sentinels.
+func (v *Verifier) Verify(r *http.Request) (Key, error) {
+	header := r.Header.Get(SignatureHeader)
+	if header == "" {
+		return Key{}, ErrMissingSignature
+	}
+	params, err := parseParams(header)
+	if err != nil {
+		return Key{}, err
+	}
+
+	key, ok := v.keys.Key(params.keyID)
+	if !ok {
+		return Key{}, fmt.Errorf("%w: %q", ErrUnknownKey, params.keyID)
+	}
+	// never let the request pick the algorithm of a known key
+	if key.Algorithm != params.alg {
+		return Key{}, fmt.Errorf("%w: algorithm mismatch for key %q", ErrInvalidSignature, key.ID)
+	}
+
+	created := time.Unix(params.created, 0)
+	if skew := v.now().Sub(created); skew > v.maxSkew || skew < -v.maxSkew {
+		return Key{}, ErrExpired
+	}
+	// Date is covered by the signature, but must agree with the signed creation time
+	if date, err := http.ParseTime(r.Header.Get("Date")); err != nil || date.Unix() != params.created {
+		return Key{}, fmt.Errorf("%w: date header does not match created", ErrInvalidSignature)
+	}
+
+	if !key.verify(signingString(r, params.created, params.nonce), params.sig) {
+		return Key{}, ErrInvalidSignature
+	}
+
+	// the digest header is authentic now; check the body against it
+	body, err := httpreq.ReadBody(r, v.maxBodyBytes)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: read body: %w", ErrDigestMismatch, err)
+	}
+	if !hmac.Equal([]byte(digest(body)), []byte(r.Header.Get(DigestHeader))) {
+		return Key{}, ErrDigestMismatch
+	}
+
+	// only authentic nonces are stored, per key so callers cannot burn each other's;
+	// the skew check rejects the request once the nonce may be forgotten
+	fresh, err := v.nonces.Remember(r.Context(), key.ID+":"+params.nonce, 2*v.maxSkew)
+	if err != nil {
+		return Key{}, fmt.Errorf("httpsig: remember nonce: %w", err)
+	}
+	if !fresh {
+		return Key{}, ErrReplayed
+	}
+	return key, nil
+}
+
+func (k Key) sign(msg []byte) ([]byte, error) {
+	switch k.Algorithm {
+	case HMACSHA256:
+		mac := hmac.New(sha256.New, k.secret)
+		_, _ = mac.Write(msg)
+		return mac.Sum(nil), nil
+	case Ed25519:
+		if k.private == nil {
+			return nil, fmt.Errorf("%w: %q: ed25519 public key cannot sign", ErrInvalidKey, k.ID)
+		}
+		return ed25519.Sign(k.private, msg), nil
+	default:
+		return nil, fmt.Errorf("%w: %q: unsupported algorithm %q", ErrInvalidKey, k.ID, k.Algorithm)
+	}
+}
+
+func (k Key) verify(msg, sig []byte) bool {
+	switch k.Algorithm {
+	case HMACSHA256:
+		mac := hmac.New(sha256.New, k.secret)
+		_, _ = mac.Write(msg)
+		return hmac.Equal(mac.Sum(nil), sig)
+	case Ed25519:
+		return len(k.public) == ed25519.PublicKeySize && ed25519.Verify(k.public, msg, sig)
+	default:
+		return false
+	}
+}
+
+// signingString lists the covered components, one per line.
+func signingString(r *http.Request, created int64, nonce string) []byte {
+	host := r.Host
+	if host == "" && r.URL != nil {
+		host = r.URL.Host
+	}
+	var b strings.Builder
+	b.WriteString("(request-target): ")
+	b.WriteString(strings.ToLower(r.Method))
+	b.WriteByte(' ')
+	b.WriteString(httpreq.Target(r))
+	b.WriteString("\nhost: ")
+	b.WriteString(strings.ToLower(host))
+	b.WriteString("\ndate: ")
+	b.WriteString(r.Header.Get("Date"))
+	b.WriteString("\ncontent-digest: ")
+	b.WriteString(r.Header.Get(DigestHeader))
+	b.WriteString("\ncreated: ")
+	b.WriteString(strconv.FormatInt(created, 10))
+	b.WriteString("\nnonce: ")
+	b.WriteString(nonce)
+	return []byte(b.String())
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("httpsig: generate nonce: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+type params struct {
+	keyID   string
+	alg     Algorithm
+	created int64
+	nonce   string
+	sig     []byte
+}
+
+func parseParams(header string) (params, error) {
+	var p params
+	var haveCreated bool
+	for part := range strings.SplitSeq(header, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return params{}, ErrMalformed
+		}
+		value = strings.Trim(value, `"`)
+		switch name {
+		case "keyid":
+			p.keyID = value
+		case "alg":
+			p.alg = Algorithm(value)
+		case "created":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return params{}, ErrMalformed
+			}
+			p.created, haveCreated = n, true
+		case "nonce":
+			p.nonce = value
+		case "sig":
+			sig, err := base64.RawURLEncoding.DecodeString(value)
+			if err != nil {
+				return params{}, ErrMalformed
+			}
+			p.sig = sig
+		}
+	}
+	if p.keyID == "" || p.alg == "" || !haveCreated || p.nonce == "" || len(p.sig) == 0 {
+		return params{}, ErrMalformed
+	}
+	return p, nil
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed catalogs/*.json
+var defaultCatalogs embed.FS
+
+var ErrNoCatalogs = errors.New("i18n: no catalogs found")
+
+type (
+	// Message is a localized problem title/detail pair. Empty fields mean
+	// "keep whatever the caller wrote".
+	Message struct {
+		Title  string `json:"title,omitempty"`
+		Detail string `json:"detail,omitempty"`
+	}
+
+	// Catalog holds messages for a set of languages and negotiates between them.
+	Catalog struct {
+		fallback language.Tag
+		tags     []language.Tag
+		matcher  language.Matcher
+		messages map[language.Tag]map[string]Message
+	}
+
+	localeKey struct{}
+)
+
+// Default loads the embedded catalogs with English as the fallback language.
+func Default() (*Catalog, error) {
+	return Load(defaultCatalogs, "catalogs", language.English)
+}
+
+// Load reads every <tag>.json file in dir. The fallback language must be among them.
+func Load(fsys fs.FS, dir string, fallback language.Tag) (*Catalog, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: read catalogs: %w", err)
+	}
+
+	c := &Catalog{
+		fallback: fallback,
+		messages: make(map[language.Tag]map[string]Message),
+	}
+	for _, e := range entries {
+		if e.IsDir() || path.Ext(e.Name()) != ".json" {
+			continue
+		}
+		tag, err := language.Parse(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: catalog %q: %w", e.Name(), err)
+		}
+		b, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: read catalog %q: %w", e.Name(), err)
+		}
+		msgs := map[string]Message{}
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			return nil, fmt.Errorf("i18n: decode catalog %q: %w", e.Name(), err)
+		}
+		c.messages[tag] = msgs
+	}
+	if len(c.messages) == 0 {
+		return nil, ErrNoCatalogs
+	}
+	if _, ok := c.messages[fallback]; !ok {
+		return nil, fmt.Errorf("i18n: fallback language %q has no catalog", fallback)
+	}
+
+	// the matcher prefers its first tag when nothing matches, so the fallback goes first
+	others := make([]language.Tag, 0, len(c.messages)-1)
+	for tag := range c.messages {
+		if tag != fallback {
+			others = append(others, tag)
+		}
+	}
+	slices.SortFunc(others, func(a, b language.Tag) int { return strings.Compare(a.String(), b.String()) })
+	c.tags = append([]language.Tag{fallback}, others...)
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// Fallback returns the language used when nothing else matches.
+func (c *Catalog) Fallback() language.Tag { return c.fallback }
+
+// Negotiate picks the best supported language for an Accept-Language header value.
+func (c *Catalog) Negotiate(acceptLanguage string) language.Tag {
+	if acceptLanguage == "" {
+		return c.fallback
+	}
+	prefs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(prefs) == 0 {
+		return c.fallback
+	}
+	_, idx, conf := c.matcher.Match(prefs...)
+	if conf == language.No {
+		return c.fallback
+	}
+	return c.tags[idx]
+}
+
+// Lookup resolves key for tag, walking tag's parents and then the fallback language.
+// It returns the language the message was found in.
+func (c *Catalog) Lookup(tag language.Tag, key string) (Message, language.Tag, bool) {
+	for t := tag; ; t = t.Parent() {
+		if msg, ok := c.messages[t][key]; ok {
+			return msg, t, true
+		}
+		if t == language.Und {
+			break
+		}
+	}
+	if msg, ok := c.messages[c.fallback][key]; ok {
+		return msg, c.fallback, true
+	}
+	return Message{}, c.fallback, false
+}
+
+// WithLocale stores the negotiated language in ctx.
+func WithLocale(ctx context.Context, tag language.Tag) context.Context {
+	return context.WithValue(ctx, localeKey{}, tag)
+}
+
+// LocaleFrom returns the language stored by WithLocale.
+func LocaleFrom(ctx context.Context) (language.Tag, bool) {
+	tag, ok := ctx.Value(localeKey{}).(language.Tag)
+	return tag, ok
+}
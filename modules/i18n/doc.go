@@ -0,0 +1,24 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package i18n provides message catalogs for client-facing texts.
+//
+// Catalogs are JSON files named after a BCP 47 tag (en.json, vi.json, ...),
+// mapping a message key to a localized title/detail pair. Keys are problem
+// codes (e.g. "profile.not_found", "unavailable") or HTTP statuses
+// ("status.404"). Lookups walk a fallback chain from the negotiated tag to
+// its parents (vi-VN -> vi) and finally to the catalog's default language.
+//
+// The default catalogs are embedded; services can load their own with Load.
+package i18n
@@ -0,0 +1,21 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jobs declares the background jobs of this service, so the server and
+// the jobs command (cmd/jobs) agree on what runs, when and under which lock.
+//
+// Adding a job means adding its definition here and binding its task in main;
+// JOBS_ENABLED, JOBS_DISABLED and JOBS_SCHEDULES then control it per deployment
+// (see locking.JobsConfig).
+package jobs
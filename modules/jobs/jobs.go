@@ -0,0 +1,107 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jobs
+
+import (
+	"time"
+
+	"app/modules/db/redis/locking"
+)
+
+// Job names, which are also their lock names.
+const (
+	StatsRefresh     = "profile.stats.refresh"
+	AuditPurge       = "profile.audit.purge"
+	CountersPurge    = "ratelimit.counters.purge"
+	ProfilesPurge    = "profile.softdelete.purge"
+	SagaResume       = "saga.resume"
+	DeliveryPurge    = "delivery.journal.purge"
+	DeliveryDispatch = "delivery.journal.dispatch"
+)
+
+// Maintenance groups the purges, which delete in bulk on the primary and must not
+// pile up across the fleet.
+const Maintenance = "maintenance"
+
+// Groups returns the concurrency groups of Definitions with their default limits.
+func Groups() []locking.ConcurrencyGroup {
+	return []locking.ConcurrencyGroup{
+		{Name: Maintenance, Limit: 1},
+	}
+}
+
+// Definitions returns every job of the service with its defaults. The server binds
+// the tasks whose requirements its configuration meets.
+func Definitions() []locking.JobDefinition {
+	return []locking.JobDefinition{
+		{
+			Name:        StatsRefresh,
+			Description: "refresh the profile stats materialized view",
+			Schedule:    "@every 5m",
+			Lock:        locking.LockConfiguration{LockAtMostFor: time.Minute, LockAtLeastFor: 30 * time.Second},
+		},
+		{
+			Name:        AuditPurge,
+			Description: "delete profile history older than the retention window",
+			Schedule:    "@every 1h",
+			Lock:        locking.LockConfiguration{LockAtMostFor: 10 * time.Minute, LockAtLeastFor: time.Minute},
+			Requires:    "AUDIT_RETENTION>0",
+			Group:       Maintenance,
+		},
+		{
+			Name:        CountersPurge,
+			Description: "delete expired rate limit counters",
+			Schedule:    "@every 5m",
+			Lock:        locking.LockConfiguration{LockAtMostFor: time.Minute, LockAtLeastFor: 30 * time.Second},
+			Requires:    "RATE_LIMIT_STORE=postgres",
+			Group:       Maintenance,
+		},
+		{
+			Name:        ProfilesPurge,
+			Description: "hard-delete profiles soft-deleted longer than the retention window",
+			Schedule:    "@every 1h",
+			Lock:        locking.LockConfiguration{LockAtMostFor: 10 * time.Minute, LockAtLeastFor: time.Minute},
+			Requires:    "SOFT_DELETE_RETENTION>0",
+			Group:       Maintenance,
+		},
+		{
+			Name:        SagaResume,
+			Description: "resume saga instances left unfinished",
+			Schedule:    "@every 1m",
+			Lock:        locking.LockConfiguration{LockAtMostFor: time.Minute, LockAtLeastFor: 30 * time.Second},
+			Requires:    "SAGA_ENABLED=true",
+		},
+		{
+			Name:        DeliveryPurge,
+			Description: "delete finished deliveries older than the retention window",
+			Schedule:    "@every 1h",
+			Lock:        locking.LockConfiguration{LockAtMostFor: 10 * time.Minute, LockAtLeastFor: time.Minute},
+			Requires:    "DELIVERY_ENABLED=true",
+			Group:       Maintenance,
+		},
+		{
+			Name:        DeliveryDispatch,
+			Description: "send the pending deliveries again",
+			Schedule:    "@every 1m",
+			Lock:        locking.LockConfiguration{LockAtMostFor: 10 * time.Minute, LockAtLeastFor: 30 * time.Second},
+			Requires:    "DELIVERY_ENABLED=true",
+		},
+	}
+}
+
+// NewRegistry returns a locking.Registry holding Definitions.
+func NewRegistry() (*locking.Registry, error) {
+	return locking.NewRegistry(Definitions()...)
+}
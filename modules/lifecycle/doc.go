@@ -0,0 +1,28 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lifecycle coordinates application startup and shutdown.
+//
+// Components (HTTP server, connection pools, consumers, telemetry) register
+// start/stop hooks together with the components they depend on. Startup runs
+// in dependency order. Shutdown runs in priority stages (ingress, workers,
+// stores, telemetry) and in reverse dependency order within a stage, giving each
+// component its own timeout and logging a single summary once everything is
+// stopped. OnShutdown registers a stop-only hook, even after startup:
+//
+//	lc.OnShutdown("locks", lifecycle.PriorityStores, func(context.Context) error { ... })
+//
+// EventLog publishes well-known milestones (config_loaded, db_ready, ...,
+// stopped) with their timing for deploy automation to assert on.
+package lifecycle
@@ -0,0 +1,155 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Event is a well-known milestone of the process lifecycle.
+type Event string
+
+const (
+	EventConfigLoaded    Event = "config_loaded"
+	EventDBReady         Event = "db_ready"
+	EventMigrated        Event = "migrated"
+	EventRedisReady      Event = "redis_ready"
+	EventServerListening Event = "server_listening"
+	EventDraining        Event = "draining"
+	EventStopped         Event = "stopped"
+)
+
+const instrumentationName = "app/modules/lifecycle"
+
+type (
+	// EventLog emits lifecycle events as structured log records and, once a phase
+	// completes, as an OTel span with one span event per milestone.
+	//
+	// Every event is logged immediately as "lifecycle: event" with:
+	//   - event:         the Event name
+	//   - phase:         "startup" or "shutdown"
+	//   - elapsed:       time since the EventLog was created (process start)
+	//   - phase_elapsed: time since the phase began
+	//
+	// so deploy automation can assert on e.g. `event=server_listening` and its
+	// elapsed time. Telemetry is not yet initialized when the first events fire, so
+	// the OTel side is buffered: EventServerListening flushes the "lifecycle.startup"
+	// span and EventStopped the "lifecycle.shutdown" span, together with the
+	// app_lifecycle_event_elapsed gauge.
+	EventLog struct {
+		mu sync.Mutex
+
+		begin      time.Time
+		phase      string
+		phaseBegin time.Time
+		pending    []eventRecord
+	}
+
+	eventRecord struct {
+		event Event
+		at    time.Time
+		attrs []slog.Attr
+	}
+)
+
+// NewEventLog starts the startup phase at the current time.
+func NewEventLog() *EventLog {
+	now := time.Now()
+	return &EventLog{begin: now, phase: "startup", phaseBegin: now}
+}
+
+// Emit records ev. A nil EventLog is a no-op.
+func (l *EventLog) Emit(ctx context.Context, ev Event, attrs ...slog.Attr) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if ev == EventDraining && l.phase != "shutdown" {
+		// whatever startup events were not flushed belong to an aborted startup
+		l.flushLocked(ctx, now)
+		l.phase = "shutdown"
+		l.phaseBegin = now
+	}
+	phase, phaseElapsed := l.phase, now.Sub(l.phaseBegin)
+	l.pending = append(l.pending, eventRecord{event: ev, at: now, attrs: attrs})
+	if ev == EventServerListening || ev == EventStopped {
+		l.flushLocked(ctx, now)
+	}
+	l.mu.Unlock()
+
+	args := make([]any, 0, len(attrs)+4)
+	args = append(args,
+		slog.String("event", string(ev)),
+		slog.String("phase", phase),
+		slog.Duration("elapsed", now.Sub(l.begin)),
+		slog.Duration("phase_elapsed", phaseElapsed),
+	)
+	for _, a := range attrs {
+		args = append(args, a)
+	}
+	slog.InfoContext(ctx, "lifecycle: event", args...)
+}
+
+// flushLocked exports the pending events of the current phase. Callers must hold l.mu.
+func (l *EventLog) flushLocked(ctx context.Context, end time.Time) {
+	if len(l.pending) == 0 {
+		return
+	}
+
+	gauge, err := otel.Meter(instrumentationName).Float64Gauge(
+		"app_lifecycle_event_elapsed",
+		metric.WithDescription("Time from process start to a lifecycle event"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		slog.WarnContext(ctx, "lifecycle: event gauge unavailable", slog.Any("error", err))
+	}
+
+	// the span is detached from any request trace, it describes the process
+	_, span := otel.Tracer(instrumentationName).Start(
+		context.WithoutCancel(ctx),
+		"lifecycle."+l.phase,
+		trace.WithNewRoot(),
+		trace.WithTimestamp(l.phaseBegin),
+	)
+	for _, rec := range l.pending {
+		elapsed := rec.at.Sub(l.begin)
+		kvs := make([]attribute.KeyValue, 0, len(rec.attrs)+1)
+		kvs = append(kvs, attribute.Float64("elapsed_seconds", elapsed.Seconds()))
+		for _, a := range rec.attrs {
+			kvs = append(kvs, attribute.String(a.Key, a.Value.String()))
+		}
+		span.AddEvent(string(rec.event), trace.WithTimestamp(rec.at), trace.WithAttributes(kvs...))
+
+		if gauge != nil {
+			gauge.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+				attribute.String("event", string(rec.event)),
+				attribute.String("phase", l.phase),
+			))
+		}
+	}
+	span.End(trace.WithTimestamp(end))
+	l.pending = l.pending[:0]
+}
@@ -0,0 +1,420 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+var (
+	ErrDuplicateComponent = errors.New("lifecycle: duplicate component")
+	ErrUnknownDependency  = errors.New("lifecycle: unknown dependency")
+	ErrDependencyCycle    = errors.New("lifecycle: dependency cycle")
+	ErrAlreadyStarted     = errors.New("lifecycle: already started")
+)
+
+// Shutdown stages, from first to last to stop. Values in between order
+// components within the usual stages.
+const (
+	// PriorityIngress stops taking work first: HTTP servers, consumers.
+	PriorityIngress = 0
+	// PriorityWorkers lets in-flight work finish: schedulers, worker pools, pollers.
+	PriorityWorkers = 100
+	// PriorityStores closes the pools the work ran on: Postgres, Redis, locks.
+	PriorityStores = 200
+	// PriorityTelemetry flushes what everything else recorded.
+	PriorityTelemetry = 300
+)
+
+type (
+	// Hook is a start or stop function of a component.
+	Hook func(ctx context.Context) error
+
+	// Component is a unit of the application with a start/stop lifecycle
+	// (HTTP server, connection pools, consumers, telemetry, ...).
+	//
+	//   - Start is optional. A component without Start is considered running as soon as
+	//     it is registered, which fits resources that are already constructed (pools, clients).
+	//   - Stop is optional and only runs for components that are running.
+	//   - DependsOn lists components that must be started before and stopped after this one.
+	//   - Priority orders shutdown in stages: every component of a lower priority stops
+	//     before any of a higher one, in reverse dependency order within a stage. A
+	//     dependency never stops before its dependents, it joins their stage if needed.
+	Component struct {
+		Name      string
+		DependsOn []string
+		Priority  int
+
+		Start Hook
+		Stop  Hook
+
+		// Per-stage timeouts; zero uses the Manager defaults.
+		StartTimeout time.Duration
+		StopTimeout  time.Duration
+	}
+
+	// StageResult records the outcome of a single start or stop stage.
+	StageResult struct {
+		Name     string
+		Duration time.Duration
+		Err      error
+	}
+
+	// Manager starts components in dependency order and stops them in reverse.
+	//
+	// It replaces a manual defer stack in the composition root:
+	//
+	//	lc := lifecycle.New()
+	//	defer lc.Stop(context.Background())
+	//
+	//	lc.Register(lifecycle.Component{Name: "postgres", Stop: pool.Shutdown})
+	//	lc.Register(lifecycle.Component{Name: "http", DependsOn: []string{"postgres"}, Start: srv.Start, Stop: srv.Shutdown})
+	//
+	//	if err := lc.Start(ctx); err != nil { ... }
+	Manager struct {
+		mu sync.Mutex
+
+		components map[string]*entry
+		// registration order, used to keep the topological order deterministic
+		names []string
+
+		startTimeout time.Duration
+		stopTimeout  time.Duration
+
+		started bool
+		stopped bool
+
+		events *EventLog
+	}
+
+	// Option configures a Manager.
+	Option func(*Manager)
+
+	entry struct {
+		Component
+		running bool
+	}
+)
+
+// WithStartTimeout sets the default timeout for each start stage.
+func WithStartTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.startTimeout = d
+		}
+	}
+}
+
+// WithStopTimeout sets the default timeout for each stop stage.
+func WithStopTimeout(d time.Duration) Option {
+	return func(m *Manager) {
+		if d > 0 {
+			m.stopTimeout = d
+		}
+	}
+}
+
+// WithEventLog makes Stop emit EventDraining before the first component stops.
+func WithEventLog(l *EventLog) Option {
+	return func(m *Manager) {
+		m.events = l
+	}
+}
+
+// New constructs an empty Manager.
+func New(opts ...Option) *Manager {
+	m := &Manager{
+		components:   make(map[string]*entry),
+		startTimeout: 30 * time.Second,
+		stopTimeout:  10 * time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	return m
+}
+
+// Register adds a component. Dependencies may be registered later, they are
+// only resolved when Start or Stop runs.
+func (m *Manager) Register(c Component) error {
+	return m.register(c, false)
+}
+
+// OnShutdown registers fn to run on Stop in the stage of priority (see the
+// Priority* constants), with the default stop timeout. Unlike Register, it may be
+// called after Start, for resources created while the process runs.
+func (m *Manager) OnShutdown(name string, priority int, fn Hook) error {
+	return m.register(Component{Name: name, Priority: priority, Stop: fn}, true)
+}
+
+func (m *Manager) register(c Component, stopOnly bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if c.Name == "" {
+		return errors.New("lifecycle: component name must not be empty")
+	}
+	if _, ok := m.components[c.Name]; ok {
+		return fmt.Errorf("%w: %q", ErrDuplicateComponent, c.Name)
+	}
+	if m.started && !stopOnly {
+		return fmt.Errorf("%w: cannot register %q", ErrAlreadyStarted, c.Name)
+	}
+	if m.stopped {
+		return fmt.Errorf("lifecycle: cannot register %q: already stopped", c.Name)
+	}
+
+	m.components[c.Name] = &entry{
+		Component: c,
+		// already constructed resources are running from the moment they are registered
+		running: c.Start == nil,
+	}
+	m.names = append(m.names, c.Name)
+	return nil
+}
+
+// Start runs every start hook in dependency order.
+//
+// On the first failure, components that were already running are stopped in reverse
+// order and the start error is returned.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.started {
+		m.mu.Unlock()
+		return ErrAlreadyStarted
+	}
+	m.started = true
+	order, err := m.order()
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	begin := time.Now()
+	for _, e := range order {
+		if e.Start == nil {
+			continue
+		}
+
+		res := m.runStage(ctx, e.Name, e.Start, e.StartTimeout, m.startTimeout)
+		if res.Err != nil {
+			slog.ErrorContext(ctx, "lifecycle: component failed to start",
+				slog.String("component", e.Name),
+				slog.Duration("duration", res.Duration),
+				slog.Any("error", res.Err),
+			)
+			// unwind whatever is already up
+			_ = m.Stop(context.WithoutCancel(ctx))
+			return fmt.Errorf("lifecycle: start %q: %w", e.Name, res.Err)
+		}
+
+		m.mu.Lock()
+		e.running = true
+		m.mu.Unlock()
+
+		slog.InfoContext(ctx, "lifecycle: component started",
+			slog.String("component", e.Name),
+			slog.Duration("duration", res.Duration),
+		)
+	}
+
+	slog.InfoContext(ctx, "lifecycle: startup complete",
+		slog.Int("components", len(order)),
+		slog.Duration("duration", time.Since(begin)),
+	)
+	return nil
+}
+
+// Stop runs the stop hooks of all running components, stage by stage in
+// ascending Priority and in reverse dependency order within a stage.
+//
+// Every stage gets its own timeout and a failing stage does not prevent the
+// remaining ones from running. A single summary record is logged at the end.
+// Stop is idempotent.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if m.stopped {
+		m.mu.Unlock()
+		return nil
+	}
+	m.stopped = true
+	order, err := m.order()
+	if err != nil {
+		// a broken graph must not leak resources: fall back to reverse registration order
+		order = order[:0]
+		for _, name := range m.names {
+			order = append(order, m.components[name])
+		}
+	}
+	order = stopOrder(order)
+	m.mu.Unlock()
+
+	m.events.Emit(ctx, EventDraining)
+
+	begin := time.Now()
+	results := make([]StageResult, 0, len(order))
+	var errs []error
+
+	for _, e := range order {
+		if !e.running || e.Stop == nil {
+			continue
+		}
+
+		res := m.runStage(ctx, e.Name, e.Stop, e.StopTimeout, m.stopTimeout)
+		results = append(results, res)
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("lifecycle: stop %q: %w", e.Name, res.Err))
+		}
+
+		m.mu.Lock()
+		e.running = false
+		m.mu.Unlock()
+	}
+
+	logSummary(ctx, results, time.Since(begin))
+	return errors.Join(errs...)
+}
+
+func (m *Manager) runStage(ctx context.Context, name string, fn Hook, timeout, fallback time.Duration) StageResult {
+	if timeout <= 0 {
+		timeout = fallback
+	}
+	stageCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err = fmt.Errorf("panic: %v", rec)
+			}
+		}()
+		return fn(stageCtx)
+	}()
+	if err == nil && errors.Is(stageCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("stage exceeded timeout %s", timeout)
+	}
+
+	return StageResult{Name: name, Duration: time.Since(start), Err: err}
+}
+
+// order returns components topologically sorted by dependency (Kahn's algorithm),
+// breaking ties by registration order. Callers must hold m.mu.
+func (m *Manager) order() ([]*entry, error) {
+	indegree := make(map[string]int, len(m.names))
+	dependents := make(map[string][]string, len(m.names))
+
+	for _, name := range m.names {
+		e := m.components[name]
+		indegree[name] += 0
+		for _, dep := range e.DependsOn {
+			if _, ok := m.components[dep]; !ok {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, name, dep)
+			}
+			indegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	out := make([]*entry, 0, len(m.names))
+	queue := make([]string, 0, len(m.names))
+	for _, name := range m.names {
+		if indegree[name] == 0 {
+			queue = append(queue, name)
+		}
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		out = append(out, m.components[name])
+		for _, d := range dependents[name] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	if len(out) != len(m.names) {
+		var stuck []string
+		for _, name := range m.names {
+			if indegree[name] > 0 {
+				stuck = append(stuck, name)
+			}
+		}
+		return out, fmt.Errorf("%w: %v", ErrDependencyCycle, stuck)
+	}
+	return out, nil
+}
+
+// stopOrder reverses a start order and sorts it by stage. A dependency takes the
+// highest priority of its dependents, so stages never stop it too early.
+// Callers must hold m.mu.
+func stopOrder(order []*entry) []*entry {
+	stage := make(map[string]int, len(order))
+	for _, e := range order {
+		stage[e.Name] = e.Priority
+	}
+	out := make([]*entry, 0, len(order))
+	// dependents come after their dependencies in a start order, so walking it
+	// backwards settles every dependent before it lifts its dependencies
+	for i := len(order) - 1; i >= 0; i-- {
+		e := order[i]
+		for _, dep := range e.DependsOn {
+			stage[dep] = max(stage[dep], stage[e.Name])
+		}
+		out = append(out, e)
+	}
+	slices.SortStableFunc(out, func(a, b *entry) int {
+		return stage[a.Name] - stage[b.Name]
+	})
+	return out
+}
+
+func logSummary(ctx context.Context, results []StageResult, total time.Duration) {
+	attrs := make([]any, 0, len(results))
+	failed := 0
+	for _, r := range results {
+		status := "ok"
+		if r.Err != nil {
+			status = r.Err.Error()
+			failed++
+		}
+		attrs = append(attrs, slog.Group(r.Name,
+			slog.Duration("duration", r.Duration),
+			slog.String("status", status),
+		))
+	}
+
+	level := slog.LevelInfo
+	if failed > 0 {
+		level = slog.LevelError
+	}
+	slog.Log(ctx, level, "lifecycle: shutdown summary",
+		slog.Int("stopped", len(results)),
+		slog.Int("failed", failed),
+		slog.Duration("duration", total),
+		slog.Group("components", attrs...),
+	)
+}
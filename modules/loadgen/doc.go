@@ -0,0 +1,29 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package loadgen generates randomized, schema-valid HTTP requests from an
+// OpenAPI document and replays them against a running server at a fixed rate.
+//
+// Values respect the declared types, formats (uuid, email, date-time, ...),
+// enums, bounds, lengths and patterns, so traffic passes request validation
+// and exercises the rate limiter and database paths rather than stopping at 400s.
+//
+//	doc, _ := loadgen.LoadSpec(ctx, oapi.Specs, oapi.ProfileSpec)
+//	gen, _ := loadgen.NewGenerator(doc, loadgen.WithOperations("listProfiles", "createProfile"))
+//	runner, _ := loadgen.NewRunner(gen, "http://localhost:8080", loadgen.WithRate(200))
+//	report, _ := runner.Run(ctx) // until ctx is done
+//	report.Print(os.Stdout)
+//
+// See cmd/loadgen for the command line front-end.
+package loadgen
@@ -0,0 +1,282 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var ErrNoOperations = errors.New("loadgen: no operations to generate")
+
+type (
+	// Operation is a single spec operation requests are generated for.
+	Operation struct {
+		ID     string
+		Method string
+		// Path is the templated path, e.g. "/v1/profiles/{id}".
+		Path string
+
+		params openapi3.Parameters
+		body   *openapi3.RequestBodyRef
+	}
+
+	// Generator produces random requests that are valid against an OpenAPI document.
+	//
+	// A Generator is read-only once built and safe for concurrent use; randomness
+	// comes from the *rand.Rand passed to Request.
+	Generator struct {
+		ops []Operation
+
+		// operation ids to keep, empty keeps all
+		only map[string]bool
+		// parameter name -> values to pick from instead of sampling the schema
+		fixed map[string][]string
+		// probability of sending an optional parameter or property
+		optionalRate float64
+	}
+
+	// Option configures a Generator.
+	Option func(*Generator)
+)
+
+// WithOperations restricts generation to the given operation ids.
+func WithOperations(ids ...string) Option {
+	return func(g *Generator) {
+		for _, id := range ids {
+			if id = strings.TrimSpace(id); id != "" {
+				g.only[id] = true
+			}
+		}
+	}
+}
+
+// WithParamValues pins a parameter to known values, e.g. ids of seeded profiles so
+// requests reach the database instead of stopping at 404.
+func WithParamValues(name string, values ...string) Option {
+	return func(g *Generator) {
+		if len(values) > 0 {
+			g.fixed[name] = append(g.fixed[name], values...)
+		}
+	}
+}
+
+// WithOptionalRate sets the probability of including optional parameters and properties.
+// Defaults to 0.5.
+func WithOptionalRate(p float64) Option {
+	return func(g *Generator) {
+		if p >= 0 && p <= 1 {
+			g.optionalRate = p
+		}
+	}
+}
+
+// LoadSpec reads and validates an OpenAPI document from fsys.
+func LoadSpec(ctx context.Context, fsys fs.FS, name string) (*openapi3.T, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("loadgen: read spec %q: %w", name, err)
+	}
+	loader := openapi3.NewLoader()
+	loader.Context = ctx
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("loadgen: load spec %q: %w", name, err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("loadgen: invalid spec %q: %w", name, err)
+	}
+	return doc, nil
+}
+
+// NewGenerator indexes the operations of doc.
+func NewGenerator(doc *openapi3.T, opts ...Option) (*Generator, error) {
+	g := &Generator{
+		only:         map[string]bool{},
+		fixed:        map[string][]string{},
+		optionalRate: 0.5,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(g)
+		}
+	}
+
+	if doc == nil || doc.Paths == nil {
+		return nil, ErrNoOperations
+	}
+	// sorted so that seeded runs pick the same operations
+	paths := doc.Paths.InMatchingOrder()
+	slices.Sort(paths)
+	for _, path := range paths {
+		item := doc.Paths.Value(path)
+		ops := item.Operations()
+		methods := make([]string, 0, len(ops))
+		for m := range ops {
+			methods = append(methods, m)
+		}
+		slices.Sort(methods)
+
+		for _, method := range methods {
+			op := ops[method]
+			if len(g.only) > 0 && !g.only[op.OperationID] {
+				continue
+			}
+			// operation-level parameters override path-level ones with the same name/location
+			params := slices.Clone(op.Parameters)
+			for _, p := range item.Parameters {
+				if p.Value != nil && op.Parameters.GetByInAndName(p.Value.In, p.Value.Name) == nil {
+					params = append(params, p)
+				}
+			}
+			g.ops = append(g.ops, Operation{
+				ID:     op.OperationID,
+				Method: method,
+				Path:   path,
+				params: params,
+				body:   op.RequestBody,
+			})
+		}
+	}
+
+	if len(g.ops) == 0 {
+		return nil, ErrNoOperations
+	}
+	return g, nil
+}
+
+// Operations lists the operations requests are generated for.
+func (g *Generator) Operations() []Operation {
+	return slices.Clone(g.ops)
+}
+
+// Request builds a random request for a random operation against base.
+func (g *Generator) Request(ctx context.Context, rng *rand.Rand, base *url.URL) (*http.Request, Operation, error) {
+	op := g.ops[rng.IntN(len(g.ops))]
+	req, err := g.RequestFor(ctx, rng, base, op)
+	return req, op, err
+}
+
+// RequestFor builds a random request for op against base.
+func (g *Generator) RequestFor(ctx context.Context, rng *rand.Rand, base *url.URL, op Operation) (*http.Request, error) {
+	s := &sampler{rng: rng, optionalRate: g.optionalRate}
+
+	path := op.Path
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+
+	for _, ref := range op.params {
+		p := ref.Value
+		if p == nil {
+			continue
+		}
+		if !p.Required && rng.Float64() >= g.optionalRate {
+			continue
+		}
+		values, err := g.paramValues(s, p)
+		if err != nil {
+			return nil, fmt.Errorf("loadgen: %s param %q: %w", op.ID, p.Name, err)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		switch p.In {
+		case openapi3.ParameterInPath:
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(values[0]))
+		case openapi3.ParameterInQuery:
+			for _, v := range values {
+				query.Add(p.Name, v)
+			}
+		case openapi3.ParameterInHeader:
+			header.Set(p.Name, strings.Join(values, ","))
+		case openapi3.ParameterInCookie:
+			cookies = append(cookies, &http.Cookie{Name: p.Name, Value: values[0]})
+		}
+	}
+
+	var body io.Reader
+	contentType := ""
+	if op.body != nil && op.body.Value != nil {
+		rb := op.body.Value
+		if mt := rb.Content.Get("application/json"); mt != nil && (rb.Required || rng.Float64() < g.optionalRate) {
+			v, err := s.value(mt.Schema, 0)
+			if err != nil {
+				return nil, fmt.Errorf("loadgen: %s body: %w", op.ID, err)
+			}
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("loadgen: %s encode body: %w", op.ID, err)
+			}
+			body = bytes.NewReader(b)
+			contentType = "application/json"
+		}
+	}
+
+	u := base.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, op.Method, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("loadgen: %s build request: %w", op.ID, err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json, application/problem+json")
+	return req, nil
+}
+
+func (g *Generator) paramValues(s *sampler, p *openapi3.Parameter) ([]string, error) {
+	if fixed := g.fixed[p.Name]; len(fixed) > 0 {
+		return []string{fixed[s.rng.IntN(len(fixed))]}, nil
+	}
+
+	v, err := s.value(p.Schema, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		out := make([]string, 0, len(x))
+		for _, item := range x {
+			out = append(out, format(item))
+		}
+		return out, nil
+	default:
+		return []string{format(x)}, nil
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/url"
+	"testing"
+
+	"app/modules/oapi"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+)
+
+// Generated requests must pass the same validation the server applies.
+func TestGeneratedRequestsValidateAgainstSpec(t *testing.T) {
+	ctx := context.Background()
+
+	for _, spec := range []string{oapi.ProfileSpec, oapi.PaymentSpec} {
+		t.Run(spec, func(t *testing.T) {
+			doc, err := LoadSpec(ctx, oapi.Specs, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc.Servers = nil // match any host
+			router, err := gorillamux.NewRouter(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gen, err := NewGenerator(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rng := rand.New(rand.NewPCG(1, 2))
+			base, _ := url.Parse("http://localhost:8080")
+			for range 300 {
+				req, op, err := gen.Request(ctx, rng, base)
+				if err != nil {
+					t.Fatalf("generate: %v", err)
+				}
+				route, params, err := router.FindRoute(req)
+				if err != nil {
+					t.Fatalf("%s: route %s: %v", op.ID, req.URL, err)
+				}
+				err = openapi3filter.ValidateRequest(ctx, &openapi3filter.RequestValidationInput{
+					Request:    req,
+					PathParams: params,
+					Route:      route,
+					Options:    &openapi3filter.Options{AuthenticationFunc: openapi3filter.NoopAuthenticationFunc},
+				})
+				if err != nil {
+					t.Fatalf("%s: %s %s: %v", op.ID, req.Method, req.URL, err)
+				}
+			}
+		})
+	}
+}
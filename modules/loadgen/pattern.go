@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"math/rand/v2"
+	"regexp/syntax"
+	"strings"
+)
+
+// maxRepeat bounds unbounded quantifiers (*, +, {n,}) when sampling a pattern.
+const maxRepeat = 12
+
+// samplePattern produces a random string matching the regular expression pattern.
+//
+// Only the subset of syntax typically found in OpenAPI patterns is supported;
+// anchors are ignored and character classes are sampled uniformly.
+func samplePattern(rng *rand.Rand, pattern string) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	writeRegexp(rng, &b, re.Simplify())
+	return b.String(), nil
+}
+
+func writeRegexp(rng *rand.Rand, b *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(sampleClass(rng, re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(alnum[rng.IntN(len(alnum))])
+	case syntax.OpCapture:
+		for _, sub := range re.Sub {
+			writeRegexp(rng, b, sub)
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegexp(rng, b, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegexp(rng, b, re.Sub[rng.IntN(len(re.Sub))])
+	case syntax.OpQuest:
+		if rng.IntN(2) == 0 {
+			writeRegexp(rng, b, re.Sub[0])
+		}
+	case syntax.OpStar:
+		repeat(rng, b, re.Sub[0], 0, maxRepeat)
+	case syntax.OpPlus:
+		repeat(rng, b, re.Sub[0], 1, maxRepeat)
+	case syntax.OpRepeat:
+		hi := re.Max
+		if hi < 0 {
+			hi = re.Min + maxRepeat
+		}
+		repeat(rng, b, re.Sub[0], re.Min, hi)
+	default:
+		// anchors, word boundaries and empty matches produce no output
+	}
+}
+
+func repeat(rng *rand.Rand, b *strings.Builder, re *syntax.Regexp, lo, hi int) {
+	n := lo
+	if hi > lo {
+		n += rng.IntN(hi - lo + 1)
+	}
+	for range n {
+		writeRegexp(rng, b, re)
+	}
+}
+
+// sampleClass picks a rune from a class given as sorted [lo, hi] pairs,
+// preferring printable ASCII so generated values stay readable.
+func sampleClass(rng *rand.Rand, ranges []rune) rune {
+	var printable []rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], 0x21), min(ranges[i+1], 0x7e)
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+
+	total := 0
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int(ranges[i+1]-ranges[i]) + 1
+	}
+	if total == 0 {
+		return 'a'
+	}
+	n := rng.IntN(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[0]
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+type (
+	// Runner fires generated requests at a target with a fixed request rate.
+	Runner struct {
+		gen    *Generator
+		target *url.URL
+		client *http.Client
+
+		rate        float64
+		concurrency int
+		seed        uint64
+	}
+
+	// RunnerOption configures a Runner.
+	RunnerOption func(*Runner)
+
+	// Report summarizes a run.
+	Report struct {
+		Elapsed time.Duration
+		Total   int64
+		// transport errors (no HTTP response)
+		Errors int64
+
+		ByStatus    map[int]int64
+		ByOperation map[string]map[int]int64
+
+		P50, P95, P99, Max time.Duration
+	}
+
+	result struct {
+		op      string
+		status  int
+		latency time.Duration
+		err     error
+	}
+)
+
+// WithRate sets the target request rate in requests per second. Defaults to 10.
+func WithRate(rps float64) RunnerOption {
+	return func(r *Runner) {
+		if rps > 0 {
+			r.rate = rps
+		}
+	}
+}
+
+// WithConcurrency sets how many requests may be in flight at once. Defaults to 8.
+func WithConcurrency(n int) RunnerOption {
+	return func(r *Runner) {
+		if n > 0 {
+			r.concurrency = n
+		}
+	}
+}
+
+// WithHTTPClient overrides the client used to send requests.
+func WithHTTPClient(c *http.Client) RunnerOption {
+	return func(r *Runner) {
+		if c != nil {
+			r.client = c
+		}
+	}
+}
+
+// WithSeed makes the generated request sequence of each worker reproducible.
+func WithSeed(seed uint64) RunnerOption {
+	return func(r *Runner) {
+		r.seed = seed
+	}
+}
+
+// NewRunner creates a Runner sending requests built by gen to target (e.g. "http://localhost:8080").
+func NewRunner(gen *Generator, target string, opts ...RunnerOption) (*Runner, error) {
+	if gen == nil {
+		return nil, errors.New("loadgen: nil generator")
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("loadgen: invalid target %q", target)
+	}
+
+	r := &Runner{
+		gen:         gen,
+		target:      u,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		rate:        10,
+		concurrency: 8,
+		seed:        rand.Uint64(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+	return r, nil
+}
+
+// Run sends requests until ctx is done and returns the collected report.
+//
+// The rate is enforced by a token bucket shared by all workers; when every worker
+// is busy the effective rate drops below the target instead of queueing requests.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	limiter := rate.NewLimiter(rate.Limit(r.rate), 1)
+	results := make(chan result, r.concurrency)
+
+	var wg sync.WaitGroup
+	for i := range r.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(r.seed, uint64(i)))
+			for {
+				if err := limiter.Wait(ctx); err != nil {
+					return
+				}
+				results <- r.fire(ctx, rng)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	report := &Report{
+		ByStatus:    map[int]int64{},
+		ByOperation: map[string]map[int]int64{},
+	}
+	var latencies []time.Duration
+	for res := range results {
+		if res.err != nil {
+			if ctx.Err() != nil {
+				// requests cut short by the end of the run are not failures
+				continue
+			}
+			report.Errors++
+			slog.DebugContext(ctx, "loadgen request failed", slog.String("operation", res.op), slog.Any("error", res.err))
+		}
+		report.Total++
+		if res.status != 0 {
+			report.ByStatus[res.status]++
+			if report.ByOperation[res.op] == nil {
+				report.ByOperation[res.op] = map[int]int64{}
+			}
+			report.ByOperation[res.op][res.status]++
+			latencies = append(latencies, res.latency)
+		}
+	}
+	report.Elapsed = time.Since(start)
+	report.summarize(latencies)
+	return report, nil
+}
+
+func (r *Runner) fire(ctx context.Context, rng *rand.Rand) result {
+	req, op, err := r.gen.Request(ctx, rng, r.target)
+	if err != nil {
+		return result{op: op.ID, err: err}
+	}
+
+	start := time.Now()
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return result{op: op.ID, err: err}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return result{op: op.ID, status: resp.StatusCode, latency: time.Since(start)}
+}
+
+func (rep *Report) summarize(latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	slices.Sort(latencies)
+	at := func(q float64) time.Duration {
+		return latencies[int(q*float64(len(latencies)-1))]
+	}
+	rep.P50, rep.P95, rep.P99 = at(0.50), at(0.95), at(0.99)
+	rep.Max = latencies[len(latencies)-1]
+}
+
+// Print writes a human readable summary to w.
+func (rep *Report) Print(w io.Writer) {
+	rps := 0.0
+	if rep.Elapsed > 0 {
+		rps = float64(rep.Total) / rep.Elapsed.Seconds()
+	}
+	fmt.Fprintf(w, "requests: %d (%.1f/s) in %s, transport errors: %d\n", rep.Total, rps, rep.Elapsed.Round(time.Millisecond), rep.Errors)
+	fmt.Fprintf(w, "latency: p50=%s p95=%s p99=%s max=%s\n", rep.P50, rep.P95, rep.P99, rep.Max)
+
+	fmt.Fprintln(w, "status:")
+	for _, status := range sortedKeys(rep.ByStatus) {
+		fmt.Fprintf(w, "  %d: %d\n", status, rep.ByStatus[status])
+	}
+
+	ops := make([]string, 0, len(rep.ByOperation))
+	for op := range rep.ByOperation {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	fmt.Fprintln(w, "operations:")
+	for _, op := range ops {
+		fmt.Fprintf(w, "  %s:", op)
+		for _, status := range sortedKeys(rep.ByOperation[op]) {
+			fmt.Fprintf(w, " %d=%d", status, rep.ByOperation[op][status])
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func sortedKeys(m map[int]int64) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
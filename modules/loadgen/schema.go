@@ -0,0 +1,273 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gofrs/uuid/v5"
+)
+
+const (
+	alnum = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+	// maxDepth stops runaway recursion on self-referencing schemas.
+	maxDepth = 8
+	// patternAttempts bounds retries when a sampled pattern violates length limits.
+	patternAttempts = 16
+)
+
+// sampler turns schemas into random values that validate against them.
+type sampler struct {
+	rng *rand.Rand
+	// probability of including an optional object property
+	optionalRate float64
+}
+
+func (s *sampler) value(ref *openapi3.SchemaRef, depth int) (any, error) {
+	if ref == nil || ref.Value == nil || depth > maxDepth {
+		return nil, nil
+	}
+	schema := ref.Value
+
+	if len(schema.Enum) > 0 {
+		return schema.Enum[s.rng.IntN(len(schema.Enum))], nil
+	}
+	if len(schema.AllOf) > 0 {
+		return s.allOf(schema, depth)
+	}
+	if len(schema.OneOf) > 0 {
+		return s.value(schema.OneOf[s.rng.IntN(len(schema.OneOf))], depth+1)
+	}
+	if len(schema.AnyOf) > 0 {
+		return s.value(schema.AnyOf[s.rng.IntN(len(schema.AnyOf))], depth+1)
+	}
+	if schema.Nullable && s.rng.Float64() < 0.1 {
+		return nil, nil
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeString):
+		return s.str(schema)
+	case schema.Type.Is(openapi3.TypeInteger):
+		return s.integer(schema), nil
+	case schema.Type.Is(openapi3.TypeNumber):
+		return s.number(schema), nil
+	case schema.Type.Is(openapi3.TypeBoolean):
+		return s.rng.IntN(2) == 0, nil
+	case schema.Type.Is(openapi3.TypeArray):
+		return s.array(schema, depth)
+	case schema.Type.Is(openapi3.TypeObject), len(schema.Properties) > 0:
+		return s.object(schema, depth)
+	}
+
+	// untyped schema: fall back to the example, if any
+	return schema.Example, nil
+}
+
+// allOf merges the objects sampled from each sub-schema.
+func (s *sampler) allOf(schema *openapi3.Schema, depth int) (any, error) {
+	merged := map[string]any{}
+	for _, sub := range schema.AllOf {
+		v, err := s.value(sub, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			// a non-object allOf member wins as is
+			return v, nil
+		}
+		for k, val := range obj {
+			merged[k] = val
+		}
+	}
+	return merged, nil
+}
+
+func (s *sampler) object(schema *openapi3.Schema, depth int) (any, error) {
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	out := map[string]any{}
+	for name, prop := range schema.Properties {
+		if prop == nil || prop.Value == nil || prop.Value.ReadOnly {
+			continue
+		}
+		if !required[name] && s.rng.Float64() >= s.optionalRate {
+			continue
+		}
+		v, err := s.value(prop, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("property %q: %w", name, err)
+		}
+		out[name] = v
+	}
+	return out, nil
+}
+
+func (s *sampler) array(schema *openapi3.Schema, depth int) (any, error) {
+	lo := int(schema.MinItems)
+	hi := lo + 3
+	if schema.MaxItems != nil {
+		hi = int(*schema.MaxItems)
+	}
+	n := lo
+	if hi > lo {
+		n += s.rng.IntN(hi - lo + 1)
+	}
+
+	out := make([]any, 0, n)
+	for range n {
+		v, err := s.value(schema.Items, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (s *sampler) bounds(schema *openapi3.Schema, defLo, defHi float64) (float64, float64) {
+	lo, hi := defLo, defHi
+	if schema.Min != nil {
+		lo = *schema.Min
+		if schema.ExclusiveMin {
+			lo++
+		}
+	}
+	if schema.Max != nil {
+		hi = *schema.Max
+		if schema.ExclusiveMax {
+			hi--
+		}
+	}
+	if hi < lo {
+		// only a lower bound was given and it is above the default upper one
+		hi = lo + (defHi - defLo)
+	}
+	return lo, hi
+}
+
+func (s *sampler) integer(schema *openapi3.Schema) int64 {
+	lo, hi := s.bounds(schema, 0, 1000)
+	l, h := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if h <= l {
+		return l
+	}
+	return l + s.rng.Int64N(h-l+1)
+}
+
+func (s *sampler) number(schema *openapi3.Schema) float64 {
+	lo, hi := s.bounds(schema, 0, 1000)
+	return lo + s.rng.Float64()*(hi-lo)
+}
+
+func (s *sampler) str(schema *openapi3.Schema) (string, error) {
+	switch schema.Format {
+	case "uuid":
+		return s.uuid().String(), nil
+	case "email":
+		return s.word(8) + "@" + s.word(6) + ".example.com", nil
+	case "date-time":
+		return s.moment().Format(time.RFC3339), nil
+	case "date":
+		return s.moment().Format(time.DateOnly), nil
+	case "uri":
+		return "https://" + s.word(8) + ".example.com/" + s.word(6), nil
+	case "ipv4":
+		return fmt.Sprintf("10.%d.%d.%d", s.rng.IntN(256), s.rng.IntN(256), 1+s.rng.IntN(254)), nil
+	}
+
+	lo := int(schema.MinLength)
+	hi := lo + 12
+	if schema.MaxLength != nil {
+		hi = int(*schema.MaxLength)
+	}
+
+	if schema.Pattern != "" {
+		for range patternAttempts {
+			v, err := samplePattern(s.rng, schema.Pattern)
+			if err != nil {
+				return "", fmt.Errorf("pattern %q: %w", schema.Pattern, err)
+			}
+			if n := len([]rune(v)); n >= lo && n <= hi {
+				return v, nil
+			}
+		}
+		return "", fmt.Errorf("pattern %q: no sample within length [%d, %d]", schema.Pattern, lo, hi)
+	}
+
+	if ex, ok := schema.Example.(string); ok && s.rng.IntN(4) == 0 && len(ex) >= lo && len(ex) <= hi {
+		return ex, nil
+	}
+
+	n := lo
+	if hi > lo {
+		n += s.rng.IntN(hi - lo + 1)
+	}
+	return s.word(n), nil
+}
+
+func (s *sampler) word(n int) string {
+	var b strings.Builder
+	b.Grow(n)
+	for range n {
+		b.WriteByte(alnum[s.rng.IntN(len(alnum))])
+	}
+	return b.String()
+}
+
+// uuid draws a v4 UUID from the sampler's source so seeded runs are reproducible.
+func (s *sampler) uuid() uuid.UUID {
+	var u uuid.UUID
+	binary.BigEndian.PutUint64(u[:8], s.rng.Uint64())
+	binary.BigEndian.PutUint64(u[8:], s.rng.Uint64())
+	u.SetVersion(uuid.V4)
+	u.SetVariant(uuid.VariantRFC9562)
+	return u
+}
+
+// moment returns a time within the last year.
+func (s *sampler) moment() time.Time {
+	return time.Now().UTC().Add(-time.Duration(s.rng.Int64N(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+// format renders a sampled scalar as a parameter string.
+func format(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case int64:
+		return strconv.FormatInt(x, 10)
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	default:
+		return fmt.Sprint(x)
+	}
+}
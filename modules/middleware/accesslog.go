@@ -0,0 +1,168 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// AccessLogConfig configures AccessLog.
+	AccessLogConfig struct {
+		Enabled bool `env:"ENABLED" envDefault:"true"`
+		// SampleRates logs only a share of the requests of busy routes, keyed by route
+		// pattern, e.g. "GET /v1/profiles/{id}=0.1,GET /healthz=0". Routes not listed are
+		// always logged, and so are requests answering 5xx.
+		SampleRates map[string]float64 `env:"SAMPLE_RATES" envSeparator:"," envKeyValSeparator:"="`
+		// RedactParams are query parameters whose values are replaced before logging.
+		RedactParams []string `env:"REDACT_PARAMS" envSeparator:"," envDefault:"token,access_token,api_key,apikey,password,secret,signature"`
+		// TrustProxy takes the client IP from the last X-Forwarded-For entry, as set
+		// by the load balancer in front of the server.
+		TrustProxy bool `env:"TRUST_PROXY" envDefault:"false"`
+	}
+
+	// accessRoute receives the route pattern from NoteRoute, which runs after the
+	// (possibly nested) mux matched the request.
+	accessRoute struct {
+		pattern string
+	}
+
+	accessRouteKey struct{}
+)
+
+// AccessLog writes one "http request" record per request with the method, route
+// pattern, path, redacted query, status, bytes written, latency, client IP, user
+// agent and trace ID. Mount it right after RequestID, so records carry the request
+// id, and mount NoteRoute so it learns the route. 5xx responses are logged at error
+// level.
+func AccessLog(cfg AccessLogConfig) func(http.Handler) http.Handler {
+	redact := make(map[string]bool, len(cfg.RedactParams))
+	for _, name := range cfg.RedactParams {
+		redact[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if !cfg.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			route := &accessRoute{}
+			recorder := newResponseRecorder(w)
+			r = r.WithContext(context.WithValue(r.Context(), accessRouteKey{}, route))
+			next.ServeHTTP(recorder, r)
+
+			pattern := route.pattern
+			if rate, ok := cfg.SampleRates[pattern]; ok && recorder.statusCode < http.StatusInternalServerError && rand.Float64() >= rate {
+				return
+			}
+
+			level := slog.LevelInfo
+			if recorder.statusCode >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("route", pattern),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", recorder.statusCode),
+				slog.Int64("bytes", recorder.bytesWritten),
+				slog.Duration("latency", time.Since(start)),
+				slog.String("client_ip", clientIP(r, cfg.TrustProxy)),
+				slog.String("user_agent", r.UserAgent()),
+			}
+			if r.URL.RawQuery != "" {
+				attrs = append(attrs, slog.String("query", redactQuery(r.URL.RawQuery, redact)))
+			}
+			if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+				attrs = append(attrs, slog.String("trace_id", sc.TraceID().String()))
+			}
+			slog.LogAttrs(r.Context(), level, "http request", attrs...)
+		})
+	}
+}
+
+// NoteRoute reports the pattern the mux matched to AccessLog. Mount it as the
+// innermost global middleware, where the server mux sets r.Pattern once the
+// request returns, and in the route middlewares of nested muxes, whose more
+// specific pattern wins.
+func NoteRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, ok := r.Context().Value(accessRouteKey{}).(*accessRoute)
+		if ok && r.Pattern != "" {
+			route.pattern = r.Pattern
+		}
+		next.ServeHTTP(w, r)
+		// ServeMux stores the match in the request it was handed
+		if ok && route.pattern == "" {
+			route.pattern = r.Pattern
+		}
+	})
+}
+
+func clientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			hops := strings.Split(xff[len(xff)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// redactQuery replaces the values of sensitive parameters; unparsable queries are
+// dropped whole rather than risk logging a secret.
+func redactQuery(raw string, redact map[string]bool) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return "[unparsable]"
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			if redact[strings.ToLower(k)] {
+				v = "REDACTED"
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
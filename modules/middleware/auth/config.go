@@ -0,0 +1,42 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import "time"
+
+type (
+	Config struct {
+		// Enabled turns bearer token authentication on, and with it the enforcement
+		// of the spec's security requirements by the request validator.
+		Enabled bool `env:"ENABLED" envDefault:"false"`
+
+		// JWKSURL serves the issuer's signing keys as a JSON Web Key Set.
+		JWKSURL string `env:"JWKS_URL"`
+		// Issuer must match the "iss" claim when set.
+		Issuer string `env:"ISSUER"`
+		// Audience lists the accepted "aud" values; a token must carry one of them.
+		Audience []string `env:"AUDIENCE" envSeparator:","`
+		// Algorithms are the accepted signing algorithms ("alg" header).
+		Algorithms []string `env:"ALGORITHMS" envSeparator:"," envDefault:"RS256,ES256,EdDSA"`
+		// Leeway tolerates clock skew on the exp, nbf and iat claims.
+		Leeway time.Duration `env:"LEEWAY" envDefault:"30s"`
+
+		// JWKSRefresh is how long fetched keys are used before they are fetched again.
+		JWKSRefresh time.Duration `env:"JWKS_REFRESH" envDefault:"15m"`
+		// JWKSMinRefresh bounds how often a token signed with an unknown key id
+		// triggers an early fetch, e.g. right after the issuer rotated its keys.
+		JWKSMinRefresh time.Duration `env:"JWKS_MIN_REFRESH" envDefault:"1m"`
+	}
+)
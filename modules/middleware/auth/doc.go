@@ -0,0 +1,23 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth authenticates requests carrying a JWT bearer token.
+//
+// Middleware verifies the token against the keys of a JWKS endpoint (fetched and
+// cached by JWKS), checks its issuer, audience and validity window, and stores the
+// resulting auth.Principal in the request context. Requests without a token pass
+// through anonymously; whether that is acceptable is decided by the security
+// requirements of the spec, which AuthenticationFunc enforces from the OpenAPI
+// validator with 401 and 403 problems.
+package auth
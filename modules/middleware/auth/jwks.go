@@ -0,0 +1,296 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/httpclient"
+)
+
+var (
+	// ErrUnknownKey is returned for a key id the key set does not hold.
+	ErrUnknownKey = errors.New("auth: unknown signing key")
+	// ErrKeysUnavailable is returned while the key set could never be fetched.
+	ErrKeysUnavailable = errors.New("auth: signing keys unavailable")
+)
+
+// maxJWKSBytes bounds the key set document, a few keys fit in a fraction of it.
+const maxJWKSBytes = 1 << 20
+
+type (
+	// KeySource resolves the public key a token was signed with.
+	KeySource interface {
+		Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+	}
+
+	// JWKS is a KeySource backed by a JSON Web Key Set endpoint.
+	//
+	// Keys are fetched on first use and again once they are older than the refresh
+	// interval, or earlier when a token names a key id the set does not hold (at
+	// most once per minimum refresh interval). When a fetch fails the previous keys
+	// stay in use.
+	JWKS struct {
+		url    string
+		client *http.Client
+		clock  clock.Clock
+
+		refresh    time.Duration
+		minRefresh time.Duration
+
+		mu          sync.Mutex
+		keys        map[string]crypto.PublicKey
+		fetchedAt   time.Time
+		attemptedAt time.Time
+	}
+
+	// JWKSOption configures a JWKS.
+	JWKSOption func(*JWKS)
+
+	jwkSet struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	jwk struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		Crv string `json:"crv"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	}
+)
+
+// WithHTTPClient overrides the client used to fetch the key set.
+func WithHTTPClient(c *http.Client) JWKSOption {
+	return func(j *JWKS) {
+		if c != nil {
+			j.client = c
+		}
+	}
+}
+
+// WithJWKSClock overrides the time source (useful in tests).
+func WithJWKSClock(c clock.Clock) JWKSOption {
+	return func(j *JWKS) {
+		if c != nil {
+			j.clock = c
+		}
+	}
+}
+
+// WithRefresh sets how long keys are used before they are fetched again, and how
+// often an unknown key id may trigger an early fetch.
+func WithRefresh(refresh, minRefresh time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		if refresh > 0 {
+			j.refresh = refresh
+		}
+		if minRefresh > 0 {
+			j.minRefresh = minRefresh
+		}
+	}
+}
+
+// NewJWKS constructs a key source for the key set served at url.
+func NewJWKS(url string, opts ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:        url,
+		client:     httpclient.New(httpclient.WithTimeout(5 * time.Second)),
+		clock:      clock.RealClock{},
+		refresh:    15 * time.Minute,
+		minRefresh: time.Minute,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(j)
+		}
+	}
+	return j
+}
+
+// Key returns the key with id kid. An empty kid is only resolved when the set
+// holds a single key.
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.clock.Now()
+	key, found := j.lookup(kid)
+	stale := j.keys == nil || now.Sub(j.fetchedAt) >= j.refresh
+	if stale || (!found && now.Sub(j.attemptedAt) >= j.minRefresh) {
+		// fetching under the lock: concurrent verifications wait for one fetch
+		// instead of each hitting the endpoint
+		j.attemptedAt = now
+		if err := j.fetch(ctx); err != nil {
+			if j.keys == nil {
+				return nil, fmt.Errorf("%w: %w", ErrKeysUnavailable, err)
+			}
+			slog.WarnContext(ctx, "auth: jwks refresh failed, keeping previous keys",
+				slog.String("url", j.url),
+				slog.Any("error", err),
+			)
+		}
+		key, found = j.lookup(kid)
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: kid %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+func (j *JWKS) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(j.keys) == 1 {
+		for _, k := range j.keys {
+			return k, true
+		}
+	}
+	k, ok := j.keys[kid]
+	return k, ok
+}
+
+// fetch replaces the keys with the current key set. Callers must hold j.mu.
+func (j *JWKS) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return fmt.Errorf("auth: jwks request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("auth: fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("auth: fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set jwkSet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&set); err != nil {
+		return fmt.Errorf("auth: decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			// one odd key must not take the others down
+			slog.WarnContext(ctx, "auth: skipping jwks key", slog.String("kid", k.Kid), slog.Any("error", err))
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return errors.New("auth: jwks holds no usable signing key")
+	}
+
+	j.keys = keys
+	j.fetchedAt = j.clock.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("modulus: %w", err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("exponent: %w", err)
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("exponent out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("y: %w", err)
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return nil, errors.New("coordinates do not match the curve")
+		}
+		// uncompressed point encoding, which is also checked to lie on the curve
+		point := append(append([]byte{4}, x...), y...)
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("x: %w", err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
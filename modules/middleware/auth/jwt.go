@@ -0,0 +1,157 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"app/modules/auth"
+	"app/modules/middleware/problem"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for a bearer token that does not verify.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// Authenticator verifies JWT bearer tokens and maps them to principals.
+type Authenticator struct {
+	keys   KeySource
+	parser *jwt.Parser
+}
+
+// NewAuthenticator verifies tokens signed by keys according to cfg: accepted
+// algorithms, issuer, audience and clock leeway. Tokens must carry an expiry.
+func NewAuthenticator(cfg Config, keys KeySource) (*Authenticator, error) {
+	if keys == nil {
+		return nil, errors.New("auth: authenticator needs a key source")
+	}
+	if len(cfg.Algorithms) == 0 {
+		return nil, errors.New("auth: no signing algorithm accepted")
+	}
+	for _, alg := range cfg.Algorithms {
+		if alg == "none" || jwt.GetSigningMethod(alg) == nil {
+			return nil, fmt.Errorf("auth: unsupported signing algorithm %q", alg)
+		}
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(cfg.Algorithms),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if len(cfg.Audience) > 0 {
+		opts = append(opts, jwt.WithAudience(cfg.Audience...))
+	}
+	return &Authenticator{keys: keys, parser: jwt.NewParser(opts...)}, nil
+}
+
+// FromConfig builds an Authenticator fetching its keys from cfg.JWKSURL.
+func FromConfig(cfg Config, opts ...JWKSOption) (*Authenticator, error) {
+	if cfg.JWKSURL == "" {
+		return nil, errors.New("auth: JWKS URL is required")
+	}
+	opts = append([]JWKSOption{WithRefresh(cfg.JWKSRefresh, cfg.JWKSMinRefresh)}, opts...)
+	return NewAuthenticator(cfg, NewJWKS(cfg.JWKSURL, opts...))
+}
+
+// Authenticate verifies token and returns the principal it names: the "sub" claim
+// as subject and the "scope"/"scp" claims as scopes.
+//
+// Failures wrap ErrInvalidToken, or ErrKeysUnavailable when no key could be
+// fetched to check the signature.
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*auth.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return a.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		if errors.Is(err, ErrKeysUnavailable) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil || sub == "" {
+		return nil, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	return &auth.Principal{
+		Subject: sub,
+		Scopes:  auth.ScopesFromClaims(claims),
+		Claims:  claims,
+	}, nil
+}
+
+// Middleware authenticates requests sending "Authorization: Bearer <token>" and
+// stores the principal in the request context.
+//
+// Requests without a bearer token, or whose principal an earlier middleware
+// already resolved (e.g. VerifySignatures), pass through untouched. An invalid
+// token is answered with 401, and with 503 while the signing keys cannot be
+// fetched: a rejected token must not silently downgrade to anonymous access.
+func Middleware(a *Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, ok := auth.PrincipalFrom(r.Context()); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			principal, err := a.Authenticate(r.Context(), token)
+			switch {
+			case errors.Is(err, ErrKeysUnavailable):
+				slog.ErrorContext(r.Context(), "auth: cannot verify token", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("authentication temporarily unavailable",
+					problem.WithCode("authentication_unavailable"),
+				))
+				return
+			case err != nil:
+				slog.InfoContext(r.Context(), "auth: token rejected", slog.Any("error", err))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				problem.Write(w, problem.Unauthorized("invalid bearer token",
+					problem.WithCode("invalid_token"),
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+		})
+	}
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
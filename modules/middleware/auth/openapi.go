@@ -0,0 +1,123 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"app/modules/authz"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+var (
+	// ErrUnauthenticated is returned for a secured operation called without a principal.
+	ErrUnauthenticated = errors.New("auth: authentication required")
+	// ErrInsufficientScope is returned when the principal lacks a required scope.
+	ErrInsufficientScope = errors.New("auth: insufficient scope")
+	// ErrUnsupportedScheme is returned for security schemes no authenticator handles.
+	ErrUnsupportedScheme = errors.New("auth: unsupported security scheme")
+)
+
+// Denial describes how to answer a request that failed its security requirements.
+type Denial struct {
+	Status int
+	// Code is the machine readable problem code.
+	Code   string
+	Detail string
+	// Challenge is the WWW-Authenticate value.
+	Challenge string
+}
+
+// AuthenticationFunc checks a security requirement of the spec against the
+// principal Middleware stored, for openapi3filter.Options.AuthenticationFunc.
+//
+// oauth2, openIdConnect and HTTP bearer schemes are satisfied when authz.Require
+// grants every scope the requirement lists; other schemes always fail. While
+// AUTHZ_ENABLED is off the enforcer grants missing scopes and only logs them, so
+// a principal is all the validator requires.
+func AuthenticationFunc() openapi3filter.AuthenticationFunc {
+	return func(_ context.Context, in *openapi3filter.AuthenticationInput) error {
+		scheme := in.SecurityScheme
+		switch {
+		case scheme == nil:
+			return fmt.Errorf("%w: %q", ErrUnsupportedScheme, in.SecuritySchemeName)
+		case scheme.Type == "oauth2", scheme.Type == "openIdConnect":
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "bearer"):
+		default:
+			return fmt.Errorf("%w: %q", ErrUnsupportedScheme, in.SecuritySchemeName)
+		}
+
+		// the enforcer of authz.Middleware expands roles, as for SpecScopes and gRPC
+		err := authz.Require(in.RequestValidationInput.Request.Context(), in.Scopes...)
+		var mse *authz.MissingScopesError
+		switch {
+		case err == nil:
+			return nil
+		case errors.Is(err, authz.ErrUnauthenticated):
+			return ErrUnauthenticated
+		case errors.As(err, &mse):
+			return fmt.Errorf("%w: missing %s", ErrInsufficientScope, strings.Join(mse.Missing, " "))
+		default:
+			return fmt.Errorf("%w: %w", ErrInsufficientScope, err)
+		}
+	}
+}
+
+// DenialOf reports whether err comes from failed security requirements and how to
+// answer it: 403 when a principal lacked scopes for some alternative, 401 otherwise.
+func DenialOf(err error) (Denial, bool) {
+	var sre *openapi3filter.SecurityRequirementsError
+	if !errors.As(err, &sre) {
+		return Denial{}, false
+	}
+	if errors.Is(err, ErrInsufficientScope) {
+		d := Denial{
+			Status:    http.StatusForbidden,
+			Code:      "insufficient_scope",
+			Detail:    "missing required scope",
+			Challenge: `Bearer error="insufficient_scope"`,
+		}
+		if scopes := requiredScopes(sre); scopes != "" {
+			d.Challenge += `, scope="` + scopes + `"`
+		}
+		return d, true
+	}
+	return Denial{
+		Status:    http.StatusUnauthorized,
+		Code:      "unauthenticated",
+		Detail:    "authentication required",
+		Challenge: "Bearer",
+	}, true
+}
+
+// requiredScopes returns the scopes of the first alternative, as the challenge
+// can only name one set.
+func requiredScopes(sre *openapi3filter.SecurityRequirementsError) string {
+	if len(sre.SecurityRequirements) == 0 {
+		return ""
+	}
+	var scopes []string
+	for _, s := range sre.SecurityRequirements[0] {
+		scopes = append(scopes, s...)
+	}
+	slices.Sort(scopes)
+	return strings.Join(slices.Compact(scopes), " ")
+}
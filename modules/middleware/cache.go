@@ -0,0 +1,180 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CacheClass names the kind of route a response belongs to for caching purposes.
+type CacheClass string
+
+const (
+	// CacheMutation covers every method other than GET and HEAD.
+	CacheMutation CacheClass = "mutation"
+	// CacheRead covers single resource reads, revalidated through their ETag.
+	CacheRead CacheClass = "read"
+	// CacheList covers collection reads listed in CacheConfig.ListPaths.
+	CacheList CacheClass = "list"
+	// CacheNoStore covers operator and probe endpoints and error responses.
+	CacheNoStore CacheClass = "no-store"
+)
+
+// CacheConfig configures CacheHygiene. Every directive is sent verbatim.
+type CacheConfig struct {
+	Mutation string `env:"MUTATION" envDefault:"no-store"`
+	Read     string `env:"READ" envDefault:"private, no-cache"`
+	List     string `env:"LIST" envDefault:"private, max-age=5"`
+	NoStore  string `env:"NO_STORE" envDefault:"no-store"`
+
+	// ListPaths are the exact (canonical) collection paths served with the List directive.
+	ListPaths []string `env:"LIST_PATHS" envSeparator:"," envDefault:"/v1/profiles"`
+	// NoStorePrefixes are path prefixes that are never cached, whatever the method.
+	NoStorePrefixes []string `env:"NO_STORE_PREFIXES" envSeparator:"," envDefault:"/admin/,/readyz"`
+
+	// Vary lists the request headers every response varies on; they are merged with
+	// whatever the handler already declared.
+	Vary []string `env:"VARY" envSeparator:"," envDefault:"Accept,Accept-Language,Authorization"`
+}
+
+// CacheHygiene sets Cache-Control and Vary on every response from the class of its
+// route, so generated handlers do not each have to remember cache headers:
+//
+//   - mutations are never stored;
+//   - reads are private and revalidated, the ETag makes that cheap;
+//   - lists are private with a short max-age;
+//   - error responses (status >= 400) and NoStorePrefixes are never stored.
+//
+// A Cache-Control set by the handler itself always wins. The middleware runs before
+// the mux, so routes are matched on the canonical URL path: it must come after
+// RoutingHygiene.
+func CacheHygiene(cfg CacheConfig) (func(http.Handler) http.Handler, error) {
+	directives := map[CacheClass]string{
+		CacheMutation: cfg.Mutation,
+		CacheRead:     cfg.Read,
+		CacheList:     cfg.List,
+		CacheNoStore:  cfg.NoStore,
+	}
+	for class, d := range directives {
+		if strings.TrimSpace(d) == "" {
+			return nil, fmt.Errorf("middleware: empty Cache-Control directive for %s responses", class)
+		}
+	}
+
+	vary := make([]string, 0, len(cfg.Vary))
+	for _, h := range cfg.Vary {
+		if h = strings.TrimSpace(h); h != "" {
+			vary = append(vary, http.CanonicalHeaderKey(h))
+		}
+	}
+
+	classify := func(r *http.Request) CacheClass {
+		for _, prefix := range cfg.NoStorePrefixes {
+			if prefix != "" && strings.HasPrefix(r.URL.Path, prefix) {
+				return CacheNoStore
+			}
+		}
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		default:
+			return CacheMutation
+		}
+		if slices.Contains(cfg.ListPaths, r.URL.Path) {
+			return CacheList
+		}
+		return CacheRead
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cw := &cacheHeaderWriter{
+				ResponseWriter: w,
+				directive:      directives[classify(r)],
+				errDirective:   directives[CacheNoStore],
+				vary:           vary,
+			}
+			next.ServeHTTP(cw, r)
+		})
+	}, nil
+}
+
+// cacheHeaderWriter fills in the cache headers right before the status line goes out.
+type cacheHeaderWriter struct {
+	http.ResponseWriter
+	directive    string
+	errDirective string
+	vary         []string
+	wroteHeader  bool
+}
+
+func (c *cacheHeaderWriter) WriteHeader(code int) {
+	// informational responses carry no cache semantics
+	if code >= 200 && !c.wroteHeader {
+		c.wroteHeader = true
+		c.apply(code)
+	}
+	c.ResponseWriter.WriteHeader(code)
+}
+
+func (c *cacheHeaderWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	return c.ResponseWriter.Write(b)
+}
+
+func (c *cacheHeaderWriter) Flush() {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *cacheHeaderWriter) Unwrap() http.ResponseWriter { return c.ResponseWriter }
+
+func (c *cacheHeaderWriter) apply(code int) {
+	h := c.Header()
+	if h.Get("Cache-Control") == "" {
+		if code >= http.StatusBadRequest {
+			h.Set("Cache-Control", c.errDirective)
+		} else {
+			h.Set("Cache-Control", c.directive)
+		}
+	}
+
+	if len(c.vary) == 0 {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				seen[strings.ToLower(f)] = true
+			}
+		}
+	}
+	if seen["*"] {
+		return
+	}
+	for _, v := range c.vary {
+		if !seen[strings.ToLower(v)] {
+			h.Add("Vary", v)
+			seen[strings.ToLower(v)] = true
+		}
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// CompressionConfig configures Compress.
+type CompressionConfig struct {
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+
+	// Encodings lists the supported content codings in order of preference; the
+	// first one the client accepts wins.
+	Encodings []string `env:"ENCODINGS" envSeparator:"," envDefault:"br,gzip"`
+	// ContentTypes are the media types worth compressing, without parameters.
+	ContentTypes []string `env:"CONTENT_TYPES" envSeparator:"," envDefault:"application/json,application/yaml,text/plain,text/html,text/css,application/javascript"`
+	// MinSize is the body size below which responses are sent as is: the coding
+	// overhead is not worth it.
+	MinSize int `env:"MIN_SIZE" envDefault:"1024"`
+
+	GzipLevel   int `env:"GZIP_LEVEL" envDefault:"5"`
+	BrotliLevel int `env:"BROTLI_LEVEL" envDefault:"4"`
+}
+
+// compressEncoder creates the writers of one content coding.
+type compressEncoder struct {
+	pool sync.Pool
+}
+
+func (e *compressEncoder) get(w io.Writer) compressWriteCloser {
+	enc := e.pool.Get().(compressWriteCloser)
+	enc.Reset(w)
+	return enc
+}
+
+func (e *compressEncoder) put(enc compressWriteCloser) { e.pool.Put(enc) }
+
+type compressWriteCloser interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Compress encodes response bodies with gzip or brotli, as negotiated through
+// Accept-Encoding.
+//
+// Only bodies of an allowed content type and of at least MinSize bytes are
+// encoded; responses that already carry a Content-Encoding, HEAD requests and
+// bodiless statuses pass through. Problem documents are never encoded, RequestID
+// rewrites them further out. Mount it right after Telemetry and AccessLog so the
+// bytes they record are the compressed ones, the size that went over the wire.
+//
+// Each content coding is a representation of its own, with its own strong
+// validator (RFC 9110, section 8.8.3): an encoded response gets the ETag of the
+// handler with the coding appended, "<tag>-gzip" or "<tag>-br". The suffix is
+// stripped again from If-Match and If-None-Match before the handler compares
+// them, and a 304 answering such a tag carries it back.
+func Compress(cfg CompressionConfig) (func(http.Handler) http.Handler, error) {
+	if !cfg.Enabled {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+	if cfg.GzipLevel < gzip.HuffmanOnly || cfg.GzipLevel > gzip.BestCompression {
+		return nil, fmt.Errorf("middleware: gzip level %d out of range", cfg.GzipLevel)
+	}
+	if cfg.BrotliLevel < brotli.BestSpeed || cfg.BrotliLevel > brotli.BestCompression {
+		return nil, fmt.Errorf("middleware: brotli level %d out of range", cfg.BrotliLevel)
+	}
+
+	encoders := make(map[string]*compressEncoder, len(cfg.Encodings))
+	var order []string
+	for _, name := range cfg.Encodings {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || encoders[name] != nil {
+			continue
+		}
+		switch name {
+		case "gzip":
+			encoders[name] = &compressEncoder{pool: sync.Pool{New: func() any {
+				// the level is validated above
+				w, _ := gzip.NewWriterLevel(io.Discard, cfg.GzipLevel)
+				return w
+			}}}
+		case "br":
+			encoders[name] = &compressEncoder{pool: sync.Pool{New: func() any {
+				return brotli.NewWriterLevel(io.Discard, cfg.BrotliLevel)
+			}}}
+		default:
+			return nil, fmt.Errorf("middleware: unsupported content coding %q", name)
+		}
+		order = append(order, name)
+	}
+
+	types := make(map[string]bool, len(cfg.ContentTypes))
+	for _, t := range cfg.ContentTypes {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types[t] = true
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := negotiateEncoding(r.Header.Values("Accept-Encoding"), order)
+			if r.Method == http.MethodHead {
+				encoding = ""
+			}
+			ifNoneMatch := r.Header.Get("If-None-Match")
+			cloned := false
+			for _, field := range []string{"If-Match", "If-None-Match"} {
+				v := r.Header.Get(field)
+				if identity := identityETags(v, order); identity != v {
+					if !cloned {
+						r, cloned = r.Clone(r.Context()), true
+					}
+					r.Header.Set(field, identity)
+				}
+			}
+			cw := &compressResponseWriter{
+				ResponseWriter: w,
+				types:          types,
+				minSize:        cfg.MinSize,
+				encoding:       encoding,
+				encoder:        encoders[encoding],
+				codings:        order,
+				ifNoneMatch:    ifNoneMatch,
+			}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}, nil
+}
+
+// negotiateEncoding picks the first coding of order the Accept-Encoding values
+// allow, or "" for identity.
+func negotiateEncoding(accept []string, order []string) string {
+	if len(accept) == 0 || len(order) == 0 {
+		return ""
+	}
+	q := make(map[string]float64)
+	for _, v := range accept {
+		for _, part := range strings.Split(v, ",") {
+			name, params, _ := strings.Cut(part, ";")
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			weight := 1.0
+			if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					weight = f
+				}
+			}
+			q[name] = weight
+		}
+	}
+	for _, name := range order {
+		weight, ok := q[name]
+		if !ok {
+			weight, ok = q["*"]
+		}
+		if ok && weight > 0 {
+			return name
+		}
+	}
+	return ""
+}
+
+// compressResponseWriter holds back the first MinSize bytes to decide whether the
+// body is worth encoding, then streams it through the encoder.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	types    map[string]bool
+	minSize  int
+	encoding string
+	encoder  *compressEncoder
+
+	// the configured codings and the If-None-Match of the request as sent
+	codings     []string
+	ifNoneMatch string
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	enc         compressWriteCloser
+}
+
+func (c *compressResponseWriter) WriteHeader(code int) {
+	if code < 200 {
+		// informational responses go out right away
+		c.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if c.wroteHeader {
+		return
+	}
+	c.wroteHeader = true
+	c.status = code
+
+	h := c.Header()
+	if c.eligible() {
+		addVary(h, "Accept-Encoding")
+	}
+	if code == http.StatusNotModified {
+		c.notModifiedETag()
+	}
+	switch {
+	case c.encoder == nil, !c.eligible(), h.Get("Content-Encoding") != "",
+		code == http.StatusNoContent, code == http.StatusNotModified:
+		c.passThrough()
+	default:
+		if n, err := strconv.Atoi(h.Get("Content-Length")); err == nil && n < c.minSize {
+			c.passThrough()
+		}
+	}
+}
+
+func (c *compressResponseWriter) Write(b []byte) (int, error) {
+	if !c.wroteHeader {
+		if c.Header().Get("Content-Type") == "" {
+			// sniff now, the stdlib would only see the encoded bytes
+			c.Header().Set("Content-Type", http.DetectContentType(b))
+		}
+		c.WriteHeader(http.StatusOK)
+	}
+	if c.enc != nil {
+		return c.enc.Write(b)
+	}
+	if c.decided {
+		return c.ResponseWriter.Write(b)
+	}
+	c.buf = append(c.buf, b...)
+	if len(c.buf) >= c.minSize {
+		if err := c.startEncoding(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush starts encoding whatever is buffered: a handler that flushes streams and
+// must not wait for MinSize bytes.
+func (c *compressResponseWriter) Flush() {
+	if !c.wroteHeader {
+		c.WriteHeader(http.StatusOK)
+	}
+	if !c.decided {
+		if err := c.startEncoding(); err != nil {
+			return
+		}
+	}
+	if c.enc != nil {
+		_ = c.enc.Flush()
+	}
+	_ = http.NewResponseController(c.ResponseWriter).Flush()
+}
+
+func (c *compressResponseWriter) Unwrap() http.ResponseWriter { return c.ResponseWriter }
+
+// eligible reports whether the content type of the response is worth encoding.
+func (c *compressResponseWriter) eligible() bool {
+	mt, _, err := mime.ParseMediaType(c.Header().Get("Content-Type"))
+	return err == nil && c.types[mt] && mt != "application/problem+json"
+}
+
+func (c *compressResponseWriter) passThrough() {
+	c.decided = true
+	c.ResponseWriter.WriteHeader(c.status)
+}
+
+func (c *compressResponseWriter) startEncoding() error {
+	c.decided = true
+	h := c.Header()
+	h.Set("Content-Encoding", c.encoding)
+	h.Del("Content-Length")
+	if tag := h.Get("ETag"); tag != "" {
+		h.Set("ETag", codedETag(tag, c.encoding))
+	}
+	c.ResponseWriter.WriteHeader(c.status)
+
+	c.enc = c.encoder.get(c.ResponseWriter)
+	buf := c.buf
+	c.buf = nil
+	if _, err := c.enc.Write(buf); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *compressResponseWriter) close() {
+	if !c.wroteHeader {
+		// the handler wrote nothing, the stdlib answers 200 with an empty body
+		return
+	}
+	if c.enc != nil {
+		_ = c.enc.Close()
+		c.encoder.put(c.enc)
+		c.enc = nil
+		return
+	}
+	if !c.decided {
+		// the whole body stayed below MinSize
+		c.passThrough()
+		_, _ = c.ResponseWriter.Write(c.buf)
+	}
+}
+
+// notModifiedETag gives a 304 the tag of the encoded representation the client
+// holds, when its If-None-Match named that one rather than the identity.
+func (c *compressResponseWriter) notModifiedETag() {
+	h := c.Header()
+	tag := h.Get("ETag")
+	if tag == "" || c.ifNoneMatch == "" {
+		return
+	}
+	for candidate := range strings.SplitSeq(c.ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		for _, coding := range c.codings {
+			if coded := codedETag(tag, coding); coded != tag && candidate == coded {
+				h.Set("ETag", coded)
+				return
+			}
+		}
+	}
+}
+
+// codedETag is the strong tag of the representation encoded with coding: "abc"
+// becomes "abc-gzip". Weak tags are returned as they are, they already compare
+// equal across codings.
+func codedETag(tag, coding string) string {
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return tag
+	}
+	return tag[:len(tag)-1] + "-" + coding + `"`
+}
+
+// identityETags strips the suffix codedETag appends from the entity tags of an
+// If-Match or If-None-Match value.
+func identityETags(value string, codings []string) string {
+	if !strings.Contains(value, "-") {
+		return value
+	}
+	tags := strings.Split(value, ",")
+	for i, tag := range tags {
+		trimmed := strings.TrimSpace(tag)
+		for _, coding := range codings {
+			if opaque, ok := strings.CutSuffix(trimmed, "-"+coding+`"`); ok {
+				tags[i] = opaque + `"`
+				break
+			}
+		}
+	}
+	return strings.Join(tags, ",")
+}
+
+// addVary adds field to Vary unless it, or "*", is already listed.
+func addVary(h http.Header, field string) {
+	for _, v := range h.Values("Vary") {
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f == "*" || strings.EqualFold(f, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"app/modules/i18n"
+
+	"golang.org/x/text/language"
+)
+
+// LocalizeProblems translates problem+json responses according to Accept-Language.
+//
+// The negotiated language is stored in the request context (see i18n.LocaleFrom).
+// Problem bodies are looked up by their "code" first, then by "status.<status>":
+//   - a code match replaces both title and detail
+//   - a status match only replaces the title, since details written by handlers
+//     are usually more specific than the generic status text
+//
+// Requests negotiated to the catalog's fallback language pass through untouched.
+func LocalizeProblems(catalog *i18n.Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if catalog == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			tag := catalog.Negotiate(r.Header.Get("Accept-Language"))
+			r = r.WithContext(i18n.WithLocale(r.Context(), tag))
+			if tag == catalog.Fallback() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			lw := &problemLocalizer{ResponseWriter: w}
+			next.ServeHTTP(lw, r)
+			if lw.buffering {
+				lw.flush(catalog, tag)
+			}
+		})
+	}
+}
+
+// problemLocalizer holds back problem+json bodies so they can be rewritten once complete.
+type problemLocalizer struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	buffering   bool
+	buf         bytes.Buffer
+}
+
+func (p *problemLocalizer) WriteHeader(code int) {
+	if p.wroteHeader {
+		return
+	}
+	p.wroteHeader = true
+	p.status = code
+	if strings.HasPrefix(p.Header().Get("Content-Type"), "application/problem+json") {
+		p.buffering = true
+		return
+	}
+	p.ResponseWriter.WriteHeader(code)
+}
+
+func (p *problemLocalizer) Write(b []byte) (int, error) {
+	if !p.wroteHeader {
+		p.WriteHeader(http.StatusOK)
+	}
+	if p.buffering {
+		return p.buf.Write(b)
+	}
+	return p.ResponseWriter.Write(b)
+}
+
+func (p *problemLocalizer) Unwrap() http.ResponseWriter { return p.ResponseWriter }
+
+func (p *problemLocalizer) flush(catalog *i18n.Catalog, tag language.Tag) {
+	body := p.buf.Bytes()
+	if localized, found, ok := localizeProblem(catalog, tag, p.status, body); ok {
+		body = localized
+		p.Header().Set("Content-Language", found.String())
+	}
+	p.Header().Add("Vary", "Accept-Language")
+	p.Header().Del("Content-Length")
+	p.ResponseWriter.WriteHeader(p.status)
+	_, _ = p.ResponseWriter.Write(body)
+}
+
+func localizeProblem(catalog *i18n.Catalog, tag language.Tag, status int, body []byte) ([]byte, language.Tag, bool) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, language.Und, false
+	}
+
+	code, _ := doc["code"].(string)
+	msg, found, byCode := catalog.Lookup(tag, code)
+	if code == "" || !byCode {
+		var ok bool
+		msg, found, ok = catalog.Lookup(tag, "status."+strconv.Itoa(status))
+		if !ok {
+			return nil, language.Und, false
+		}
+		// generic status text only names the problem, keep the handler's detail
+		msg.Detail = ""
+	}
+
+	if msg.Title != "" {
+		doc["title"] = msg.Title
+	}
+	if msg.Detail != "" {
+		doc["detail"] = msg.Detail
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, language.Und, false
+	}
+	return append(out, '\n'), found, true
+}
@@ -0,0 +1,289 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/modules/auth"
+	"app/modules/db"
+	"app/modules/middleware/problem"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key of a retryable request.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKey = 255
+)
+
+type (
+	// IdempotencyConfig configures Idempotency.
+	IdempotencyConfig struct {
+		Enabled bool `env:"ENABLED" envDefault:"false"`
+		// TTL is how long a key and its response are kept; retries after it run again.
+		TTL time.Duration `env:"TTL" envDefault:"24h"`
+		// MaxBodyBytes bounds the request bodies fingerprinted and the responses
+		// stored. Keyed requests with a larger body are rejected with 413, larger
+		// responses are sent but not stored.
+		MaxBodyBytes int64 `env:"MAX_BODY_BYTES" envDefault:"1048576"`
+	}
+
+	// idempotencyRecord is what the store holds for a key: only the fingerprint while
+	// the first request runs, then its response.
+	idempotencyRecord struct {
+		Fingerprint string      `json:"fingerprint"`
+		Done        bool        `json:"done"`
+		Status      int         `json:"status,omitempty"`
+		Header      http.Header `json:"header,omitempty"`
+		Body        []byte      `json:"body,omitempty"`
+	}
+)
+
+// Idempotency makes POST requests carrying an Idempotency-Key safe to retry.
+//
+// The first request with a key runs and its response (status, headers, body) is
+// stored in kv for cfg.TTL, next to a fingerprint of the method, path and body.
+// A retry with the same key and payload gets the stored response replayed, marked
+// with Idempotent-Replayed: true, and never reaches the handler. Reusing a key with
+// a different payload, or while the first request still runs, is rejected with 409.
+//
+// Keys are scoped to the principal, so callers cannot replay each other's
+// responses; mount it after authentication. 5xx responses are not stored, so a
+// retry runs again. A store error answers 503; give kv a FailOpen degraded mode to
+// serve requests without replay protection during an outage instead.
+func Idempotency(kv db.CASKV, cfg IdempotencyConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKey {
+				problem.Write(w, problem.BadRequest("Idempotency-Key is too long",
+					problem.WithCode("invalid_idempotency_key"),
+					problem.WithInvalidParam(IdempotencyKeyHeader, "must be at most 255 characters"),
+				))
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(r.Body, cfg.MaxBodyBytes+1))
+			if err != nil {
+				problem.Write(w, problem.BadRequest("request body could not be read"))
+				return
+			}
+			if int64(len(body)) > cfg.MaxBodyBytes {
+				problem.Write(w, problem.New(
+					problem.WithStatus(http.StatusRequestEntityTooLarge),
+					problem.WithTitle(http.StatusText(http.StatusRequestEntityTooLarge)),
+					problem.WithDetail("request body is too large for an Idempotency-Key"),
+					problem.WithCode("idempotency_body_too_large"),
+				))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			storeKey := idempotencyStoreKey(ctx, key)
+			fingerprint := idempotencyFingerprint(r, body)
+
+			prev, err := claimIdempotencyKey(ctx, kv, storeKey, fingerprint)
+			if err != nil {
+				slog.ErrorContext(ctx, "middleware: idempotency store failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("idempotency keys are unavailable, retry later"))
+				return
+			}
+			if prev != nil {
+				replayIdempotent(w, prev, fingerprint)
+				return
+			}
+
+			rec := &idempotencyRecorder{ResponseWriter: w, limit: cfg.MaxBodyBytes, status: http.StatusOK}
+			// a panicking handler must not leave the key claimed until it expires
+			stored := false
+			defer func() {
+				if !stored {
+					releaseIdempotencyKey(ctx, kv, storeKey)
+				}
+			}()
+			next.ServeHTTP(rec, r)
+
+			if rec.status >= http.StatusInternalServerError || rec.overflow {
+				return
+			}
+			done := idempotencyRecord{
+				Fingerprint: fingerprint,
+				Done:        true,
+				Status:      rec.status,
+				Header:      rec.stored,
+				Body:        rec.body.Bytes(),
+			}
+			if _, err := kv.AtomicSet(context.WithoutCancel(ctx), storeKey, done); err != nil {
+				slog.WarnContext(ctx, "middleware: idempotent response not stored", slog.Any("error", err))
+				return
+			}
+			stored = true
+		})
+	}
+}
+
+// claimIdempotencyKey marks key as in flight, or returns the record already held.
+//
+// The claim is a compare-and-set on the value just read, so of two requests racing
+// for a key exactly one claims it and the other gets its record.
+func claimIdempotencyKey(ctx context.Context, kv db.CASKV, key, fingerprint string) (*idempotencyRecord, error) {
+	for {
+		v, err := kv.AtomicGet(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		prev, err := decodeIdempotencyRecord(v)
+		if err != nil || prev != nil {
+			return prev, err
+		}
+
+		// nothing stored yet, or the empty value of a released key
+		var expected any
+		if v != nil {
+			expected = v
+		}
+		err = kv.AtomicCompareAndSet(ctx, key, expected, idempotencyRecord{Fingerprint: fingerprint})
+		if !errors.Is(err, db.ErrCASMismatch) {
+			return nil, err
+		}
+		// another request claimed or released the key in between: read it again
+	}
+}
+
+func releaseIdempotencyKey(ctx context.Context, kv db.KV, key string) {
+	// an empty value reads as no record
+	if _, err := kv.AtomicSet(context.WithoutCancel(ctx), key, ""); err != nil {
+		slog.WarnContext(ctx, "middleware: idempotency key not released", slog.Any("error", err))
+	}
+}
+
+func decodeIdempotencyRecord(v any) (*idempotencyRecord, error) {
+	b, _ := v.([]byte)
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var rec idempotencyRecord
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func replayIdempotent(w http.ResponseWriter, prev *idempotencyRecord, fingerprint string) {
+	switch {
+	case prev.Fingerprint != fingerprint:
+		problem.Write(w, problem.New(
+			problem.WithStatus(http.StatusConflict),
+			problem.WithTitle(http.StatusText(http.StatusConflict)),
+			problem.WithDetail("Idempotency-Key was already used for a different request"),
+			problem.WithCode("idempotency_key_reused"),
+		))
+	case !prev.Done:
+		w.Header().Set("Retry-After", "1")
+		problem.Write(w, problem.New(
+			problem.WithStatus(http.StatusConflict),
+			problem.WithTitle(http.StatusText(http.StatusConflict)),
+			problem.WithDetail("a request with this Idempotency-Key is still being processed"),
+			problem.WithCode("idempotency_key_in_use"),
+		))
+	default:
+		h := w.Header()
+		for name, values := range prev.Header {
+			h[name] = values
+		}
+		h.Set(IdempotentReplayedHeader, "true")
+		w.WriteHeader(prev.Status)
+		_, _ = w.Write(prev.Body)
+	}
+}
+
+// idempotencyStoreKey scopes key to the caller, anonymous callers share one scope.
+// The subject is length-prefixed, as subjects and keys may both contain ":".
+func idempotencyStoreKey(ctx context.Context, key string) string {
+	subject := ""
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		subject = p.Subject
+	}
+	return strconv.Itoa(len(subject)) + ":" + subject + ":" + key
+}
+
+func idempotencyFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method)
+	h.Write([]byte{0})
+	io.WriteString(h, r.URL.RequestURI())
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyRecorder passes the response through while keeping a copy to store.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	limit int64
+
+	wroteHeader bool
+	status      int
+	stored      http.Header
+	body        bytes.Buffer
+	overflow    bool
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	if code >= 200 && !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = code
+		rec.stored = rec.Header().Clone()
+		// recomputed or per response
+		rec.stored.Del("Date")
+		rec.stored.Del("Content-Length")
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+	if !rec.overflow {
+		if int64(rec.body.Len()+len(b)) > rec.limit {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(b)
+		}
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter { return rec.ResponseWriter }
@@ -1,8 +1,11 @@
 package problem
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Problem is an RFC7807 Problem Details document with optional extensions.
@@ -86,6 +89,37 @@ func WithTraceID(traceID string) Option {
 	return func(p *Problem) { p.TraceID = strPtr(traceID) }
 }
 
+// WithInstance sets the URI reference identifying this occurrence of the problem.
+func WithInstance(instance string) Option {
+	return func(p *Problem) { p.Instance = strPtr(instance) }
+}
+
+// WithSpanContext sets the trace id of the span active in ctx, if any.
+func WithSpanContext(ctx context.Context) Option {
+	return func(p *Problem) {
+		if id := TraceID(ctx); id != "" {
+			p.TraceID = strPtr(id)
+		}
+	}
+}
+
+// FromRequest sets the trace id of the request's span and uses the request path
+// as instance.
+func FromRequest(r *http.Request) Option {
+	return func(p *Problem) {
+		WithSpanContext(r.Context())(p)
+		WithInstance(r.URL.Path)(p)
+	}
+}
+
+// TraceID returns the trace id of the span context in ctx, or "" when there is none.
+func TraceID(ctx context.Context) string {
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
 func WithInvalidParam(name, reason string) Option {
 	return func(p *Problem) {
 		if p.InvalidParams == nil {
@@ -116,6 +150,33 @@ func BadRequest(detail string, opts ...Option) *Problem {
 	return New(append(base, opts...)...)
 }
 
+func Unauthorized(detail string, opts ...Option) *Problem {
+	base := []Option{
+		WithTitle("Unauthorized"),
+		WithStatus(http.StatusUnauthorized),
+		WithDetail(detail),
+	}
+	return New(append(base, opts...)...)
+}
+
+func Forbidden(detail string, opts ...Option) *Problem {
+	base := []Option{
+		WithTitle("Forbidden"),
+		WithStatus(http.StatusForbidden),
+		WithDetail(detail),
+	}
+	return New(append(base, opts...)...)
+}
+
+func NotFound(detail string, opts ...Option) *Problem {
+	base := []Option{
+		WithTitle("Not Found"),
+		WithStatus(http.StatusNotFound),
+		WithDetail(detail),
+	}
+	return New(append(base, opts...)...)
+}
+
 func MethodNotAllowed(detail string, opts ...Option) *Problem {
 	base := []Option{
 		WithTitle("Method Not Allowed"),
@@ -143,6 +204,15 @@ func Internal(detail string, opts ...Option) *Problem {
 	return New(append(base, opts...)...)
 }
 
+func ServiceUnavailable(detail string, opts ...Option) *Problem {
+	base := []Option{
+		WithTitle("Service Unavailable"),
+		WithStatus(http.StatusServiceUnavailable),
+		WithDetail(detail),
+	}
+	return New(append(base, opts...)...)
+}
+
 func strPtr(s string) *string { return &s }
 
 // MarshalJSON merges Extensions into the base Problem object.
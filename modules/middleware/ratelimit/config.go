@@ -8,6 +8,12 @@ type KeyStrategyId string
 
 const (
 	RemoteIpKeyStrategy KeyStrategyId = "remote_ip"
+	// SubjectKeyStrategy keys by authenticated subject or Bearer token, see SubjectKeyFunc.
+	SubjectKeyStrategy KeyStrategyId = "subject"
+	// APIKeyKeyStrategy keys by the API key header, see RestHTTPConfig.APIKeyHeader.
+	APIKeyKeyStrategy KeyStrategyId = "api_key"
+	// SessionKeyStrategy keys by the session cookie, see RestHTTPConfig.SessionCookie.
+	SessionKeyStrategy KeyStrategyId = "session"
 )
 
 // TODO: sane defaults so the apps run right out of the box
@@ -17,6 +23,43 @@ type (
 		DefaultPolicy       EndpointRule `envPrefix:"DEFAULT_"`
 		AllowIfNoMatch      bool         `env:"ALLOW_IF_NO_MATCH"`
 		AllowIfNoIdentifier bool         `env:"ALLOW_IF_NO_ID"`
+		// FailOpen lets requests through while the counter store is unavailable
+		FailOpen bool `env:"FAIL_OPEN" envDefault:"false"`
+
+		Fallback FallbackConfig `envPrefix:"FALLBACK_"`
+
+		// Store selects where the sliding window counters live.
+		Store CounterBackend `env:"STORE" envDefault:"redis"`
+
+		// Algorithm selects the limiter of every rule.
+		Algorithm Algorithm `env:"ALGORITHM" envDefault:"sliding_window"`
+		// Burst caps back to back requests under AlgorithmGCRA, zero uses the rule limit.
+		Burst int64 `env:"BURST"`
+
+		// Headers selects which rate limit headers responses carry.
+		Headers HeaderMode `env:"HEADERS" envDefault:"legacy"`
+
+		// APIKeyHeader is read by the api_key strategy.
+		APIKeyHeader string `env:"API_KEY_HEADER" envDefault:"X-API-Key"`
+		// SessionCookie is read by the session strategy.
+		SessionCookie string `env:"SESSION_COOKIE" envDefault:"session_id"`
+	}
+
+	// Algorithm names a rate limiting algorithm.
+	Algorithm string
+
+	// HeaderMode names a set of rate limit response headers.
+	HeaderMode string
+
+	// CounterBackend names the store holding rate limit counters.
+	CounterBackend string
+
+	// FallbackConfig enables process-local counters while the shared counter store is down.
+	FallbackConfig struct {
+		Enabled bool `env:"ENABLED" envDefault:"false"`
+		// Persist saves the local counters on shutdown and restores them on startup,
+		// so restarting a node does not reset abuse counters.
+		Persist bool `env:"PERSIST" envDefault:"true"`
 	}
 
 	Route struct {
@@ -32,3 +75,28 @@ type (
 		KeyStrategy KeyStrategyId `env:"KEY_STRATEGY"`
 	}
 )
+
+const (
+	// AlgorithmSlidingWindow interpolates between two fixed windows of counters.
+	AlgorithmSlidingWindow Algorithm = "sliding_window"
+	// AlgorithmGCRA spaces requests evenly over the window, see modules/db/redis/gcra.
+	AlgorithmGCRA Algorithm = "gcra"
+)
+
+const (
+	// CounterBackendRedis keeps counters in Redis.
+	CounterBackendRedis CounterBackend = "redis"
+	// CounterBackendPostgres keeps counters in a Postgres table, for deployments
+	// without Redis; see modules/db/postgres/counter. Not supported by AlgorithmGCRA.
+	CounterBackendPostgres CounterBackend = "postgres"
+)
+
+const (
+	// HeaderModeLegacy emits X-RateLimit-Limit, -Remaining, -Window-Seconds and -Reset-Seconds.
+	HeaderModeLegacy HeaderMode = "legacy"
+	// HeaderModeDraft emits RateLimit-Limit, -Remaining, -Reset and -Policy per the IETF
+	// draft-ietf-httpapi-ratelimit-headers, plus Retry-After on rejected requests.
+	HeaderModeDraft HeaderMode = "draft"
+	// HeaderModeBoth emits the legacy and the draft headers, for migrating clients.
+	HeaderModeBoth HeaderMode = "both"
+)
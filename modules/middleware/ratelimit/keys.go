@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"app/modules/apikey"
+	"app/modules/auth"
+	rl "app/modules/ratelimit"
+)
+
+const (
+	// default header and cookie names read by KeyStrategies
+	DefaultAPIKeyHeader  = "X-API-Key"
+	DefaultSessionCookie = "session_id"
+)
+
+// KeyStrategies returns every built-in key strategy by the name rules reference in
+// KEY_STRATEGY, with the API key header and session cookie taken from cfg.
+//
+// Callers may add their own strategies to the returned map before ParsePolicy.
+func KeyStrategies(cfg *RestHTTPConfig) map[KeyStrategyId]KeyFunc {
+	header, cookie := DefaultAPIKeyHeader, DefaultSessionCookie
+	if cfg != nil {
+		if cfg.APIKeyHeader != "" {
+			header = cfg.APIKeyHeader
+		}
+		if cfg.SessionCookie != "" {
+			cookie = cfg.SessionCookie
+		}
+	}
+	return map[KeyStrategyId]KeyFunc{
+		RemoteIpKeyStrategy: RemoteIpKeyFunc,
+		SubjectKeyStrategy:  SubjectKeyFunc,
+		APIKeyKeyStrategy:   APIKeyKeyFunc(header),
+		SessionKeyStrategy:  SessionKeyFunc(cookie),
+	}
+}
+
+// SubjectKeyFunc keys requests by caller.
+//
+// The subject of a Principal resolved by an earlier authentication middleware is
+// used when present. Otherwise the raw Bearer token keys the request: its claims are
+// not trusted before verification, since a forged "sub" would drain another user's budget.
+// Requests with neither yield an empty key, see AllowIfNoIdentifier.
+func SubjectKeyFunc(r *http.Request) rl.Key {
+	if p, ok := auth.PrincipalFrom(r.Context()); ok && p.Subject != "" {
+		return rl.Key("sub:" + p.Subject)
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return digestKey("bearer:", strings.TrimSpace(token))
+}
+
+// APIKeyKeyFunc keys requests by the API key sent in header.
+//
+// Once apikey.Middleware verified the key, its id keys the request, so the budget
+// survives a rename of the header and shows up readably in the counter store.
+// Unverified keys are keyed by their digest, like SubjectKeyFunc does with tokens.
+func APIKeyKeyFunc(header string) KeyFunc {
+	return func(r *http.Request) rl.Key {
+		if p, ok := auth.PrincipalFrom(r.Context()); ok {
+			if id, ok := apikey.KeyIDFrom(p); ok {
+				return rl.Key("apikey:" + id)
+			}
+		}
+		return digestKey("apikey:", strings.TrimSpace(r.Header.Get(header)))
+	}
+}
+
+// SessionKeyFunc keys requests by the session ID stored in cookie.
+func SessionKeyFunc(cookie string) KeyFunc {
+	return func(r *http.Request) rl.Key {
+		c, err := r.Cookie(cookie)
+		if err != nil {
+			return ""
+		}
+		return digestKey("session:", c.Value)
+	}
+}
+
+// digestKey hashes credentials so they never end up in counter store keys.
+func digestKey(prefix, secret string) rl.Key {
+	if secret == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(secret))
+	return rl.Key(prefix + hex.EncodeToString(sum[:16]))
+}
@@ -2,10 +2,12 @@ package ratelimit
 
 import (
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"app/modules/middleware/problem"
 	rl "app/modules/ratelimit"
@@ -48,8 +50,19 @@ type (
 		AllowIfNoMatch bool
 		// Allow to next middleware if no identifier is extracted from the http.Request using KeyFn
 		AllowIfNoIdentifier bool
+		// Allow to next middleware when limits cannot be checked (counter store down);
+		// otherwise such requests get 503
+		FailOpen bool
+
+		// Degraded optionally reports the counter store as unreachable (e.g. redis.Watchdog.Degraded),
+		// so requests apply FailOpen right away instead of waiting on store timeouts.
+		// Leave it nil when the limiter has a local fallback that keeps counting.
+		Degraded func() bool
 
 		RouteInfoFn RouteInfoFunc
+
+		// Headers selects the rate limit headers written on responses, legacy when empty.
+		Headers HeaderMode
 	}
 )
 
@@ -96,7 +109,15 @@ func ParsePolicy(
 		policyMap:           make(map[Pattern]map[method]Policy, 0),
 		AllowIfNoIdentifier: cfg.AllowIfNoIdentifier,
 		AllowIfNoMatch:      cfg.AllowIfNoMatch,
+		FailOpen:            cfg.FailOpen,
 		RouteInfoFn:         routeFn,
+		Headers:             cfg.Headers,
+	}
+
+	switch rtp.Headers {
+	case "", HeaderModeLegacy, HeaderModeDraft, HeaderModeBoth:
+	default:
+		return nil, fmt.Errorf("ratelimit parse policy: unknown header mode %q", rtp.Headers)
 	}
 
 	// Default policy fallback (optional). Consider it configured only when it has
@@ -229,20 +250,21 @@ func NewRateLimitMiddleware(p *RuntimePolicy) func(http.Handler) http.Handler {
 				return
 			}
 
+			if p.Degraded != nil && p.Degraded() {
+				p.storeUnavailable(w, r, next, errCounterStoreDegraded)
+				return
+			}
+
 			result, err := px.Limiter.Allow(r.Context(), key)
 			if err != nil {
-				slog.Error("rate limit error",
-					slog.Any("error", err),
-					slog.String("url", r.URL.Path),
-				)
 				// Counter store may be down
-				problem.Write(w, problem.Internal(http.StatusText(http.StatusInternalServerError)))
+				p.storeUnavailable(w, r, next, err)
 				return
 			}
 
 			// generated code's response visitor unconditionally does w.Header().Set("X-RateLimit-Limit", fmt.Sprint(response.Headers.XRateLimitLimit)), etc.
 			// so we have to re-apply before response is committed
-			w = &rateLimitHeaderWriter{ResponseWriter: w, result: result}
+			w = &rateLimitHeaderWriter{ResponseWriter: w, result: result, mode: p.Headers}
 
 			if !result.Allowed {
 				slog.Debug("rate limited",
@@ -258,19 +280,56 @@ func NewRateLimitMiddleware(p *RuntimePolicy) func(http.Handler) http.Handler {
 	}
 }
 
-func writeRateLimitHeaders(w http.ResponseWriter, result rl.Result) {
+var legacyHeaders = []string{
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Window-Seconds",
+	"X-RateLimit-Reset-Seconds",
+}
+
+func writeRateLimitHeaders(w http.ResponseWriter, result rl.Result, mode HeaderMode) {
 	h := w.Header()
-	h.Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
-	h.Set("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
-	h.Set("X-RateLimit-Window-Seconds",
-		strconv.FormatInt(int64(result.Window.Seconds()), 10))
-	h.Set("X-RateLimit-Reset-Seconds",
-		strconv.FormatInt(int64(result.WindowResetIn.Seconds()), 10))
+	if mode != HeaderModeDraft {
+		h.Set("X-RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		h.Set("X-RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		h.Set("X-RateLimit-Window-Seconds",
+			strconv.FormatInt(int64(result.Window.Seconds()), 10))
+		h.Set("X-RateLimit-Reset-Seconds",
+			strconv.FormatInt(int64(result.WindowResetIn.Seconds()), 10))
+	} else {
+		// the generated response visitors set them unconditionally
+		for _, name := range legacyHeaders {
+			h.Del(name)
+		}
+	}
+
+	if mode == HeaderModeDraft || mode == HeaderModeBoth {
+		h.Set("RateLimit-Limit", strconv.FormatInt(result.Limit, 10))
+		h.Set("RateLimit-Remaining", strconv.FormatInt(result.Remaining, 10))
+		h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(result.WindowResetIn), 10))
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, ceilSeconds(result.Window)))
+		if !result.Allowed {
+			retry := result.RetryAfter
+			if retry <= 0 {
+				retry = result.WindowResetIn
+			}
+			h.Set("Retry-After", strconv.FormatInt(max(ceilSeconds(retry), 1), 10))
+		}
+	}
+}
+
+// ceilSeconds rounds up, so a client waiting the advertised delay is never early.
+func ceilSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
 }
 
 type rateLimitHeaderWriter struct {
 	http.ResponseWriter
 	result  rl.Result
+	mode    HeaderMode
 	ensured bool
 }
 
@@ -278,7 +337,7 @@ func (w *rateLimitHeaderWriter) ensure() {
 	if w.ensured {
 		return
 	}
-	writeRateLimitHeaders(w.ResponseWriter, w.result)
+	writeRateLimitHeaders(w.ResponseWriter, w.result, w.mode)
 	w.ensured = true
 }
 
@@ -294,11 +353,11 @@ func (w *rateLimitHeaderWriter) Write(p []byte) (int, error) {
 
 func (w *rateLimitHeaderWriter) Flush() {
 	w.ensure()
-	if f, ok := w.ResponseWriter.(http.Flusher); ok {
-		f.Flush()
-	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
 }
 
+func (w *rateLimitHeaderWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
 // TODO: temporary mechanism, realistically need something more robust (api key, jwt, etc)
 func RemoteIpKeyFunc(r *http.Request) rl.Key {
 	h := r.Header
@@ -309,3 +368,20 @@ func RemoteIpKeyFunc(r *http.Request) rl.Key {
 
 	return rl.Key(ips[len(ips)-1])
 }
+
+var errCounterStoreDegraded = errors.New("ratelimit: counter store degraded")
+
+// storeUnavailable applies the fail-open/fail-closed policy when a limit cannot be checked.
+func (p *RuntimePolicy) storeUnavailable(w http.ResponseWriter, r *http.Request, next http.Handler, err error) {
+	slog.ErrorContext(r.Context(), "rate limit error",
+		slog.Any("error", err),
+		slog.String("url", r.URL.Path),
+		slog.Bool("fail_open", p.FailOpen),
+	)
+	if p.FailOpen {
+		next.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Retry-After", "1")
+	problem.Write(w, problem.ServiceUnavailable("rate limits cannot be checked right now", problem.WithCode("ratelimit_unavailable")))
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"app/modules/middleware/problem"
+	"app/modules/readonly"
+)
+
+// ReadOnly rejects mutating requests with 503 while sw is enabled; GET, HEAD and
+// OPTIONS keep being served. Paths under an exempt prefix (the admin endpoint that
+// lifts the mode) are never rejected.
+func ReadOnly(sw *readonly.Switch, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !sw.Enabled() {
+				next.ServeHTTP(w, r)
+				return
+			}
+			for _, prefix := range exempt {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			detail := "the API is temporarily read-only, retry later"
+			if reason := sw.State().Reason; reason != "" {
+				detail += ": " + reason
+			}
+			w.Header().Set("Retry-After", "30")
+			problem.Write(w, problem.ServiceUnavailable(detail, problem.WithCode("read_only")))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"app/modules/hmac"
+	"app/modules/middleware/problem"
+)
+
+// VerifyRequestSignatures rejects requests not signed by hmac.RequestSigner.
+//
+// Missing, invalid and stale signatures get a 401 problem, and a replayed nonce a
+// 409. A 503 answers while the nonce store is unreachable, as the request could
+// then be a replay.
+//
+// It guards webhook-style endpoints whose callers share a secret rather than
+// a key pair, so it is mounted on those routes instead of globally:
+//
+//	verifier, err := hmac.NewRequestVerifier(secret, redis.NewNonceCache(redisClient, appConfig.KeyPrefix("nonce")))
+//	mux.Handle("POST /v1/webhooks/billing", middleware.VerifyRequestSignatures(verifier)(billingHandler))
+func VerifyRequestSignatures(v *hmac.RequestVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := v.Verify(r)
+			if err == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			attrs := []any{
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Any("error", err),
+			}
+			switch {
+			case errors.Is(err, hmac.ErrReplayed):
+				slog.WarnContext(r.Context(), "middleware: replayed request signature", attrs...)
+				problem.Write(w, problem.New(
+					problem.WithStatus(http.StatusConflict),
+					problem.WithTitle(http.StatusText(http.StatusConflict)),
+					problem.WithDetail("request was already received"),
+					problem.WithCode("signature_replayed"),
+				))
+			case errors.Is(err, hmac.ErrMissingSignature),
+				errors.Is(err, hmac.ErrInvalidSignature),
+				errors.Is(err, hmac.ErrStaleTimestamp):
+				slog.InfoContext(r.Context(), "middleware: request signature rejected", attrs...)
+				w.Header().Set("WWW-Authenticate", `HMAC header="`+hmac.SignatureHeader+`"`)
+				problem.Write(w, problem.Unauthorized("request signature is missing, invalid or expired",
+					problem.WithCode("invalid_signature"),
+				))
+			default:
+				slog.ErrorContext(r.Context(), "middleware: request signature check failed", attrs...)
+				problem.Write(w, problem.ServiceUnavailable("request signature cannot be checked right now",
+					problem.WithCode("signature_unavailable"),
+				))
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"app/modules/middleware/problem"
+	"app/modules/requestid"
+
+	"github.com/gofrs/uuid/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// RequestID gives every request a correlation id.
+//
+// A valid X-Request-Id sent by the client (see requestid.Valid) is adopted, so one
+// id follows a call across services; otherwise a UUIDv7 is generated. The id is
+// stored in the context (requestid.From), echoed in the X-Request-Id response
+// header and added to problem+json bodies as "requestId".
+//
+// Problem bodies that lack them also get an "instance", the request path, and a
+// "traceId": the trace of the active span, else the one propagated by the caller
+// (traceparent), else the request id. Mount it right after RoutingHygiene so
+// every later middleware logs with the id.
+func RequestID() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = newRequestID()
+				r.Header.Set(requestid.Header, id)
+			}
+			w.Header().Set(requestid.Header, id)
+
+			r = r.WithContext(requestid.With(r.Context(), id))
+			rw := &problemRequestID{ResponseWriter: w, id: id, r: r}
+			next.ServeHTTP(rw, r)
+			if rw.buffering {
+				rw.flush()
+			}
+		})
+	}
+}
+
+func newRequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		// only fails with the random source; serve the request uncorrelated rather than fail it
+		return uuid.Nil.String()
+	}
+	return id.String()
+}
+
+// problemRequestID holds back problem+json bodies to stamp the request id into them.
+type problemRequestID struct {
+	http.ResponseWriter
+	id          string
+	r           *http.Request
+	status      int
+	wroteHeader bool
+	buffering   bool
+	buf         bytes.Buffer
+}
+
+func (p *problemRequestID) WriteHeader(code int) {
+	if p.wroteHeader {
+		return
+	}
+	p.wroteHeader = true
+	p.status = code
+	if strings.HasPrefix(p.Header().Get("Content-Type"), "application/problem+json") {
+		p.buffering = true
+		return
+	}
+	p.ResponseWriter.WriteHeader(code)
+}
+
+func (p *problemRequestID) Write(b []byte) (int, error) {
+	if !p.wroteHeader {
+		p.WriteHeader(http.StatusOK)
+	}
+	if p.buffering {
+		return p.buf.Write(b)
+	}
+	return p.ResponseWriter.Write(b)
+}
+
+func (p *problemRequestID) Unwrap() http.ResponseWriter { return p.ResponseWriter }
+
+func (p *problemRequestID) flush() {
+	body := p.buf.Bytes()
+	if stamped, ok := stampProblem(body, p.id, problemTraceID(p.r), p.r.URL.Path); ok {
+		body = stamped
+	}
+	p.Header().Del("Content-Length")
+	p.ResponseWriter.WriteHeader(p.status)
+	_, _ = p.ResponseWriter.Write(body)
+}
+
+// problemTraceID returns the trace of the active span, falling back to the one
+// the caller propagated: nothing starts server spans in front of the handlers.
+func problemTraceID(r *http.Request) string {
+	if id := problem.TraceID(r.Context()); id != "" {
+		return id
+	}
+	return problem.TraceID(otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header)))
+}
+
+func stampProblem(body []byte, id, traceID, instance string) ([]byte, bool) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, false
+	}
+	doc["requestId"] = id
+	if traceID == "" {
+		traceID = id
+	}
+	if v, _ := doc["traceId"].(string); v == "" {
+		doc["traceId"] = traceID
+	}
+	if v, _ := doc["instance"].(string); v == "" && instance != "" {
+		doc["instance"] = instance
+	}
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+	return append(out, '\n'), true
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routepolicy
+
+import "time"
+
+type (
+	Config struct {
+		Routes        []Route `envPrefix:"ROUTE_"`
+		DefaultPolicy Rule    `envPrefix:"DEFAULT_"`
+	}
+
+	Route struct {
+		Pattern string `env:"PATTERN"`
+		Rules   []Rule `envPrefix:"POLICY_"`
+	}
+
+	// Rule is the policy of a single route-method. Zero values disable the
+	// corresponding behaviour (or inherit it from the default rule).
+	Rule struct {
+		Method string `env:"METHOD"`
+
+		// Timeout bounds the request context.
+		Timeout time.Duration `env:"TIMEOUT"`
+		// CacheTTL sets Cache-Control: max-age on successful GET/HEAD responses
+		// that do not set their own.
+		CacheTTL time.Duration `env:"CACHE_TTL"`
+		// Scopes the principal must hold, comma separated.
+		Scopes []string `env:"SCOPES" envSeparator:","`
+
+		Breaker BreakerRule `envPrefix:"BREAKER_"`
+		Shed    ShedRule    `envPrefix:"SHED_"`
+	}
+
+	BreakerRule struct {
+		// FailureThreshold is the number of consecutive 5xx responses that opens the breaker.
+		FailureThreshold int `env:"FAILURE_THRESHOLD"`
+		// OpenFor is how long the breaker rejects requests before letting a probe through.
+		OpenFor time.Duration `env:"OPEN_FOR"`
+	}
+
+	ShedRule struct {
+		// MaxInFlight caps concurrent requests; excess requests are rejected with 503.
+		MaxInFlight int `env:"MAX_IN_FLIGHT"`
+	}
+)
+
+// merge fills zero fields of r from def.
+func (r Rule) merge(def Rule) Rule {
+	if r.Timeout == 0 {
+		r.Timeout = def.Timeout
+	}
+	if r.CacheTTL == 0 {
+		r.CacheTTL = def.CacheTTL
+	}
+	if len(r.Scopes) == 0 {
+		r.Scopes = def.Scopes
+	}
+	if r.Breaker.FailureThreshold == 0 {
+		r.Breaker = def.Breaker
+	}
+	if r.Shed.MaxInFlight == 0 {
+		r.Shed = def.Shed
+	}
+	return r
+}
+
+func (r Rule) empty() bool {
+	return r.Timeout == 0 && r.CacheTTL == 0 && len(r.Scopes) == 0 &&
+		r.Breaker.FailureThreshold == 0 && r.Shed.MaxInFlight == 0
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package routepolicy compiles declarative per-route policies (timeout, circuit
+// breaker, load shedding, cache TTL and required scopes) from config into a single
+// middleware, mirroring how ratelimit.ParsePolicy compiles rate limits.
+//
+// Routes follow the rate limit schema: a pattern with per-method rules, plus an
+// optional default rule. Fields left zero in a route rule inherit the default:
+//
+//	ROUTE_POLICY_DEFAULT_TIMEOUT=5s
+//	ROUTE_POLICY_ROUTE_0_PATTERN=/v1/profiles
+//	ROUTE_POLICY_ROUTE_0_POLICY_0_METHOD=GET
+//	ROUTE_POLICY_ROUTE_0_POLICY_0_CACHE_TTL=30s
+//	ROUTE_POLICY_ROUTE_0_POLICY_0_SCOPES=profiles:read
+//	ROUTE_POLICY_ROUTE_0_POLICY_1_METHOD=DELETE
+//	ROUTE_POLICY_ROUTE_0_POLICY_1_BREAKER_FAILURE_THRESHOLD=5
+//	ROUTE_POLICY_ROUTE_0_POLICY_1_SHED_MAX_IN_FLIGHT=32
+package routepolicy
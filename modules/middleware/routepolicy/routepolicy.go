@@ -0,0 +1,307 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routepolicy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/modules/auth"
+	"app/modules/clock"
+	"app/modules/middleware/problem"
+	"app/modules/middleware/ratelimit"
+	"app/modules/resilience"
+)
+
+// errServerError is the outcome reported to a breaker for a 5xx response.
+var errServerError = errors.New("routepolicy: server error")
+
+type (
+	// Policy is the compiled form of a Rule.
+	Policy struct {
+		Timeout  time.Duration
+		CacheTTL time.Duration
+		Scopes   []string
+
+		breaker *resilience.Breaker
+		// buffered channel used as a counting semaphore
+		inFlight chan struct{}
+	}
+
+	// compiled policy to be injected and used at runtime
+	RuntimePolicy struct {
+		policyMap map[ratelimit.Pattern]map[string]*Policy
+
+		// default policy, applied to routes without an explicit rule for the method
+		defaultPolicy *Policy
+
+		RouteInfoFn ratelimit.RouteInfoFunc
+	}
+
+	// Option configures ParsePolicy.
+	Option func(*parseOptions)
+
+	parseOptions struct {
+		clock clock.Clock
+	}
+)
+
+// WithClock overrides the time source of the circuit breakers (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(o *parseOptions) {
+		if c != nil {
+			o.clock = c
+		}
+	}
+}
+
+// ParsePolicy compiles cfg into a RuntimePolicy.
+//
+// Every route-method gets its own breaker and in-flight limit, so an unhealthy
+// endpoint does not trip the others.
+func ParsePolicy(cfg *Config, routeFn ratelimit.RouteInfoFunc, opts ...Option) (*RuntimePolicy, error) {
+	o := parseOptions{clock: clock.RealClock{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	rtp := &RuntimePolicy{
+		policyMap:   make(map[ratelimit.Pattern]map[string]*Policy),
+		RouteInfoFn: routeFn,
+	}
+
+	if cfg.DefaultPolicy.Method != "" {
+		return nil, errors.New("routepolicy parse policy: default policy must not set a method")
+	}
+	if !cfg.DefaultPolicy.empty() {
+		p, err := compile("default", cfg.DefaultPolicy, o)
+		if err != nil {
+			return nil, fmt.Errorf("routepolicy parse policy: default: %w", err)
+		}
+		rtp.defaultPolicy = p
+	}
+
+	for _, r := range cfg.Routes {
+		pat := ratelimit.Pattern(r.Pattern)
+		if _, ok := rtp.policyMap[pat]; !ok {
+			rtp.policyMap[pat] = make(map[string]*Policy)
+		}
+
+		for _, rule := range r.Rules {
+			m := strings.ToUpper(rule.Method)
+			if m == "" {
+				return nil, fmt.Errorf("routepolicy parse policy: %q: method must not be empty", r.Pattern)
+			}
+			if _, ok := rtp.policyMap[pat][m]; ok {
+				return nil, errors.New("routepolicy parse policy: duplicate method config on same pattern")
+			}
+
+			p, err := compile(m+" "+r.Pattern, rule.merge(cfg.DefaultPolicy), o)
+			if err != nil {
+				return nil, fmt.Errorf("routepolicy parse policy: %s %q: %w", m, r.Pattern, err)
+			}
+			rtp.policyMap[pat][m] = p
+		}
+	}
+	return rtp, nil
+}
+
+// compile builds the policy of rule; name identifies its breaker in logs.
+func compile(name string, rule Rule, o parseOptions) (*Policy, error) {
+	switch {
+	case rule.Timeout < 0, rule.CacheTTL < 0, rule.Breaker.OpenFor < 0:
+		return nil, errors.New("durations must not be negative")
+	case rule.Breaker.FailureThreshold < 0:
+		return nil, errors.New("breaker failure threshold must not be negative")
+	case rule.Shed.MaxInFlight < 0:
+		return nil, errors.New("max in-flight must not be negative")
+	}
+
+	p := &Policy{
+		Timeout:  rule.Timeout,
+		CacheTTL: rule.CacheTTL,
+	}
+	for _, s := range rule.Scopes {
+		if s = strings.TrimSpace(s); s != "" {
+			p.Scopes = append(p.Scopes, s)
+		}
+	}
+	if rule.Breaker.FailureThreshold > 0 {
+		openFor := rule.Breaker.OpenFor
+		if openFor == 0 {
+			openFor = 30 * time.Second
+		}
+		p.breaker = resilience.New("routepolicy "+name,
+			resilience.WithFailureThreshold(rule.Breaker.FailureThreshold),
+			resilience.WithOpenTimeout(openFor),
+			resilience.WithClock(o.clock),
+		)
+	}
+	if rule.Shed.MaxInFlight > 0 {
+		p.inFlight = make(chan struct{}, rule.Shed.MaxInFlight)
+	}
+	return p, nil
+}
+
+func (p *RuntimePolicy) findPolicy(routeInfo ratelimit.RouteInfo) (*Policy, bool) {
+	if pm, ok := p.policyMap[routeInfo.ID]; ok {
+		if px, ok := pm[strings.ToUpper(routeInfo.Method)]; ok {
+			return px, true
+		}
+	}
+	if p.defaultPolicy != nil {
+		return p.defaultPolicy, true
+	}
+	return nil, false
+}
+
+// NewRoutePolicyMiddleware enforces the compiled policies. Checks run cheapest first:
+// scopes, load shedding, circuit breaker; then the handler runs under the timeout.
+func NewRoutePolicyMiddleware(p *RuntimePolicy) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			routeInfo := p.RouteInfoFn(r)
+			px, ok := p.findPolicy(routeInfo)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if len(px.Scopes) > 0 {
+				principal, ok := auth.PrincipalFrom(r.Context())
+				if !ok {
+					w.Header().Set("WWW-Authenticate", "Bearer")
+					problem.Write(w, problem.Unauthorized("authentication required", problem.WithCode("unauthenticated")))
+					return
+				}
+				if !hasAll(principal.Granted(), px.Scopes) {
+					problem.Write(w, problem.Forbidden("missing required scope",
+						problem.WithCode("insufficient_scope"),
+						problem.WithExtension("requiredScopes", px.Scopes),
+					))
+					return
+				}
+			}
+
+			if px.inFlight != nil {
+				select {
+				case px.inFlight <- struct{}{}:
+					defer func() { <-px.inFlight }()
+				default:
+					slog.Warn("request shed",
+						slog.String("middleware", "route_policy"),
+						slog.String("url", r.URL.Path),
+						slog.Any("route_info", routeInfo),
+					)
+					w.Header().Set("Retry-After", "1")
+					problem.Write(w, problem.ServiceUnavailable("server is at capacity", problem.WithCode("overloaded")))
+					return
+				}
+			}
+
+			// the breaker sees the context of the request without the timeout, so a
+			// handler timing out counts as a failure and a client going away does not
+			ctx := r.Context()
+			if px.Timeout > 0 {
+				tctx, cancel := context.WithTimeout(ctx, px.Timeout)
+				defer cancel()
+				r = r.WithContext(tctx)
+			}
+
+			pw := &policyWriter{ResponseWriter: w, status: http.StatusOK}
+			if px.CacheTTL > 0 && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+				pw.maxAge = "max-age=" + strconv.FormatInt(int64(px.CacheTTL/time.Second), 10)
+			}
+
+			if px.breaker != nil {
+				var panicked any
+				err := px.breaker.Do(ctx, func(context.Context) (err error) {
+					// a panic counts as a failure, it is re-raised for the recovery middleware
+					defer func() {
+						if panicked = recover(); panicked != nil {
+							err = errServerError
+						}
+					}()
+					next.ServeHTTP(pw, r)
+					if pw.status >= http.StatusInternalServerError {
+						return errServerError
+					}
+					return nil
+				})
+				if panicked != nil {
+					panic(panicked)
+				}
+				if errors.Is(err, resilience.ErrOpen) {
+					w.Header().Set("Retry-After", strconv.Itoa(int(px.breaker.RetryAfter()/time.Second)+1))
+					problem.Write(w, problem.ServiceUnavailable("endpoint temporarily unavailable", problem.WithCode("circuit_open")))
+				}
+				return
+			}
+			next.ServeHTTP(pw, r)
+		})
+	}
+}
+
+// policyWriter records the status for the breaker and applies Cache-Control
+// to successful responses before the header is committed.
+type policyWriter struct {
+	http.ResponseWriter
+	status      int
+	maxAge      string
+	wroteHeader bool
+}
+
+func (w *policyWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+		if w.maxAge != "" && code >= 200 && code < 300 && w.Header().Get("Cache-Control") == "" {
+			w.Header().Set("Cache-Control", w.maxAge)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *policyWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *policyWriter) Flush() {
+	// through the controller, wrappers below that only implement Unwrap still flush
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *policyWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+func hasAll(held, want []string) bool {
+	for _, s := range want {
+		if !slices.Contains(held, s) {
+			return false
+		}
+	}
+	return true
+}
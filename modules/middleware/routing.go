@@ -0,0 +1,150 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"app/modules/middleware/problem"
+)
+
+// MethodOverrideHeader carries the intended method of a tunnelled POST request.
+const MethodOverrideHeader = "X-HTTP-Method-Override"
+
+// TrailingSlashMode selects how paths ending in "/" are handled.
+type TrailingSlashMode string
+
+const (
+	// TrailingSlashOff leaves paths untouched.
+	TrailingSlashOff TrailingSlashMode = "off"
+	// TrailingSlashStrip rewrites "/v1/profiles/" to "/v1/profiles" before routing.
+	TrailingSlashStrip TrailingSlashMode = "strip"
+	// TrailingSlashRedirect answers 308 with the canonical path, so clients learn it.
+	TrailingSlashRedirect TrailingSlashMode = "redirect"
+)
+
+// RoutingConfig configures RoutingHygiene.
+type RoutingConfig struct {
+	TrailingSlash TrailingSlashMode `env:"TRAILING_SLASH" envDefault:"strip"`
+
+	// MethodOverride lets POST requests declare their real method in
+	// X-HTTP-Method-Override, for clients behind proxies that only pass GET/POST.
+	MethodOverride bool `env:"METHOD_OVERRIDE" envDefault:"false"`
+	// OverrideMethods lists the methods a POST may be turned into.
+	OverrideMethods []string `env:"OVERRIDE_METHODS" envSeparator:"," envDefault:"PUT,PATCH,DELETE"`
+}
+
+// RoutingHygiene canonicalizes the request line before anything looks at it.
+//
+// It must be the outermost global middleware: rate limiting, route policies and
+// telemetry all derive their route from the method and path, so a request sent as
+// "POST /v1/profiles/1/" with an override to PATCH is matched exactly like
+// "PATCH /v1/profiles/1".
+//
+// An override header on a non-POST request, or naming a method outside
+// OverrideMethods, is rejected with 400 instead of being silently ignored.
+func RoutingHygiene(cfg RoutingConfig) (func(http.Handler) http.Handler, error) {
+	switch cfg.TrailingSlash {
+	case "":
+		cfg.TrailingSlash = TrailingSlashOff
+	case TrailingSlashOff, TrailingSlashStrip, TrailingSlashRedirect:
+	default:
+		return nil, fmt.Errorf("middleware: unknown trailing slash mode %q", cfg.TrailingSlash)
+	}
+
+	allowed := make([]string, 0, len(cfg.OverrideMethods))
+	for _, m := range cfg.OverrideMethods {
+		m = strings.ToUpper(strings.TrimSpace(m))
+		if m == "" {
+			continue
+		}
+		if m == http.MethodPost || m == http.MethodConnect || m == http.MethodTrace {
+			return nil, fmt.Errorf("middleware: method %q cannot be an override target", m)
+		}
+		allowed = append(allowed, m)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.MethodOverride {
+				if override := r.Header.Get(MethodOverrideHeader); override != "" {
+					method := strings.ToUpper(strings.TrimSpace(override))
+					if r.Method != http.MethodPost || !slices.Contains(allowed, method) {
+						slog.DebugContext(r.Context(), "middleware: method override rejected",
+							slog.String("method", r.Method),
+							slog.String("override", override),
+						)
+						problem.Write(w, problem.BadRequest("method override is not allowed",
+							problem.WithCode("invalid_method_override"),
+							problem.WithInvalidParam(MethodOverrideHeader, "must be one of "+strings.Join(allowed, ", ")+" on a POST request"),
+						))
+						return
+					}
+					r = r.Clone(r.Context())
+					r.Method = method
+					r.Header.Del(MethodOverrideHeader)
+				}
+			}
+
+			if cfg.TrailingSlash != TrailingSlashOff {
+				if path, ok := trimTrailingSlash(r.URL.Path); ok {
+					if cfg.TrailingSlash == TrailingSlashRedirect {
+						target := redirectTarget(path)
+						if r.URL.RawQuery != "" {
+							target += "?" + r.URL.RawQuery
+						}
+						http.Redirect(w, r, target, http.StatusPermanentRedirect)
+						return
+					}
+					u := *r.URL
+					u.Path = path
+					if u.RawPath != "" {
+						u.RawPath, _ = trimTrailingSlash(u.RawPath)
+					}
+					r = r.WithContext(r.Context())
+					r.URL = &u
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// redirectTarget is the Location of a redirect to path. The path comes straight
+// from the request line, not yet cleaned by the mux: leading slashes are collapsed
+// and it is escaped, so that "//evil.com/" or "/\evil.com/" cannot turn into a
+// scheme-relative URL pointing to another host.
+func redirectTarget(path string) string {
+	u := url.URL{Path: "/" + strings.TrimLeft(path, "/")}
+	return u.EscapedPath()
+}
+
+// trimTrailingSlash strips every trailing "/" but keeps the root path.
+func trimTrailingSlash(path string) (string, bool) {
+	if len(path) <= 1 || !strings.HasSuffix(path, "/") {
+		return path, false
+	}
+	trimmed := strings.TrimRight(path, "/")
+	if trimmed == "" {
+		trimmed = "/"
+	}
+	return trimmed, true
+}
@@ -0,0 +1,55 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRoutingHygieneRedirect(t *testing.T) {
+	mw, err := RoutingHygiene(RoutingConfig{TrailingSlash: TrailingSlashRedirect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("handler reached for %s", r.URL.Path)
+	}))
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{"trailing slash", "/v1/profiles/", "/v1/profiles"},
+		{"query kept", "/v1/profiles/?limit=10", "/v1/profiles?limit=10"},
+		{"scheme-relative", "//evil.com/", "/evil.com"},
+		{"many leading slashes", "///evil.com//", "/evil.com"},
+		{"backslash", "/%5Cevil.com/", "/%5Cevil.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://api.test"+tt.target, nil))
+			if rec.Code != http.StatusPermanentRedirect {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusPermanentRedirect)
+			}
+			if got := rec.Header().Get("Location"); got != tt.want {
+				t.Fatalf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
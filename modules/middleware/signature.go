@@ -0,0 +1,90 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"app/modules/auth"
+	"app/modules/httpsig"
+	"app/modules/middleware/problem"
+)
+
+// VerifySignatures authenticates service-to-service requests signed with httpsig.
+//
+// A valid signature stores an auth.Principal whose Subject is the key id and whose
+// Scopes are the scopes configured for that key, so scope enforcement applies to
+// services like to any other caller; mount it before the authz middleware.
+//
+// Requests without a Signature header pass through untouched unless required is
+// set; a present but invalid signature is always rejected with 401, and a replayed
+// one with 409.
+func VerifySignatures(v *httpsig.Verifier, required bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, err := v.Verify(r)
+			if errors.Is(err, httpsig.ErrMissingSignature) && !required {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if err != nil {
+				attrs := []any{
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Any("error", err),
+				}
+				switch {
+				case errors.Is(err, httpsig.ErrReplayed):
+					slog.WarnContext(r.Context(), "middleware: replayed request signature", attrs...)
+					problem.Write(w, problem.New(
+						problem.WithStatus(http.StatusConflict),
+						problem.WithTitle(http.StatusText(http.StatusConflict)),
+						problem.WithDetail("request was already received"),
+						problem.WithCode("signature_replayed"),
+					))
+				case errors.Is(err, httpsig.ErrMissingSignature),
+					errors.Is(err, httpsig.ErrMalformed),
+					errors.Is(err, httpsig.ErrUnknownKey),
+					errors.Is(err, httpsig.ErrInvalidSignature),
+					errors.Is(err, httpsig.ErrExpired),
+					errors.Is(err, httpsig.ErrDigestMismatch):
+					slog.InfoContext(r.Context(), "middleware: request signature rejected", attrs...)
+					w.Header().Set("WWW-Authenticate", `Signature alg="`+string(httpsig.Ed25519)+` `+string(httpsig.HMACSHA256)+`"`)
+					problem.Write(w, problem.Unauthorized("request signature is missing or invalid",
+						problem.WithCode("invalid_signature"),
+					))
+				default:
+					slog.ErrorContext(r.Context(), "middleware: request signature check failed", attrs...)
+					problem.Write(w, problem.ServiceUnavailable("request signature cannot be checked right now",
+						problem.WithCode("signature_unavailable"),
+					))
+				}
+				return
+			}
+
+			ctx := auth.WithPrincipal(r.Context(), &auth.Principal{
+				Subject: key.ID,
+				Scopes:  key.Scopes,
+				Claims: map[string]any{
+					"amr": "http-signature",
+					"alg": string(key.Algorithm),
+				},
+			})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -17,6 +17,7 @@ package middleware
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"app/modules/telemetry"
@@ -57,6 +58,31 @@ func (r *responseRecorder) Write(b []byte) (int, error) {
 	return n, err
 }
 
+// Flush implements http.Flusher, so streamed responses are not held back by the recorder.
+func (r *responseRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection (write deadlines, ...).
+func (r *responseRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// protocolOf names the protocol of r as in ALPN, telling h2c from HTTP/2 over TLS.
+func protocolOf(r *http.Request) string {
+	switch r.ProtoMajor {
+	case 3:
+		return "h3"
+	case 2:
+		if r.TLS == nil {
+			return "h2c"
+		}
+		return "h2"
+	}
+	return strings.ToLower(r.Proto)
+}
+
 // Telemetry creates a middleware that records metrics for ALL HTTP requests.
 // This middleware wraps the ResponseWriter to capture status codes and response sizes
 // from any layer (validation middleware, handlers, error handlers, etc.).
@@ -83,6 +109,7 @@ func Telemetry(metrics *telemetry.HTTPMetrics) func(http.Handler) http.Handler {
 				r.Context(),
 				r.Method,
 				r.URL.Path,
+				protocolOf(r),
 				fmt.Sprintf("%d", recorder.statusCode),
 				durationMs,
 				recorder.bytesWritten,
@@ -19,6 +19,7 @@ import (
 	"io/fs"
 	"net/http"
 	"sync"
+	"sync/atomic"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
@@ -31,10 +32,27 @@ type ValidationErrorHandler func(ctx context.Context, err error, w http.Response
 // SpecLoadErrorHandler handles errors that occur when loading the OpenAPI spec.
 type SpecLoadErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
 
+// ValidationOption configures OpenAPIValidation.
+type ValidationOption func(*openapi3filter.Options)
+
+// WithAuthenticationFunc checks the security requirements of the spec with fn;
+// a failed requirement reaches the error handler as a
+// *openapi3filter.SecurityRequirementsError with status 401.
+func WithAuthenticationFunc(fn openapi3filter.AuthenticationFunc) ValidationOption {
+	return func(o *openapi3filter.Options) {
+		if fn != nil {
+			o.AuthenticationFunc = fn
+		}
+	}
+}
+
 // specCache holds cached OpenAPI specs keyed by file path.
 var (
 	specCacheMu sync.RWMutex
 	specCache   = make(map[specCacheKey]*specCacheEntry)
+
+	// bumped by ResetSpecCache, see SpecGeneration
+	specGeneration atomic.Uint64
 )
 
 type specCacheKey struct {
@@ -83,6 +101,30 @@ func loadSpec(fsys fs.FS, specPath string) (*openapi3.T, error) {
 	return doc, err
 }
 
+// ResetSpecCache drops every cached spec so the next load reads its file again,
+// e.g. after a hotfix to a spec served from an external FS. Middlewares built by
+// OpenAPIValidation rebuild on their next request. It returns how many specs were dropped.
+func ResetSpecCache() int {
+	specCacheMu.Lock()
+	defer specCacheMu.Unlock()
+	n := len(specCache)
+	clear(specCache)
+	specGeneration.Add(1)
+	return n
+}
+
+// SpecGeneration counts ResetSpecCache calls. State derived from a spec loaded at
+// one generation is stale once the generation changed.
+func SpecGeneration() uint64 {
+	return specGeneration.Load()
+}
+
+// LoadSpec loads (and caches) the OpenAPI document at specPath, the same way the
+// validation middleware does, for other spec-driven middlewares.
+func LoadSpec(fsys fs.FS, specPath string) (*openapi3.T, error) {
+	return loadSpec(fsys, specPath)
+}
+
 // OpenAPIValidation creates a middleware that validates requests against an OpenAPI spec.
 // The errorHandler is called when validation fails.
 // The loadErrorHandler is called when the spec fails to load.
@@ -92,18 +134,15 @@ func OpenAPIValidation(
 	specPath string,
 	errorHandler ValidationErrorHandler,
 	loadErrorHandler SpecLoadErrorHandler,
+	validationOpts ...ValidationOption,
 ) func(http.Handler) http.Handler {
-	spec, err := loadSpec(specFS, specPath)
-	if err != nil {
-		return func(next http.Handler) http.Handler {
-			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				loadErrorHandler(w, r, err)
-			})
-		}
-	}
-
 	opts := &nethttpmiddleware.Options{
-		Options:               openapi3filter.Options{MultiError: true},
+		Options: openapi3filter.Options{
+			MultiError: true,
+			// unless WithAuthenticationFunc is given, security requirements are left to
+			// authz.SpecScopes against the request Principal
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
 		DoNotValidateServers:  true,
 		SilenceServersWarning: true,
 		ErrorHandlerWithOpts: func(ctx context.Context, err error, w http.ResponseWriter, r *http.Request, eopts nethttpmiddleware.ErrorHandlerOpts) {
@@ -118,6 +157,46 @@ func OpenAPIValidation(
 			errorHandler(ctx, err, w, r, status)
 		},
 	}
+	for _, opt := range validationOpts {
+		if opt != nil {
+			opt(&opts.Options)
+		}
+	}
+
+	build := func(next http.Handler) http.Handler {
+		spec, err := loadSpec(specFS, specPath)
+		if err != nil {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				loadErrorHandler(w, r, err)
+			})
+		}
+		return nethttpmiddleware.OapiRequestValidatorWithOptions(spec, opts)(next)
+	}
 
-	return nethttpmiddleware.OapiRequestValidatorWithOptions(spec, opts)
+	return func(next http.Handler) http.Handler {
+		return rebuildOnSpecReset(func() http.Handler { return build(next) })
+	}
+}
+
+// rebuildOnSpecReset serves the handler built from the current specs and builds it
+// again on the first request after ResetSpecCache.
+func rebuildOnSpecReset(build func() http.Handler) http.Handler {
+	type built struct {
+		generation uint64
+		handler    http.Handler
+	}
+	var current atomic.Pointer[built]
+	current.Store(&built{generation: specGeneration.Load(), handler: build()})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := current.Load()
+		if gen := specGeneration.Load(); b.generation != gen {
+			fresh := &built{generation: gen, handler: build()}
+			if !current.CompareAndSwap(b, fresh) {
+				fresh = current.Load()
+			}
+			b = fresh
+		}
+		b.handler.ServeHTTP(w, r)
+	})
 }
@@ -0,0 +1,31 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oapi holds the OpenAPI specs the servers are generated from.
+//
+// The specs are embedded so tools (load generators, smoke tests, ...) can
+// work from the exact contract the binary was built with.
+package oapi
+
+import "embed"
+
+// Specs contains every top-level spec, e.g. "openapi-profile.yaml".
+//
+//go:embed *.yaml
+var Specs embed.FS
+
+const (
+	ProfileSpec = "openapi-profile.yaml"
+	PaymentSpec = "openapi-payment.yaml"
+)
@@ -0,0 +1,81 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "app/modules/pagination"
+
+// DefaultMaxPageSize is the largest page served when Config leaves it unset.
+const DefaultMaxPageSize = 100
+
+// Config bounds the pages list endpoints serve.
+type Config struct {
+	// MaxPageSize caps pageSize and limit. Larger requests are served MaxPageSize
+	// items and flagged as clamped instead of being rejected.
+	MaxPageSize int `env:"MAX_PAGE_SIZE" envDefault:"100"`
+}
+
+// Clamp caps the page size of p at maxSize, DefaultMaxPageSize when it is not
+// positive, and marks the params it lowered as Clamped. Every clamp is counted
+// by mode in app_pagination_clamped_total.
+func Clamp(p Params, maxSize int) Params {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPageSize
+	}
+	switch p := p.(type) {
+	case OffsetParams:
+		if p.PageSize > maxSize {
+			p.PageSize, p.Clamped = maxSize, true
+			recordClamped("offset")
+		}
+		return p
+	case CursorParams:
+		if p.Limit > maxSize {
+			p.Limit, p.Clamped = maxSize, true
+			recordClamped("cursor")
+		}
+		return p
+	}
+	return p
+}
+
+// clampedRequests is created lazily so the global MeterProvider set up by
+// telemetry is in place before the first use.
+var clampedRequests = sync.OnceValue(func() metric.Int64Counter {
+	c, err := otel.Meter(instrumentationName).Int64Counter("app_pagination_clamped_total",
+		metric.WithDescription("List requests whose page size was lowered to the maximum, by pagination mode"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		slog.Warn("pagination: clamped request counter not created", slog.Any("error", err))
+		return nil
+	}
+	return c
+})
+
+func recordClamped(mode string) {
+	if c := clampedRequests(); c != nil {
+		c.Add(context.Background(), 1, metric.WithAttributes(attribute.String("mode", mode)))
+	}
+}
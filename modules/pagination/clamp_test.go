@@ -0,0 +1,40 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import "testing"
+
+func TestClamp(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      Params
+		maxSize int
+		want    Params
+	}{
+		{"offset within", OffsetParams{Page: 2, PageSize: 50}, 100, OffsetParams{Page: 2, PageSize: 50}},
+		{"offset at max", OffsetParams{PageSize: 100}, 100, OffsetParams{PageSize: 100}},
+		{"offset above", OffsetParams{Page: 1, PageSize: 150}, 100, OffsetParams{Page: 1, PageSize: 100, Clamped: true}},
+		{"cursor above", CursorParams{Limit: 200, Direction: After, Cursor: "c"}, 50, CursorParams{Limit: 50, Direction: After, Cursor: "c", Clamped: true}},
+		{"cursor within", CursorParams{Limit: 10, Direction: First}, 50, CursorParams{Limit: 10, Direction: First}},
+		{"default max", CursorParams{Limit: DefaultMaxPageSize + 1}, 0, CursorParams{Limit: DefaultMaxPageSize, Clamped: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Clamp(tt.in, tt.maxSize); got != tt.want {
+				t.Fatalf("Clamp(%+v, %d) = %+v, want %+v", tt.in, tt.maxSize, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pagination parses list endpoint parameters into either offset or cursor
+// pagination.
+//
+// List endpoints accept two mutually exclusive parameter sets:
+//
+//	page + pageSize             offset pagination
+//	limit [+ after | + before]  cursor pagination
+//
+// ParseParams checks that exactly one set is supplied and complete, and returns a
+// sealed Params union so handlers switch on the concrete type:
+//
+//	params, err := pagination.ParseParams(pagination.Query{Page: req.Page, ...})
+//	if err != nil {
+//		return badRequest(pagination.Problem(err))
+//	}
+//	switch p := params.(type) {
+//	case pagination.OffsetParams:
+//	case pagination.CursorParams:
+//	}
+//
+// Clamp then caps the page size at Config.MaxPageSize; the handler reports
+// Clamped in the response meta so clients can tell a short page from a capped one.
+//
+// CursorLinks and OffsetLinks build the next/prev references of the page that was
+// served; Links.Header formats them as the Link response header. A client that
+// follows them (or nextCursor) until they run out has seen the whole list, see
+// app/modules/api/profileapi/client for a Go iterator doing exactly that.
+package pagination
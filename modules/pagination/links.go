@@ -0,0 +1,137 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Links holds the next and prev page references of a list response.
+//
+// They are query-only relative references ("?limit=20&after=..."), which resolve
+// against the request URL, so handlers do not need to know the path or API version
+// they are mounted under. An empty field means there is no such page.
+type Links struct {
+	Next string
+	Prev string
+}
+
+// CursorLinks builds the links of a cursor page from the cursors minted for it.
+func CursorLinks(limit int, next, prev string) Links {
+	var l Links
+	if next != "" {
+		l.Next = cursorRef(limit, "after", next)
+	}
+	if prev != "" {
+		l.Prev = cursorRef(limit, "before", prev)
+	}
+	return l
+}
+
+// OffsetLinks builds the links of a 0-based offset page.
+func OffsetLinks(page, pageSize, totalPages int) Links {
+	var l Links
+	if page+1 < totalPages {
+		l.Next = offsetRef(page+1, pageSize)
+	}
+	if page > 0 {
+		// a page past the end links back to the last one
+		l.Prev = offsetRef(min(page-1, max(totalPages-1, 0)), pageSize)
+	}
+	return l
+}
+
+// Preserve carries the params of q, typically the filters and sort of the list,
+// over to both links so following them stays within the same list. Pagination
+// params already set on a link win over those of q.
+func (l Links) Preserve(q url.Values) Links {
+	if len(q) == 0 {
+		return l
+	}
+	l.Next = preserveRef(l.Next, q)
+	l.Prev = preserveRef(l.Prev, q)
+	return l
+}
+
+// Header formats l as an RFC 8288 Link header value, empty when there are no links.
+func (l Links) Header() string {
+	var parts []string
+	if l.Next != "" {
+		parts = append(parts, "<"+l.Next+`>; rel="next"`)
+	}
+	if l.Prev != "" {
+		parts = append(parts, "<"+l.Prev+`>; rel="prev"`)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseLinkHeader returns the target of each rel in an RFC 8288 Link header value.
+// Entries without a rel are skipped; for a repeated rel the first one wins.
+func ParseLinkHeader(h string) map[string]string {
+	out := make(map[string]string)
+	for _, entry := range strings.Split(h, ",") {
+		entry = strings.TrimSpace(entry)
+		end := strings.IndexByte(entry, '>')
+		if !strings.HasPrefix(entry, "<") || end < 0 {
+			continue
+		}
+		target := entry[1:end]
+		for _, param := range strings.Split(entry[end+1:], ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(key, "rel") {
+				continue
+			}
+			// rel may hold several space separated relation types
+			for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+				if _, seen := out[rel]; !seen {
+					out[rel] = target
+				}
+			}
+		}
+	}
+	return out
+}
+
+func cursorRef(limit int, param, cursor string) string {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set(param, cursor)
+	return "?" + q.Encode()
+}
+
+func offsetRef(page, pageSize int) string {
+	q := url.Values{}
+	q.Set("page", strconv.Itoa(page))
+	q.Set("pageSize", strconv.Itoa(pageSize))
+	return "?" + q.Encode()
+}
+
+func preserveRef(ref string, q url.Values) string {
+	if ref == "" {
+		return ""
+	}
+	v, err := url.ParseQuery(strings.TrimPrefix(ref, "?"))
+	if err != nil {
+		return ref
+	}
+	for name, values := range q {
+		if _, ok := v[name]; !ok {
+			v[name] = values
+		}
+	}
+	return "?" + v.Encode()
+}
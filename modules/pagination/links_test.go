@@ -0,0 +1,90 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestOffsetLinks(t *testing.T) {
+	cases := []struct {
+		name                   string
+		page, size, totalPages int
+		want                   Links
+	}{
+		{"only page", 0, 10, 1, Links{}},
+		{"first of three", 0, 10, 3, Links{Next: "?page=1&pageSize=10"}},
+		{"middle", 1, 10, 3, Links{Next: "?page=2&pageSize=10", Prev: "?page=0&pageSize=10"}},
+		{"last", 2, 10, 3, Links{Prev: "?page=1&pageSize=10"}},
+		{"past the end", 7, 10, 3, Links{Prev: "?page=2&pageSize=10"}},
+		{"empty collection", 0, 10, 0, Links{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := OffsetLinks(tc.page, tc.size, tc.totalPages); got != tc.want {
+				t.Fatalf("OffsetLinks = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+// The Link header produced for a page must parse back to the same targets,
+// cursors being escaped on the way.
+func TestLinkHeader_RoundTrip(t *testing.T) {
+	l := CursorLinks(20, "n+x/=", "p")
+	h := l.Header()
+	want := `<?after=n%2Bx%2F%3D&limit=20>; rel="next", <?before=p&limit=20>; rel="prev"`
+	if h != want {
+		t.Fatalf("Header = %q, want %q", h, want)
+	}
+
+	got := ParseLinkHeader(h)
+	if !reflect.DeepEqual(got, map[string]string{"next": l.Next, "prev": l.Prev}) {
+		t.Fatalf("ParseLinkHeader = %#v", got)
+	}
+
+	if h := (Links{}).Header(); h != "" {
+		t.Fatalf("empty Links header = %q", h)
+	}
+}
+
+func TestLinks_Preserve(t *testing.T) {
+	q := url.Values{"sort": {"-name"}, "limit": {"99"}}
+	got := OffsetLinks(1, 10, 3).Preserve(q)
+	want := Links{Next: "?limit=99&page=2&pageSize=10&sort=-name", Prev: "?limit=99&page=0&pageSize=10&sort=-name"}
+	if got != want {
+		t.Fatalf("Preserve = %#v, want %#v", got, want)
+	}
+
+	// the link's own pagination params win
+	got = CursorLinks(20, "n", "").Preserve(q)
+	if want := (Links{Next: "?after=n&limit=20&sort=-name"}); got != want {
+		t.Fatalf("Preserve = %#v, want %#v", got, want)
+	}
+
+	if got := (Links{}).Preserve(q); got != (Links{}) {
+		t.Fatalf("Preserve of no links = %#v", got)
+	}
+}
+
+func TestParseLinkHeader(t *testing.T) {
+	got := ParseLinkHeader(`<https://a/x?page=2>; rel="next last", <bad; rel=prev, <https://a/y>; title="no rel", <https://a/z>;REL=next`)
+	want := map[string]string{"next": "https://a/x?page=2", "last": "https://a/x?page=2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseLinkHeader = %#v, want %#v", got, want)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"errors"
+	"strings"
+
+	"app/modules/middleware/problem"
+)
+
+// ErrInvalidParams matches every error returned by ParseParams.
+var ErrInvalidParams = errors.New("pagination: invalid parameters")
+
+// Detail is the problem detail used for every pagination error.
+const Detail = "Provide either page+pageSize or cursor+limit (complete pair)"
+
+// Direction tells where a cursor page starts.
+type Direction string
+
+const (
+	// First is the initial cursor page, no cursor supplied.
+	First  Direction = "first"
+	After  Direction = "after"
+	Before Direction = "before"
+)
+
+type (
+	// Params is either OffsetParams or CursorParams.
+	Params interface {
+		isParams()
+	}
+
+	OffsetParams struct {
+		// Page is 0-based.
+		Page     int
+		PageSize int
+		// Clamped tells that Clamp lowered PageSize.
+		Clamped bool
+	}
+
+	CursorParams struct {
+		Limit     int
+		Direction Direction
+		// Cursor is the opaque token, empty for the First page.
+		Cursor string
+		// Clamped tells that Clamp lowered Limit.
+		Clamped bool
+	}
+
+	// Query holds the raw optional parameters as decoded by the transport layer.
+	Query struct {
+		Page     *int
+		PageSize *int
+		Limit    *int
+		After    *string
+		Before   *string
+	}
+
+	// Error describes why a Query was rejected, one InvalidParam per offending parameter.
+	Error struct {
+		InvalidParams []problem.InvalidParam
+	}
+)
+
+func (OffsetParams) isParams() {}
+func (CursorParams) isParams() {}
+
+var (
+	_ Params = OffsetParams{}
+	_ Params = CursorParams{}
+)
+
+func (e *Error) Error() string {
+	names := make([]string, 0, len(e.InvalidParams))
+	for _, p := range e.InvalidParams {
+		names = append(names, p.Name+": "+p.Reason)
+	}
+	return "pagination: invalid parameters: " + strings.Join(names, "; ")
+}
+
+func (e *Error) Is(target error) bool {
+	return target == ErrInvalidParams
+}
+
+// ParseParams resolves q into exactly one pagination mode.
+//
+// It rejects a query that mixes both sets, supplies neither, or leaves the chosen
+// set incomplete (page without pageSize, cursors without limit, after with before).
+// Range checks mirror the OpenAPI bounds so callers without request validation
+// still get typed errors.
+func ParseParams(q Query) (Params, error) {
+	offsetProvided := q.Page != nil || q.PageSize != nil
+	cursorProvided := q.Limit != nil || q.After != nil || q.Before != nil
+
+	e := &Error{}
+	switch {
+	case offsetProvided && cursorProvided:
+		for _, name := range q.provided() {
+			e.add(name, "page/pageSize cannot be combined with limit/after/before")
+		}
+	case !offsetProvided && !cursorProvided:
+		e.add("page", "page+pageSize or limit is required")
+		e.add("limit", "page+pageSize or limit is required")
+	case offsetProvided:
+		if q.Page == nil {
+			e.add("page", "required with pageSize")
+		} else if *q.Page < 0 {
+			e.add("page", "must be >= 0")
+		}
+		if q.PageSize == nil {
+			e.add("pageSize", "required with page")
+		} else if *q.PageSize < 1 {
+			e.add("pageSize", "must be >= 1")
+		}
+		if len(e.InvalidParams) == 0 {
+			return OffsetParams{Page: *q.Page, PageSize: *q.PageSize}, nil
+		}
+	default:
+		if q.Limit == nil {
+			e.add("limit", "required with after/before")
+		} else if *q.Limit < 1 {
+			e.add("limit", "must be >= 1")
+		}
+		if q.After != nil && q.Before != nil {
+			e.add("before", "cannot be combined with after")
+		}
+		if q.After != nil && *q.After == "" {
+			e.add("after", "must not be empty")
+		}
+		if q.Before != nil && *q.Before == "" {
+			e.add("before", "must not be empty")
+		}
+		if len(e.InvalidParams) == 0 {
+			return q.cursor(), nil
+		}
+	}
+	return nil, e
+}
+
+func (q Query) cursor() CursorParams {
+	p := CursorParams{Limit: *q.Limit, Direction: First}
+	switch {
+	case q.After != nil:
+		p.Direction, p.Cursor = After, *q.After
+	case q.Before != nil:
+		p.Direction, p.Cursor = Before, *q.Before
+	}
+	return p
+}
+
+// provided lists the supplied parameter names in a stable order.
+func (q Query) provided() []string {
+	var names []string
+	if q.Page != nil {
+		names = append(names, "page")
+	}
+	if q.PageSize != nil {
+		names = append(names, "pageSize")
+	}
+	if q.Limit != nil {
+		names = append(names, "limit")
+	}
+	if q.After != nil {
+		names = append(names, "after")
+	}
+	if q.Before != nil {
+		names = append(names, "before")
+	}
+	return names
+}
+
+func (e *Error) add(name, reason string) {
+	e.InvalidParams = append(e.InvalidParams, problem.InvalidParam{Name: name, Reason: reason})
+}
+
+// Problem builds the 400 problem for an error returned by ParseParams.
+// It returns nil for any other error.
+func Problem(err error) *problem.Problem {
+	var e *Error
+	if !errors.As(err, &e) {
+		return nil
+	}
+	opts := []problem.Option{problem.WithCode("invalid_pagination")}
+	for _, p := range e.InvalidParams {
+		opts = append(opts, problem.WithInvalidParam(p.Name, p.Reason))
+	}
+	return problem.BadRequest(Detail, opts...)
+}
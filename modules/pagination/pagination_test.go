@@ -0,0 +1,201 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"app/modules/middleware/problem"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+// legacyValid is the completeness rule ListProfiles used before ParseParams.
+func legacyValid(q Query) bool {
+	offsetProvided := q.Page != nil || q.PageSize != nil
+	cursorProvided := q.After != nil || q.Before != nil || q.Limit != nil
+	offsetComplete := q.Page != nil && q.PageSize != nil
+	cursorComplete := q.Limit != nil && (q.After == nil || q.Before == nil)
+	return !((offsetComplete && cursorComplete) || (!offsetProvided && !cursorProvided) ||
+		(offsetProvided && !offsetComplete) || (cursorProvided && !cursorComplete))
+}
+
+// Every presence combination of the five parameters (with valid values) must agree
+// with the legacy rule and produce the expected variant.
+func TestParseParams_AllCombinations(t *testing.T) {
+	for mask := 0; mask < 1<<5; mask++ {
+		q := Query{}
+		if mask&1 != 0 {
+			q.Page = ptr(2)
+		}
+		if mask&2 != 0 {
+			q.PageSize = ptr(20)
+		}
+		if mask&4 != 0 {
+			q.Limit = ptr(10)
+		}
+		if mask&8 != 0 {
+			q.After = ptr("a-cursor")
+		}
+		if mask&16 != 0 {
+			q.Before = ptr("b-cursor")
+		}
+
+		t.Run(fmt.Sprintf("%v", q.provided()), func(t *testing.T) {
+			params, err := ParseParams(q)
+			if want := legacyValid(q); (err == nil) != want {
+				t.Fatalf("valid = %v, want %v (err: %v)", err == nil, want, err)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidParams) {
+					t.Fatalf("error %v does not match ErrInvalidParams", err)
+				}
+				if params != nil {
+					t.Fatalf("params = %#v on error", params)
+				}
+				var e *Error
+				if !errors.As(err, &e) || len(e.InvalidParams) == 0 {
+					t.Fatalf("error %v carries no invalid params", err)
+				}
+				return
+			}
+
+			switch p := params.(type) {
+			case OffsetParams:
+				if q.Page == nil || p != (OffsetParams{Page: 2, PageSize: 20}) {
+					t.Fatalf("unexpected offset params %#v", p)
+				}
+			case CursorParams:
+				want := CursorParams{Limit: 10, Direction: First}
+				switch {
+				case q.After != nil:
+					want.Direction, want.Cursor = After, "a-cursor"
+				case q.Before != nil:
+					want.Direction, want.Cursor = Before, "b-cursor"
+				}
+				if p != want {
+					t.Fatalf("cursor params = %#v, want %#v", p, want)
+				}
+			default:
+				t.Fatalf("unexpected params type %T", params)
+			}
+		})
+	}
+}
+
+func TestParseParams_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name string
+		q    Query
+		want []problem.InvalidParam
+	}{
+		{
+			name: "nothing",
+			q:    Query{},
+			want: []problem.InvalidParam{
+				{Name: "page", Reason: "page+pageSize or limit is required"},
+				{Name: "limit", Reason: "page+pageSize or limit is required"},
+			},
+		},
+		{
+			name: "page without size",
+			q:    Query{Page: ptr(0)},
+			want: []problem.InvalidParam{{Name: "pageSize", Reason: "required with page"}},
+		},
+		{
+			name: "size without page",
+			q:    Query{PageSize: ptr(10)},
+			want: []problem.InvalidParam{{Name: "page", Reason: "required with pageSize"}},
+		},
+		{
+			name: "negative page and zero size",
+			q:    Query{Page: ptr(-1), PageSize: ptr(0)},
+			want: []problem.InvalidParam{
+				{Name: "page", Reason: "must be >= 0"},
+				{Name: "pageSize", Reason: "must be >= 1"},
+			},
+		},
+		{
+			name: "cursor without limit",
+			q:    Query{After: ptr("x")},
+			want: []problem.InvalidParam{{Name: "limit", Reason: "required with after/before"}},
+		},
+		{
+			name: "zero limit",
+			q:    Query{Limit: ptr(0)},
+			want: []problem.InvalidParam{{Name: "limit", Reason: "must be >= 1"}},
+		},
+		{
+			name: "after and before",
+			q:    Query{Limit: ptr(5), After: ptr("x"), Before: ptr("y")},
+			want: []problem.InvalidParam{{Name: "before", Reason: "cannot be combined with after"}},
+		},
+		{
+			name: "empty cursors",
+			q:    Query{Limit: ptr(5), Before: ptr("")},
+			want: []problem.InvalidParam{{Name: "before", Reason: "must not be empty"}},
+		},
+		{
+			name: "mixed sets",
+			q:    Query{Page: ptr(1), PageSize: ptr(10), Limit: ptr(5)},
+			want: []problem.InvalidParam{
+				{Name: "page", Reason: "page/pageSize cannot be combined with limit/after/before"},
+				{Name: "pageSize", Reason: "page/pageSize cannot be combined with limit/after/before"},
+				{Name: "limit", Reason: "page/pageSize cannot be combined with limit/after/before"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseParams(tt.q)
+			var e *Error
+			if !errors.As(err, &e) {
+				t.Fatalf("err = %v, want *Error", err)
+			}
+			if !reflect.DeepEqual(e.InvalidParams, tt.want) {
+				t.Fatalf("invalid params = %#v, want %#v", e.InvalidParams, tt.want)
+			}
+		})
+	}
+}
+
+func TestProblem(t *testing.T) {
+	_, err := ParseParams(Query{Page: ptr(1)})
+	p := Problem(err)
+	if p == nil {
+		t.Fatal("Problem returned nil for a pagination error")
+	}
+	if p.Status != http.StatusBadRequest || p.Detail == nil || *p.Detail != Detail {
+		t.Fatalf("unexpected problem %#v", p)
+	}
+	if p.Code == nil || *p.Code != "invalid_pagination" {
+		t.Fatalf("code = %v, want invalid_pagination", p.Code)
+	}
+	if p.InvalidParams == nil || len(*p.InvalidParams) != 1 || (*p.InvalidParams)[0].Name != "pageSize" {
+		t.Fatalf("invalid params = %#v", p.InvalidParams)
+	}
+
+	if Problem(errors.New("other")) != nil {
+		t.Fatal("Problem must ignore foreign errors")
+	}
+	if Problem(nil) != nil {
+		t.Fatal("Problem(nil) must be nil")
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package preferences stores per-principal defaults for the list endpoints.
+//
+// A caller saves the page size and sort it wants once, and later list requests that
+// omit those parameters get them:
+//
+//	store := preferences.NewStore(redis.NewRedisKV(client, redis.WithKeyPrefix("dev:preferences")))
+//
+//	mux.Handle("/v1/preferences", preferences.Handler(store))
+//	handler = preferences.Middleware(store)(handler) // after the principal is known
+//
+//	prefs := preferences.From(ctx) // in a handler, zero when the caller saved none
+//
+// Preferences are keyed by Principal.Subject, so anonymous requests have none.
+package preferences
@@ -0,0 +1,104 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"app/modules/auth"
+	"app/modules/middleware/problem"
+)
+
+type lookupKey struct{}
+
+// Middleware makes the caller's preferences available to From. They are only
+// loaded when a handler asks for them, so requests that do not cost no round trip.
+func Middleware(store *Store) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			lookup := sync.OnceValue(func() Preferences {
+				p, err := store.For(ctx)
+				if err != nil {
+					// the server defaults still answer the request
+					slog.WarnContext(ctx, "preferences: lookup failed, using defaults", slog.Any("error", err))
+				}
+				return p
+			})
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, lookupKey{}, lookup)))
+		})
+	}
+}
+
+// From returns the preferences of the request's principal, zero when there are
+// none or Middleware did not run.
+func From(ctx context.Context) Preferences {
+	if lookup, ok := ctx.Value(lookupKey{}).(func() Preferences); ok {
+		return lookup()
+	}
+	return Preferences{}
+}
+
+// Handler serves the caller's own preferences: GET returns them, PUT with
+// {"pageSize": int, "sort": string} replaces them. Both require a principal.
+func Handler(store *Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.PrincipalFrom(r.Context())
+		if !ok || principal.Subject == "" {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			problem.Write(w, problem.Unauthorized("authentication required", problem.WithCode("unauthenticated")))
+			return
+		}
+
+		var prefs Preferences
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+			p, err := store.Get(r.Context(), principal.Subject)
+			if err != nil {
+				slog.ErrorContext(r.Context(), "preferences: get failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("preferences could not be read, retry later"))
+				return
+			}
+			prefs = p
+		case http.MethodPut:
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&prefs); err != nil {
+				problem.Write(w, problem.BadRequest("body must be {\"pageSize\": int, \"sort\": string}"))
+				return
+			}
+			if err := store.Put(r.Context(), principal.Subject, prefs); err != nil {
+				if errors.Is(err, ErrInvalid) {
+					problem.Write(w, problem.BadRequest(err.Error(), problem.WithCode("invalid_preferences")))
+					return
+				}
+				slog.ErrorContext(r.Context(), "preferences: put failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("preferences could not be saved, retry later"))
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(prefs)
+	})
+}
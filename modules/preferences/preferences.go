@@ -0,0 +1,116 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"app/modules/auth"
+	"app/modules/db"
+)
+
+// ErrInvalid is returned by Store.Put for preferences the validator rejects.
+var ErrInvalid = errors.New("preferences: invalid")
+
+type (
+	// Preferences are the defaults of one principal. Zero fields keep the server defaults.
+	Preferences struct {
+		PageSize int    `json:"pageSize,omitempty"`
+		Sort     string `json:"sort,omitempty"`
+	}
+
+	// Store keeps Preferences in a db.KV, one key per principal subject.
+	Store struct {
+		kv       db.KV
+		validate func(Preferences) error
+	}
+
+	// Option configures a Store.
+	Option func(*Store)
+)
+
+// WithValidator checks preferences before Put saves them, e.g. that Sort names a
+// sort the list endpoints accept. Its error is wrapped in ErrInvalid.
+func WithValidator(fn func(Preferences) error) Option {
+	return func(s *Store) {
+		if fn != nil {
+			s.validate = fn
+		}
+	}
+}
+
+func NewStore(kv db.KV, opts ...Option) *Store {
+	s := &Store{kv: kv}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Get returns the preferences saved for subject, zero when there are none.
+func (s *Store) Get(ctx context.Context, subject string) (Preferences, error) {
+	var p Preferences
+	v, err := s.kv.AtomicGet(ctx, key(subject))
+	if err != nil {
+		return p, fmt.Errorf("preferences: get: %w", err)
+	}
+	b, _ := v.([]byte)
+	if len(b) == 0 {
+		return p, nil
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return Preferences{}, fmt.Errorf("preferences: decode: %w", err)
+	}
+	return p, nil
+}
+
+// Put saves p for subject, replacing what was saved before. Zero preferences
+// clear them.
+func (s *Store) Put(ctx context.Context, subject string, p Preferences) error {
+	if p.PageSize < 0 {
+		return fmt.Errorf("%w: pageSize must not be negative", ErrInvalid)
+	}
+	if s.validate != nil {
+		if err := s.validate(p); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalid, err)
+		}
+	}
+	var v any = p
+	if p == (Preferences{}) {
+		v = ""
+	}
+	if _, err := s.kv.AtomicSet(ctx, key(subject), v); err != nil {
+		return fmt.Errorf("preferences: put: %w", err)
+	}
+	return nil
+}
+
+// For returns the preferences of the principal in ctx, zero for anonymous requests.
+func (s *Store) For(ctx context.Context) (Preferences, error) {
+	principal, ok := auth.PrincipalFrom(ctx)
+	if !ok || principal.Subject == "" {
+		return Preferences{}, nil
+	}
+	return s.Get(ctx, principal.Subject)
+}
+
+func key(subject string) string {
+	return "principal:" + subject
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+var _ CounterStore = (*FallbackCounterStore)(nil)
+
+// FallbackCounterStore serves counters from a shared store and switches to a
+// local MemoryCounterStore while the shared one fails, instead of failing every
+// rate limit decision.
+//
+// The local counters are only ever seen by this node. To keep a restart from
+// resetting them, Persist saves them on shutdown and Restore loads them back
+// on startup (see SnapshotStore).
+type FallbackCounterStore struct {
+	primary CounterStore
+	local   *MemoryCounterStore
+
+	// optional external health signal, see WithPrimaryDegraded
+	primaryDegraded func() bool
+
+	degraded atomic.Bool
+}
+
+// FallbackOption configures a FallbackCounterStore.
+type FallbackOption func(*FallbackCounterStore)
+
+// WithPrimaryDegraded goes straight to the local counters while fn reports true
+// (e.g. redis.Watchdog.Degraded), instead of paying the primary's timeout on every call.
+func WithPrimaryDegraded(fn func() bool) FallbackOption {
+	return func(f *FallbackCounterStore) {
+		f.primaryDegraded = fn
+	}
+}
+
+func NewFallbackCounterStore(primary CounterStore, local *MemoryCounterStore, opts ...FallbackOption) *FallbackCounterStore {
+	if local == nil {
+		local = NewMemoryCounterStore(nil)
+	}
+	f := &FallbackCounterStore{primary: primary, local: local}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	return f
+}
+
+// Incr implements CounterStore.
+func (f *FallbackCounterStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if f.skipPrimary(ctx) {
+		return f.local.Incr(ctx, key, ttl)
+	}
+	v, err := f.primary.Incr(ctx, key, ttl)
+	if err == nil {
+		f.recovered(ctx)
+		return v, nil
+	}
+	if ctx.Err() != nil {
+		return 0, err
+	}
+	f.degrade(ctx, err)
+	return f.local.Incr(ctx, key, ttl)
+}
+
+// Get implements CounterStore.
+func (f *FallbackCounterStore) Get(ctx context.Context, key string) (int64, error) {
+	if f.skipPrimary(ctx) {
+		return f.local.Get(ctx, key)
+	}
+	v, err := f.primary.Get(ctx, key)
+	if err == nil {
+		f.recovered(ctx)
+		return v, nil
+	}
+	if ctx.Err() != nil {
+		return 0, err
+	}
+	f.degrade(ctx, err)
+	return f.local.Get(ctx, key)
+}
+
+// Degraded reports whether the last call fell back to local counters.
+func (f *FallbackCounterStore) Degraded() bool {
+	return f.degraded.Load()
+}
+
+// Persist saves the local counters. Nothing is written when there are none.
+func (f *FallbackCounterStore) Persist(ctx context.Context, store SnapshotStore) error {
+	s := f.local.Snapshot()
+	if len(s.Counters) == 0 {
+		return nil
+	}
+	if err := store.SaveSnapshot(ctx, s); err != nil {
+		return fmt.Errorf("ratelimit: persist fallback counters: %w", err)
+	}
+	slog.InfoContext(ctx, "ratelimit: fallback counters persisted", slog.Int("counters", len(s.Counters)))
+	return nil
+}
+
+// Restore loads counters saved by a previous Persist.
+func (f *FallbackCounterStore) Restore(ctx context.Context, store SnapshotStore) error {
+	s, ok, err := store.LoadSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("ratelimit: restore fallback counters: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+	n := f.local.Restore(s)
+	slog.InfoContext(ctx, "ratelimit: fallback counters restored",
+		slog.Int("counters", n),
+		slog.Int("expired", len(s.Counters)-n),
+		slog.Time("taken_at", s.TakenAt),
+	)
+	return nil
+}
+
+func (f *FallbackCounterStore) skipPrimary(ctx context.Context) bool {
+	if f.primaryDegraded == nil || !f.primaryDegraded() {
+		return false
+	}
+	f.degrade(ctx, errors.New("primary reported degraded"))
+	return true
+}
+
+func (f *FallbackCounterStore) degrade(ctx context.Context, err error) {
+	if !f.degraded.Swap(true) {
+		slog.WarnContext(ctx, "ratelimit: counter store unavailable, using local counters", slog.Any("error", err))
+	}
+}
+
+func (f *FallbackCounterStore) recovered(ctx context.Context) {
+	if f.degraded.Swap(false) {
+		slog.InfoContext(ctx, "ratelimit: counter store recovered, leaving local counters")
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+)
+
+var _ CounterStore = (*MemoryCounterStore)(nil)
+
+// sweep expired counters every this many increments
+const memorySweepEvery = 1024
+
+type (
+	// MemoryCounterStore is a process-local CounterStore with per-key expiry.
+	//
+	// It is meant as a stand-in while the shared store is unreachable: limits
+	// become per node, which is still better than none.
+	MemoryCounterStore struct {
+		mu      sync.Mutex
+		clock   clock.Clock
+		entries map[string]memoryEntry
+		ops     int
+	}
+
+	memoryEntry struct {
+		value     int64
+		expiresAt time.Time
+	}
+
+	// Snapshot is a point-in-time copy of a MemoryCounterStore.
+	Snapshot struct {
+		TakenAt  time.Time       `json:"takenAt"`
+		Counters []SnapshotEntry `json:"counters"`
+	}
+
+	SnapshotEntry struct {
+		Key       string    `json:"key"`
+		Value     int64     `json:"value"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+
+	// SnapshotStore keeps a Snapshot across process restarts.
+	SnapshotStore interface {
+		// SaveSnapshot replaces the stored snapshot.
+		SaveSnapshot(ctx context.Context, s Snapshot) error
+		// LoadSnapshot returns and removes the stored snapshot; ok is false when there is none.
+		LoadSnapshot(ctx context.Context) (s Snapshot, ok bool, err error)
+	}
+)
+
+func NewMemoryCounterStore(c clock.Clock) *MemoryCounterStore {
+	if c == nil {
+		c = clock.RealClock{}
+	}
+	return &MemoryCounterStore{clock: c, entries: make(map[string]memoryEntry)}
+}
+
+// Incr implements CounterStore.
+func (m *MemoryCounterStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ops++
+	if m.ops%memorySweepEvery == 0 {
+		m.sweep(now)
+	}
+
+	e, ok := m.entries[key]
+	if !ok || !now.Before(e.expiresAt) {
+		// like the Redis store, the TTL is only set when the counter is created
+		e = memoryEntry{expiresAt: now.Add(ttl)}
+	}
+	e.value++
+	m.entries[key] = e
+	return e.value, nil
+}
+
+// Get implements CounterStore.
+func (m *MemoryCounterStore) Get(_ context.Context, key string) (int64, error) {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok || !now.Before(e.expiresAt) {
+		return 0, nil
+	}
+	return e.value, nil
+}
+
+// Len returns the number of live counters.
+func (m *MemoryCounterStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sweep(m.clock.Now())
+	return len(m.entries)
+}
+
+// Snapshot copies every live counter.
+func (m *MemoryCounterStore) Snapshot() Snapshot {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := Snapshot{TakenAt: now, Counters: make([]SnapshotEntry, 0, len(m.entries))}
+	for k, e := range m.entries {
+		if !now.Before(e.expiresAt) {
+			continue
+		}
+		s.Counters = append(s.Counters, SnapshotEntry{Key: k, Value: e.value, ExpiresAt: e.expiresAt})
+	}
+	return s
+}
+
+// Restore merges a snapshot, keeping the higher value where a counter already
+// exists. Expired entries are dropped. It returns the number of counters restored.
+func (m *MemoryCounterStore) Restore(s Snapshot) int {
+	now := m.clock.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	restored := 0
+	for _, c := range s.Counters {
+		if !now.Before(c.ExpiresAt) {
+			continue
+		}
+		if e, ok := m.entries[c.Key]; ok && now.Before(e.expiresAt) && e.value >= c.Value {
+			continue
+		}
+		m.entries[c.Key] = memoryEntry{value: c.Value, expiresAt: c.ExpiresAt}
+		restored++
+	}
+	return restored
+}
+
+// sweep drops expired counters. Callers must hold m.mu.
+func (m *MemoryCounterStore) sweep(now time.Time) {
+	for k, e := range m.entries {
+		if !now.Before(e.expiresAt) {
+			delete(m.entries, k)
+		}
+	}
+}
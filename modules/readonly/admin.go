@@ -0,0 +1,70 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"app/modules/auth"
+	"app/modules/authz"
+	"app/modules/middleware/problem"
+)
+
+// AdminHandler serves the Switch for operators: GET returns the State, PUT with
+// {"enabled": bool, "reason": string} changes it. Both require scope.
+func AdminHandler(sw *Switch, scope string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authz.Require(r.Context(), scope); err != nil {
+			authz.WriteProblem(w, err)
+			return
+		}
+		principal, _ := auth.PrincipalFrom(r.Context())
+
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPut:
+			var body struct {
+				Enabled *bool  `json:"enabled"`
+				Reason  string `json:"reason"`
+			}
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&body); err != nil || body.Enabled == nil {
+				problem.Write(w, problem.BadRequest("body must be {\"enabled\": bool, \"reason\": string}",
+					problem.WithInvalidParam("enabled", "is required"),
+				))
+				return
+			}
+			if _, err := sw.Set(r.Context(), *body.Enabled, body.Reason); err != nil {
+				slog.ErrorContext(r.Context(), "readonly: set failed", slog.Any("error", err))
+				problem.Write(w, problem.ServiceUnavailable("read-only state could not be saved, retry later"))
+				return
+			}
+			slog.InfoContext(r.Context(), "readonly: changed by operator",
+				slog.String("subject", principal.Subject),
+				slog.Bool("enabled", *body.Enabled),
+				slog.String("reason", body.Reason),
+			)
+		default:
+			w.Header().Set("Allow", "GET, HEAD, PUT")
+			problem.Write(w, problem.MethodNotAllowed(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(sw.State())
+	})
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import "time"
+
+// Config configures the read-only switch.
+type Config struct {
+	// Enabled forces read-only mode on this node regardless of the shared state.
+	Enabled bool   `env:"ENABLED" envDefault:"false"`
+	Reason  string `env:"REASON"`
+	// RefreshInterval is how often the shared state is reloaded.
+	RefreshInterval time.Duration `env:"REFRESH_INTERVAL" envDefault:"2s"`
+	// AdminScope is required to read or change the state through the admin endpoint.
+	AdminScope string `env:"ADMIN_SCOPE" envDefault:"admin"`
+}
@@ -0,0 +1,28 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readonly holds the fleet-wide read-only switch.
+//
+// While read-only, mutating requests are rejected with 503 and reads keep being
+// served, e.g. during a migration or while an incident is investigated:
+//
+//	sw := readonly.NewSwitch(redis.NewReadOnlyStore(client, "dev:read_only"))
+//	go sw.Run(ctx) // picks up changes made on other nodes
+//
+//	mux.Handle("/admin/read-only", readonly.AdminHandler(sw, "admin"))
+//	handler = middleware.ReadOnly(sw, "/admin/")(handler)
+//
+// The state lives in the Store, so toggling it on one node reaches every node
+// within the refresh interval.
+package readonly
@@ -0,0 +1,179 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readonly
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"app/modules/clock"
+)
+
+type (
+	// State is the read-only state shared by the fleet.
+	State struct {
+		Enabled bool      `json:"enabled"`
+		Reason  string    `json:"reason,omitempty"`
+		Since   time.Time `json:"since,omitzero"`
+	}
+
+	// Store persists the shared State. Load returns the zero State when none was saved.
+	Store interface {
+		Load(ctx context.Context) (State, error)
+		Save(ctx context.Context, s State) error
+	}
+
+	// Switch caches the shared State for the request path and refreshes it in the
+	// background. A forced Switch (see WithForced) is read-only regardless of the Store.
+	Switch struct {
+		store    Store
+		clock    clock.Clock
+		interval time.Duration
+		forced   *State
+
+		current atomic.Pointer[State]
+		// serializes Set against Refresh so a refresh cannot undo a fresh Set
+		mu sync.Mutex
+	}
+
+	// Option configures a Switch.
+	Option func(*Switch)
+)
+
+// WithRefreshInterval sets how often Run reloads the State. Defaults to 2 seconds.
+func WithRefreshInterval(d time.Duration) Option {
+	return func(s *Switch) {
+		if d > 0 {
+			s.interval = d
+		}
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(s *Switch) {
+		if c != nil {
+			s.clock = c
+		}
+	}
+}
+
+// WithForced pins the Switch to read-only, e.g. from static config during a planned
+// migration. Set still records the shared State but cannot lift a forced mode.
+func WithForced(reason string) Option {
+	return func(s *Switch) {
+		s.forced = &State{Enabled: true, Reason: reason}
+	}
+}
+
+// NewSwitch constructs a Switch. A nil store keeps the State in process.
+func NewSwitch(store Store, opts ...Option) *Switch {
+	s := &Switch{
+		store:    store,
+		clock:    clock.RealClock{},
+		interval: 2 * time.Second,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	if s.forced != nil {
+		s.forced.Since = s.clock.Now()
+	}
+	s.current.Store(&State{})
+	return s
+}
+
+// State returns the effective State.
+func (s *Switch) State() State {
+	if s.forced != nil {
+		return *s.forced
+	}
+	return *s.current.Load()
+}
+
+// Enabled reports whether mutating requests must be rejected.
+func (s *Switch) Enabled() bool {
+	return s.State().Enabled
+}
+
+// Set saves the shared State and applies it locally right away; other nodes pick it
+// up on their next refresh.
+func (s *Switch) Set(ctx context.Context, enabled bool, reason string) (State, error) {
+	st := State{Enabled: enabled, Reason: reason}
+	if enabled {
+		st.Since = s.clock.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.store != nil {
+		if err := s.store.Save(ctx, st); err != nil {
+			return State{}, fmt.Errorf("readonly: save: %w", err)
+		}
+	}
+	prev := s.current.Swap(&st)
+	if prev.Enabled != st.Enabled {
+		slog.WarnContext(ctx, "readonly: mode changed", slog.Bool("enabled", st.Enabled), slog.String("reason", st.Reason))
+	}
+	return s.State(), nil
+}
+
+// Refresh reloads the shared State. On error the last known State is kept.
+func (s *Switch) Refresh(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st, err := s.store.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("readonly: load: %w", err)
+	}
+	prev := s.current.Swap(&st)
+	if prev.Enabled != st.Enabled {
+		slog.WarnContext(ctx, "readonly: mode changed", slog.Bool("enabled", st.Enabled), slog.String("reason", st.Reason))
+	}
+	return nil
+}
+
+// Run refreshes the State every interval until ctx is cancelled.
+func (s *Switch) Run(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		slog.WarnContext(ctx, "readonly: refresh failed", slog.Any("error", err))
+	}
+	if s.store == nil {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				slog.WarnContext(ctx, "readonly: refresh failed", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package requestid carries the correlation id of a request through its context.
+//
+// middleware.RequestID stores the id of every incoming request; anything holding
+// the context can read it back or pass it on:
+//
+//	id := requestid.From(ctx)
+//
+//	slog.SetDefault(slog.New(requestid.NewLogHandler(slog.NewJSONHandler(os.Stderr, nil))))
+//	slog.InfoContext(ctx, "profile created") // ... request_id=0199...
+//
+//	client := httpclient.New(httpclient.WithRequestID()) // forwards X-Request-Id
+package requestid
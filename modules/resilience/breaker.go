@@ -0,0 +1,281 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+)
+
+// ErrOpen is returned without calling the dependency while a breaker is open.
+var ErrOpen = errors.New("resilience: circuit open")
+
+// State is the position of a Breaker.
+type State string
+
+const (
+	// StateClosed lets every call through.
+	StateClosed State = "closed"
+	// StateOpen rejects every call with ErrOpen until the cool-down elapsed.
+	StateOpen State = "open"
+	// StateHalfOpen lets a limited number of probe calls through.
+	StateHalfOpen State = "half-open"
+)
+
+type (
+	// Breaker is a consecutive-failure circuit breaker. It is safe for concurrent use.
+	Breaker struct {
+		name  string
+		clock clock.Clock
+
+		failureThreshold int
+		openTimeout      time.Duration
+		halfOpenProbes   int
+		isFailure        func(error) bool
+		onStateChange    []StateChangeHook
+
+		mu       sync.Mutex
+		state    State
+		failures int
+		openedAt time.Time
+		probes   int
+	}
+
+	// StateChangeHook is called, outside the breaker lock, whenever the state changes.
+	StateChangeHook func(ctx context.Context, name string, from, to State)
+
+	// Option configures a Breaker.
+	Option func(*Breaker)
+)
+
+// WithFailureThreshold sets how many consecutive failures open the breaker. Defaults to 5.
+func WithFailureThreshold(n int) Option {
+	return func(b *Breaker) {
+		if n > 0 {
+			b.failureThreshold = n
+		}
+	}
+}
+
+// WithOpenTimeout sets how long the breaker stays open before probing. Defaults to 10 seconds.
+func WithOpenTimeout(d time.Duration) Option {
+	return func(b *Breaker) {
+		if d > 0 {
+			b.openTimeout = d
+		}
+	}
+}
+
+// WithHalfOpenProbes sets how many calls may probe the dependency concurrently
+// while half-open. Defaults to 1.
+func WithHalfOpenProbes(n int) Option {
+	return func(b *Breaker) {
+		if n > 0 {
+			b.halfOpenProbes = n
+		}
+	}
+}
+
+// WithIsFailure decides which errors count as failures of the dependency.
+// Errors it rejects, e.g. "lock held by someone else", count as successes.
+func WithIsFailure(fn func(error) bool) Option {
+	return func(b *Breaker) {
+		if fn != nil {
+			b.isFailure = fn
+		}
+	}
+}
+
+// WithStateChangeHook registers fn to be called on every state change.
+func WithStateChangeHook(fn StateChangeHook) Option {
+	return func(b *Breaker) {
+		if fn != nil {
+			b.onStateChange = append(b.onStateChange, fn)
+		}
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(c clock.Clock) Option {
+	return func(b *Breaker) {
+		if c != nil {
+			b.clock = c
+		}
+	}
+}
+
+// New constructs a closed Breaker; name identifies it in logs and errors.
+func New(name string, opts ...Option) *Breaker {
+	b := &Breaker{
+		name:             name,
+		clock:            clock.RealClock{},
+		failureThreshold: 5,
+		openTimeout:      10 * time.Second,
+		halfOpenProbes:   1,
+		isFailure:        func(err error) bool { return err != nil },
+		state:            StateClosed,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(b)
+		}
+	}
+	return b
+}
+
+// Name returns the breaker name.
+func (b *Breaker) Name() string { return b.name }
+
+// State returns the current state, reporting an open breaker whose cool-down
+// elapsed as half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.clock.Now().Sub(b.openedAt) >= b.openTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// RetryAfter returns how long the breaker keeps rejecting calls, or 0 while it
+// lets them through. A half-open breaker with every probe in flight answers the
+// open timeout, the outcome of a probe is not known before.
+func (b *Breaker) RetryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case StateOpen:
+		return max(b.openTimeout-b.clock.Now().Sub(b.openedAt), 0)
+	case StateHalfOpen:
+		if b.probes >= b.halfOpenProbes {
+			return b.openTimeout
+		}
+	}
+	return 0
+}
+
+// Do runs fn unless the breaker is open, and records its outcome.
+//
+// A rejected call returns an error wrapping ErrOpen.
+func (b *Breaker) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if err := b.allow(ctx); err != nil {
+		return err
+	}
+	err := fn(ctx)
+	b.record(ctx, err)
+	return err
+}
+
+// Call is Do for functions returning a value.
+func Call[T any](ctx context.Context, b *Breaker, fn func(ctx context.Context) (T, error)) (T, error) {
+	var v T
+	err := b.Do(ctx, func(ctx context.Context) error {
+		var err error
+		v, err = fn(ctx)
+		return err
+	})
+	return v, err
+}
+
+func (b *Breaker) allow(ctx context.Context) error {
+	b.mu.Lock()
+	var from State
+	switch b.state {
+	case StateOpen:
+		if b.clock.Now().Sub(b.openedAt) < b.openTimeout {
+			b.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrOpen, b.name)
+		}
+		from = b.state
+		b.state, b.probes = StateHalfOpen, 0
+		fallthrough
+	case StateHalfOpen:
+		if b.probes >= b.halfOpenProbes {
+			b.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrOpen, b.name)
+		}
+		b.probes++
+	}
+	b.mu.Unlock()
+	b.notify(ctx, from, StateHalfOpen)
+	return nil
+}
+
+func (b *Breaker) record(ctx context.Context, err error) {
+	// the caller gave up; that says nothing about the dependency
+	neutral := err != nil && ctx.Err() != nil
+	failed := !neutral && b.isFailure(err)
+
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case StateHalfOpen:
+		b.probes--
+		switch {
+		case neutral:
+		case failed:
+			b.trip()
+		default:
+			b.state, b.failures = StateClosed, 0
+		}
+	case StateClosed:
+		switch {
+		case neutral:
+		case failed:
+			b.failures++
+			if b.failures >= b.failureThreshold {
+				b.trip()
+			}
+		default:
+			b.failures = 0
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	if from != to && to == StateOpen {
+		slog.WarnContext(ctx, "resilience: circuit opened",
+			slog.String("breaker", b.name),
+			slog.Duration("open_timeout", b.openTimeout),
+			slog.Any("error", err),
+		)
+	}
+	b.notify(ctx, from, to)
+}
+
+// trip opens the breaker. Callers must hold b.mu.
+func (b *Breaker) trip() {
+	b.state = StateOpen
+	b.openedAt = b.clock.Now()
+	b.failures, b.probes = 0, 0
+}
+
+func (b *Breaker) notify(ctx context.Context, from, to State) {
+	if from == "" || from == to {
+		return
+	}
+	if to == StateClosed {
+		slog.InfoContext(ctx, "resilience: circuit closed", slog.String("breaker", b.name))
+	}
+	for _, hook := range b.onStateChange {
+		hook(ctx, b.name, from, to)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resilience
+
+import "time"
+
+// Config configures the breakers guarding Redis-backed dependencies.
+type Config struct {
+	Enabled bool `env:"ENABLED" envDefault:"true"`
+	// FailureThreshold consecutive failures open a breaker.
+	FailureThreshold int `env:"FAILURE_THRESHOLD" envDefault:"5"`
+	// OpenTimeout is how long an open breaker rejects calls before probing again.
+	OpenTimeout time.Duration `env:"OPEN_TIMEOUT" envDefault:"10s"`
+	// HalfOpenProbes calls may probe the dependency at once while half-open.
+	HalfOpenProbes int `env:"HALF_OPEN_PROBES" envDefault:"1"`
+}
+
+// Options returns the breaker options described by c.
+func (c Config) Options() []Option {
+	return []Option{
+		WithFailureThreshold(c.FailureThreshold),
+		WithOpenTimeout(c.OpenTimeout),
+		WithHalfOpenProbes(c.HalfOpenProbes),
+	}
+}
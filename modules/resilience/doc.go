@@ -0,0 +1,39 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package resilience guards calls to remote dependencies with circuit breakers.
+//
+// A Breaker counts consecutive failures of the calls it guards:
+//
+//	closed --N consecutive failures--> open --cool-down--> half-open
+//	half-open --probe succeeds--> closed
+//	half-open --probe fails--> open
+//
+// While open, calls fail immediately with ErrOpen instead of waiting on a dependency
+// that is known to be down, so a Redis outage costs every request nothing rather
+// than a full client timeout. Calls whose own context was cancelled do not count
+// either way: the caller gave up, the dependency did not fail.
+//
+// The wrappers put a breaker in front of the Redis-backed ports of the template
+// without touching their callers:
+//
+//	counters := resilience.NewCounterStore(redisCounter, resilience.New("redis.counter"))
+//	kv := resilience.NewKV(redisKV, resilience.New("redis.kv"))
+//	locker := resilience.NewLocker(redisLocker, resilience.New("redis.locks"))
+//
+// Wrapped in a ratelimit.FallbackCounterStore, an open breaker switches the limiter
+// to its local counters right away.
+//
+// routepolicy uses a Breaker per route-method too, counting 5xx responses as failures.
+package resilience
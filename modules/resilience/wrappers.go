@@ -0,0 +1,134 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/modules/db"
+	"app/modules/db/redis/locking"
+	"app/modules/ratelimit"
+
+	"github.com/redis/rueidis/rueidislock"
+)
+
+var (
+	_ ratelimit.CounterStore = (*CounterStore)(nil)
+	_ db.KV                  = (*KV)(nil)
+	_ locking.Locker         = (*Locker)(nil)
+)
+
+// CounterStore guards a ratelimit.CounterStore with a Breaker.
+type CounterStore struct {
+	next    ratelimit.CounterStore
+	breaker *Breaker
+}
+
+// NewCounterStore wraps next with b.
+func NewCounterStore(next ratelimit.CounterStore, b *Breaker) *CounterStore {
+	return &CounterStore{next: next, breaker: b}
+}
+
+// Incr implements ratelimit.CounterStore.
+func (c *CounterStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return Call(ctx, c.breaker, func(ctx context.Context) (int64, error) {
+		return c.next.Incr(ctx, key, ttl)
+	})
+}
+
+// Get implements ratelimit.CounterStore.
+func (c *CounterStore) Get(ctx context.Context, key string) (int64, error) {
+	return Call(ctx, c.breaker, func(ctx context.Context) (int64, error) {
+		return c.next.Get(ctx, key)
+	})
+}
+
+// KV guards a db.KV with a Breaker.
+type KV struct {
+	next    db.KV
+	breaker *Breaker
+}
+
+// NewKV wraps next with b.
+func NewKV(next db.KV, b *Breaker) *KV {
+	return &KV{next: next, breaker: b}
+}
+
+// AtomicGet implements db.KV.
+func (k *KV) AtomicGet(ctx context.Context, key string) (any, error) {
+	return Call(ctx, k.breaker, func(ctx context.Context) (any, error) {
+		return k.next.AtomicGet(ctx, key)
+	})
+}
+
+// AtomicSet implements db.KV.
+func (k *KV) AtomicSet(ctx context.Context, key string, value any) (any, error) {
+	return Call(ctx, k.breaker, func(ctx context.Context) (any, error) {
+		return k.next.AtomicSet(ctx, key, value)
+	})
+}
+
+// Locker guards a locking.Locker with a Breaker.
+//
+// Contention is not a failure: a lock held by another node, or a wait that ended
+// because the caller's context did, leaves the breaker alone.
+type Locker struct {
+	next    locking.Locker
+	breaker *Breaker
+}
+
+// NewLocker wraps next with b. It replaces the failure classification of b.
+func NewLocker(next locking.Locker, b *Breaker) *Locker {
+	WithIsFailure(lockFailure)(b)
+	return &Locker{next: next, breaker: b}
+}
+
+func lockFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, locking.ErrLockNotAcquired) &&
+		!errors.Is(err, rueidislock.ErrNotLocked)
+}
+
+// WithContext implements locking.Locker.
+func (l *Locker) WithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.acquire(ctx, name, l.next.WithContext)
+}
+
+// TryWithContext implements locking.Locker.
+func (l *Locker) TryWithContext(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	return l.acquire(ctx, name, l.next.TryWithContext)
+}
+
+// Close implements locking.Locker.
+func (l *Locker) Close() { l.next.Close() }
+
+func (l *Locker) acquire(
+	ctx context.Context,
+	name string,
+	fn func(context.Context, string) (context.Context, context.CancelFunc, error),
+) (context.Context, context.CancelFunc, error) {
+	var (
+		lockCtx context.Context
+		cancel  context.CancelFunc
+	)
+	err := l.breaker.Do(ctx, func(ctx context.Context) error {
+		var err error
+		lockCtx, cancel, err = fn(ctx, name)
+		return err
+	})
+	return lockCtx, cancel, err
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import "sync"
+
+// Budget caps retries to a share of the calls made through it: every call earns
+// Ratio of a token, up to Burst tokens, and every retry spends one. While a
+// dependency is healthy the budget stays full; once most calls fail, retries are
+// limited to Ratio of the traffic instead of multiplying it.
+//
+// A nil *Budget never denies a retry. A Budget is safe for concurrent use.
+type Budget struct {
+	ratio float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewBudget returns a full budget allowing retries for ratio of the calls (0.1
+// for one retry every ten calls), with burst retries available up front.
+func NewBudget(ratio float64, burst int) *Budget {
+	b := &Budget{ratio: max(ratio, 0), burst: float64(max(burst, 1))}
+	b.tokens = b.burst
+	return b
+}
+
+func (b *Budget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.tokens = min(b.burst, b.tokens+b.ratio)
+	b.mu.Unlock()
+}
+
+func (b *Budget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
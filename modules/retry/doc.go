@@ -0,0 +1,33 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package retry runs an operation again after transient failures, waiting an
+// exponentially growing, jittered delay between attempts.
+//
+//	err := retry.Do(ctx, retry.Policy{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond, MaxDelay: 500 * time.Millisecond},
+//		func(ctx context.Context) error { return tx(ctx) },
+//		retry.If(postgres.IsRetryableTxError),
+//	)
+//
+// Do stops at the first success, at an error the predicate of If rejects, once
+// the attempts or MaxElapsed are used up, or when ctx ends; it returns the last
+// error of fn, joined with ctx.Err() in the latter case.
+//
+// An error wrapped with After asks for a specific delay, such as a Retry-After
+// the server sent, instead of the computed backoff.
+//
+// A Budget shared by the callers of one dependency caps the share of retries in
+// its traffic, so retries stop adding load once most calls fail. Every failed
+// attempt that is retried is recorded as a "retry" event on the span of ctx.
+package retry
@@ -0,0 +1,215 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"app/modules/clock"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type (
+	// Policy shapes the attempts of Do.
+	Policy struct {
+		// MaxAttempts counts the first attempt; zero or less leaves the attempts
+		// bounded only by MaxElapsed and the context.
+		MaxAttempts int
+		// The delay before retry n is min(MaxDelay, BaseDelay*2^(n-1)), jittered.
+		// A zero MaxDelay does not cap the delay.
+		BaseDelay time.Duration
+		MaxDelay  time.Duration
+		// MaxElapsed gives up before a wait that would end later than MaxElapsed
+		// after the first attempt started. Zero does not bound the time.
+		MaxElapsed time.Duration
+		// Jitter is the fraction of each delay drawn at random: computed delays
+		// shrink by up to Jitter, delays asked for with After grow by up to Jitter.
+		// Zero is full jitter, a computed delay is drawn from [0, delay), which
+		// spreads out callers that failed together. Use a negative value for none.
+		Jitter float64
+	}
+
+	// Option configures a single Do call.
+	Option func(*options)
+
+	// OnRetry observes a failed attempt before Do waits delay to retry it.
+	OnRetry func(ctx context.Context, attempt int, delay time.Duration, err error)
+
+	options struct {
+		retryable func(error) bool
+		budget    *Budget
+		onRetry   []OnRetry
+		timers    clock.Timers
+		name      string
+	}
+
+	delayed struct {
+		err   error
+		delay time.Duration
+	}
+)
+
+// If retries only the errors pred accepts. By default every error is retried.
+func If(pred func(error) bool) Option {
+	return func(o *options) {
+		if pred != nil {
+			o.retryable = pred
+		}
+	}
+}
+
+// WithBudget draws every retry from b; once it is spent Do returns the last error.
+func WithBudget(b *Budget) Option {
+	return func(o *options) {
+		o.budget = b
+	}
+}
+
+// WithOnRetry calls fn for every attempt that is retried, e.g. to log it.
+func WithOnRetry(fn OnRetry) Option {
+	return func(o *options) {
+		if fn != nil {
+			o.onRetry = append(o.onRetry, fn)
+		}
+	}
+}
+
+// WithName labels the span events of the operation.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithTimers drives the waits from t instead of the wall clock (useful in tests).
+func WithTimers(t clock.Timers) Option {
+	return func(o *options) {
+		if t != nil {
+			o.timers = t
+		}
+	}
+}
+
+// After wraps err so Do waits d before the next attempt instead of its backoff.
+func After(err error, d time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	return &delayed{err: err, delay: d}
+}
+
+func (e *delayed) Error() string { return e.err.Error() }
+func (e *delayed) Unwrap() error { return e.err }
+
+// Do calls fn until it succeeds or the policy gives up, see the package doc.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error, opts ...Option) error {
+	o := options{retryable: func(error) bool { return true }, timers: clock.RealClock{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	start := o.timers.Now()
+	o.budget.deposit()
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil || !o.retryable(err) || (p.MaxAttempts > 0 && attempt >= p.MaxAttempts) {
+			return err
+		}
+
+		delay := p.delay(attempt, err)
+		if p.MaxElapsed > 0 && o.timers.Now().Add(delay).Sub(start) > p.MaxElapsed {
+			return err
+		}
+		if !o.budget.withdraw() {
+			return err
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("retry", trace.WithAttributes(
+			attribute.String("retry.operation", o.name),
+			attribute.Int("retry.attempt", attempt),
+			attribute.Int64("retry.delay_ms", delay.Milliseconds()),
+			attribute.String("error", err.Error()),
+		))
+		for _, fn := range o.onRetry {
+			fn(ctx, attempt, delay, err)
+		}
+
+		if o.sleep(ctx, delay) != nil {
+			// the caller cares more about the failure than about the deadline
+			return errors.Join(err, ctx.Err())
+		}
+	}
+}
+
+// Delay returns the jittered wait before retry n (n >= 1) when the failed attempt
+// did not ask for one, for loops that pace themselves, such as pollers.
+func (p Policy) Delay(n int) time.Duration {
+	return p.delay(n, nil)
+}
+
+func (p Policy) delay(n int, err error) time.Duration {
+	var d *delayed
+	if errors.As(err, &d) {
+		if p.Jitter > 0 && d.delay > 0 {
+			return d.delay + rand.N(time.Duration(float64(d.delay)*p.Jitter)+1)
+		}
+		return max(d.delay, 0)
+	}
+
+	ceiling := p.BaseDelay
+	// doubling stops at MaxDelay, or before it would overflow
+	for i := 1; i < n && ceiling > 0 && ceiling <= math.MaxInt64/2 && (p.MaxDelay <= 0 || ceiling < p.MaxDelay); i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 {
+		ceiling = min(ceiling, p.MaxDelay)
+	}
+	switch {
+	case ceiling <= 0:
+		return 0
+	case p.Jitter < 0:
+		return ceiling
+	case p.Jitter == 0 || p.Jitter >= 1:
+		return rand.N(ceiling)
+	default:
+		return ceiling - rand.N(time.Duration(float64(ceiling)*p.Jitter)+1)
+	}
+}
+
+func (o *options) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	done := make(chan struct{})
+	stop := o.timers.AfterFunc(d, func() { close(done) })
+	select {
+	case <-ctx.Done():
+		stop()
+		return ctx.Err()
+	case <-done:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/modules/clock"
+)
+
+var errTransient = errors.New("transient")
+
+// failing returns an fn failing n times with err before succeeding, and its call count.
+func failing(n int, err error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}, &calls
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	fn, calls := failing(2, errTransient)
+	var delays []time.Duration
+	err := Do(t.Context(), Policy{MaxAttempts: 5, BaseDelay: time.Millisecond, Jitter: -1}, fn,
+		WithOnRetry(func(_ context.Context, _ int, d time.Duration, _ error) { delays = append(delays, d) }))
+	if err != nil || *calls != 3 {
+		t.Fatalf("Do = %v after %d calls, want nil after 3", err, *calls)
+	}
+	if want := []time.Duration{time.Millisecond, 2 * time.Millisecond}; len(delays) != 2 || delays[0] != want[0] || delays[1] != want[1] {
+		t.Fatalf("delays = %v, want %v", delays, want)
+	}
+}
+
+func TestDo_StopsAt(t *testing.T) {
+	fatal := errors.New("fatal")
+	tests := []struct {
+		name      string
+		policy    Policy
+		err       error
+		opts      []Option
+		wantCalls int
+	}{
+		{"max attempts", Policy{MaxAttempts: 3}, errTransient, nil, 3},
+		{"rejected error", Policy{MaxAttempts: 3}, fatal, []Option{If(func(err error) bool { return !errors.Is(err, fatal) })}, 1},
+		{"max elapsed", Policy{BaseDelay: time.Hour, MaxElapsed: time.Minute}, errTransient, nil, 1},
+		{"spent budget", Policy{MaxAttempts: 10}, errTransient, []Option{WithBudget(NewBudget(0, 2))}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, calls := failing(100, tt.err)
+			if err := Do(t.Context(), tt.policy, fn, tt.opts...); !errors.Is(err, tt.err) {
+				t.Fatalf("Do = %v, want %v", err, tt.err)
+			}
+			if *calls != tt.wantCalls {
+				t.Fatalf("fn called %d times, want %d", *calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDo_WaitsOnTimers(t *testing.T) {
+	c := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	fn, calls := failing(1, After(errTransient, 30*time.Second))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Do(t.Context(), Policy{MaxAttempts: 2, BaseDelay: time.Millisecond, Jitter: -1}, fn, WithTimers(c))
+	}()
+	// the delay asked for with After replaces the backoff
+	c.BlockUntil(1)
+	c.Advance(30*time.Second - time.Nanosecond)
+	select {
+	case err := <-done:
+		t.Fatalf("Do returned %v before the requested delay", err)
+	default:
+	}
+	c.Advance(time.Nanosecond)
+	if err := <-done; err != nil || *calls != 2 {
+		t.Fatalf("Do = %v after %d calls, want nil after 2", err, *calls)
+	}
+}
+
+func TestDo_ContextEndsWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	err := Do(ctx, Policy{BaseDelay: time.Hour, Jitter: -1}, func(context.Context) error {
+		cancel()
+		return errTransient
+	})
+	if !errors.Is(err, errTransient) {
+		t.Fatalf("Do = %v, want the last error", err)
+	}
+
+	ctx, cancel = context.WithCancel(t.Context())
+	fn, _ := failing(100, errTransient)
+	time.AfterFunc(10*time.Millisecond, cancel)
+	err = Do(ctx, Policy{BaseDelay: time.Hour, Jitter: -1}, fn)
+	if !errors.Is(err, errTransient) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("Do = %v, want the last error joined with context.Canceled", err)
+	}
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	p := Policy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond, Jitter: 0.5}
+	for n, ceiling := range []time.Duration{10, 20, 40, 50, 50} {
+		ceiling *= time.Millisecond
+		for range 100 {
+			if d := p.Delay(n + 1); d < ceiling/2 || d > ceiling {
+				t.Fatalf("Delay(%d) = %s, want within [%s, %s]", n+1, d, ceiling/2, ceiling)
+			}
+		}
+	}
+	full := Policy{BaseDelay: time.Second, MaxDelay: time.Second}
+	for range 100 {
+		if d := full.Delay(40); d < 0 || d >= time.Second {
+			t.Fatalf("full jitter Delay = %s, want within [0, 1s)", d)
+		}
+	}
+}
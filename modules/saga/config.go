@@ -0,0 +1,38 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saga
+
+import "time"
+
+// Config configures the sagas of the service.
+type Config struct {
+	// Enabled runs the saga workers and the resume job.
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// Workers bounds the instances of each saga running at once on a node.
+	Workers int `env:"WORKERS" envDefault:"4"`
+	// LockTimeout bounds one run of an instance before another node may take over.
+	LockTimeout time.Duration `env:"LOCK_TIMEOUT" envDefault:"1m"`
+	// StaleAfter is how long an unfinished instance is left alone before it is resumed.
+	StaleAfter time.Duration `env:"STALE_AFTER" envDefault:"5m"`
+}
+
+// Options returns the saga options described by c.
+func (c Config) Options() []Option {
+	return []Option{
+		WithWorkers(c.Workers),
+		WithLockTimeout(c.LockTimeout),
+		WithStaleAfter(c.StaleAfter),
+	}
+}
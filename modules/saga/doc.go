@@ -0,0 +1,35 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package saga coordinates workflows spanning services without a distributed
+// transaction: each step commits on its own and a failure undoes the steps done so
+// far with their compensations.
+//
+//	signup, err := saga.New("signup", store, executor, []saga.Step[Signup]{
+//		{Name: "profile.create", Action: createProfile, Compensate: deleteProfile},
+//		{Name: "payment.open", Action: openAccount, Compensate: closeAccount},
+//	})
+//	signup.Start(ctx)
+//	id, err := signup.Begin(ctx, Signup{Username: "ada"})
+//
+// The state of every instance, its data and how far it got, is kept in a Store,
+// usually the Postgres table of modules/db/postgres/saga, and saved after each
+// step. The workers of a node run begun instances on a worker.BlockingPool, each
+// under a distributed lock of its own (see locking.LockingTaskExecutor).
+// Instances a node left behind when it stopped, or whose compensation failed, are
+// picked up by Resume, usually a scheduled job, on whichever node runs it.
+//
+// Because a step may run again after a crash, actions and compensations must be
+// idempotent. Keep the ids they create in the data so a rerun finds them.
+package saga
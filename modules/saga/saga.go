@@ -0,0 +1,446 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"app/modules/clock"
+	"app/modules/db/redis/locking"
+	"app/modules/worker"
+
+	"github.com/gofrs/uuid/v5"
+)
+
+var (
+	// ErrNotFound is returned by a Store for an instance it does not hold.
+	ErrNotFound = errors.New("saga: instance not found")
+	// ErrInvalid is returned by New for a saga that cannot run as declared.
+	ErrInvalid = errors.New("saga: invalid definition")
+	// ErrAlreadyStarted is returned by Start when the workers already run.
+	ErrAlreadyStarted = errors.New("saga: already started")
+)
+
+// Status is the state of a saga instance.
+type Status string
+
+const (
+	// StatusRunning instances still have steps to run.
+	StatusRunning Status = "running"
+	// StatusCompleted instances ran every step.
+	StatusCompleted Status = "completed"
+	// StatusCompensating instances had a step fail and undo the steps done so far.
+	StatusCompensating Status = "compensating"
+	// StatusCompensated instances were undone after a failure.
+	StatusCompensated Status = "compensated"
+)
+
+// Done reports whether s is a final status.
+func (s Status) Done() bool {
+	return s == StatusCompleted || s == StatusCompensated
+}
+
+type (
+	// Step is one local transaction of a saga, with the compensation undoing it.
+	Step[T any] struct {
+		Name string
+		// Action performs the step and may update data, which is saved once it
+		// returns. It runs again when the process stopped before that, so it
+		// must be idempotent, e.g. by using ids kept in data.
+		Action func(ctx context.Context, data *T) error
+		// Compensate undoes Action, nil for steps with nothing to undo. It is
+		// retried until it succeeds, so it must be idempotent too.
+		Compensate func(ctx context.Context, data *T) error
+	}
+
+	// Instance is the persisted state of one run of a saga.
+	Instance struct {
+		ID     uuid.UUID
+		Saga   string
+		Status Status
+		// Step counts the steps done while running, and the steps left to
+		// compensate while compensating.
+		Step int
+		// Data is the JSON encoded data passed to the steps.
+		Data json.RawMessage
+		// Error describes the failure that made the saga compensate.
+		Error     string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+
+	// Store persists saga instances.
+	Store interface {
+		Create(ctx context.Context, inst Instance) error
+		// Get returns ErrNotFound for an unknown id.
+		Get(ctx context.Context, id uuid.UUID) (Instance, error)
+		// Update returns ErrNotFound for an unknown id.
+		Update(ctx context.Context, inst Instance) error
+		// Unfinished returns up to limit instances of saga that are running or
+		// compensating and were not updated since before, oldest first.
+		Unfinished(ctx context.Context, saga string, before time.Time, limit int) ([]Instance, error)
+	}
+
+	// Executor runs a task under a distributed lock, see locking.LockingTaskExecutor.
+	Executor interface {
+		Execute(ctx context.Context, cfg locking.LockConfiguration, task locking.TaskFunc) error
+	}
+
+	// Saga orchestrates the steps of a workflow spanning services over data of type T.
+	//
+	// Every instance runs under a distributed lock of its own, so a node resuming it
+	// never races the node still running it, and its state is saved after each step.
+	Saga[T any] struct {
+		name     string
+		steps    []Step[T]
+		store    Store
+		executor Executor
+		cfg      config
+
+		queue chan uuid.UUID
+
+		mu     sync.Mutex
+		cancel context.CancelFunc
+		done   chan struct{}
+	}
+
+	// Option configures a Saga.
+	Option func(*config)
+
+	config struct {
+		workers   int
+		queueSize int
+
+		lockAtMostFor time.Duration
+		staleAfter    time.Duration
+		resumeBatch   int
+
+		clock clock.Clock
+	}
+)
+
+// WithWorkers sets how many instances run at once on this node. Defaults to 4.
+func WithWorkers(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithQueueSize sets how many begun instances may wait for a worker. Instances
+// begun while it is full wait for Resume. Defaults to 64.
+func WithQueueSize(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.queueSize = n
+		}
+	}
+}
+
+// WithLockTimeout bounds one run of an instance, after which its lock expires and
+// another node may resume it. Defaults to a minute.
+func WithLockTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.lockAtMostFor = d
+		}
+	}
+}
+
+// WithStaleAfter sets how long an unfinished instance must have been left alone
+// before Resume picks it up. Defaults to 5 minutes.
+func WithStaleAfter(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.staleAfter = d
+		}
+	}
+}
+
+// WithResumeBatch caps the instances a single Resume queues. Defaults to 100.
+func WithResumeBatch(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.resumeBatch = n
+		}
+	}
+}
+
+// WithClock overrides the time source (useful in tests).
+func WithClock(cl clock.Clock) Option {
+	return func(c *config) {
+		if cl != nil {
+			c.clock = cl
+		}
+	}
+}
+
+// New declares the saga name running steps in order. The name is persisted with
+// every instance and must stay stable, like the order of the steps: instances
+// left unfinished resume by step index.
+func New[T any](name string, store Store, executor Executor, steps []Step[T], opts ...Option) (*Saga[T], error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: name must not be empty", ErrInvalid)
+	}
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("%w: saga %q has no steps", ErrInvalid, name)
+	}
+	for i, step := range steps {
+		if step.Name == "" || step.Action == nil {
+			return nil, fmt.Errorf("%w: saga %q: step %d needs a name and an action", ErrInvalid, name, i)
+		}
+	}
+
+	cfg := config{
+		workers:       4,
+		queueSize:     64,
+		lockAtMostFor: time.Minute,
+		staleAfter:    5 * time.Minute,
+		resumeBatch:   100,
+		clock:         clock.RealClock{},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	return &Saga[T]{
+		name:     name,
+		steps:    steps,
+		store:    store,
+		executor: executor,
+		cfg:      cfg,
+		queue:    make(chan uuid.UUID, cfg.queueSize),
+	}, nil
+}
+
+// Name returns the name of the saga.
+func (s *Saga[T]) Name() string { return s.name }
+
+// Begin persists a new instance over data and queues it for the workers.
+func (s *Saga[T]) Begin(ctx context.Context, data T) (uuid.UUID, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("saga %q: generate id: %w", s.name, err)
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("saga %q: encode data: %w", s.name, err)
+	}
+	now := s.cfg.clock.Now().UTC()
+	inst := Instance{
+		ID:        id,
+		Saga:      s.name,
+		Status:    StatusRunning,
+		Data:      encoded,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.Create(ctx, inst); err != nil {
+		return uuid.Nil, fmt.Errorf("saga %q: create instance: %w", s.name, err)
+	}
+
+	select {
+	case s.queue <- id:
+	default:
+		slog.WarnContext(ctx, "saga: queue full, instance left to resume",
+			slog.String("saga", s.name),
+			slog.String("saga.id", id.String()),
+		)
+	}
+	return id, nil
+}
+
+// Get returns the instance id with its decoded data.
+func (s *Saga[T]) Get(ctx context.Context, id uuid.UUID) (Instance, T, error) {
+	var data T
+	inst, err := s.store.Get(ctx, id)
+	if err != nil {
+		return Instance{}, data, err
+	}
+	if err := json.Unmarshal(inst.Data, &data); err != nil {
+		return Instance{}, data, fmt.Errorf("saga %q: decode data of %s: %w", s.name, id, err)
+	}
+	return inst, data, nil
+}
+
+// Run drives instance id under its lock until it is completed or compensated.
+//
+// Steps run in order, each saved once done. When one fails, the saga turns to
+// compensating and undoes the steps in reverse, the failed one included since it
+// may have taken effect before failing. A failing compensation is returned and
+// retried on the next run. A cancelled ctx stops the instance where it is,
+// without compensating, for a later run to resume.
+//
+// Run returns locking.ErrLockNotAcquired while another node runs the instance.
+func (s *Saga[T]) Run(ctx context.Context, id uuid.UUID) error {
+	return s.executor.Execute(ctx, locking.LockConfiguration{
+		Name:          "saga:" + s.name + ":" + id.String(),
+		LockAtMostFor: s.cfg.lockAtMostFor,
+	}, func(ctx context.Context) error {
+		return s.run(ctx, id)
+	})
+}
+
+func (s *Saga[T]) run(ctx context.Context, id uuid.UUID) error {
+	inst, data, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if inst.Saga != s.name {
+		return fmt.Errorf("saga %q: instance %s belongs to saga %q", s.name, id, inst.Saga)
+	}
+
+	for inst.Status == StatusRunning {
+		if inst.Step >= len(s.steps) {
+			inst.Status = StatusCompleted
+		} else {
+			step := s.steps[inst.Step]
+			err := step.Action(ctx, &data)
+			if err != nil && ctx.Err() != nil {
+				return fmt.Errorf("saga %q: step %q interrupted: %w", s.name, step.Name, err)
+			}
+			inst.Step++
+			if err != nil {
+				slog.WarnContext(ctx, "saga: step failed, compensating",
+					slog.String("saga", s.name),
+					slog.String("saga.id", id.String()),
+					slog.String("saga.step", step.Name),
+					slog.Any("error", err),
+				)
+				inst.Status = StatusCompensating
+				inst.Error = step.Name + ": " + err.Error()
+			}
+		}
+		if err := s.save(ctx, &inst, data); err != nil {
+			return err
+		}
+	}
+
+	for inst.Status == StatusCompensating {
+		if inst.Step <= 0 {
+			inst.Status = StatusCompensated
+		} else {
+			step := s.steps[inst.Step-1]
+			if step.Compensate != nil {
+				if err := step.Compensate(ctx, &data); err != nil {
+					return fmt.Errorf("saga %q: compensate step %q of %s: %w", s.name, step.Name, id, err)
+				}
+			}
+			inst.Step--
+		}
+		if err := s.save(ctx, &inst, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Saga[T]) save(ctx context.Context, inst *Instance, data T) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("saga %q: encode data of %s: %w", s.name, inst.ID, err)
+	}
+	inst.Data = encoded
+	inst.UpdatedAt = s.cfg.clock.Now().UTC()
+	if err := s.store.Update(ctx, *inst); err != nil {
+		return fmt.Errorf("saga %q: save %s: %w", s.name, inst.ID, err)
+	}
+	return nil
+}
+
+// Start runs the queued instances on a worker.BlockingPool until Stop.
+func (s *Saga[T]) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return ErrAlreadyStarted
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.done = make(chan struct{})
+	go func() {
+		defer close(s.done)
+		worker.BlockingPool(ctx, s.cfg.workers, s.queue, func(ctx context.Context, id uuid.UUID) {
+			err := s.Run(ctx, id)
+			switch {
+			case err == nil, errors.Is(err, locking.ErrLockNotAcquired):
+			case ctx.Err() != nil:
+				slog.InfoContext(ctx, "saga: instance interrupted by shutdown",
+					slog.String("saga", s.name),
+					slog.String("saga.id", id.String()),
+				)
+			default:
+				slog.ErrorContext(ctx, "saga: run failed",
+					slog.String("saga", s.name),
+					slog.String("saga.id", id.String()),
+					slog.Any("error", err),
+				)
+			}
+		})
+	}()
+	return nil
+}
+
+// Stop cancels the running instances, which later resume where they stopped, and
+// waits for the workers to return or ctx to be done.
+func (s *Saga[T]) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Resume queues the instances left unfinished for longer than the stale delay,
+// e.g. by a node that stopped or a compensation that failed, and returns how many
+// it queued. It blocks while the queue is full, so the workers must be started.
+func (s *Saga[T]) Resume(ctx context.Context) (int, error) {
+	before := s.cfg.clock.Now().Add(-s.cfg.staleAfter)
+	insts, err := s.store.Unfinished(ctx, s.name, before, s.cfg.resumeBatch)
+	if err != nil {
+		return 0, fmt.Errorf("saga %q: list unfinished: %w", s.name, err)
+	}
+	for i, inst := range insts {
+		select {
+		case s.queue <- inst.ID:
+		case <-ctx.Done():
+			return i, ctx.Err()
+		}
+	}
+	if len(insts) > 0 {
+		slog.InfoContext(ctx, "saga: resumed unfinished instances",
+			slog.String("saga", s.name),
+			slog.Int("count", len(insts)),
+		)
+	}
+	return len(insts), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import "time"
+
+// Router selects the generated server a service mounts its routes with.
+type Router string
+
+const (
+	// RouterStdlib mounts the oapi-codegen net/http server directly on the ServeMux.
+	RouterStdlib Router = "stdlib"
+	// RouterEcho mounts the oapi-codegen echo server, itself mounted on the ServeMux.
+	RouterEcho Router = "echo"
+)
+
+// Config configures the HTTP transport.
+type Config struct {
+	// Router switches between the generated routers without a rebuild, to compare
+	// them or migrate one service at a time.
+	Router Router `env:"ROUTER" envDefault:"stdlib"`
+
+	// Connection limits, see WithReadHeaderTimeout, WithIdleTimeout and WithMaxHeaderBytes.
+	ReadHeaderTimeout time.Duration `env:"READ_HEADER_TIMEOUT" envDefault:"5s"`
+	IdleTimeout       time.Duration `env:"IDLE_TIMEOUT" envDefault:"120s"`
+	MaxHeaderBytes    int           `env:"MAX_HEADER_BYTES" envDefault:"1048576"`
+
+	// V1 deprecates or retires the v1 API, see Versioned.
+	V1 VersionConfig `envPrefix:"V1_"`
+
+	// TLS serves HTTPS directly, see TLSConfig.
+	TLS TLSConfig `envPrefix:"TLS_"`
+
+	// H2C serves HTTP/2 over cleartext connections too, see WithH2C.
+	H2C bool `env:"H2C"`
+	// HTTP3Addr serves HTTP/3 on that UDP address too, e.g. ":8443", see WithHTTP3.
+	HTTP3Addr string `env:"HTTP3_ADDR"`
+}
@@ -0,0 +1,24 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package echoserver hosts server.RegistrableService implementations on an echo
+// router, for teams preferring echo to the generated net/http server.
+//
+// A Service is itself a RegistrableService mounted by server.New, so TLS, timeouts,
+// protocols and draining stay with the server module. Services implementing
+// EchoService add their routes to the shared echo instance, the others keep their
+// ServeMux behind echo's not-found route. Their middlewares, including route
+// groups, run as echo middlewares through Middleware, and errors returned by
+// handlers are written as problems.
+package echoserver
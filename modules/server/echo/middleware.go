@@ -0,0 +1,70 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echoserver
+
+import (
+	"errors"
+	"net/http"
+
+	"app/modules/middleware/problem"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware runs a net/http middleware as an echo middleware.
+//
+// Unlike echo.WrapMiddleware, errors of the inner handlers are written inside mw,
+// so middlewares inspecting the response (telemetry, access logs) see the problem
+// and not an empty response.
+func Middleware(mw func(http.Handler) http.Handler) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				if w != http.ResponseWriter(c.Response()) {
+					c.SetResponse(echo.NewResponse(w, c.Echo()))
+				}
+				if err := next(c); err != nil {
+					c.Error(err)
+				}
+			})).ServeHTTP(c.Response(), c.Request())
+			return nil
+		}
+	}
+}
+
+// ErrorHandler writes handler errors as problems: the status and message of an
+// echo.HTTPError (route not found, binding failures), 500 otherwise.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	status, detail := http.StatusInternalServerError, "internal server error"
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		status = he.Code
+		if msg, ok := he.Message.(string); ok && status < http.StatusInternalServerError {
+			detail = msg
+		} else {
+			detail = http.StatusText(status)
+		}
+	}
+	problem.Write(c.Response(), problem.New(
+		problem.WithStatus(status),
+		problem.WithTitle(http.StatusText(status)),
+		problem.WithDetail(detail),
+		problem.FromRequest(c.Request()),
+	))
+}
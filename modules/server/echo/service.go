@@ -0,0 +1,176 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package echoserver
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"app/modules/server"
+
+	"github.com/labstack/echo/v4"
+)
+
+var _ server.RegistrableService = (*Service)(nil)
+
+type (
+	// EchoService is a server.RegistrableService that registers its routes on echo
+	// directly. Service calls RegisterEcho instead of Register.
+	EchoService interface {
+		server.RegistrableService
+		RegisterEcho(e *echo.Echo)
+	}
+
+	// Service serves its services through one echo instance.
+	Service struct {
+		e           *echo.Echo
+		services    []server.RegistrableService
+		middlewares []echo.MiddlewareFunc
+	}
+
+	// Option configures a Service.
+	Option func(*Service)
+)
+
+// WithServices adds services to the echo instance.
+func WithServices(svcs ...server.RegistrableService) Option {
+	return func(s *Service) {
+		s.services = append(s.services, svcs...)
+	}
+}
+
+// WithMiddlewares adds echo middlewares, in the order provided, wrapping the
+// middlewares of the services.
+func WithMiddlewares(mw ...echo.MiddlewareFunc) Option {
+	return func(s *Service) {
+		s.middlewares = append(s.middlewares, mw...)
+	}
+}
+
+// WithErrorHandler replaces ErrorHandler for the errors returned by handlers.
+func WithErrorHandler(h echo.HTTPErrorHandler) Option {
+	return func(s *Service) {
+		if h != nil {
+			s.e.HTTPErrorHandler = h
+		}
+	}
+}
+
+// New constructs a Service with a fresh echo instance.
+func New(opts ...Option) *Service {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = ErrorHandler
+	s := &Service{e: e}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(s)
+		}
+	}
+	return s
+}
+
+// Echo returns the echo instance, e.g. to add routes not owned by a service.
+func (s *Service) Echo() *echo.Echo {
+	return s.e
+}
+
+// Register mounts the services on the echo instance and the echo instance under
+// "/" of mux, so services registered on mux with more specific patterns keep
+// their routes.
+func (s *Service) Register(mux *http.ServeMux) {
+	// first, so the access log sees the echo route even when a middleware below
+	// replaces the request
+	s.e.Use(notePattern)
+	s.e.Use(s.middlewares...)
+
+	var fallback *http.ServeMux
+	var groups []server.RouteGroup
+	for _, svc := range s.services {
+		if es, ok := svc.(EchoService); ok {
+			es.RegisterEcho(s.e)
+		} else {
+			if fallback == nil {
+				fallback = http.NewServeMux()
+			}
+			svc.Register(fallback)
+		}
+		for _, mw := range svc.Middlewares() {
+			s.e.Use(Middleware(mw))
+		}
+		if g, ok := svc.(server.GroupedService); ok {
+			groups = append(groups, g.RouteGroups()...)
+		}
+	}
+	// shorter prefixes first, so nested groups compose outermost first
+	slices.SortStableFunc(groups, func(a, b server.RouteGroup) int {
+		return len(strings.TrimSuffix(a.Prefix, "/")) - len(strings.TrimSuffix(b.Prefix, "/"))
+	})
+	for _, g := range groups {
+		for _, mw := range g.Middlewares {
+			s.e.Use(groupMiddleware(g, Middleware(mw)))
+		}
+	}
+	if fallback != nil {
+		s.e.RouteNotFound("/*", echo.WrapHandler(fallback))
+	}
+
+	mux.Handle("/", s.e)
+}
+
+// Middlewares returns nil: the service middlewares run inside echo.
+func (s *Service) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
+
+// groupMiddleware runs mw only for requests under the prefix of g.
+func groupMiddleware(g server.RouteGroup, mw echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		wrapped := mw(next)
+		return func(c echo.Context) error {
+			if g.Matches(c.Request().URL.Path) {
+				return wrapped(c)
+			}
+			return next(c)
+		}
+	}
+}
+
+// notePattern sets the request pattern to the echo route, "GET /profiles/{id}",
+// as ServeMux does for its routes, for middleware.NoteRoute.
+func notePattern(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if path := c.Path(); path != "" {
+			c.Request().Pattern = c.Request().Method + " " + muxPath(path)
+		}
+		return next(c)
+	}
+}
+
+// muxPath rewrites echo path parameters, ":id" and "*", the ServeMux way.
+func muxPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, seg := range segments {
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			segments[i] = "{" + seg[1:] + "}"
+		case seg == "*":
+			segments[i] = "{path...}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
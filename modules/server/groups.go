@@ -0,0 +1,107 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// groupRoutes wraps h with the chains of groups, see RouteGroup.
+func groupRoutes(h http.Handler, groups []RouteGroup) http.Handler {
+	if len(groups) == 0 {
+		return h
+	}
+	// merge groups of the same prefix, keeping the declaration order
+	byPrefix := make(map[string]*RouteGroup, len(groups))
+	var merged []*RouteGroup
+	for _, g := range groups {
+		prefix := groupPrefix(g.Prefix)
+		if m, ok := byPrefix[prefix]; ok {
+			m.Middlewares = append(m.Middlewares, g.Middlewares...)
+			continue
+		}
+		m := &RouteGroup{Prefix: prefix, Middlewares: slices.Clone(g.Middlewares)}
+		byPrefix[prefix] = m
+		merged = append(merged, m)
+	}
+	// a prefix is always shorter than the ones nested below it
+	slices.SortStableFunc(merged, func(a, b *RouteGroup) int {
+		return len(a.Prefix) - len(b.Prefix)
+	})
+	return buildGroups(h, merged)
+}
+
+// buildGroups nests every group under the shortest one containing it, so each
+// level dispatches to disjoint prefixes. groups must be sorted by prefix length.
+func buildGroups(h http.Handler, groups []*RouteGroup) http.Handler {
+	if len(groups) == 0 {
+		return h
+	}
+	d := &groupDispatcher{next: h}
+	for i, g := range groups {
+		if slices.ContainsFunc(groups[:i], func(o *RouteGroup) bool { return underPrefix(g.Prefix, o.Prefix) }) {
+			continue
+		}
+		var nested []*RouteGroup
+		for _, o := range groups[i+1:] {
+			if underPrefix(o.Prefix, g.Prefix) {
+				nested = append(nested, o)
+			}
+		}
+		d.prefixes = append(d.prefixes, g.Prefix)
+		d.handlers = append(d.handlers, chain(buildGroups(h, nested), g.Middlewares))
+	}
+	return d
+}
+
+type groupDispatcher struct {
+	next     http.Handler
+	prefixes []string
+	handlers []http.Handler
+}
+
+func (d *groupDispatcher) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	for i, prefix := range d.prefixes {
+		if underPrefix(r.URL.Path, prefix) {
+			d.handlers[i].ServeHTTP(w, r)
+			return
+		}
+	}
+	d.next.ServeHTTP(w, r)
+}
+
+// Matches reports whether path is the prefix of g or below it.
+func (g RouteGroup) Matches(path string) bool {
+	return underPrefix(path, groupPrefix(g.Prefix))
+}
+
+// groupPrefix normalizes a prefix to a path without a trailing slash, "" for the root.
+func groupPrefix(prefix string) string {
+	prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return prefix
+}
+
+// underPrefix reports whether path is prefix or a path below it.
+func underPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
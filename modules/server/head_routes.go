@@ -0,0 +1,78 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// HeadRoutes is a ServeMux adapter for generated servers that register explicit
+// HEAD routes.
+//
+// A "GET" pattern also matches HEAD requests, so "HEAD /items/{id}" registered next
+// to "GET /items/count" conflicts (neither is more specific) and ServeMux panics.
+// HeadRoutes instead folds every HEAD handler into the GET route of the same path
+// and dispatches on the method at request time. Call Flush once registration is done.
+type HeadRoutes struct {
+	mux   *http.ServeMux
+	heads map[string]http.HandlerFunc
+	gets  map[string]bool
+}
+
+func NewHeadRoutes(mux *http.ServeMux) *HeadRoutes {
+	return &HeadRoutes{
+		mux:   mux,
+		heads: make(map[string]http.HandlerFunc),
+		gets:  make(map[string]bool),
+	}
+}
+
+func (m *HeadRoutes) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	method, path, ok := strings.Cut(pattern, " ")
+	if !ok {
+		m.mux.HandleFunc(pattern, handler)
+		return
+	}
+	switch method {
+	case http.MethodHead:
+		m.heads[path] = handler
+	case http.MethodGet:
+		m.gets[path] = true
+		m.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			// heads is complete once serving starts, reads need no locking
+			if head := m.heads[path]; head != nil && r.Method == http.MethodHead {
+				head(w, r)
+				return
+			}
+			handler(w, r)
+		})
+	default:
+		m.mux.HandleFunc(pattern, handler)
+	}
+}
+
+func (m *HeadRoutes) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mux.ServeHTTP(w, r)
+}
+
+// Flush registers the HEAD routes that have no GET route to fold into.
+func (m *HeadRoutes) Flush() {
+	for path, head := range m.heads {
+		if !m.gets[path] {
+			m.mux.HandleFunc(http.MethodHead+" "+path, head)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// WithH2C also serves HTTP/2 over cleartext connections (prior knowledge, RFC 9113
+// section 3.3), for gRPC-web and streaming clients behind a proxy that speaks
+// HTTP/2 to its backends without TLS.
+func WithH2C() ServerOptions {
+	return func(s *Server) {
+		s.h2c = true
+	}
+}
+
+// WithHTTP3 also serves HTTP/3 over QUIC on the UDP address quicAddr, and
+// advertises it to TCP clients with an Alt-Svc header. It requires TLS. An empty
+// address leaves HTTP/3 off.
+//
+// Experimental: the server never reads HTTP/3 requests without the edge or the
+// client choosing to, and the QUIC stack is a third party one.
+func WithHTTP3(quicAddr string) ServerOptions {
+	return func(s *Server) {
+		s.http3Addr = quicAddr
+	}
+}
+
+// WithProtocolsFromConfig applies WithH2C and WithHTTP3 as configured by cfg.
+func WithProtocolsFromConfig(cfg Config) ServerOptions {
+	return func(s *Server) {
+		if cfg.H2C {
+			WithH2C()(s)
+		}
+		WithHTTP3(cfg.HTTP3Addr)(s)
+	}
+}
+
+// buildProtocols enables the protocols of WithH2C and WithHTTP3. Callers run it
+// after buildTLS and once the handler is set.
+func (s *Server) buildProtocols() error {
+	if s.h2c {
+		p := new(http.Protocols)
+		p.SetHTTP1(true)
+		p.SetHTTP2(true)
+		p.SetUnencryptedHTTP2(true)
+		s.server.Protocols = p
+	}
+
+	if s.http3Addr == "" {
+		return nil
+	}
+	if s.server.TLSConfig == nil {
+		return errors.New("server: http3 requires tls")
+	}
+	s.h3 = &http3.Server{
+		Addr:      s.http3Addr,
+		TLSConfig: http3.ConfigureTLSConfig(s.server.TLSConfig),
+		Handler:   s.server.Handler,
+	}
+	next := s.server.Handler
+	s.server.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// tells TCP clients where to find the QUIC listener for their next requests
+		_ = s.h3.SetQUICHeaders(w.Header())
+		next.ServeHTTP(w, r)
+	})
+	return nil
+}
+
+// startHTTP3 binds the QUIC listener and serves in the background.
+func (s *Server) startHTTP3(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", s.h3.Addr)
+	if err != nil {
+		return fmt.Errorf("server: listen udp %s: %w", s.h3.Addr, err)
+	}
+	s.h3Conn = conn
+	slog.InfoContext(ctx, "started http3 server", slog.String("addr", conn.LocalAddr().String()))
+
+	go func() {
+		if err := s.h3.Serve(conn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.reportErr(fmt.Errorf("server: http3: %w", err))
+		}
+	}()
+	return nil
+}
+
+// shutdownHTTP3 stops the QUIC listener, waiting for in-flight requests until ctx is done.
+func (s *Server) shutdownHTTP3(ctx context.Context) error {
+	err := s.h3.Shutdown(ctx)
+	// the server does not own the packet conn it was given
+	if s.h3Conn != nil {
+		err = errors.Join(err, s.h3Conn.Close())
+	}
+	return err
+}
@@ -24,3 +24,23 @@ type RegistrableService interface {
 	// Middlewares returns server-level middlewares required when this service is active.
 	Middlewares() []func(http.Handler) http.Handler
 }
+
+// GroupedService is a RegistrableService that also scopes middlewares to the routes
+// under a path prefix, e.g. authentication on "/v1/profiles" only.
+type GroupedService interface {
+	RegistrableService
+	// RouteGroups returns the middleware chains of the service's route prefixes.
+	RouteGroups() []RouteGroup
+}
+
+// RouteGroup applies Middlewares, the first one outermost, to the requests whose
+// path is Prefix or below it: "/v1/profiles" matches "/v1/profiles/{id}" but not
+// "/v1/profiles-archive".
+//
+// The chains run inside the global middlewares, before routing. Nested prefixes
+// compose, the shorter one outermost, and groups of the same prefix are chained in
+// the order they were declared.
+type RouteGroup struct {
+	Prefix      string
+	Middlewares []func(http.Handler) http.Handler
+}
@@ -16,16 +16,28 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"strconv"
 	"time"
+
+	"github.com/quic-go/quic-go/http3"
 )
 
 const MAX_TCP_PORT = 1 << 16 // A TCP header uses a 16-bit field for port numbers
 
+// Connection limits applied unless overridden by the matching option.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultIdleTimeout       = 120 * time.Second
+	DefaultMaxHeaderBytes    = 1 << 20
+)
+
 type (
 	Server struct {
 		server *http.Server
@@ -38,6 +50,25 @@ type (
 
 		// registrable services that mount routes and provide their own middlewares
 		services []RegistrableService
+
+		// fatal errors from the background serve loop
+		errCh chan error
+
+		// TLS, merged into server.TLSConfig by New, see WithTLS
+		tlsConfig    *tls.Config
+		certificates []tls.Certificate
+		clientCAs    *x509.CertPool
+		clientAuth   tls.ClientAuthType
+		redirectAddr string
+		redirect     *http.Server
+		// errors of options that load files, reported by New
+		optErrs []error
+
+		// extra protocols, see WithH2C and WithHTTP3
+		h2c       bool
+		http3Addr string
+		h3        *http3.Server
+		h3Conn    net.PacketConn
 	}
 
 	ServerOptions func(*Server)
@@ -63,6 +94,43 @@ func WithReadTimeout(t time.Duration) ServerOptions {
 	}
 }
 
+// WithReadHeaderTimeout bounds how long a client may take to send the request
+// headers. Zero keeps the default of DefaultReadHeaderTimeout; without it a slow
+// client could hold a connection open indefinitely (slowloris).
+func WithReadHeaderTimeout(t time.Duration) ServerOptions {
+	return func(s *Server) {
+		if t > 0 {
+			s.server.ReadHeaderTimeout = t
+		} else {
+			s.server.ReadHeaderTimeout = DefaultReadHeaderTimeout
+		}
+	}
+}
+
+// WithIdleTimeout bounds how long a keep-alive connection waits for the next
+// request. Zero keeps the default of DefaultIdleTimeout.
+func WithIdleTimeout(t time.Duration) ServerOptions {
+	return func(s *Server) {
+		if t > 0 {
+			s.server.IdleTimeout = t
+		} else {
+			s.server.IdleTimeout = DefaultIdleTimeout
+		}
+	}
+}
+
+// WithMaxHeaderBytes caps the size of the request line and headers. Zero keeps
+// the default of DefaultMaxHeaderBytes.
+func WithMaxHeaderBytes(n int) ServerOptions {
+	return func(s *Server) {
+		if n > 0 {
+			s.server.MaxHeaderBytes = n
+		} else {
+			s.server.MaxHeaderBytes = DefaultMaxHeaderBytes
+		}
+	}
+}
+
 // WithServices registers a collection of self-contained, registrable services.
 func WithServices(svcs ...RegistrableService) ServerOptions {
 	return func(s *Server) {
@@ -98,12 +166,16 @@ func New(host string, port int, opts ...ServerOptions) (*Server, error) {
 		return nil, fmt.Errorf("bad port")
 	}
 	s := &Server{
-		host: host,
-		port: uint16(port),
+		host:  host,
+		port:  uint16(port),
+		errCh: make(chan error, 1),
 	}
 
 	s.server = &http.Server{
-		Addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		IdleTimeout:       DefaultIdleTimeout,
+		MaxHeaderBytes:    DefaultMaxHeaderBytes,
 	}
 	// Allocate a base mux before applying options so options can register routes.
 	s.mux = http.NewServeMux()
@@ -111,52 +183,133 @@ func New(host string, port int, opts ...ServerOptions) (*Server, error) {
 	for _, opt := range opts {
 		opt(s)
 	}
+	if err := s.buildTLS(); err != nil {
+		return nil, err
+	}
 
-	// Register all services and collect their required global middlewares.
+	// Register all services and collect their required global and route group middlewares.
+	var groups []RouteGroup
 	for _, svc := range s.services {
 		svc.Register(s.mux)
 		s.middlewares = append(s.middlewares, svc.Middlewares()...)
+		if g, ok := svc.(GroupedService); ok {
+			groups = append(groups, g.RouteGroups()...)
+		}
 		slog.Info("registered service", slog.String("type", fmt.Sprintf("%T", svc)))
 	}
 
 	// Build handler chain: middlewares wrap the mux in declaration order.
-	handler := http.Handler(s.mux)
-	for i := len(s.middlewares) - 1; i >= 0; i-- {
-		handler = s.middlewares[i](handler)
+	// Consumers can add recover/logging via options.
+	s.server.Handler = chain(groupRoutes(s.mux, groups), s.middlewares)
+	if err := s.buildProtocols(); err != nil {
+		return nil, err
 	}
-	// Attach the composed handler chain. Consumers can add recover/logging via options.
-	s.server.Handler = handler
 
 	return s, nil
 }
 
-func (s *Server) Run(ctx context.Context) error {
-	done := make(chan struct{}, 1)
-	errCh := make(chan error, 1)
-	go func() {
-		slog.InfoContext(ctx, "started server", slog.Any("host", s.host), slog.Any("port", s.port))
-		if err := s.server.ListenAndServe(); err != nil {
-			errCh <- err
-			return
+// Start binds the listener and serves in the background.
+//
+// Bind errors are returned synchronously; errors while serving are reported on Err.
+func (s *Server) Start(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("server: listen %s: %w", s.server.Addr, err)
+	}
+	var redirectLn net.Listener
+	if s.redirect != nil {
+		if redirectLn, err = net.Listen("tcp", s.redirect.Addr); err != nil {
+			_ = ln.Close()
+			return fmt.Errorf("server: listen %s: %w", s.redirect.Addr, err)
 		}
-	}()
+	}
+	if s.h3 != nil {
+		if err := s.startHTTP3(ctx); err != nil {
+			_ = ln.Close()
+			if redirectLn != nil {
+				_ = redirectLn.Close()
+			}
+			return err
+		}
+	}
+	tlsOn := s.server.TLSConfig != nil
+	slog.InfoContext(ctx, "started server", slog.Any("host", s.host), slog.Any("port", s.port), slog.Bool("tls", tlsOn))
 
 	go func() {
-		for {
-			select {
-			case e := <-errCh:
-				slog.ErrorContext(ctx, "server error", slog.Any("error", e))
-				done <- struct{}{}
-			case <-ctx.Done():
-				done <- struct{}{}
-			}
+		serve := s.server.Serve
+		if tlsOn {
+			// certificates come from TLSConfig; ServeTLS adds the h2 ALPN protocol
+			serve = func(ln net.Listener) error { return s.server.ServeTLS(ln, "", "") }
+		}
+		if err := serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.reportErr(err)
 		}
 	}()
+	if redirectLn != nil {
+		slog.InfoContext(ctx, "started https redirect", slog.String("addr", s.redirect.Addr))
+		go func() {
+			if err := s.redirect.Serve(redirectLn); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.reportErr(fmt.Errorf("server: https redirect: %w", err))
+			}
+		}()
+	}
+	return nil
+}
 
-	<-done
+// reportErr reports a serve loop failure on Err, keeping the first one.
+func (s *Server) reportErr(err error) {
+	select {
+	case s.errCh <- err:
+	default:
+	}
+}
+
+// Addr returns the configured listen address (host:port).
+func (s *Server) Addr() string {
+	return s.server.Addr
+}
+
+// Err reports a fatal error from the background serve loop started by Start.
+func (s *Server) Err() <-chan error {
+	return s.errCh
+}
+
+// Drain stops keeping connections alive: every response closes its connection,
+// so clients reconnect, through the load balancer, to other instances. Requests
+// are still served until Shutdown.
+func (s *Server) Drain() {
+	slog.Info("server draining, keep-alives disabled")
+	s.server.SetKeepAlivesEnabled(false)
+}
+
+// Shutdown gracefully stops the server, waiting for in-flight requests until ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
 	slog.InfoContext(ctx, "shutting down...")
-	dCtx, dCancel := context.WithTimeout(ctx, 10*time.Second)
+	errs := []error{s.server.Shutdown(ctx)}
+	if s.redirect != nil {
+		errs = append(errs, s.redirect.Shutdown(ctx))
+	}
+	if s.h3 != nil {
+		errs = append(errs, s.shutdownHTTP3(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// Run starts the server and blocks until ctx is cancelled or serving fails,
+// then shuts down gracefully.
+func (s *Server) Run(ctx context.Context) error {
+	if err := s.Start(ctx); err != nil {
+		return err
+	}
+
+	select {
+	case e := <-s.errCh:
+		slog.ErrorContext(ctx, "server error", slog.Any("error", e))
+	case <-ctx.Done():
+	}
+
+	// allows 10 seconds for graceful shutdown, ctx itself is most likely cancelled by now
+	dCtx, dCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
 	defer dCancel()
-	// allows 10 seconds for graceful shutdown
-	return s.server.Shutdown(dCtx)
+	return s.Shutdown(dCtx)
 }
@@ -0,0 +1,172 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// TLSConfig serves HTTPS, optionally with mutual TLS, without a terminating proxy.
+type TLSConfig struct {
+	// CertFile and KeyFile hold the PEM certificate chain and key; TLS is off
+	// while CertFile is empty.
+	CertFile string `env:"CERT_FILE"`
+	KeyFile  string `env:"KEY_FILE"`
+
+	// ClientCAFile enables mutual TLS: client certificates must chain to one of its
+	// PEM certificates.
+	ClientCAFile string `env:"CLIENT_CA_FILE"`
+	// ClientCertRequired rejects clients without a certificate; otherwise a
+	// certificate is only verified when presented.
+	ClientCertRequired bool `env:"CLIENT_CERT_REQUIRED" envDefault:"true"`
+
+	// RedirectAddr, when set, listens there for plain HTTP and redirects every
+	// request to HTTPS, e.g. ":80".
+	RedirectAddr string `env:"REDIRECT_ADDR"`
+}
+
+// Enabled reports whether a certificate is configured.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+// WithTLSFromConfig applies WithTLS, WithClientCAFile and WithHTTPSRedirect as
+// configured by cfg. It does nothing while TLS is disabled.
+func WithTLSFromConfig(cfg TLSConfig) ServerOptions {
+	return func(s *Server) {
+		if !cfg.Enabled() {
+			return
+		}
+		WithTLS(cfg.CertFile, cfg.KeyFile)(s)
+		if cfg.ClientCAFile != "" {
+			WithClientCAFile(cfg.ClientCAFile, cfg.ClientCertRequired)(s)
+		}
+		if cfg.RedirectAddr != "" {
+			WithHTTPSRedirect(cfg.RedirectAddr)(s)
+		}
+	}
+}
+
+// WithTLSConfig serves HTTPS with a copy of cfg. It is merged with WithTLS and
+// WithClientCAFile, which fill in what cfg leaves empty.
+func WithTLSConfig(cfg *tls.Config) ServerOptions {
+	return func(s *Server) {
+		if cfg != nil {
+			s.tlsConfig = cfg.Clone()
+		}
+	}
+}
+
+// WithTLS serves HTTPS with the PEM certificate chain and key of the given files.
+// A file that cannot be loaded makes New fail.
+func WithTLS(certFile, keyFile string) ServerOptions {
+	return func(s *Server) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			s.optErrs = append(s.optErrs, fmt.Errorf("server: load tls key pair: %w", err))
+			return
+		}
+		s.certificates = append(s.certificates, cert)
+	}
+}
+
+// WithClientCAFile verifies client certificates against the PEM CAs of caFile.
+// With required, clients without a certificate are rejected during the handshake.
+func WithClientCAFile(caFile string, required bool) ServerOptions {
+	return func(s *Server) {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			s.optErrs = append(s.optErrs, fmt.Errorf("server: read client ca: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			s.optErrs = append(s.optErrs, fmt.Errorf("server: client ca %s: no PEM certificate", caFile))
+			return
+		}
+		s.clientCAs = pool
+		s.clientAuth = tls.VerifyClientCertIfGiven
+		if required {
+			s.clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+}
+
+// WithHTTPSRedirect listens for plain HTTP on addr and redirects every request
+// to the same host and path over HTTPS, with 308 so methods and bodies survive.
+func WithHTTPSRedirect(addr string) ServerOptions {
+	return func(s *Server) {
+		s.redirectAddr = addr
+	}
+}
+
+// buildTLS merges the TLS options into the http.Server. Callers run it after
+// every option applied.
+func (s *Server) buildTLS() error {
+	if len(s.optErrs) > 0 {
+		return errors.Join(s.optErrs...)
+	}
+	if s.tlsConfig == nil && len(s.certificates) == 0 && s.clientCAs == nil {
+		if s.redirectAddr != "" {
+			return errors.New("server: https redirect requires tls")
+		}
+		return nil
+	}
+
+	cfg := s.tlsConfig
+	if cfg == nil {
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	cfg.Certificates = append(cfg.Certificates, s.certificates...)
+	if len(cfg.Certificates) == 0 && cfg.GetCertificate == nil && cfg.GetConfigForClient == nil {
+		return errors.New("server: tls enabled without a certificate")
+	}
+	if s.clientCAs != nil {
+		cfg.ClientCAs = s.clientCAs
+		cfg.ClientAuth = s.clientAuth
+	}
+	s.server.TLSConfig = cfg
+
+	if s.redirectAddr != "" {
+		s.redirect = &http.Server{
+			Addr:              s.redirectAddr,
+			Handler:           redirectToHTTPS(s.port),
+			ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		}
+	}
+	return nil
+}
+
+// redirectToHTTPS redirects to port on the requested host, dropping it when it
+// is the default HTTPS port.
+func redirectToHTTPS(port uint16) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.Itoa(int(port)))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,205 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/modules/middleware/problem"
+)
+
+var _ RegistrableService = (*VersionedService)(nil)
+
+type (
+	// VersionConfig configures one major version of the API.
+	VersionConfig struct {
+		// Disabled retires the version on every instance reading this config: its
+		// routes answer 410 Gone while the other versions keep serving.
+		Disabled bool `env:"DISABLED"`
+		// Deprecated, when set, is announced in a Deprecation header (RFC 9745).
+		// An instant in the future announces an upcoming deprecation.
+		Deprecated time.Time `env:"DEPRECATED"`
+		// Sunset, when set, is announced in a Sunset header (RFC 8594).
+		Sunset time.Time `env:"SUNSET"`
+		// Link points clients at the migration guide from the Deprecation and Sunset links.
+		Link string `env:"LINK"`
+	}
+
+	// VersionedService mounts a RegistrableService whose spec paths start with
+	// "/{version}/", so several spec versions are served side by side over the
+	// same domain layer.
+	//
+	// The service registers on a mux of its own, mounted under the version prefix,
+	// and its middlewares and route groups only wrap that mux: the spec validation
+	// of v1 never sees a v2 request. Spec routes outside the prefix (liveness and the like) are only
+	// reachable when listed with WithUnversionedRoutes.
+	VersionedService struct {
+		version     string
+		svc         RegistrableService
+		cfg         VersionConfig
+		unversioned []string
+	}
+
+	// VersionOption configures a VersionedService.
+	VersionOption func(*VersionedService)
+)
+
+// WithUnversionedRoutes also mounts the given patterns of the service, e.g.
+// "GET /healthz", outside the version prefix. They carry no deprecation headers and
+// are still served once the version is disabled. List each pattern on one version only.
+func WithUnversionedRoutes(patterns ...string) VersionOption {
+	return func(v *VersionedService) {
+		v.unversioned = append(v.unversioned, patterns...)
+	}
+}
+
+// Versioned mounts svc as version (e.g. "v1") of the API, configured by cfg.
+func Versioned(version string, svc RegistrableService, cfg VersionConfig, opts ...VersionOption) *VersionedService {
+	v := &VersionedService{version: version, svc: svc, cfg: cfg}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(v)
+		}
+	}
+	return v
+}
+
+// Version returns the version svc is mounted as.
+func (v *VersionedService) Version() string {
+	return v.version
+}
+
+// Register mounts the service under "/{version}/", or a 410 Gone handler when the
+// version is disabled.
+func (v *VersionedService) Register(mux *http.ServeMux) {
+	inner := http.NewServeMux()
+	v.svc.Register(inner)
+	var groups []RouteGroup
+	if g, ok := v.svc.(GroupedService); ok {
+		groups = g.RouteGroups()
+	}
+	handler := chain(groupRoutes(inner, groups), v.svc.Middlewares())
+
+	for _, pattern := range v.unversioned {
+		mux.Handle(pattern, handler)
+	}
+
+	prefix := "/" + v.version + "/"
+	if v.cfg.Disabled {
+		slog.Info("api version disabled", slog.String("version", v.version))
+		mux.HandleFunc(prefix, v.gone)
+		return
+	}
+	if !v.deprecated() {
+		mux.Handle(prefix, handler)
+		return
+	}
+	mux.Handle(prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if !v.cfg.Deprecated.IsZero() {
+			h.Set("Deprecation", "@"+strconv.FormatInt(v.cfg.Deprecated.Unix(), 10))
+		}
+		if !v.cfg.Sunset.IsZero() {
+			h.Set("Sunset", v.cfg.Sunset.UTC().Format(http.TimeFormat))
+		}
+		handler.ServeHTTP(&versionHeaderWriter{ResponseWriter: w, links: v.links()}, r)
+	}))
+}
+
+// Middlewares returns nil: the service middlewares only wrap the version's routes.
+func (v *VersionedService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
+
+func (v *VersionedService) deprecated() bool {
+	return !v.cfg.Deprecated.IsZero() || !v.cfg.Sunset.IsZero()
+}
+
+// links returns the Link header values pointing at the migration guide.
+func (v *VersionedService) links() []string {
+	if v.cfg.Link == "" {
+		return nil
+	}
+	var links []string
+	if !v.cfg.Deprecated.IsZero() {
+		links = append(links, "<"+v.cfg.Link+`>; rel="deprecation"; type="text/html"`)
+	}
+	if !v.cfg.Sunset.IsZero() {
+		links = append(links, "<"+v.cfg.Link+`>; rel="sunset"; type="text/html"`)
+	}
+	return links
+}
+
+func (v *VersionedService) gone(w http.ResponseWriter, _ *http.Request) {
+	for _, l := range v.links() {
+		w.Header().Add("Link", l)
+	}
+	problem.Write(w, problem.New(
+		problem.WithStatus(http.StatusGone),
+		problem.WithTitle(http.StatusText(http.StatusGone)),
+		problem.WithDetail("API "+v.version+" has been retired"),
+		problem.WithCode("version_retired"),
+	))
+}
+
+// versionHeaderWriter adds the deprecation links when the response is committed,
+// after the handler set its own Link header (pagination) instead of being
+// overwritten by it.
+type versionHeaderWriter struct {
+	http.ResponseWriter
+	links []string
+	added bool
+}
+
+func (w *versionHeaderWriter) addLinks() {
+	if w.added {
+		return
+	}
+	w.added = true
+	for _, l := range w.links {
+		w.ResponseWriter.Header().Add("Link", l)
+	}
+}
+
+func (w *versionHeaderWriter) WriteHeader(code int) {
+	w.addLinks()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *versionHeaderWriter) Write(b []byte) (int, error) {
+	w.addLinks()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *versionHeaderWriter) Flush() {
+	w.addLinks()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *versionHeaderWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// chain wraps h with mws, the first one outermost.
+func chain(h http.Handler, mws []func(http.Handler) http.Handler) http.Handler {
+	for i := len(mws) - 1; i >= 0; i-- {
+		h = mws[i](h)
+	}
+	return h
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net/http"
+
+	"app/modules/admin"
+	"app/modules/readonly"
+	"app/modules/server"
+)
+
+var _ server.RegistrableService = (*AdminService)(nil)
+
+// AdminService mounts the operator endpoints under /admin/.
+type AdminService struct {
+	readOnly   *readonly.Switch
+	adminScope string
+	caches     []admin.Cache
+}
+
+func NewAdminService(readOnly *readonly.Switch, adminScope string, caches ...admin.Cache) *AdminService {
+	return &AdminService{readOnly: readOnly, adminScope: adminScope, caches: caches}
+}
+
+func (s *AdminService) Register(mux *http.ServeMux) {
+	mux.Handle("/admin/read-only", readonly.AdminHandler(s.readOnly, s.adminScope))
+	mux.Handle("/admin/caches", admin.CachesHandler(s.adminScope, s.caches...))
+}
+
+func (s *AdminService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
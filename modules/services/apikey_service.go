@@ -0,0 +1,43 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net/http"
+
+	"app/modules/apikey"
+	"app/modules/server"
+)
+
+var _ server.RegistrableService = (*APIKeyService)(nil)
+
+// APIKeyService mounts the API key management endpoints under /admin/api-keys.
+type APIKeyService struct {
+	keys       *apikey.Keys
+	adminScope string
+}
+
+func NewAPIKeyService(keys *apikey.Keys, adminScope string) *APIKeyService {
+	return &APIKeyService{keys: keys, adminScope: adminScope}
+}
+
+func (s *APIKeyService) Register(mux *http.ServeMux) {
+	mux.Handle("/admin/api-keys", apikey.Handler(s.keys, s.adminScope))
+	mux.Handle("/admin/api-keys/{id}", apikey.RevokeHandler(s.keys, s.adminScope))
+}
+
+func (s *APIKeyService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net/http"
+
+	"app/modules/delivery"
+	"app/modules/server"
+)
+
+var _ server.RegistrableService = (*DeliveryService)(nil)
+
+// DeliveryService mounts the delivery journal endpoints under /admin/deliveries.
+type DeliveryService struct {
+	journal    *delivery.Journal
+	adminScope string
+}
+
+func NewDeliveryService(journal *delivery.Journal, adminScope string) *DeliveryService {
+	return &DeliveryService{journal: journal, adminScope: adminScope}
+}
+
+func (s *DeliveryService) Register(mux *http.ServeMux) {
+	mux.Handle("/admin/deliveries/requeue", delivery.RequeueHandler(s.journal, s.adminScope))
+}
+
+func (s *DeliveryService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net/http"
+
+	"app/modules/health"
+	"app/modules/server"
+)
+
+var _ server.RegistrableService = (*HealthService)(nil)
+
+// HealthService mounts /healthz (liveness) and /readyz (readiness, aggregated
+// from the dependency trackers) outside any API version.
+type HealthService struct {
+	monitor *health.Monitor
+}
+
+func NewHealthService(monitor *health.Monitor) *HealthService {
+	return &HealthService{monitor: monitor}
+}
+
+func (s *HealthService) Register(mux *http.ServeMux) {
+	mux.Handle("GET /healthz", s.monitor.LiveHandler())
+	mux.Handle("GET /readyz", s.monitor.ReadyHandler())
+}
+
+func (s *HealthService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"net/http"
+
+	"app/modules/preferences"
+	"app/modules/server"
+)
+
+var _ server.RegistrableService = (*PreferencesService)(nil)
+
+// PreferencesService mounts the caller's list defaults on /v1/preferences.
+type PreferencesService struct {
+	store *preferences.Store
+}
+
+func NewPreferencesService(store *preferences.Store) *PreferencesService {
+	return &PreferencesService{store: store}
+}
+
+func (s *PreferencesService) Register(mux *http.ServeMux) {
+	mux.Handle("/v1/preferences", preferences.Handler(s.store))
+}
+
+func (s *PreferencesService) Middlewares() []func(http.Handler) http.Handler {
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"io/fs"
+	"net/http"
+
+	profile_http "app/core/profile/adapters/rest"
+	echo_profile_api "app/modules/api/profileapi/echo"
+	profile_api "app/modules/api/profileapi/stdlib"
+	"app/modules/middleware"
+	echoserver "app/modules/server/echo"
+
+	"github.com/labstack/echo/v4"
+)
+
+var _ echoserver.EchoService = (*ProfileEchoAPIService)(nil)
+
+// ProfileEchoAPIService registers the Profile API through the generated echo server.
+//
+// The echo instance is mounted under "/" of the ServeMux, so services registering
+// more specific patterns on the same mux keep their routes.
+type ProfileEchoAPIService struct {
+	specPath string
+	specFS   fs.FS
+	handler  profile_api.StrictServerInterface
+	// passed on to the validation middleware
+	validation []middleware.ValidationOption
+}
+
+func NewProfileEchoAPIService(h profile_api.StrictServerInterface, specFS fs.FS, specPath string, opts ...middleware.ValidationOption) *ProfileEchoAPIService {
+	return &ProfileEchoAPIService{specFS: specFS, specPath: specPath, handler: h, validation: opts}
+}
+
+// Register mounts the service on an echo instance of its own. Use echoserver to
+// share one instance with other services.
+func (s *ProfileEchoAPIService) Register(mux *http.ServeMux) {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = profile_http.EchoErrorHandler
+	s.RegisterEcho(e)
+
+	mux.Handle("/", e)
+}
+
+// RegisterEcho configures the echo strict handler and registers its routes on e.
+func (s *ProfileEchoAPIService) RegisterEcho(e *echo.Echo) {
+	strict := echo_profile_api.NewStrictHandler(
+		profile_http.NewEchoBridge(s.handler),
+		[]echo_profile_api.StrictMiddlewareFunc{profile_http.EchoProblemBridge()},
+	)
+	echo_profile_api.RegisterHandlers(e, strict)
+}
+
+// Middlewares returns the same validation middleware as the stdlib registration,
+// it works on net/http and does not depend on the router.
+func (s *ProfileEchoAPIService) Middlewares() []func(http.Handler) http.Handler {
+	return []func(http.Handler) http.Handler{
+		profile_http.ProfileHTTPValidationMiddleware(s.specFS, s.specPath, s.validation...),
+	}
+}
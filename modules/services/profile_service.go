@@ -20,6 +20,7 @@ import (
 
 	profile_http "app/core/profile/adapters/rest"
 	profile_api "app/modules/api/profileapi/stdlib"
+	"app/modules/middleware"
 	"app/modules/server"
 )
 
@@ -30,36 +31,42 @@ type ProfileAPIService struct {
 	specPath string
 	specFS   fs.FS
 	handler  profile_api.StrictServerInterface
+	// passed on to the validation middleware
+	validation []middleware.ValidationOption
 }
 
-func NewProfileAPIService(h profile_api.StrictServerInterface, specFS fs.FS, specPath string) *ProfileAPIService {
-	return &ProfileAPIService{specFS: specFS, specPath: specPath, handler: h}
+func NewProfileAPIService(h profile_api.StrictServerInterface, specFS fs.FS, specPath string, opts ...middleware.ValidationOption) *ProfileAPIService {
+	return &ProfileAPIService{specFS: specFS, specPath: specPath, handler: h, validation: opts}
 }
 
 // Register configures the strict handler and mounts the profile API routes.
 func (s *ProfileAPIService) Register(mux *http.ServeMux) {
 	strict := profile_api.NewStrictHandlerWithOptions(
 		s.handler,
-		[]profile_api.StrictMiddlewareFunc{},
+		[]profile_api.StrictMiddlewareFunc{profile_http.ProblemBridge()},
 		profile_api.StrictHTTPServerOptions{
 			RequestErrorHandlerFunc:  profile_http.ProblemDetailsRequestErrorHandler,
 			ResponseErrorHandlerFunc: profile_http.ProblemDetailsResponseErrorHandler,
 		},
 	)
 
+	// the spec has HEAD /v1/profiles/{id} next to GET /v1/profiles/count
+	routes := server.NewHeadRoutes(mux)
 	profile_api.HandlerWithOptions(
 		strict,
 		profile_api.StdHTTPServerOptions{
-			BaseRouter:       mux,
-			Middlewares:      []profile_api.MiddlewareFunc{},
+			BaseRouter: routes,
+			// the version's nested mux matches the spec routes, not the server mux
+			Middlewares:      []profile_api.MiddlewareFunc{middleware.NoteRoute},
 			ErrorHandlerFunc: profile_http.ProblemDetailsRequestErrorHandler,
 		},
 	)
+	routes.Flush()
 }
 
 // Middlewares returns global middlewares required by the Profile API, such as validation.
 func (s *ProfileAPIService) Middlewares() []func(http.Handler) http.Handler {
 	return []func(http.Handler) http.Handler{
-		profile_http.ProfileHTTPValidationMiddleware(s.specFS, s.specPath),
+		profile_http.ProfileHTTPValidationMiddleware(s.specFS, s.specPath, s.validation...),
 	}
 }
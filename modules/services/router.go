@@ -0,0 +1,44 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"io/fs"
+
+	profile_http "app/core/profile/adapters/rest"
+	profile_api "app/modules/api/profileapi/stdlib"
+	"app/modules/middleware"
+	"app/modules/server"
+	echoserver "app/modules/server/echo"
+)
+
+// NewProfileService returns the Profile API registration for the configured router.
+//
+// Both registrations serve the same handler, spec validation and problem mapping;
+// only the generated routing and binding layer differs.
+func NewProfileService(router server.Router, h profile_api.StrictServerInterface, specFS fs.FS, specPath string, opts ...middleware.ValidationOption) (server.RegistrableService, error) {
+	switch router {
+	case server.RouterStdlib, "":
+		return NewProfileAPIService(h, specFS, specPath, opts...), nil
+	case server.RouterEcho:
+		return echoserver.New(
+			echoserver.WithErrorHandler(profile_http.EchoErrorHandler),
+			echoserver.WithServices(NewProfileEchoAPIService(h, specFS, specPath, opts...)),
+		), nil
+	default:
+		return nil, fmt.Errorf("services: unknown router %q", router)
+	}
+}
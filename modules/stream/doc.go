@@ -0,0 +1,36 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stream moves batches from a producer to a consumer with backpressure.
+//
+// Pipe connects a Source, typically a database cursor, to a Sink, typically an
+// encoder writing to an HTTP response, through a bounded channel. The source
+// can only run a few batches ahead of the sink: when the client reads slowly,
+// the sink blocks on the socket, the channel fills up and the source blocks
+// instead of buffering the whole result in memory.
+//
+//	err := stream.Pipe(ctx,
+//		func(ctx context.Context, emit func([]Row) error) error {
+//			return store.Stream(ctx, 500, emit)
+//		},
+//		func(ctx context.Context, rows []Row) error {
+//			return enc.Encode(rows)
+//		},
+//		stream.WithStallTimeout(30*time.Second),
+//	)
+//
+// The source runs under a context that is cancelled as soon as the sink fails,
+// stalls or the caller goes away, so the query behind it stops promptly instead
+// of running to completion for nobody.
+package stream
@@ -0,0 +1,142 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"app/modules/worker"
+)
+
+// ErrStalled is returned by Pipe when the sink did not accept a batch within the
+// stall timeout, e.g. a client that keeps the connection open but stopped reading.
+var ErrStalled = errors.New("stream: sink stalled")
+
+type (
+	// Source produces batches by calling emit until it is exhausted. It must stop
+	// and return as soon as emit or ctx fail.
+	Source[T any] func(ctx context.Context, emit func([]T) error) error
+
+	// Sink consumes one batch. Batches are passed in the order they were emitted.
+	Sink[T any] func(ctx context.Context, batch []T) error
+
+	// Option configures Pipe.
+	Option func(*config)
+
+	config struct {
+		// batches the source may run ahead of the sink
+		buffer int
+		// longest time a single sink call may take, zero waits forever
+		stallTimeout time.Duration
+	}
+)
+
+// WithBuffer sets how many batches the source may produce ahead of the sink.
+// Defaults to 2, keeping at most buffer+2 batches in memory (one being filled,
+// one being consumed).
+func WithBuffer(n int) Option {
+	return func(c *config) {
+		if n >= 0 {
+			c.buffer = n
+		}
+	}
+}
+
+// WithStallTimeout aborts the pipe with ErrStalled when a single sink call takes
+// longer than d. The source is cancelled at once; the sink should also watch its
+// ctx, otherwise Pipe only returns once the blocked call does.
+func WithStallTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.stallTimeout = d
+		}
+	}
+}
+
+// Pipe runs src and sink concurrently, connected by a bounded channel, and blocks
+// until both are done.
+//
+// The sink is driven by a single worker of a worker.BlockingPool, which keeps
+// batches in order. The first failure cancels the other side and is returned:
+// the sink error, ErrStalled, the source error or the cause of ctx, in that order
+// of precedence. An emit call fails once the pipe is cancelled.
+func Pipe[T any](ctx context.Context, src Source[T], sink Sink[T], opts ...Option) error {
+	cfg := config{buffer: 2}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	batches := make(chan []T, cfg.buffer)
+	srcDone := make(chan error, 1)
+	go func() {
+		defer close(batches)
+		srcDone <- src(ctx, func(batch []T) error {
+			if len(batch) == 0 {
+				return ctx.Err()
+			}
+			select {
+			case batches <- batch:
+				return nil
+			case <-ctx.Done():
+				return context.Cause(ctx)
+			}
+		})
+	}()
+
+	var sinkErr error
+	worker.BlockingPool(ctx, 1, batches, func(ctx context.Context, batch []T) {
+		if ctx.Err() != nil {
+			// the pool may still pick up a buffered batch after a failure
+			return
+		}
+		defer func() {
+			// the pool swallows panics, which would leave the source blocked on emit
+			if rec := recover(); rec != nil {
+				sinkErr = fmt.Errorf("panic: %v", rec)
+				cancel(sinkErr)
+			}
+		}()
+		if cfg.stallTimeout > 0 {
+			stall := time.AfterFunc(cfg.stallTimeout, func() { cancel(ErrStalled) })
+			defer stall.Stop()
+		}
+		if err := sink(ctx, batch); err != nil {
+			sinkErr = err
+			cancel(err)
+		}
+	})
+	// the pool returns early on cancellation, make sure the source is gone too
+	srcErr := <-srcDone
+
+	switch cause := context.Cause(ctx); {
+	case sinkErr != nil && !errors.Is(cause, ErrStalled):
+		return fmt.Errorf("stream: sink: %w", sinkErr)
+	case errors.Is(cause, ErrStalled):
+		return ErrStalled
+	case srcErr != nil:
+		return fmt.Errorf("stream: source: %w", srcErr)
+	default:
+		// a cancelled caller may leave batches unconsumed without any side failing
+		return cause
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// BusinessMetrics counts business events (profiles created and deleted, payment
+// amounts) for product dashboards, on the global MeterProvider.
+type BusinessMetrics struct {
+	profilesCreated metric.Int64Counter
+	profilesDeleted metric.Int64Counter
+	paymentAmount   metric.Float64Counter
+}
+
+// NewBusinessMetrics creates the business event instruments for a given service name
+func NewBusinessMetrics(serviceName string) (*BusinessMetrics, error) {
+	meter := otel.Meter(serviceName)
+
+	profilesCreated, err := meter.Int64Counter(
+		"profiles_created_total",
+		metric.WithDescription("Profiles created, by how they were created"),
+		metric.WithUnit("{profile}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	profilesDeleted, err := meter.Int64Counter(
+		"profiles_deleted_total",
+		metric.WithDescription("Profiles deleted"),
+		metric.WithUnit("{profile}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentAmount, err := meter.Float64Counter(
+		"payment_amount_sum",
+		metric.WithDescription("Sum of payment amounts in major currency units, by ISO 4217 currency"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BusinessMetrics{
+		profilesCreated: profilesCreated,
+		profilesDeleted: profilesDeleted,
+		paymentAmount:   paymentAmount,
+	}, nil
+}
+
+// ProfileCreated counts a created profile; source tells how ("post", "put", "upsert", "bulk").
+func (m *BusinessMetrics) ProfileCreated(ctx context.Context, source string) {
+	// the request may be gone, the profile is created nonetheless
+	m.profilesCreated.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(attribute.String("source", source)))
+}
+
+// ProfileDeleted counts a deleted profile.
+func (m *BusinessMetrics) ProfileDeleted(ctx context.Context) {
+	m.profilesDeleted.Add(context.WithoutCancel(ctx), 1)
+}
+
+// PaymentRecorded adds a settled payment to the amount sum of its currency.
+// Negative amounts (refunds) are ignored, since the sum is monotonic.
+func (m *BusinessMetrics) PaymentRecorded(ctx context.Context, amount float64, currency string) {
+	if amount <= 0 {
+		return
+	}
+	m.paymentAmount.Add(context.WithoutCancel(ctx), amount, metric.WithAttributes(attribute.String("currency", currencyLabel(currency))))
+}
+
+// currencyLabel keeps the attribute to ISO 4217 codes, so a bad input cannot blow
+// up the series count.
+func currencyLabel(c string) string {
+	c = strings.ToUpper(strings.TrimSpace(c))
+	if len(c) != 3 || strings.ContainsFunc(c, func(r rune) bool { return r < 'A' || r > 'Z' }) {
+		return "other"
+	}
+	return c
+}
@@ -27,7 +27,10 @@ const (
 type Config struct {
 	ServiceName    string `env:"OTEL_SERVICE_NAME" envDefault:"profile-api"`
 	ServiceVersion string `env:"SERVICE_VERSION" envDefault:"dev"`
-	Environment    string `env:"ENVIRONMENT" envDefault:"local"`
+	// Environment and Namespace become deployment.environment and service.namespace;
+	// appconfig fills them from ENV and NAMESPACE when unset.
+	Environment string `env:"ENVIRONMENT"`
+	Namespace   string `env:"SERVICE_NAMESPACE"`
 
 	// Optional; if empty, OTEL_EXPORTER_OTLP_(TRACES_)ENDPOINT is used.
 	// Can be "http://otel-collector:4317" or just "otel-collector:4317"
@@ -48,5 +51,5 @@ type Config struct {
 	DisableMetrics bool `envDefault:"false"`
 
 	// Extra resource attributes.
-	ResourceAttrs map[string]string `env:"OTEL_RESOURCE_ATTRIBUTES" envDefault:"deployment.environment=local,service.version=dev" envSeparator:"," envKeyValSeparator:"="`
+	ResourceAttrs map[string]string `env:"OTEL_RESOURCE_ATTRIBUTES" envDefault:"service.version=dev" envSeparator:"," envKeyValSeparator:"="`
 }
@@ -0,0 +1,49 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+)
+
+// exportErrors receives the errors the OTel SDK reports, mostly failed exports.
+var exportErrors errorRecorder
+
+var _ otel.ErrorHandler = (*errorRecorder)(nil)
+
+// errorRecorder logs SDK errors and keeps the last one for CheckExporters.
+type errorRecorder struct {
+	last atomic.Pointer[error]
+}
+
+// Handle implements otel.ErrorHandler.
+func (r *errorRecorder) Handle(err error) {
+	r.last.Store(&err)
+	slog.Warn("telemetry: sdk error", slog.Any("error", err))
+}
+
+// CheckExporters is a health check failing when the SDK reported an error, usually
+// a failed export, since the previous call. Telemetry is never critical: a
+// collector outage loses spans and metrics, not requests.
+func CheckExporters(context.Context) error {
+	if err := exportErrors.last.Swap(nil); err != nil {
+		return *err
+	}
+	return nil
+}
@@ -67,10 +67,12 @@ func NewHTTPMetrics(serviceName string) (*HTTPMetrics, error) {
 	}, nil
 }
 
-// RecordRequest records a single HTTP request with its attributes
-func (m *HTTPMetrics) RecordRequest(ctx context.Context, method, endpoint, statusCode string, durationMs float64, responseSize int64) {
+// RecordRequest records a single HTTP request with its attributes. protocol is
+// "http/1.1", "h2", "h2c" or "h3".
+func (m *HTTPMetrics) RecordRequest(ctx context.Context, method, endpoint, protocol, statusCode string, durationMs float64, responseSize int64) {
 	attrs := []attribute.KeyValue{
 		attribute.String("http_method", method),
+		attribute.String("http_protocol", protocol),
 		attribute.String("http_endpoint", endpoint),
 		attribute.String("http_status_code", statusCode),
 	}
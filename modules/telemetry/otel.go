@@ -50,6 +50,8 @@ func Init(ctx context.Context, cfg Config) (ShutdownFunc, error) {
 	if cfg.Mode == "" {
 		cfg.Mode = ModeDetect
 	}
+	// exporter failures feed CheckExporters instead of only reaching stderr
+	otel.SetErrorHandler(&exportErrors)
 
 	autoDetected := detectGoAuto()
 
@@ -248,6 +250,9 @@ func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error)
 	if cfg.Environment != "" {
 		attrs = append(attrs, attribute.String("deployment.environment", cfg.Environment))
 	}
+	if cfg.Namespace != "" {
+		attrs = append(attrs, semconv.ServiceNamespaceKey.String(cfg.Namespace))
+	}
 	for k, v := range cfg.ResourceAttrs {
 		attrs = append(attrs, attribute.String(k, v))
 	}
@@ -0,0 +1,206 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	rl "app/modules/ratelimit"
+
+	"github.com/coder/websocket"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrClosed is returned when sending to a connection that is closed.
+	ErrClosed = errors.New("ws: connection closed")
+	// ErrSlowConsumer is returned when the send buffer of a connection is full.
+	// The connection is closed with StatusTryAgainLater.
+	ErrSlowConsumer = errors.New("ws: slow consumer")
+)
+
+type (
+	// MessageHandler is called with every message read from a connection, one at
+	// a time. Returning an error closes the connection with StatusInternalError.
+	MessageHandler func(ctx context.Context, c *Conn, typ websocket.MessageType, data []byte) error
+
+	// Conn is an upgraded connection registered in a Hub.
+	Conn struct {
+		id  string
+		key rl.Key
+
+		ws   *websocket.Conn
+		hub  *Hub
+		cfg  *config
+		send chan message
+		// nil when message rates are not limited
+		limiter *rate.Limiter
+
+		done      chan struct{}
+		closeOnce sync.Once
+	}
+
+	message struct {
+		typ  websocket.MessageType
+		data []byte
+	}
+)
+
+func newConn(id string, key rl.Key, wsConn *websocket.Conn, hub *Hub, cfg *config) *Conn {
+	c := &Conn{
+		id:   id,
+		key:  key,
+		ws:   wsConn,
+		hub:  hub,
+		cfg:  cfg,
+		send: make(chan message, cfg.sendBuffer),
+		done: make(chan struct{}),
+	}
+	if cfg.messageRate > 0 {
+		c.limiter = rate.NewLimiter(cfg.messageRate, cfg.messageBurst)
+	}
+	return c
+}
+
+// ID identifies the connection in logs.
+func (c *Conn) ID() string { return c.id }
+
+// Key returns the key the connection was admitted with, empty without admission
+// control.
+func (c *Conn) Key() rl.Key { return c.key }
+
+// Send queues a message without blocking. A connection whose buffer is full is
+// closed with StatusTryAgainLater and ErrSlowConsumer is returned.
+func (c *Conn) Send(typ websocket.MessageType, data []byte) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	select {
+	case c.send <- message{typ: typ, data: data}:
+		return nil
+	case <-c.done:
+		return ErrClosed
+	default:
+		go c.Close(websocket.StatusTryAgainLater, "too slow")
+		return ErrSlowConsumer
+	}
+}
+
+// Close sends a close frame with code and reason and waits for the peer to answer
+// it, for a few seconds at most. Later calls do nothing.
+func (c *Conn) Close(code websocket.StatusCode, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.ws.Close(code, reason)
+	})
+}
+
+// run serves the connection until either side closes it.
+func (c *Conn) run(ctx context.Context, onMessage MessageHandler) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		// tear down the reader when writes or pings fail; after Close the
+		// reader ends by itself once the close handshake is done
+		if err := c.writeLoop(ctx); err != nil {
+			cancel()
+		}
+	}()
+
+	err := c.readLoop(ctx, onMessage)
+	// the peer hung up or the reader failed: stop taking messages
+	c.closeOnce.Do(func() {
+		close(c.done)
+		_ = c.ws.CloseNow()
+	})
+	cancel()
+	<-writerDone
+
+	status := websocket.CloseStatus(err)
+	slog.DebugContext(ctx, "ws: connection closed",
+		slog.String("ws.conn_id", c.id),
+		slog.Int("ws.close_status", int(status)),
+		slog.Any("error", err),
+	)
+}
+
+func (c *Conn) readLoop(ctx context.Context, onMessage MessageHandler) error {
+	for {
+		typ, data, err := c.ws.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if c.limiter != nil && !c.limiter.Allow() {
+			c.Close(websocket.StatusPolicyViolation, "message rate exceeded")
+			return errors.New("ws: message rate exceeded")
+		}
+		if onMessage == nil {
+			continue
+		}
+		if err := onMessage(ctx, c, typ, data); err != nil {
+			slog.ErrorContext(ctx, "ws: message handler failed",
+				slog.String("ws.conn_id", c.id),
+				slog.Any("error", err),
+			)
+			c.Close(websocket.StatusInternalError, "internal error")
+			return err
+		}
+	}
+}
+
+func (c *Conn) writeLoop(ctx context.Context) error {
+	ping := time.NewTicker(c.cfg.pingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.done:
+			return nil
+		case msg := <-c.send:
+			if err := c.write(ctx, msg); err != nil {
+				return err
+			}
+		case <-ping.C:
+			// Ping returns once the pong arrived, which needs the concurrent reader
+			pctx, cancel := context.WithTimeout(ctx, c.cfg.pongTimeout)
+			err := c.ws.Ping(pctx)
+			cancel()
+			if err != nil {
+				slog.DebugContext(ctx, "ws: ping failed",
+					slog.String("ws.conn_id", c.id),
+					slog.Any("error", err),
+				)
+				return err
+			}
+		}
+	}
+}
+
+func (c *Conn) write(ctx context.Context, msg message) error {
+	wctx, cancel := context.WithTimeout(ctx, c.cfg.writeTimeout)
+	defer cancel()
+	return c.ws.Write(wctx, msg.typ, msg.data)
+}
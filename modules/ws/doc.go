@@ -0,0 +1,47 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ws serves WebSocket connections for real-time features.
+//
+// Handler upgrades requests and keeps every connection in a Hub, which sends a
+// message to all of them with Broadcast and closes them on shutdown:
+//
+//	hub := ws.NewHub()
+//	lc.OnShutdown("ws-hub", lifecycle.PriorityIngress, hub.Shutdown)
+//
+//	mux.Handle("GET /v1/events", ws.NewHandler(hub, onMessage,
+//		ws.WithAdmission(ws.Admission{
+//			Limiter: limiterFactory(10, time.Minute),
+//			KeyFn:   ratelimit.SubjectKeyFunc,
+//		}),
+//		ws.WithMessageRate(5, 10),
+//	))
+//
+//	hub.Broadcast(websocket.MessageText, []byte(`{"type":"profile.updated"}`))
+//
+// Connection attempts are admitted through the same limiters and key functions as
+// the HTTP rate limiter, so a caller gets one budget whatever the transport. Once
+// upgraded, every connection has its own local message rate: a client going over it
+// is closed with StatusPolicyViolation, and one that does not read the messages
+// sent to it fast enough is closed with StatusTryAgainLater instead of buffering
+// for it.
+//
+// The server pings every connection and closes the ones that do not answer with
+// a pong in time, which also frees connections to clients that went away without
+// a close frame.
+//
+// Hijacked connections are not tracked by http.Server.Shutdown: register
+// Hub.Shutdown with the lifecycle at PriorityIngress so clients receive
+// StatusGoingAway and can reconnect to another instance.
+package ws
@@ -0,0 +1,274 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/modules/middleware/problem"
+	"app/modules/middleware/ratelimit"
+	rl "app/modules/ratelimit"
+
+	"github.com/coder/websocket"
+	"github.com/gofrs/uuid/v5"
+	"golang.org/x/time/rate"
+)
+
+const (
+	DefaultPingInterval    = 30 * time.Second
+	DefaultPongTimeout     = 10 * time.Second
+	DefaultWriteTimeout    = 10 * time.Second
+	DefaultSendBuffer      = 16
+	DefaultMaxMessageBytes = 32 << 10
+)
+
+type (
+	// Admission rate limits connection attempts per key, with the same limiters
+	// and key functions as the HTTP rate limiter (see ratelimit.KeyStrategies).
+	Admission struct {
+		Limiter rl.RateLimiter
+		KeyFn   ratelimit.KeyFunc
+
+		// Admit attempts KeyFn yields no key for; otherwise they get 429
+		AllowIfNoIdentifier bool
+		// Admit attempts when the limit cannot be checked; otherwise they get 503
+		FailOpen bool
+	}
+
+	// Handler upgrades requests to WebSocket connections served by a Hub.
+	Handler struct {
+		hub       *Hub
+		onMessage MessageHandler
+		cfg       config
+	}
+
+	// Option configures a Handler.
+	Option func(*config)
+
+	config struct {
+		admission *Admission
+
+		// zero disables the message rate limit
+		messageRate  rate.Limit
+		messageBurst int
+
+		maxConns        int
+		maxMessageBytes int64
+		sendBuffer      int
+
+		pingInterval time.Duration
+		pongTimeout  time.Duration
+		writeTimeout time.Duration
+
+		accept websocket.AcceptOptions
+	}
+)
+
+// WithAdmission rate limits connection attempts, see Admission.
+func WithAdmission(a Admission) Option {
+	return func(c *config) {
+		if a.Limiter != nil && a.KeyFn != nil {
+			c.admission = &a
+		}
+	}
+}
+
+// WithMessageRate limits every connection to perSecond messages, with bursts of
+// up to burst. Clients going over it are closed with StatusPolicyViolation.
+//
+// The limit is kept in memory per connection rather than in the counter store,
+// since checking it remotely would cost a round trip for every message.
+func WithMessageRate(perSecond float64, burst int) Option {
+	return func(c *config) {
+		if perSecond > 0 {
+			c.messageRate = rate.Limit(perSecond)
+			c.messageBurst = max(burst, 1)
+		}
+	}
+}
+
+// WithMaxConnections rejects upgrades with 503 once the hub holds n connections.
+func WithMaxConnections(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.maxConns = n
+		}
+	}
+}
+
+// WithMaxMessageBytes closes connections sending larger messages with
+// StatusMessageTooBig. Defaults to DefaultMaxMessageBytes.
+func WithMaxMessageBytes(n int64) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.maxMessageBytes = n
+		}
+	}
+}
+
+// WithSendBuffer sets how many outgoing messages may wait for a connection
+// before it counts as a slow consumer. Defaults to DefaultSendBuffer.
+func WithSendBuffer(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.sendBuffer = n
+		}
+	}
+}
+
+// WithPing sets how often connections are pinged and how long the pong may take.
+// Defaults to DefaultPingInterval and DefaultPongTimeout.
+func WithPing(interval, timeout time.Duration) Option {
+	return func(c *config) {
+		if interval > 0 {
+			c.pingInterval = interval
+		}
+		if timeout > 0 {
+			c.pongTimeout = timeout
+		}
+	}
+}
+
+// WithWriteTimeout bounds how long writing a single message may take. Defaults to
+// DefaultWriteTimeout.
+func WithWriteTimeout(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.writeTimeout = d
+		}
+	}
+}
+
+// WithOriginPatterns allows cross-origin upgrades from the given host patterns,
+// see websocket.AcceptOptions. Only same-origin requests are accepted by default.
+func WithOriginPatterns(patterns ...string) Option {
+	return func(c *config) {
+		c.accept.OriginPatterns = append(c.accept.OriginPatterns, patterns...)
+	}
+}
+
+// WithSubprotocols negotiates one of the given subprotocols, in order of preference.
+func WithSubprotocols(protocols ...string) Option {
+	return func(c *config) {
+		c.accept.Subprotocols = append(c.accept.Subprotocols, protocols...)
+	}
+}
+
+// NewHandler constructs a Handler adding its connections to hub and passing the
+// messages they read to onMessage, which may be nil for send-only streams.
+func NewHandler(hub *Hub, onMessage MessageHandler, opts ...Option) *Handler {
+	h := &Handler{
+		hub:       hub,
+		onMessage: onMessage,
+		cfg: config{
+			maxMessageBytes: DefaultMaxMessageBytes,
+			sendBuffer:      DefaultSendBuffer,
+			pingInterval:    DefaultPingInterval,
+			pongTimeout:     DefaultPongTimeout,
+			writeTimeout:    DefaultWriteTimeout,
+		},
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&h.cfg)
+		}
+	}
+	return h
+}
+
+// ServeHTTP admits and upgrades the request, then blocks until the connection is
+// closed. Rejected attempts get a problem response before the upgrade.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, ok := h.admit(w, r)
+	if !ok {
+		return
+	}
+	if h.cfg.maxConns > 0 && h.hub.Len() >= h.cfg.maxConns {
+		problem.Write(w, problem.ServiceUnavailable("too many connections", problem.FromRequest(r)))
+		return
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		problem.Write(w, problem.Internal("cannot identify connection", problem.FromRequest(r)))
+		return
+	}
+
+	// Accept writes its own error response
+	wsConn, err := websocket.Accept(w, r, &h.cfg.accept)
+	if err != nil {
+		slog.DebugContext(r.Context(), "ws: upgrade failed", slog.Any("error", err))
+		return
+	}
+	wsConn.SetReadLimit(h.cfg.maxMessageBytes)
+
+	c := newConn(id.String(), key, wsConn, h.hub, &h.cfg)
+	if err := h.hub.add(c); err != nil {
+		_ = wsConn.Close(websocket.StatusGoingAway, "server shutting down")
+		return
+	}
+	defer h.hub.remove(c)
+
+	slog.DebugContext(r.Context(), "ws: connection opened",
+		slog.String("ws.conn_id", c.id),
+		slog.String("ws.subprotocol", wsConn.Subprotocol()),
+	)
+	c.run(r.Context(), h.onMessage)
+}
+
+// admit applies the admission rate limit and returns the key of the caller.
+func (h *Handler) admit(w http.ResponseWriter, r *http.Request) (rl.Key, bool) {
+	a := h.cfg.admission
+	if a == nil {
+		return "", true
+	}
+
+	key := a.KeyFn(r)
+	if key == "" {
+		if a.AllowIfNoIdentifier {
+			return "", true
+		}
+		problem.Write(w, problem.TooManyRequests(http.StatusText(http.StatusTooManyRequests), problem.FromRequest(r)))
+		return "", false
+	}
+
+	result, err := a.Limiter.Allow(r.Context(), key)
+	if err != nil {
+		slog.ErrorContext(r.Context(), "ws: admission rate limit error",
+			slog.Any("error", err),
+			slog.Bool("fail_open", a.FailOpen),
+		)
+		if a.FailOpen {
+			return key, true
+		}
+		problem.Write(w, problem.ServiceUnavailable("rate limit unavailable", problem.FromRequest(r)))
+		return "", false
+	}
+	if !result.Allowed {
+		retry := result.RetryAfter
+		if retry <= 0 {
+			retry = result.WindowResetIn
+		}
+		w.Header().Set("Retry-After", strconv.FormatInt(max(int64((retry+time.Second-1)/time.Second), 1), 10))
+		problem.Write(w, problem.TooManyRequests(http.StatusText(http.StatusTooManyRequests), problem.FromRequest(r)))
+		return "", false
+	}
+	return key, true
+}
+
+var _ http.Handler = (*Handler)(nil)
@@ -0,0 +1,117 @@
+// Copyright 2025 Nhat-Nguyen Nguyen
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ws
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/coder/websocket"
+)
+
+// ErrHubClosed is returned when a connection is added to a Hub that shut down.
+var ErrHubClosed = errors.New("ws: hub closed")
+
+// Hub keeps track of the open connections of one or more Handlers.
+type Hub struct {
+	mu     sync.RWMutex
+	conns  map[*Conn]struct{}
+	closed bool
+
+	// running connection loops, waited for on Shutdown
+	wg sync.WaitGroup
+}
+
+// NewHub constructs an empty Hub.
+func NewHub() *Hub {
+	return &Hub{conns: make(map[*Conn]struct{})}
+}
+
+// Len returns the number of open connections.
+func (h *Hub) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns)
+}
+
+// Broadcast queues a message to every open connection and returns how many
+// accepted it. Connections too slow to take it are closed, see Conn.Send.
+func (h *Hub) Broadcast(typ websocket.MessageType, data []byte) int {
+	h.mu.RLock()
+	conns := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.RUnlock()
+
+	sent := 0
+	for _, c := range conns {
+		if c.Send(typ, data) == nil {
+			sent++
+		}
+	}
+	return sent
+}
+
+// Shutdown closes every connection with StatusGoingAway and waits until their
+// loops returned or ctx is done. Connections cannot be added afterwards.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	conns := make([]*Conn, 0, len(h.conns))
+	for c := range h.conns {
+		conns = append(conns, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range conns {
+		// the close handshake waits for the peer, do not serialize on slow clients
+		go c.Close(websocket.StatusGoingAway, "server shutting down")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *Hub) add(c *Conn) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return ErrHubClosed
+	}
+	h.conns[c] = struct{}{}
+	h.wg.Add(1)
+	return nil
+}
+
+func (h *Hub) remove(c *Conn) {
+	h.mu.Lock()
+	_, ok := h.conns[c]
+	delete(h.conns, c)
+	h.mu.Unlock()
+	if ok {
+		h.wg.Done()
+	}
+}